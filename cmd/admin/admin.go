@@ -29,6 +29,8 @@ func New() *cobra.Command {
 		start.New(nil),
 		start.NewStartFromInit(nil),
 		key.New(),
+		newProjections(),
+		newEvents(),
 	)
 
 	return adminCMD
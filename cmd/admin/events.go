@@ -0,0 +1,144 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/cmd/start"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore/archive"
+)
+
+func newEvents() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "manage the events of ZITADEL",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("no additional command provided")
+		},
+	}
+
+	cmd.AddCommand(
+		newArchiveEvents(),
+		newImportEvents(),
+	)
+
+	return cmd
+}
+
+func newArchiveEvents() *cobra.Command {
+	var (
+		before     string
+		aggregates bool
+	)
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "move events of removed instances and aggregates into the event archive",
+		Long: `Exports all events of instances removed before the given date
+into gzip compressed JSON lines files in the configured EventArchive storage.
+Each file is verified after the upload and the exported events are deleted afterwards.
+With --aggregates the events of removed organisations, projects and users are archived as well.
+Requirements:
+- cockroachdb or postgres`,
+		Example: `zitadel admin events archive --before 2022-01-01T00:00:00Z --aggregates`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if before == "" {
+				return errors.New("--before is required")
+			}
+			cutoff, err := parseCutoff(before)
+			if err != nil {
+				return err
+			}
+			config := start.MustNewConfig(viper.GetViper())
+			return archiveEvents(cmd.Context(), config, cutoff, aggregates)
+		},
+	}
+
+	cmd.Flags().StringVar(&before, "before", "", "events of instances and aggregates removed before this date are archived (RFC3339 or YYYY-MM-DD)")
+	cmd.Flags().BoolVar(&aggregates, "aggregates", false, "also archive the events of removed organisations, projects and users")
+
+	return cmd
+}
+
+func parseCutoff(before string) (time.Time, error) {
+	if cutoff, err := time.Parse(time.RFC3339, before); err == nil {
+		return cutoff, nil
+	}
+	return time.Parse("2006-01-02", before)
+}
+
+func archiveEvents(ctx context.Context, config *start.Config, before time.Time, aggregates bool) error {
+	store, err := config.EventArchive.NewStore()
+	if err != nil {
+		return err
+	}
+	dbClient, err := database.Connect(config.Database, false)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Close()
+
+	archiver := archive.NewArchiver(dbClient.DB, store)
+	units, err := archiver.Units(ctx, before, aggregates)
+	if err != nil {
+		return err
+	}
+	for _, unit := range units {
+		archived, err := archiver.Archive(ctx, unit, before)
+		if err != nil {
+			logging.WithFields("instance", unit.InstanceID, "aggregateType", unit.AggregateType).WithError(err).Error("archive failed")
+			return err
+		}
+		logging.WithFields("instance", archived.InstanceID, "aggregateType", archived.AggregateType, "archive", archived.Name, "events", archived.Events).Info("events archived")
+	}
+	return nil
+}
+
+func newImportEvents() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <instanceID>/<name>",
+		Short: "import an archive back into the eventstore",
+		Long: `Inserts the events of an archive created by "zitadel admin events archive" into the eventstore.
+Events which still exist in the eventstore are skipped.
+The imported events keep their sequences, so the projections only contain them
+after they are rebuilt with "zitadel admin projections rebuild".
+Requirements:
+- cockroachdb or postgres`,
+		Example: `zitadel admin events import 165460246853992705/instance-20220101T000000Z.jsonl.gz`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instanceID, name, ok := strings.Cut(args[0], "/")
+			if !ok || instanceID == "" || name == "" {
+				return errors.New("archive must be in the form <instanceID>/<name>")
+			}
+			config := start.MustNewConfig(viper.GetViper())
+			return importEvents(cmd.Context(), config, instanceID, name)
+		},
+	}
+}
+
+func importEvents(ctx context.Context, config *start.Config, instanceID, name string) error {
+	store, err := config.EventArchive.NewStore()
+	if err != nil {
+		return err
+	}
+	dbClient, err := database.Connect(config.Database, false)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Close()
+
+	imported, err := archive.NewArchiver(dbClient.DB, store).Import(ctx, instanceID, name)
+	if err != nil {
+		return err
+	}
+	logging.WithFields("instance", instanceID, "archive", name, "events", imported).Info("events imported")
+	return nil
+}
@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/zitadel/zitadel/cmd/key"
+	"github.com/zitadel/zitadel/cmd/start"
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/query"
+	"github.com/zitadel/zitadel/internal/query/projection"
+)
+
+func newProjections() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "projections",
+		Short: "manage the projections of ZITADEL",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("no additional command provided")
+		},
+	}
+
+	cmd.AddCommand(newRebuildProjection())
+
+	return cmd
+}
+
+func newRebuildProjection() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebuild <name>",
+		Short: "rebuild a projection without downtime",
+		Long: `Replays all events of the projection into shadow tables
+and replaces the tables of the projection as soon as the shadow tables caught up.
+The projection stays available during the rebuild.
+An interrupted rebuild continues where it stopped.
+Requirements:
+- cockroachdb or postgres`,
+		Example: `zitadel admin projections rebuild projections.users8 --masterkey "MasterkeyNeedsToHave32Characters"`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := start.MustNewConfig(viper.GetViper())
+
+			masterKey, err := key.MasterKeyIfRequired(cmd, config.KMS)
+			if err != nil {
+				return err
+			}
+
+			return rebuildProjection(cmd.Context(), config, masterKey, args[0])
+		},
+	}
+
+	key.AddMasterKeyFlag(cmd)
+
+	return cmd
+}
+
+func rebuildProjection(ctx context.Context, config *start.Config, masterKey, name string) error {
+	dbClient, err := database.Connect(config.Database, false)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Close()
+
+	keyStorage, err := key.NewKeyStorage(dbClient.DB, masterKey, config.KMS)
+	if err != nil {
+		return err
+	}
+	rotations, err := keyStorage.ReadKeyRotations()
+	if err != nil {
+		return err
+	}
+	// the keys projection decrypts the signing keys and certificates
+	oidcKey, err := crypto.NewAESCrypto(rotations[crypto.KeyPurposeOIDC].ApplyDecryption(config.EncryptionKeys.OIDC), keyStorage)
+	if err != nil {
+		return err
+	}
+	samlKey, err := crypto.NewAESCrypto(config.EncryptionKeys.SAML, keyStorage)
+	if err != nil {
+		return err
+	}
+
+	config.Eventstore.Client = dbClient
+	config.Eventstore.PIIKeyStorage = keyStorage
+	es, err := eventstore.Start(config.Eventstore)
+	if err != nil {
+		return err
+	}
+
+	query.RegisterEventMappers(es)
+	if err = projection.Create(ctx, dbClient, es, config.Projections, oidcKey, samlKey); err != nil {
+		return err
+	}
+	// the progress is logged by the rebuild
+	return projection.Rebuild(ctx, name, nil)
+}
@@ -26,6 +26,9 @@ var (
 	createEventsStmt         string
 	createSystemSequenceStmt string
 	createUniqueConstraints  string
+	createIDMachineLeases    string
+	createPIIKeys            string
+	createCodeCopies         string
 
 	roleAlreadyExistsCode = "42710"
 	dbAlreadyExistsCode   = "42P04"
@@ -146,6 +149,21 @@ func ReadStmts(typ string) (err error) {
 		return err
 	}
 
+	createIDMachineLeases, err = readStmt(typ, "11_id_machine_leases_table")
+	if err != nil {
+		return err
+	}
+
+	createPIIKeys, err = readStmt(typ, "12_pii_keys_table")
+	if err != nil {
+		return err
+	}
+
+	createCodeCopies, err = readStmt(typ, "13_code_copies_table")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
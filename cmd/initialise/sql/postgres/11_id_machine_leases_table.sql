@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS system.id_machine_leases (
+    machine_id INT4 NOT NULL,
+    owner TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (machine_id)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS system.code_copies (
+    instance_id TEXT NOT NULL,
+    hash BYTEA NOT NULL,
+    copy JSONB NOT NULL,
+    expires_at TIMESTAMPTZ,
+
+    PRIMARY KEY (instance_id, hash)
+)
\ No newline at end of file
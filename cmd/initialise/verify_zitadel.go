@@ -62,6 +62,18 @@ func VerifyZitadel(db *sql.DB, config database.Config) error {
 	if err := exec(db, createUniqueConstraints, nil); err != nil {
 		return err
 	}
+
+	if err := exec(db, createIDMachineLeases, nil); err != nil {
+		return err
+	}
+
+	if err := exec(db, createPIIKeys, nil); err != nil {
+		return err
+	}
+
+	if err := exec(db, createCodeCopies, nil); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package key
 
 import (
+	"database/sql"
 	"io"
 	"os"
 	"strings"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/zitadel/zitadel/internal/crypto"
 	cryptoDB "github.com/zitadel/zitadel/internal/crypto/database"
+	"github.com/zitadel/zitadel/internal/crypto/kms"
 	"github.com/zitadel/zitadel/internal/database"
 )
 
@@ -21,7 +23,17 @@ const (
 )
 
 type Config struct {
-	Database database.Config
+	Database       database.Config
+	EncryptionKeys *encryptionKeyConfig
+	KMS            *kms.Config
+}
+
+// Storage stores the encryption keys and their rotations
+type Storage interface {
+	crypto.KeyStorage
+	crypto.KeyRotationStorage
+	crypto.PIIKeyStorage
+	crypto.CodeCopyStorage
 }
 
 func New() *cobra.Command {
@@ -30,7 +42,13 @@ func New() *cobra.Command {
 		Short: "manage encryption keys",
 	}
 	AddMasterKeyFlag(cmd)
-	cmd.AddCommand(newKey())
+	cmd.AddCommand(
+		newKey(),
+		newRotate(),
+		newRotations(),
+		newDelete(),
+		newWrap(),
+	)
 	return cmd
 }
 
@@ -38,7 +56,7 @@ func newKey() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "new [keyID=key]... [-f file]",
 		Short: "create new encryption key(s)",
-		Long: `create new encryption key(s) (encrypted by the provided master key)
+		Long: `create new encryption key(s) (encrypted by the provided master key or wrapped by the configured KMS)
 provide key(s) by YAML file and/or by argument
 Requirements:
 - cockroachdb`,
@@ -66,11 +84,11 @@ new -f keys.yaml key2=anotherkey`,
 			if err := viper.Unmarshal(config); err != nil {
 				return err
 			}
-			masterKey, err := MasterKey(cmd)
+			masterKey, err := MasterKeyIfRequired(cmd, config.KMS)
 			if err != nil {
 				return err
 			}
-			storage, err := keyStorage(config.Database, masterKey)
+			storage, err := keyStorage(config, masterKey)
 			if err != nil {
 				return err
 			}
@@ -123,10 +141,23 @@ func openFile(fileName string) (io.Reader, error) {
 	return file, nil
 }
 
-func keyStorage(config database.Config, masterKey string) (crypto.KeyStorage, error) {
-	db, err := database.Connect(config, false)
+func keyStorage(config *Config, masterKey string) (Storage, error) {
+	db, err := database.Connect(config.Database, false)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyStorage(db.DB, masterKey, config.KMS)
+}
+
+// NewKeyStorage returns the storage of the encryption keys,
+// which are wrapped by the KMS if configured and by the masterkey otherwise
+func NewKeyStorage(client *sql.DB, masterKey string, kmsConfig *kms.Config) (Storage, error) {
+	if !kmsConfig.Enabled() {
+		return cryptoDB.NewKeyStorage(client, masterKey)
+	}
+	wrapper, err := kmsConfig.NewKeyWrapper()
 	if err != nil {
 		return nil, err
 	}
-	return cryptoDB.NewKeyStorage(db.DB, masterKey)
+	return cryptoDB.NewKMSKeyStorage(client, wrapper), nil
 }
@@ -0,0 +1,62 @@
+package key
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	cryptoDB "github.com/zitadel/zitadel/internal/crypto/database"
+	"github.com/zitadel/zitadel/internal/database"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+func newWrap() *cobra.Command {
+	return &cobra.Command{
+		Use:   "wrap",
+		Short: "wrap the encryption keys by the KMS",
+		Long: `wrap the encryption keys, which are encrypted by the provided master key, by the configured KMS
+the master key isn't required anymore afterwards
+Requirements:
+- cockroachdb`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := new(Config)
+			if err := viper.Unmarshal(config); err != nil {
+				return err
+			}
+			if !config.KMS.Enabled() {
+				return caos_errs.ThrowPreconditionFailed(nil, "KEY-ahX3o", "no KMS configured")
+			}
+			masterKey, err := MasterKey(cmd)
+			if err != nil {
+				return err
+			}
+			wrapper, err := config.KMS.NewKeyWrapper()
+			if err != nil {
+				return err
+			}
+			db, err := database.Connect(config.Database, false)
+			if err != nil {
+				return err
+			}
+			masterKeyStorage, err := cryptoDB.NewKeyStorage(db.DB, masterKey)
+			if err != nil {
+				return err
+			}
+			readKeys, err := masterKeyStorage.ReadKeys()
+			if err != nil {
+				return err
+			}
+			keys := make([]*crypto.Key, 0, len(readKeys))
+			for id, value := range readKeys {
+				keys = append(keys, &crypto.Key{ID: id, Value: value})
+			}
+			if err = cryptoDB.NewKMSKeyStorage(db.DB, wrapper).UpdateKeys(keys...); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "wrapped %d keys by the %s KMS\n", len(keys), config.KMS.Type)
+			return nil
+		},
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/zitadel/zitadel/internal/crypto/kms"
 )
 
 const (
@@ -49,6 +51,15 @@ func MasterKey(cmd *cobra.Command) (string, error) {
 	return string(data), nil
 }
 
+// MasterKeyIfRequired returns the masterkey,
+// which isn't required if the encryption keys are wrapped by a KMS
+func MasterKeyIfRequired(cmd *cobra.Command, kmsConfig *kms.Config) (string, error) {
+	if kmsConfig.Enabled() {
+		return "", nil
+	}
+	return MasterKey(cmd)
+}
+
 func checkSingleFlag(masterKeyFile, masterKeyFromArg string, masterKeyFromEnv bool) error {
 	var flags int
 	if masterKeyFile != "" {
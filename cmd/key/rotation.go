@@ -0,0 +1,213 @@
+package key
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+type encryptionKeyConfig struct {
+	DomainVerification   *crypto.KeyConfig
+	IDPConfig            *crypto.KeyConfig
+	OIDC                 *crypto.KeyConfig
+	SAML                 *crypto.KeyConfig
+	OTP                  *crypto.KeyConfig
+	SMS                  *crypto.KeyConfig
+	SMTP                 *crypto.KeyConfig
+	User                 *crypto.KeyConfig
+	EventWebhook         *crypto.KeyConfig
+	CSRFCookieKeyID      string
+	UserAgentCookieKeyID string
+	CodeHashKeyID        string
+}
+
+func (c *encryptionKeyConfig) purpose(purpose crypto.KeyPurpose) *crypto.KeyConfig {
+	switch purpose {
+	case crypto.KeyPurposeOTP:
+		return c.OTP
+	case crypto.KeyPurposeSMS:
+		return c.SMS
+	case crypto.KeyPurposeSMTP:
+		return c.SMTP
+	case crypto.KeyPurposeIDPConfig:
+		return c.IDPConfig
+	case crypto.KeyPurposeOIDC:
+		return c.OIDC
+	}
+	return nil
+}
+
+// permanentKeyIDs returns the keys which are still used regardless of rotations,
+// e.g. the oidc key which encrypts the tokens
+func (c *encryptionKeyConfig) permanentKeyIDs() []string {
+	ids := []string{c.CSRFCookieKeyID, c.UserAgentCookieKeyID, c.CodeHashKeyID}
+	if c.OIDC != nil {
+		ids = append(ids, c.OIDC.EncryptionKeyID)
+	}
+	for _, config := range []*crypto.KeyConfig{c.DomainVerification, c.SAML, c.User, c.EventWebhook} {
+		if config != nil {
+			ids = append(ids, append([]string{config.EncryptionKeyID}, config.DecryptionKeyIDs...)...)
+		}
+	}
+	return ids
+}
+
+func newRotate() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate [purpose] [keyID]",
+		Short: "rotate the encryption key of a purpose",
+		Long: `rotate the encryption key of a purpose (otp, sms, smtp, idpconfig or oidc)
+the key must already exist (see: keys new)
+ZITADEL encrypts new values with the rotated key after its restart
+and re-encrypts the existing secrets in the background (see: keys rotations)
+Requirements:
+- cockroachdb`,
+		Example: `rotate otp otpKey2`,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			purpose, err := crypto.ParseKeyPurpose(args[0])
+			if err != nil {
+				return err
+			}
+			config, storage, err := rotationConfigAndStorage(cmd)
+			if err != nil {
+				return err
+			}
+			if _, err = storage.ReadKey(args[1]); err != nil {
+				return err
+			}
+			rotations, err := storage.ReadKeyRotations()
+			if err != nil {
+				return err
+			}
+			rotation, err := crypto.NewKeyRotation(purpose, args[1], config.EncryptionKeys.purpose(purpose), rotations[purpose])
+			if err != nil {
+				return err
+			}
+			if err = storage.SetKeyRotation(rotation); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "rotated %s to key %s, restart ZITADEL to encrypt with the new key\n", purpose, rotation.KeyID)
+			return nil
+		},
+	}
+}
+
+func newRotations() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotations",
+		Short: "show the progress of the key rotations",
+		Long: `show the progress of the key rotations
+the secrets are re-encrypted as soon as all running processes confirmed the rotated key
+the previous keys of a rotation can be deleted as soon as no secrets remain (see: keys delete)
+Requirements:
+- cockroachdb`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, storage, err := rotationConfigAndStorage(cmd)
+			if err != nil {
+				return err
+			}
+			rotations, err := storage.ReadKeyRotations()
+			if err != nil {
+				return err
+			}
+			purposes := make([]string, 0, len(rotations))
+			for purpose := range rotations {
+				purposes = append(purposes, string(purpose))
+			}
+			sort.Strings(purposes)
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "PURPOSE\tKEY\tPREVIOUS KEYS\tROTATED AT\tCONFIRMED AT\tREMAINING\tCHECKED AT\tDONE")
+			for _, purpose := range purposes {
+				rotation := rotations[crypto.KeyPurpose(purpose)]
+				confirmedAt, remaining, checkedAt := "-", "-", "-"
+				if rotation.Confirmed() {
+					confirmedAt = rotation.ConfirmedAt.Format(time.RFC3339)
+				}
+				if !rotation.CheckedAt.IsZero() {
+					remaining = fmt.Sprint(rotation.Remaining)
+					checkedAt = rotation.CheckedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%t\n",
+					purpose,
+					rotation.KeyID,
+					strings.Join(rotation.PreviousKeyIDs, ","),
+					rotation.RotatedAt.Format(time.RFC3339),
+					confirmedAt,
+					remaining,
+					checkedAt,
+					rotation.Done(),
+				)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newDelete() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete [keyID]...",
+		Short: "delete previous encryption keys",
+		Long: `delete previous encryption keys of finished rotations (see: keys rotations)
+keys which are still in use can't be deleted
+Requirements:
+- cockroachdb`,
+		Example: `delete otpKey`,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, storage, err := rotationConfigAndStorage(cmd)
+			if err != nil {
+				return err
+			}
+			rotations, err := storage.ReadKeyRotations()
+			if err != nil {
+				return err
+			}
+			if err = checkKeysDeletable(args, rotations, config.EncryptionKeys.permanentKeyIDs()); err != nil {
+				return err
+			}
+			return storage.DeleteRotatedKeys(args...)
+		},
+	}
+}
+
+func checkKeysDeletable(ids []string, rotations crypto.KeyRotations, permanentKeyIDs []string) error {
+	for _, id := range ids {
+		for _, permanent := range permanentKeyIDs {
+			if id == permanent {
+				return caos_errs.ThrowPreconditionFailedf(nil, "KEY-Ohng4", "key %s is in use", id)
+			}
+		}
+		if !rotations.DeletableKeyID(id) {
+			return caos_errs.ThrowPreconditionFailedf(nil, "KEY-yai5E", "key %s is not a previous key of a finished rotation", id)
+		}
+	}
+	return nil
+}
+
+func rotationConfigAndStorage(cmd *cobra.Command) (*Config, Storage, error) {
+	config := new(Config)
+	if err := viper.Unmarshal(config); err != nil {
+		return nil, nil, err
+	}
+	if config.EncryptionKeys == nil {
+		config.EncryptionKeys = new(encryptionKeyConfig)
+	}
+	masterKey, err := MasterKeyIfRequired(cmd, config.KMS)
+	if err != nil {
+		return nil, nil, err
+	}
+	storage, err := keyStorage(config, masterKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, storage, nil
+}
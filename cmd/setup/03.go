@@ -13,7 +13,6 @@ import (
 	"github.com/zitadel/zitadel/internal/command"
 	"github.com/zitadel/zitadel/internal/config/systemdefaults"
 	"github.com/zitadel/zitadel/internal/crypto"
-	crypto_db "github.com/zitadel/zitadel/internal/crypto/database"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 )
@@ -29,7 +28,8 @@ type FirstInstance struct {
 	userEncryptionKey *crypto.KeyConfig
 	smtpEncryptionKey *crypto.KeyConfig
 	oidcEncryptionKey *crypto.KeyConfig
-	masterKey         string
+	codeHashKeyID     string
+	keyStorage        crypto.KeyStorage
 	db                *sql.DB
 	es                *eventstore.Eventstore
 	defaults          systemdefaults.SystemDefaults
@@ -41,17 +41,23 @@ type FirstInstance struct {
 }
 
 func (mig *FirstInstance) Execute(ctx context.Context) error {
-	keyStorage, err := crypto_db.NewKeyStorage(mig.db, mig.masterKey)
+	keyStorage := mig.keyStorage
+	if err := verifyKey(mig.userEncryptionKey, keyStorage); err != nil {
+		return err
+	}
+	userEncryption, err := crypto.NewAESCrypto(mig.userEncryptionKey, keyStorage)
 	if err != nil {
-		return fmt.Errorf("cannot start key storage: %w", err)
+		return err
 	}
-	if err = verifyKey(mig.userEncryptionKey, keyStorage); err != nil {
+	if err = verifyKey(&crypto.KeyConfig{EncryptionKeyID: mig.codeHashKeyID}, keyStorage); err != nil {
 		return err
 	}
-	userAlg, err := crypto.NewAESCrypto(mig.userEncryptionKey, keyStorage)
+	codeHashKey, err := crypto.LoadKey(mig.codeHashKeyID, keyStorage)
 	if err != nil {
 		return err
 	}
+	copies, _ := keyStorage.(crypto.CodeCopyStorage)
+	userAlg := crypto.NewCodeAlgorithm(userEncryption, crypto.NewHMACHasher([]byte(codeHashKey)), copies)
 
 	if err = verifyKey(mig.smtpEncryptionKey, keyStorage); err != nil {
 		return err
@@ -84,6 +90,8 @@ func (mig *FirstInstance) Execute(ctx context.Context) error {
 		userAlg,
 		nil,
 		oidcEncryption,
+		oidcEncryption,
+		nil,
 		nil,
 		nil,
 		nil,
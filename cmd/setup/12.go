@@ -0,0 +1,25 @@
+package setup
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+)
+
+var (
+	//go:embed 12.sql
+	createSnapshotsTable string
+)
+
+type SnapshotsTable struct {
+	dbClient *sql.DB
+}
+
+func (mig *SnapshotsTable) Execute(ctx context.Context) error {
+	_, err := mig.dbClient.ExecContext(ctx, createSnapshotsTable)
+	return err
+}
+
+func (mig *SnapshotsTable) String() string {
+	return "12_snapshots_table"
+}
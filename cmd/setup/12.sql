@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS eventstore.snapshots (
+    instance_id TEXT NOT NULL,
+    snapshot_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    query_hash TEXT NOT NULL,
+    version TEXT NOT NULL,
+    event_sequence BIGINT NOT NULL,
+    resource_owner TEXT NOT NULL,
+    aggregate_creation_date TIMESTAMPTZ,
+    change_date TIMESTAMPTZ,
+    snapshot_data JSONB NOT NULL,
+    creation_date TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (instance_id, snapshot_type, aggregate_id, query_hash)
+);
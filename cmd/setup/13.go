@@ -0,0 +1,25 @@
+package setup
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+)
+
+var (
+	//go:embed 13.sql
+	createKeyRotationsTable string
+)
+
+type KeyRotationsTable struct {
+	dbClient *sql.DB
+}
+
+func (mig *KeyRotationsTable) Execute(ctx context.Context) error {
+	_, err := mig.dbClient.ExecContext(ctx, createKeyRotationsTable)
+	return err
+}
+
+func (mig *KeyRotationsTable) String() string {
+	return "13_key_rotations_table"
+}
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS system.encryption_key_rotations (
+    purpose TEXT NOT NULL,
+    key_id TEXT NOT NULL,
+    previous_key_ids TEXT[],
+    rotated_at TIMESTAMPTZ NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    remaining BIGINT,
+    checked_at TIMESTAMPTZ,
+
+    PRIMARY KEY (purpose)
+);
+
+CREATE TABLE IF NOT EXISTS system.encryption_key_nodes (
+    node_id TEXT NOT NULL,
+    purpose TEXT NOT NULL,
+    key_id TEXT NOT NULL,
+    confirmed_at TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (node_id, purpose)
+);
@@ -0,0 +1,25 @@
+package setup
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+)
+
+var (
+	//go:embed 14.sql
+	createIDMachineLeasesTable string
+)
+
+type IDMachineLeasesTable struct {
+	dbClient *sql.DB
+}
+
+func (mig *IDMachineLeasesTable) Execute(ctx context.Context) error {
+	_, err := mig.dbClient.ExecContext(ctx, createIDMachineLeasesTable)
+	return err
+}
+
+func (mig *IDMachineLeasesTable) String() string {
+	return "14_id_machine_leases_table"
+}
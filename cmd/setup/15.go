@@ -0,0 +1,25 @@
+package setup
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+)
+
+var (
+	//go:embed 15.sql
+	createPIIKeysTable string
+)
+
+type PIIKeysTable struct {
+	dbClient *sql.DB
+}
+
+func (mig *PIIKeysTable) Execute(ctx context.Context) error {
+	_, err := mig.dbClient.ExecContext(ctx, createPIIKeysTable)
+	return err
+}
+
+func (mig *PIIKeysTable) String() string {
+	return "15_pii_keys_table"
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS system.pii_keys (
+    instance_id TEXT NOT NULL,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+
+    PRIMARY KEY (instance_id, aggregate_id)
+);
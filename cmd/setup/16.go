@@ -0,0 +1,25 @@
+package setup
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+)
+
+var (
+	//go:embed 16.sql
+	createCodeCopiesTable string
+)
+
+type CodeCopiesTable struct {
+	dbClient *sql.DB
+}
+
+func (mig *CodeCopiesTable) Execute(ctx context.Context) error {
+	_, err := mig.dbClient.ExecContext(ctx, createCodeCopiesTable)
+	return err
+}
+
+func (mig *CodeCopiesTable) String() string {
+	return "16_code_copies_table"
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/zitadel/zitadel/internal/config/hook"
 	"github.com/zitadel/zitadel/internal/config/systemdefaults"
 	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/crypto/kms"
 	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/id"
 	"github.com/zitadel/zitadel/internal/query/projection"
 )
@@ -28,9 +30,11 @@ type Config struct {
 	ExternalSecure  bool
 	Log             *logging.Config
 	EncryptionKeys  *encryptionKeyConfig
+	KMS             *kms.Config
 	DefaultInstance command.InstanceSetup
 	Machine         *id.Config
 	Projections     projection.Config
+	Eventstore      *eventstore.Config
 }
 
 func MustNewConfig(v *viper.Viper) *Config {
@@ -67,12 +71,18 @@ type Steps struct {
 	s9EventstoreIndexes2 *EventstoreIndexesNew
 	CorrectCreationDate  *CorrectCreationDate
 	AddEventCreatedAt    *AddEventCreatedAt
+	s12SnapshotsTable    *SnapshotsTable
+	s13KeyRotationsTable *KeyRotationsTable
+	s14IDMachineLeases   *IDMachineLeasesTable
+	s15PIIKeysTable      *PIIKeysTable
+	s16CodeCopiesTable   *CodeCopiesTable
 }
 
 type encryptionKeyConfig struct {
-	User *crypto.KeyConfig
-	SMTP *crypto.KeyConfig
-	OIDC *crypto.KeyConfig
+	User          *crypto.KeyConfig
+	SMTP          *crypto.KeyConfig
+	OIDC          *crypto.KeyConfig
+	CodeHashKeyID string
 }
 
 func MustNewSteps(v *viper.Viper) *Steps {
@@ -14,6 +14,7 @@ import (
 	"github.com/zitadel/zitadel/cmd/tls"
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/id"
 	"github.com/zitadel/zitadel/internal/migration"
 	"github.com/zitadel/zitadel/internal/query/projection"
 )
@@ -38,7 +39,7 @@ Requirements:
 			config := MustNewConfig(viper.GetViper())
 			steps := MustNewSteps(viper.New())
 
-			masterKey, err := key.MasterKey(cmd)
+			masterKey, err := key.MasterKeyIfRequired(cmd, config.KMS)
 			logging.OnError(err).Panic("No master key provided")
 
 			Setup(config, steps, masterKey)
@@ -64,8 +65,14 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 
 	dbClient, err := database.Connect(config.Database, false)
 	logging.OnError(err).Fatal("unable to connect to database")
+	id.ConfigureDatabase(dbClient.DB)
 
-	eventstoreClient, err := eventstore.Start(&eventstore.Config{Client: dbClient})
+	keyStorage, err := key.NewKeyStorage(dbClient.DB, masterKey, config.KMS)
+	logging.OnError(err).Fatal("unable to start key storage")
+
+	config.Eventstore.Client = dbClient
+	config.Eventstore.PIIKeyStorage = keyStorage
+	eventstoreClient, err := eventstore.Start(config.Eventstore)
 	logging.OnError(err).Fatal("unable to start eventstore")
 	migration.RegisterMappers(eventstoreClient)
 
@@ -76,7 +83,8 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 	steps.FirstInstance.userEncryptionKey = config.EncryptionKeys.User
 	steps.FirstInstance.smtpEncryptionKey = config.EncryptionKeys.SMTP
 	steps.FirstInstance.oidcEncryptionKey = config.EncryptionKeys.OIDC
-	steps.FirstInstance.masterKey = masterKey
+	steps.FirstInstance.codeHashKeyID = config.EncryptionKeys.CodeHashKeyID
+	steps.FirstInstance.keyStorage = keyStorage
 	steps.FirstInstance.db = dbClient.DB
 	steps.FirstInstance.es = eventstoreClient
 	steps.FirstInstance.defaults = config.SystemDefaults
@@ -94,6 +102,15 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 	steps.CorrectCreationDate.dbClient = dbClient
 	steps.AddEventCreatedAt.dbClient = dbClient
 	steps.AddEventCreatedAt.step10 = steps.CorrectCreationDate
+	steps.s12SnapshotsTable = &SnapshotsTable{dbClient: dbClient.DB}
+	steps.s13KeyRotationsTable = &KeyRotationsTable{dbClient: dbClient.DB}
+	steps.s14IDMachineLeases = &IDMachineLeasesTable{dbClient: dbClient.DB}
+	steps.s15PIIKeysTable = &PIIKeysTable{dbClient: dbClient.DB}
+	steps.s16CodeCopiesTable = &CodeCopiesTable{dbClient: dbClient.DB}
+
+	// the machine ids are leased from the table as soon as the first id is generated by the projections
+	err = migration.Migrate(ctx, eventstoreClient, steps.s14IDMachineLeases)
+	logging.OnError(err).Fatal("unable to migrate step 14")
 
 	err = projection.Create(ctx, dbClient, eventstoreClient, config.Projections, nil, nil)
 	logging.OnError(err).Fatal("unable to start projections")
@@ -133,6 +150,14 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 	logging.OnError(err).Fatal("unable to migrate step 10")
 	err = migration.Migrate(ctx, eventstoreClient, steps.AddEventCreatedAt)
 	logging.OnError(err).Fatal("unable to migrate step 11")
+	err = migration.Migrate(ctx, eventstoreClient, steps.s12SnapshotsTable)
+	logging.OnError(err).Fatal("unable to migrate step 12")
+	err = migration.Migrate(ctx, eventstoreClient, steps.s13KeyRotationsTable)
+	logging.OnError(err).Fatal("unable to migrate step 13")
+	err = migration.Migrate(ctx, eventstoreClient, steps.s15PIIKeysTable)
+	logging.OnError(err).Fatal("unable to migrate step 15")
+	err = migration.Migrate(ctx, eventstoreClient, steps.s16CodeCopiesTable)
+	logging.OnError(err).Fatal("unable to migrate step 16")
 
 	for _, repeatableStep := range repeatableSteps {
 		err = migration.Migrate(ctx, eventstoreClient, repeatableStep)
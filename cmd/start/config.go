@@ -21,8 +21,10 @@ import (
 	"github.com/zitadel/zitadel/internal/config/network"
 	"github.com/zitadel/zitadel/internal/config/systemdefaults"
 	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/crypto/kms"
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/archive"
 	"github.com/zitadel/zitadel/internal/id"
 	"github.com/zitadel/zitadel/internal/logstore"
 	"github.com/zitadel/zitadel/internal/notification/handlers"
@@ -41,6 +43,7 @@ type Config struct {
 	TLS               network.TLS
 	HTTP2HostHeader   string
 	HTTP1HostHeader   string
+	TrustedProxies    []string
 	WebAuthNName      string
 	Database          database.Config
 	Tracing           tracing.Config
@@ -57,6 +60,9 @@ type Config struct {
 	InternalAuthZ     internal_authz.Config
 	SystemDefaults    systemdefaults.SystemDefaults
 	EncryptionKeys    *encryptionKeyConfig
+	KMS               *kms.Config
+	KeyRotation       *KeyRotationConfig
+	LDAPSync          *LDAPSyncConfig
 	DefaultInstance   command.InstanceSetup
 	AuditLogRetention time.Duration
 	SystemAPIUsers    map[string]*internal_authz.SystemAPIUser
@@ -64,9 +70,11 @@ type Config struct {
 	Machine           *id.Config
 	Actions           *actions.Config
 	Eventstore        *eventstore.Config
+	EventArchive      *archive.Config
 	LogStore          *logstore.Configs
 	Quotas            *QuotasConfig
 	Telemetry         *handlers.TelemetryPusherConfig
+	EventWebhooks     *handlers.EventWebhooksConfig
 }
 
 type QuotasConfig struct {
@@ -113,6 +121,8 @@ type encryptionKeyConfig struct {
 	SMS                  *crypto.KeyConfig
 	SMTP                 *crypto.KeyConfig
 	User                 *crypto.KeyConfig
+	EventWebhook         *crypto.KeyConfig
 	CSRFCookieKeyID      string
 	UserAgentCookieKeyID string
+	CodeHashKeyID        string
 }
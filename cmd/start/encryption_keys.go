@@ -15,8 +15,10 @@ var (
 		"smsKey",
 		"smtpKey",
 		"userKey",
+		"eventWebhookKey",
 		"csrfCookieKey",
 		"userAgentCookieKey",
+		"codeHashKey",
 	}
 )
 
@@ -24,11 +26,13 @@ type encryptionKeys struct {
 	DomainVerification crypto.EncryptionAlgorithm
 	IDPConfig          crypto.EncryptionAlgorithm
 	OIDC               crypto.EncryptionAlgorithm
+	OIDCKeyPairs       crypto.EncryptionAlgorithm
 	SAML               crypto.EncryptionAlgorithm
 	OTP                crypto.EncryptionAlgorithm
 	SMS                crypto.EncryptionAlgorithm
 	SMTP               crypto.EncryptionAlgorithm
 	User               crypto.EncryptionAlgorithm
+	EventWebhook       crypto.EncryptionAlgorithm
 	CSRFCookieKey      []byte
 	UserAgentCookieKey []byte
 	OIDCKey            []byte
@@ -38,16 +42,25 @@ func ensureEncryptionKeys(keyConfig *encryptionKeyConfig, keyStorage crypto.KeyS
 	if err := verifyDefaultKeys(keyStorage); err != nil {
 		return nil, err
 	}
+	rotations, err := readKeyRotations(keyStorage)
+	if err != nil {
+		return nil, err
+	}
 	keys = new(encryptionKeys)
 	keys.DomainVerification, err = crypto.NewAESCrypto(keyConfig.DomainVerification, keyStorage)
 	if err != nil {
 		return nil, err
 	}
-	keys.IDPConfig, err = crypto.NewAESCrypto(keyConfig.IDPConfig, keyStorage)
+	keys.IDPConfig, err = crypto.NewAESCrypto(rotations[crypto.KeyPurposeIDPConfig].Apply(keyConfig.IDPConfig), keyStorage)
+	if err != nil {
+		return nil, err
+	}
+	// tokens are encrypted without key id, so only the signing keys are encrypted with the rotated oidc key
+	keys.OIDC, err = crypto.NewAESCrypto(rotations[crypto.KeyPurposeOIDC].ApplyDecryption(keyConfig.OIDC), keyStorage)
 	if err != nil {
 		return nil, err
 	}
-	keys.OIDC, err = crypto.NewAESCrypto(keyConfig.OIDC, keyStorage)
+	keys.OIDCKeyPairs, err = crypto.NewAESCrypto(rotations[crypto.KeyPurposeOIDC].Apply(keyConfig.OIDC), keyStorage)
 	if err != nil {
 		return nil, err
 	}
@@ -60,19 +73,30 @@ func ensureEncryptionKeys(keyConfig *encryptionKeyConfig, keyStorage crypto.KeyS
 		return nil, err
 	}
 	keys.OIDCKey = []byte(key)
-	keys.OTP, err = crypto.NewAESCrypto(keyConfig.OTP, keyStorage)
+	keys.OTP, err = crypto.NewAESCrypto(rotations[crypto.KeyPurposeOTP].Apply(keyConfig.OTP), keyStorage)
 	if err != nil {
 		return nil, err
 	}
-	keys.SMS, err = crypto.NewAESCrypto(keyConfig.SMS, keyStorage)
+	keys.SMS, err = crypto.NewAESCrypto(rotations[crypto.KeyPurposeSMS].Apply(keyConfig.SMS), keyStorage)
 	if err != nil {
 		return nil, err
 	}
-	keys.SMTP, err = crypto.NewAESCrypto(keyConfig.SMTP, keyStorage)
+	keys.SMTP, err = crypto.NewAESCrypto(rotations[crypto.KeyPurposeSMTP].Apply(keyConfig.SMTP), keyStorage)
 	if err != nil {
 		return nil, err
 	}
-	keys.User, err = crypto.NewAESCrypto(keyConfig.User, keyStorage)
+	userEncryption, err := crypto.NewAESCrypto(keyConfig.User, keyStorage)
+	if err != nil {
+		return nil, err
+	}
+	key, err = crypto.LoadKey(keyConfig.CodeHashKeyID, keyStorage)
+	if err != nil {
+		return nil, err
+	}
+	// the one-time codes of the users are hashed instead of encrypted if the secret generator requires it
+	copies, _ := keyStorage.(crypto.CodeCopyStorage)
+	keys.User = crypto.NewCodeAlgorithm(userEncryption, crypto.NewHMACHasher([]byte(key)), copies)
+	keys.EventWebhook, err = crypto.NewAESCrypto(keyConfig.EventWebhook, keyStorage)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +113,31 @@ func ensureEncryptionKeys(keyConfig *encryptionKeyConfig, keyStorage crypto.KeyS
 	return keys, nil
 }
 
+// rotatedKeyIDs returns the keys which encrypt the secrets of the rotatable purposes
+func (k *encryptionKeys) rotatedKeyIDs() map[crypto.KeyPurpose]string {
+	return map[crypto.KeyPurpose]string{
+		crypto.KeyPurposeOTP:       k.OTP.EncryptionKeyID(),
+		crypto.KeyPurposeSMS:       k.SMS.EncryptionKeyID(),
+		crypto.KeyPurposeSMTP:      k.SMTP.EncryptionKeyID(),
+		crypto.KeyPurposeIDPConfig: k.IDPConfig.EncryptionKeyID(),
+		crypto.KeyPurposeOIDC:      k.OIDCKeyPairs.EncryptionKeyID(),
+	}
+}
+
+// readKeyRotations returns the rotated encryption keys
+// if the storage supports rotations
+func readKeyRotations(keyStorage crypto.KeyStorage) (crypto.KeyRotations, error) {
+	rotationStorage, ok := keyStorage.(crypto.KeyRotationStorage)
+	if !ok {
+		return nil, nil
+	}
+	rotations, err := rotationStorage.ReadKeyRotations()
+	if err != nil {
+		return nil, caos_errs.ThrowInternal(err, "START-Eeph1", "cannot read key rotations")
+	}
+	return rotations, nil
+}
+
 func verifyDefaultKeys(keyStorage crypto.KeyStorage) (err error) {
 	keys := make([]*crypto.Key, 0, len(defaultKeyIDs))
 	for _, keyID := range defaultKeyIDs {
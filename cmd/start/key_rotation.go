@@ -0,0 +1,130 @@
+package start
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/command"
+	"github.com/zitadel/zitadel/internal/crypto"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+type KeyRotationConfig struct {
+	Enabled bool
+	// Interval defines how often the secrets of rotated keys are re-encrypted
+	Interval time.Duration
+	// NodeTimeout is the time after which a process which didn't confirm its keys is considered stopped.
+	// The secrets of a rotation are only re-encrypted after all running processes confirmed the rotated key.
+	NodeTimeout time.Duration
+	// BulkLimit is the maximum amount of secrets re-encrypted per instance and purpose in one run
+	BulkLimit int
+}
+
+// startKeyRotation confirms the keys used by this process periodically
+// and re-encrypts the secrets of unfinished key rotations in the background,
+// as soon as all running processes confirmed the rotated keys.
+// It stores the progress, so the previous keys can be deleted as soon as no secrets remain
+func startKeyRotation(ctx context.Context, config *KeyRotationConfig, keyStorage crypto.KeyStorage, keys *encryptionKeys, commands *command.Commands) error {
+	storage, ok := keyStorage.(crypto.KeyRotationStorage)
+	if config == nil || !ok {
+		return nil
+	}
+	if config.NodeTimeout <= 0 || (config.Enabled && config.Interval <= 0) {
+		return caos_errs.ThrowInvalidArgument(nil, "START-Ied4a", "interval and node timeout of the key rotation must be positive")
+	}
+	nodeID, err := keyRotationNodeID()
+	if err != nil {
+		return err
+	}
+	keyIDs := keys.rotatedKeyIDs()
+	// the keys are confirmed before the process serves, even if the re-encryption is disabled
+	if err = storage.ConfirmKeyRotations(nodeID, keyIDs, time.Now().Add(-config.NodeTimeout)); err != nil {
+		return err
+	}
+	go confirmKeyRotations(ctx, config.NodeTimeout, storage, nodeID, keyIDs)
+	if !config.Enabled {
+		return nil
+	}
+	go func() {
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+		for {
+			reencryptRotatedSecrets(ctx, config, storage, commands)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// confirmKeyRotations renews the confirmations of the process well within the timeout
+func confirmKeyRotations(ctx context.Context, timeout time.Duration, storage crypto.KeyRotationStorage, nodeID string, keyIDs map[crypto.KeyPurpose]string) {
+	ticker := time.NewTicker(timeout / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := storage.ConfirmKeyRotations(nodeID, keyIDs, time.Now().Add(-timeout))
+		logging.WithFields("node", nodeID).OnError(err).Warn("unable to confirm key rotations")
+	}
+}
+
+func reencryptRotatedSecrets(ctx context.Context, config *KeyRotationConfig, storage crypto.KeyRotationStorage, commands *command.Commands) {
+	rotations, err := storage.ReadKeyRotations()
+	if err != nil {
+		logging.WithError(err).Warn("unable to read key rotations")
+		return
+	}
+	for purpose, rotation := range rotations {
+		if rotation.Done() {
+			continue
+		}
+		if !rotation.Confirmed() {
+			// processes which still use a previous key couldn't decrypt the re-encrypted secrets
+			unconfirmed, err := storage.UnconfirmedKeyRotationNodes(purpose, rotation.KeyID, time.Now().Add(-config.NodeTimeout))
+			if err != nil {
+				logging.WithFields("purpose", purpose).WithError(err).Warn("unable to check key rotation confirmations")
+				continue
+			}
+			if unconfirmed > 0 {
+				logging.WithFields("purpose", purpose, "unconfirmed", unconfirmed).Info("key rotation not yet confirmed by all processes, restart them")
+				continue
+			}
+			if err = storage.SetKeyRotationConfirmed(purpose); err != nil {
+				logging.WithFields("purpose", purpose).WithError(err).Warn("unable to confirm key rotation")
+				continue
+			}
+		}
+		remaining, err := commands.ReencryptInstancesSecrets(ctx, purpose, rotation.KeyID, config.BulkLimit)
+		if err != nil {
+			logging.WithFields("purpose", purpose).WithError(err).Warn("unable to re-encrypt secrets")
+			continue
+		}
+		err = storage.SetKeyRotationProgress(purpose, remaining)
+		logging.WithFields("purpose", purpose, "remaining", remaining).OnError(err).Warn("unable to set key rotation progress")
+	}
+}
+
+// keyRotationNodeID identifies the process confirming its keys
+func keyRotationNodeID() (string, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	random := make([]byte, 8)
+	if _, err = rand.Read(random); err != nil {
+		return "", err
+	}
+	return host + "-" + hex.EncodeToString(random), nil
+}
@@ -0,0 +1,127 @@
+package start
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/command"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/handler/crdb"
+)
+
+const (
+	defaultLDAPSyncInterval = time.Hour
+	ldapSyncLocksTable      = "projections.locks"
+	ldapSyncLockDuration    = time.Minute
+)
+
+type LDAPSyncConfig struct {
+	Enabled bool
+	// Interval defines how often the directories of the LDAP identity providers are synced
+	Interval time.Duration
+	// PageSize is the amount of entries requested from the LDAP server per page
+	PageSize uint32
+	// MaxDeactivations is the maximum amount of users deactivated per identity provider and sync,
+	// if more users are missing in the directory, none of them are deactivated (0 disables the limit)
+	MaxDeactivations int
+	// DryRun only logs the changes, without applying them to the users
+	DryRun bool
+}
+
+// startLDAPSync periodically syncs the users of all LDAP identity providers with their directories.
+// Every identity provider is synced as a separate job, which is locked, so only one ZITADEL process syncs it at a time.
+func startLDAPSync(ctx context.Context, config *LDAPSyncConfig, commands *command.Commands, client *sql.DB) {
+	if config == nil || !config.Enabled {
+		return
+	}
+	interval := config.Interval
+	if interval <= 0 {
+		logging.WithFields("interval", config.Interval, "default", defaultLDAPSyncInterval).Warn("invalid ldap sync interval, default is used")
+		interval = defaultLDAPSyncInterval
+	}
+	options := &command.LDAPSyncOptions{
+		PageSize:         config.PageSize,
+		MaxDeactivations: config.MaxDeactivations,
+		DryRun:           config.DryRun,
+	}
+	lockers := make(map[string]crdb.Locker)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			syncLDAPUsers(ctx, options, commands, client, lockers)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func syncLDAPUsers(ctx context.Context, options *command.LDAPSyncOptions, commands *command.Commands, client *sql.DB, lockers map[string]crdb.Locker) {
+	jobs, err := commands.LDAPSyncJobs(ctx)
+	if err != nil {
+		logging.WithError(err).Warn("unable to sync ldap users")
+		return
+	}
+	for _, job := range jobs {
+		locker, ok := lockers[job.IDPID]
+		if !ok {
+			locker = crdb.NewLocker(client, ldapSyncLocksTable, "ldap_sync_"+job.IDPID)
+			lockers[job.IDPID] = locker
+		}
+		runLDAPSyncJob(ctx, options, commands, locker, job)
+	}
+}
+
+// runLDAPSyncJob syncs the users of the identity provider of the job,
+// if it's not already synced by another process
+func runLDAPSyncJob(ctx context.Context, options *command.LDAPSyncOptions, commands *command.Commands, locker crdb.Locker, job *command.LDAPSyncJob) {
+	lockCtx, cancel := context.WithCancel(ctx)
+	errs := locker.Lock(lockCtx, ldapSyncLockDuration, job.InstanceID)
+	if err, ok := <-errs; err != nil || !ok {
+		cancel()
+		if !errors.IsErrorAlreadyExists(err) {
+			logging.WithFields("instance", job.InstanceID, "idp", job.IDPID).OnError(err).Warn("unable to lock ldap sync")
+		}
+		return
+	}
+	// the lock is renewed until the job is done
+	go func() {
+		for err := range errs {
+			logging.WithFields("instance", job.InstanceID, "idp", job.IDPID).OnError(err).Warn("unable to renew lock of ldap sync")
+		}
+	}()
+	summary, err := commands.SyncLDAPJob(lockCtx, job, options)
+	cancel()
+	logging.WithFields("instance", job.InstanceID, "idp", job.IDPID).OnError(locker.Unlock(job.InstanceID)).Warn("unable to unlock ldap sync")
+	if err != nil {
+		logging.WithFields("instance", job.InstanceID, "idp", job.IDPID).WithError(err).Warn("unable to sync ldap users")
+		return
+	}
+	logLDAPSyncSummary(job, summary)
+}
+
+func logLDAPSyncSummary(job *command.LDAPSyncJob, summary *command.LDAPSyncSummary) {
+	logging.WithFields(
+		"instance", job.InstanceID,
+		"idp", summary.IDPID,
+		"dryRun", summary.DryRun,
+		"added", len(summary.Added),
+		"updated", len(summary.Updated),
+		"deactivated", len(summary.Deactivated),
+		"reactivated", len(summary.Reactivated),
+		"unchanged", summary.Unchanged,
+		"failed", len(summary.Failed),
+	).Info("ldap users synced")
+	if len(summary.DeactivationAborted) > 0 {
+		logging.WithFields("instance", job.InstanceID, "idp", summary.IDPID, "missing", len(summary.DeactivationAborted)).Warn("too many users missing in the directory, none of them deactivated")
+	}
+	for externalUserID, err := range summary.Failed {
+		logging.WithFields("instance", job.InstanceID, "idp", summary.IDPID, "externalUserID", externalUserID).WithError(err).Warn("unable to sync ldap user")
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/zitadel/zitadel/internal/api/oidc"
 	"github.com/zitadel/zitadel/internal/api/robots_txt"
 	"github.com/zitadel/zitadel/internal/api/saml"
+	"github.com/zitadel/zitadel/internal/api/scim"
 	"github.com/zitadel/zitadel/internal/api/ui/console"
 	"github.com/zitadel/zitadel/internal/api/ui/login"
 	auth_es "github.com/zitadel/zitadel/internal/auth/repository/eventsourcing"
@@ -50,7 +51,6 @@ import (
 	authz_es "github.com/zitadel/zitadel/internal/authz/repository/eventsourcing/eventstore"
 	"github.com/zitadel/zitadel/internal/command"
 	"github.com/zitadel/zitadel/internal/crypto"
-	cryptoDB "github.com/zitadel/zitadel/internal/crypto/database"
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
@@ -79,7 +79,7 @@ Requirements:
 				return err
 			}
 			config := MustNewConfig(viper.GetViper())
-			masterKey, err := key.MasterKey(cmd)
+			masterKey, err := key.MasterKeyIfRequired(cmd, config.KMS)
 			if err != nil {
 				return err
 			}
@@ -116,8 +116,9 @@ func startZitadel(config *Config, masterKey string, server chan<- *Server) error
 	if err != nil {
 		return fmt.Errorf("cannot start client for projection: %w", err)
 	}
+	id.ConfigureDatabase(dbClient.DB)
 
-	keyStorage, err := cryptoDB.NewKeyStorage(dbClient.DB, masterKey)
+	keyStorage, err := key.NewKeyStorage(dbClient.DB, masterKey, config.KMS)
 	if err != nil {
 		return fmt.Errorf("cannot start key storage: %w", err)
 	}
@@ -127,13 +128,19 @@ func startZitadel(config *Config, masterKey string, server chan<- *Server) error
 	}
 
 	config.Eventstore.Client = dbClient
+	config.Eventstore.PIIKeyStorage = keyStorage
 	eventstoreClient, err := eventstore.Start(config.Eventstore)
 	if err != nil {
 		return fmt.Errorf("cannot start eventstore for queries: %w", err)
 	}
+	eventstoreClient.StartPIIErasure(ctx, config.Eventstore.PII.ErasureInterval)
 
 	sessionTokenVerifier := internal_authz.SessionTokenVerifier(keys.OIDC)
 
+	if config.EventWebhooks.Enabled {
+		config.Projections.EventWebhookEvents = config.EventWebhooks.Events
+	}
+
 	queries, err := query.StartQueries(
 		ctx,
 		eventstoreClient,
@@ -188,7 +195,9 @@ func startZitadel(config *Config, masterKey string, server chan<- *Server) error
 		keys.User,
 		keys.DomainVerification,
 		keys.OIDC,
+		keys.OIDCKeyPairs,
 		keys.SAML,
+		keys.EventWebhook,
 		&http.Client{},
 		permissionCheck,
 		sessionTokenVerifier,
@@ -197,6 +206,11 @@ func startZitadel(config *Config, masterKey string, server chan<- *Server) error
 		return fmt.Errorf("cannot start commands: %w", err)
 	}
 
+	if err = startKeyRotation(ctx, config.KeyRotation, keyStorage, keys, commands); err != nil {
+		return fmt.Errorf("cannot start key rotation: %w", err)
+	}
+	startLDAPSync(ctx, config.LDAPSync, commands, dbClient.DB)
+
 	clock := clockpkg.New()
 	actionsExecutionStdoutEmitter, err := logstore.NewEmitter(ctx, clock, config.LogStore.Execution.Stdout, stdout.NewStdoutEmitter())
 	if err != nil {
@@ -214,9 +228,17 @@ func startZitadel(config *Config, masterKey string, server chan<- *Server) error
 	}
 	actions.SetLogstoreService(actionsLogstoreSvc)
 
-	notification.Start(ctx, config.Projections.Customizations["notifications"], config.Projections.Customizations["notificationsquotas"], config.Projections.Customizations["telemetry"], *config.Telemetry, config.ExternalDomain, config.ExternalPort, config.ExternalSecure, commands, queries, eventstoreClient, assets.AssetAPIFromDomain(config.ExternalSecure, config.ExternalPort), config.SystemDefaults.Notifications.FileSystemPath, keys.User, keys.SMTP, keys.SMS)
+	err = notification.Start(ctx, config.Projections.Customizations["notifications"], config.Projections.Customizations["notificationsquotas"], config.Projections.Customizations["telemetry"], *config.Telemetry, *config.EventWebhooks, config.ExternalDomain, config.ExternalPort, config.ExternalSecure, commands, queries, eventstoreClient, dbClient, assets.AssetAPIFromDomain(config.ExternalSecure, config.ExternalPort), config.SystemDefaults.Notifications.FileSystemPath, keys.User, keys.SMTP, keys.SMS, keys.EventWebhook)
+	if err != nil {
+		return fmt.Errorf("cannot start notifications: %w", err)
+	}
 
+	trustedProxies, err := http_util.ParseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
 	router := mux.NewRouter()
+	router.Use(http_util.ClientIPHandler(trustedProxies))
 	tlsConfig, err := config.TLS.Config()
 	if err != nil {
 		return err
@@ -349,11 +371,12 @@ func startAPIs(
 	}
 	instanceInterceptor := middleware.InstanceInterceptor(queries, config.HTTP1HostHeader, login.IgnoreInstanceEndpoints...)
 	assetsCache := middleware.AssetsCacheInterceptor(config.AssetStorage.Cache.MaxAge, config.AssetStorage.Cache.SharedMaxAge)
-	apis.RegisterHandlerOnPrefix(assets.HandlerPrefix, assets.NewHandler(commands, verifier, config.InternalAuthZ, id.SonyFlakeGenerator(), store, queries, middleware.CallDurationHandler, instanceInterceptor.Handler, assetsCache.Handler, limitingAccessInterceptor.Handle))
+	apis.RegisterHandlerOnPrefix(assets.HandlerPrefix, assets.NewHandler(commands, verifier, config.InternalAuthZ, id.DefaultGenerator(), store, queries, middleware.CallDurationHandler, instanceInterceptor.Handler, assetsCache.Handler, limitingAccessInterceptor.Handle))
 
 	apis.RegisterHandlerOnPrefix(idp.HandlerPrefix, idp.NewHandler(commands, queries, keys.IDPConfig, config.ExternalSecure, instanceInterceptor.Handler))
+	apis.RegisterHandlerOnPrefix(scim.HandlerPrefix, scim.NewHandler(commands, queries, verifier, config.InternalAuthZ, keys.User, config.ExternalSecure, middleware.CallDurationHandler, instanceInterceptor.Handler, limitingAccessInterceptor.Handle))
 
-	userAgentInterceptor, err := middleware.NewUserAgentHandler(config.UserAgentCookie, keys.UserAgentCookieKey, id.SonyFlakeGenerator(), config.ExternalSecure, login.EndpointResources)
+	userAgentInterceptor, err := middleware.NewUserAgentHandler(config.UserAgentCookie, keys.UserAgentCookieKey, id.DefaultGenerator(), config.ExternalSecure, login.EndpointResources, login.EndpointSAML)
 	if err != nil {
 		return err
 	}
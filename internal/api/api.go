@@ -20,6 +20,7 @@ import (
 	http_mw "github.com/zitadel/zitadel/internal/api/http/middleware"
 	"github.com/zitadel/zitadel/internal/api/ui/login"
 	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/id"
 	"github.com/zitadel/zitadel/internal/query"
 	"github.com/zitadel/zitadel/internal/telemetry/metrics"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
@@ -199,6 +200,12 @@ func (a *API) healthHandler() http.Handler {
 			}
 			return nil
 		},
+		func(ctx context.Context) error {
+			if err := id.Health(ctx); err != nil {
+				return errors.ThrowInternal(err, "API-Ohg3u", "ID GENERATOR ERROR")
+			}
+			return nil
+		},
 	}
 	handler := http.NewServeMux()
 	handler.HandleFunc("/healthz", handleHealth)
@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
+	event_grpc "github.com/zitadel/zitadel/internal/api/grpc/event"
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/query"
 	admin_pb "github.com/zitadel/zitadel/pkg/grpc/admin"
 )
 
@@ -35,6 +37,32 @@ func (s *Server) ListAggregateTypes(ctx context.Context, in *admin_pb.ListAggreg
 	return admin_pb.AggregateTypesToPb(aggregateTypes), nil
 }
 
+func (s *Server) SubscribeEvents(in *admin_pb.SubscribeEventsRequest, stream admin_pb.AdminService_SubscribeEventsServer) error {
+	return s.query.StreamEvents(stream.Context(), subscribeEventsRequestToFilter(in), in.FromSequence, s.auditLogRetention, func(event *query.Event) error {
+		pbEvent, err := event_grpc.EventToPb(event)
+		if err != nil {
+			return err
+		}
+		return stream.Send(&admin_pb.SubscribeEventsResponse{Event: pbEvent})
+	})
+}
+
+func subscribeEventsRequestToFilter(req *admin_pb.SubscribeEventsRequest) *eventstore.SearchQueryBuilder {
+	eventTypes := make([]eventstore.EventType, len(req.EventTypes))
+	for i, eventType := range req.EventTypes {
+		eventTypes[i] = eventstore.EventType(eventType)
+	}
+	aggregateTypes := make([]eventstore.AggregateType, len(req.AggregateTypes))
+	for i, aggregateType := range req.AggregateTypes {
+		aggregateTypes[i] = eventstore.AggregateType(aggregateType)
+	}
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(aggregateTypes...).
+		EventTypes(eventTypes...).
+		Builder()
+}
+
 func eventRequestToFilter(ctx context.Context, req *admin_pb.ListEventsRequest) (*eventstore.SearchQueryBuilder, error) {
 	eventTypes := make([]eventstore.EventType, len(req.EventTypes))
 	for i, eventType := range req.EventTypes {
@@ -0,0 +1,87 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/grpc/object"
+	admin_pb "github.com/zitadel/zitadel/pkg/grpc/admin"
+)
+
+func (s *Server) ListEventWebhooks(ctx context.Context, req *admin_pb.ListEventWebhooksRequest) (*admin_pb.ListEventWebhooksResponse, error) {
+	result, err := s.query.SearchEventWebhooks(ctx, listEventWebhooksToModel(req))
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.ListEventWebhooksResponse{
+		Details: object.ToListDetails(result.Count, result.Sequence, result.Timestamp),
+		Result:  EventWebhooksToPb(result.EventWebhooks),
+	}, nil
+}
+
+func (s *Server) GetEventWebhook(ctx context.Context, req *admin_pb.GetEventWebhookRequest) (*admin_pb.GetEventWebhookResponse, error) {
+	result, err := s.query.EventWebhookByID(ctx, true, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.GetEventWebhookResponse{
+		Webhook: EventWebhookToPb(result),
+	}, nil
+}
+
+func (s *Server) AddEventWebhook(ctx context.Context, req *admin_pb.AddEventWebhookRequest) (*admin_pb.AddEventWebhookResponse, error) {
+	id, signingKey, result, err := s.command.AddEventWebhook(ctx, AddEventWebhookToDomain(req))
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.AddEventWebhookResponse{
+		Details:    object.DomainToAddDetailsPb(result),
+		Id:         id,
+		SigningKey: signingKey,
+	}, nil
+}
+
+func (s *Server) UpdateEventWebhook(ctx context.Context, req *admin_pb.UpdateEventWebhookRequest) (*admin_pb.UpdateEventWebhookResponse, error) {
+	result, err := s.command.ChangeEventWebhook(ctx, UpdateEventWebhookToDomain(req))
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.UpdateEventWebhookResponse{
+		Details: object.DomainToChangeDetailsPb(result),
+	}, nil
+}
+
+func (s *Server) RegenerateEventWebhookSigningKey(ctx context.Context, req *admin_pb.RegenerateEventWebhookSigningKeyRequest) (*admin_pb.RegenerateEventWebhookSigningKeyResponse, error) {
+	signingKey, result, err := s.command.RegenerateEventWebhookSigningKey(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.RegenerateEventWebhookSigningKeyResponse{
+		Details:    object.DomainToChangeDetailsPb(result),
+		SigningKey: signingKey,
+	}, nil
+}
+
+func (s *Server) RemoveEventWebhook(ctx context.Context, req *admin_pb.RemoveEventWebhookRequest) (*admin_pb.RemoveEventWebhookResponse, error) {
+	result, err := s.command.RemoveEventWebhook(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.RemoveEventWebhookResponse{
+		Details: object.DomainToChangeDetailsPb(result),
+	}, nil
+}
+
+func (s *Server) ListEventWebhookDeliveries(ctx context.Context, req *admin_pb.ListEventWebhookDeliveriesRequest) (*admin_pb.ListEventWebhookDeliveriesResponse, error) {
+	queries, err := listEventWebhookDeliveriesToModel(req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.query.SearchEventWebhookDeliveries(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.ListEventWebhookDeliveriesResponse{
+		Details: object.ToListDetails(result.Count, result.Sequence, result.Timestamp),
+		Result:  EventWebhookDeliveriesToPb(result.Deliveries),
+	}, nil
+}
@@ -0,0 +1,109 @@
+package admin
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/zitadel/zitadel/internal/api/grpc/object"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/query"
+	admin_pb "github.com/zitadel/zitadel/pkg/grpc/admin"
+	settings_pb "github.com/zitadel/zitadel/pkg/grpc/settings"
+)
+
+func listEventWebhooksToModel(req *admin_pb.ListEventWebhooksRequest) *query.EventWebhookSearchQueries {
+	offset, limit, asc := object.ListQueryToModel(req.Query)
+	return &query.EventWebhookSearchQueries{
+		SearchRequest: query.SearchRequest{
+			Offset: offset,
+			Limit:  limit,
+			Asc:    asc,
+		},
+	}
+}
+
+func listEventWebhookDeliveriesToModel(req *admin_pb.ListEventWebhookDeliveriesRequest) (*query.EventWebhookDeliverySearchQueries, error) {
+	offset, limit, asc := object.ListQueryToModel(req.Query)
+	webhookQuery, err := query.NewEventWebhookDeliveryWebhookIDSearchQuery(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	queries := []query.SearchQuery{webhookQuery}
+	if req.FailedOnly {
+		stateQuery, err := query.NewEventWebhookDeliveryStateQuery(domain.EventWebhookDeliveryStateFailed)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, stateQuery)
+	}
+	return &query.EventWebhookDeliverySearchQueries{
+		SearchRequest: query.SearchRequest{
+			Offset: offset,
+			Limit:  limit,
+			Asc:    asc,
+		},
+		Queries: queries,
+	}, nil
+}
+
+func EventWebhooksToPb(webhooks []*query.EventWebhook) []*settings_pb.EventWebhook {
+	w := make([]*settings_pb.EventWebhook, len(webhooks))
+	for i, webhook := range webhooks {
+		w[i] = EventWebhookToPb(webhook)
+	}
+	return w
+}
+
+func EventWebhookToPb(webhook *query.EventWebhook) *settings_pb.EventWebhook {
+	return &settings_pb.EventWebhook{
+		Details:    object.ToViewDetailsPb(webhook.Sequence, webhook.CreationDate, webhook.ChangeDate, webhook.ResourceOwner),
+		Id:         webhook.ID,
+		Name:       webhook.Name,
+		CallUrl:    webhook.CallURL,
+		EventTypes: webhook.EventTypes,
+	}
+}
+
+func EventWebhookDeliveriesToPb(deliveries []*query.EventWebhookDelivery) []*settings_pb.EventWebhookDelivery {
+	d := make([]*settings_pb.EventWebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		d[i] = &settings_pb.EventWebhookDelivery{
+			WebhookId:     delivery.WebhookID,
+			EventSequence: delivery.EventSequence,
+			EventType:     delivery.EventType,
+			CreationDate:  timestamppb.New(delivery.CreationDate),
+			Attempts:      delivery.Attempts,
+			NextAttempt:   timestamppb.New(delivery.NextAttempt),
+			LastError:     delivery.LastError,
+			State:         eventWebhookDeliveryStateToPb(delivery.State),
+		}
+	}
+	return d
+}
+
+func eventWebhookDeliveryStateToPb(state domain.EventWebhookDeliveryState) settings_pb.EventWebhookDeliveryState {
+	switch state {
+	case domain.EventWebhookDeliveryStatePending:
+		return settings_pb.EventWebhookDeliveryState_EVENT_WEBHOOK_DELIVERY_STATE_PENDING
+	case domain.EventWebhookDeliveryStateFailed:
+		return settings_pb.EventWebhookDeliveryState_EVENT_WEBHOOK_DELIVERY_STATE_FAILED
+	default:
+		return settings_pb.EventWebhookDeliveryState_EVENT_WEBHOOK_DELIVERY_STATE_UNSPECIFIED
+	}
+}
+
+func AddEventWebhookToDomain(req *admin_pb.AddEventWebhookRequest) *domain.EventWebhook {
+	return &domain.EventWebhook{
+		Name:       req.Name,
+		CallURL:    req.CallUrl,
+		EventTypes: req.EventTypes,
+	}
+}
+
+func UpdateEventWebhookToDomain(req *admin_pb.UpdateEventWebhookRequest) *domain.EventWebhook {
+	return &domain.EventWebhook{
+		ID:         req.Id,
+		Name:       req.Name,
+		CallURL:    req.CallUrl,
+		EventTypes: req.EventTypes,
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	authn_grpc "github.com/zitadel/zitadel/internal/api/grpc/authn"
+	policy_grpc "github.com/zitadel/zitadel/internal/api/grpc/policy"
 	text_grpc "github.com/zitadel/zitadel/internal/api/grpc/text"
 	"github.com/zitadel/zitadel/internal/domain"
 	caos_errors "github.com/zitadel/zitadel/internal/errors"
@@ -486,6 +487,11 @@ func (s *Server) getLockoutPolicy(ctx context.Context, orgID string) (_ *managem
 	if !queriedLockout.IsDefault {
 		return &management_pb.AddCustomLockoutPolicyRequest{
 			MaxPasswordAttempts: uint32(queriedLockout.MaxPasswordAttempts),
+			MaxOtpAttempts:      uint32(queriedLockout.MaxOTPAttempts),
+			MaxPasskeyAttempts:  uint32(queriedLockout.MaxPasskeyAttempts),
+			MaxIpAttempts:       uint32(queriedLockout.MaxIPAttempts),
+			LockoutDuration:     durationpb.New(queriedLockout.LockoutDuration),
+			ProgressiveDelay:    durationpb.New(queriedLockout.ProgressiveDelay),
 		}, nil
 	}
 	return nil, nil
@@ -501,11 +507,18 @@ func (s *Server) getPasswordComplexityPolicy(ctx context.Context, orgID string)
 	}
 	if !queriedPasswordComplexity.IsDefault {
 		return &management_pb.AddCustomPasswordComplexityPolicyRequest{
-			MinLength:    queriedPasswordComplexity.MinLength,
-			HasUppercase: queriedPasswordComplexity.HasUppercase,
-			HasLowercase: queriedPasswordComplexity.HasLowercase,
-			HasNumber:    queriedPasswordComplexity.HasNumber,
-			HasSymbol:    queriedPasswordComplexity.HasSymbol,
+			MinLength:            queriedPasswordComplexity.MinLength,
+			HasUppercase:         queriedPasswordComplexity.HasUppercase,
+			HasLowercase:         queriedPasswordComplexity.HasLowercase,
+			HasNumber:            queriedPasswordComplexity.HasNumber,
+			HasSymbol:            queriedPasswordComplexity.HasSymbol,
+			RejectBreached:       queriedPasswordComplexity.RejectBreached,
+			MaxLength:            queriedPasswordComplexity.MaxLength,
+			ForbidUsername:       queriedPasswordComplexity.ForbidUsername,
+			ForbidEmailLocalPart: queriedPasswordComplexity.ForbidEmailLocalPart,
+			ForbidOrgName:        queriedPasswordComplexity.ForbidOrgName,
+			Rules:                policy_grpc.ModelPasswordRulesToPb(queriedPasswordComplexity.Rules),
+			BannedWords:          queriedPasswordComplexity.BannedWords,
 		}, nil
 	}
 	return nil, nil
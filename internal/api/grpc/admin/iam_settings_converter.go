@@ -59,6 +59,7 @@ func UpdateSecretGeneratorToConfig(req *admin_pb.UpdateSecretGeneratorRequest) *
 		IncludeLowerLetters: req.IncludeLowerLetters,
 		IncludeDigits:       req.IncludeDigits,
 		IncludeSymbols:      req.IncludeSymbols,
+		Hashed:              req.Hashed,
 	}
 }
 
@@ -70,6 +71,7 @@ func SecretGeneratorToPb(generator *query.SecretGenerator) *settings_pb.SecretGe
 		IncludeLowerLetters: generator.IncludeLowerLetters,
 		IncludeDigits:       generator.IncludeDigits,
 		IncludeSymbols:      generator.IncludeSymbols,
+		Hashed:              generator.Hashed,
 		Details:             obj_grpc.ToViewDetailsPb(generator.Sequence, generator.CreationDate, generator.ChangeDate, generator.AggregateID),
 	}
 	return mapped
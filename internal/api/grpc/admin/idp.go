@@ -405,6 +405,60 @@ func (s *Server) UpdateLDAPProvider(ctx context.Context, req *admin_pb.UpdateLDA
 	}, nil
 }
 
+func (s *Server) AddSAMLProvider(ctx context.Context, req *admin_pb.AddSAMLProviderRequest) (*admin_pb.AddSAMLProviderResponse, error) {
+	id, details, err := s.command.AddInstanceSAMLProvider(ctx, addSAMLProviderToCommand(req))
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.AddSAMLProviderResponse{
+		Id:      id,
+		Details: object_pb.DomainToAddDetailsPb(details),
+	}, nil
+}
+
+func (s *Server) UpdateSAMLProvider(ctx context.Context, req *admin_pb.UpdateSAMLProviderRequest) (*admin_pb.UpdateSAMLProviderResponse, error) {
+	details, err := s.command.UpdateInstanceSAMLProvider(ctx, req.Id, updateSAMLProviderToCommand(req))
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.UpdateSAMLProviderResponse{
+		Details: object_pb.DomainToChangeDetailsPb(details),
+	}, nil
+}
+
+func (s *Server) GetProviderClaimMappings(ctx context.Context, req *admin_pb.GetProviderClaimMappingsRequest) (*admin_pb.GetProviderClaimMappingsResponse, error) {
+	ownerQuery, err := query.NewIDPTemplateResourceOwnerSearchQuery(authz.GetInstance(ctx).InstanceID())
+	if err != nil {
+		return nil, err
+	}
+	// ensure the provider exists and belongs to the owner
+	if _, err = s.query.IDPTemplateByID(ctx, true, req.Id, false, ownerQuery); err != nil {
+		return nil, err
+	}
+	claimMappings, err := s.query.IDPClaimMappingsByID(ctx, true, req.Id, false)
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.GetProviderClaimMappingsResponse{
+		Details: object_pb.ChangeToDetailsPb(
+			claimMappings.Sequence,
+			claimMappings.ChangeDate,
+			claimMappings.ResourceOwner,
+		),
+		Mappings: idp_grpc.ClaimMappingsToPb(claimMappings.Mappings),
+	}, nil
+}
+
+func (s *Server) SetProviderClaimMappings(ctx context.Context, req *admin_pb.SetProviderClaimMappingsRequest) (*admin_pb.SetProviderClaimMappingsResponse, error) {
+	details, err := s.command.SetInstanceIDPClaimMappings(ctx, req.Id, idp_grpc.ClaimMappingsToCommand(req.Mappings))
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.SetProviderClaimMappingsResponse{
+		Details: object_pb.DomainToChangeDetailsPb(details),
+	}, nil
+}
+
 func (s *Server) DeleteProvider(ctx context.Context, req *admin_pb.DeleteProviderRequest) (*admin_pb.DeleteProviderResponse, error) {
 	details, err := s.command.DeleteInstanceProvider(ctx, req.Id)
 	if err != nil {
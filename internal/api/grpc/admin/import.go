@@ -536,9 +536,11 @@ func (s *Server) importData(ctx context.Context, orgs []*admin_pb.DataOrg) (*adm
 		if org.HumanUsers != nil {
 			for _, user := range org.GetHumanUsers() {
 				logging.Debugf("import user: %s", user.GetUserId())
-				human, passwordless, links := management.ImportHumanUserRequestToDomain(user.User)
-				human.AggregateID = user.UserId
-				_, _, err := s.command.ImportHuman(ctx, org.GetOrgId(), human, passwordless, links, initCodeGenerator, emailCodeGenerator, phoneCodeGenerator, passwordlessInitCode)
+				human, passwordless, links, err := management.ImportHumanUserRequestToDomain(user.User)
+				if err == nil {
+					human.AggregateID = user.UserId
+					_, _, err = s.command.ImportHuman(ctx, org.GetOrgId(), human, passwordless, links, initCodeGenerator, emailCodeGenerator, phoneCodeGenerator, passwordlessInitCode)
+				}
 				if err != nil {
 					errors = append(errors, &admin_pb.ImportDataError{Type: "human_user", Id: user.GetUserId(), Message: err.Error()})
 					if isCtxTimeout(ctx) {
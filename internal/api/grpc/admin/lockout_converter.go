@@ -8,5 +8,10 @@ import (
 func UpdateLockoutPolicyToDomain(p *admin.UpdateLockoutPolicyRequest) *domain.LockoutPolicy {
 	return &domain.LockoutPolicy{
 		MaxPasswordAttempts: uint64(p.MaxPasswordAttempts),
+		MaxOTPAttempts:      uint64(p.MaxOtpAttempts),
+		MaxPasskeyAttempts:  uint64(p.MaxPasskeyAttempts),
+		MaxIPAttempts:       uint64(p.MaxIpAttempts),
+		LockoutDuration:     p.LockoutDuration.AsDuration(),
+		ProgressiveDelay:    p.ProgressiveDelay.AsDuration(),
 	}
 }
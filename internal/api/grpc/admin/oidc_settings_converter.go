@@ -17,6 +17,7 @@ func OIDCSettingsToPb(config *query.OIDCSettings) *settings_pb.OIDCSettings {
 		IdTokenLifetime:            durationpb.New(config.IdTokenLifetime),
 		RefreshTokenIdleExpiration: durationpb.New(config.RefreshTokenIdleExpiration),
 		RefreshTokenExpiration:     durationpb.New(config.RefreshTokenExpiration),
+		SigningKeyAlgorithm:        config.SigningKeyAlgorithm,
 	}
 }
 
@@ -26,6 +27,7 @@ func AddOIDCConfigToConfig(req *admin_pb.AddOIDCSettingsRequest) *domain.OIDCSet
 		IdTokenLifetime:            req.IdTokenLifetime.AsDuration(),
 		RefreshTokenIdleExpiration: req.RefreshTokenIdleExpiration.AsDuration(),
 		RefreshTokenExpiration:     req.RefreshTokenExpiration.AsDuration(),
+		SigningKeyAlgorithm:        req.SigningKeyAlgorithm,
 	}
 }
 
@@ -35,5 +37,6 @@ func UpdateOIDCConfigToConfig(req *admin_pb.UpdateOIDCSettingsRequest) *domain.O
 		IdTokenLifetime:            req.IdTokenLifetime.AsDuration(),
 		RefreshTokenIdleExpiration: req.RefreshTokenIdleExpiration.AsDuration(),
 		RefreshTokenExpiration:     req.RefreshTokenExpiration.AsDuration(),
+		SigningKeyAlgorithm:        req.SigningKeyAlgorithm,
 	}
 }
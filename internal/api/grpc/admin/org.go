@@ -50,6 +50,14 @@ func (s *Server) GetOrgByID(ctx context.Context, req *admin_pb.GetOrgByIDRequest
 	return &admin_pb.GetOrgByIDResponse{Org: org_grpc.OrgViewToPb(org)}, nil
 }
 
+func (s *Server) GetOrgByIDAt(ctx context.Context, req *admin_pb.GetOrgByIDAtRequest) (*admin_pb.GetOrgByIDAtResponse, error) {
+	org, err := s.query.OrgAt(ctx, req.Id, object.PointInTimeToQuery(req.GetAt()))
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.GetOrgByIDAtResponse{Org: org_grpc.OrgViewToPb(org)}, nil
+}
+
 func (s *Server) ListOrgs(ctx context.Context, req *admin_pb.ListOrgsRequest) (*admin_pb.ListOrgsResponse, error) {
 	queries, err := listOrgRequestToModel(req)
 	if err != nil {
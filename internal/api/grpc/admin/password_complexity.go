@@ -16,6 +16,14 @@ func (s *Server) GetPasswordComplexityPolicy(ctx context.Context, _ *admin_pb.Ge
 	return &admin_pb.GetPasswordComplexityPolicyResponse{Policy: policy_grpc.ModelPasswordComplexityPolicyToPb(policy)}, nil
 }
 
+func (s *Server) GetPasswordComplexityPolicyAt(ctx context.Context, req *admin_pb.GetPasswordComplexityPolicyAtRequest) (*admin_pb.GetPasswordComplexityPolicyAtResponse, error) {
+	policy, err := s.query.PasswordComplexityPolicyAt(ctx, "", object.PointInTimeToQuery(req.GetAt()))
+	if err != nil {
+		return nil, err
+	}
+	return &admin_pb.GetPasswordComplexityPolicyAtResponse{Policy: policy_grpc.ModelPasswordComplexityPolicyToPb(policy)}, nil
+}
+
 func (s *Server) UpdatePasswordComplexityPolicy(ctx context.Context, req *admin_pb.UpdatePasswordComplexityPolicyRequest) (*admin_pb.UpdatePasswordComplexityPolicyResponse, error) {
 	result, err := s.command.ChangeDefaultPasswordComplexityPolicy(ctx, UpdatePasswordComplexityPolicyToDomain(req))
 	if err != nil {
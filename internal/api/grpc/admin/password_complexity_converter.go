@@ -1,16 +1,24 @@
 package admin
 
 import (
+	policy_grpc "github.com/zitadel/zitadel/internal/api/grpc/policy"
 	"github.com/zitadel/zitadel/internal/domain"
 	admin_pb "github.com/zitadel/zitadel/pkg/grpc/admin"
 )
 
 func UpdatePasswordComplexityPolicyToDomain(req *admin_pb.UpdatePasswordComplexityPolicyRequest) *domain.PasswordComplexityPolicy {
 	return &domain.PasswordComplexityPolicy{
-		MinLength:    uint64(req.MinLength),
-		HasLowercase: req.HasLowercase,
-		HasUppercase: req.HasUppercase,
-		HasNumber:    req.HasNumber,
-		HasSymbol:    req.HasSymbol,
+		MinLength:            uint64(req.MinLength),
+		HasLowercase:         req.HasLowercase,
+		HasUppercase:         req.HasUppercase,
+		HasNumber:            req.HasNumber,
+		HasSymbol:            req.HasSymbol,
+		RejectBreached:       req.RejectBreached,
+		MaxLength:            uint64(req.MaxLength),
+		ForbidUsername:       req.ForbidUsername,
+		ForbidEmailLocalPart: req.ForbidEmailLocalPart,
+		ForbidOrgName:        req.ForbidOrgName,
+		Rules:                policy_grpc.PasswordRulesToDomain(req.Rules),
+		BannedWords:          req.BannedWords,
 	}
 }
@@ -332,6 +332,27 @@ func LDAPAttributesToCommand(attributes *idp_pb.LDAPAttributes) idp.LDAPAttribut
 	}
 }
 
+func LDAPGroupSyncToCommand(groupSync *idp_pb.LDAPGroupSync) idp.LDAPGroupSync {
+	if groupSync == nil {
+		return idp.LDAPGroupSync{}
+	}
+	mappings := make([]idp.LDAPGroupMapping, len(groupSync.GroupMappings))
+	for i, mapping := range groupSync.GroupMappings {
+		mappings[i] = idp.LDAPGroupMapping{
+			Group:     mapping.Group,
+			ProjectID: mapping.ProjectId,
+			Roles:     mapping.Roles,
+		}
+	}
+	return idp.LDAPGroupSync{
+		GroupsAttribute:      groupSync.GroupsAttribute,
+		GroupBase:            groupSync.GroupBase,
+		GroupMemberAttribute: groupSync.GroupMemberAttribute,
+		NestedGroups:         groupSync.NestedGroups,
+		GroupMappings:        mappings,
+	}
+}
+
 func AzureADTenantToCommand(tenant *idp_pb.AzureADTenant) string {
 	if tenant == nil {
 		return string(azuread.CommonTenant)
@@ -359,6 +380,170 @@ func azureADTenantTypeToCommand(tenantType idp_pb.AzureADTenantType) azuread.Ten
 	}
 }
 
+func SAMLBindingToCommand(binding idp_pb.SAMLBinding) string {
+	switch binding {
+	case idp_pb.SAMLBinding_SAML_BINDING_POST:
+		return domain.SAMLBindingPost.URN()
+	case idp_pb.SAMLBinding_SAML_BINDING_REDIRECT:
+		return domain.SAMLBindingRedirect.URN()
+	case idp_pb.SAMLBinding_SAML_BINDING_UNSPECIFIED:
+		fallthrough
+	default:
+		return ""
+	}
+}
+
+func ClaimMappingsToCommand(mappings []*idp_pb.ClaimMapping) []*domain.IDPClaimMapping {
+	if len(mappings) == 0 {
+		return nil
+	}
+	claimMappings := make([]*domain.IDPClaimMapping, len(mappings))
+	for i, mapping := range mappings {
+		var transforms []domain.IDPClaimTransform
+		for _, transform := range mapping.Transforms {
+			transforms = append(transforms, claimTransformToDomain(transform))
+		}
+		claimMappings[i] = &domain.IDPClaimMapping{
+			Claim:      mapping.Claim,
+			Target:     claimMappingTargetToDomain(mapping.Target),
+			Key:        mapping.Key,
+			Default:    mapping.DefaultValue,
+			Transforms: transforms,
+			Separator:  mapping.Separator,
+			Roles:      mapping.Roles,
+		}
+	}
+	return claimMappings
+}
+
+func ClaimMappingsToPb(mappings []*domain.IDPClaimMapping) []*idp_pb.ClaimMapping {
+	claimMappings := make([]*idp_pb.ClaimMapping, len(mappings))
+	for i, mapping := range mappings {
+		transforms := make([]idp_pb.ClaimTransform, len(mapping.Transforms))
+		for j, transform := range mapping.Transforms {
+			transforms[j] = claimTransformToPb(transform)
+		}
+		claimMappings[i] = &idp_pb.ClaimMapping{
+			Claim:        mapping.Claim,
+			Target:       claimMappingTargetToPb(mapping.Target),
+			Key:          mapping.Key,
+			DefaultValue: mapping.Default,
+			Transforms:   transforms,
+			Separator:    mapping.Separator,
+			Roles:        mapping.Roles,
+		}
+	}
+	return claimMappings
+}
+
+func claimMappingTargetToDomain(target idp_pb.ClaimMappingTarget) domain.IDPClaimMappingTarget {
+	switch target {
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_FIRST_NAME:
+		return domain.IDPClaimMappingTargetFirstName
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_LAST_NAME:
+		return domain.IDPClaimMappingTargetLastName
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_DISPLAY_NAME:
+		return domain.IDPClaimMappingTargetDisplayName
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_NICK_NAME:
+		return domain.IDPClaimMappingTargetNickName
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_PREFERRED_USERNAME:
+		return domain.IDPClaimMappingTargetPreferredUsername
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_EMAIL:
+		return domain.IDPClaimMappingTargetEmail
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_EMAIL_VERIFIED:
+		return domain.IDPClaimMappingTargetEmailVerified
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_PHONE:
+		return domain.IDPClaimMappingTargetPhone
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_PHONE_VERIFIED:
+		return domain.IDPClaimMappingTargetPhoneVerified
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_PREFERRED_LANGUAGE:
+		return domain.IDPClaimMappingTargetPreferredLanguage
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_AVATAR_URL:
+		return domain.IDPClaimMappingTargetAvatarURL
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_PROFILE:
+		return domain.IDPClaimMappingTargetProfile
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_METADATA:
+		return domain.IDPClaimMappingTargetMetadata
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_ROLE:
+		return domain.IDPClaimMappingTargetRole
+	case idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_UNSPECIFIED:
+		fallthrough
+	default:
+		return domain.IDPClaimMappingTargetUnspecified
+	}
+}
+
+func claimMappingTargetToPb(target domain.IDPClaimMappingTarget) idp_pb.ClaimMappingTarget {
+	switch target {
+	case domain.IDPClaimMappingTargetFirstName:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_FIRST_NAME
+	case domain.IDPClaimMappingTargetLastName:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_LAST_NAME
+	case domain.IDPClaimMappingTargetDisplayName:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_DISPLAY_NAME
+	case domain.IDPClaimMappingTargetNickName:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_NICK_NAME
+	case domain.IDPClaimMappingTargetPreferredUsername:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_PREFERRED_USERNAME
+	case domain.IDPClaimMappingTargetEmail:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_EMAIL
+	case domain.IDPClaimMappingTargetEmailVerified:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_EMAIL_VERIFIED
+	case domain.IDPClaimMappingTargetPhone:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_PHONE
+	case domain.IDPClaimMappingTargetPhoneVerified:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_PHONE_VERIFIED
+	case domain.IDPClaimMappingTargetPreferredLanguage:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_PREFERRED_LANGUAGE
+	case domain.IDPClaimMappingTargetAvatarURL:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_AVATAR_URL
+	case domain.IDPClaimMappingTargetProfile:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_PROFILE
+	case domain.IDPClaimMappingTargetMetadata:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_METADATA
+	case domain.IDPClaimMappingTargetRole:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_ROLE
+	case domain.IDPClaimMappingTargetUnspecified:
+		fallthrough
+	default:
+		return idp_pb.ClaimMappingTarget_CLAIM_MAPPING_TARGET_UNSPECIFIED
+	}
+}
+
+func claimTransformToDomain(transform idp_pb.ClaimTransform) domain.IDPClaimTransform {
+	switch transform {
+	case idp_pb.ClaimTransform_CLAIM_TRANSFORM_LOWERCASE:
+		return domain.IDPClaimTransformLowercase
+	case idp_pb.ClaimTransform_CLAIM_TRANSFORM_UPPERCASE:
+		return domain.IDPClaimTransformUppercase
+	case idp_pb.ClaimTransform_CLAIM_TRANSFORM_TRIM:
+		return domain.IDPClaimTransformTrim
+	case idp_pb.ClaimTransform_CLAIM_TRANSFORM_SPLIT:
+		return domain.IDPClaimTransformSplit
+	case idp_pb.ClaimTransform_CLAIM_TRANSFORM_UNSPECIFIED:
+		fallthrough
+	default:
+		return domain.IDPClaimTransformUnspecified
+	}
+}
+
+func claimTransformToPb(transform domain.IDPClaimTransform) idp_pb.ClaimTransform {
+	switch transform {
+	case domain.IDPClaimTransformLowercase:
+		return idp_pb.ClaimTransform_CLAIM_TRANSFORM_LOWERCASE
+	case domain.IDPClaimTransformUppercase:
+		return idp_pb.ClaimTransform_CLAIM_TRANSFORM_UPPERCASE
+	case domain.IDPClaimTransformTrim:
+		return idp_pb.ClaimTransform_CLAIM_TRANSFORM_TRIM
+	case domain.IDPClaimTransformSplit:
+		return idp_pb.ClaimTransform_CLAIM_TRANSFORM_SPLIT
+	case domain.IDPClaimTransformUnspecified:
+		fallthrough
+	default:
+		return idp_pb.ClaimTransform_CLAIM_TRANSFORM_UNSPECIFIED
+	}
+}
+
 func ProvidersToPb(providers []*query.IDPTemplate) []*idp_pb.Provider {
 	list := make([]*idp_pb.Provider, len(providers))
 	for i, provider := range providers {
@@ -414,6 +599,8 @@ func providerTypeToPb(idpType domain.IDPType) idp_pb.ProviderType {
 		return idp_pb.ProviderType_PROVIDER_TYPE_GITLAB_SELF_HOSTED
 	case domain.IDPTypeGoogle:
 		return idp_pb.ProviderType_PROVIDER_TYPE_GOOGLE
+	case domain.IDPTypeSAML:
+		return idp_pb.ProviderType_PROVIDER_TYPE_SAML
 	case domain.IDPTypeUnspecified:
 		return idp_pb.ProviderType_PROVIDER_TYPE_UNSPECIFIED
 	default:
@@ -470,6 +657,10 @@ func configToPb(config *query.IDPTemplate) *idp_pb.ProviderConfig {
 		ldapConfigToPb(providerConfig, config.LDAPIDPTemplate)
 		return providerConfig
 	}
+	if config.SAMLIDPTemplate != nil {
+		samlConfigToPb(providerConfig, config.SAMLIDPTemplate)
+		return providerConfig
+	}
 	return providerConfig
 }
 
@@ -599,10 +790,34 @@ func ldapConfigToPb(providerConfig *idp_pb.ProviderConfig, template *query.LDAPI
 			UserFilters:       template.UserFilters,
 			Timeout:           timeout,
 			Attributes:        ldapAttributesToPb(template.LDAPAttributes),
+			GroupSync:         ldapGroupSyncToPb(template.LDAPGroupSync),
+		},
+	}
+}
+
+func samlConfigToPb(providerConfig *idp_pb.ProviderConfig, template *query.SAMLIDPTemplate) {
+	providerConfig.Config = &idp_pb.ProviderConfig_Saml{
+		Saml: &idp_pb.SAMLConfig{
+			MetadataXml:       template.Metadata,
+			Binding:           samlBindingToPb(template.Binding),
+			WithSignedRequest: template.WithSignedRequest,
 		},
 	}
 }
 
+func samlBindingToPb(binding string) idp_pb.SAMLBinding {
+	switch domain.SAMLBindingFromURN(binding) {
+	case domain.SAMLBindingPost:
+		return idp_pb.SAMLBinding_SAML_BINDING_POST
+	case domain.SAMLBindingRedirect:
+		return idp_pb.SAMLBinding_SAML_BINDING_REDIRECT
+	case domain.SAMLBindingUnspecified:
+		fallthrough
+	default:
+		return idp_pb.SAMLBinding_SAML_BINDING_UNSPECIFIED
+	}
+}
+
 func ldapAttributesToPb(attributes idp.LDAPAttributes) *idp_pb.LDAPAttributes {
 	return &idp_pb.LDAPAttributes{
 		IdAttribute:                attributes.IDAttribute,
@@ -620,3 +835,21 @@ func ldapAttributesToPb(attributes idp.LDAPAttributes) *idp_pb.LDAPAttributes {
 		ProfileAttribute:           attributes.ProfileAttribute,
 	}
 }
+
+func ldapGroupSyncToPb(groupSync idp.LDAPGroupSync) *idp_pb.LDAPGroupSync {
+	mappings := make([]*idp_pb.LDAPGroupMapping, len(groupSync.GroupMappings))
+	for i, mapping := range groupSync.GroupMappings {
+		mappings[i] = &idp_pb.LDAPGroupMapping{
+			Group:     mapping.Group,
+			ProjectId: mapping.ProjectID,
+			Roles:     mapping.Roles,
+		}
+	}
+	return &idp_pb.LDAPGroupSync{
+		GroupsAttribute:      groupSync.GroupsAttribute,
+		GroupBase:            groupSync.GroupBase,
+		GroupMemberAttribute: groupSync.GroupMemberAttribute,
+		NestedGroups:         groupSync.NestedGroups,
+		GroupMappings:        mappings,
+	}
+}
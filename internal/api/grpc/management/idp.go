@@ -397,6 +397,60 @@ func (s *Server) UpdateLDAPProvider(ctx context.Context, req *mgmt_pb.UpdateLDAP
 	}, nil
 }
 
+func (s *Server) AddSAMLProvider(ctx context.Context, req *mgmt_pb.AddSAMLProviderRequest) (*mgmt_pb.AddSAMLProviderResponse, error) {
+	id, details, err := s.command.AddOrgSAMLProvider(ctx, authz.GetCtxData(ctx).OrgID, addSAMLProviderToCommand(req))
+	if err != nil {
+		return nil, err
+	}
+	return &mgmt_pb.AddSAMLProviderResponse{
+		Id:      id,
+		Details: object_pb.DomainToAddDetailsPb(details),
+	}, nil
+}
+
+func (s *Server) UpdateSAMLProvider(ctx context.Context, req *mgmt_pb.UpdateSAMLProviderRequest) (*mgmt_pb.UpdateSAMLProviderResponse, error) {
+	details, err := s.command.UpdateOrgSAMLProvider(ctx, authz.GetCtxData(ctx).OrgID, req.Id, updateSAMLProviderToCommand(req))
+	if err != nil {
+		return nil, err
+	}
+	return &mgmt_pb.UpdateSAMLProviderResponse{
+		Details: object_pb.DomainToChangeDetailsPb(details),
+	}, nil
+}
+
+func (s *Server) GetProviderClaimMappings(ctx context.Context, req *mgmt_pb.GetProviderClaimMappingsRequest) (*mgmt_pb.GetProviderClaimMappingsResponse, error) {
+	ownerQuery, err := query.NewIDPTemplateResourceOwnerSearchQuery(authz.GetCtxData(ctx).OrgID)
+	if err != nil {
+		return nil, err
+	}
+	// ensure the provider exists and belongs to the owner
+	if _, err = s.query.IDPTemplateByID(ctx, true, req.Id, false, ownerQuery); err != nil {
+		return nil, err
+	}
+	claimMappings, err := s.query.IDPClaimMappingsByID(ctx, true, req.Id, false)
+	if err != nil {
+		return nil, err
+	}
+	return &mgmt_pb.GetProviderClaimMappingsResponse{
+		Details: object_pb.ChangeToDetailsPb(
+			claimMappings.Sequence,
+			claimMappings.ChangeDate,
+			claimMappings.ResourceOwner,
+		),
+		Mappings: idp_grpc.ClaimMappingsToPb(claimMappings.Mappings),
+	}, nil
+}
+
+func (s *Server) SetProviderClaimMappings(ctx context.Context, req *mgmt_pb.SetProviderClaimMappingsRequest) (*mgmt_pb.SetProviderClaimMappingsResponse, error) {
+	details, err := s.command.SetOrgIDPClaimMappings(ctx, authz.GetCtxData(ctx).OrgID, req.Id, idp_grpc.ClaimMappingsToCommand(req.Mappings))
+	if err != nil {
+		return nil, err
+	}
+	return &mgmt_pb.SetProviderClaimMappingsResponse{
+		Details: object_pb.DomainToChangeDetailsPb(details),
+	}, nil
+}
+
 func (s *Server) DeleteProvider(ctx context.Context, req *mgmt_pb.DeleteProviderRequest) (*mgmt_pb.DeleteProviderResponse, error) {
 	details, err := s.command.DeleteOrgProvider(ctx, authz.GetCtxData(ctx).OrgID, req.Id)
 	if err != nil {
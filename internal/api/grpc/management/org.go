@@ -27,6 +27,14 @@ func (s *Server) GetMyOrg(ctx context.Context, req *mgmt_pb.GetMyOrgRequest) (*m
 	return &mgmt_pb.GetMyOrgResponse{Org: org_grpc.OrgViewToPb(org)}, nil
 }
 
+func (s *Server) GetMyOrgAt(ctx context.Context, req *mgmt_pb.GetMyOrgAtRequest) (*mgmt_pb.GetMyOrgAtResponse, error) {
+	org, err := s.query.OrgAt(ctx, authz.GetCtxData(ctx).OrgID, obj_grpc.PointInTimeToQuery(req.GetAt()))
+	if err != nil {
+		return nil, err
+	}
+	return &mgmt_pb.GetMyOrgAtResponse{Org: org_grpc.OrgViewToPb(org)}, nil
+}
+
 func (s *Server) GetOrgByDomainGlobal(ctx context.Context, req *mgmt_pb.GetOrgByDomainGlobalRequest) (*mgmt_pb.GetOrgByDomainGlobalResponse, error) {
 	org, err := s.query.OrgByPrimaryDomain(ctx, req.Domain)
 	if err != nil {
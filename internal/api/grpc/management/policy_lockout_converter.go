@@ -8,11 +8,21 @@ import (
 func AddLockoutPolicyToDomain(p *mgmt.AddCustomLockoutPolicyRequest) *domain.LockoutPolicy {
 	return &domain.LockoutPolicy{
 		MaxPasswordAttempts: uint64(p.MaxPasswordAttempts),
+		MaxOTPAttempts:      uint64(p.MaxOtpAttempts),
+		MaxPasskeyAttempts:  uint64(p.MaxPasskeyAttempts),
+		MaxIPAttempts:       uint64(p.MaxIpAttempts),
+		LockoutDuration:     p.LockoutDuration.AsDuration(),
+		ProgressiveDelay:    p.ProgressiveDelay.AsDuration(),
 	}
 }
 
 func UpdateLockoutPolicyToDomain(p *mgmt.UpdateCustomLockoutPolicyRequest) *domain.LockoutPolicy {
 	return &domain.LockoutPolicy{
 		MaxPasswordAttempts: uint64(p.MaxPasswordAttempts),
+		MaxOTPAttempts:      uint64(p.MaxOtpAttempts),
+		MaxPasskeyAttempts:  uint64(p.MaxPasskeyAttempts),
+		MaxIPAttempts:       uint64(p.MaxIpAttempts),
+		LockoutDuration:     p.LockoutDuration.AsDuration(),
+		ProgressiveDelay:    p.ProgressiveDelay.AsDuration(),
 	}
 }
@@ -9,6 +9,8 @@ func AddPasswordAgePolicyToDomain(policy *mgmt_pb.AddCustomPasswordAgePolicyRequ
 	return &domain.PasswordAgePolicy{
 		MaxAgeDays:     uint64(policy.MaxAgeDays),
 		ExpireWarnDays: uint64(policy.ExpireWarnDays),
+		MinAgeDays:     uint64(policy.MinAgeDays),
+		HistoryCount:   uint64(policy.HistoryCount),
 	}
 }
 
@@ -16,5 +18,7 @@ func UpdatePasswordAgePolicyToDomain(policy *mgmt_pb.UpdateCustomPasswordAgePoli
 	return &domain.PasswordAgePolicy{
 		MaxAgeDays:     uint64(policy.MaxAgeDays),
 		ExpireWarnDays: uint64(policy.ExpireWarnDays),
+		MinAgeDays:     uint64(policy.MinAgeDays),
+		HistoryCount:   uint64(policy.HistoryCount),
 	}
 }
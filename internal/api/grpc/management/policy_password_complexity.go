@@ -17,6 +17,14 @@ func (s *Server) GetPasswordComplexityPolicy(ctx context.Context, req *mgmt_pb.G
 	return &mgmt_pb.GetPasswordComplexityPolicyResponse{Policy: policy_grpc.ModelPasswordComplexityPolicyToPb(policy), IsDefault: policy.IsDefault}, nil
 }
 
+func (s *Server) GetPasswordComplexityPolicyAt(ctx context.Context, req *mgmt_pb.GetPasswordComplexityPolicyAtRequest) (*mgmt_pb.GetPasswordComplexityPolicyAtResponse, error) {
+	policy, err := s.query.PasswordComplexityPolicyAt(ctx, authz.GetCtxData(ctx).OrgID, object.PointInTimeToQuery(req.GetAt()))
+	if err != nil {
+		return nil, err
+	}
+	return &mgmt_pb.GetPasswordComplexityPolicyAtResponse{Policy: policy_grpc.ModelPasswordComplexityPolicyToPb(policy)}, nil
+}
+
 func (s *Server) GetDefaultPasswordComplexityPolicy(ctx context.Context, req *mgmt_pb.GetDefaultPasswordComplexityPolicyRequest) (*mgmt_pb.GetDefaultPasswordComplexityPolicyResponse, error) {
 	policy, err := s.query.DefaultPasswordComplexityPolicy(ctx, true)
 	if err != nil {
@@ -1,26 +1,41 @@
 package management
 
 import (
+	policy_grpc "github.com/zitadel/zitadel/internal/api/grpc/policy"
 	"github.com/zitadel/zitadel/internal/domain"
 	mgmt_pb "github.com/zitadel/zitadel/pkg/grpc/management"
 )
 
 func AddPasswordComplexityPolicyToDomain(req *mgmt_pb.AddCustomPasswordComplexityPolicyRequest) *domain.PasswordComplexityPolicy {
 	return &domain.PasswordComplexityPolicy{
-		MinLength:    req.MinLength,
-		HasLowercase: req.HasLowercase,
-		HasUppercase: req.HasUppercase,
-		HasNumber:    req.HasNumber,
-		HasSymbol:    req.HasSymbol,
+		MinLength:            req.MinLength,
+		HasLowercase:         req.HasLowercase,
+		HasUppercase:         req.HasUppercase,
+		HasNumber:            req.HasNumber,
+		HasSymbol:            req.HasSymbol,
+		RejectBreached:       req.RejectBreached,
+		MaxLength:            req.MaxLength,
+		ForbidUsername:       req.ForbidUsername,
+		ForbidEmailLocalPart: req.ForbidEmailLocalPart,
+		ForbidOrgName:        req.ForbidOrgName,
+		Rules:                policy_grpc.PasswordRulesToDomain(req.Rules),
+		BannedWords:          req.BannedWords,
 	}
 }
 
 func UpdatePasswordComplexityPolicyToDomain(req *mgmt_pb.UpdateCustomPasswordComplexityPolicyRequest) *domain.PasswordComplexityPolicy {
 	return &domain.PasswordComplexityPolicy{
-		MinLength:    req.MinLength,
-		HasLowercase: req.HasLowercase,
-		HasUppercase: req.HasUppercase,
-		HasNumber:    req.HasNumber,
-		HasSymbol:    req.HasSymbol,
+		MinLength:            req.MinLength,
+		HasLowercase:         req.HasLowercase,
+		HasUppercase:         req.HasUppercase,
+		HasNumber:            req.HasNumber,
+		HasSymbol:            req.HasSymbol,
+		RejectBreached:       req.RejectBreached,
+		MaxLength:            req.MaxLength,
+		ForbidUsername:       req.ForbidUsername,
+		ForbidEmailLocalPart: req.ForbidEmailLocalPart,
+		ForbidOrgName:        req.ForbidOrgName,
+		Rules:                policy_grpc.PasswordRulesToDomain(req.Rules),
+		BannedWords:          req.BannedWords,
 	}
 }
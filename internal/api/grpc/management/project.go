@@ -24,6 +24,16 @@ func (s *Server) GetProjectByID(ctx context.Context, req *mgmt_pb.GetProjectByID
 	}, nil
 }
 
+func (s *Server) GetProjectByIDAt(ctx context.Context, req *mgmt_pb.GetProjectByIDAtRequest) (*mgmt_pb.GetProjectByIDAtResponse, error) {
+	project, err := s.query.ProjectAt(ctx, req.Id, authz.GetCtxData(ctx).OrgID, object_grpc.PointInTimeToQuery(req.GetAt()))
+	if err != nil {
+		return nil, err
+	}
+	return &mgmt_pb.GetProjectByIDAtResponse{
+		Project: project_grpc.ProjectViewToPb(project),
+	}, nil
+}
+
 func (s *Server) GetGrantedProjectByID(ctx context.Context, req *mgmt_pb.GetGrantedProjectByIDRequest) (*mgmt_pb.GetGrantedProjectByIDResponse, error) {
 	grant, err := s.query.ProjectGrantByID(ctx, true, req.GrantId, false)
 	if err != nil {
@@ -48,6 +48,16 @@ func (s *Server) GetUserByID(ctx context.Context, req *mgmt_pb.GetUserByIDReques
 	}, nil
 }
 
+func (s *Server) GetUserByIDAt(ctx context.Context, req *mgmt_pb.GetUserByIDAtRequest) (*mgmt_pb.GetUserByIDAtResponse, error) {
+	user, err := s.query.UserAt(ctx, req.GetId(), authz.GetCtxData(ctx).OrgID, obj_grpc.PointInTimeToQuery(req.GetAt()))
+	if err != nil {
+		return nil, err
+	}
+	return &mgmt_pb.GetUserByIDAtResponse{
+		User: user_grpc.UserToPb(user, s.assetAPIPrefix(ctx)),
+	}, nil
+}
+
 func (s *Server) GetUserByLoginNameGlobal(ctx context.Context, req *mgmt_pb.GetUserByLoginNameGlobalRequest) (*mgmt_pb.GetUserByLoginNameGlobalResponse, error) {
 	loginName, err := query.NewUserPreferredLoginNameSearchQuery(req.LoginName, query.TextEquals)
 	if err != nil {
@@ -260,7 +270,10 @@ func AddHumanUserRequestToAddHuman(req *mgmt_pb.AddHumanUserRequest) *command.Ad
 }
 
 func (s *Server) ImportHumanUser(ctx context.Context, req *mgmt_pb.ImportHumanUserRequest) (*mgmt_pb.ImportHumanUserResponse, error) {
-	human, passwordless, links := ImportHumanUserRequestToDomain(req)
+	human, passwordless, links, err := ImportHumanUserRequestToDomain(req)
+	if err != nil {
+		return nil, err
+	}
 	initCodeGenerator, err := s.query.InitEncryptionGenerator(ctx, domain.SecretGeneratorTypeInitCode, s.userCodeAlg)
 	if err != nil {
 		return nil, err
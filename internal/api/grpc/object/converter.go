@@ -149,3 +149,14 @@ func ListQueryToModel(query *object_pb.ListQuery) (offset, limit uint64, asc boo
 	}
 	return query.Offset, uint64(query.Limit), query.Asc
 }
+
+func PointInTimeToQuery(at *object_pb.PointInTime) query.PointInTime {
+	switch point := at.GetPoint().(type) {
+	case *object_pb.PointInTime_Timestamp:
+		return query.PointInTime{Timestamp: point.Timestamp.AsTime()}
+	case *object_pb.PointInTime_Sequence:
+		return query.PointInTime{Sequence: point.Sequence}
+	default:
+		return query.PointInTime{}
+	}
+}
@@ -2,18 +2,26 @@ package policy
 
 import (
 	"github.com/zitadel/zitadel/internal/api/grpc/object"
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/query"
 	policy_pb "github.com/zitadel/zitadel/pkg/grpc/policy"
 )
 
 func ModelPasswordComplexityPolicyToPb(policy *query.PasswordComplexityPolicy) *policy_pb.PasswordComplexityPolicy {
 	return &policy_pb.PasswordComplexityPolicy{
-		IsDefault:    policy.IsDefault,
-		MinLength:    policy.MinLength,
-		HasUppercase: policy.HasUppercase,
-		HasLowercase: policy.HasLowercase,
-		HasNumber:    policy.HasNumber,
-		HasSymbol:    policy.HasSymbol,
+		IsDefault:            policy.IsDefault,
+		MinLength:            policy.MinLength,
+		HasUppercase:         policy.HasUppercase,
+		HasLowercase:         policy.HasLowercase,
+		HasNumber:            policy.HasNumber,
+		HasSymbol:            policy.HasSymbol,
+		RejectBreached:       policy.RejectBreached,
+		MaxLength:            policy.MaxLength,
+		ForbidUsername:       policy.ForbidUsername,
+		ForbidEmailLocalPart: policy.ForbidEmailLocalPart,
+		ForbidOrgName:        policy.ForbidOrgName,
+		Rules:                ModelPasswordRulesToPb(policy.Rules),
+		BannedWords:          policy.BannedWords,
 		Details: object.ToViewDetailsPb(
 			policy.Sequence,
 			policy.CreationDate,
@@ -22,3 +30,52 @@ func ModelPasswordComplexityPolicyToPb(policy *query.PasswordComplexityPolicy) *
 		),
 	}
 }
+
+func PasswordRulesToDomain(rules []*policy_pb.PasswordRule) []domain.PasswordRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	r := make([]domain.PasswordRule, len(rules))
+	for i, rule := range rules {
+		r[i] = domain.PasswordRule{
+			Type:        PasswordRuleTypeToDomain(rule.Type),
+			Pattern:     rule.Pattern,
+			Description: rule.Description,
+		}
+	}
+	return r
+}
+
+func PasswordRuleTypeToDomain(ruleType policy_pb.PasswordRuleType) domain.PasswordRuleType {
+	switch ruleType {
+	case policy_pb.PasswordRuleType_PASSWORD_RULE_TYPE_ALLOW:
+		return domain.PasswordRuleTypeAllow
+	case policy_pb.PasswordRuleType_PASSWORD_RULE_TYPE_DENY:
+		return domain.PasswordRuleTypeDeny
+	default:
+		return domain.PasswordRuleTypeUnspecified
+	}
+}
+
+func ModelPasswordRulesToPb(rules []domain.PasswordRule) []*policy_pb.PasswordRule {
+	r := make([]*policy_pb.PasswordRule, len(rules))
+	for i, rule := range rules {
+		r[i] = &policy_pb.PasswordRule{
+			Type:        ModelPasswordRuleTypeToPb(rule.Type),
+			Pattern:     rule.Pattern,
+			Description: rule.Description,
+		}
+	}
+	return r
+}
+
+func ModelPasswordRuleTypeToPb(ruleType domain.PasswordRuleType) policy_pb.PasswordRuleType {
+	switch ruleType {
+	case domain.PasswordRuleTypeAllow:
+		return policy_pb.PasswordRuleType_PASSWORD_RULE_TYPE_ALLOW
+	case domain.PasswordRuleTypeDeny:
+		return policy_pb.PasswordRuleType_PASSWORD_RULE_TYPE_DENY
+	default:
+		return policy_pb.PasswordRuleType_PASSWORD_RULE_TYPE_UNSPECIFIED
+	}
+}
@@ -2,7 +2,10 @@ package middleware
 
 import (
 	"context"
+	"sync"
+	"time"
 
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -49,6 +52,88 @@ func authorize(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
 	return handler(ctxSetter(ctx), req)
 }
 
+// streamReauthorizationInterval defines how often the authorization of an open stream is checked again,
+// so revoked tokens and removed permissions end the stream
+const streamReauthorizationInterval = time.Minute
+
+// AuthorizationStreamInterceptor checks the authorization of the caller before the stream is opened
+// and periodically while it is open.
+// As the request is read by the handler itself, permissions cannot be checked against request params
+func AuthorizationStreamInterceptor(verifier *authz.TokenVerifier, authConfig authz.Config) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return authorizeStream(srv, stream, info, handler, streamReauthorizationInterval, func(ctx context.Context) (func(context.Context) context.Context, error) {
+			authOpt, needsToken := verifier.CheckAuthMethod(info.FullMethod)
+			if !needsToken {
+				return nil, nil
+			}
+			authToken := grpc_util.GetAuthorizationHeader(ctx)
+			if authToken == "" {
+				return nil, status.Error(codes.Unauthenticated, "auth header missing")
+			}
+			orgID := grpc_util.GetHeader(ctx, http.ZitadelOrgID)
+			return authz.CheckUserAuthorization(ctx, nil, authToken, orgID, "", verifier, authConfig, authOpt, info.FullMethod)
+		})
+	}
+}
+
+// authorizeStream calls check before the handler and every interval while the handler runs.
+// If a later check fails, the context of the stream is cancelled and the error of the check is returned.
+// A nil context setter of the first check means the method doesn't need authorization.
+func authorizeStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler, interval time.Duration, check func(context.Context) (func(context.Context) context.Context, error)) (err error) {
+	authCtx, span := tracing.NewServerInterceptorSpan(stream.Context())
+	defer func() { span.EndWithError(err) }()
+
+	ctxSetter, err := check(authCtx)
+	if err != nil {
+		return err
+	}
+	span.End()
+	if ctxSetter == nil {
+		return handler(srv, stream)
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	var (
+		authErr error
+		mu      sync.Mutex
+		done    = make(chan struct{})
+	)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := check(stream.Context()); err != nil {
+					mu.Lock()
+					authErr = err
+					mu.Unlock()
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	wrapped := grpc_middleware.WrapServerStream(stream)
+	wrapped.WrappedContext = ctxSetter(ctx)
+	err = handler(srv, wrapped)
+	close(done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if authErr != nil {
+		return authErr
+	}
+	return err
+}
+
 type OrganisationFromRequest interface {
 	OrganisationFromRequest() *object.Organisation
 }
@@ -3,10 +3,14 @@ package middleware
 import (
 	"context"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
 )
@@ -150,3 +154,90 @@ func Test_authorize(t *testing.T) {
 		})
 	}
 }
+
+func Test_authorizeStream(t *testing.T) {
+	type args struct {
+		check   func(calls int) error
+		handler func(stream grpc.ServerStream) error
+	}
+	type res struct {
+		wantErr     bool
+		wantHandled bool
+	}
+	tests := []struct {
+		name string
+		args args
+		res  res
+	}{
+		{
+			"unauthorized, handler not called",
+			args{
+				check: func(int) error {
+					return status.Error(codes.Unauthenticated, "auth header missing")
+				},
+				handler: func(grpc.ServerStream) error { return nil },
+			},
+			res{
+				wantErr: true,
+			},
+		},
+		{
+			"authorized",
+			args{
+				check:   func(int) error { return nil },
+				handler: func(grpc.ServerStream) error { return nil },
+			},
+			res{
+				wantHandled: true,
+			},
+		},
+		{
+			"authorization revoked while open, stream cancelled",
+			args{
+				check: func(calls int) error {
+					if calls > 1 {
+						return status.Error(codes.PermissionDenied, "revoked")
+					}
+					return nil
+				},
+				handler: func(stream grpc.ServerStream) error {
+					<-stream.Context().Done()
+					return stream.Context().Err()
+				},
+			},
+			res{
+				wantErr:     true,
+				wantHandled: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				calls   int
+				handled bool
+				mu      sync.Mutex
+			)
+			check := func(ctx context.Context) (func(context.Context) context.Context, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls++
+				if err := tt.args.check(calls); err != nil {
+					return nil, err
+				}
+				return func(ctx context.Context) context.Context { return ctx }, nil
+			}
+			handler := func(_ interface{}, stream grpc.ServerStream) error {
+				handled = true
+				return tt.args.handler(stream)
+			}
+			err := authorizeStream(nil, &mockServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "need.authentication"}, handler, time.Millisecond, check)
+			if (err != nil) != tt.res.wantErr {
+				t.Errorf("authorizeStream() error = %v, wantErr %v", err, tt.res.wantErr)
+			}
+			if handled != tt.res.wantHandled {
+				t.Errorf("authorizeStream() handled = %v, want %v", handled, tt.res.wantHandled)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
 
 	"github.com/zitadel/zitadel/internal/api/call"
@@ -14,3 +15,11 @@ func CallDurationHandler() grpc.UnaryServerInterceptor {
 		return handler(ctx, req)
 	}
 }
+
+func CallDurationStreamHandler() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = call.WithTimestamp(stream.Context())
+		return handler(srv, wrapped)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/zitadel/logging"
 	"golang.org/x/text/language"
 	"google.golang.org/grpc"
@@ -56,11 +57,53 @@ func setInstance(ctx context.Context, req interface{}, info *grpc.UnaryServerInf
 		}
 	}
 
-	host, err := hostFromContext(interceptorCtx, headerName)
+	instance, err := instanceByHost(interceptorCtx, verifier, headerName, translator)
+	if err != nil {
+		return nil, err
+	}
+	span.End()
+	return handler(authz.WithInstance(ctx, instance), req)
+}
+
+// InstanceStreamInterceptor sets the instance of the requested host on the context of the stream.
+// Streams of the ignored services (e.g. the system API) are not bound to an instance.
+func InstanceStreamInterceptor(verifier authz.InstanceVerifier, headerName string, ignoredServices ...string) grpc.StreamServerInterceptor {
+	translator, err := newZitadelTranslator(language.English)
+	logging.OnError(err).Panic("unable to get translator")
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return setStreamInstance(srv, stream, info, handler, verifier, headerName, translator, ignoredServices...)
+	}
+}
+
+func setStreamInstance(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler, verifier authz.InstanceVerifier, headerName string, translator *i18n.Translator, ignoredServices ...string) (err error) {
+	for _, service := range ignoredServices {
+		if !strings.HasPrefix(service, "/") {
+			service = "/" + service
+		}
+		if strings.HasPrefix(info.FullMethod, service) {
+			return handler(srv, stream)
+		}
+	}
+
+	interceptorCtx, span := tracing.NewServerInterceptorSpan(stream.Context())
+	defer func() { span.EndWithError(err) }()
+
+	instance, err := instanceByHost(interceptorCtx, verifier, headerName, translator)
+	if err != nil {
+		return err
+	}
+	span.End()
+	wrapped := grpc_middleware.WrapServerStream(stream)
+	wrapped.WrappedContext = authz.WithInstance(stream.Context(), instance)
+	return handler(srv, wrapped)
+}
+
+func instanceByHost(ctx context.Context, verifier authz.InstanceVerifier, headerName string, translator *i18n.Translator) (authz.Instance, error) {
+	host, err := hostFromContext(ctx, headerName)
 	if err != nil {
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
-	instance, err := verifier.InstanceByHost(interceptorCtx, host)
+	instance, err := verifier.InstanceByHost(ctx, host)
 	if err != nil {
 		notFoundErr := new(errors.NotFoundError)
 		if errs.As(err, &notFoundErr) {
@@ -68,8 +111,7 @@ func setInstance(ctx context.Context, req interface{}, info *grpc.UnaryServerInf
 		}
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
-	span.End()
-	return handler(authz.WithInstance(ctx, instance), req)
+	return instance, nil
 }
 
 func hostFromContext(ctx context.Context, headerName string) (string, error) {
@@ -147,6 +147,68 @@ func Test_setInstance(t *testing.T) {
 	}
 }
 
+func Test_setStreamInstance(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		verifier authz.InstanceVerifier
+	}
+	type res struct {
+		instanceID string
+		err        bool
+	}
+	tests := []struct {
+		name string
+		args args
+		res  res
+	}{
+		{
+			"hostname not found, error",
+			args{
+				ctx: context.Background(),
+			},
+			res{
+				err: true,
+			},
+		},
+		{
+			"invalid host, error",
+			args{
+				ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs("header", "host2")),
+				verifier: &mockInstanceVerifier{"host"},
+			},
+			res{
+				err: true,
+			},
+		},
+		{
+			"valid host",
+			args{
+				ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs("header", "host")),
+				verifier: &mockInstanceVerifier{"host"},
+			},
+			res{
+				instanceID: "instanceID",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var instanceID string
+			err := setStreamInstance(nil, &mockServerStream{ctx: tt.args.ctx}, &grpc.StreamServerInfo{FullMethod: "/zitadel.admin.v1.AdminService/SubscribeEvents"}, func(_ interface{}, stream grpc.ServerStream) error {
+				instanceID = authz.GetInstance(stream.Context()).InstanceID()
+				return nil
+			}, tt.args.verifier, "header", nil)
+			if (err != nil) != tt.res.err {
+				t.Errorf("setStreamInstance() error = %v, wantErr %v", err, tt.res.err)
+				return
+			}
+			if instanceID != tt.res.instanceID {
+				t.Errorf("setStreamInstance() got = %v, want %v", instanceID, tt.res.instanceID)
+			}
+		})
+	}
+}
+
 type mockRequest struct{}
 
 type mockInstanceVerifier struct {
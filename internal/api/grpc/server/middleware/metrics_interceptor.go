@@ -43,20 +43,52 @@ func RegisterMetrics(ctx context.Context, req interface{}, info *grpc.UnaryServe
 
 	resp, err := handler(ctx, req)
 	if containsMetricsMethod(metrics.MetricTypeRequestCount, metricTypes) {
-		RegisterGrpcRequestCounter(ctx, info)
+		RegisterGrpcRequestCounter(ctx, info.FullMethod)
 	}
 	if containsMetricsMethod(metrics.MetricTypeTotalCount, metricTypes) {
 		RegisterGrpcTotalRequestCounter(ctx)
 	}
 	if containsMetricsMethod(metrics.MetricTypeStatusCode, metricTypes) {
-		RegisterGrpcRequestCodeCounter(ctx, info, err)
+		RegisterGrpcRequestCodeCounter(ctx, info.FullMethod, err)
 	}
 	return resp, err
 }
 
-func RegisterGrpcRequestCounter(ctx context.Context, info *grpc.UnaryServerInfo) {
+// MetricsStreamHandler registers the metrics of a stream as soon as it's closed
+func MetricsStreamHandler(metricTypes []metrics.MetricType, ignoredMethodSuffixes ...string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return registerStreamMetrics(srv, stream, info, handler, metricTypes, ignoredMethodSuffixes...)
+	}
+}
+
+func registerStreamMetrics(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler, metricTypes []metrics.MetricType, ignoredMethodSuffixes ...string) error {
+	if len(metricTypes) == 0 {
+		return handler(srv, stream)
+	}
+
+	for _, ignore := range ignoredMethodSuffixes {
+		if strings.HasSuffix(info.FullMethod, ignore) {
+			return handler(srv, stream)
+		}
+	}
+
+	err := handler(srv, stream)
+	ctx := stream.Context()
+	if containsMetricsMethod(metrics.MetricTypeRequestCount, metricTypes) {
+		RegisterGrpcRequestCounter(ctx, info.FullMethod)
+	}
+	if containsMetricsMethod(metrics.MetricTypeTotalCount, metricTypes) {
+		RegisterGrpcTotalRequestCounter(ctx)
+	}
+	if containsMetricsMethod(metrics.MetricTypeStatusCode, metricTypes) {
+		RegisterGrpcRequestCodeCounter(ctx, info.FullMethod, err)
+	}
+	return err
+}
+
+func RegisterGrpcRequestCounter(ctx context.Context, method string) {
 	var labels = map[string]attribute.Value{
-		GrpcMethod: attribute.StringValue(info.FullMethod),
+		GrpcMethod: attribute.StringValue(method),
 	}
 	metrics.RegisterCounter(GrpcRequestCounter, GrpcRequestCounterDescription)
 	metrics.AddCount(ctx, GrpcRequestCounter, 1, labels)
@@ -67,10 +99,10 @@ func RegisterGrpcTotalRequestCounter(ctx context.Context) {
 	metrics.AddCount(ctx, TotalGrpcRequestCounter, 1, nil)
 }
 
-func RegisterGrpcRequestCodeCounter(ctx context.Context, info *grpc.UnaryServerInfo, err error) {
+func RegisterGrpcRequestCodeCounter(ctx context.Context, method string, err error) {
 	statusCode := status.Code(err)
 	var labels = map[string]attribute.Value{
-		GrpcMethod: attribute.StringValue(info.FullMethod),
+		GrpcMethod: attribute.StringValue(method),
 		ReturnCode: attribute.IntValue(runtime.HTTPStatusFromCode(statusCode)),
 	}
 	metrics.RegisterCounter(GrpcStatusCodeCounter, GrpcStatusCodeCounterDescription)
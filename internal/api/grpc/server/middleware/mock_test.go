@@ -24,3 +24,12 @@ func mockInfo(path string) *grpc.UnaryServerInfo {
 		FullMethod: path,
 	}
 }
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (m *mockServerStream) Context() context.Context {
+	return m.ctx
+}
@@ -13,15 +13,7 @@ import (
 )
 
 func QuotaExhaustedInterceptor(svc *logstore.Service, ignoreService ...string) grpc.UnaryServerInterceptor {
-
-	prunedIgnoredServices := make([]string, len(ignoreService))
-	for idx, service := range ignoreService {
-		if !strings.HasPrefix(service, "/") {
-			service = "/" + service
-		}
-		prunedIgnoredServices[idx] = service
-	}
-
+	prunedIgnoredServices := pruneIgnoredServices(ignoreService)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (_ interface{}, err error) {
 		if !svc.Enabled() {
 			return handler(ctx, req)
@@ -44,3 +36,40 @@ func QuotaExhaustedInterceptor(svc *logstore.Service, ignoreService ...string) g
 		return handler(ctx, req)
 	}
 }
+
+// QuotaExhaustedStreamInterceptor rejects opening a stream if the access quota of the instance is exhausted
+func QuotaExhaustedStreamInterceptor(svc *logstore.Service, ignoreService ...string) grpc.StreamServerInterceptor {
+	prunedIgnoredServices := pruneIgnoredServices(ignoreService)
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		if !svc.Enabled() {
+			return handler(srv, stream)
+		}
+		interceptorCtx, span := tracing.NewServerInterceptorSpan(stream.Context())
+		defer func() { span.EndWithError(err) }()
+
+		for _, service := range prunedIgnoredServices {
+			if strings.HasPrefix(info.FullMethod, service) {
+				return handler(srv, stream)
+			}
+		}
+
+		instance := authz.GetInstance(stream.Context())
+		remaining := svc.Limit(interceptorCtx, instance.InstanceID())
+		if remaining != nil && *remaining == 0 {
+			return errors.ThrowResourceExhausted(nil, "QUOTA-Hm3sq", "Quota.Access.Exhausted")
+		}
+		span.End()
+		return handler(srv, stream)
+	}
+}
+
+func pruneIgnoredServices(ignoreService []string) []string {
+	prunedIgnoredServices := make([]string, len(ignoreService))
+	for idx, service := range ignoreService {
+		if !strings.HasPrefix(service, "/") {
+			service = "/" + service
+		}
+		prunedIgnoredServices[idx] = service
+	}
+	return prunedIgnoredServices
+}
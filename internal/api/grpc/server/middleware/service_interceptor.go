@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
 
 	"github.com/zitadel/zitadel/internal/api/service"
@@ -19,3 +20,15 @@ func ServiceHandler() grpc.UnaryServerInterceptor {
 		return handler(ctx, req)
 	}
 }
+
+func ServiceStreamHandler() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		namer, ok := srv.(interface{ AppName() string })
+		if !ok {
+			return handler(srv, stream)
+		}
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = service.WithService(stream.Context(), namer.AppName())
+		return handler(srv, wrapped)
+	}
+}
@@ -31,3 +31,7 @@ func TracingServer(ignoredMethods ...GRPCMethod) grpc.UnaryServerInterceptor {
 		return grpc_trace.UnaryServerInterceptor()(ctx, req, info, handler)
 	}
 }
+
+func DefaultTracingStreamServer() grpc.StreamServerInterceptor {
+	return grpc_trace.StreamServerInterceptor()
+}
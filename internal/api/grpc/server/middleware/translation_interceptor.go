@@ -36,3 +36,23 @@ func TranslationHandler() func(ctx context.Context, req interface{}, info *grpc.
 		return resp, err
 	}
 }
+
+// TranslationStreamHandler translates the error returned by the stream.
+// The messages sent on the stream are not translated.
+func TranslationStreamHandler() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, stream)
+		if err == nil {
+			return nil
+		}
+		ctx, span := tracing.NewSpan(stream.Context())
+		defer func() { span.EndWithError(err) }()
+
+		translator, translatorError := newZitadelTranslator(authz.GetInstance(ctx).DefaultLanguage())
+		if translatorError != nil {
+			logging.New().WithError(translatorError).Error("could not load translator")
+			return err
+		}
+		return translateError(ctx, err, translator)
+	}
+}
@@ -34,3 +34,29 @@ func validate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
 	}
 	return handler(ctx, req)
 }
+
+func ValidationStreamHandler() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingServerStream{ServerStream: stream})
+	}
+}
+
+// validatingServerStream validates the received messages
+// as the request of a stream is not known before the handler reads it
+type validatingServerStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	validate, ok := m.(validator)
+	if !ok {
+		return nil
+	}
+	if err := validate.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
@@ -59,6 +59,20 @@ func CreateServer(
 				middleware.QuotaExhaustedInterceptor(accessSvc, system_pb.SystemService_ServiceDesc.ServiceName),
 			),
 		),
+		grpc.StreamInterceptor(
+			grpc_middleware.ChainStreamServer(
+				middleware.CallDurationStreamHandler(),
+				middleware.DefaultTracingStreamServer(),
+				middleware.MetricsStreamHandler(metricTypes, grpc_api.Probes...),
+				middleware.ErrorStreamHandler(),
+				middleware.InstanceStreamInterceptor(queries, hostHeaderName, system_pb.SystemService_ServiceDesc.ServiceName, healthpb.Health_ServiceDesc.ServiceName),
+				middleware.AuthorizationStreamInterceptor(verifier, authConfig),
+				middleware.TranslationStreamHandler(),
+				middleware.ValidationStreamHandler(),
+				middleware.ServiceStreamHandler(),
+				middleware.QuotaExhaustedStreamInterceptor(accessSvc, system_pb.SystemService_ServiceDesc.ServiceName),
+			),
+		),
 	}
 	if tlsConfig != nil {
 		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
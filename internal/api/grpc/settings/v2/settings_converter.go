@@ -86,12 +86,44 @@ func multiFactorTypeToPb(typ domain.MultiFactorType) settings.MultiFactorType {
 
 func passwordSettingsToPb(current *query.PasswordComplexityPolicy) *settings.PasswordComplexitySettings {
 	return &settings.PasswordComplexitySettings{
-		MinLength:         current.MinLength,
-		RequiresUppercase: current.HasUppercase,
-		RequiresLowercase: current.HasLowercase,
-		RequiresNumber:    current.HasNumber,
-		RequiresSymbol:    current.HasSymbol,
-		ResourceOwnerType: isDefaultToResourceOwnerTypePb(current.IsDefault),
+		MinLength:            current.MinLength,
+		RequiresUppercase:    current.HasUppercase,
+		RequiresLowercase:    current.HasLowercase,
+		RequiresNumber:       current.HasNumber,
+		RequiresSymbol:       current.HasSymbol,
+		RejectsBreached:      current.RejectBreached,
+		MaxLength:            current.MaxLength,
+		ForbidUsername:       current.ForbidUsername,
+		ForbidEmailLocalPart: current.ForbidEmailLocalPart,
+		ForbidOrgName:        current.ForbidOrgName,
+		Rules:                passwordRulesToPb(current.Rules),
+		BannedWords:          current.BannedWords,
+		ResourceOwnerType:    isDefaultToResourceOwnerTypePb(current.IsDefault),
+	}
+}
+
+func passwordRulesToPb(rules []domain.PasswordRule) []*settings.PasswordRule {
+	r := make([]*settings.PasswordRule, len(rules))
+	for i, rule := range rules {
+		r[i] = &settings.PasswordRule{
+			Type:        passwordRuleTypeToPb(rule.Type),
+			Pattern:     rule.Pattern,
+			Description: rule.Description,
+		}
+	}
+	return r
+}
+
+func passwordRuleTypeToPb(ruleType domain.PasswordRuleType) settings.PasswordRuleType {
+	switch ruleType {
+	case domain.PasswordRuleTypeAllow:
+		return settings.PasswordRuleType_PASSWORD_RULE_TYPE_ALLOW
+	case domain.PasswordRuleTypeDeny:
+		return settings.PasswordRuleType_PASSWORD_RULE_TYPE_DENY
+	case domain.PasswordRuleTypeUnspecified:
+		return settings.PasswordRuleType_PASSWORD_RULE_TYPE_UNSPECIFIED
+	default:
+		return settings.PasswordRuleType_PASSWORD_RULE_TYPE_UNSPECIFIED
 	}
 }
 
@@ -140,6 +172,11 @@ func lockoutSettingsToPb(current *query.LockoutPolicy) *settings.LockoutSettings
 	return &settings.LockoutSettings{
 		MaxPasswordAttempts: current.MaxPasswordAttempts,
 		ResourceOwnerType:   isDefaultToResourceOwnerTypePb(current.IsDefault),
+		MaxOtpAttempts:      current.MaxOTPAttempts,
+		MaxPasskeyAttempts:  current.MaxPasskeyAttempts,
+		MaxIpAttempts:       current.MaxIPAttempts,
+		LockoutDuration:     durationpb.New(current.LockoutDuration),
+		ProgressiveDelay:    durationpb.New(current.ProgressiveDelay),
 	}
 }
 
@@ -183,6 +220,8 @@ func idpTypeToPb(idpType domain.IDPType) settings.IdentityProviderType {
 		return settings.IdentityProviderType_IDENTITY_PROVIDER_TYPE_GITLAB_SELF_HOSTED
 	case domain.IDPTypeGoogle:
 		return settings.IdentityProviderType_IDENTITY_PROVIDER_TYPE_GOOGLE
+	case domain.IDPTypeSAML:
+		return settings.IdentityProviderType_IDENTITY_PROVIDER_TYPE_SAML
 	default:
 		return settings.IdentityProviderType_IDENTITY_PROVIDER_TYPE_UNSPECIFIED
 	}
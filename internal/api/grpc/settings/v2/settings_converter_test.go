@@ -12,6 +12,7 @@ import (
 	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/zitadel/zitadel/internal/api/grpc"
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/query"
 	settings "github.com/zitadel/zitadel/pkg/grpc/settings/v2alpha"
@@ -199,20 +200,38 @@ func Test_multiFactorTypeToPb(t *testing.T) {
 
 func Test_passwordSettingsToPb(t *testing.T) {
 	arg := &query.PasswordComplexityPolicy{
-		MinLength:    12,
-		HasUppercase: true,
-		HasLowercase: true,
-		HasNumber:    true,
-		HasSymbol:    true,
-		IsDefault:    true,
+		MinLength:            12,
+		HasUppercase:         true,
+		HasLowercase:         true,
+		HasNumber:            true,
+		HasSymbol:            true,
+		RejectBreached:       true,
+		MaxLength:            64,
+		ForbidUsername:       true,
+		ForbidEmailLocalPart: true,
+		ForbidOrgName:        true,
+		Rules: database.JSONArray[domain.PasswordRule]{
+			{Type: domain.PasswordRuleTypeDeny, Pattern: `(?i)qwerty`, Description: "no keyboard sequences"},
+		},
+		BannedWords: database.StringArray{"zitadel"},
+		IsDefault:   true,
 	}
 	want := &settings.PasswordComplexitySettings{
-		MinLength:         12,
-		RequiresUppercase: true,
-		RequiresLowercase: true,
-		RequiresNumber:    true,
-		RequiresSymbol:    true,
-		ResourceOwnerType: settings.ResourceOwnerType_RESOURCE_OWNER_TYPE_INSTANCE,
+		MinLength:            12,
+		RequiresUppercase:    true,
+		RequiresLowercase:    true,
+		RequiresNumber:       true,
+		RequiresSymbol:       true,
+		ResourceOwnerType:    settings.ResourceOwnerType_RESOURCE_OWNER_TYPE_INSTANCE,
+		RejectsBreached:      true,
+		MaxLength:            64,
+		ForbidUsername:       true,
+		ForbidEmailLocalPart: true,
+		ForbidOrgName:        true,
+		Rules: []*settings.PasswordRule{
+			{Type: settings.PasswordRuleType_PASSWORD_RULE_TYPE_DENY, Pattern: `(?i)qwerty`, Description: "no keyboard sequences"},
+		},
+		BannedWords: []string{"zitadel"},
 	}
 
 	got := passwordSettingsToPb(arg)
@@ -321,11 +340,21 @@ func Test_legalSettingsToPb(t *testing.T) {
 func Test_lockoutSettingsToPb(t *testing.T) {
 	arg := &query.LockoutPolicy{
 		MaxPasswordAttempts: 22,
+		MaxOTPAttempts:      5,
+		MaxPasskeyAttempts:  10,
+		MaxIPAttempts:       50,
+		LockoutDuration:     15 * time.Minute,
+		ProgressiveDelay:    time.Second,
 		IsDefault:           true,
 	}
 	want := &settings.LockoutSettings{
 		MaxPasswordAttempts: 22,
 		ResourceOwnerType:   settings.ResourceOwnerType_RESOURCE_OWNER_TYPE_INSTANCE,
+		MaxOtpAttempts:      5,
+		MaxPasskeyAttempts:  10,
+		MaxIpAttempts:       50,
+		LockoutDuration:     durationpb.New(15 * time.Minute),
+		ProgressiveDelay:    durationpb.New(time.Second),
 	}
 	got := lockoutSettingsToPb(arg)
 	grpc.AllFieldsSet(t, got.ProtoReflect(), ignoreTypes...)
@@ -421,6 +450,10 @@ func Test_idpTypeToPb(t *testing.T) {
 			args: args{domain.IDPTypeGoogle},
 			want: settings.IdentityProviderType_IDENTITY_PROVIDER_TYPE_GOOGLE,
 		},
+		{
+			args: args{domain.IDPTypeSAML},
+			want: settings.IdentityProviderType_IDENTITY_PROVIDER_TYPE_SAML,
+		},
 		{
 			args: args{99},
 			want: settings.IdentityProviderType_IDENTITY_PROVIDER_TYPE_UNSPECIFIED,
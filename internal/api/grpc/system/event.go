@@ -0,0 +1,37 @@
+package system
+
+import (
+	"github.com/zitadel/zitadel/internal/api/authz"
+	event_grpc "github.com/zitadel/zitadel/internal/api/grpc/event"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/query"
+	system_pb "github.com/zitadel/zitadel/pkg/grpc/system"
+)
+
+func (s *Server) SubscribeEvents(in *system_pb.SubscribeEventsRequest, stream system_pb.SystemService_SubscribeEventsServer) error {
+	// the events are filtered by the instance of the context, without an instance the events of all instances are sent
+	ctx := authz.WithInstanceID(stream.Context(), in.InstanceId)
+	return s.query.StreamEvents(ctx, subscribeEventsRequestToFilter(in), in.FromSequence, 0, func(event *query.Event) error {
+		pbEvent, err := event_grpc.EventToPb(event)
+		if err != nil {
+			return err
+		}
+		return stream.Send(&system_pb.SubscribeEventsResponse{Event: pbEvent})
+	})
+}
+
+func subscribeEventsRequestToFilter(req *system_pb.SubscribeEventsRequest) *eventstore.SearchQueryBuilder {
+	eventTypes := make([]eventstore.EventType, len(req.EventTypes))
+	for i, eventType := range req.EventTypes {
+		eventTypes[i] = eventstore.EventType(eventType)
+	}
+	aggregateTypes := make([]eventstore.AggregateType, len(req.AggregateTypes))
+	for i, aggregateType := range req.AggregateTypes {
+		aggregateTypes[i] = eventstore.AggregateType(aggregateType)
+	}
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(aggregateTypes...).
+		EventTypes(eventTypes...).
+		Builder()
+}
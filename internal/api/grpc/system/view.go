@@ -35,3 +35,27 @@ func (s *Server) ClearView(ctx context.Context, req *system_pb.ClearViewRequest)
 	}
 	return &system_pb.ClearViewResponse{}, nil
 }
+
+func (s *Server) RebuildProjection(ctx context.Context, req *system_pb.RebuildProjectionRequest) (*system_pb.RebuildProjectionResponse, error) {
+	err := s.query.RebuildProjection(ctx, req.ProjectionName)
+	if err != nil {
+		return nil, err
+	}
+	return &system_pb.RebuildProjectionResponse{}, nil
+}
+
+func (s *Server) GetProjectionRebuild(ctx context.Context, req *system_pb.GetProjectionRebuildRequest) (*system_pb.GetProjectionRebuildResponse, error) {
+	rebuild, err := s.query.ProjectionRebuild(ctx, req.ProjectionName)
+	if err != nil {
+		return nil, err
+	}
+	return &system_pb.GetProjectionRebuildResponse{Rebuild: ProjectionRebuildToPb(rebuild)}, nil
+}
+
+func (s *Server) ListProjectionLags(ctx context.Context, _ *system_pb.ListProjectionLagsRequest) (*system_pb.ListProjectionLagsResponse, error) {
+	lags, err := s.query.SearchProjectionLags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &system_pb.ListProjectionLagsResponse{Result: ProjectionLagsToPb(lags)}, nil
+}
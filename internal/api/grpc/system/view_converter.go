@@ -1,8 +1,10 @@
 package system
 
 import (
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/query"
 	"github.com/zitadel/zitadel/internal/view/model"
 	system_pb "github.com/zitadel/zitadel/pkg/grpc/system"
@@ -42,3 +44,51 @@ func CurrentSequenceToPb(database string, currentSequence *query.CurrentSequence
 		LastSuccessfulSpoolerRun: timestamppb.New(currentSequence.Timestamp),
 	}
 }
+
+func ProjectionLagsToPb(lags *query.ProjectionLags) []*system_pb.ProjectionLag {
+	l := make([]*system_pb.ProjectionLag, len(lags.ProjectionLags))
+	for i, lag := range lags.ProjectionLags {
+		l[i] = ProjectionLagToPb(lag)
+	}
+	return l
+}
+
+func ProjectionLagToPb(lag *query.ProjectionLag) *system_pb.ProjectionLag {
+	return &system_pb.ProjectionLag{
+		ProjectionName:           lag.ProjectionName,
+		InstanceId:               lag.InstanceID,
+		ProcessedSequence:        lag.CurrentSequence,
+		LatestSequence:           lag.LatestSequence,
+		SequenceLag:              lag.SequenceLag(),
+		TimeLag:                  durationpb.New(lag.TimeLag()),
+		LastSuccessfulSpoolerRun: timestamppb.New(lag.LastRun),
+		FailedEvents:             lag.FailedEvents,
+	}
+}
+
+func ProjectionRebuildToPb(rebuild *query.ProjectionRebuild) *system_pb.ProjectionRebuild {
+	return &system_pb.ProjectionRebuild{
+		ProjectionName:    rebuild.ProjectionName,
+		State:             ProjectionRebuildStateToPb(rebuild.State),
+		Round:             rebuild.Round,
+		ReplayedInstances: rebuild.ReplayedInstances,
+		Instances:         rebuild.Instances,
+		Swapped:           rebuild.Swapped,
+		StartedAt:         timestamppb.New(rebuild.StartedAt),
+		ChangedAt:         timestamppb.New(rebuild.ChangedAt),
+		Error:             rebuild.Error,
+	}
+}
+
+func ProjectionRebuildStateToPb(state domain.ProjectionRebuildState) system_pb.ProjectionRebuildState {
+	switch state {
+	case domain.ProjectionRebuildStateRunning:
+		return system_pb.ProjectionRebuildState_PROJECTION_REBUILD_STATE_RUNNING
+	case domain.ProjectionRebuildStateDone:
+		return system_pb.ProjectionRebuildState_PROJECTION_REBUILD_STATE_DONE
+	case domain.ProjectionRebuildStateFailed:
+		return system_pb.ProjectionRebuildState_PROJECTION_REBUILD_STATE_FAILED
+	default:
+		return system_pb.ProjectionRebuildState_PROJECTION_REBUILD_STATE_UNSPECIFIED
+	}
+}
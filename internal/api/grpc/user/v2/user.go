@@ -2,7 +2,9 @@ package user
 
 import (
 	"context"
+	"encoding/json"
 	"io"
+	"sort"
 
 	"golang.org/x/text/language"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -14,6 +16,7 @@ import (
 	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/idp"
 	object_pb "github.com/zitadel/zitadel/pkg/grpc/object/v2alpha"
 	user "github.com/zitadel/zitadel/pkg/grpc/user/v2alpha"
 )
@@ -24,10 +27,18 @@ func (s *Server) AddHumanUser(ctx context.Context, req *user.AddHumanUserRequest
 		return nil, err
 	}
 	orgID := authz.GetCtxData(ctx).OrgID
+	mappedInformation, err := s.intentMappedInformation(ctx, req.GetIdpIntent(), orgID)
+	if err != nil {
+		return nil, err
+	}
+	human.Metadata = appendMappedMetadata(human.Metadata, mappedInformation)
 	err = s.command.AddHuman(ctx, orgID, human, false)
 	if err != nil {
 		return nil, err
 	}
+	if err = s.syncMappedRoles(ctx, human.ID, orgID, mappedInformation); err != nil {
+		return nil, err
+	}
 	return &user.AddHumanUserResponse{
 		UserId:    human.ID,
 		Details:   object.DomainToDetailsPb(human.Details),
@@ -94,6 +105,67 @@ func addUserRequestToAddHuman(req *user.AddHumanUserRequest) (*command.AddHuman,
 	}, nil
 }
 
+// intentMappedInformation returns the information mapped from the claims of the succeeded intent,
+// nil is returned if no intent is provided
+func (s *Server) intentMappedInformation(ctx context.Context, idpIntent *user.IDPIntent, orgID string) (*idp.MappedInformation, error) {
+	if idpIntent == nil {
+		return nil, nil
+	}
+	intent, err := s.command.GetIntentWriteModel(ctx, idpIntent.GetIntentId(), orgID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkIntentToken(idpIntent.GetToken(), intent.AggregateID); err != nil {
+		return nil, err
+	}
+	if intent.State != domain.IDPIntentStateSucceeded {
+		return nil, errors.ThrowPreconditionFailed(nil, "USER-Ks8dq", "Errors.Intent.NotSucceeded")
+	}
+	mappedInformation := new(idp.MappedInformation)
+	if len(intent.IDPMappedUser) == 0 {
+		return mappedInformation, nil
+	}
+	if err := json.Unmarshal(intent.IDPMappedUser, mappedInformation); err != nil {
+		return nil, errors.ThrowInternal(err, "USER-Wq2hs", "Errors.Internal")
+	}
+	return mappedInformation, nil
+}
+
+// appendMappedMetadata adds the mapped metadata to the metadata of the request,
+// entries already provided by the request are not overwritten
+func appendMappedMetadata(metadata []*command.AddMetadataEntry, mappedInformation *idp.MappedInformation) []*command.AddMetadataEntry {
+	if mappedInformation == nil || len(mappedInformation.Metadata) == 0 {
+		return metadata
+	}
+	existing := make(map[string]struct{}, len(metadata))
+	for _, entry := range metadata {
+		existing[entry.Key] = struct{}{}
+	}
+	keys := make([]string, 0, len(mappedInformation.Metadata))
+	for key := range mappedInformation.Metadata {
+		if _, ok := existing[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		metadata = append(metadata, &command.AddMetadataEntry{
+			Key:   key,
+			Value: mappedInformation.Metadata[key],
+		})
+	}
+	return metadata
+}
+
+// syncMappedRoles grants the roles mapped from the claims to the created user
+func (s *Server) syncMappedRoles(ctx context.Context, userID, orgID string, mappedInformation *idp.MappedInformation) error {
+	if mappedInformation == nil || len(mappedInformation.Roles) == 0 {
+		return nil
+	}
+	// the user was just created, so only the mapped (and therefore allowed) roles need to be managed
+	return s.command.SyncUserGrantsByIDPClaims(ctx, userID, orgID, mappedInformation.Roles, mappedInformation.Roles, nil)
+}
+
 func genderToDomain(gender user.Gender) domain.Gender {
 	switch gender {
 	case user.Gender_GENDER_UNSPECIFIED:
@@ -113,9 +185,9 @@ func hashedPasswordToCommand(hashed *user.HashedPassword) (string, error) {
 	if hashed == nil {
 		return "", nil
 	}
-	// we currently only handle bcrypt
-	if hashed.GetAlgorithm() != "bcrypt" {
-		return "", errors.ThrowInvalidArgument(nil, "USER-JDk4t", "Errors.InvalidArgument")
+	// the algorithm is identified by the encoded hash on verification
+	if err := crypto.CheckPasswordHash(hashed.GetAlgorithm(), []byte(hashed.GetHash())); err != nil {
+		return "", errors.ThrowInvalidArgument(err, "USER-JDk4t", "Errors.InvalidArgument")
 	}
 	return hashed.GetHash(), nil
 }
@@ -181,6 +253,13 @@ func intentToIDPInformationPb(intent *command.IDPIntentWriteModel, alg crypto.En
 	if err != nil {
 		return nil, err
 	}
+	var mappedInformation *structpb.Struct
+	if len(intent.IDPMappedUser) > 0 {
+		mappedInformation = new(structpb.Struct)
+		if err = mappedInformation.UnmarshalJSON(intent.IDPMappedUser); err != nil {
+			return nil, err
+		}
+	}
 
 	return &user.RetrieveIdentityProviderInformationResponse{
 		Details: &object_pb.Details{
@@ -195,10 +274,11 @@ func intentToIDPInformationPb(intent *command.IDPIntentWriteModel, alg crypto.En
 					IdToken:     idToken,
 				},
 			},
-			IdpId:          intent.IDPID,
-			UserId:         intent.IDPUserID,
-			UserName:       intent.IDPUserName,
-			RawInformation: rawInformation,
+			IdpId:             intent.IDPID,
+			UserId:            intent.IDPUserID,
+			UserName:          intent.IDPUserName,
+			RawInformation:    rawInformation,
+			MappedInformation: mappedInformation,
 		},
 	}, nil
 }
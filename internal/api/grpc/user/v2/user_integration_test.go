@@ -421,6 +421,43 @@ func TestServer_AddHumanUser(t *testing.T) {
 	}
 }
 
+func TestServer_AddHumanUser_RejectBreached(t *testing.T) {
+	_, err := Tester.Client.Mgmt.AddCustomPasswordComplexityPolicy(CTX, &mgmt.AddCustomPasswordComplexityPolicyRequest{
+		MinLength:      8,
+		RejectBreached: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := Tester.Client.Mgmt.ResetPasswordComplexityPolicyToDefault(CTX, &mgmt.ResetPasswordComplexityPolicyToDefaultRequest{})
+		require.NoError(t, err)
+	})
+
+	userID := fmt.Sprint(time.Now().UnixNano())
+	// the integration setup has no breached-password corpus configured,
+	// so every password is rejected by the policy
+	_, err = Client.AddHumanUser(CTX, &user.AddHumanUserRequest{
+		UserId: &userID,
+		Organisation: &object.Organisation{
+			Org: &object.Organisation_OrgId{
+				OrgId: Tester.Organisation.ID,
+			},
+		},
+		Profile: &user.SetHumanProfile{
+			FirstName: "Donald",
+			LastName:  "Duck",
+		},
+		Email: &user.SetHumanEmail{
+			Email: fmt.Sprintf("%s@me.now", userID),
+		},
+		PasswordType: &user.AddHumanUserRequest_Password{
+			Password: &user.Password{
+				Password: "DifficultPW666!",
+			},
+		},
+	})
+	require.Error(t, err)
+}
+
 func TestServer_AddIDPLink(t *testing.T) {
 	idpID := Tester.AddGenericOAuthProvider(t)
 	type args struct {
@@ -19,6 +19,7 @@ import (
 	"github.com/zitadel/zitadel/internal/domain"
 	caos_errs "github.com/zitadel/zitadel/internal/errors"
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/idp"
 	object_pb "github.com/zitadel/zitadel/pkg/grpc/object/v2alpha"
 	user "github.com/zitadel/zitadel/pkg/grpc/user/v2alpha"
 )
@@ -63,19 +64,92 @@ func Test_hashedPasswordToCommand(t *testing.T) {
 				},
 			},
 		},
+		{
+			"hashed, argon2id",
+			args{
+				hashed: &user.HashedPassword{
+					Hash:      "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$c2FsdHNhbHRzYWx0c2FsdA",
+					Algorithm: "argon2id",
+				},
+			},
+			res{
+				"$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$c2FsdHNhbHRzYWx0c2FsdA",
+				nil,
+			},
+		},
+		{
+			"hashed, argon2id without parallelism",
+			args{
+				hashed: &user.HashedPassword{
+					Hash:      "$argon2id$v=19$m=65536,t=3,p=0$c2FsdA$c2FsdHNhbHRzYWx0c2FsdA",
+					Algorithm: "argon2id",
+				},
+			},
+			res{
+				"",
+				func(err error) bool {
+					return errors.Is(err, caos_errs.ThrowInvalidArgument(nil, "USER-JDk4t", "Errors.InvalidArgument"))
+				},
+			},
+		},
+		{
+			"hashed, scrypt with short digest",
+			args{
+				hashed: &user.HashedPassword{
+					Hash:      "$scrypt$ln=10,r=8,p=1$c2FsdA$aGFzaA",
+					Algorithm: "scrypt",
+				},
+			},
+			res{
+				"",
+				func(err error) bool {
+					return errors.Is(err, caos_errs.ThrowInvalidArgument(nil, "USER-JDk4t", "Errors.InvalidArgument"))
+				},
+			},
+		},
+		{
+			"hashed, pbkdf2 with too many iterations",
+			args{
+				hashed: &user.HashedPassword{
+					Hash:      "$pbkdf2-sha256$i=2147483647$c2FsdA$c2FsdHNhbHRzYWx0c2FsdA",
+					Algorithm: "pbkdf2",
+				},
+			},
+			res{
+				"",
+				func(err error) bool {
+					return errors.Is(err, caos_errs.ThrowInvalidArgument(nil, "USER-JDk4t", "Errors.InvalidArgument"))
+				},
+			},
+		},
 		{
 			"hashed, bcrypt",
 			args{
 				hashed: &user.HashedPassword{
-					Hash:      "hash",
+					Hash:      "$2a$04$FSc5eGlPZuGrnIz7wQiIqeJ3xgOqrG8jQhoVMjaTGgXibHKMdQ9ae",
 					Algorithm: "bcrypt",
 				},
 			},
 			res{
-				"hash",
+				"$2a$04$FSc5eGlPZuGrnIz7wQiIqeJ3xgOqrG8jQhoVMjaTGgXibHKMdQ9ae",
 				nil,
 			},
 		},
+		{
+			"hashed, invalid bcrypt",
+			args{
+				hashed: &user.HashedPassword{
+					Hash:      "hash",
+					Algorithm: "bcrypt",
+				},
+			},
+			res{
+				"",
+				func(err error) bool {
+					return errors.Is(err, caos_errs.ThrowInvalidArgument(nil, "USER-JDk4t", "Errors.InvalidArgument"))
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -164,10 +238,11 @@ func Test_intentToIDPInformationPb(t *testing.T) {
 						InstanceID:        "instanceID",
 						ChangeDate:        time.Date(2019, 4, 1, 1, 1, 1, 1, time.Local),
 					},
-					IDPID:       "idpID",
-					IDPUser:     []byte(`{"userID": "idpUserID", "username": "username"}`),
-					IDPUserID:   "idpUserID",
-					IDPUserName: "username",
+					IDPID:         "idpID",
+					IDPUser:       []byte(`{"userID": "idpUserID", "username": "username"}`),
+					IDPMappedUser: []byte(`{"firstName": "first", "metadata": {"department": "c2FsZXM="}}`),
+					IDPUserID:     "idpUserID",
+					IDPUserName:   "username",
 					IDPAccessToken: &crypto.CryptoValue{
 						CryptoType: crypto.TypeEncryption,
 						Algorithm:  "enc",
@@ -205,6 +280,16 @@ func Test_intentToIDPInformationPb(t *testing.T) {
 							require.NoError(t, err)
 							return s
 						}(),
+						MappedInformation: func() *structpb.Struct {
+							s, err := structpb.NewStruct(map[string]interface{}{
+								"firstName": "first",
+								"metadata": map[string]interface{}{
+									"department": "c2FsZXM=",
+								},
+							})
+							require.NoError(t, err)
+							return s
+						}(),
 					},
 				},
 				err: nil,
@@ -223,6 +308,50 @@ func Test_intentToIDPInformationPb(t *testing.T) {
 	}
 }
 
+func Test_appendMappedMetadata(t *testing.T) {
+	type args struct {
+		metadata          []*command.AddMetadataEntry
+		mappedInformation *idp.MappedInformation
+	}
+	tests := []struct {
+		name string
+		args args
+		want []*command.AddMetadataEntry
+	}{
+		{
+			"no intent",
+			args{
+				metadata: []*command.AddMetadataEntry{{Key: "key", Value: []byte("value")}},
+			},
+			[]*command.AddMetadataEntry{{Key: "key", Value: []byte("value")}},
+		},
+		{
+			"mapped metadata appended",
+			args{
+				metadata: []*command.AddMetadataEntry{{Key: "key", Value: []byte("value")}},
+				mappedInformation: &idp.MappedInformation{
+					Metadata: map[string][]byte{
+						"key":        []byte("mapped"),
+						"department": []byte("sales"),
+						"country":    []byte("ch"),
+					},
+				},
+			},
+			[]*command.AddMetadataEntry{
+				{Key: "key", Value: []byte("value")},
+				{Key: "country", Value: []byte("ch")},
+				{Key: "department", Value: []byte("sales")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := appendMappedMetadata(tt.args.metadata, tt.args.mappedInformation)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_authMethodTypesToPb(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TrustedProxies are the networks of the reverse proxies in front of ZITADEL,
+// which are allowed to set the X-Forwarded-For header.
+// Loopback addresses are always trusted, as the grpc gateway calls the grpc server through them.
+type TrustedProxies []*net.IPNet
+
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: cidr}
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+func (p TrustedProxies) trusted(ip net.IP) bool {
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the IP of the client of the request:
+// the peer address, unless it's a trusted proxy,
+// in which case the X-Forwarded-For header is read from right to left until the first untrusted address.
+func (p TrustedProxies) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !p.trusted(ip) {
+		return host
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values(ForwardedFor), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedIP := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if forwardedIP == nil {
+			// an invalid entry can't be traced any further, the last valid address is used
+			break
+		}
+		ip = forwardedIP
+		if !p.trusted(ip) {
+			break
+		}
+	}
+	return ip.String()
+}
+
+// ClientIPHandler resolves the IP of the client by the trusted proxies and sets it to the context of the request
+func ClientIPHandler(proxies TrustedProxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIP, proxies.ClientIP(r))))
+		})
+	}
+}
+
+// ClientIPFromCtx returns the IP of the client resolved by the ClientIPHandler,
+// an empty string if the IP was not resolved
+func ClientIPFromCtx(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIP).(string)
+	return ip
+}
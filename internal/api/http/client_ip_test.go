@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxies_ClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{
+			name:       "untrusted peer, forwarded ignored",
+			remoteAddr: "1.2.3.4:1234",
+			forwarded:  []string{"5.6.7.8"},
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "trusted peer, no forwarded",
+			remoteAddr: "10.0.0.1:1234",
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "trusted peer, forwarded",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"5.6.7.8"},
+			want:       "5.6.7.8",
+		},
+		{
+			name:       "trusted peer, spoofed forwarded entries ignored",
+			remoteAddr: "192.168.1.1:1234",
+			forwarded:  []string{"9.9.9.9, 5.6.7.8", "10.1.1.1"},
+			want:       "5.6.7.8",
+		},
+		{
+			name:       "loopback peer (grpc gateway), forwarded",
+			remoteAddr: "127.0.0.1:1234",
+			forwarded:  []string{"5.6.7.8"},
+			want:       "5.6.7.8",
+		},
+		{
+			name:       "trusted peer, invalid forwarded entry",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"invalid, 5.6.7.8"},
+			want:       "5.6.7.8",
+		},
+		{
+			name:       "only trusted addresses, leftmost",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"10.0.0.3, 10.0.0.2"},
+			want:       "10.0.0.3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, forwarded := range tt.forwarded {
+				r.Header.Add(ForwardedFor, forwarded)
+			}
+			assert.Equal(t, tt.want, proxies.ClientIP(r))
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	_, err := ParseTrustedProxies([]string{"invalid"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	proxies, err := ParseTrustedProxies([]string{"", " 10.0.0.0/8 ", "::1"})
+	require.NoError(t, err)
+	assert.Len(t, proxies, 2)
+}
+
+func TestClientIPHandler(t *testing.T) {
+	var got string
+	handler := ClientIPHandler(nil)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = ClientIPFromCtx(r.Context())
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "1.2.3.4:1234"
+	r.Header.Set(ForwardedFor, "5.6.7.8")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, "1.2.3.4", got)
+}
@@ -25,6 +25,7 @@ const (
 	XRequestedWith  = "x-requested-with"
 	XRobotsTag      = "x-robots-tag"
 	IfNoneMatch     = "If-None-Match"
+	IfMatch         = "If-Match"
 	LastModified    = "Last-Modified"
 	Etag            = "Etag"
 
@@ -45,6 +46,7 @@ type key int
 const (
 	httpHeaders key = iota
 	remoteAddr
+	clientIP
 )
 
 func CopyHeadersToContext(h http.Handler) http.Handler {
@@ -69,7 +71,12 @@ func OriginFromCtx(ctx context.Context) string {
 	return headers.Get(Origin)
 }
 
+// RemoteIPFromCtx returns the IP of the client resolved by the trusted proxies,
+// if the ClientIPHandler was not called, the first address of the X-Forwarded-For header or the peer address is returned
 func RemoteIPFromCtx(ctx context.Context) string {
+	if ip := ClientIPFromCtx(ctx); ip != "" {
+		return ip
+	}
 	ctxHeaders, ok := HeadersFromCtx(ctx)
 	if !ok {
 		return RemoteAddrFromCtx(ctx)
@@ -86,6 +93,9 @@ func RemoteIPFromRequest(r *http.Request) net.IP {
 }
 
 func RemoteIPStringFromRequest(r *http.Request) string {
+	if ip := ClientIPFromCtx(r.Context()); ip != "" {
+		return ip
+	}
 	ip, ok := GetForwardedFor(r.Header)
 	if ok {
 		return ip
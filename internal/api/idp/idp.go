@@ -24,6 +24,7 @@ import (
 	"github.com/zitadel/zitadel/internal/idp/providers/ldap"
 	"github.com/zitadel/zitadel/internal/idp/providers/oauth"
 	openid "github.com/zitadel/zitadel/internal/idp/providers/oidc"
+	"github.com/zitadel/zitadel/internal/idp/providers/saml"
 	"github.com/zitadel/zitadel/internal/query"
 )
 
@@ -117,6 +118,13 @@ func (h *Handler) handleCallback(w http.ResponseWriter, r *http.Request) {
 		redirectToFailureURLErr(w, r, intent, err)
 		return
 	}
+	idpUser, err = h.mapIDPUser(ctx, intent.IDPID, idpUser)
+	if err != nil {
+		cmdErr := h.commands.FailIDPIntent(ctx, intent, err.Error())
+		logging.WithFields("intent", intent.AggregateID).OnError(cmdErr).Error("failed to push failed event on idp intent")
+		redirectToFailureURLErr(w, r, intent, err)
+		return
+	}
 	userID, err := h.checkExternalUser(ctx, intent.IDPID, idpUser.GetID())
 	logging.WithFields("intent", intent.AggregateID).OnError(err).Error("could not check if idp user already exists")
 
@@ -203,7 +211,7 @@ func (h *Handler) fetchIDPUser(ctx context.Context, identityProvider idp.Provide
 		session = &openid.Session{Provider: provider.Provider, Code: code}
 	case *google.Provider:
 		session = &openid.Session{Provider: provider.Provider, Code: code}
-	case *jwt.Provider, *ldap.Provider:
+	case *jwt.Provider, *ldap.Provider, *saml.Provider:
 		return nil, nil, z_errs.ThrowInvalidArgument(nil, "IDP-52jmn", "Errors.ExternalIDP.IDPTypeNotImplemented")
 	default:
 		return nil, nil, z_errs.ThrowUnimplemented(nil, "IDP-SSDg", "Errors.ExternalIDP.IDPTypeNotImplemented")
@@ -216,6 +224,19 @@ func (h *Handler) fetchIDPUser(ctx context.Context, identityProvider idp.Provide
 	return user, session, nil
 }
 
+// mapIDPUser applies the claim mappings of the identity provider to the user
+func (h *Handler) mapIDPUser(ctx context.Context, idpID string, user idp.User) (idp.User, error) {
+	claimMappings, err := h.queries.IDPClaimMappingsByID(ctx, false, idpID, false)
+	if err != nil {
+		return nil, err
+	}
+	mappedUser, err := idp.MapUser(user, claimMappings.Mappings)
+	if err != nil {
+		return nil, err
+	}
+	return mappedUser, nil
+}
+
 func (h *Handler) checkExternalUser(ctx context.Context, idpID, externalUserID string) (userID string, err error) {
 	idQuery, err := query.NewIDPUserLinkIDPIDSearchQuery(idpID)
 	if err != nil {
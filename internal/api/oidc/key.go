@@ -114,18 +114,35 @@ func (o *OPStorage) SigningKey(ctx context.Context) (key op.SigningKey, err erro
 }
 
 func (o *OPStorage) getSigningKey(ctx context.Context) (op.SigningKey, error) {
+	algorithm, err := o.getSigningKeyAlgorithm(ctx)
+	if err != nil {
+		return nil, err
+	}
 	keys, err := o.query.ActivePrivateSigningKey(ctx, time.Now().Add(gracefulPeriod))
 	if err != nil {
 		return nil, err
 	}
-	if len(keys.Keys) > 0 {
-		return o.privateKeyToSigningKey(selectSigningKey(keys.Keys))
+	// keys of a previously configured algorithm are only used for verification until they expire
+	if key := selectSigningKey(keys.Keys, algorithm); key != nil {
+		return o.privateKeyToSigningKey(key)
 	}
 	var sequence uint64
 	if keys.LatestSequence != nil {
 		sequence = keys.LatestSequence.Sequence
 	}
-	return nil, o.refreshSigningKey(ctx, o.signingKeyAlgorithm, sequence)
+	return nil, o.refreshSigningKey(ctx, algorithm, sequence)
+}
+
+// getSigningKeyAlgorithm returns the signing key algorithm of the instance or the default if none is set
+func (o *OPStorage) getSigningKeyAlgorithm(ctx context.Context) (string, error) {
+	oidcSettings, err := o.query.OIDCSettingsByAggID(ctx, authz.GetInstance(ctx).InstanceID())
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	if oidcSettings != nil && oidcSettings.SigningKeyAlgorithm != "" {
+		return oidcSettings.SigningKeyAlgorithm, nil
+	}
+	return o.signingKeyAlgorithm, nil
 }
 
 func (o *OPStorage) refreshSigningKey(ctx context.Context, algorithm string, sequence uint64) error {
@@ -153,7 +170,7 @@ func (o *OPStorage) privateKeyToSigningKey(key query.PrivateKey) (_ op.SigningKe
 	if err != nil {
 		return nil, err
 	}
-	privateKey, err := crypto.BytesToPrivateKey(keyData)
+	privateKey, err := crypto.BytesToPrivateSigningKey(keyData)
 	if err != nil {
 		return nil, err
 	}
@@ -194,8 +211,13 @@ func (o *OPStorage) getMaxKeySequence(ctx context.Context) (uint64, error) {
 	)
 }
 
-func selectSigningKey(keys []query.PrivateKey) query.PrivateKey {
-	return keys[len(keys)-1]
+func selectSigningKey(keys []query.PrivateKey, algorithm string) query.PrivateKey {
+	for i := len(keys) - 1; i >= 0; i-- {
+		if keys[i].Algorithm() == algorithm {
+			return keys[i]
+		}
+	}
+	return nil
 }
 
 func setOIDCCtx(ctx context.Context) context.Context {
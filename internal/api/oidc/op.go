@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/rakyll/statik/fs"
+	"github.com/zitadel/oidc/v2/pkg/oidc"
 	"github.com/zitadel/oidc/v2/pkg/op"
 	"golang.org/x/text/language"
+	"gopkg.in/square/go-jose.v2"
 
 	"github.com/zitadel/zitadel/internal/api/assets"
 	http_utils "github.com/zitadel/zitadel/internal/api/http"
@@ -81,11 +83,11 @@ func NewProvider(config Config, defaultLogoutRedirectURI string, externalSecure
 		return nil, caos_errs.ThrowInternal(err, "OIDC-EGrqd", "cannot create op config: %w")
 	}
 	storage := newStorage(config, command, query, repo, encryptionAlg, es, projections, externalSecure)
-	options, err := createOptions(config, externalSecure, userAgentCookie, instanceHandler, accessHandler)
+	options, err := createOptions(config, externalSecure)
 	if err != nil {
 		return nil, caos_errs.ThrowInternal(err, "OIDC-D3gq1", "cannot create options: %w")
 	}
-	provider, err := op.NewDynamicOpenIDProvider(
+	opProvider, err := op.NewDynamicOpenIDProvider(
 		"",
 		opConfig,
 		storage,
@@ -94,7 +96,64 @@ func NewProvider(config Config, defaultLogoutRedirectURI string, externalSecure
 	if err != nil {
 		return nil, caos_errs.ThrowInternal(err, "OIDC-DAtg3", "cannot create provider")
 	}
-	return provider, nil
+	p := &provider{
+		Provider: opProvider,
+		keySet:   &openIDKeySet{storage},
+	}
+	p.httpHandler = op.CreateRouter(p, createInterceptors(userAgentCookie, instanceHandler, accessHandler)...)
+	return p, nil
+}
+
+// provider verifies the id token hints and access tokens with a key set
+// which matches the ed25519 keys of EdDSA signatures, as the oidc library only matches RSA and ECDSA keys.
+// The router is created with the provider itself, so the endpoints use these verifiers
+type provider struct {
+	*op.Provider
+	keySet      oidc.KeySet
+	httpHandler http.Handler
+}
+
+func (p *provider) HttpHandler() http.Handler {
+	return p.httpHandler
+}
+
+// IDTokenHintVerifier accepts the signatures of all algorithms the signing keys of the instances might use,
+// also of the keys of a previously configured algorithm until they expire
+func (p *provider) IDTokenHintVerifier(ctx context.Context) op.IDTokenHintVerifier {
+	return op.NewIDTokenHintVerifier(op.IssuerFromContext(ctx), p.keySet, op.WithSupportedIDTokenHintSigningAlgorithms(crypto.SigningAlgorithms...))
+}
+
+// AccessTokenVerifier accepts the signatures of all algorithms the signing keys of the instances might use,
+// also of the keys of a previously configured algorithm until they expire
+func (p *provider) AccessTokenVerifier(ctx context.Context) op.AccessTokenVerifier {
+	return op.NewAccessTokenVerifier(op.IssuerFromContext(ctx), p.keySet, op.WithSupportedAccessTokenSigningAlgorithms(crypto.SigningAlgorithms...))
+}
+
+type openIDKeySet struct {
+	storage op.Storage
+}
+
+// VerifySignature implements the oidc.KeySet interface
+func (k *openIDKeySet) VerifySignature(ctx context.Context, jws *jose.JSONWebSignature) ([]byte, error) {
+	keys, err := k.storage.KeySet(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching keys: %w", err)
+	}
+	webKeys := make([]jose.JSONWebKey, len(keys))
+	for i, key := range keys {
+		webKeys[i] = jose.JSONWebKey{
+			KeyID:     key.ID(),
+			Algorithm: string(key.Algorithm()),
+			Use:       key.Use(),
+			Key:       key.Key(),
+		}
+	}
+	keyID, alg := oidc.GetKeyIDAndAlg(jws)
+	key, err := crypto.FindSigningKey(keyID, alg, webKeys...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	return jws.Verify(&key)
 }
 
 func createOPConfig(config Config, defaultLogoutRedirectURI string, cryptoKey []byte) (*op.Config, error) {
@@ -119,19 +178,21 @@ func createOPConfig(config Config, defaultLogoutRedirectURI string, cryptoKey []
 	return opConfig, nil
 }
 
-func createOptions(config Config, externalSecure bool, userAgentCookie, instanceHandler, accessHandler func(http.Handler) http.Handler) ([]op.Option, error) {
+func createInterceptors(userAgentCookie, instanceHandler, accessHandler func(http.Handler) http.Handler) []op.HttpInterceptor {
 	metricTypes := []metrics.MetricType{metrics.MetricTypeRequestCount, metrics.MetricTypeStatusCode, metrics.MetricTypeTotalCount}
-	options := []op.Option{
-		op.WithHttpInterceptors(
-			middleware.MetricsHandler(metricTypes),
-			middleware.TelemetryHandler(),
-			middleware.NoCacheInterceptor().Handler,
-			instanceHandler,
-			userAgentCookie,
-			http_utils.CopyHeadersToContext,
-			accessHandler,
-		),
+	return []op.HttpInterceptor{
+		middleware.MetricsHandler(metricTypes),
+		middleware.TelemetryHandler(),
+		middleware.NoCacheInterceptor().Handler,
+		instanceHandler,
+		userAgentCookie,
+		http_utils.CopyHeadersToContext,
+		accessHandler,
 	}
+}
+
+func createOptions(config Config, externalSecure bool) ([]op.Option, error) {
+	var options []op.Option
 	if !externalSecure {
 		options = append(options, op.WithAllowInsecure())
 	}
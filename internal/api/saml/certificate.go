@@ -2,6 +2,7 @@ package saml
 
 import (
 	"context"
+	"crypto/rsa"
 	"fmt"
 	"time"
 
@@ -28,6 +29,11 @@ const (
 	retryCount     = 3
 	lockDuration   = retryCount * retryBackoff * 5
 	gracefulPeriod = 10 * time.Minute
+
+	// certificateAlgorithm is the algorithm of the certificate keys.
+	// The saml library only signs with RSA keys (key.CertificateAndKey),
+	// so the certificates don't follow the signing key algorithm of the OIDC settings
+	certificateAlgorithm = crypto.SigningAlgorithmRS256
 )
 
 type CertificateAndKey struct {
@@ -139,14 +145,14 @@ func (p *Storage) lockAndGenerateCertificateAndKey(ctx context.Context, usage do
 
 		switch usage {
 		case domain.KeyUsageSAMLMetadataSigning:
-			return p.command.GenerateSAMLMetadataCertificate(setSAMLCtx(ctx), p.certificateAlgorithm, certAndKey.Key, certAndKey.Certificate)
+			return p.command.GenerateSAMLMetadataCertificate(setSAMLCtx(ctx), certificateAlgorithm, certAndKey.Key, certAndKey.Certificate)
 		case domain.KeyUsageSAMLResponseSinging:
-			return p.command.GenerateSAMLResponseCertificate(setSAMLCtx(ctx), p.certificateAlgorithm, certAndKey.Key, certAndKey.Certificate)
+			return p.command.GenerateSAMLResponseCertificate(setSAMLCtx(ctx), certificateAlgorithm, certAndKey.Key, certAndKey.Certificate)
 		default:
 			return fmt.Errorf("unknown usage")
 		}
 	case domain.KeyUsageSAMLCA:
-		return p.command.GenerateSAMLCACertificate(setSAMLCtx(ctx), p.certificateAlgorithm)
+		return p.command.GenerateSAMLCACertificate(setSAMLCtx(ctx), certificateAlgorithm)
 	default:
 		return fmt.Errorf("unknown certificate usage")
 	}
@@ -167,10 +173,14 @@ func (p *Storage) certificateToCertificateAndKey(certificate query.Certificate)
 	if err != nil {
 		return nil, err
 	}
-	privateKey, err := crypto.BytesToPrivateKey(keyData)
+	signingKey, err := crypto.BytesToPrivateSigningKey(keyData)
 	if err != nil {
 		return nil, err
 	}
+	privateKey, ok := signingKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.ThrowInternal(nil, "SAML-Ooz4c", "certificate key is not an RSA key")
+	}
 
 	cert, err := crypto.BytesToCertificate(certificate.Certificate())
 	if err != nil {
@@ -35,10 +35,9 @@ type Storage struct {
 	currentMetadataCertificate query.Certificate
 	currentResponseCertificate query.Certificate
 
-	locker               crdb.Locker
-	certificateAlgorithm string
-	encAlg               crypto.EncryptionAlgorithm
-	certEncAlg           crypto.EncryptionAlgorithm
+	locker     crdb.Locker
+	encAlg     crypto.EncryptionAlgorithm
+	certEncAlg crypto.EncryptionAlgorithm
 
 	eventstore *eventstore.Eventstore
 	repo       repository.Repository
@@ -0,0 +1,436 @@
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+// filterExpression is a node of a parsed filter (RFC 7644, section 3.4.2.2)
+type filterExpression interface {
+	isFilterExpression()
+}
+
+// attributeExpression compares an attribute with a value, e.g. `userName eq "bjensen"`
+type attributeExpression struct {
+	// Path is the lower cased attribute path without the schema of the resource, e.g. `name.givenname`
+	Path     string
+	Operator string
+	// Value is a string, float64, bool or nil
+	Value interface{}
+}
+
+// logicalExpression combines two expressions with `and` or `or`
+type logicalExpression struct {
+	Operator string
+	Left     filterExpression
+	Right    filterExpression
+}
+
+type notExpression struct {
+	Expression filterExpression
+}
+
+// valuePathExpression filters the values of a multi-valued attribute, e.g. `emails[type eq "work"]`
+type valuePathExpression struct {
+	Path   string
+	Filter filterExpression
+}
+
+func (*attributeExpression) isFilterExpression() {}
+func (*logicalExpression) isFilterExpression()   {}
+func (*notExpression) isFilterExpression()       {}
+func (*valuePathExpression) isFilterExpression() {}
+
+const (
+	operatorEqual      = "eq"
+	operatorNotEqual   = "ne"
+	operatorContains   = "co"
+	operatorStartsWith = "sw"
+	operatorEndsWith   = "ew"
+	operatorPresent    = "pr"
+	operatorGreater    = "gt"
+	operatorGreaterEq  = "ge"
+	operatorLess       = "lt"
+	operatorLessEq     = "le"
+
+	operatorAnd = "and"
+	operatorOr  = "or"
+	operatorNot = "not"
+)
+
+var comparisonOperators = map[string]bool{
+	operatorEqual:      true,
+	operatorNotEqual:   true,
+	operatorContains:   true,
+	operatorStartsWith: true,
+	operatorEndsWith:   true,
+	operatorGreater:    true,
+	operatorGreaterEq:  true,
+	operatorLess:       true,
+	operatorLessEq:     true,
+}
+
+const (
+	// maxFilterDepth is the maximum nesting of parentheses, not and value paths in a filter
+	maxFilterDepth = 10
+	// maxFilterTerms is the maximum amount of attribute comparisons in a filter
+	maxFilterTerms = 50
+)
+
+type tokenType int
+
+const (
+	tokenWord tokenType = iota
+	tokenString
+	tokenOpenParenthesis
+	tokenCloseParenthesis
+	tokenOpenBracket
+	tokenCloseBracket
+)
+
+type token struct {
+	typ   tokenType
+	value string
+}
+
+// parseFilter parses the filter of a list request,
+// the attribute paths of the schema are removed, so they can be compared without it
+func parseFilter(filter, schema string) (filterExpression, error) {
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens, schema: strings.ToLower(schema) + ":"}
+	expression, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.position < len(p.tokens) {
+		return nil, invalidFilterError("SCIM-Fk2lq", "unexpected "+p.tokens[p.position].value)
+	}
+	return expression, nil
+}
+
+func tokenizeFilter(filter string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(filter)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{typ: tokenOpenParenthesis, value: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{typ: tokenCloseParenthesis, value: ")"})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{typ: tokenOpenBracket, value: "["})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{typ: tokenCloseBracket, value: "]"})
+			i++
+		case r == '"':
+			end := i + 1
+			for ; end < len(runes) && runes[end] != '"'; end++ {
+				if runes[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(runes) {
+				return nil, invalidFilterError("SCIM-Pw9dm", "unterminated string")
+			}
+			var value string
+			if err := json.Unmarshal([]byte(string(runes[i:end+1])), &value); err != nil {
+				return nil, invalidFilterError("SCIM-Ud8wq", "invalid string")
+			}
+			tokens = append(tokens, token{typ: tokenString, value: value})
+			i = end + 1
+		default:
+			end := i
+			for ; end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()[]"`, runes[end]); end++ {
+			}
+			tokens = append(tokens, token{typ: tokenWord, value: string(runes[i:end])})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens   []token
+	position int
+	schema   string
+	depth    int
+	terms    int
+}
+
+func (p *filterParser) peek() *token {
+	if p.position >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.position]
+}
+
+func (p *filterParser) next() *token {
+	t := p.peek()
+	if t != nil {
+		p.position++
+	}
+	return t
+}
+
+func (p *filterParser) peekKeyword(keyword string) bool {
+	t := p.peek()
+	return t != nil && t.typ == tokenWord && strings.EqualFold(t.value, keyword)
+}
+
+// nested parses a nested expression, e.g. in parentheses, and limits the depth of the filter
+func (p *filterParser) nested(parse func() (filterExpression, error)) (filterExpression, error) {
+	if p.depth >= maxFilterDepth {
+		return nil, invalidFilterError("SCIM-Dp4kw", "too deeply nested")
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+	return parse()
+}
+
+func (p *filterParser) expect(typ tokenType, value string) error {
+	t := p.next()
+	if t == nil || t.typ != typ {
+		return invalidFilterError("SCIM-Qm3ls", "expected "+value)
+	}
+	return nil
+}
+
+// parseOr has the lowest precedence: `a and b or c` is parsed as `(a and b) or c`
+func (p *filterParser) parseOr() (filterExpression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword(operatorOr) {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpression{Operator: operatorOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword(operatorAnd) {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpression{Operator: operatorAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (filterExpression, error) {
+	if !p.peekKeyword(operatorNot) {
+		return p.parseTerm()
+	}
+	p.next()
+	if err := p.expect(tokenOpenParenthesis, "("); err != nil {
+		return nil, err
+	}
+	expression, err := p.nested(p.parseOr)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokenCloseParenthesis, ")"); err != nil {
+		return nil, err
+	}
+	return &notExpression{Expression: expression}, nil
+}
+
+func (p *filterParser) parseTerm() (filterExpression, error) {
+	t := p.next()
+	if t == nil {
+		return nil, invalidFilterError("SCIM-Vn2ka", "unexpected end of filter")
+	}
+	switch t.typ {
+	case tokenOpenParenthesis:
+		expression, err := p.nested(p.parseOr)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenCloseParenthesis, ")"); err != nil {
+			return nil, err
+		}
+		return expression, nil
+	case tokenWord:
+		return p.parseAttribute(p.attributePath(t.value))
+	default:
+		return nil, invalidFilterError("SCIM-Ba8wn", "unexpected "+t.value)
+	}
+}
+
+func (p *filterParser) parseAttribute(path string) (filterExpression, error) {
+	if t := p.peek(); t != nil && t.typ == tokenOpenBracket {
+		p.next()
+		filter, err := p.nested(p.parseOr)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenCloseBracket, "]"); err != nil {
+			return nil, err
+		}
+		return &valuePathExpression{Path: path, Filter: filter}, nil
+	}
+	p.terms++
+	if p.terms > maxFilterTerms {
+		return nil, invalidFilterError("SCIM-Tr5nq", "too many terms")
+	}
+	t := p.next()
+	if t == nil || t.typ != tokenWord {
+		return nil, invalidFilterError("SCIM-Lw0dk", "operator missing after "+path)
+	}
+	operator := strings.ToLower(t.value)
+	if operator == operatorPresent {
+		return &attributeExpression{Path: path, Operator: operator}, nil
+	}
+	if !comparisonOperators[operator] {
+		return nil, invalidFilterError("SCIM-Hs3mq", "unknown operator "+t.value)
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return &attributeExpression{Path: path, Operator: operator, Value: value}, nil
+}
+
+func (p *filterParser) parseValue() (interface{}, error) {
+	t := p.next()
+	if t == nil {
+		return nil, invalidFilterError("SCIM-Jd6wn", "value missing")
+	}
+	if t.typ == tokenString {
+		return t.value, nil
+	}
+	if t.typ != tokenWord {
+		return nil, invalidFilterError("SCIM-Ox8sl", "unexpected "+t.value)
+	}
+	switch strings.ToLower(t.value) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	var number float64
+	if err := json.Unmarshal([]byte(t.value), &number); err != nil {
+		return nil, invalidFilterError("SCIM-Tm2aw", "invalid value "+t.value)
+	}
+	return number, nil
+}
+
+// attributePath lower cases the path and removes the schema of the resource
+func (p *filterParser) attributePath(path string) string {
+	path = strings.ToLower(path)
+	return strings.TrimPrefix(path, p.schema)
+}
+
+// matchesFilter evaluates the filter against a (sub) resource, e.g. a value of a multi-valued attribute.
+// Strings are compared case-insensitive.
+func matchesFilter(expression filterExpression, resource map[string]interface{}) bool {
+	switch e := expression.(type) {
+	case *logicalExpression:
+		if e.Operator == operatorAnd {
+			return matchesFilter(e.Left, resource) && matchesFilter(e.Right, resource)
+		}
+		return matchesFilter(e.Left, resource) || matchesFilter(e.Right, resource)
+	case *notExpression:
+		return !matchesFilter(e.Expression, resource)
+	case *valuePathExpression:
+		values, _ := attributeValue(resource, e.Path).([]interface{})
+		for _, value := range values {
+			if subResource, ok := value.(map[string]interface{}); ok && matchesFilter(e.Filter, subResource) {
+				return true
+			}
+		}
+		return false
+	case *attributeExpression:
+		return matchesAttribute(e, attributeValue(resource, e.Path))
+	}
+	return false
+}
+
+func matchesAttribute(expression *attributeExpression, value interface{}) bool {
+	if expression.Operator == operatorPresent {
+		return value != nil && value != ""
+	}
+	text, isText := value.(string)
+	filterText, isFilterText := expression.Value.(string)
+	if !isText || !isFilterText {
+		switch expression.Operator {
+		case operatorEqual:
+			return value == expression.Value
+		case operatorNotEqual:
+			return value != expression.Value
+		}
+		return false
+	}
+	text, filterText = strings.ToLower(text), strings.ToLower(filterText)
+	switch expression.Operator {
+	case operatorEqual:
+		return text == filterText
+	case operatorNotEqual:
+		return text != filterText
+	case operatorContains:
+		return strings.Contains(text, filterText)
+	case operatorStartsWith:
+		return strings.HasPrefix(text, filterText)
+	case operatorEndsWith:
+		return strings.HasSuffix(text, filterText)
+	}
+	return false
+}
+
+// attributeValue returns the value of the (lower cased) path, the keys of the resource are compared case-insensitive
+func attributeValue(resource map[string]interface{}, path string) interface{} {
+	name, subPath, isSub := cutPath(path)
+	for key, value := range resource {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		if !isSub {
+			return value
+		}
+		subResource, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		return attributeValue(subResource, subPath)
+	}
+	return nil
+}
+
+func cutPath(path string) (name, subPath string, found bool) {
+	if i := strings.Index(path, "."); i >= 0 {
+		return path[:i], path[i+1:], true
+	}
+	return path, "", false
+}
+
+func invalidFilterError(id, detail string) error {
+	return &scimTypeError{
+		scimType: scimTypeInvalidFilter,
+		parent:   caos_errs.ThrowInvalidArgument(nil, id, "invalid filter: "+detail),
+	}
+}
@@ -0,0 +1,270 @@
+package scim
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+func Test_parseFilter(t *testing.T) {
+	type args struct {
+		filter string
+		schema string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    filterExpression
+		wantErr bool
+	}{
+		{
+			name: "equal",
+			args: args{
+				filter: `userName eq "bjensen"`,
+			},
+			want: &attributeExpression{Path: "username", Operator: operatorEqual, Value: "bjensen"},
+		},
+		{
+			name: "schema prefix removed",
+			args: args{
+				filter: `urn:ietf:params:scim:schemas:core:2.0:User:name.familyName co "O'Malley"`,
+				schema: schemaUser,
+			},
+			want: &attributeExpression{Path: "name.familyname", Operator: operatorContains, Value: "O'Malley"},
+		},
+		{
+			name: "present",
+			args: args{
+				filter: `title pr`,
+			},
+			want: &attributeExpression{Path: "title", Operator: operatorPresent},
+		},
+		{
+			name: "boolean and number values",
+			args: args{
+				filter: `active eq true and age gt 18`,
+			},
+			want: &logicalExpression{
+				Operator: operatorAnd,
+				Left:     &attributeExpression{Path: "active", Operator: operatorEqual, Value: true},
+				Right:    &attributeExpression{Path: "age", Operator: operatorGreater, Value: float64(18)},
+			},
+		},
+		{
+			name: "and binds stronger than or",
+			args: args{
+				filter: `a eq "1" or b eq "2" and c eq "3"`,
+			},
+			want: &logicalExpression{
+				Operator: operatorOr,
+				Left:     &attributeExpression{Path: "a", Operator: operatorEqual, Value: "1"},
+				Right: &logicalExpression{
+					Operator: operatorAnd,
+					Left:     &attributeExpression{Path: "b", Operator: operatorEqual, Value: "2"},
+					Right:    &attributeExpression{Path: "c", Operator: operatorEqual, Value: "3"},
+				},
+			},
+		},
+		{
+			name: "parentheses and not",
+			args: args{
+				filter: `not (a eq "1" or b eq "2")`,
+			},
+			want: &notExpression{
+				Expression: &logicalExpression{
+					Operator: operatorOr,
+					Left:     &attributeExpression{Path: "a", Operator: operatorEqual, Value: "1"},
+					Right:    &attributeExpression{Path: "b", Operator: operatorEqual, Value: "2"},
+				},
+			},
+		},
+		{
+			name: "value path",
+			args: args{
+				filter: `emails[type eq "work" and value co "@example.com"]`,
+			},
+			want: &valuePathExpression{
+				Path: "emails",
+				Filter: &logicalExpression{
+					Operator: operatorAnd,
+					Left:     &attributeExpression{Path: "type", Operator: operatorEqual, Value: "work"},
+					Right:    &attributeExpression{Path: "value", Operator: operatorContains, Value: "@example.com"},
+				},
+			},
+		},
+		{
+			name: "unknown operator",
+			args: args{
+				filter: `userName like "bjensen"`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "value missing",
+			args: args{
+				filter: `userName eq`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unclosed parenthesis",
+			args: args{
+				filter: `(userName eq "bjensen"`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unterminated string",
+			args: args{
+				filter: `userName eq "bjensen`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "maximum depth",
+			args: args{
+				filter: strings.Repeat("(", maxFilterDepth) + `userName eq "bjensen"` + strings.Repeat(")", maxFilterDepth),
+			},
+			want: &attributeExpression{Path: "username", Operator: operatorEqual, Value: "bjensen"},
+		},
+		{
+			name: "too deeply nested",
+			args: args{
+				filter: strings.Repeat("not(", maxFilterDepth+1) + `userName eq "bjensen"` + strings.Repeat(")", maxFilterDepth+1),
+			},
+			wantErr: true,
+		},
+		{
+			name: "too many terms",
+			args: args{
+				filter: strings.Repeat(`userName eq "bjensen" or `, maxFilterTerms) + `userName eq "bjensen"`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFilter(tt.args.filter, tt.args.schema)
+			if tt.wantErr {
+				assert.Error(t, err)
+				status, scimType := errorStatus(err)
+				assert.Equal(t, 400, status)
+				assert.Equal(t, scimTypeInvalidFilter, scimType)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_matchesFilter(t *testing.T) {
+	resource := map[string]interface{}{
+		"userName": "bjensen",
+		"active":   true,
+		"name": map[string]interface{}{
+			"familyName": "Jensen",
+		},
+		"emails": []interface{}{
+			map[string]interface{}{"value": "bjensen@example.com", "type": "work"},
+		},
+	}
+	tests := []struct {
+		name   string
+		filter string
+		want   bool
+	}{
+		{
+			name:   "equal case-insensitive",
+			filter: `USERNAME eq "BJensen"`,
+			want:   true,
+		},
+		{
+			name:   "sub attribute",
+			filter: `name.familyName sw "jen"`,
+			want:   true,
+		},
+		{
+			name:   "boolean",
+			filter: `active eq false`,
+			want:   false,
+		},
+		{
+			name:   "value path",
+			filter: `emails[type eq "work" and value ew "example.com"]`,
+			want:   true,
+		},
+		{
+			name:   "not present",
+			filter: `not (title pr)`,
+			want:   true,
+		},
+		{
+			name:   "or",
+			filter: `userName eq "other" or active eq true`,
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := parseFilter(tt.filter, "")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, matchesFilter(expression, resource))
+		})
+	}
+}
+
+func Test_errorStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantStatus   int
+		wantScimType string
+	}{
+		{
+			name:         "invalid argument",
+			err:          caos_errs.ThrowInvalidArgument(nil, "id", "invalid"),
+			wantStatus:   400,
+			wantScimType: scimTypeInvalidValue,
+		},
+		{
+			name:         "already exists",
+			err:          caos_errs.ThrowAlreadyExists(nil, "id", "exists"),
+			wantStatus:   409,
+			wantScimType: scimTypeUniqueness,
+		},
+		{
+			name:       "not found",
+			err:        caos_errs.ThrowNotFound(nil, "id", "not found"),
+			wantStatus: 404,
+		},
+		{
+			name:       "permission denied",
+			err:        caos_errs.ThrowPermissionDenied(nil, "id", "denied"),
+			wantStatus: 403,
+		},
+		{
+			name: "status of scim error",
+			err: &scimTypeError{
+				status: 412,
+				parent: caos_errs.ThrowPreconditionFailed(nil, "id", "version mismatch"),
+			},
+			wantStatus: 412,
+		},
+		{
+			name:       "internal",
+			err:        caos_errs.ThrowInternal(nil, "id", "internal"),
+			wantStatus: 500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, scimType := errorStatus(tt.err)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantScimType, scimType)
+		})
+	}
+}
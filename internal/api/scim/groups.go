@@ -0,0 +1,517 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
+	"github.com/zitadel/zitadel/internal/query"
+)
+
+// groupIDSeparator separates the project and the key of the role in the id of a group
+const groupIDSeparator = ":"
+
+var groupSortingColumns = map[string]query.Column{
+	"displayname":       query.ProjectRoleColumnDisplayName,
+	"meta.created":      query.ProjectRoleColumnCreationDate,
+	"meta.lastmodified": query.ProjectRoleColumnChangeDate,
+}
+
+// group is a project role of the organisation with its granted users
+type group struct {
+	role    *query.ProjectRole
+	members []*query.UserGrant
+}
+
+func groupID(projectID, key string) string {
+	return projectID + groupIDSeparator + key
+}
+
+func (h *Handler) listGroups(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := parseListRequest(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	queries, err := groupListQueries(ctx, req.filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	roles, err := h.queries.SearchProjectRoles(ctx, true, &query.ProjectRoleSearchQueries{
+		SearchRequest: req.searchRequest(groupSortingColumns),
+		Queries:       queries,
+	}, false)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	withMembers := !strings.Contains(strings.ToLower(r.URL.Query().Get("excludedAttributes")), "members")
+	resources := make([]*Group, len(roles.ProjectRoles))
+	for i, role := range roles.ProjectRoles {
+		g := &group{role: role}
+		if withMembers {
+			if g.members, err = h.groupMembers(ctx, role); err != nil {
+				writeError(w, r, err)
+				return
+			}
+		}
+		resources[i] = h.groupToResource(ctx, g)
+	}
+	writeJSON(w, &ListResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: roles.Count,
+		StartIndex:   req.startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, http.StatusOK)
+}
+
+// groupListQueries restricts the list to the roles of the organisation and applies the filter,
+// which only supports conjunctions of the display name
+func groupListQueries(ctx context.Context, filter string) ([]query.SearchQuery, error) {
+	ownerQuery, err := query.NewProjectRoleResourceOwnerSearchQuery(authz.GetCtxData(ctx).OrgID)
+	if err != nil {
+		return nil, err
+	}
+	queries := []query.SearchQuery{ownerQuery}
+	if filter == "" {
+		return queries, nil
+	}
+	expression, err := parseFilter(filter, schemaGroup)
+	if err != nil {
+		return nil, err
+	}
+	return groupFilterQueries(expression, queries)
+}
+
+func groupFilterQueries(expression filterExpression, queries []query.SearchQuery) ([]query.SearchQuery, error) {
+	switch e := expression.(type) {
+	case *logicalExpression:
+		if e.Operator != operatorAnd {
+			return nil, invalidFilterError("SCIM-Gl2ms", "only and is supported for groups")
+		}
+		queries, err := groupFilterQueries(e.Left, queries)
+		if err != nil {
+			return nil, err
+		}
+		return groupFilterQueries(e.Right, queries)
+	case *attributeExpression:
+		if e.Path != "displayname" {
+			return nil, invalidFilterError("SCIM-Ga8wn", "unsupported attribute "+e.Path)
+		}
+		value, ok := e.Value.(string)
+		if !ok {
+			return nil, invalidFilterError("SCIM-Gv3ld", "value of displayName must be a string")
+		}
+		var comparison query.TextComparison
+		switch e.Operator {
+		case operatorEqual:
+			comparison = query.TextEqualsIgnoreCase
+		case operatorContains:
+			comparison = query.TextContainsIgnoreCase
+		case operatorStartsWith:
+			comparison = query.TextStartsWithIgnoreCase
+		case operatorEndsWith:
+			comparison = query.TextEndsWithIgnoreCase
+		default:
+			return nil, invalidFilterError("SCIM-Go2ks", "unsupported operator "+e.Operator+" for displayName")
+		}
+		q, err := query.NewProjectRoleDisplayNameSearchQuery(comparison, value)
+		if err != nil {
+			return nil, err
+		}
+		return append(queries, q), nil
+	}
+	return nil, invalidFilterError("SCIM-Ge4lw", "unsupported expression")
+}
+
+func (h *Handler) getGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	g, err := h.groupByID(ctx, mux.Vars(r)[paramID])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resource := h.groupToResource(ctx, g)
+	if notModified(w, r, resource.Meta.Version) {
+		return
+	}
+	writeResource(w, resource, resource.Meta.Version, http.StatusOK)
+}
+
+func (h *Handler) createGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := checkPermission(r, "project.role.write"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resource := new(Group)
+	if err := decodeResource(r, resource); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if resource.Role == nil || resource.Role.ProjectID == "" {
+		writeError(w, r, &scimTypeError{
+			scimType: scimTypeInvalidValue,
+			parent:   caos_errs.ThrowInvalidArgument(nil, "SCIM-Gp9sl", "projectId of "+schemaGroupExtension+" missing"),
+		})
+		return
+	}
+	key := resource.Role.Key
+	if key == "" {
+		key = resource.DisplayName
+	}
+	orgID := authz.GetCtxData(ctx).OrgID
+	role, err := h.commands.AddProjectRole(ctx, &domain.ProjectRole{
+		ObjectRoot:  models.ObjectRoot{AggregateID: resource.Role.ProjectID},
+		Key:         key,
+		DisplayName: resource.DisplayName,
+		Group:       resource.Role.Group,
+	}, orgID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	g := &group{role: &query.ProjectRole{ProjectID: role.AggregateID, Key: role.Key, ResourceOwner: orgID}}
+	if err = h.setGroupMembers(ctx, g, resource.Members); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	h.writeGroup(w, r, groupID(role.AggregateID, role.Key), http.StatusCreated)
+}
+
+func (h *Handler) replaceGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	current, err := h.groupByID(ctx, mux.Vars(r)[paramID])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err = checkIfMatch(r, groupVersion(current)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resource := new(Group)
+	if err = decodeResource(r, resource); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err = h.updateGroup(r, current, resource); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	h.writeGroup(w, r, groupID(current.role.ProjectID, current.role.Key), http.StatusOK)
+}
+
+func (h *Handler) patchGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	current, err := h.groupByID(ctx, mux.Vars(r)[paramID])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err = checkIfMatch(r, groupVersion(current)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	patch := new(PatchRequest)
+	if err = decodeResource(r, patch); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resource := new(Group)
+	if err = applyPatch(patch, schemaGroup, h.groupToResource(ctx, current), resource); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err = h.updateGroup(r, current, resource); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	h.writeGroup(w, r, groupID(current.role.ProjectID, current.role.Key), http.StatusOK)
+}
+
+func (h *Handler) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	current, err := h.groupByID(ctx, mux.Vars(r)[paramID])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err = checkIfMatch(r, groupVersion(current)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	projectID, key := current.role.ProjectID, current.role.Key
+	projectQuery, err := query.NewUserGrantProjectIDSearchQuery(projectID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	rolesQuery, err := query.NewUserGrantRoleQuery(key)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	userGrants, err := h.queries.UserGrants(ctx, &query.UserGrantsQueries{
+		Queries: []query.SearchQuery{projectQuery, rolesQuery},
+	}, false, false)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	projectGrants, err := h.queries.SearchProjectGrantsByProjectIDAndRoleKey(ctx, projectID, key, false)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	projectGrantIDs := make([]string, len(projectGrants.ProjectGrants))
+	for i, grant := range projectGrants.ProjectGrants {
+		projectGrantIDs[i] = grant.GrantID
+	}
+	_, err = h.commands.RemoveProjectRole(ctx, projectID, key, current.role.ResourceOwner, projectGrantIDs, userGrantsToIDs(userGrants.UserGrants)...)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) writeGroup(w http.ResponseWriter, r *http.Request, id string, status int) {
+	ctx := r.Context()
+	g, err := h.groupByID(ctx, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resource := h.groupToResource(ctx, g)
+	writeResource(w, resource, resource.Meta.Version, status)
+}
+
+// updateGroup changes the display name and group of the role (if changed) and sets the members
+func (h *Handler) updateGroup(r *http.Request, current *group, resource *Group) error {
+	ctx := r.Context()
+	roleGroup := current.role.Group
+	if resource.Role != nil {
+		roleGroup = resource.Role.Group
+	}
+	if resource.DisplayName != current.role.DisplayName || roleGroup != current.role.Group {
+		if err := checkPermission(r, "project.role.write"); err != nil {
+			return err
+		}
+		_, err := h.commands.ChangeProjectRole(ctx, &domain.ProjectRole{
+			ObjectRoot:  models.ObjectRoot{AggregateID: current.role.ProjectID},
+			Key:         current.role.Key,
+			DisplayName: resource.DisplayName,
+			Group:       roleGroup,
+		}, current.role.ResourceOwner)
+		if err != nil {
+			return err
+		}
+	}
+	return h.setGroupMembers(ctx, current, resource.Members)
+}
+
+// setGroupMembers grants the role to the added members and revokes it from the removed ones,
+// a user grant without any remaining role is removed
+func (h *Handler) setGroupMembers(ctx context.Context, g *group, members []*Member) error {
+	desired := make(map[string]bool, len(members))
+	for _, member := range members {
+		desired[member.Value] = true
+	}
+	existing := make(map[string]bool, len(g.members))
+	for _, grant := range g.members {
+		existing[grant.UserID] = true
+		if desired[grant.UserID] {
+			continue
+		}
+		roles := removeRole(grant.Roles, g.role.Key)
+		if len(roles) == 0 {
+			if _, err := h.commands.RemoveUserGrant(ctx, grant.ID, grant.ResourceOwner); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := h.changeUserGrantRoles(ctx, grant, roles); err != nil {
+			return err
+		}
+	}
+	for _, member := range members {
+		if existing[member.Value] {
+			continue
+		}
+		if err := h.addGroupMember(ctx, g.role, member.Value); err != nil {
+			return err
+		}
+		existing[member.Value] = true
+	}
+	return nil
+}
+
+// addGroupMember adds the role to the existing grant of the user on the project or creates a new one
+func (h *Handler) addGroupMember(ctx context.Context, role *query.ProjectRole, userID string) error {
+	userQuery, err := query.NewUserGrantUserIDSearchQuery(userID)
+	if err != nil {
+		return err
+	}
+	grants, err := h.projectUserGrants(ctx, role, userQuery)
+	if err != nil {
+		return err
+	}
+	if len(grants) > 0 {
+		return h.changeUserGrantRoles(ctx, grants[0], append(grants[0].Roles, role.Key))
+	}
+	_, err = h.commands.AddUserGrant(ctx, &domain.UserGrant{
+		UserID:    userID,
+		ProjectID: role.ProjectID,
+		RoleKeys:  []string{role.Key},
+	}, role.ResourceOwner)
+	return err
+}
+
+func (h *Handler) changeUserGrantRoles(ctx context.Context, grant *query.UserGrant, roles []string) error {
+	_, err := h.commands.ChangeUserGrant(ctx, &domain.UserGrant{
+		ObjectRoot: models.ObjectRoot{AggregateID: grant.ID, ResourceOwner: grant.ResourceOwner},
+		RoleKeys:   roles,
+	}, grant.ResourceOwner)
+	return err
+}
+
+func removeRole(roles []string, key string) []string {
+	remaining := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role != key {
+			remaining = append(remaining, role)
+		}
+	}
+	return remaining
+}
+
+// groupByID returns the role of the organisation identified by the id and its members
+func (h *Handler) groupByID(ctx context.Context, id string) (*group, error) {
+	projectID, key, ok := cutGroupID(id)
+	if !ok {
+		return nil, caos_errs.ThrowNotFound(nil, "SCIM-Gn3ls", "Errors.Project.Role.NotExisting")
+	}
+	projectQuery, err := query.NewProjectRoleProjectIDSearchQuery(projectID)
+	if err != nil {
+		return nil, err
+	}
+	keyQuery, err := query.NewProjectRoleKeySearchQuery(query.TextEquals, key)
+	if err != nil {
+		return nil, err
+	}
+	ownerQuery, err := query.NewProjectRoleResourceOwnerSearchQuery(authz.GetCtxData(ctx).OrgID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := h.queries.SearchProjectRoles(ctx, true, &query.ProjectRoleSearchQueries{
+		Queries: []query.SearchQuery{projectQuery, keyQuery, ownerQuery},
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles.ProjectRoles) != 1 {
+		return nil, caos_errs.ThrowNotFound(nil, "SCIM-Gn4lt", "Errors.Project.Role.NotExisting")
+	}
+	g := &group{role: roles.ProjectRoles[0]}
+	g.members, err = h.groupMembers(ctx, g.role)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func cutGroupID(id string) (projectID, key string, ok bool) {
+	i := strings.Index(id, groupIDSeparator)
+	if i <= 0 || i == len(id)-1 {
+		return "", "", false
+	}
+	return id[:i], id[i+1:], true
+}
+
+// groupMembers returns the grants of the organisation containing the role
+func (h *Handler) groupMembers(ctx context.Context, role *query.ProjectRole) ([]*query.UserGrant, error) {
+	roleQuery, err := query.NewUserGrantRoleQuery(role.Key)
+	if err != nil {
+		return nil, err
+	}
+	return h.projectUserGrants(ctx, role, roleQuery)
+}
+
+// projectUserGrants returns the grants of the organisation on the project of the role,
+// grants of granted projects are ignored
+func (h *Handler) projectUserGrants(ctx context.Context, role *query.ProjectRole, queries ...query.SearchQuery) ([]*query.UserGrant, error) {
+	projectQuery, err := query.NewUserGrantProjectIDSearchQuery(role.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	ownerQuery, err := query.NewUserGrantResourceOwnerSearchQuery(role.ResourceOwner)
+	if err != nil {
+		return nil, err
+	}
+	grantQuery, err := query.NewUserGrantGrantIDSearchQuery("")
+	if err != nil {
+		return nil, err
+	}
+	grants, err := h.queries.UserGrants(ctx, &query.UserGrantsQueries{
+		Queries: append([]query.SearchQuery{projectQuery, ownerQuery, grantQuery}, queries...),
+	}, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return grants.UserGrants, nil
+}
+
+// groupVersion changes with the role and its grants
+func groupVersion(g *group) string {
+	sequence := g.role.Sequence
+	for _, grant := range g.members {
+		if grant.Sequence > sequence {
+			sequence = grant.Sequence
+		}
+	}
+	return version(sequence)
+}
+
+func (h *Handler) groupToResource(ctx context.Context, g *group) *Group {
+	lastModified := g.role.ChangeDate
+	members := make([]*Member, len(g.members))
+	for i, grant := range g.members {
+		members[i] = &Member{
+			Value:   grant.UserID,
+			Ref:     h.location(ctx, "Users", grant.UserID),
+			Display: grant.DisplayName,
+		}
+		if grant.ChangeDate.After(lastModified) {
+			lastModified = grant.ChangeDate
+		}
+	}
+	id := groupID(g.role.ProjectID, g.role.Key)
+	return &Group{
+		Schemas:     []string{schemaGroup, schemaGroupExtension},
+		ID:          id,
+		DisplayName: g.role.DisplayName,
+		Members:     members,
+		Role: &GroupExtension{
+			ProjectID: g.role.ProjectID,
+			Key:       g.role.Key,
+			Group:     g.role.Group,
+		},
+		Meta: &Meta{
+			ResourceType: resourceTypeGroup,
+			Created:      g.role.CreationDate,
+			LastModified: lastModified,
+			Location:     h.location(ctx, "Groups", id),
+			Version:      groupVersion(g),
+		},
+	}
+}
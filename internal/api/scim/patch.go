@@ -0,0 +1,236 @@
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+const (
+	patchOpAdd     = "add"
+	patchOpReplace = "replace"
+	patchOpRemove  = "remove"
+)
+
+// applyPatch applies the operations of the request (RFC 7644, section 3.5.2) to the current representation of a resource
+// and unmarshals the result into patched, which will be used to replace the resource
+func applyPatch(req *PatchRequest, schema string, current, patched interface{}) error {
+	if !containsSchema(req.Schemas, schemaPatchOp) {
+		return invalidPatchError("SCIM-Ps8wm", scimTypeInvalidSyntax, "schema "+schemaPatchOp+" missing")
+	}
+	b, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	resource := make(map[string]interface{})
+	if err = json.Unmarshal(b, &resource); err != nil {
+		return err
+	}
+	for _, operation := range req.Operations {
+		var value interface{}
+		if len(operation.Value) > 0 {
+			if err = json.Unmarshal(operation.Value, &value); err != nil {
+				return invalidPatchError("SCIM-Vq2mf", scimTypeInvalidSyntax, "invalid value")
+			}
+		}
+		if err = applyOperation(resource, strings.ToLower(operation.Op), operation.Path, schema, value); err != nil {
+			return err
+		}
+	}
+	if b, err = json.Marshal(resource); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(b, patched); err != nil {
+		return invalidPatchError("SCIM-Wn3ls", scimTypeInvalidValue, "invalid value")
+	}
+	return nil
+}
+
+func applyOperation(resource map[string]interface{}, op, path, schema string, value interface{}) error {
+	if op != patchOpAdd && op != patchOpReplace && op != patchOpRemove {
+		return invalidPatchError("SCIM-Oe9wl", scimTypeInvalidSyntax, "unknown op "+op)
+	}
+	if path == "" {
+		if op == patchOpRemove {
+			return invalidPatchError("SCIM-Rm2ow", "noTarget", "path missing")
+		}
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return invalidPatchError("SCIM-Ao8vd", scimTypeInvalidValue, "value must be an object")
+		}
+		for attributePath, attributeValue := range values {
+			if err := applyOperation(resource, op, attributePath, schema, attributeValue); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	attribute, filter, subAttribute, err := parsePatchPath(path, schema)
+	if err != nil {
+		return err
+	}
+	if filter != nil {
+		return applyFilteredOperation(resource, op, attribute, filter, subAttribute, value)
+	}
+	if subAttribute != "" {
+		subResource, ok := resource[mapKey(resource, attribute)].(map[string]interface{})
+		if !ok {
+			if op == patchOpRemove {
+				return nil
+			}
+			subResource = make(map[string]interface{})
+			resource[mapKey(resource, attribute)] = subResource
+		}
+		return applyOperation(subResource, op, subAttribute, "", value)
+	}
+	key := mapKey(resource, attribute)
+	switch op {
+	case patchOpRemove:
+		existing, isList := resource[key].([]interface{})
+		removed, isRemovedList := value.([]interface{})
+		if !isList || !isRemovedList {
+			delete(resource, key)
+			return nil
+		}
+		// e.g. `{"op": "remove", "path": "members", "value": [{"value": "id"}]}`
+		resource[key] = filterValues(existing, func(v interface{}) bool {
+			return !containsValue(removed, v)
+		})
+	case patchOpAdd:
+		existing, isList := resource[key].([]interface{})
+		if !isList {
+			resource[key] = value
+			return nil
+		}
+		if values, ok := value.([]interface{}); ok {
+			resource[key] = append(existing, values...)
+			return nil
+		}
+		resource[key] = append(existing, value)
+	case patchOpReplace:
+		resource[key] = value
+	}
+	return nil
+}
+
+// applyFilteredOperation applies the operation to the values of a multi-valued attribute matching the filter.
+// If no value matches on an add or replace of a simple equality filter, the value is added,
+// e.g. `emails[type eq "work"].value`
+func applyFilteredOperation(resource map[string]interface{}, op, attribute string, filter filterExpression, subAttribute string, value interface{}) error {
+	key := mapKey(resource, attribute)
+	existing, _ := resource[key].([]interface{})
+	matched := false
+	values := make([]interface{}, 0, len(existing))
+	for _, v := range existing {
+		subResource, ok := v.(map[string]interface{})
+		if !ok || !matchesFilter(filter, subResource) {
+			values = append(values, v)
+			continue
+		}
+		matched = true
+		switch {
+		case op == patchOpRemove && subAttribute == "":
+			continue
+		case subAttribute == "":
+			if replacement, ok := value.(map[string]interface{}); ok {
+				v = replacement
+			}
+		default:
+			if err := applyOperation(subResource, op, subAttribute, "", value); err != nil {
+				return err
+			}
+		}
+		values = append(values, v)
+	}
+	if !matched && op != patchOpRemove {
+		equality, ok := filter.(*attributeExpression)
+		if !ok || equality.Operator != operatorEqual {
+			return invalidPatchError("SCIM-Nt4la", "noTarget", "no value matches the filter")
+		}
+		added := map[string]interface{}{equality.Path: equality.Value}
+		if subAttribute == "" {
+			return invalidPatchError("SCIM-Nt5lb", "noTarget", "no value matches the filter")
+		}
+		if err := applyOperation(added, patchOpReplace, subAttribute, "", value); err != nil {
+			return err
+		}
+		values = append(values, added)
+	}
+	resource[key] = values
+	return nil
+}
+
+// parsePatchPath splits a path like `emails[type eq "work"].value` into the attribute, the filter and the sub attribute
+func parsePatchPath(path, schema string) (attribute string, filter filterExpression, subAttribute string, err error) {
+	path = strings.TrimPrefix(strings.ToLower(path), strings.ToLower(schema)+":")
+	open := strings.Index(path, "[")
+	if open < 0 {
+		attribute, subAttribute, _ = cutPath(path)
+		return attribute, nil, subAttribute, nil
+	}
+	closing := strings.LastIndex(path, "]")
+	if closing < open {
+		return "", nil, "", invalidPatchError("SCIM-Bq7an", scimTypeInvalidPath, "invalid path "+path)
+	}
+	filter, err = parseFilter(path[open+1:closing], "")
+	if err != nil {
+		return "", nil, "", invalidPatchError("SCIM-Fp3ml", scimTypeInvalidPath, "invalid filter in path "+path)
+	}
+	return path[:open], filter, strings.TrimPrefix(path[closing+1:], "."), nil
+}
+
+// mapKey returns the existing key of the resource matching the attribute case-insensitive
+func mapKey(resource map[string]interface{}, attribute string) string {
+	for key := range resource {
+		if strings.EqualFold(key, attribute) {
+			return key
+		}
+	}
+	return attribute
+}
+
+func filterValues(values []interface{}, keep func(interface{}) bool) []interface{} {
+	kept := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if keep(value) {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
+
+// containsValue compares the values of complex attributes (e.g. members) by their value attribute
+func containsValue(values []interface{}, value interface{}) bool {
+	compared := attributeValue(asMap(value), "value")
+	if compared == nil {
+		return false
+	}
+	for _, v := range values {
+		if attributeValue(asMap(v), "value") == compared {
+			return true
+		}
+	}
+	return false
+}
+
+func asMap(value interface{}) map[string]interface{} {
+	m, _ := value.(map[string]interface{})
+	return m
+}
+
+func containsSchema(schemas []string, schema string) bool {
+	for _, s := range schemas {
+		if strings.EqualFold(s, schema) {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidPatchError(id, scimType, detail string) error {
+	return &scimTypeError{
+		scimType: scimType,
+		parent:   caos_errs.ThrowInvalidArgument(nil, id, "invalid patch: "+detail),
+	}
+}
@@ -0,0 +1,211 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_applyPatch(t *testing.T) {
+	current := &User{
+		Schemas:  []string{schemaUser},
+		ID:       "id",
+		UserName: "bjensen",
+		Name: &Name{
+			FamilyName: "Jensen",
+			GivenName:  "Barbara",
+		},
+		Emails: []*MultiValued{
+			{Value: "bjensen@example.com", Type: "work", Primary: true},
+		},
+	}
+	tests := []struct {
+		name    string
+		req     *PatchRequest
+		want    *User
+		wantErr bool
+	}{
+		{
+			name: "schema missing",
+			req: &PatchRequest{
+				Operations: []*PatchOperation{
+					{Op: "replace", Path: "userName", Value: []byte(`"babs"`)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown op",
+			req: &PatchRequest{
+				Schemas: []string{schemaPatchOp},
+				Operations: []*PatchOperation{
+					{Op: "move", Path: "userName", Value: []byte(`"babs"`)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "replace attribute with schema",
+			req: &PatchRequest{
+				Schemas: []string{schemaPatchOp},
+				Operations: []*PatchOperation{
+					{Op: "Replace", Path: schemaUser + ":userName", Value: []byte(`"babs"`)},
+				},
+			},
+			want: &User{
+				Schemas:  []string{schemaUser},
+				ID:       "id",
+				UserName: "babs",
+				Name:     &Name{FamilyName: "Jensen", GivenName: "Barbara"},
+				Emails:   []*MultiValued{{Value: "bjensen@example.com", Type: "work", Primary: true}},
+			},
+		},
+		{
+			name: "replace without path",
+			req: &PatchRequest{
+				Schemas: []string{schemaPatchOp},
+				Operations: []*PatchOperation{
+					{Op: "replace", Value: []byte(`{"active": false, "name.givenName": "Babs"}`)},
+				},
+			},
+			want: &User{
+				Schemas:  []string{schemaUser},
+				ID:       "id",
+				UserName: "bjensen",
+				Name:     &Name{FamilyName: "Jensen", GivenName: "Babs"},
+				Active:   boolPtr(false),
+				Emails:   []*MultiValued{{Value: "bjensen@example.com", Type: "work", Primary: true}},
+			},
+		},
+		{
+			name: "replace filtered value",
+			req: &PatchRequest{
+				Schemas: []string{schemaPatchOp},
+				Operations: []*PatchOperation{
+					{Op: "replace", Path: `emails[type eq "work"].value`, Value: []byte(`"babs@example.com"`)},
+				},
+			},
+			want: &User{
+				Schemas:  []string{schemaUser},
+				ID:       "id",
+				UserName: "bjensen",
+				Name:     &Name{FamilyName: "Jensen", GivenName: "Barbara"},
+				Emails:   []*MultiValued{{Value: "babs@example.com", Type: "work", Primary: true}},
+			},
+		},
+		{
+			name: "add value of missing filter",
+			req: &PatchRequest{
+				Schemas: []string{schemaPatchOp},
+				Operations: []*PatchOperation{
+					{Op: "add", Path: `phoneNumbers[type eq "mobile"].value`, Value: []byte(`"+41791234567"`)},
+				},
+			},
+			want: &User{
+				Schemas:      []string{schemaUser},
+				ID:           "id",
+				UserName:     "bjensen",
+				Name:         &Name{FamilyName: "Jensen", GivenName: "Barbara"},
+				Emails:       []*MultiValued{{Value: "bjensen@example.com", Type: "work", Primary: true}},
+				PhoneNumbers: []*MultiValued{{Value: "+41791234567", Type: "mobile"}},
+			},
+		},
+		{
+			name: "remove attribute",
+			req: &PatchRequest{
+				Schemas: []string{schemaPatchOp},
+				Operations: []*PatchOperation{
+					{Op: "remove", Path: "name.givenName"},
+					{Op: "remove", Path: `emails[type eq "work"]`},
+				},
+			},
+			want: &User{
+				Schemas:  []string{schemaUser},
+				ID:       "id",
+				UserName: "bjensen",
+				Name:     &Name{FamilyName: "Jensen"},
+				Emails:   []*MultiValued{},
+			},
+		},
+		{
+			name: "remove without path",
+			req: &PatchRequest{
+				Schemas: []string{schemaPatchOp},
+				Operations: []*PatchOperation{
+					{Op: "remove"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(User)
+			err := applyPatch(tt.req, schemaUser, current, got)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_applyPatch_members(t *testing.T) {
+	current := &Group{
+		Schemas:     []string{schemaGroup},
+		ID:          "project:key",
+		DisplayName: "Key",
+		Members: []*Member{
+			{Value: "user1"},
+			{Value: "user2"},
+		},
+	}
+	tests := []struct {
+		name string
+		ops  []*PatchOperation
+		want []*Member
+	}{
+		{
+			name: "add members",
+			ops: []*PatchOperation{
+				{Op: "add", Path: "members", Value: []byte(`[{"value": "user3"}]`)},
+			},
+			want: []*Member{{Value: "user1"}, {Value: "user2"}, {Value: "user3"}},
+		},
+		{
+			name: "remove members by value",
+			ops: []*PatchOperation{
+				{Op: "remove", Path: "members", Value: []byte(`[{"value": "user1"}]`)},
+			},
+			want: []*Member{{Value: "user2"}},
+		},
+		{
+			name: "remove members by filter",
+			ops: []*PatchOperation{
+				{Op: "remove", Path: `members[value eq "user2"]`},
+			},
+			want: []*Member{{Value: "user1"}},
+		},
+		{
+			name: "replace members",
+			ops: []*PatchOperation{
+				{Op: "replace", Path: "members", Value: []byte(`[{"value": "user3"}]`)},
+			},
+			want: []*Member{{Value: "user3"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(Group)
+			err := applyPatch(&PatchRequest{Schemas: []string{schemaPatchOp}, Operations: tt.ops}, schemaGroup, current, got)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Members)
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,123 @@
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+const (
+	schemaUser           = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup          = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaGroupExtension = "urn:zitadel:params:scim:schemas:extension:2.0:Group"
+	schemaListResponse   = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp        = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError          = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	resourceTypeUser  = "User"
+	resourceTypeGroup = "Group"
+)
+
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+	Version      string    `json:"version"`
+}
+
+// User is the representation of a human user
+type User struct {
+	Schemas           []string       `json:"schemas"`
+	ID                string         `json:"id,omitempty"`
+	UserName          string         `json:"userName"`
+	Name              *Name          `json:"name,omitempty"`
+	DisplayName       string         `json:"displayName,omitempty"`
+	NickName          string         `json:"nickName,omitempty"`
+	PreferredLanguage string         `json:"preferredLanguage,omitempty"`
+	Active            *bool          `json:"active,omitempty"`
+	Password          string         `json:"password,omitempty"`
+	Emails            []*MultiValued `json:"emails,omitempty"`
+	PhoneNumbers      []*MultiValued `json:"phoneNumbers,omitempty"`
+	Meta              *Meta          `json:"meta,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+}
+
+type MultiValued struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// primaryValue returns the value marked as primary, respectively the first one
+func primaryValue(values []*MultiValued) string {
+	for _, value := range values {
+		if value.Primary {
+			return value.Value
+		}
+	}
+	if len(values) > 0 {
+		return values[0].Value
+	}
+	return ""
+}
+
+// Group is the representation of a project role
+type Group struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	DisplayName string          `json:"displayName"`
+	Members     []*Member       `json:"members,omitempty"`
+	Role        *GroupExtension `json:"urn:zitadel:params:scim:schemas:extension:2.0:Group,omitempty"`
+	Meta        *Meta           `json:"meta,omitempty"`
+}
+
+type Member struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+// GroupExtension identifies the project role of a group.
+// If no key is provided on creation, the display name is used as key.
+type GroupExtension struct {
+	ProjectID string `json:"projectId"`
+	Key       string `json:"key,omitempty"`
+	Group     string `json:"group,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults uint64      `json:"totalResults"`
+	StartIndex   uint64      `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+type PatchRequest struct {
+	Schemas    []string          `json:"schemas"`
+	Operations []*PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+	Status   string   `json:"status"`
+}
+
+// version returns the weak ETag of a resource
+func version(sequence uint64) string {
+	return `W/"` + strconv.FormatUint(sequence, 10) + `"`
+}
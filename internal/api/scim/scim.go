@@ -0,0 +1,292 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	http_util "github.com/zitadel/zitadel/internal/api/http"
+	"github.com/zitadel/zitadel/internal/command"
+	"github.com/zitadel/zitadel/internal/crypto"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/query"
+)
+
+const (
+	HandlerPrefix = "/scim/v2"
+
+	paramOrgID = "orgID"
+	paramID    = "id"
+
+	usersPath  = "/{" + paramOrgID + "}/Users"
+	groupsPath = "/{" + paramOrgID + "}/Groups"
+	idPath     = "/{" + paramID + "}"
+
+	contentType = "application/scim+json"
+
+	defaultCount = 100
+	maxCount     = 1000
+)
+
+// Handler serves the SCIM 2.0 protocol (RFC 7644) for the users and groups of an organisation:
+// users are mapped to human users and groups to the roles of the projects of the organisation,
+// the members of a group are the users granted the role
+type Handler struct {
+	commands       *command.Commands
+	queries        *query.Queries
+	verifier       *authz.TokenVerifier
+	authConfig     authz.Config
+	userCodeAlg    crypto.EncryptionAlgorithm
+	externalSecure bool
+}
+
+func NewHandler(
+	commands *command.Commands,
+	queries *query.Queries,
+	verifier *authz.TokenVerifier,
+	authConfig authz.Config,
+	userCodeAlg crypto.EncryptionAlgorithm,
+	externalSecure bool,
+	callDurationInterceptor, instanceInterceptor, accessInterceptor func(handler http.Handler) http.Handler,
+) http.Handler {
+	h := &Handler{
+		commands:       commands,
+		queries:        queries,
+		verifier:       verifier,
+		authConfig:     authConfig,
+		userCodeAlg:    userCodeAlg,
+		externalSecure: externalSecure,
+	}
+
+	router := mux.NewRouter()
+	router.Use(callDurationInterceptor, instanceInterceptor, accessInterceptor)
+	router.HandleFunc(usersPath, h.authorize("user.read", h.listUsers)).Methods(http.MethodGet)
+	router.HandleFunc(usersPath, h.authorize("user.write", h.createUser)).Methods(http.MethodPost)
+	router.HandleFunc(usersPath+idPath, h.authorize("user.read", h.getUser)).Methods(http.MethodGet)
+	router.HandleFunc(usersPath+idPath, h.authorize("user.write", h.replaceUser)).Methods(http.MethodPut)
+	router.HandleFunc(usersPath+idPath, h.authorize("user.write", h.patchUser)).Methods(http.MethodPatch)
+	router.HandleFunc(usersPath+idPath, h.authorize("user.delete", h.deleteUser)).Methods(http.MethodDelete)
+	router.HandleFunc(groupsPath, h.authorize("user.grant.read", h.listGroups)).Methods(http.MethodGet)
+	router.HandleFunc(groupsPath, h.authorize("user.grant.write", h.createGroup)).Methods(http.MethodPost)
+	router.HandleFunc(groupsPath+idPath, h.authorize("user.grant.read", h.getGroup)).Methods(http.MethodGet)
+	router.HandleFunc(groupsPath+idPath, h.authorize("user.grant.write", h.replaceGroup)).Methods(http.MethodPut)
+	router.HandleFunc(groupsPath+idPath, h.authorize("user.grant.write", h.patchGroup)).Methods(http.MethodPatch)
+	router.HandleFunc(groupsPath+idPath, h.authorize("project.role.delete", h.deleteGroup)).Methods(http.MethodDelete)
+	return http_util.CopyHeadersToContext(router)
+}
+
+// authorize verifies the bearer token (e.g. of a machine user) and checks the permission in the organisation of the path
+func (h *Handler) authorize(permission string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		token := http_util.GetAuthorization(r)
+		if token == "" {
+			writeError(w, r, caos_errs.ThrowUnauthenticated(nil, "SCIM-Ak3ls", "auth header missing"))
+			return
+		}
+		ctxSetter, err := authz.CheckUserAuthorization(ctx, nil, token, mux.Vars(r)[paramOrgID], "", h.verifier, h.authConfig, authz.Option{Permission: permission}, r.RequestURI)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		next(w, r.WithContext(ctxSetter(ctx)))
+	}
+}
+
+// location returns the URL of a resource of the organisation
+func (h *Handler) location(ctx context.Context, resourceType, id string) string {
+	return http_util.BuildOrigin(authz.GetInstance(ctx).RequestedHost(), h.externalSecure) + HandlerPrefix + "/" + authz.GetCtxData(ctx).OrgID + "/" + resourceType + "/" + id
+}
+
+// checkPermission checks an additional permission of the authorized user in the organisation
+func checkPermission(r *http.Request, permission string) error {
+	if !authz.ExistsPerm(authz.GetAllPermissionsFromCtx(r.Context()), permission) {
+		return caos_errs.ThrowPermissionDenied(nil, "SCIM-Pe8sk", "Errors.PermissionDenied")
+	}
+	return nil
+}
+
+// listRequest contains the query parameters of a list request
+type listRequest struct {
+	filter     string
+	startIndex uint64
+	count      uint64
+	sortBy     string
+	ascending  bool
+}
+
+// parseListRequest parses the (1-based) pagination, which is limited to a maximum of entries per page
+func parseListRequest(r *http.Request) (*listRequest, error) {
+	params := r.URL.Query()
+	req := &listRequest{
+		filter:     params.Get("filter"),
+		startIndex: 1,
+		count:      defaultCount,
+		sortBy:     strings.ToLower(params.Get("sortBy")),
+		ascending:  !strings.EqualFold(params.Get("sortOrder"), "descending"),
+	}
+	if startIndex := params.Get("startIndex"); startIndex != "" {
+		index, err := strconv.ParseUint(startIndex, 10, 64)
+		if err != nil {
+			return nil, caos_errs.ThrowInvalidArgument(err, "SCIM-Sn3ka", "invalid startIndex")
+		}
+		if index > 1 {
+			req.startIndex = index
+		}
+	}
+	if count := params.Get("count"); count != "" {
+		c, err := strconv.ParseUint(count, 10, 64)
+		if err != nil {
+			return nil, caos_errs.ThrowInvalidArgument(err, "SCIM-Cm9ds", "invalid count")
+		}
+		req.count = c
+	}
+	if req.count > maxCount {
+		req.count = maxCount
+	}
+	return req, nil
+}
+
+func (req *listRequest) searchRequest(sortingColumns map[string]query.Column) query.SearchRequest {
+	return query.SearchRequest{
+		Offset:        req.startIndex - 1,
+		Limit:         req.count,
+		SortingColumn: sortingColumns[req.sortBy],
+		Asc:           req.ascending,
+	}
+}
+
+func decodeResource(r *http.Request, resource interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(resource); err != nil {
+		return &scimTypeError{
+			scimType: scimTypeInvalidSyntax,
+			parent:   caos_errs.ThrowInvalidArgument(err, "SCIM-Jd2ks", "invalid request body"),
+		}
+	}
+	return nil
+}
+
+// checkIfMatch prevents changes of outdated versions if the client sent an If-Match header
+func checkIfMatch(r *http.Request, version string) error {
+	ifMatch := r.Header.Get(http_util.IfMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return nil
+	}
+	for _, etag := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(etag) == version {
+			return nil
+		}
+	}
+	return &scimTypeError{
+		status: http.StatusPreconditionFailed,
+		parent: caos_errs.ThrowPreconditionFailed(nil, "SCIM-Em2lq", "version mismatch"),
+	}
+}
+
+// notModified responds with 304 if the client already has the current version
+func notModified(w http.ResponseWriter, r *http.Request, version string) bool {
+	if r.Header.Get(http_util.IfNoneMatch) != version {
+		return false
+	}
+	w.Header().Set(http_util.Etag, version)
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+func writeResource(w http.ResponseWriter, resource interface{}, version string, status int) {
+	if version != "" {
+		w.Header().Set(http_util.Etag, version)
+	}
+	writeJSON(w, resource, status)
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}, status int) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(http_util.ContentType, contentType)
+	w.WriteHeader(status)
+	_, err = w.Write(b)
+	logging.OnError(err).Error("error writing scim response")
+}
+
+const (
+	scimTypeInvalidFilter = "invalidFilter"
+	scimTypeInvalidSyntax = "invalidSyntax"
+	scimTypeInvalidPath   = "invalidPath"
+	scimTypeInvalidValue  = "invalidValue"
+	scimTypeUniqueness    = "uniqueness"
+)
+
+// scimTypeError enriches an error with the scimType and status of the response
+type scimTypeError struct {
+	scimType string
+	status   int
+	parent   error
+}
+
+func (err *scimTypeError) Error() string {
+	return err.parent.Error()
+}
+
+func (err *scimTypeError) Unwrap() error {
+	return err.parent
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, scimType := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		logging.WithFields("uri", r.RequestURI).WithError(err).Warn("error occurred on scim api")
+	}
+	detail := err.Error()
+	var caosErr *caos_errs.CaosError
+	if errors.As(err, &caosErr) {
+		detail = caosErr.GetMessage()
+	}
+	writeJSON(w, &ErrorResponse{
+		Schemas:  []string{schemaError},
+		ScimType: scimType,
+		Detail:   detail,
+		Status:   strconv.Itoa(status),
+	}, status)
+}
+
+func errorStatus(err error) (status int, scimType string) {
+	var typed *scimTypeError
+	if errors.As(err, &typed) {
+		if typed.status != 0 {
+			return typed.status, typed.scimType
+		}
+		err, scimType = typed.parent, typed.scimType
+	}
+	switch {
+	case caos_errs.IsErrorInvalidArgument(err):
+		if scimType == "" {
+			scimType = scimTypeInvalidValue
+		}
+		return http.StatusBadRequest, scimType
+	case caos_errs.IsPreconditionFailed(err):
+		return http.StatusBadRequest, scimType
+	case caos_errs.IsErrorAlreadyExists(err):
+		return http.StatusConflict, scimTypeUniqueness
+	case caos_errs.IsNotFound(err):
+		return http.StatusNotFound, scimType
+	case caos_errs.IsUnauthenticated(err):
+		return http.StatusUnauthorized, scimType
+	case caos_errs.IsPermissionDenied(err):
+		return http.StatusForbidden, scimType
+	case caos_errs.IsUnimplemented(err):
+		return http.StatusNotImplemented, scimType
+	default:
+		return http.StatusInternalServerError, scimType
+	}
+}
@@ -0,0 +1,556 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/command"
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
+	"github.com/zitadel/zitadel/internal/query"
+)
+
+var userSortingColumns = map[string]query.Column{
+	"username":          query.UserUsernameCol,
+	"name.givenname":    query.HumanFirstNameCol,
+	"name.familyname":   query.HumanLastNameCol,
+	"displayname":       query.HumanDisplayNameCol,
+	"nickname":          query.HumanNickNameCol,
+	"emails":            query.HumanEmailCol,
+	"emails.value":      query.HumanEmailCol,
+	"meta.created":      query.UserCreationDateCol,
+	"meta.lastmodified": query.UserChangeDateCol,
+}
+
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := parseListRequest(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	queries, err := h.userListQueries(ctx, req.filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	users, err := h.queries.SearchUsers(ctx, &query.UserSearchQueries{
+		SearchRequest: req.searchRequest(userSortingColumns),
+		Queries:       queries,
+	}, false)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resources := make([]*User, len(users.Users))
+	for i, user := range users.Users {
+		resources[i] = h.userToResource(ctx, user)
+	}
+	writeJSON(w, &ListResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: users.Count,
+		StartIndex:   req.startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, http.StatusOK)
+}
+
+// userListQueries restricts the list to the human users of the organisation and applies the filter
+func (h *Handler) userListQueries(ctx context.Context, filter string) ([]query.SearchQuery, error) {
+	ownerQuery, err := query.NewUserResourceOwnerSearchQuery(authz.GetCtxData(ctx).OrgID, query.TextEquals)
+	if err != nil {
+		return nil, err
+	}
+	typeQuery, err := query.NewUserTypeSearchQuery(int32(domain.UserTypeHuman))
+	if err != nil {
+		return nil, err
+	}
+	queries := []query.SearchQuery{ownerQuery, typeQuery}
+	if filter == "" {
+		return queries, nil
+	}
+	expression, err := parseFilter(filter, schemaUser)
+	if err != nil {
+		return nil, err
+	}
+	filterQuery, err := userFilterQuery(expression, "")
+	if err != nil {
+		return nil, err
+	}
+	return append(queries, filterQuery), nil
+}
+
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, err := h.userByID(ctx, mux.Vars(r)[paramID])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resource := h.userToResource(ctx, user)
+	if notModified(w, r, resource.Meta.Version) {
+		return
+	}
+	writeResource(w, resource, resource.Meta.Version, http.StatusOK)
+}
+
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resource := new(User)
+	if err := decodeResource(r, resource); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	orgID := authz.GetCtxData(ctx).OrgID
+	human := resourceToAddHuman(resource)
+	if err := h.commands.AddHuman(ctx, orgID, human, false); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if resource.Active != nil && !*resource.Active {
+		if _, err := h.commands.DeactivateUser(ctx, human.ID, orgID); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	h.writeUser(w, r, human.ID, http.StatusCreated)
+}
+
+func (h *Handler) replaceUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	current, err := h.userByID(ctx, mux.Vars(r)[paramID])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err = checkIfMatch(r, version(current.Sequence)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resource := new(User)
+	if err = decodeResource(r, resource); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err = h.updateUser(ctx, current, resource); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	h.writeUser(w, r, current.ID, http.StatusOK)
+}
+
+func (h *Handler) patchUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	current, err := h.userByID(ctx, mux.Vars(r)[paramID])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err = checkIfMatch(r, version(current.Sequence)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	patch := new(PatchRequest)
+	if err = decodeResource(r, patch); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resource := new(User)
+	if err = applyPatch(patch, schemaUser, h.userToResource(ctx, current), resource); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err = h.updateUser(ctx, current, resource); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	h.writeUser(w, r, current.ID, http.StatusOK)
+}
+
+func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	current, err := h.userByID(ctx, mux.Vars(r)[paramID])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err = checkIfMatch(r, version(current.Sequence)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	memberships, grants, err := h.removeUserDependencies(ctx, current.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if _, err = h.commands.RemoveUser(ctx, current.ID, current.ResourceOwner, memberships, grants...); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) writeUser(w http.ResponseWriter, r *http.Request, userID string, status int) {
+	ctx := r.Context()
+	user, err := h.userByID(ctx, userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resource := h.userToResource(ctx, user)
+	writeResource(w, resource, resource.Meta.Version, status)
+}
+
+// userByID returns the human user of the organisation, machine users are not provided by SCIM
+func (h *Handler) userByID(ctx context.Context, userID string) (*query.User, error) {
+	ownerQuery, err := query.NewUserResourceOwnerSearchQuery(authz.GetCtxData(ctx).OrgID, query.TextEquals)
+	if err != nil {
+		return nil, err
+	}
+	user, err := h.queries.GetUserByID(ctx, true, userID, false, ownerQuery)
+	if err != nil {
+		return nil, err
+	}
+	if user.Human == nil {
+		return nil, caos_errs.ThrowNotFound(nil, "SCIM-Hm2ls", "Errors.User.NotFound")
+	}
+	return user, nil
+}
+
+// updateUser applies the changes of the resource to the user.
+// As names are required on a user, missing names are kept.
+func (h *Handler) updateUser(ctx context.Context, current *query.User, resource *User) (err error) {
+	orgID := current.ResourceOwner
+	if resource.UserName != "" && resource.UserName != current.Username {
+		if _, err = h.commands.ChangeUsername(ctx, orgID, current.ID, resource.UserName); err != nil {
+			return err
+		}
+	}
+	if profile := resourceToProfile(current, resource); profile != nil {
+		if _, err = h.commands.ChangeHumanProfile(ctx, profile); err != nil {
+			return err
+		}
+	}
+	objectRoot := models.ObjectRoot{AggregateID: current.ID, ResourceOwner: orgID}
+	if email := primaryValue(resource.Emails); email != "" && !strings.EqualFold(email, string(current.Human.Email)) {
+		emailCodeGenerator, err := h.queries.InitEncryptionGenerator(ctx, domain.SecretGeneratorTypeVerifyEmailCode, h.userCodeAlg)
+		if err != nil {
+			return err
+		}
+		_, err = h.commands.ChangeHumanEmail(ctx, &domain.Email{
+			ObjectRoot:      objectRoot,
+			EmailAddress:    domain.EmailAddress(email),
+			IsEmailVerified: true,
+		}, emailCodeGenerator)
+		if err != nil {
+			return err
+		}
+	}
+	if err = h.updateUserPhone(ctx, current, primaryValue(resource.PhoneNumbers)); err != nil {
+		return err
+	}
+	if resource.Password != "" {
+		if _, err = h.commands.SetPassword(ctx, orgID, current.ID, resource.Password, false); err != nil {
+			return err
+		}
+	}
+	return h.updateUserState(ctx, current, resource.Active)
+}
+
+func (h *Handler) updateUserPhone(ctx context.Context, current *query.User, phone string) error {
+	if phone == string(current.Human.Phone) {
+		return nil
+	}
+	if phone == "" {
+		_, err := h.commands.RemoveHumanPhone(ctx, current.ID, current.ResourceOwner)
+		return err
+	}
+	phoneCodeGenerator, err := h.queries.InitEncryptionGenerator(ctx, domain.SecretGeneratorTypeVerifyPhoneCode, h.userCodeAlg)
+	if err != nil {
+		return err
+	}
+	_, err = h.commands.ChangeHumanPhone(ctx, &domain.Phone{
+		ObjectRoot:      models.ObjectRoot{AggregateID: current.ID},
+		PhoneNumber:     domain.PhoneNumber(phone),
+		IsPhoneVerified: true,
+	}, current.ResourceOwner, phoneCodeGenerator)
+	return err
+}
+
+func (h *Handler) updateUserState(ctx context.Context, current *query.User, active *bool) (err error) {
+	if active == nil {
+		return nil
+	}
+	inactive := current.State == domain.UserStateInactive
+	switch {
+	case *active && inactive:
+		_, err = h.commands.ReactivateUser(ctx, current.ID, current.ResourceOwner)
+	case !*active && !inactive:
+		_, err = h.commands.DeactivateUser(ctx, current.ID, current.ResourceOwner)
+	}
+	return err
+}
+
+func (h *Handler) removeUserDependencies(ctx context.Context, userID string) ([]*command.CascadingMembership, []string, error) {
+	userGrantUserQuery, err := query.NewUserGrantUserIDSearchQuery(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	grants, err := h.queries.UserGrants(ctx, &query.UserGrantsQueries{
+		Queries: []query.SearchQuery{userGrantUserQuery},
+	}, true, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	membershipsUserQuery, err := query.NewMembershipUserIDQuery(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	memberships, err := h.queries.Memberships(ctx, &query.MembershipSearchQuery{
+		Queries: []query.SearchQuery{membershipsUserQuery},
+	}, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cascadingMemberships(memberships.Memberships), userGrantsToIDs(grants.UserGrants), nil
+}
+
+func (h *Handler) userToResource(ctx context.Context, user *query.User) *User {
+	active := user.State != domain.UserStateInactive
+	resource := &User{
+		Schemas:  []string{schemaUser},
+		ID:       user.ID,
+		UserName: user.Username,
+		Name: &Name{
+			Formatted:  strings.TrimSpace(user.Human.FirstName + " " + user.Human.LastName),
+			GivenName:  user.Human.FirstName,
+			FamilyName: user.Human.LastName,
+		},
+		DisplayName: user.Human.DisplayName,
+		NickName:    user.Human.NickName,
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: resourceTypeUser,
+			Created:      user.CreationDate,
+			LastModified: user.ChangeDate,
+			Location:     h.location(ctx, "Users", user.ID),
+			Version:      version(user.Sequence),
+		},
+	}
+	if !user.Human.PreferredLanguage.IsRoot() {
+		resource.PreferredLanguage = user.Human.PreferredLanguage.String()
+	}
+	if user.Human.Email != "" {
+		resource.Emails = []*MultiValued{{Value: string(user.Human.Email), Primary: true}}
+	}
+	if user.Human.Phone != "" {
+		resource.PhoneNumbers = []*MultiValued{{Value: string(user.Human.Phone), Primary: true}}
+	}
+	return resource
+}
+
+// resourceToAddHuman maps the resource to a new user,
+// the email and phone are considered verified, as they are provided by the system of record
+func resourceToAddHuman(resource *User) *command.AddHuman {
+	human := &command.AddHuman{
+		Username:          resource.UserName,
+		NickName:          resource.NickName,
+		DisplayName:       resource.DisplayName,
+		Email:             command.Email{Address: domain.EmailAddress(primaryValue(resource.Emails)), Verified: true},
+		PreferredLanguage: language.Make(resource.PreferredLanguage),
+		Password:          resource.Password,
+	}
+	if phone := primaryValue(resource.PhoneNumbers); phone != "" {
+		human.Phone = command.Phone{Number: domain.PhoneNumber(phone), Verified: true}
+	}
+	if resource.Name != nil {
+		human.FirstName = resource.Name.GivenName
+		human.LastName = resource.Name.FamilyName
+	}
+	return human
+}
+
+// resourceToProfile returns the changed profile or nil if it's unchanged
+func resourceToProfile(current *query.User, resource *User) *domain.Profile {
+	profile := &domain.Profile{
+		ObjectRoot:        models.ObjectRoot{AggregateID: current.ID, ResourceOwner: current.ResourceOwner},
+		FirstName:         current.Human.FirstName,
+		LastName:          current.Human.LastName,
+		NickName:          resource.NickName,
+		DisplayName:       current.Human.DisplayName,
+		PreferredLanguage: language.Make(resource.PreferredLanguage),
+		Gender:            current.Human.Gender,
+	}
+	if resource.Name != nil && resource.Name.GivenName != "" {
+		profile.FirstName = resource.Name.GivenName
+	}
+	if resource.Name != nil && resource.Name.FamilyName != "" {
+		profile.LastName = resource.Name.FamilyName
+	}
+	if resource.DisplayName != "" {
+		profile.DisplayName = resource.DisplayName
+	}
+	if profile.FirstName == current.Human.FirstName &&
+		profile.LastName == current.Human.LastName &&
+		profile.NickName == current.Human.NickName &&
+		profile.DisplayName == current.Human.DisplayName &&
+		profile.PreferredLanguage == current.Human.PreferredLanguage {
+		return nil
+	}
+	return profile
+}
+
+// userFilterQuery maps the filter to the columns of the user,
+// the prefix is the attribute of a value path, e.g. `emails` of `emails[value eq "x"]`
+func userFilterQuery(expression filterExpression, prefix string) (query.SearchQuery, error) {
+	switch e := expression.(type) {
+	case *logicalExpression:
+		left, err := userFilterQuery(e.Left, prefix)
+		if err != nil {
+			return nil, err
+		}
+		right, err := userFilterQuery(e.Right, prefix)
+		if err != nil {
+			return nil, err
+		}
+		if e.Operator == operatorAnd {
+			return query.NewUserAndSearchQuery(left, right)
+		}
+		return query.NewUserOrSearchQuery(left, right)
+	case *notExpression:
+		q, err := userFilterQuery(e.Expression, prefix)
+		if err != nil {
+			return nil, err
+		}
+		return query.NewUserNotSearchQuery(q)
+	case *valuePathExpression:
+		if prefix != "" {
+			return nil, invalidFilterError("SCIM-Vp2ms", "nested value paths are not supported")
+		}
+		return userFilterQuery(e.Filter, e.Path+".")
+	case *attributeExpression:
+		return userAttributeQuery(e, prefix+e.Path)
+	}
+	return nil, invalidFilterError("SCIM-Ue3lq", "unsupported expression")
+}
+
+var userTextQueries = map[string]func(string, query.TextComparison) (query.SearchQuery, error){
+	"username":           query.NewUserUsernameSearchQuery,
+	"name.givenname":     query.NewUserFirstNameSearchQuery,
+	"name.familyname":    query.NewUserLastNameSearchQuery,
+	"displayname":        query.NewUserDisplayNameSearchQuery,
+	"nickname":           query.NewUserNickNameSearchQuery,
+	"emails":             query.NewUserEmailSearchQuery,
+	"emails.value":       query.NewUserEmailSearchQuery,
+	"phonenumbers":       query.NewUserPhoneSearchQuery,
+	"phonenumbers.value": query.NewUserPhoneSearchQuery,
+}
+
+func userAttributeQuery(expression *attributeExpression, path string) (query.SearchQuery, error) {
+	if path == "active" {
+		return userActiveQuery(expression)
+	}
+	textQuery, ok := userTextQueries[path]
+	if !ok {
+		return nil, invalidFilterError("SCIM-At8wk", "unsupported attribute "+path)
+	}
+	if expression.Operator == operatorPresent {
+		// a value is present if it's not empty, NULL values don't match the negation either
+		q, err := textQuery("", query.TextEquals)
+		if err != nil {
+			return nil, err
+		}
+		return query.NewUserNotSearchQuery(q)
+	}
+	value, ok := expression.Value.(string)
+	if !ok {
+		return nil, invalidFilterError("SCIM-Vt2kd", "value of "+path+" must be a string")
+	}
+	switch expression.Operator {
+	case operatorEqual:
+		return textQuery(value, query.TextEqualsIgnoreCase)
+	case operatorNotEqual:
+		q, err := textQuery(value, query.TextEqualsIgnoreCase)
+		if err != nil {
+			return nil, err
+		}
+		return query.NewUserNotSearchQuery(q)
+	case operatorContains:
+		return textQuery(value, query.TextContainsIgnoreCase)
+	case operatorStartsWith:
+		return textQuery(value, query.TextStartsWithIgnoreCase)
+	case operatorEndsWith:
+		return textQuery(value, query.TextEndsWithIgnoreCase)
+	}
+	return nil, invalidFilterError("SCIM-Op3ls", "unsupported operator "+expression.Operator+" for "+path)
+}
+
+// userActiveQuery maps the active attribute to the state, only deactivated users are inactive
+func userActiveQuery(expression *attributeExpression) (query.SearchQuery, error) {
+	active, ok := expression.Value.(bool)
+	if !ok || (expression.Operator != operatorEqual && expression.Operator != operatorNotEqual) {
+		return nil, invalidFilterError("SCIM-Ac2wn", "active must be compared with eq or ne to a boolean")
+	}
+	if expression.Operator == operatorNotEqual {
+		active = !active
+	}
+	inactiveQuery, err := query.NewUserStateSearchQuery(int32(domain.UserStateInactive))
+	if err != nil || !active {
+		return inactiveQuery, err
+	}
+	return query.NewUserNotSearchQuery(inactiveQuery)
+}
+
+func cascadingMemberships(memberships []*query.Membership) []*command.CascadingMembership {
+	cascades := make([]*command.CascadingMembership, len(memberships))
+	for i, membership := range memberships {
+		cascades[i] = &command.CascadingMembership{
+			UserID:        membership.UserID,
+			ResourceOwner: membership.ResourceOwner,
+			IAM:           cascadingIAMMembership(membership.IAM),
+			Org:           cascadingOrgMembership(membership.Org),
+			Project:       cascadingProjectMembership(membership.Project),
+			ProjectGrant:  cascadingProjectGrantMembership(membership.ProjectGrant),
+		}
+	}
+	return cascades
+}
+
+func cascadingIAMMembership(membership *query.IAMMembership) *command.CascadingIAMMembership {
+	if membership == nil {
+		return nil
+	}
+	return &command.CascadingIAMMembership{IAMID: membership.IAMID}
+}
+func cascadingOrgMembership(membership *query.OrgMembership) *command.CascadingOrgMembership {
+	if membership == nil {
+		return nil
+	}
+	return &command.CascadingOrgMembership{OrgID: membership.OrgID}
+}
+func cascadingProjectMembership(membership *query.ProjectMembership) *command.CascadingProjectMembership {
+	if membership == nil {
+		return nil
+	}
+	return &command.CascadingProjectMembership{ProjectID: membership.ProjectID}
+}
+func cascadingProjectGrantMembership(membership *query.ProjectGrantMembership) *command.CascadingProjectGrantMembership {
+	if membership == nil {
+		return nil
+	}
+	return &command.CascadingProjectGrantMembership{ProjectID: membership.ProjectID, GrantID: membership.GrantID}
+}
+
+func userGrantsToIDs(userGrants []*query.UserGrant) []string {
+	converted := make([]string, len(userGrants))
+	for i, grant := range userGrants {
+		converted[i] = grant.ID
+	}
+	return converted
+}
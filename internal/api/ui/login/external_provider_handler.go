@@ -3,6 +3,7 @@ package login
 import (
 	"context"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/zitadel/logging"
@@ -26,6 +27,7 @@ import (
 	"github.com/zitadel/zitadel/internal/idp/providers/ldap"
 	"github.com/zitadel/zitadel/internal/idp/providers/oauth"
 	openid "github.com/zitadel/zitadel/internal/idp/providers/oidc"
+	"github.com/zitadel/zitadel/internal/idp/providers/saml"
 	"github.com/zitadel/zitadel/internal/query"
 )
 
@@ -39,8 +41,9 @@ type externalIDPData struct {
 }
 
 type externalIDPCallbackData struct {
-	State string `schema:"state"`
-	Code  string `schema:"code"`
+	State        string `schema:"state"`
+	Code         string `schema:"code"`
+	SAMLResponse string `schema:"SAMLResponse"`
 }
 
 type externalNotFoundOptionFormData struct {
@@ -160,6 +163,8 @@ func (l *Login) handleIDP(w http.ResponseWriter, r *http.Request, authReq *domai
 		provider, err = l.googleProvider(r.Context(), identityProvider)
 	case domain.IDPTypeLDAP:
 		provider, err = l.ldapProvider(r.Context(), identityProvider)
+	case domain.IDPTypeSAML:
+		provider, err = l.samlProvider(r.Context(), identityProvider)
 	case domain.IDPTypeUnspecified:
 		fallthrough
 	default:
@@ -176,6 +181,10 @@ func (l *Login) handleIDP(w http.ResponseWriter, r *http.Request, authReq *domai
 		l.renderLogin(w, r, authReq, err)
 		return
 	}
+	if samlSession, ok := session.(*saml.Session); ok && samlSession.PostForm != nil {
+		l.renderSAMLPost(w, r, authReq, samlSession.GetAuthURL(), samlSession.PostForm, false)
+		return
+	}
 	http.Redirect(w, r, session.GetAuthURL(), http.StatusFound)
 }
 
@@ -258,6 +267,13 @@ func (l *Login) handleExternalLoginCallback(w http.ResponseWriter, r *http.Reque
 			return
 		}
 		session = &openid.Session{Provider: provider.(*google.Provider).Provider, Code: data.Code}
+	case domain.IDPTypeSAML:
+		provider, err = l.samlProvider(r.Context(), identityProvider)
+		if err != nil {
+			l.externalAuthFailed(w, r, authReq, nil, nil, err)
+			return
+		}
+		session = &saml.Session{Provider: provider.(*saml.Provider), RequestID: saml.RequestID(authReq.ID), Response: data.SAMLResponse}
 	case domain.IDPTypeJWT,
 		domain.IDPTypeLDAP,
 		domain.IDPTypeUnspecified:
@@ -285,14 +301,23 @@ func (l *Login) handleExternalUserAuthenticated(
 	user idp.User,
 	callback func(w http.ResponseWriter, r *http.Request, authReq *domain.AuthRequest),
 ) {
-	externalUser := mapIDPUserToExternalUser(user, provider.ID)
+	mappedUser, err := l.mapIDPUser(r.Context(), provider.ID, user)
+	if err != nil {
+		l.renderError(w, r, authReq, err)
+		return
+	}
+	externalUser := mapIDPUserToExternalUser(mappedUser, provider.ID)
+	externalUser.Metadatas = append(externalUser.Metadatas, mappedMetadata(mappedUser)...)
+	externalUser.Roles = mappedUser.Roles
+	if ldapUser, ok := user.(*ldap.User); ok {
+		externalUser.Groups = ldapUser.GetGroups()
+	}
 	// check and fill in local linked user
 	externalErr := l.authRepo.CheckExternalUserLogin(setContext(r.Context(), ""), authReq.ID, authReq.AgentID, externalUser, domain.BrowserInfoFromRequest(r))
 	if externalErr != nil && !errors.IsNotFound(externalErr) {
 		l.renderError(w, r, authReq, externalErr)
 		return
 	}
-	var err error
 	// read current auth request state (incl. authorized user)
 	authReq, err = l.authRepo.AuthRequestByID(r.Context(), authReq.ID, authReq.AgentID)
 	if err != nil {
@@ -323,9 +348,92 @@ func (l *Login) handleExternalUserAuthenticated(
 			return
 		}
 	}
+	if err = l.syncIDPClaimRoles(r.Context(), authReq, provider.ID, externalUser.Roles); err != nil {
+		l.renderError(w, r, authReq, err)
+		return
+	}
+	if err = l.syncLDAPGroups(r.Context(), authReq, provider, externalUser.Groups); err != nil {
+		l.renderError(w, r, authReq, err)
+		return
+	}
 	callback(w, r, authReq)
 }
 
+// mapIDPUser applies the claim mappings of the identity provider to the user
+func (l *Login) mapIDPUser(ctx context.Context, idpID string, user idp.User) (*idp.MappedUser, error) {
+	claimMappings, err := l.query.IDPClaimMappingsByID(ctx, false, idpID, false)
+	if err != nil {
+		return nil, err
+	}
+	return idp.MapUser(user, claimMappings.Mappings)
+}
+
+func mappedMetadata(user *idp.MappedUser) []*domain.Metadata {
+	keys := make([]string, 0, len(user.Metadata))
+	for key := range user.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	metadata := make([]*domain.Metadata, len(keys))
+	for i, key := range keys {
+		metadata[i] = &domain.Metadata{Key: key, Value: user.Metadata[key]}
+	}
+	return metadata
+}
+
+// syncIDPClaimRoles updates the user grants of the (linked) user according to the roles mapped from its claims,
+// only the roles allowed by the claim mappings of the identity provider are managed
+func (l *Login) syncIDPClaimRoles(ctx context.Context, authReq *domain.AuthRequest, idpID string, roles map[string][]string) error {
+	if authReq.UserID == "" {
+		return nil
+	}
+	claimMappings, err := l.query.IDPClaimMappingsByID(ctx, false, idpID, false)
+	if err != nil {
+		return err
+	}
+	managedRoles := idp.ManagedRoles(claimMappings.Mappings)
+	if len(managedRoles) == 0 {
+		return nil
+	}
+	userIDQuery, err := query.NewUserGrantUserIDSearchQuery(authReq.UserID)
+	if err != nil {
+		return err
+	}
+	grants, err := l.query.UserGrants(ctx, &query.UserGrantsQueries{Queries: []query.SearchQuery{userIDQuery}}, true, false)
+	if err != nil {
+		return err
+	}
+	grantIDs := make([]string, len(grants.UserGrants))
+	for i, grant := range grants.UserGrants {
+		grantIDs[i] = grant.ID
+	}
+	return l.command.SyncUserGrantsByIDPClaims(setContext(ctx, authReq.UserOrgID), authReq.UserID, authReq.UserOrgID, roles, managedRoles, grantIDs)
+}
+
+// syncExternalUserGrants updates the user grants of a user, which was created or linked with the external user,
+// according to its mapped roles and (LDAP) groups
+func (l *Login) syncExternalUserGrants(ctx context.Context, authReq *domain.AuthRequest, externalUser *domain.ExternalUser) error {
+	if err := l.syncIDPClaimRoles(ctx, authReq, externalUser.IDPConfigID, externalUser.Roles); err != nil {
+		return err
+	}
+	identityProvider, err := l.query.IDPTemplateByID(ctx, false, externalUser.IDPConfigID, false)
+	if err != nil {
+		return err
+	}
+	return l.syncLDAPGroups(ctx, authReq, identityProvider, externalUser.Groups)
+}
+
+// storedLinkingUser returns the external user stored on the auth request,
+// which contains the information not part of the data submitted by the user
+func storedLinkingUser(authReq *domain.AuthRequest, externalUser *domain.ExternalUser) *domain.ExternalUser {
+	for _, linkingUser := range authReq.LinkingUsers {
+		if linkingUser.IDPConfigID == externalUser.IDPConfigID && linkingUser.ExternalUserID == externalUser.ExternalUserID {
+			return linkingUser
+		}
+	}
+	return nil
+}
+
 // externalUserNotExisting is called if an externalAuthentication couldn't find a corresponding externalID
 // possible solutions are:
 //
@@ -506,6 +614,11 @@ func (l *Login) handleExternalNotFoundOptionCheck(w http.ResponseWriter, r *http
 		return
 	}
 	linkingUser := mapExternalNotFoundOptionFormDataToLoginUser(data)
+	if storedUser := storedLinkingUser(authReq, linkingUser); storedUser != nil {
+		linkingUser.Metadatas = storedUser.Metadatas
+		linkingUser.Groups = storedUser.Groups
+		linkingUser.Roles = storedUser.Roles
+	}
 	l.registerExternalUser(w, r, authReq, linkingUser)
 }
 
@@ -553,6 +666,11 @@ func (l *Login) registerExternalUser(w http.ResponseWriter, r *http.Request, aut
 		l.renderError(w, r, authReq, err)
 		return
 	}
+	err = l.syncExternalUserGrants(r.Context(), authReq, externalUser)
+	if err != nil {
+		l.renderError(w, r, authReq, err)
+		return
+	}
 	l.renderNextStep(w, r, authReq)
 }
 
@@ -713,6 +831,15 @@ func (l *Login) ldapProvider(ctx context.Context, identityProvider *query.IDPTem
 	if identityProvider.LDAPIDPTemplate.LDAPAttributes.ProfileAttribute != "" {
 		opts = append(opts, ldap.WithProfileAttribute(identityProvider.LDAPIDPTemplate.LDAPAttributes.ProfileAttribute))
 	}
+	if identityProvider.LDAPIDPTemplate.GroupsAttribute != "" {
+		opts = append(opts, ldap.WithGroupsAttribute(identityProvider.LDAPIDPTemplate.GroupsAttribute))
+	}
+	if identityProvider.LDAPIDPTemplate.GroupBase != "" {
+		opts = append(opts, ldap.WithGroupSearch(identityProvider.LDAPIDPTemplate.GroupBase, identityProvider.LDAPIDPTemplate.GroupMemberAttribute))
+	}
+	if identityProvider.LDAPIDPTemplate.NestedGroups {
+		opts = append(opts, ldap.WithNestedGroups())
+	}
 	return ldap.New(
 		identityProvider.Name,
 		identityProvider.Servers,
@@ -1,6 +1,7 @@
 package login
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/zitadel/logging"
@@ -8,6 +9,7 @@ import (
 	http_mw "github.com/zitadel/zitadel/internal/api/http/middleware"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/idp/providers/ldap"
+	"github.com/zitadel/zitadel/internal/query"
 )
 
 const (
@@ -81,3 +83,24 @@ func (l *Login) handleLDAPCallback(w http.ResponseWriter, r *http.Request) {
 	}
 	l.handleExternalUserAuthenticated(w, r, authReq, identityProvider, session, user, l.renderNextStep)
 }
+
+// syncLDAPGroups updates the user grants of the (linked) user according to its groups and the group mappings of the provider,
+// providers other than LDAP are ignored
+func (l *Login) syncLDAPGroups(ctx context.Context, authReq *domain.AuthRequest, identityProvider *query.IDPTemplate, groups []string) error {
+	if identityProvider.LDAPIDPTemplate == nil || len(identityProvider.LDAPIDPTemplate.GroupMappings) == 0 {
+		return nil
+	}
+	userIDQuery, err := query.NewUserGrantUserIDSearchQuery(authReq.UserID)
+	if err != nil {
+		return err
+	}
+	grants, err := l.query.UserGrants(ctx, &query.UserGrantsQueries{Queries: []query.SearchQuery{userIDQuery}}, true, false)
+	if err != nil {
+		return err
+	}
+	grantIDs := make([]string, len(grants.UserGrants))
+	for i, grant := range grants.UserGrants {
+		grantIDs[i] = grant.ID
+	}
+	return l.command.SyncUserGrantsByLDAPGroups(setContext(ctx, authReq.UserOrgID), authReq.UserID, authReq.UserOrgID, identityProvider.LDAPIDPTemplate.GroupMappings, groups, grantIDs)
+}
@@ -14,6 +14,14 @@ const (
 func (l *Login) linkUsers(w http.ResponseWriter, r *http.Request, authReq *domain.AuthRequest, err error) {
 	userAgentID, _ := http_mw.UserAgentIDFromCtx(r.Context())
 	err = l.authRepo.LinkExternalUsers(setContext(r.Context(), authReq.UserOrgID), authReq.ID, userAgentID, domain.BrowserInfoFromRequest(r))
+	if err == nil {
+		for _, linkingUser := range authReq.LinkingUsers {
+			if err = l.syncExternalUserGrants(r.Context(), authReq, linkingUser); err != nil {
+				l.renderError(w, r, authReq, err)
+				return
+			}
+		}
+	}
 	l.renderLinkUsersDone(w, r, authReq, err)
 }
 
@@ -0,0 +1,128 @@
+package login
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/idp/providers/saml"
+	"github.com/zitadel/zitadel/internal/query"
+)
+
+const (
+	tmplSAMLPost = "saml_post"
+	varSAMLIDPID = "idpid"
+)
+
+type samlACSData struct {
+	SAMLResponse string `schema:"SAMLResponse"`
+	RelayState   string `schema:"RelayState"`
+}
+
+type samlPostData struct {
+	userData
+	Action     string
+	Parameters map[string]string
+	// WithCSRF is only set if the form is posted back to the login
+	WithCSRF bool
+}
+
+// handleSAMLMetadata returns the service provider metadata, which has to be registered on the SAML identity provider
+func (l *Login) handleSAMLMetadata(w http.ResponseWriter, r *http.Request) {
+	identityProvider, err := l.getIDPByID(r, mux.Vars(r)[varSAMLIDPID])
+	if err != nil {
+		l.renderError(w, r, nil, err)
+		return
+	}
+	if identityProvider.Type != domain.IDPTypeSAML {
+		l.renderError(w, r, nil, errors.ThrowInvalidArgument(nil, "LOGIN-Gd3kq", "Errors.ExternalIDP.IDPTypeNotImplemented"))
+		return
+	}
+	provider, err := l.samlProvider(r.Context(), identityProvider)
+	if err != nil {
+		l.renderError(w, r, nil, err)
+		return
+	}
+	metadata, err := provider.Metadata()
+	if err != nil {
+		l.renderError(w, r, nil, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	_, err = w.Write(metadata)
+	if err != nil {
+		l.renderError(w, r, nil, err)
+	}
+}
+
+// handleSAMLACS is the assertion consumer service, where the SAML identity provider posts its response to.
+// As the request is cross-site, the cookies of the user agent are not sent (SameSite),
+// so the response is posted again (same-site) to the callback of the external login
+func (l *Login) handleSAMLACS(w http.ResponseWriter, r *http.Request) {
+	data := new(samlACSData)
+	err := l.getParseData(r, data)
+	if err != nil {
+		l.renderError(w, r, nil, err)
+		return
+	}
+	l.renderSAMLPost(w, r, nil, l.renderer.pathPrefix+EndpointExternalLoginCallback, url.Values{
+		"state":        {data.RelayState},
+		"SAMLResponse": {data.SAMLResponse},
+	}, true)
+}
+
+// renderSAMLPost renders a form, which will be submitted automatically to the action (HTTP-POST binding)
+func (l *Login) renderSAMLPost(w http.ResponseWriter, r *http.Request, authReq *domain.AuthRequest, action string, form url.Values, withCSRF bool) {
+	parameters := make(map[string]string, len(form))
+	for key := range form {
+		parameters[key] = form.Get(key)
+	}
+	data := samlPostData{
+		userData:   l.getUserData(r, authReq, "SAML.Title", "SAML.Description", "", ""),
+		Action:     action,
+		Parameters: parameters,
+		WithCSRF:   withCSRF,
+	}
+	l.renderer.RenderTemplate(w, r, l.getTranslator(r.Context(), authReq), l.renderer.Templates[tmplSAMLPost], data, nil)
+}
+
+func (l *Login) samlProvider(ctx context.Context, identityProvider *query.IDPTemplate) (*saml.Provider, error) {
+	key, err := crypto.Decrypt(identityProvider.SAMLIDPTemplate.Key, l.idpConfigAlg)
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]saml.ProviderOpts, 0, 6)
+	if identityProvider.IsLinkingAllowed {
+		opts = append(opts, saml.WithLinkingAllowed())
+	}
+	if identityProvider.IsCreationAllowed {
+		opts = append(opts, saml.WithCreationAllowed())
+	}
+	if identityProvider.IsAutoCreation {
+		opts = append(opts, saml.WithAutoCreation())
+	}
+	if identityProvider.IsAutoUpdate {
+		opts = append(opts, saml.WithAutoUpdate())
+	}
+	if identityProvider.SAMLIDPTemplate.Binding != "" {
+		opts = append(opts, saml.WithBinding(identityProvider.SAMLIDPTemplate.Binding))
+	}
+	if identityProvider.SAMLIDPTemplate.WithSignedRequest {
+		opts = append(opts, saml.WithSignedRequest())
+	}
+	rootURL := l.baseURL(ctx) + EndpointSAML + "/" + identityProvider.ID
+	return saml.New(
+		identityProvider.Name,
+		saml.MetadataURL(rootURL),
+		saml.ACSURL(rootURL),
+		identityProvider.SAMLIDPTemplate.Metadata,
+		identityProvider.SAMLIDPTemplate.Certificate,
+		key,
+		opts...,
+	)
+}
@@ -302,7 +302,7 @@ func (repo *AuthRequestRepo) SelectUser(ctx context.Context, id, userID, userAge
 	if err != nil {
 		return err
 	}
-	user, err := activeUserByID(ctx, repo.UserViewProvider, repo.UserEventProvider, repo.OrgViewProvider, repo.LockoutPolicyViewProvider, userID, false)
+	user, err := repo.activeUserByID(ctx, userID, false)
 	if err != nil {
 		return err
 	}
@@ -358,6 +358,11 @@ func lockoutPolicyToDomain(policy *query.LockoutPolicy) *domain.LockoutPolicy {
 		Default:             policy.IsDefault,
 		MaxPasswordAttempts: policy.MaxPasswordAttempts,
 		ShowLockOutFailures: policy.ShowFailures,
+		MaxOTPAttempts:      policy.MaxOTPAttempts,
+		MaxPasskeyAttempts:  policy.MaxPasskeyAttempts,
+		MaxIPAttempts:       policy.MaxIPAttempts,
+		LockoutDuration:     policy.LockoutDuration,
+		ProgressiveDelay:    policy.ProgressiveDelay,
 	}
 }
 
@@ -677,6 +682,10 @@ func (repo *AuthRequestRepo) checkLoginName(ctx context.Context, request *domain
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
+	// a locked user might be unlocked, because the lockout duration has passed
+	if user != nil && user.State == int32(domain.UserStateLocked) && repo.releaseExpiredUserLock(ctx, user.ID) {
+		user.State = int32(domain.UserStateActive)
+	}
 	// if there's an active (human) user, let's use it
 	if user != nil && !user.HumanView.IsZero() && domain.UserState(user.State).NotDisabled() {
 		request.SetUserInfo(user.ID, loginName, user.PreferredLoginName, "", "", user.ResourceOwner)
@@ -940,7 +949,7 @@ func (repo *AuthRequestRepo) nextSteps(ctx context.Context, request *domain.Auth
 		}
 		return steps, nil
 	}
-	user, err := activeUserByID(ctx, repo.UserViewProvider, repo.UserEventProvider, repo.OrgViewProvider, repo.LockoutPolicyViewProvider, request.UserID, request.LoginPolicy.IgnoreUnknownUsernames)
+	user, err := repo.activeUserByID(ctx, request.UserID, request.LoginPolicy.IgnoreUnknownUsernames)
 	if err != nil {
 		return nil, err
 	}
@@ -1370,6 +1379,24 @@ func userSessionByIDs(ctx context.Context, provider userSessionViewProvider, eve
 	return user_view_model.UserSessionToModel(&sessionCopy), nil
 }
 
+// activeUserByID returns the active user and releases the lock of the user, if the lockout duration has passed
+func (repo *AuthRequestRepo) activeUserByID(ctx context.Context, userID string, ignoreUnknownUsernames bool) (*user_model.UserView, error) {
+	user, err := activeUserByID(ctx, repo.UserViewProvider, repo.UserEventProvider, repo.OrgViewProvider, repo.LockoutPolicyViewProvider, userID, ignoreUnknownUsernames)
+	if err == nil || !errors.Contains(err, "Errors.User.Locked") || !repo.releaseExpiredUserLock(ctx, userID) {
+		return user, err
+	}
+	return activeUserByID(ctx, repo.UserViewProvider, repo.UserEventProvider, repo.OrgViewProvider, repo.LockoutPolicyViewProvider, userID, ignoreUnknownUsernames)
+}
+
+func (repo *AuthRequestRepo) releaseExpiredUserLock(ctx context.Context, userID string) bool {
+	if repo.Command == nil {
+		return false
+	}
+	released, err := repo.Command.ReleaseExpiredUserLock(ctx, userID, "")
+	logging.WithFields("traceID", tracing.TraceIDFromCtx(ctx)).OnError(err).Warn("unable to release expired user lock")
+	return released
+}
+
 func activeUserByID(ctx context.Context, userViewProvider userViewProvider, userEventProvider userEventProvider, queries orgViewProvider, lockoutPolicyProvider lockoutPolicyViewProvider, userID string, ignoreUnknownUsernames bool) (user *user_model.UserView, err error) {
 	// PLANNED: Check LockoutPolicy
 	user, err = userByID(ctx, userViewProvider, userEventProvider, userID)
@@ -35,11 +35,11 @@ type EsRepository struct {
 }
 
 func Start(ctx context.Context, conf Config, systemDefaults sd.SystemDefaults, command *command.Commands, queries *query.Queries, dbClient *database.DB, esV2 *eventstore2.Eventstore, oidcEncryption crypto.EncryptionAlgorithm, userEncryption crypto.EncryptionAlgorithm, allowOrderByCreationDate bool) (*EsRepository, error) {
-	es, err := v1.Start(dbClient, allowOrderByCreationDate)
+	es, err := v1.Start(dbClient, allowOrderByCreationDate, esV2.PII())
 	if err != nil {
 		return nil, err
 	}
-	idGenerator := id.SonyFlakeGenerator()
+	idGenerator := id.DefaultGenerator()
 
 	view, err := auth_view.StartView(dbClient, oidcEncryption, queries, idGenerator, es)
 	if err != nil {
@@ -169,7 +169,12 @@ func (repo *TokenVerifierRepo) getTokenIDAndSubject(ctx context.Context, accessT
 func (repo *TokenVerifierRepo) jwtTokenVerifier(ctx context.Context) op.AccessTokenVerifier {
 	keySet := &openIDKeySet{repo.Query}
 	issuer := http_util.BuildOrigin(authz.GetInstance(ctx).RequestedHost(), repo.ExternalSecure)
-	return op.NewAccessTokenVerifier(issuer, keySet)
+	return newAccessTokenVerifier(issuer, keySet)
+}
+
+// newAccessTokenVerifier accepts the signatures of all algorithms the signing keys of the instances might use
+func newAccessTokenVerifier(issuer string, keySet oidc.KeySet) op.AccessTokenVerifier {
+	return op.NewAccessTokenVerifier(issuer, keySet, op.WithSupportedAccessTokenSigningAlgorithms(crypto.SigningAlgorithms...))
 }
 
 func (repo *TokenVerifierRepo) decryptAccessToken(token string) (string, error) {
@@ -184,19 +189,23 @@ func (repo *TokenVerifierRepo) decryptAccessToken(token string) (string, error)
 	return tokenIDSubject, nil
 }
 
+type activePublicKeys interface {
+	ActivePublicKeys(ctx context.Context, t time.Time) (*query.PublicKeys, error)
+}
+
 type openIDKeySet struct {
-	*query.Queries
+	activePublicKeys
 }
 
 // VerifySignature implements the oidc.KeySet interface
 // providing an implementation for the keys retrieved directly from Queries
 func (o *openIDKeySet) VerifySignature(ctx context.Context, jws *jose.JSONWebSignature) ([]byte, error) {
-	keySet, err := o.Queries.ActivePublicKeys(ctx, time.Now())
+	keySet, err := o.ActivePublicKeys(ctx, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("error fetching keys: %w", err)
 	}
 	keyID, alg := oidc.GetKeyIDAndAlg(jws)
-	key, err := oidc.FindMatchingKey(keyID, oidc.KeyUseSignature, alg, jsonWebKeys(keySet.Keys)...)
+	key, err := crypto.FindSigningKey(keyID, alg, jsonWebKeys(keySet.Keys)...)
 	if err != nil {
 		return nil, fmt.Errorf("invalid signature: %w", err)
 	}
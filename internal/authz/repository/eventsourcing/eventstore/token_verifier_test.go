@@ -0,0 +1,87 @@
+package eventstore
+
+import (
+	"context"
+	gocrypto "crypto"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zitadel/oidc/v2/pkg/oidc"
+	"github.com/zitadel/oidc/v2/pkg/op"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/query"
+)
+
+type testPublicKey struct {
+	id        string
+	algorithm string
+	key       gocrypto.PublicKey
+}
+
+func (k *testPublicKey) ID() string           { return k.id }
+func (k *testPublicKey) Algorithm() string    { return k.algorithm }
+func (k *testPublicKey) Use() domain.KeyUsage { return domain.KeyUsageSigning }
+func (k *testPublicKey) Sequence() uint64     { return 1 }
+func (k *testPublicKey) Expiry() time.Time    { return time.Now().Add(time.Hour) }
+func (k *testPublicKey) Key() interface{}     { return k.key }
+
+type testActivePublicKeys []query.PublicKey
+
+func (k testActivePublicKeys) ActivePublicKeys(context.Context, time.Time) (*query.PublicKeys, error) {
+	return &query.PublicKeys{Keys: k}, nil
+}
+
+func signAccessToken(t *testing.T, issuer, algorithm, keyID string, key gocrypto.Signer) string {
+	t.Helper()
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.SignatureAlgorithm(algorithm), Key: &jose.JSONWebKey{Key: key, KeyID: keyID}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	require.NoError(t, err)
+	claims := oidc.NewAccessTokenClaims(issuer, "user1", []string{"client1"}, time.Now().Add(time.Hour), "token1", "client1", 0)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed, err := signer.Sign(payload)
+	require.NoError(t, err)
+	token, err := signed.CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func Test_newAccessTokenVerifier(t *testing.T) {
+	issuer := "https://zitadel.cloud"
+	for _, algorithm := range crypto.SigningAlgorithms {
+		t.Run(algorithm, func(t *testing.T) {
+			privateKey, publicKey, err := crypto.GenerateSigningKeyPair(algorithm, 2048)
+			require.NoError(t, err)
+			keySet := &openIDKeySet{testActivePublicKeys{
+				&testPublicKey{id: "key1", algorithm: algorithm, key: publicKey},
+			}}
+			token := signAccessToken(t, issuer, algorithm, "key1", privateKey)
+
+			claims, err := op.VerifyAccessToken[*oidc.AccessTokenClaims](context.Background(), token, newAccessTokenVerifier(issuer, keySet))
+			require.NoError(t, err)
+			assert.Equal(t, "token1", claims.JWTID)
+			assert.Equal(t, "user1", claims.Subject)
+		})
+	}
+	t.Run("unknown key, error", func(t *testing.T) {
+		privateKey, _, err := crypto.GenerateSigningKeyPair(crypto.SigningAlgorithmES256, 0)
+		require.NoError(t, err)
+		_, otherPublicKey, err := crypto.GenerateSigningKeyPair(crypto.SigningAlgorithmES256, 0)
+		require.NoError(t, err)
+		keySet := &openIDKeySet{testActivePublicKeys{
+			&testPublicKey{id: "key1", algorithm: crypto.SigningAlgorithmES256, key: otherPublicKey},
+		}}
+		token := signAccessToken(t, issuer, crypto.SigningAlgorithmES256, "key1", privateKey)
+
+		_, err = op.VerifyAccessToken[*oidc.AccessTokenClaims](context.Background(), token, newAccessTokenVerifier(issuer, keySet))
+		assert.ErrorIs(t, err, oidc.ErrSignatureInvalid)
+	})
+}
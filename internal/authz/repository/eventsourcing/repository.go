@@ -19,12 +19,13 @@ type EsRepository struct {
 }
 
 func Start(queries *query.Queries, dbClient *database.DB, keyEncryptionAlgorithm crypto.EncryptionAlgorithm, externalSecure, allowOrderByCreationDate bool) (repository.Repository, error) {
-	es, err := v1.Start(dbClient, allowOrderByCreationDate)
+	// the token events read by authz don't contain personal data
+	es, err := v1.Start(dbClient, allowOrderByCreationDate, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	idGenerator := id.SonyFlakeGenerator()
+	idGenerator := id.DefaultGenerator()
 	view, err := authz_view.StartView(dbClient, idGenerator, queries)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,69 @@
+package breach
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+const prefixLength = 5
+
+type Config struct {
+	// Path of the file containing the SHA-1 hashes of the breached passwords
+	Path string
+}
+
+func (c *Config) Enabled() bool {
+	return c.Path != ""
+}
+
+// NewChecker returns a checker which looks up the passwords in the file of the config
+func (c *Config) NewChecker() (*Checker, error) {
+	source, err := NewFileSource(c.Path)
+	if err != nil {
+		return nil, err
+	}
+	return NewChecker(source), nil
+}
+
+// RangeSource returns all hashes of a breached password starting with the prefix
+// in the range format of Have I Been Pwned ("SUFFIX:COUNT" per line, the suffix without the prefix)
+type RangeSource interface {
+	Range(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Checker checks passwords against a RangeSource.
+// Only the first five characters of the SHA-1 hash of the password are passed to the source,
+// so the source is never able to tell which password is checked.
+type Checker struct {
+	source RangeSource
+}
+
+func NewChecker(source RangeSource) *Checker {
+	return &Checker{source: source}
+}
+
+func (c *Checker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	lines, err := c.source.Range(ctx, prefix)
+	if err != nil {
+		return false, errors.ThrowInternal(err, "BREACH-ohB7a", "Errors.Internal")
+	}
+	for _, line := range lines {
+		lineSuffix, count, _ := strings.Cut(strings.TrimSpace(line), ":")
+		// padding entries of the range API have a count of 0
+		if count == "0" {
+			continue
+		}
+		if strings.EqualFold(lineSuffix, suffix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
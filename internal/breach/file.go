@@ -0,0 +1,117 @@
+package breach
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+// FileSource reads the hashes from a file sorted by hash,
+// as provided by the download of Have I Been Pwned ("FULLHASH:COUNT" per line, upper case).
+// The file is searched binary, so it doesn't have to fit into memory.
+type FileSource struct {
+	file *os.File
+	size int64
+}
+
+func NewFileSource(path string) (*FileSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "BREACH-Eeth4", "unable to open breached password file")
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, errors.ThrowInternal(err, "BREACH-kae2N", "unable to read breached password file")
+	}
+	return &FileSource{file: file, size: info.Size()}, nil
+}
+
+func (s *FileSource) Close() error {
+	return s.file.Close()
+}
+
+func (s *FileSource) Range(ctx context.Context, prefix string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	prefix = strings.ToUpper(prefix)
+	start, err := s.search(prefix)
+	if err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(io.NewSectionReader(s.file, start, s.size-start))
+	lines := make([]string, 0)
+	for {
+		line, err := readLine(reader)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if !strings.HasPrefix(line, prefix) {
+			return lines, nil
+		}
+		lines = append(lines, line[len(prefix):])
+		if err == io.EOF {
+			return lines, nil
+		}
+	}
+}
+
+// search returns the offset of the first line which is not lower than the prefix
+func (s *FileSource) search(prefix string) (int64, error) {
+	low, high := int64(0), s.size
+	for low < high {
+		mid := low + (high-low)/2
+		start, err := s.lineStart(mid)
+		if err != nil {
+			return 0, err
+		}
+		if start >= s.size {
+			high = mid
+			continue
+		}
+		line, err := readLine(bufio.NewReader(io.NewSectionReader(s.file, start, s.size-start)))
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if line >= prefix {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return s.lineStart(low)
+}
+
+// lineStart returns the offset of the first line starting at or after the offset
+func (s *FileSource) lineStart(offset int64) (int64, error) {
+	if offset == 0 {
+		return 0, nil
+	}
+	reader := bufio.NewReader(io.NewSectionReader(s.file, offset-1, s.size-offset+1))
+	skipped, err := reader.ReadSlice('\n')
+	if err == io.EOF {
+		return s.size, nil
+	}
+	if err != nil && err != bufio.ErrBufferFull {
+		return 0, err
+	}
+	start := offset - 1 + int64(len(skipped))
+	for err == bufio.ErrBufferFull {
+		skipped, err = reader.ReadSlice('\n')
+		start += int64(len(skipped))
+	}
+	if err == io.EOF {
+		return s.size, nil
+	}
+	return start, err
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
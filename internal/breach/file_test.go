@@ -0,0 +1,118 @@
+package breach
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileSource(t *testing.T, lines ...string) *FileSource {
+	path := filepath.Join(t.TempDir(), "pwned-passwords.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")), 0o600))
+	source, err := NewFileSource(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { source.Close() })
+	return source
+}
+
+func TestFileSource_Range(t *testing.T) {
+	source := newTestFileSource(t,
+		"0000000A1B2C3D4E5F60718293A4B5C6D7E8F901:3",
+		"21BD10018A45C4D1DEF81644B54AB7F969B88D65:1",
+		"21BD12DC183F740EE76F27B78EB39C8AD972A757:52579",
+		"21BD1FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF:2",
+		"21BD200000000000000000000000000000000000:4",
+		"FFFFF00000000000000000000000000000000000:1",
+	)
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{
+			prefix: "21BD1",
+			want: []string{
+				"0018A45C4D1DEF81644B54AB7F969B88D65:1",
+				"2DC183F740EE76F27B78EB39C8AD972A757:52579",
+				"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF:2",
+			},
+		},
+		{
+			prefix: "21bd1",
+			want: []string{
+				"0018A45C4D1DEF81644B54AB7F969B88D65:1",
+				"2DC183F740EE76F27B78EB39C8AD972A757:52579",
+				"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF:2",
+			},
+		},
+		{
+			prefix: "00000",
+			want:   []string{"00A1B2C3D4E5F60718293A4B5C6D7E8F901:3"},
+		},
+		{
+			prefix: "FFFFF",
+			want:   []string{"00000000000000000000000000000000000:1"},
+		},
+		{
+			prefix: "12345",
+			want:   []string{},
+		},
+		{
+			prefix: "FFFFE",
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got, err := source.Range(context.Background(), tt.prefix)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileSource_Range_empty(t *testing.T) {
+	got, err := newTestFileSource(t).Range(context.Background(), "21BD1")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestChecker_IsBreached(t *testing.T) {
+	// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	source := newTestFileSource(t,
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824",
+		"5BAA6FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF:0",
+	)
+	checker := NewChecker(source)
+
+	breached, err := checker.IsBreached(context.Background(), "password")
+	require.NoError(t, err)
+	assert.True(t, breached)
+
+	breached, err = checker.IsBreached(context.Background(), "correct horse battery staple")
+	require.NoError(t, err)
+	assert.False(t, breached)
+}
+
+type rangeSourceFunc func(ctx context.Context, prefix string) ([]string, error)
+
+func (f rangeSourceFunc) Range(ctx context.Context, prefix string) ([]string, error) {
+	return f(ctx, prefix)
+}
+
+func TestChecker_IsBreached_padding(t *testing.T) {
+	var requested string
+	checker := NewChecker(rangeSourceFunc(func(_ context.Context, prefix string) ([]string, error) {
+		requested = prefix
+		return []string{"1E4C9B93F3F0682250B6CF8331B7EE68FD8:0"}, nil
+	}))
+
+	breached, err := checker.IsBreached(context.Background(), "password")
+	require.NoError(t, err)
+	assert.False(t, breached)
+	assert.Equal(t, "5BAA6", requested)
+}
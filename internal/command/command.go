@@ -18,6 +18,7 @@ import (
 	"github.com/zitadel/zitadel/internal/repository/idpintent"
 	instance_repo "github.com/zitadel/zitadel/internal/repository/instance"
 	"github.com/zitadel/zitadel/internal/repository/keypair"
+	"github.com/zitadel/zitadel/internal/repository/lockout"
 	"github.com/zitadel/zitadel/internal/repository/milestone"
 	"github.com/zitadel/zitadel/internal/repository/org"
 	proj_repo "github.com/zitadel/zitadel/internal/repository/project"
@@ -43,23 +44,27 @@ type Commands struct {
 	externalSecure bool
 	externalPort   uint16
 
-	idpConfigEncryption         crypto.EncryptionAlgorithm
-	smtpEncryption              crypto.EncryptionAlgorithm
-	smsEncryption               crypto.EncryptionAlgorithm
-	userEncryption              crypto.EncryptionAlgorithm
-	userPasswordAlg             crypto.HashAlgorithm
-	machineKeySize              int
-	applicationKeySize          int
-	domainVerificationAlg       crypto.EncryptionAlgorithm
-	domainVerificationGenerator crypto.Generator
-	domainVerificationValidator func(domain, token, verifier string, checkType api_http.CheckType) error
-	sessionTokenCreator         func(sessionID string) (id string, token string, err error)
-	sessionTokenVerifier        func(ctx context.Context, sessionToken, sessionID, tokenID string) (err error)
+	idpConfigEncryption            crypto.EncryptionAlgorithm
+	smtpEncryption                 crypto.EncryptionAlgorithm
+	smsEncryption                  crypto.EncryptionAlgorithm
+	eventWebhookEncryption         crypto.EncryptionAlgorithm
+	userEncryption                 crypto.EncryptionAlgorithm
+	userPasswordAlg                crypto.HashAlgorithm
+	breachedPasswords              domain.BreachedPasswordChecker
+	machineKeySize                 int
+	applicationKeySize             int
+	domainVerificationAlg          crypto.EncryptionAlgorithm
+	domainVerificationGenerator    crypto.Generator
+	domainVerificationValidator    func(domain, token, verifier string, checkType api_http.CheckType) error
+	sessionTokenCreator            func(sessionID string) (id string, token string, err error)
+	sessionTokenVerifier           func(ctx context.Context, sessionToken, sessionID, tokenID string) (err error)
+	samlCertificateAndKeyGenerator func(id string) ([]byte, []byte, error)
 
 	multifactors         domain.MultifactorConfigs
 	webauthnConfig       *webauthn_helper.Config
 	keySize              int
 	keyAlgorithm         crypto.EncryptionAlgorithm
+	keyPairAlgorithm     crypto.EncryptionAlgorithm
 	certificateAlgorithm crypto.EncryptionAlgorithm
 	certKeySize          int
 	privateKeyLifetime   time.Duration
@@ -76,7 +81,7 @@ func StartCommands(
 	externalDomain string,
 	externalSecure bool,
 	externalPort uint16,
-	idpConfigEncryption, otpEncryption, smtpEncryption, smsEncryption, userEncryption, domainVerificationEncryption, oidcEncryption, samlEncryption crypto.EncryptionAlgorithm,
+	idpConfigEncryption, otpEncryption, smtpEncryption, smsEncryption, userEncryption, domainVerificationEncryption, oidcEncryption, keyPairEncryption, samlEncryption, eventWebhookEncryption crypto.EncryptionAlgorithm,
 	httpClient *http.Client,
 	permissionCheck domain.PermissionCheck,
 	sessionTokenVerifier func(ctx context.Context, sessionToken string, sessionID string, tokenID string) (err error),
@@ -84,35 +89,38 @@ func StartCommands(
 	if externalDomain == "" {
 		return nil, errors.ThrowInvalidArgument(nil, "COMMAND-Df21s", "no external domain specified")
 	}
-	idGenerator := id.SonyFlakeGenerator()
+	idGenerator := id.DefaultGenerator()
 	// reuse the oidcEncryption to be able to handle both tokens in the interceptor later on
 	sessionAlg := oidcEncryption
 	repo = &Commands{
-		eventstore:            es,
-		static:                staticStore,
-		idGenerator:           idGenerator,
-		zitadelRoles:          zitadelRoles,
-		externalDomain:        externalDomain,
-		externalSecure:        externalSecure,
-		externalPort:          externalPort,
-		keySize:               defaults.KeyConfig.Size,
-		certKeySize:           defaults.KeyConfig.CertificateSize,
-		privateKeyLifetime:    defaults.KeyConfig.PrivateKeyLifetime,
-		publicKeyLifetime:     defaults.KeyConfig.PublicKeyLifetime,
-		certificateLifetime:   defaults.KeyConfig.CertificateLifetime,
-		idpConfigEncryption:   idpConfigEncryption,
-		smtpEncryption:        smtpEncryption,
-		smsEncryption:         smsEncryption,
-		userEncryption:        userEncryption,
-		domainVerificationAlg: domainVerificationEncryption,
-		keyAlgorithm:          oidcEncryption,
-		certificateAlgorithm:  samlEncryption,
-		webauthnConfig:        webAuthN,
-		httpClient:            httpClient,
-		checkPermission:       permissionCheck,
-		newCode:               newCryptoCodeWithExpiry,
-		sessionTokenCreator:   sessionTokenCreator(idGenerator, sessionAlg),
-		sessionTokenVerifier:  sessionTokenVerifier,
+		eventstore:                     es,
+		static:                         staticStore,
+		idGenerator:                    idGenerator,
+		zitadelRoles:                   zitadelRoles,
+		externalDomain:                 externalDomain,
+		externalSecure:                 externalSecure,
+		externalPort:                   externalPort,
+		keySize:                        defaults.KeyConfig.Size,
+		certKeySize:                    defaults.KeyConfig.CertificateSize,
+		privateKeyLifetime:             defaults.KeyConfig.PrivateKeyLifetime,
+		publicKeyLifetime:              defaults.KeyConfig.PublicKeyLifetime,
+		certificateLifetime:            defaults.KeyConfig.CertificateLifetime,
+		idpConfigEncryption:            idpConfigEncryption,
+		smtpEncryption:                 smtpEncryption,
+		smsEncryption:                  smsEncryption,
+		eventWebhookEncryption:         eventWebhookEncryption,
+		userEncryption:                 userEncryption,
+		domainVerificationAlg:          domainVerificationEncryption,
+		keyAlgorithm:                   oidcEncryption,
+		keyPairAlgorithm:               keyPairEncryption,
+		certificateAlgorithm:           samlEncryption,
+		webauthnConfig:                 webAuthN,
+		httpClient:                     httpClient,
+		checkPermission:                permissionCheck,
+		newCode:                        newCryptoCodeWithExpiry,
+		sessionTokenCreator:            sessionTokenCreator(idGenerator, sessionAlg),
+		sessionTokenVerifier:           sessionTokenVerifier,
+		samlCertificateAndKeyGenerator: samlCertificateAndKeyGenerator(defaults.KeyConfig.CertificateSize, defaults.KeyConfig.CertificateLifetime),
 	}
 
 	instance_repo.RegisterEventMappers(repo.eventstore)
@@ -126,8 +134,18 @@ func StartCommands(
 	session.RegisterEventMappers(repo.eventstore)
 	idpintent.RegisterEventMappers(repo.eventstore)
 	milestone.RegisterEventMappers(repo.eventstore)
+	lockout.RegisterEventMappers(repo.eventstore)
 
-	repo.userPasswordAlg = crypto.NewBCrypt(defaults.SecretGenerators.PasswordSaltCost)
+	repo.userPasswordAlg, err = crypto.NewPasswordHasher(&defaults.PasswordHasher, defaults.SecretGenerators.PasswordSaltCost)
+	if err != nil {
+		return nil, err
+	}
+	if defaults.BreachedPasswords.Enabled() {
+		repo.breachedPasswords, err = defaults.BreachedPasswords.NewChecker()
+		if err != nil {
+			return nil, err
+		}
+	}
 	repo.machineKeySize = int(defaults.SecretGenerators.MachineKeySize)
 	repo.applicationKeySize = int(defaults.SecretGenerators.ApplicationKeySize)
 
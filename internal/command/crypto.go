@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/command/preparation"
 	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
@@ -59,7 +60,11 @@ func secretGenerator(ctx context.Context, filter preparation.FilterToQueryReduce
 	case crypto.HashAlgorithm:
 		return crypto.NewHashGenerator(*config, a), config, nil
 	case crypto.EncryptionAlgorithm:
-		return crypto.NewEncryptionGenerator(*config, a), config, nil
+		gen, err := crypto.NewCodeGenerator(*config, a, authz.GetInstance(ctx).InstanceID())
+		if err != nil {
+			return nil, nil, err
+		}
+		return gen, config, nil
 	default:
 		return nil, nil, errors.ThrowInternalf(nil, "COMMA-RreV6", "Errors.Internal unsupported crypto algorithm type %T", a)
 	}
@@ -82,5 +87,6 @@ func secretGeneratorConfig(ctx context.Context, filter preparation.FilterToQuery
 		IncludeUpperLetters: wm.IncludeUpperLetters,
 		IncludeDigits:       wm.IncludeDigits,
 		IncludeSymbols:      wm.IncludeSymbols,
+		Hashed:              wm.Hashed,
 	}, nil
 }
@@ -42,6 +42,15 @@ var (
 		IncludeDigits:       true,
 		IncludeSymbols:      true,
 	}
+	testHashedGeneratorConfig = crypto.GeneratorConfig{
+		Length:              12,
+		Expiry:              60000000000,
+		IncludeLowerLetters: true,
+		IncludeUpperLetters: true,
+		IncludeDigits:       true,
+		IncludeSymbols:      true,
+		Hashed:              true,
+	}
 )
 
 func testSecretGeneratorAddedEvent(typ domain.SecretGeneratorType) *instance.SecretGeneratorAddedEvent {
@@ -53,9 +62,16 @@ func testSecretGeneratorAddedEvent(typ domain.SecretGeneratorType) *instance.Sec
 		testGeneratorConfig.IncludeUpperLetters,
 		testGeneratorConfig.IncludeDigits,
 		testGeneratorConfig.IncludeSymbols,
+		false,
 	)
 }
 
+func testHashedSecretGeneratorAddedEvent(typ domain.SecretGeneratorType) *instance.SecretGeneratorAddedEvent {
+	event := testSecretGeneratorAddedEvent(typ)
+	event.Hashed = true
+	return event
+}
+
 func Test_newCryptoCode(t *testing.T) {
 	type args struct {
 		typ domain.SecretGeneratorType
@@ -65,6 +81,7 @@ func Test_newCryptoCode(t *testing.T) {
 		name       string
 		eventstore *eventstore.Eventstore
 		args       args
+		wantHashed bool
 		wantErr    error
 	}{
 		{
@@ -86,6 +103,17 @@ func Test_newCryptoCode(t *testing.T) {
 				alg: crypto.CreateMockHashAlg(gomock.NewController(t)),
 			},
 		},
+		{
+			name: "hashed, success",
+			eventstore: eventstoreExpect(t, expectFilter(
+				eventFromEventPusher(testHashedSecretGeneratorAddedEvent(domain.SecretGeneratorTypeVerifyEmailCode)),
+			)),
+			args: args{
+				typ: domain.SecretGeneratorTypeVerifyEmailCode,
+				alg: crypto.NewCodeAlgorithm(crypto.CreateMockEncryptionAlg(gomock.NewController(t)), crypto.NewHMACHasher([]byte("key")), crypto.CreateMockCodeCopyStorage()),
+			},
+			wantHashed: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -96,6 +124,13 @@ func Test_newCryptoCode(t *testing.T) {
 				assert.NotNil(t, got.Crypted)
 				assert.NotEmpty(t, got)
 				assert.Equal(t, testGeneratorConfig.Expiry, got.Expiry)
+				if tt.wantHashed {
+					assert.Equal(t, crypto.TypeHash, got.Crypted.CryptoType)
+					// the notification handler sends the code from its encrypted copy
+					plain, err := crypto.DecryptCode("", got.Crypted, tt.args.alg.(crypto.EncryptionAlgorithm))
+					require.NoError(t, err)
+					assert.Equal(t, got.Plain, plain)
+				}
 			}
 		})
 	}
@@ -216,9 +251,32 @@ func Test_secretGenerator(t *testing.T) {
 				typ: domain.SecretGeneratorTypeVerifyEmailCode,
 				alg: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
 			},
-			want:     crypto.NewEncryptionGenerator(testGeneratorConfig, crypto.CreateMockEncryptionAlg(gomock.NewController(t))),
+			want:     mustCodeGenerator(t, testGeneratorConfig, crypto.CreateMockEncryptionAlg(gomock.NewController(t))),
 			wantConf: &testGeneratorConfig,
 		},
+		{
+			name: "hashed code generator",
+			eventsore: eventstoreExpect(t, expectFilter(
+				eventFromEventPusher(testHashedSecretGeneratorAddedEvent(domain.SecretGeneratorTypeVerifyEmailCode)),
+			)),
+			args: args{
+				typ: domain.SecretGeneratorTypeVerifyEmailCode,
+				alg: crypto.NewCodeAlgorithm(crypto.CreateMockEncryptionAlg(gomock.NewController(t)), crypto.NewHMACHasher([]byte("key")), crypto.CreateMockCodeCopyStorage()),
+			},
+			want:     mustCodeGenerator(t, testHashedGeneratorConfig, crypto.NewCodeAlgorithm(crypto.CreateMockEncryptionAlg(gomock.NewController(t)), crypto.NewHMACHasher([]byte("key")), crypto.CreateMockCodeCopyStorage())),
+			wantConf: &testHashedGeneratorConfig,
+		},
+		{
+			name: "hashed code generator, hashing not supported",
+			eventsore: eventstoreExpect(t, expectFilter(
+				eventFromEventPusher(testHashedSecretGeneratorAddedEvent(domain.SecretGeneratorTypeVerifyEmailCode)),
+			)),
+			args: args{
+				typ: domain.SecretGeneratorTypeVerifyEmailCode,
+				alg: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+			},
+			wantErr: errors.ThrowPreconditionFailed(nil, "CODE-Ooh4i", "Errors.SecretGenerator.HashingNotSupported"),
+		},
 		{
 			name: "unsupported type",
 			eventsore: eventstoreExpect(t, expectFilter(
@@ -240,3 +298,9 @@ func Test_secretGenerator(t *testing.T) {
 		})
 	}
 }
+
+func mustCodeGenerator(t *testing.T, config crypto.GeneratorConfig, alg crypto.EncryptionAlgorithm) crypto.Generator {
+	gen, err := crypto.NewCodeGenerator(config, alg, "instanceID")
+	require.NoError(t, err)
+	return gen
+}
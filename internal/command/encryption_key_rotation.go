@@ -0,0 +1,163 @@
+package command
+
+import (
+	"context"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+const keyRotationUserID = "SYSTEM"
+
+// ReencryptInstancesSecrets re-encrypts the secrets of the purpose in all instances
+// and returns the amount of secrets which remain encrypted with a previous key.
+// The keyID must be the current key of the purpose, which is only the case
+// if the process was started after the rotation.
+func (c *Commands) ReencryptInstancesSecrets(ctx context.Context, purpose crypto.KeyPurpose, keyID string, limit int) (remaining uint64, err error) {
+	alg, err := c.encryptionForPurpose(purpose)
+	if err != nil {
+		return 0, err
+	}
+	if alg.EncryptionKeyID() != keyID {
+		return 0, errors.ThrowPreconditionFailedf(nil, "COMMAND-Eiph5", "key %s is not the current key of %s, restart required", keyID, purpose)
+	}
+	instanceIDs, err := c.activeInstanceIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, instanceID := range instanceIDs {
+		instanceCtx := authz.SetCtxData(authz.WithInstanceID(ctx, instanceID), authz.CtxData{UserID: keyRotationUserID})
+		instanceRemaining, err := c.ReencryptSecrets(instanceCtx, purpose, limit)
+		if err != nil {
+			// the secrets of the instance remain until the error is resolved
+			logging.WithFields("instance", instanceID, "purpose", purpose).WithError(err).Warn("unable to re-encrypt secrets")
+			instanceRemaining = 1
+		}
+		remaining += instanceRemaining
+	}
+	return remaining, nil
+}
+
+func (c *Commands) activeInstanceIDs(ctx context.Context) ([]string, error) {
+	instanceIDs, err := c.eventstore.InstanceIDs(ctx, eventstore.NewSearchQueryBuilder(eventstore.ColumnsInstanceIDs).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		EventTypes(instance.InstanceAddedEventType).
+		Builder(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	removedIDs, err := c.eventstore.InstanceIDs(ctx, eventstore.NewSearchQueryBuilder(eventstore.ColumnsInstanceIDs).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		EventTypes(instance.InstanceRemovedEventType).
+		Builder(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	removed := make(map[string]bool, len(removedIDs))
+	for _, id := range removedIDs {
+		removed[id] = true
+	}
+	active := make([]string, 0, len(instanceIDs))
+	for _, id := range instanceIDs {
+		if !removed[id] {
+			active = append(active, id)
+		}
+	}
+	return active, nil
+}
+
+// ReencryptSecrets re-encrypts up to limit secrets of the purpose in the instance of the context
+// which are still encrypted with a previous key.
+// It returns the amount of secrets which remain encrypted with a previous key.
+// The oidc signing keys are not re-encrypted, but only counted until they expire.
+func (c *Commands) ReencryptSecrets(ctx context.Context, purpose crypto.KeyPurpose, limit int) (remaining uint64, err error) {
+	alg, err := c.encryptionForPurpose(purpose)
+	if err != nil {
+		return 0, err
+	}
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	if purpose == crypto.KeyPurposeOIDC {
+		writeModel := NewKeyPairsWriteModel(alg.EncryptionKeyID(), instanceID, c.publicKeyLifetime)
+		if err = c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+			return 0, err
+		}
+		return writeModel.Remaining, nil
+	}
+	writeModel := NewEncryptedSecretsWriteModel(purpose, instanceID)
+	if err = c.filterEncryptedSecrets(ctx, writeModel); err != nil {
+		return 0, err
+	}
+	outdated := writeModel.outdated(alg.EncryptionKeyID())
+	if limit > 0 && len(outdated) > limit {
+		remaining = uint64(len(outdated) - limit)
+		outdated = outdated[:limit]
+	}
+	cmds := make([]eventstore.Command, len(outdated))
+	for i, secret := range outdated {
+		cmds[i], err = reencrypt(ctx, secret, alg)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if len(cmds) == 0 {
+		return remaining, nil
+	}
+	if _, err = c.eventstore.Push(ctx, cmds...); err != nil {
+		return 0, err
+	}
+	return remaining, nil
+}
+
+// filterEncryptedSecrets reduces the events of the write model page by page
+// until a page isn't full anymore
+func (c *Commands) filterEncryptedSecrets(ctx context.Context, writeModel *EncryptedSecretsWriteModel) error {
+	for {
+		writeModel.pageEvents = 0
+		if err := c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+			return err
+		}
+		if uint64(writeModel.pageEvents) < encryptedSecretsPageSize {
+			return nil
+		}
+	}
+}
+
+func reencrypt(ctx context.Context, secret *encryptedSecret, alg crypto.EncryptionAlgorithm) (eventstore.Command, error) {
+	value, err := crypto.Decrypt(secret.value, alg)
+	if err != nil {
+		return nil, errors.ThrowPreconditionFailedf(err, "COMMAND-Aiw3e", "unable to decrypt secret of %s %s with key %s", secret.aggregate.Type, secret.aggregate.ID, secret.value.KeyID)
+	}
+	reencrypted, err := crypto.Encrypt(value, alg)
+	if err != nil {
+		return nil, err
+	}
+	return secret.reencrypt(ctx, secret.aggregate, reencrypted)
+}
+
+func (c *Commands) encryptionForPurpose(purpose crypto.KeyPurpose) (alg crypto.EncryptionAlgorithm, err error) {
+	switch purpose {
+	case crypto.KeyPurposeOTP:
+		alg = c.multifactors.OTP.CryptoMFA
+	case crypto.KeyPurposeSMS:
+		alg = c.smsEncryption
+	case crypto.KeyPurposeSMTP:
+		alg = c.smtpEncryption
+	case crypto.KeyPurposeIDPConfig:
+		alg = c.idpConfigEncryption
+	case crypto.KeyPurposeOIDC:
+		alg = c.keyPairAlgorithm
+	}
+	if alg == nil {
+		return nil, errors.ThrowPreconditionFailedf(nil, "COMMAND-ku3Ie", "no encryption configured for key purpose %q", purpose)
+	}
+	return alg, nil
+}
@@ -0,0 +1,581 @@
+package command
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/idp"
+	"github.com/zitadel/zitadel/internal/repository/idpconfig"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/keypair"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+// reencryptSecret creates the event which replaces the secret by its re-encrypted value
+type reencryptSecret func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error)
+
+type encryptedSecret struct {
+	aggregate *eventstore.Aggregate
+	value     *crypto.CryptoValue
+	reencrypt reencryptSecret
+}
+
+// encryptedSecretsPageSize is the maximum amount of events filtered at once,
+// so only the secrets but not all their events are held in memory
+var encryptedSecretsPageSize uint64 = 1000
+
+// EncryptedSecretsWriteModel collects the current encrypted secrets of a key purpose of an instance.
+// The events are filtered in pages, see filterEncryptedSecrets
+type EncryptedSecretsWriteModel struct {
+	eventstore.WriteModel
+
+	purpose crypto.KeyPurpose
+	secrets map[string]*encryptedSecret
+	// pageEvents is the amount of events appended since the last page was filtered
+	pageEvents int
+}
+
+func NewEncryptedSecretsWriteModel(purpose crypto.KeyPurpose, instanceID string) *EncryptedSecretsWriteModel {
+	return &EncryptedSecretsWriteModel{
+		WriteModel: eventstore.WriteModel{
+			InstanceID: instanceID,
+		},
+		purpose: purpose,
+		secrets: make(map[string]*encryptedSecret),
+	}
+}
+
+func (wm *EncryptedSecretsWriteModel) AppendEvents(events ...eventstore.Event) {
+	wm.pageEvents += len(events)
+	for _, event := range events {
+		switch e := event.(type) {
+		case *instance.OAuthIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.OAuthIDPAddedEvent)
+		case *instance.OAuthIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.OAuthIDPChangedEvent)
+		case *instance.OIDCIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCIDPAddedEvent)
+		case *instance.OIDCIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCIDPChangedEvent)
+		case *instance.AzureADIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.AzureADIDPAddedEvent)
+		case *instance.AzureADIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.AzureADIDPChangedEvent)
+		case *instance.GitHubIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.GitHubIDPAddedEvent)
+		case *instance.GitHubIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.GitHubIDPChangedEvent)
+		case *instance.GitHubEnterpriseIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.GitHubEnterpriseIDPAddedEvent)
+		case *instance.GitHubEnterpriseIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.GitHubEnterpriseIDPChangedEvent)
+		case *instance.GitLabIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.GitLabIDPAddedEvent)
+		case *instance.GitLabIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.GitLabIDPChangedEvent)
+		case *instance.GitLabSelfHostedIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.GitLabSelfHostedIDPAddedEvent)
+		case *instance.GitLabSelfHostedIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.GitLabSelfHostedIDPChangedEvent)
+		case *instance.GoogleIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.GoogleIDPAddedEvent)
+		case *instance.GoogleIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.GoogleIDPChangedEvent)
+		case *instance.LDAPIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.LDAPIDPAddedEvent)
+		case *instance.LDAPIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.LDAPIDPChangedEvent)
+		case *instance.SAMLIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.SAMLIDPAddedEvent)
+		case *instance.SAMLIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.SAMLIDPChangedEvent)
+		case *instance.OIDCIDPMigratedAzureADEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCIDPMigratedAzureADEvent)
+		case *instance.OIDCIDPMigratedGoogleEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCIDPMigratedGoogleEvent)
+		case *instance.IDPRemovedEvent:
+			wm.WriteModel.AppendEvents(&e.RemovedEvent)
+		case *instance.IDPOIDCConfigAddedEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCConfigAddedEvent)
+		case *instance.IDPOIDCConfigChangedEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCConfigChangedEvent)
+		case *instance.IDPConfigRemovedEvent:
+			wm.WriteModel.AppendEvents(&e.IDPConfigRemovedEvent)
+		case *org.OAuthIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.OAuthIDPAddedEvent)
+		case *org.OAuthIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.OAuthIDPChangedEvent)
+		case *org.OIDCIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCIDPAddedEvent)
+		case *org.OIDCIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCIDPChangedEvent)
+		case *org.AzureADIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.AzureADIDPAddedEvent)
+		case *org.AzureADIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.AzureADIDPChangedEvent)
+		case *org.GitHubIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.GitHubIDPAddedEvent)
+		case *org.GitHubIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.GitHubIDPChangedEvent)
+		case *org.GitHubEnterpriseIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.GitHubEnterpriseIDPAddedEvent)
+		case *org.GitHubEnterpriseIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.GitHubEnterpriseIDPChangedEvent)
+		case *org.GitLabIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.GitLabIDPAddedEvent)
+		case *org.GitLabIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.GitLabIDPChangedEvent)
+		case *org.GitLabSelfHostedIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.GitLabSelfHostedIDPAddedEvent)
+		case *org.GitLabSelfHostedIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.GitLabSelfHostedIDPChangedEvent)
+		case *org.GoogleIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.GoogleIDPAddedEvent)
+		case *org.GoogleIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.GoogleIDPChangedEvent)
+		case *org.LDAPIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.LDAPIDPAddedEvent)
+		case *org.LDAPIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.LDAPIDPChangedEvent)
+		case *org.SAMLIDPAddedEvent:
+			wm.WriteModel.AppendEvents(&e.SAMLIDPAddedEvent)
+		case *org.SAMLIDPChangedEvent:
+			wm.WriteModel.AppendEvents(&e.SAMLIDPChangedEvent)
+		case *org.OIDCIDPMigratedAzureADEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCIDPMigratedAzureADEvent)
+		case *org.OIDCIDPMigratedGoogleEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCIDPMigratedGoogleEvent)
+		case *org.IDPRemovedEvent:
+			wm.WriteModel.AppendEvents(&e.RemovedEvent)
+		case *org.IDPOIDCConfigAddedEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCConfigAddedEvent)
+		case *org.IDPOIDCConfigChangedEvent:
+			wm.WriteModel.AppendEvents(&e.OIDCConfigChangedEvent)
+		case *org.IDPConfigRemovedEvent:
+			wm.WriteModel.AppendEvents(&e.IDPConfigRemovedEvent)
+		default:
+			wm.WriteModel.AppendEvents(e)
+		}
+	}
+}
+
+func (wm *EncryptedSecretsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *user.HumanOTPAddedEvent:
+			wm.addSecret(e, "", e.Secret, reencryptOTPSecret)
+		case *user.HumanOTPSecretReencryptedEvent:
+			wm.changeSecret(e, "", e.Secret)
+		case *user.HumanOTPRemovedEvent:
+			wm.removeSecret(e, "")
+		case *user.UserRemovedEvent:
+			wm.removeSecret(e, "")
+		case *instance.SMSConfigTwilioAddedEvent:
+			wm.addSecret(e, e.ID, e.Token, reencryptSMSToken(e.ID))
+		case *instance.SMSConfigTwilioTokenChangedEvent:
+			wm.changeSecret(e, e.ID, e.Token)
+		case *instance.SMSConfigRemovedEvent:
+			wm.removeSecret(e, e.ID)
+		case *instance.SMTPConfigAddedEvent:
+			wm.addSecret(e, "", e.Password, reencryptSMTPPassword)
+		case *instance.SMTPConfigPasswordChangedEvent:
+			wm.changeSecret(e, "", e.Password)
+		case *instance.SMTPConfigRemovedEvent:
+			wm.removeSecret(e, "")
+		case *idp.OAuthIDPAddedEvent:
+			wm.addSecret(e, e.ID, e.ClientSecret, reencryptOAuthIDP(e.ID))
+		case *idp.OAuthIDPChangedEvent:
+			wm.changeSecret(e, e.ID, e.ClientSecret)
+		case *idp.OIDCIDPAddedEvent:
+			wm.addSecret(e, e.ID, e.ClientSecret, reencryptOIDCIDP(e.ID))
+		case *idp.OIDCIDPChangedEvent:
+			wm.changeSecret(e, e.ID, e.ClientSecret)
+		case *idp.AzureADIDPAddedEvent:
+			wm.addSecret(e, e.ID, e.ClientSecret, reencryptAzureADIDP(e.ID))
+		case *idp.AzureADIDPChangedEvent:
+			wm.changeSecret(e, e.ID, e.ClientSecret)
+		case *idp.GitHubIDPAddedEvent:
+			wm.addSecret(e, e.ID, e.ClientSecret, reencryptGitHubIDP(e.ID))
+		case *idp.GitHubIDPChangedEvent:
+			wm.changeSecret(e, e.ID, e.ClientSecret)
+		case *idp.GitHubEnterpriseIDPAddedEvent:
+			wm.addSecret(e, e.ID, e.ClientSecret, reencryptGitHubEnterpriseIDP(e.ID))
+		case *idp.GitHubEnterpriseIDPChangedEvent:
+			wm.changeSecret(e, e.ID, e.ClientSecret)
+		case *idp.GitLabIDPAddedEvent:
+			wm.addSecret(e, e.ID, e.ClientSecret, reencryptGitLabIDP(e.ID))
+		case *idp.GitLabIDPChangedEvent:
+			wm.changeSecret(e, e.ID, e.ClientSecret)
+		case *idp.GitLabSelfHostedIDPAddedEvent:
+			wm.addSecret(e, e.ID, e.ClientSecret, reencryptGitLabSelfHostedIDP(e.ID))
+		case *idp.GitLabSelfHostedIDPChangedEvent:
+			wm.changeSecret(e, e.ID, e.ClientSecret)
+		case *idp.GoogleIDPAddedEvent:
+			wm.addSecret(e, e.ID, e.ClientSecret, reencryptGoogleIDP(e.ID))
+		case *idp.GoogleIDPChangedEvent:
+			wm.changeSecret(e, e.ID, e.ClientSecret)
+		case *idp.LDAPIDPAddedEvent:
+			wm.addSecret(e, e.ID, e.BindPassword, reencryptLDAPIDP(e.ID))
+		case *idp.LDAPIDPChangedEvent:
+			wm.changeSecret(e, e.ID, e.BindPassword)
+		case *idp.SAMLIDPAddedEvent:
+			wm.addSecret(e, e.ID, e.Key, reencryptSAMLIDP(e.ID))
+		case *idp.SAMLIDPChangedEvent:
+			wm.changeSecret(e, e.ID, e.Key)
+		case *idp.OIDCIDPMigratedAzureADEvent:
+			wm.addSecret(e, e.ID, e.ClientSecret, reencryptAzureADIDP(e.ID))
+		case *idp.OIDCIDPMigratedGoogleEvent:
+			wm.addSecret(e, e.ID, e.ClientSecret, reencryptGoogleIDP(e.ID))
+		case *idp.RemovedEvent:
+			wm.removeSecret(e, e.ID)
+		case *idpconfig.OIDCConfigAddedEvent:
+			wm.addSecret(e, e.IDPConfigID, e.ClientSecret, reencryptOIDCConfig(e.IDPConfigID))
+		case *idpconfig.OIDCConfigChangedEvent:
+			wm.changeSecret(e, e.IDPConfigID, e.ClientSecret)
+		case *idpconfig.IDPConfigRemovedEvent:
+			wm.removeSecret(e, e.ConfigID)
+		case *org.OrgRemovedEvent:
+			wm.removeAggregate(e)
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+// Query returns the next page of events after the processed sequence
+func (wm *EncryptedSecretsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID(wm.InstanceID).
+		OrderAsc().
+		Limit(encryptedSecretsPageSize).
+		AddQuery().
+		SequenceGreater(wm.ProcessedSequence)
+	switch wm.purpose {
+	case crypto.KeyPurposeOTP:
+		query = query.AggregateTypes(user.AggregateType).
+			EventTypes(
+				user.HumanMFAOTPAddedType,
+				user.HumanMFAOTPSecretReencryptedType,
+				user.HumanMFAOTPRemovedType,
+				user.UserV1MFAOTPAddedType,
+				user.UserV1MFAOTPRemovedType,
+				user.UserRemovedType,
+			)
+	case crypto.KeyPurposeSMS:
+		query = query.AggregateTypes(instance.AggregateType).
+			EventTypes(
+				instance.SMSConfigTwilioAddedEventType,
+				instance.SMSConfigTwilioTokenChangedEventType,
+				instance.SMSConfigRemovedEventType,
+			)
+	case crypto.KeyPurposeSMTP:
+		query = query.AggregateTypes(instance.AggregateType).
+			EventTypes(
+				instance.SMTPConfigAddedEventType,
+				instance.SMTPConfigPasswordChangedEventType,
+				instance.SMTPConfigRemovedEventType,
+			)
+	case crypto.KeyPurposeIDPConfig:
+		query = query.AggregateTypes(instance.AggregateType).
+			EventTypes(
+				instance.OAuthIDPAddedEventType,
+				instance.OAuthIDPChangedEventType,
+				instance.OIDCIDPAddedEventType,
+				instance.OIDCIDPChangedEventType,
+				instance.AzureADIDPAddedEventType,
+				instance.AzureADIDPChangedEventType,
+				instance.GitHubIDPAddedEventType,
+				instance.GitHubIDPChangedEventType,
+				instance.GitHubEnterpriseIDPAddedEventType,
+				instance.GitHubEnterpriseIDPChangedEventType,
+				instance.GitLabIDPAddedEventType,
+				instance.GitLabIDPChangedEventType,
+				instance.GitLabSelfHostedIDPAddedEventType,
+				instance.GitLabSelfHostedIDPChangedEventType,
+				instance.GoogleIDPAddedEventType,
+				instance.GoogleIDPChangedEventType,
+				instance.LDAPIDPAddedEventType,
+				instance.LDAPIDPChangedEventType,
+				instance.SAMLIDPAddedEventType,
+				instance.SAMLIDPChangedEventType,
+				instance.OIDCIDPMigratedAzureADEventType,
+				instance.OIDCIDPMigratedGoogleEventType,
+				instance.IDPRemovedEventType,
+				instance.IDPOIDCConfigAddedEventType,
+				instance.IDPOIDCConfigChangedEventType,
+				instance.IDPConfigRemovedEventType,
+			).
+			Or().
+			SequenceGreater(wm.ProcessedSequence).
+			AggregateTypes(org.AggregateType).
+			EventTypes(
+				org.OAuthIDPAddedEventType,
+				org.OAuthIDPChangedEventType,
+				org.OIDCIDPAddedEventType,
+				org.OIDCIDPChangedEventType,
+				org.AzureADIDPAddedEventType,
+				org.AzureADIDPChangedEventType,
+				org.GitHubIDPAddedEventType,
+				org.GitHubIDPChangedEventType,
+				org.GitHubEnterpriseIDPAddedEventType,
+				org.GitHubEnterpriseIDPChangedEventType,
+				org.GitLabIDPAddedEventType,
+				org.GitLabIDPChangedEventType,
+				org.GitLabSelfHostedIDPAddedEventType,
+				org.GitLabSelfHostedIDPChangedEventType,
+				org.GoogleIDPAddedEventType,
+				org.GoogleIDPChangedEventType,
+				org.LDAPIDPAddedEventType,
+				org.LDAPIDPChangedEventType,
+				org.SAMLIDPAddedEventType,
+				org.SAMLIDPChangedEventType,
+				org.OIDCIDPMigratedAzureADEventType,
+				org.OIDCIDPMigratedGoogleEventType,
+				org.IDPRemovedEventType,
+				org.IDPOIDCConfigAddedEventType,
+				org.IDPOIDCConfigChangedEventType,
+				org.IDPConfigRemovedEventType,
+				org.OrgRemovedEventType,
+			)
+	}
+	return query.Builder()
+}
+
+// outdated returns the secrets which aren't encrypted with the key,
+// ordered by their aggregate and id to re-encrypt them in a stable order
+func (wm *EncryptedSecretsWriteModel) outdated(keyID string) []*encryptedSecret {
+	keys := make([]string, 0, len(wm.secrets))
+	for key, secret := range wm.secrets {
+		if secret.value.KeyID != keyID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	outdated := make([]*encryptedSecret, len(keys))
+	for i, key := range keys {
+		outdated[i] = wm.secrets[key]
+	}
+	return outdated
+}
+
+func secretKey(event eventstore.Event, id string) string {
+	return event.Aggregate().ID + "/" + id
+}
+
+func (wm *EncryptedSecretsWriteModel) addSecret(event eventstore.Event, id string, value *crypto.CryptoValue, reencrypt reencryptSecret) {
+	if value == nil || value.CryptoType != crypto.TypeEncryption {
+		delete(wm.secrets, secretKey(event, id))
+		return
+	}
+	aggregate := event.Aggregate()
+	wm.secrets[secretKey(event, id)] = &encryptedSecret{
+		aggregate: &aggregate,
+		value:     value,
+		reencrypt: reencrypt,
+	}
+}
+
+func (wm *EncryptedSecretsWriteModel) changeSecret(event eventstore.Event, id string, value *crypto.CryptoValue) {
+	secret, ok := wm.secrets[secretKey(event, id)]
+	if !ok || value == nil {
+		return
+	}
+	secret.value = value
+}
+
+func (wm *EncryptedSecretsWriteModel) removeSecret(event eventstore.Event, id string) {
+	if id != "" {
+		delete(wm.secrets, secretKey(event, id))
+		return
+	}
+	// the secret is the only one of the aggregate
+	wm.removeAggregate(event)
+}
+
+func (wm *EncryptedSecretsWriteModel) removeAggregate(event eventstore.Event) {
+	prefix := secretKey(event, "")
+	for key := range wm.secrets {
+		if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
+			delete(wm.secrets, key)
+		}
+	}
+}
+
+func reencryptOTPSecret(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+	return user.NewHumanOTPSecretReencryptedEvent(ctx, aggregate, secret), nil
+}
+
+func reencryptSMTPPassword(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+	return instance.NewSMTPConfigPasswordChangedEvent(ctx, aggregate, secret), nil
+}
+
+func reencryptSMSToken(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		return instance.NewSMSConfigTokenChangedEvent(ctx, aggregate, id, secret), nil
+	}
+}
+
+func reencryptOIDCConfig(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idpconfig.OIDCConfigChanges{idpconfig.ChangeClientSecret(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewIDPOIDCConfigChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewIDPOIDCConfigChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+func reencryptOAuthIDP(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idp.OAuthIDPChanges{idp.ChangeOAuthClientSecret(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewOAuthIDPChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewOAuthIDPChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+func reencryptOIDCIDP(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idp.OIDCIDPChanges{idp.ChangeOIDCClientSecret(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewOIDCIDPChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewOIDCIDPChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+func reencryptAzureADIDP(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idp.AzureADIDPChanges{idp.ChangeAzureADClientSecret(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewAzureADIDPChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewAzureADIDPChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+func reencryptGitHubIDP(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idp.GitHubIDPChanges{idp.ChangeGitHubClientSecret(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewGitHubIDPChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewGitHubIDPChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+func reencryptGitHubEnterpriseIDP(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idp.GitHubEnterpriseIDPChanges{idp.ChangeGitHubEnterpriseClientSecret(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewGitHubEnterpriseIDPChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewGitHubEnterpriseIDPChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+func reencryptGitLabIDP(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idp.GitLabIDPChanges{idp.ChangeGitLabClientSecret(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewGitLabIDPChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewGitLabIDPChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+func reencryptGitLabSelfHostedIDP(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idp.GitLabSelfHostedIDPChanges{idp.ChangeGitLabSelfHostedClientSecret(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewGitLabSelfHostedIDPChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewGitLabSelfHostedIDPChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+func reencryptGoogleIDP(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idp.GoogleIDPChanges{idp.ChangeGoogleClientSecret(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewGoogleIDPChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewGoogleIDPChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+func reencryptLDAPIDP(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idp.LDAPIDPChanges{idp.ChangeLDAPBindPassword(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewLDAPIDPChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewLDAPIDPChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+func reencryptSAMLIDP(id string) reencryptSecret {
+	return func(ctx context.Context, aggregate *eventstore.Aggregate, secret *crypto.CryptoValue) (eventstore.Command, error) {
+		changes := []idp.SAMLIDPChanges{idp.ChangeSAMLKey(secret)}
+		if aggregate.Type == org.AggregateType {
+			return org.NewSAMLIDPChangedEvent(ctx, aggregate, id, changes)
+		}
+		return instance.NewSAMLIDPChangedEvent(ctx, aggregate, id, changes)
+	}
+}
+
+// KeyPairsWriteModel counts the oidc signing keys which are not yet expired
+// and still encrypted with other keys than the current one.
+// They are not re-encrypted but replaced by new keys as soon as they expire.
+type KeyPairsWriteModel struct {
+	eventstore.WriteModel
+
+	keyID     string
+	now       time.Time
+	lifetime  time.Duration
+	Remaining uint64
+}
+
+// NewKeyPairsWriteModel only queries the keys created within the lifetime,
+// as all older keys are expired
+func NewKeyPairsWriteModel(keyID, instanceID string, lifetime time.Duration) *KeyPairsWriteModel {
+	return &KeyPairsWriteModel{
+		WriteModel: eventstore.WriteModel{
+			InstanceID: instanceID,
+		},
+		keyID:    keyID,
+		now:      time.Now(),
+		lifetime: lifetime,
+	}
+}
+
+func (wm *KeyPairsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		e, ok := event.(*keypair.AddedEvent)
+		if !ok {
+			continue
+		}
+		if wm.outdated(e.PrivateKey) || wm.outdated(e.PublicKey) {
+			wm.Remaining++
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *KeyPairsWriteModel) outdated(key *keypair.Key) bool {
+	return key != nil && key.Key != nil && key.Key.KeyID != wm.keyID && key.Expiry.After(wm.now)
+}
+
+func (wm *KeyPairsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID(wm.InstanceID).
+		AddQuery().
+		AggregateTypes(keypair.AggregateType).
+		EventTypes(keypair.AddedEventType).
+		CreationDateAfter(wm.now.Add(-wm.lifetime)).
+		Builder()
+}
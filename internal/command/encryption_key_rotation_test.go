@@ -0,0 +1,310 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+// rotatedEncryptionAlg encrypts with the key "new" and decrypts values of the keys "new" and "old"
+func rotatedEncryptionAlg(ctrl *gomock.Controller) crypto.EncryptionAlgorithm {
+	mCrypto := crypto.NewMockEncryptionAlgorithm(ctrl)
+	mCrypto.EXPECT().Algorithm().AnyTimes().Return("enc")
+	mCrypto.EXPECT().EncryptionKeyID().AnyTimes().Return("new")
+	mCrypto.EXPECT().DecryptionKeyIDs().AnyTimes().Return([]string{"new", "old"})
+	mCrypto.EXPECT().Encrypt(gomock.Any()).AnyTimes().DoAndReturn(
+		func(value []byte) ([]byte, error) {
+			return value, nil
+		},
+	)
+	mCrypto.EXPECT().Decrypt(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(value []byte, keyID string) ([]byte, error) {
+			return value, nil
+		},
+	)
+	return mCrypto
+}
+
+func encryptedValue(keyID, value string) *crypto.CryptoValue {
+	return &crypto.CryptoValue{
+		CryptoType: crypto.TypeEncryption,
+		Algorithm:  "enc",
+		KeyID:      keyID,
+		Crypted:    []byte(value),
+	}
+}
+
+func TestCommands_ReencryptSecrets(t *testing.T) {
+	type fields struct {
+		eventstore *eventstore.Eventstore
+		smtpAlg    crypto.EncryptionAlgorithm
+		smsAlg     crypto.EncryptionAlgorithm
+		otpAlg     crypto.EncryptionAlgorithm
+	}
+	type args struct {
+		purpose  crypto.KeyPurpose
+		limit    int
+		pageSize uint64
+	}
+	type res struct {
+		remaining uint64
+		err       func(error) bool
+	}
+	ctx := authz.WithInstanceID(context.Background(), "INSTANCE")
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "no encryption, precondition error",
+			fields: fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args: args{
+				purpose: crypto.KeyPurposeSMS,
+			},
+			res: res{
+				err: caos_errs.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "smtp password of previous key, re-encrypted",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewSMTPConfigAddedEvent(ctx,
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								true, "from", "name", "host:587", "user",
+								encryptedValue("old", "password"),
+							),
+						),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID("INSTANCE",
+								instance.NewSMTPConfigPasswordChangedEvent(ctx,
+									&instance.NewAggregate("INSTANCE").Aggregate,
+									encryptedValue("new", "password"),
+								),
+							),
+						},
+					),
+				),
+				smtpAlg: rotatedEncryptionAlg(gomock.NewController(t)),
+			},
+			args: args{
+				purpose: crypto.KeyPurposeSMTP,
+			},
+			res: res{
+				remaining: 0,
+			},
+		},
+		{
+			name: "sms tokens, limited and current skipped",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewSMSConfigTwilioAddedEvent(ctx,
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								"sms1", "sid", "number",
+								encryptedValue("old", "token1"),
+							),
+						),
+						eventFromEventPusher(
+							instance.NewSMSConfigTwilioAddedEvent(ctx,
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								"sms2", "sid", "number",
+								encryptedValue("old", "token2"),
+							),
+						),
+						eventFromEventPusher(
+							instance.NewSMSConfigTwilioAddedEvent(ctx,
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								"sms3", "sid", "number",
+								encryptedValue("old", "token3"),
+							),
+						),
+						eventFromEventPusher(
+							instance.NewSMSConfigTokenChangedEvent(ctx,
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								"sms3",
+								encryptedValue("new", "token3"),
+							),
+						),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID("INSTANCE",
+								instance.NewSMSConfigTokenChangedEvent(ctx,
+									&instance.NewAggregate("INSTANCE").Aggregate,
+									"sms1",
+									encryptedValue("new", "token1"),
+								),
+							),
+						},
+					),
+				),
+				smsAlg: rotatedEncryptionAlg(gomock.NewController(t)),
+			},
+			args: args{
+				purpose: crypto.KeyPurposeSMS,
+				limit:   1,
+			},
+			res: res{
+				remaining: 1,
+			},
+		},
+		{
+			name: "otp secret of removed user, nothing to re-encrypt",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							user.NewHumanOTPAddedEvent(ctx,
+								&user.NewAggregate("user1", "org1").Aggregate,
+								encryptedValue("old", "secret"),
+							),
+						),
+						eventFromEventPusher(
+							user.NewUserRemovedEvent(ctx,
+								&user.NewAggregate("user1", "org1").Aggregate,
+								"username",
+								nil,
+								true,
+							),
+						),
+					),
+				),
+				otpAlg: rotatedEncryptionAlg(gomock.NewController(t)),
+			},
+			args: args{
+				purpose: crypto.KeyPurposeOTP,
+			},
+			res: res{
+				remaining: 0,
+			},
+		},
+		{
+			name: "otp secret, re-encrypted",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							user.NewHumanOTPAddedEvent(ctx,
+								&user.NewAggregate("user1", "org1").Aggregate,
+								encryptedValue("old", "secret"),
+							),
+						),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID("INSTANCE",
+								user.NewHumanOTPSecretReencryptedEvent(ctx,
+									&user.NewAggregate("user1", "org1").Aggregate,
+									encryptedValue("new", "secret"),
+								),
+							),
+						},
+					),
+				),
+				otpAlg: rotatedEncryptionAlg(gomock.NewController(t)),
+			},
+			args: args{
+				purpose: crypto.KeyPurposeOTP,
+			},
+			res: res{
+				remaining: 0,
+			},
+		},
+		{
+			name: "otp secrets filtered in pages, re-encrypted",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							user.NewHumanOTPAddedEvent(ctx,
+								&user.NewAggregate("user1", "org1").Aggregate,
+								encryptedValue("old", "secret1"),
+							),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							user.NewHumanOTPAddedEvent(ctx,
+								&user.NewAggregate("user2", "org1").Aggregate,
+								encryptedValue("old", "secret2"),
+							),
+						),
+					),
+					expectFilter(),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID("INSTANCE",
+								user.NewHumanOTPSecretReencryptedEvent(ctx,
+									&user.NewAggregate("user1", "org1").Aggregate,
+									encryptedValue("new", "secret1"),
+								),
+							),
+							eventFromEventPusherWithInstanceID("INSTANCE",
+								user.NewHumanOTPSecretReencryptedEvent(ctx,
+									&user.NewAggregate("user2", "org1").Aggregate,
+									encryptedValue("new", "secret2"),
+								),
+							),
+						},
+					),
+				),
+				otpAlg: rotatedEncryptionAlg(gomock.NewController(t)),
+			},
+			args: args{
+				purpose:  crypto.KeyPurposeOTP,
+				pageSize: 1,
+			},
+			res: res{
+				remaining: 0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.args.pageSize > 0 {
+				defaultPageSize := encryptedSecretsPageSize
+				encryptedSecretsPageSize = tt.args.pageSize
+				defer func() { encryptedSecretsPageSize = defaultPageSize }()
+			}
+			r := &Commands{
+				eventstore:     tt.fields.eventstore,
+				smtpEncryption: tt.fields.smtpAlg,
+				smsEncryption:  tt.fields.smsAlg,
+				multifactors: domain.MultifactorConfigs{
+					OTP: domain.OTPConfig{
+						CryptoMFA: tt.fields.otpAlg,
+					},
+				},
+			}
+			remaining, err := r.ReencryptSecrets(ctx, tt.args.purpose, tt.args.limit)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			assert.Equal(t, tt.res.remaining, remaining)
+		})
+	}
+}
@@ -2,10 +2,17 @@ package command
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"math/big"
+	"strings"
 	"time"
 
+	saml_xml "github.com/zitadel/saml/pkg/provider/xml"
+
 	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/command/preparation"
+	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/errors"
 	"github.com/zitadel/zitadel/internal/repository/idp"
 )
@@ -107,6 +114,26 @@ type LDAPProvider struct {
 	UserFilters       []string
 	Timeout           time.Duration
 	LDAPAttributes    idp.LDAPAttributes
+	LDAPGroupSync     idp.LDAPGroupSync
+	IDPOptions        idp.Options
+}
+
+// validLDAPGroupMappings checks that every mapping grants at least one role of a project to a group
+func validLDAPGroupMappings(mappings []idp.LDAPGroupMapping) bool {
+	for _, mapping := range mappings {
+		if strings.TrimSpace(mapping.Group) == "" || mapping.ProjectID == "" || len(mapping.Roles) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+type SAMLProvider struct {
+	Name              string
+	Metadata          []byte
+	MetadataURL       string
+	Binding           string
+	WithSignedRequest bool
 	IDPOptions        idp.Options
 }
 
@@ -174,3 +201,44 @@ func IDPProviderWriteModel(ctx context.Context, filter preparation.FilterToQuery
 
 	return allWriteModel, err
 }
+
+// samlMetadata returns the metadata of the SAML provider, either directly provided or loaded from the URL
+func (c *Commands) samlMetadata(provider SAMLProvider) ([]byte, error) {
+	metadata := provider.Metadata
+	if len(metadata) == 0 {
+		var err error
+		metadata, err = saml_xml.ReadMetadataFromURL(c.httpClient, provider.MetadataURL)
+		if err != nil {
+			return nil, errors.ThrowPreconditionFailed(err, "COMMAND-Sfg3r", "Errors.IDPConfig.SAMLMetadataNotReachable")
+		}
+	}
+	entityDescriptor, err := saml_xml.ParseMetadataXmlIntoStruct(metadata)
+	if err != nil || entityDescriptor.IDPSSODescriptor == nil {
+		return nil, errors.ThrowInvalidArgument(err, "COMMAND-Bw31h", "Errors.IDPConfig.SAMLMetadataInvalid")
+	}
+	return metadata, nil
+}
+
+// samlCertificateAndKeyGenerator returns a function creating a self-signed certificate and private key (both PEM encoded)
+// the service provider uses to sign its requests and to decrypt the assertions
+func samlCertificateAndKeyGenerator(keySize int, lifetime time.Duration) func(id string) ([]byte, []byte, error) {
+	return func(id string) ([]byte, []byte, error) {
+		serialNumber, err := rand.Int(rand.Reader, big.NewInt(1000))
+		if err != nil {
+			return nil, nil, err
+		}
+		now := time.Now().UTC()
+		privateKey, _, certificate, err := crypto.GenerateCACertificate(keySize, &crypto.CertificateInformations{
+			SerialNumber: serialNumber,
+			Organisation: []string{"ZITADEL"},
+			CommonName:   id,
+			NotBefore:    now,
+			NotAfter:     now.Add(lifetime),
+			KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return crypto.PrivateKeyToBytes(privateKey), certificate, nil
+	}
+}
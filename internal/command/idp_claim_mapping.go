@@ -0,0 +1,126 @@
+package command
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/command/preparation"
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/idp"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
+)
+
+// SetInstanceIDPClaimMappings replaces the claim mappings of the identity provider of the instance.
+// An empty list removes all mappings.
+func (c *Commands) SetInstanceIDPClaimMappings(ctx context.Context, id string, mappings []*domain.IDPClaimMapping) (*domain.ObjectDetails, error) {
+	instanceAgg := instance.NewAggregate(authz.GetInstance(ctx).InstanceID())
+	writeModel := NewInstanceIDPClaimMappingsWriteModel(instanceAgg.InstanceID, id)
+	cmds, err := preparation.PrepareCommands(ctx, c.eventstore.Filter, c.prepareSetInstanceIDPClaimMappings(instanceAgg, writeModel, mappings))
+	if err != nil {
+		return nil, err
+	}
+	if len(cmds) == 0 {
+		return writeModelToObjectDetails(&writeModel.WriteModel), nil
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, cmds...)
+	if err != nil {
+		return nil, err
+	}
+	return pushedEventsToObjectDetails(pushedEvents), nil
+}
+
+// SetOrgIDPClaimMappings replaces the claim mappings of the identity provider of the organisation.
+// An empty list removes all mappings.
+func (c *Commands) SetOrgIDPClaimMappings(ctx context.Context, resourceOwner, id string, mappings []*domain.IDPClaimMapping) (*domain.ObjectDetails, error) {
+	orgAgg := org.NewAggregate(resourceOwner)
+	writeModel := NewOrgIDPClaimMappingsWriteModel(resourceOwner, id)
+	cmds, err := preparation.PrepareCommands(ctx, c.eventstore.Filter, c.prepareSetOrgIDPClaimMappings(orgAgg, writeModel, mappings))
+	if err != nil {
+		return nil, err
+	}
+	if len(cmds) == 0 {
+		return writeModelToObjectDetails(&writeModel.WriteModel), nil
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, cmds...)
+	if err != nil {
+		return nil, err
+	}
+	return pushedEventsToObjectDetails(pushedEvents), nil
+}
+
+func (c *Commands) prepareSetInstanceIDPClaimMappings(a *instance.Aggregate, writeModel *InstanceIDPClaimMappingsWriteModel, mappings []*domain.IDPClaimMapping) preparation.Validation {
+	return func() (preparation.CreateCommands, error) {
+		if writeModel.ID = strings.TrimSpace(writeModel.ID); writeModel.ID == "" {
+			return nil, caos_errs.ThrowInvalidArgument(nil, "INST-Cm2lq", "Errors.IDMissing")
+		}
+		if err := validateIDPClaimMappings(mappings); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, filter preparation.FilterToQueryReducer) ([]eventstore.Command, error) {
+			events, err := filter(ctx, writeModel.Query())
+			if err != nil {
+				return nil, err
+			}
+			writeModel.AppendEvents(events...)
+			if err = writeModel.Reduce(); err != nil {
+				return nil, err
+			}
+			if !writeModel.State.Exists() {
+				return nil, caos_errs.ThrowNotFound(nil, "INST-Cm3ls", "Errors.IDPConfig.NotExisting")
+			}
+			if !idpClaimMappingsChanged(writeModel.Mappings, mappings) {
+				return nil, nil
+			}
+			return []eventstore.Command{instance.NewIDPClaimMappingsSetEvent(ctx, &a.Aggregate, writeModel.ID, mappings)}, nil
+		}, nil
+	}
+}
+
+func (c *Commands) prepareSetOrgIDPClaimMappings(a *org.Aggregate, writeModel *OrgIDPClaimMappingsWriteModel, mappings []*domain.IDPClaimMapping) preparation.Validation {
+	return func() (preparation.CreateCommands, error) {
+		if writeModel.ID = strings.TrimSpace(writeModel.ID); writeModel.ID == "" {
+			return nil, caos_errs.ThrowInvalidArgument(nil, "ORG-Cm2lq", "Errors.IDMissing")
+		}
+		if err := validateIDPClaimMappings(mappings); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, filter preparation.FilterToQueryReducer) ([]eventstore.Command, error) {
+			events, err := filter(ctx, writeModel.Query())
+			if err != nil {
+				return nil, err
+			}
+			writeModel.AppendEvents(events...)
+			if err = writeModel.Reduce(); err != nil {
+				return nil, err
+			}
+			if !writeModel.State.Exists() {
+				return nil, caos_errs.ThrowNotFound(nil, "ORG-Cm3ls", "Errors.Org.IDPConfig.NotExisting")
+			}
+			if !idpClaimMappingsChanged(writeModel.Mappings, mappings) {
+				return nil, nil
+			}
+			return []eventstore.Command{org.NewIDPClaimMappingsSetEvent(ctx, &a.Aggregate, writeModel.ID, mappings)}, nil
+		}, nil
+	}
+}
+
+func validateIDPClaimMappings(mappings []*domain.IDPClaimMapping) error {
+	for _, mapping := range mappings {
+		if !mapping.IsValid() || !idp.ValidClaimPath(mapping.Claim) {
+			return caos_errs.ThrowInvalidArgument(nil, "COMMAND-Cm9wk", "Errors.IDPConfig.ClaimMappingInvalid")
+		}
+	}
+	return nil
+}
+
+func idpClaimMappingsChanged(existing, mappings []*domain.IDPClaimMapping) bool {
+	if len(existing) == 0 && len(mappings) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(existing, mappings)
+}
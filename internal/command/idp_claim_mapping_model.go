@@ -0,0 +1,218 @@
+package command
+
+import (
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/idp"
+	"github.com/zitadel/zitadel/internal/repository/idpconfig"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
+)
+
+// IDPClaimMappingsWriteModel contains the claim mappings of an identity provider (template or old config)
+// and its state, which is reduced by the embedded [IDPRemoveWriteModel]
+type IDPClaimMappingsWriteModel struct {
+	IDPRemoveWriteModel
+
+	Mappings []*domain.IDPClaimMapping
+}
+
+func (wm *IDPClaimMappingsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *idp.ClaimMappingsSetEvent:
+			if e.ID == wm.ID {
+				wm.Mappings = e.Mappings
+			}
+		case *idp.RemovedEvent:
+			if e.ID == wm.ID {
+				wm.Mappings = nil
+			}
+		case *idpconfig.IDPConfigRemovedEvent:
+			if e.ConfigID == wm.ID {
+				wm.Mappings = nil
+			}
+		}
+	}
+	return wm.IDPRemoveWriteModel.Reduce()
+}
+
+type InstanceIDPClaimMappingsWriteModel struct {
+	IDPClaimMappingsWriteModel
+}
+
+func NewInstanceIDPClaimMappingsWriteModel(instanceID, id string) *InstanceIDPClaimMappingsWriteModel {
+	return &InstanceIDPClaimMappingsWriteModel{
+		IDPClaimMappingsWriteModel{
+			IDPRemoveWriteModel: IDPRemoveWriteModel{
+				WriteModel: eventstore.WriteModel{
+					AggregateID:   instanceID,
+					ResourceOwner: instanceID,
+				},
+				ID: id,
+			},
+		},
+	}
+}
+
+func (wm *InstanceIDPClaimMappingsWriteModel) AppendEvents(events ...eventstore.Event) {
+	for _, event := range events {
+		switch e := event.(type) {
+		case *instance.IDPClaimMappingsSetEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.ClaimMappingsSetEvent)
+		case *instance.OAuthIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.OAuthIDPAddedEvent)
+		case *instance.OIDCIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.OIDCIDPAddedEvent)
+		case *instance.JWTIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.JWTIDPAddedEvent)
+		case *instance.AzureADIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.AzureADIDPAddedEvent)
+		case *instance.GitHubIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.GitHubIDPAddedEvent)
+		case *instance.GitHubEnterpriseIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.GitHubEnterpriseIDPAddedEvent)
+		case *instance.GitLabIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.GitLabIDPAddedEvent)
+		case *instance.GitLabSelfHostedIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.GitLabSelfHostedIDPAddedEvent)
+		case *instance.GoogleIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.GoogleIDPAddedEvent)
+		case *instance.LDAPIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.LDAPIDPAddedEvent)
+		case *instance.SAMLIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.SAMLIDPAddedEvent)
+		case *instance.IDPRemovedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.RemovedEvent)
+		case *instance.IDPConfigAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.IDPConfigAddedEvent)
+		case *instance.IDPConfigRemovedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.IDPConfigRemovedEvent)
+		default:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(e)
+		}
+	}
+}
+
+func (wm *InstanceIDPClaimMappingsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			instance.OAuthIDPAddedEventType,
+			instance.OIDCIDPAddedEventType,
+			instance.JWTIDPAddedEventType,
+			instance.AzureADIDPAddedEventType,
+			instance.GitHubIDPAddedEventType,
+			instance.GitHubEnterpriseIDPAddedEventType,
+			instance.GitLabIDPAddedEventType,
+			instance.GitLabSelfHostedIDPAddedEventType,
+			instance.GoogleIDPAddedEventType,
+			instance.LDAPIDPAddedEventType,
+			instance.SAMLIDPAddedEventType,
+			instance.IDPRemovedEventType,
+			instance.IDPClaimMappingsSetEventType,
+		).
+		EventData(map[string]interface{}{"id": wm.ID}).
+		Or(). // old events
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			instance.IDPConfigAddedEventType,
+			instance.IDPConfigRemovedEventType,
+		).
+		EventData(map[string]interface{}{"idpConfigId": wm.ID}).
+		Builder()
+}
+
+type OrgIDPClaimMappingsWriteModel struct {
+	IDPClaimMappingsWriteModel
+}
+
+func NewOrgIDPClaimMappingsWriteModel(orgID, id string) *OrgIDPClaimMappingsWriteModel {
+	return &OrgIDPClaimMappingsWriteModel{
+		IDPClaimMappingsWriteModel{
+			IDPRemoveWriteModel: IDPRemoveWriteModel{
+				WriteModel: eventstore.WriteModel{
+					AggregateID:   orgID,
+					ResourceOwner: orgID,
+				},
+				ID: id,
+			},
+		},
+	}
+}
+
+func (wm *OrgIDPClaimMappingsWriteModel) AppendEvents(events ...eventstore.Event) {
+	for _, event := range events {
+		switch e := event.(type) {
+		case *org.IDPClaimMappingsSetEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.ClaimMappingsSetEvent)
+		case *org.OAuthIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.OAuthIDPAddedEvent)
+		case *org.OIDCIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.OIDCIDPAddedEvent)
+		case *org.JWTIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.JWTIDPAddedEvent)
+		case *org.AzureADIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.AzureADIDPAddedEvent)
+		case *org.GitHubIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.GitHubIDPAddedEvent)
+		case *org.GitHubEnterpriseIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.GitHubEnterpriseIDPAddedEvent)
+		case *org.GitLabIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.GitLabIDPAddedEvent)
+		case *org.GitLabSelfHostedIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.GitLabSelfHostedIDPAddedEvent)
+		case *org.GoogleIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.GoogleIDPAddedEvent)
+		case *org.LDAPIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.LDAPIDPAddedEvent)
+		case *org.SAMLIDPAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.SAMLIDPAddedEvent)
+		case *org.IDPRemovedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.RemovedEvent)
+		case *org.IDPConfigAddedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.IDPConfigAddedEvent)
+		case *org.IDPConfigRemovedEvent:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(&e.IDPConfigRemovedEvent)
+		default:
+			wm.IDPClaimMappingsWriteModel.AppendEvents(e)
+		}
+	}
+}
+
+func (wm *OrgIDPClaimMappingsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(org.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			org.OAuthIDPAddedEventType,
+			org.OIDCIDPAddedEventType,
+			org.JWTIDPAddedEventType,
+			org.AzureADIDPAddedEventType,
+			org.GitHubIDPAddedEventType,
+			org.GitHubEnterpriseIDPAddedEventType,
+			org.GitLabIDPAddedEventType,
+			org.GitLabSelfHostedIDPAddedEventType,
+			org.GoogleIDPAddedEventType,
+			org.LDAPIDPAddedEventType,
+			org.SAMLIDPAddedEventType,
+			org.IDPRemovedEventType,
+			org.IDPClaimMappingsSetEventType,
+		).
+		EventData(map[string]interface{}{"id": wm.ID}).
+		Or(). // old events
+		AggregateTypes(org.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			org.IDPConfigAddedEventType,
+			org.IDPConfigRemovedEventType,
+		).
+		EventData(map[string]interface{}{"idpConfigId": wm.ID}).
+		Builder()
+}
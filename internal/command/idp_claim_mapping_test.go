@@ -0,0 +1,372 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errors "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/repository/idp"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
+)
+
+func TestCommandSide_SetInstanceIDPClaimMappings(t *testing.T) {
+	type fields struct {
+		eventstore *eventstore.Eventstore
+	}
+	type args struct {
+		ctx      context.Context
+		id       string
+		mappings []*domain.IDPClaimMapping
+	}
+	type res struct {
+		want *domain.ObjectDetails
+		err  func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"invalid id",
+			fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "INST-Cm2lq", ""))
+				},
+			},
+		},
+		{
+			"invalid mapping",
+			fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				mappings: []*domain.IDPClaimMapping{
+					{Claim: "department", Target: domain.IDPClaimMappingTargetMetadata},
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "COMMAND-Cm9wk", ""))
+				},
+			},
+		},
+		{
+			"invalid claim path",
+			fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				mappings: []*domain.IDPClaimMapping{
+					{Claim: "$.groups[", Target: domain.IDPClaimMappingTargetRole, Key: "project1", Roles: []string{"admin"}},
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "COMMAND-Cm9wk", ""))
+				},
+			},
+		},
+		{
+			"role without allowed roles",
+			fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				mappings: []*domain.IDPClaimMapping{
+					{Claim: "groups", Target: domain.IDPClaimMappingTargetRole, Key: "project1"},
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "COMMAND-Cm9wk", ""))
+				},
+			},
+		},
+		{
+			"not found",
+			fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(),
+				),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				mappings: []*domain.IDPClaimMapping{
+					{Claim: "given_name", Target: domain.IDPClaimMappingTargetFirstName},
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowNotFound(nil, "INST-Cm3ls", ""))
+				},
+			},
+		},
+		{
+			"removed",
+			fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewJWTIDPAddedEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate,
+								"id1", "name", "issuer", "jwt", "keys", "header", idp.Options{},
+							),
+						),
+						eventFromEventPusher(
+							instance.NewIDPRemovedEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate, "id1"),
+						),
+					),
+				),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				mappings: []*domain.IDPClaimMapping{
+					{Claim: "given_name", Target: domain.IDPClaimMappingTargetFirstName},
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowNotFound(nil, "INST-Cm3ls", ""))
+				},
+			},
+		},
+		{
+			"no changes",
+			fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewJWTIDPAddedEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate,
+								"id1", "name", "issuer", "jwt", "keys", "header", idp.Options{},
+							),
+						),
+						eventFromEventPusher(
+							instance.NewIDPClaimMappingsSetEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate,
+								"id1",
+								[]*domain.IDPClaimMapping{
+									{Claim: "given_name", Target: domain.IDPClaimMappingTargetFirstName},
+								},
+							),
+						),
+					),
+				),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				mappings: []*domain.IDPClaimMapping{
+					{Claim: "given_name", Target: domain.IDPClaimMappingTargetFirstName},
+				},
+			},
+			res{
+				want: &domain.ObjectDetails{ResourceOwner: "instance1"},
+			},
+		},
+		{
+			"set ok",
+			fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewJWTIDPAddedEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate,
+								"id1", "name", "issuer", "jwt", "keys", "header", idp.Options{},
+							),
+						),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID(
+								"instance1",
+								instance.NewIDPClaimMappingsSetEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate,
+									"id1",
+									[]*domain.IDPClaimMapping{
+										{Claim: "given_name", Target: domain.IDPClaimMappingTargetFirstName},
+										{
+											Claim:      "$.groups[*]",
+											Target:     domain.IDPClaimMappingTargetRole,
+											Key:        "project1",
+											Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformLowercase},
+											Roles:      []string{"admin"},
+										},
+									},
+								),
+							),
+						},
+					),
+				),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				mappings: []*domain.IDPClaimMapping{
+					{Claim: "given_name", Target: domain.IDPClaimMappingTargetFirstName},
+					{
+						Claim:      "$.groups[*]",
+						Target:     domain.IDPClaimMappingTargetRole,
+						Key:        "project1",
+						Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformLowercase},
+						Roles:      []string{"admin"},
+					},
+				},
+			},
+			res{
+				want: &domain.ObjectDetails{ResourceOwner: "instance1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.fields.eventstore,
+			}
+			got, err := c.SetInstanceIDPClaimMappings(tt.args.ctx, tt.args.id, tt.args.mappings)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
+
+func TestCommandSide_SetOrgIDPClaimMappings(t *testing.T) {
+	type fields struct {
+		eventstore *eventstore.Eventstore
+	}
+	type args struct {
+		ctx           context.Context
+		resourceOwner string
+		id            string
+		mappings      []*domain.IDPClaimMapping
+	}
+	type res struct {
+		want *domain.ObjectDetails
+		err  func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"invalid transform",
+			fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args{
+				ctx:           context.Background(),
+				resourceOwner: "org1",
+				id:            "id1",
+				mappings: []*domain.IDPClaimMapping{
+					{Claim: "email", Target: domain.IDPClaimMappingTargetEmail, Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformUnspecified}},
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "COMMAND-Cm9wk", ""))
+				},
+			},
+		},
+		{
+			"not found",
+			fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				resourceOwner: "org1",
+				id:            "id1",
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowNotFound(nil, "ORG-Cm3ls", ""))
+				},
+			},
+		},
+		{
+			"remove mappings ok",
+			fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							org.NewJWTIDPAddedEvent(context.Background(), &org.NewAggregate("org1").Aggregate,
+								"id1", "name", "issuer", "jwt", "keys", "header", idp.Options{},
+							),
+						),
+						eventFromEventPusher(
+							org.NewIDPClaimMappingsSetEvent(context.Background(), &org.NewAggregate("org1").Aggregate,
+								"id1",
+								[]*domain.IDPClaimMapping{
+									{Claim: "department", Target: domain.IDPClaimMappingTargetMetadata, Key: "department"},
+								},
+							),
+						),
+					),
+					expectPush(
+						eventPusherToEvents(
+							org.NewIDPClaimMappingsSetEvent(context.Background(), &org.NewAggregate("org1").Aggregate,
+								"id1",
+								nil,
+							),
+						),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				resourceOwner: "org1",
+				id:            "id1",
+			},
+			res{
+				want: &domain.ObjectDetails{ResourceOwner: "org1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.fields.eventstore,
+			}
+			got, err := c.SetOrgIDPClaimMappings(tt.args.ctx, tt.args.resourceOwner, tt.args.id, tt.args.mappings)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
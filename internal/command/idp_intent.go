@@ -119,10 +119,19 @@ func (c *Commands) SucceedIDPIntent(ctx context.Context, writeModel *IDPIntentWr
 	if err != nil {
 		return "", err
 	}
+	// the mapped information is stored separately, as the user is marshalled as returned by the provider
+	var idpMappedInfo []byte
+	if mappedUser, ok := idpUser.(*idp.MappedUser); ok {
+		idpMappedInfo, err = json.Marshal(mappedUser.Information())
+		if err != nil {
+			return "", err
+		}
+	}
 	cmd := idpintent.NewSucceededEvent(
 		ctx,
 		&idpintent.NewAggregate(writeModel.AggregateID, writeModel.ResourceOwner).Aggregate,
 		idpInfo,
+		idpMappedInfo,
 		idpUser.GetID(),
 		idpUser.GetPreferredUsername(),
 		userID,
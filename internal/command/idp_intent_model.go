@@ -16,6 +16,7 @@ type IDPIntentWriteModel struct {
 	FailureURL     *url.URL
 	IDPID          string
 	IDPUser        []byte
+	IDPMappedUser  []byte
 	IDPUserID      string
 	IDPUserName    string
 	IDPAccessToken *crypto.CryptoValue
@@ -45,6 +46,8 @@ func (wm *IDPIntentWriteModel) Reduce() error {
 			wm.reduceSucceededEvent(e)
 		case *idpintent.FailedEvent:
 			wm.reduceFailedEvent(e)
+		case *idpintent.UserRemovedEvent:
+			wm.reduceUserRemovedEvent(e)
 		}
 	}
 	return wm.WriteModel.Reduce()
@@ -60,6 +63,7 @@ func (wm *IDPIntentWriteModel) Query() *eventstore.SearchQueryBuilder {
 			idpintent.StartedEventType,
 			idpintent.SucceededEventType,
 			idpintent.FailedEventType,
+			idpintent.UserRemovedEventType,
 		).
 		Builder()
 }
@@ -74,6 +78,7 @@ func (wm *IDPIntentWriteModel) reduceStartedEvent(e *idpintent.StartedEvent) {
 func (wm *IDPIntentWriteModel) reduceSucceededEvent(e *idpintent.SucceededEvent) {
 	wm.UserID = e.UserID
 	wm.IDPUser = e.IDPUser
+	wm.IDPMappedUser = e.IDPMappedUser
 	wm.IDPUserID = e.IDPUserID
 	wm.IDPUserName = e.IDPUserName
 	wm.IDPAccessToken = e.IDPAccessToken
@@ -84,3 +89,69 @@ func (wm *IDPIntentWriteModel) reduceSucceededEvent(e *idpintent.SucceededEvent)
 func (wm *IDPIntentWriteModel) reduceFailedEvent(e *idpintent.FailedEvent) {
 	wm.State = domain.IDPIntentStateFailed
 }
+
+func (wm *IDPIntentWriteModel) reduceUserRemovedEvent(e *idpintent.UserRemovedEvent) {
+	wm.IDPUser = nil
+	wm.IDPMappedUser = nil
+	wm.IDPUserName = ""
+	wm.IDPIDToken = ""
+	wm.State = domain.IDPIntentStateFailed
+}
+
+// userIDPIntentsWriteModel collects the succeeded intents of a user,
+// intents of users which were not linked yet are found by the external user ids of the links
+type userIDPIntentsWriteModel struct {
+	eventstore.WriteModel
+
+	userID          string
+	externalUserIDs []string
+
+	Intents []*eventstore.Aggregate
+}
+
+func newUserIDPIntentsWriteModel(userID string, links []*domain.UserIDPLink) *userIDPIntentsWriteModel {
+	externalUserIDs := make([]string, len(links))
+	for i, link := range links {
+		externalUserIDs[i] = link.ExternalUserID
+	}
+	return &userIDPIntentsWriteModel{
+		userID:          userID,
+		externalUserIDs: externalUserIDs,
+	}
+}
+
+func (wm *userIDPIntentsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		if _, ok := event.(*idpintent.SucceededEvent); !ok {
+			continue
+		}
+		wm.addIntent(event.Aggregate())
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *userIDPIntentsWriteModel) addIntent(aggregate eventstore.Aggregate) {
+	for _, intent := range wm.Intents {
+		if intent.ID == aggregate.ID {
+			return
+		}
+	}
+	wm.Intents = append(wm.Intents, &idpintent.NewAggregate(aggregate.ID, aggregate.ResourceOwner).Aggregate)
+}
+
+func (wm *userIDPIntentsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(idpintent.AggregateType).
+		EventTypes(idpintent.SucceededEventType).
+		EventData(map[string]interface{}{"userId": wm.userID}).
+		Builder()
+	for _, externalUserID := range wm.externalUserIDs {
+		query = query.AddQuery().
+			AggregateTypes(idpintent.AggregateType).
+			EventTypes(idpintent.SucceededEventType).
+			EventData(map[string]interface{}{"idpUserId": externalUserID}).
+			Builder()
+	}
+	return query
+}
@@ -439,6 +439,7 @@ func TestCommands_SucceedIDPIntent(t *testing.T) {
 								context.Background(),
 								&idpintent.NewAggregate("id", "ro").Aggregate,
 								[]byte(`{"sub":"id","preferred_username":"username"}`),
+								nil,
 								"id",
 								"username",
 								"",
@@ -476,6 +477,63 @@ func TestCommands_SucceedIDPIntent(t *testing.T) {
 				token: "aWQ",
 			},
 		},
+		{
+			"push, mapped user",
+			fields{
+				idpConfigEncryption: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				eventstore: eventstoreExpect(t,
+					expectPush(
+						eventPusherToEvents(
+							idpintent.NewSucceededEvent(
+								context.Background(),
+								&idpintent.NewAggregate("id", "ro").Aggregate,
+								[]byte(`{"department":"sales","preferred_username":"username","sub":"id"}`),
+								[]byte(`{"firstName":"department","preferredUsername":"username","preferredLanguage":"und","metadata":{"department":"c2FsZXM="}}`),
+								"id",
+								"username",
+								"",
+								&crypto.CryptoValue{
+									CryptoType: crypto.TypeEncryption,
+									Algorithm:  "enc",
+									KeyID:      "id",
+									Crypted:    []byte("accessToken"),
+								},
+								"idToken",
+							),
+						),
+					),
+				),
+			},
+			args{
+				ctx:        context.Background(),
+				writeModel: NewIDPIntentWriteModel("id", "ro"),
+				idpSession: &openid.Session{
+					Tokens: &oidc.Tokens[*oidc.IDTokenClaims]{
+						Token: &oauth2.Token{
+							AccessToken: "accessToken",
+						},
+						IDToken: "idToken",
+					},
+				},
+				idpUser: func() idp.User {
+					mapped, err := idp.MapUser(openid.NewUser(&oidc.UserInfo{
+						Subject: "id",
+						UserInfoProfile: oidc.UserInfoProfile{
+							PreferredUsername: "username",
+						},
+						Claims: map[string]any{"department": "sales"},
+					}), []*domain.IDPClaimMapping{
+						{Claim: "department", Target: domain.IDPClaimMappingTargetMetadata, Key: "department"},
+						{Claim: "missing", Target: domain.IDPClaimMappingTargetFirstName, Default: "department"},
+					})
+					require.NoError(t, err)
+					return mapped
+				}(),
+			},
+			res{
+				token: "aWQ",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -22,6 +22,7 @@ import (
 	"github.com/zitadel/zitadel/internal/idp/providers/ldap"
 	"github.com/zitadel/zitadel/internal/idp/providers/oauth"
 	"github.com/zitadel/zitadel/internal/idp/providers/oidc"
+	"github.com/zitadel/zitadel/internal/idp/providers/saml"
 	"github.com/zitadel/zitadel/internal/repository/idp"
 	"github.com/zitadel/zitadel/internal/repository/idpconfig"
 	"github.com/zitadel/zitadel/internal/repository/instance"
@@ -1321,6 +1322,7 @@ type LDAPIDPWriteModel struct {
 	UserFilters       []string
 	Timeout           time.Duration
 	idp.LDAPAttributes
+	idp.LDAPGroupSync
 	idp.Options
 
 	State domain.IDPState
@@ -1361,6 +1363,7 @@ func (wm *LDAPIDPWriteModel) reduceAddedEvent(e *idp.LDAPIDPAddedEvent) {
 	wm.UserFilters = e.UserFilters
 	wm.Timeout = e.Timeout
 	wm.LDAPAttributes = e.LDAPAttributes
+	wm.LDAPGroupSync = e.LDAPGroupSync
 	wm.Options = e.Options
 	wm.State = domain.IDPStateActive
 }
@@ -1400,6 +1403,7 @@ func (wm *LDAPIDPWriteModel) reduceChangedEvent(e *idp.LDAPIDPChangedEvent) {
 		wm.Timeout = *e.Timeout
 	}
 	wm.LDAPAttributes.ReduceChanges(e.LDAPAttributeChanges)
+	wm.LDAPGroupSync.ReduceChanges(e.LDAPGroupSyncChanges)
 	wm.Options.ReduceChanges(e.OptionChanges)
 }
 
@@ -1416,6 +1420,7 @@ func (wm *LDAPIDPWriteModel) NewChanges(
 	timeout time.Duration,
 	secretCrypto crypto.Crypto,
 	attributes idp.LDAPAttributes,
+	groupSync idp.LDAPGroupSync,
 	options idp.Options,
 ) ([]idp.LDAPIDPChanges, error) {
 	changes := make([]idp.LDAPIDPChanges, 0)
@@ -1459,6 +1464,10 @@ func (wm *LDAPIDPWriteModel) NewChanges(
 	if !attrs.IsZero() {
 		changes = append(changes, idp.ChangeLDAPAttributes(attrs))
 	}
+	groups := wm.LDAPGroupSync.Changes(groupSync)
+	if !groups.IsZero() {
+		changes = append(changes, idp.ChangeLDAPGroupSync(groups))
+	}
 	opts := wm.Options.Changes(options)
 	if !opts.IsZero() {
 		changes = append(changes, idp.ChangeLDAPOptions(opts))
@@ -1514,6 +1523,15 @@ func (wm *LDAPIDPWriteModel) ToProvider(callbackURL string, idpAlg crypto.Encryp
 	if wm.LDAPAttributes.ProfileAttribute != "" {
 		opts = append(opts, ldap.WithProfileAttribute(wm.LDAPAttributes.ProfileAttribute))
 	}
+	if wm.LDAPGroupSync.GroupsAttribute != "" {
+		opts = append(opts, ldap.WithGroupsAttribute(wm.LDAPGroupSync.GroupsAttribute))
+	}
+	if wm.LDAPGroupSync.GroupBase != "" {
+		opts = append(opts, ldap.WithGroupSearch(wm.LDAPGroupSync.GroupBase, wm.LDAPGroupSync.GroupMemberAttribute))
+	}
+	if wm.LDAPGroupSync.NestedGroups {
+		opts = append(opts, ldap.WithNestedGroups())
+	}
 	if wm.IsCreationAllowed {
 		opts = append(opts, ldap.WithCreationAllowed())
 	}
@@ -1541,6 +1559,141 @@ func (wm *LDAPIDPWriteModel) ToProvider(callbackURL string, idpAlg crypto.Encryp
 	), nil
 }
 
+type SAMLIDPWriteModel struct {
+	eventstore.WriteModel
+
+	ID                string
+	Name              string
+	Metadata          []byte
+	Key               *crypto.CryptoValue
+	Certificate       []byte
+	Binding           string
+	WithSignedRequest bool
+	idp.Options
+
+	State domain.IDPState
+}
+
+func (wm *SAMLIDPWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *idp.SAMLIDPAddedEvent:
+			if wm.ID != e.ID {
+				continue
+			}
+			wm.reduceAddedEvent(e)
+		case *idp.SAMLIDPChangedEvent:
+			if wm.ID != e.ID {
+				continue
+			}
+			wm.reduceChangedEvent(e)
+		case *idp.RemovedEvent:
+			if wm.ID != e.ID {
+				continue
+			}
+			wm.State = domain.IDPStateRemoved
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *SAMLIDPWriteModel) reduceAddedEvent(e *idp.SAMLIDPAddedEvent) {
+	wm.Name = e.Name
+	wm.Metadata = e.Metadata
+	wm.Key = e.Key
+	wm.Certificate = e.Certificate
+	wm.Binding = e.Binding
+	wm.WithSignedRequest = e.WithSignedRequest
+	wm.Options = e.Options
+	wm.State = domain.IDPStateActive
+}
+
+func (wm *SAMLIDPWriteModel) reduceChangedEvent(e *idp.SAMLIDPChangedEvent) {
+	if e.Name != nil {
+		wm.Name = *e.Name
+	}
+	if e.Metadata != nil {
+		wm.Metadata = e.Metadata
+	}
+	if e.Key != nil {
+		wm.Key = e.Key
+	}
+	if e.Certificate != nil {
+		wm.Certificate = e.Certificate
+	}
+	if e.Binding != nil {
+		wm.Binding = *e.Binding
+	}
+	if e.WithSignedRequest != nil {
+		wm.WithSignedRequest = *e.WithSignedRequest
+	}
+	wm.Options.ReduceChanges(e.OptionChanges)
+}
+
+func (wm *SAMLIDPWriteModel) NewChanges(
+	name string,
+	metadata []byte,
+	binding string,
+	withSignedRequest bool,
+	options idp.Options,
+) []idp.SAMLIDPChanges {
+	changes := make([]idp.SAMLIDPChanges, 0)
+	if wm.Name != name {
+		changes = append(changes, idp.ChangeSAMLName(name))
+	}
+	if len(metadata) > 0 && !reflect.DeepEqual(wm.Metadata, metadata) {
+		changes = append(changes, idp.ChangeSAMLMetadata(metadata))
+	}
+	if wm.Binding != binding {
+		changes = append(changes, idp.ChangeSAMLBinding(binding))
+	}
+	if wm.WithSignedRequest != withSignedRequest {
+		changes = append(changes, idp.ChangeSAMLWithSignedRequest(withSignedRequest))
+	}
+	opts := wm.Options.Changes(options)
+	if !opts.IsZero() {
+		changes = append(changes, idp.ChangeSAMLOptions(opts))
+	}
+	return changes
+}
+
+// ToProvider returns the SAML provider, where the callbackURL is the root URL of the SAML endpoints of the provider
+// (the metadata and assertion consumer service)
+func (wm *SAMLIDPWriteModel) ToProvider(callbackURL string, idpAlg crypto.EncryptionAlgorithm) (providers.Provider, error) {
+	key, err := crypto.Decrypt(wm.Key, idpAlg)
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]saml.ProviderOpts, 0, 6)
+	if wm.Binding != "" {
+		opts = append(opts, saml.WithBinding(wm.Binding))
+	}
+	if wm.WithSignedRequest {
+		opts = append(opts, saml.WithSignedRequest())
+	}
+	if wm.IsCreationAllowed {
+		opts = append(opts, saml.WithCreationAllowed())
+	}
+	if wm.IsLinkingAllowed {
+		opts = append(opts, saml.WithLinkingAllowed())
+	}
+	if wm.IsAutoCreation {
+		opts = append(opts, saml.WithAutoCreation())
+	}
+	if wm.IsAutoUpdate {
+		opts = append(opts, saml.WithAutoUpdate())
+	}
+	return saml.New(
+		wm.Name,
+		saml.MetadataURL(callbackURL),
+		saml.ACSURL(callbackURL),
+		wm.Metadata,
+		wm.Certificate,
+		key,
+		opts...,
+	)
+}
+
 type IDPRemoveWriteModel struct {
 	eventstore.WriteModel
 
@@ -1571,6 +1724,8 @@ func (wm *IDPRemoveWriteModel) Reduce() error {
 			wm.reduceAdded(e.ID)
 		case *idp.LDAPIDPAddedEvent:
 			wm.reduceAdded(e.ID)
+		case *idp.SAMLIDPAddedEvent:
+			wm.reduceAdded(e.ID)
 		case *idp.RemovedEvent:
 			wm.reduceRemoved(e.ID)
 		case *idpconfig.IDPConfigAddedEvent:
@@ -1653,6 +1808,10 @@ func (wm *IDPTypeWriteModel) Reduce() error {
 			wm.reduceAdded(e.ID, domain.IDPTypeLDAP, e.Aggregate())
 		case *org.LDAPIDPAddedEvent:
 			wm.reduceAdded(e.ID, domain.IDPTypeLDAP, e.Aggregate())
+		case *instance.SAMLIDPAddedEvent:
+			wm.reduceAdded(e.ID, domain.IDPTypeSAML, e.Aggregate())
+		case *org.SAMLIDPAddedEvent:
+			wm.reduceAdded(e.ID, domain.IDPTypeSAML, e.Aggregate())
 		case *instance.IDPRemovedEvent:
 			wm.reduceRemoved(e.ID)
 		case *org.IDPRemovedEvent:
@@ -1713,6 +1872,7 @@ func (wm *IDPTypeWriteModel) Query() *eventstore.SearchQueryBuilder {
 			instance.GitLabSelfHostedIDPAddedEventType,
 			instance.GoogleIDPAddedEventType,
 			instance.LDAPIDPAddedEventType,
+			instance.SAMLIDPAddedEventType,
 			instance.IDPRemovedEventType,
 		).
 		EventData(map[string]interface{}{"id": wm.ID}).
@@ -1729,6 +1889,7 @@ func (wm *IDPTypeWriteModel) Query() *eventstore.SearchQueryBuilder {
 			org.GitLabSelfHostedIDPAddedEventType,
 			org.GoogleIDPAddedEventType,
 			org.LDAPIDPAddedEventType,
+			org.SAMLIDPAddedEventType,
 			org.IDPRemovedEventType,
 		).
 		EventData(map[string]interface{}{"id": wm.ID}).
@@ -1793,6 +1954,8 @@ func NewAllIDPWriteModel(resourceOwner string, instanceBool bool, id string, idp
 			writeModel.model = NewGitLabSelfHostedInstanceIDPWriteModel(resourceOwner, id)
 		case domain.IDPTypeGoogle:
 			writeModel.model = NewGoogleInstanceIDPWriteModel(resourceOwner, id)
+		case domain.IDPTypeSAML:
+			writeModel.model = NewSAMLInstanceIDPWriteModel(resourceOwner, id)
 		case domain.IDPTypeUnspecified:
 			fallthrough
 		default:
@@ -1820,6 +1983,8 @@ func NewAllIDPWriteModel(resourceOwner string, instanceBool bool, id string, idp
 			writeModel.model = NewGitLabSelfHostedOrgIDPWriteModel(resourceOwner, id)
 		case domain.IDPTypeGoogle:
 			writeModel.model = NewGoogleOrgIDPWriteModel(resourceOwner, id)
+		case domain.IDPTypeSAML:
+			writeModel.model = NewSAMLOrgIDPWriteModel(resourceOwner, id)
 		case domain.IDPTypeUnspecified:
 			fallthrough
 		default:
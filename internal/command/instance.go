@@ -50,15 +50,25 @@ type InstanceSetup struct {
 		DomainVerification       *crypto.GeneratorConfig
 	}
 	PasswordComplexityPolicy struct {
-		MinLength    uint64
-		HasLowercase bool
-		HasUppercase bool
-		HasNumber    bool
-		HasSymbol    bool
+		MinLength      uint64
+		HasLowercase   bool
+		HasUppercase   bool
+		HasNumber      bool
+		HasSymbol      bool
+		RejectBreached bool
+		// MaxLength, the forbidden attributes and the banned words of the default policy,
+		// custom rules can only be added through the API
+		MaxLength            uint64
+		ForbidUsername       bool
+		ForbidEmailLocalPart bool
+		ForbidOrgName        bool
+		BannedWords          []string
 	}
 	PasswordAgePolicy struct {
 		ExpireWarnDays uint64
 		MaxAgeDays     uint64
+		MinAgeDays     uint64
+		HistoryCount   uint64
 	}
 	DomainPolicy struct {
 		UserLoginMustBeDomain                  bool
@@ -108,6 +118,11 @@ type InstanceSetup struct {
 	LockoutPolicy struct {
 		MaxAttempts              uint64
 		ShouldShowLockoutFailure bool
+		MaxOTPAttempts           uint64
+		MaxPasskeyAttempts       uint64
+		MaxIPAttempts            uint64
+		LockoutDuration          time.Duration
+		ProgressiveDelay         time.Duration
 	}
 	EmailTemplate     []byte
 	MessageTexts      []*domain.CustomMessageText
@@ -117,6 +132,7 @@ type InstanceSetup struct {
 		IdTokenLifetime            time.Duration
 		RefreshTokenIdleExpiration time.Duration
 		RefreshTokenExpiration     time.Duration
+		SigningKeyAlgorithm        string
 	}
 	Quotas *struct {
 		Items []*AddQuota
@@ -208,11 +224,20 @@ func (c *Commands) SetUpInstance(ctx context.Context, setup *InstanceSetup) (str
 			setup.PasswordComplexityPolicy.HasUppercase,
 			setup.PasswordComplexityPolicy.HasNumber,
 			setup.PasswordComplexityPolicy.HasSymbol,
+			setup.PasswordComplexityPolicy.RejectBreached,
+			setup.PasswordComplexityPolicy.MaxLength,
+			setup.PasswordComplexityPolicy.ForbidUsername,
+			setup.PasswordComplexityPolicy.ForbidEmailLocalPart,
+			setup.PasswordComplexityPolicy.ForbidOrgName,
+			nil,
+			setup.PasswordComplexityPolicy.BannedWords,
 		),
 		prepareAddDefaultPasswordAgePolicy(
 			instanceAgg,
 			setup.PasswordAgePolicy.ExpireWarnDays,
 			setup.PasswordAgePolicy.MaxAgeDays,
+			setup.PasswordAgePolicy.MinAgeDays,
+			setup.PasswordAgePolicy.HistoryCount,
 		),
 		prepareAddDefaultDomainPolicy(
 			instanceAgg,
@@ -245,7 +270,7 @@ func (c *Commands) SetUpInstance(ctx context.Context, setup *InstanceSetup) (str
 
 		prepareAddDefaultPrivacyPolicy(instanceAgg, setup.PrivacyPolicy.TOSLink, setup.PrivacyPolicy.PrivacyLink, setup.PrivacyPolicy.HelpLink, setup.PrivacyPolicy.SupportEmail),
 		prepareAddDefaultNotificationPolicy(instanceAgg, setup.NotificationPolicy.PasswordChange),
-		prepareAddDefaultLockoutPolicy(instanceAgg, setup.LockoutPolicy.MaxAttempts, setup.LockoutPolicy.ShouldShowLockoutFailure),
+		prepareAddDefaultLockoutPolicy(instanceAgg, setup.LockoutPolicy.MaxAttempts, setup.LockoutPolicy.ShouldShowLockoutFailure, setup.LockoutPolicy.MaxOTPAttempts, setup.LockoutPolicy.MaxPasskeyAttempts, setup.LockoutPolicy.MaxIPAttempts, setup.LockoutPolicy.LockoutDuration, setup.LockoutPolicy.ProgressiveDelay),
 
 		prepareAddDefaultLabelPolicy(
 			instanceAgg,
@@ -419,6 +444,7 @@ func (c *Commands) SetUpInstance(ctx context.Context, setup *InstanceSetup) (str
 				setup.OIDCSettings.IdTokenLifetime,
 				setup.OIDCSettings.RefreshTokenIdleExpiration,
 				setup.OIDCSettings.RefreshTokenExpiration,
+				setup.OIDCSettings.SigningKeyAlgorithm,
 			),
 		)
 	}
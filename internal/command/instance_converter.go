@@ -99,17 +99,26 @@ func writeModelToPasswordAgePolicy(wm *PasswordAgePolicyWriteModel) *domain.Pass
 		ObjectRoot:     writeModelToObjectRoot(wm.WriteModel),
 		MaxAgeDays:     wm.MaxAgeDays,
 		ExpireWarnDays: wm.ExpireWarnDays,
+		MinAgeDays:     wm.MinAgeDays,
+		HistoryCount:   wm.HistoryCount,
 	}
 }
 
 func writeModelToPasswordComplexityPolicy(wm *PasswordComplexityPolicyWriteModel) *domain.PasswordComplexityPolicy {
 	return &domain.PasswordComplexityPolicy{
-		ObjectRoot:   writeModelToObjectRoot(wm.WriteModel),
-		MinLength:    wm.MinLength,
-		HasLowercase: wm.HasLowercase,
-		HasUppercase: wm.HasUppercase,
-		HasNumber:    wm.HasNumber,
-		HasSymbol:    wm.HasSymbol,
+		ObjectRoot:           writeModelToObjectRoot(wm.WriteModel),
+		MinLength:            wm.MinLength,
+		HasLowercase:         wm.HasLowercase,
+		HasUppercase:         wm.HasUppercase,
+		HasNumber:            wm.HasNumber,
+		HasSymbol:            wm.HasSymbol,
+		RejectBreached:       wm.RejectBreached,
+		MaxLength:            wm.MaxLength,
+		ForbidUsername:       wm.ForbidUsername,
+		ForbidEmailLocalPart: wm.ForbidEmailLocalPart,
+		ForbidOrgName:        wm.ForbidOrgName,
+		Rules:                wm.Rules,
+		BannedWords:          wm.BannedWords,
 	}
 }
 
@@ -118,6 +127,11 @@ func writeModelToLockoutPolicy(wm *LockoutPolicyWriteModel) *domain.LockoutPolic
 		ObjectRoot:          writeModelToObjectRoot(wm.WriteModel),
 		MaxPasswordAttempts: wm.MaxPasswordAttempts,
 		ShowLockOutFailures: wm.ShowLockOutFailures,
+		MaxOTPAttempts:      wm.MaxOTPAttempts,
+		MaxPasskeyAttempts:  wm.MaxPasskeyAttempts,
+		MaxIPAttempts:       wm.MaxIPAttempts,
+		LockoutDuration:     wm.LockoutDuration,
+		ProgressiveDelay:    wm.ProgressiveDelay,
 	}
 }
 
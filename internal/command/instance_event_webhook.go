@@ -0,0 +1,142 @@
+package command
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+// AddEventWebhook adds an event webhook to the instance of the context.
+// The signing key of the payloads is generated and only returned once.
+func (c *Commands) AddEventWebhook(ctx context.Context, webhook *domain.EventWebhook) (id, signingKey string, _ *domain.ObjectDetails, err error) {
+	if !webhook.IsValid() {
+		return "", "", nil, caos_errs.ThrowInvalidArgument(nil, "INST-Wh2ad", "Errors.EventWebhook.Invalid")
+	}
+	id, err = c.idGenerator.Next()
+	if err != nil {
+		return "", "", nil, err
+	}
+	writeModel, err := c.getEventWebhook(ctx, id)
+	if err != nil {
+		return "", "", nil, err
+	}
+	key, err := c.newCode(ctx, c.eventstore.Filter, domain.SecretGeneratorTypeAppSecret, c.eventWebhookEncryption)
+	if err != nil {
+		return "", "", nil, err
+	}
+	instanceAgg := InstanceAggregateFromWriteModel(&writeModel.WriteModel)
+	pushedEvents, err := c.eventstore.Push(ctx, instance.NewEventWebhookAddedEvent(
+		ctx,
+		instanceAgg,
+		id,
+		webhook.Name,
+		webhook.CallURL,
+		webhook.EventTypes,
+		key.Crypted,
+	))
+	if err != nil {
+		return "", "", nil, err
+	}
+	err = AppendAndReduce(writeModel, pushedEvents...)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return id, key.Plain, writeModelToObjectDetails(&writeModel.WriteModel), nil
+}
+
+func (c *Commands) ChangeEventWebhook(ctx context.Context, webhook *domain.EventWebhook) (*domain.ObjectDetails, error) {
+	if webhook.ID == "" {
+		return nil, caos_errs.ThrowInvalidArgument(nil, "INST-Wh3ch", "Errors.IDMissing")
+	}
+	if !webhook.IsValid() {
+		return nil, caos_errs.ThrowInvalidArgument(nil, "INST-Wh4ch", "Errors.EventWebhook.Invalid")
+	}
+	writeModel, err := c.getEventWebhook(ctx, webhook.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !writeModel.State.Exists() {
+		return nil, caos_errs.ThrowNotFound(nil, "INST-Wh5ch", "Errors.EventWebhook.NotFound")
+	}
+	instanceAgg := InstanceAggregateFromWriteModel(&writeModel.WriteModel)
+	changedEvent, hasChanged, err := writeModel.NewChangedEvent(ctx, instanceAgg, webhook)
+	if err != nil {
+		return nil, err
+	}
+	if !hasChanged {
+		return nil, caos_errs.ThrowPreconditionFailed(nil, "INST-Wh6ch", "Errors.NoChangesFound")
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, changedEvent)
+	if err != nil {
+		return nil, err
+	}
+	err = AppendAndReduce(writeModel, pushedEvents...)
+	if err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&writeModel.WriteModel), nil
+}
+
+// RegenerateEventWebhookSigningKey replaces the signing key of the event webhook, the new key is only returned once
+func (c *Commands) RegenerateEventWebhookSigningKey(ctx context.Context, id string) (string, *domain.ObjectDetails, error) {
+	if id == "" {
+		return "", nil, caos_errs.ThrowInvalidArgument(nil, "INST-Wh7sk", "Errors.IDMissing")
+	}
+	writeModel, err := c.getEventWebhook(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if !writeModel.State.Exists() {
+		return "", nil, caos_errs.ThrowNotFound(nil, "INST-Wh8sk", "Errors.EventWebhook.NotFound")
+	}
+	key, err := c.newCode(ctx, c.eventstore.Filter, domain.SecretGeneratorTypeAppSecret, c.eventWebhookEncryption)
+	if err != nil {
+		return "", nil, err
+	}
+	instanceAgg := InstanceAggregateFromWriteModel(&writeModel.WriteModel)
+	pushedEvents, err := c.eventstore.Push(ctx, instance.NewEventWebhookSigningKeyChangedEvent(ctx, instanceAgg, id, key.Crypted))
+	if err != nil {
+		return "", nil, err
+	}
+	err = AppendAndReduce(writeModel, pushedEvents...)
+	if err != nil {
+		return "", nil, err
+	}
+	return key.Plain, writeModelToObjectDetails(&writeModel.WriteModel), nil
+}
+
+// RemoveEventWebhook removes the event webhook, pending and failed deliveries are removed as well
+func (c *Commands) RemoveEventWebhook(ctx context.Context, id string) (*domain.ObjectDetails, error) {
+	if id == "" {
+		return nil, caos_errs.ThrowInvalidArgument(nil, "INST-Wh9rm", "Errors.IDMissing")
+	}
+	writeModel, err := c.getEventWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !writeModel.State.Exists() {
+		return nil, caos_errs.ThrowNotFound(nil, "INST-Wh0rm", "Errors.EventWebhook.NotFound")
+	}
+	instanceAgg := InstanceAggregateFromWriteModel(&writeModel.WriteModel)
+	pushedEvents, err := c.eventstore.Push(ctx, instance.NewEventWebhookRemovedEvent(ctx, instanceAgg, id))
+	if err != nil {
+		return nil, err
+	}
+	err = AppendAndReduce(writeModel, pushedEvents...)
+	if err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&writeModel.WriteModel), nil
+}
+
+func (c *Commands) getEventWebhook(ctx context.Context, id string) (*InstanceEventWebhookWriteModel, error) {
+	writeModel := NewInstanceEventWebhookWriteModel(authz.GetInstance(ctx).InstanceID(), id)
+	err := c.eventstore.FilterToQueryReducer(ctx, writeModel)
+	if err != nil {
+		return nil, err
+	}
+	return writeModel, nil
+}
@@ -0,0 +1,108 @@
+package command
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+type InstanceEventWebhookWriteModel struct {
+	eventstore.WriteModel
+
+	ID         string
+	Name       string
+	CallURL    string
+	EventTypes []string
+	SigningKey *crypto.CryptoValue
+	State      domain.EventWebhookState
+}
+
+func NewInstanceEventWebhookWriteModel(instanceID, id string) *InstanceEventWebhookWriteModel {
+	return &InstanceEventWebhookWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   instanceID,
+			ResourceOwner: instanceID,
+		},
+		ID: id,
+	}
+}
+
+func (wm *InstanceEventWebhookWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *instance.EventWebhookAddedEvent:
+			if wm.ID != e.ID {
+				continue
+			}
+			wm.Name = e.Name
+			wm.CallURL = e.CallURL
+			wm.EventTypes = e.EventTypes
+			wm.SigningKey = e.SigningKey
+			wm.State = domain.EventWebhookStateActive
+		case *instance.EventWebhookChangedEvent:
+			if wm.ID != e.ID {
+				continue
+			}
+			if e.Name != nil {
+				wm.Name = *e.Name
+			}
+			if e.CallURL != nil {
+				wm.CallURL = *e.CallURL
+			}
+			if e.EventTypes != nil {
+				wm.EventTypes = *e.EventTypes
+			}
+		case *instance.EventWebhookSigningKeyChangedEvent:
+			if wm.ID != e.ID {
+				continue
+			}
+			wm.SigningKey = e.SigningKey
+		case *instance.EventWebhookRemovedEvent:
+			if wm.ID != e.ID {
+				continue
+			}
+			wm.SigningKey = nil
+			wm.State = domain.EventWebhookStateRemoved
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *InstanceEventWebhookWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			instance.EventWebhookAddedEventType,
+			instance.EventWebhookChangedEventType,
+			instance.EventWebhookSigningKeyChangedEventType,
+			instance.EventWebhookRemovedEventType).
+		Builder()
+}
+
+func (wm *InstanceEventWebhookWriteModel) NewChangedEvent(ctx context.Context, aggregate *eventstore.Aggregate, webhook *domain.EventWebhook) (*instance.EventWebhookChangedEvent, bool, error) {
+	changes := make([]instance.EventWebhookChanges, 0, 3)
+	if wm.Name != webhook.Name {
+		changes = append(changes, instance.ChangeEventWebhookName(webhook.Name))
+	}
+	if wm.CallURL != webhook.CallURL {
+		changes = append(changes, instance.ChangeEventWebhookCallURL(webhook.CallURL))
+	}
+	if !reflect.DeepEqual(wm.EventTypes, webhook.EventTypes) {
+		changes = append(changes, instance.ChangeEventWebhookEventTypes(webhook.EventTypes))
+	}
+	if len(changes) == 0 {
+		return nil, false, nil
+	}
+	changeEvent, err := instance.NewEventWebhookChangedEvent(ctx, aggregate, wm.ID, changes)
+	if err != nil {
+		return nil, false, err
+	}
+	return changeEvent, true, nil
+}
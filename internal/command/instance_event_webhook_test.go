@@ -0,0 +1,482 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/id"
+	id_mock "github.com/zitadel/zitadel/internal/id/mock"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+func eventWebhookSigningKey(key string) *crypto.CryptoValue {
+	return &crypto.CryptoValue{
+		CryptoType: crypto.TypeEncryption,
+		Algorithm:  "enc",
+		KeyID:      "id",
+		Crypted:    []byte(key),
+	}
+}
+
+func eventWebhookAddedEvent(ctx context.Context) *instance.EventWebhookAddedEvent {
+	return instance.NewEventWebhookAddedEvent(
+		ctx,
+		&instance.NewAggregate("INSTANCE").Aggregate,
+		"webhookID",
+		"name",
+		"https://example.com/events",
+		[]string{"user.human.added"},
+		eventWebhookSigningKey("key"),
+	)
+}
+
+func TestCommandSide_AddEventWebhook(t *testing.T) {
+	type fields struct {
+		eventstore  *eventstore.Eventstore
+		idGenerator id.Generator
+		newCode     cryptoCodeFunc
+	}
+	type args struct {
+		ctx     context.Context
+		webhook *domain.EventWebhook
+	}
+	type res struct {
+		id         string
+		signingKey string
+		want       *domain.ObjectDetails
+		err        func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "invalid call url, invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				webhook: &domain.EventWebhook{
+					Name:       "name",
+					CallURL:    "ftp://example.com",
+					EventTypes: []string{"user.human.added"},
+				},
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "no event types, invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				webhook: &domain.EventWebhook{
+					Name:    "name",
+					CallURL: "https://example.com/events",
+				},
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "add event webhook, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID("INSTANCE", eventWebhookAddedEvent(context.Background())),
+						},
+					),
+				),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "webhookID"),
+				newCode:     mockCode("key", time.Hour),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				webhook: &domain.EventWebhook{
+					Name:       "name",
+					CallURL:    "https://example.com/events",
+					EventTypes: []string{"user.human.added"},
+				},
+			},
+			res: res{
+				id:         "webhookID",
+				signingKey: "key",
+				want: &domain.ObjectDetails{
+					ResourceOwner: "INSTANCE",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore:  tt.fields.eventstore,
+				idGenerator: tt.fields.idGenerator,
+				newCode:     tt.fields.newCode,
+			}
+			id, signingKey, got, err := r.AddEventWebhook(tt.args.ctx, tt.args.webhook)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.id, id)
+				assert.Equal(t, tt.res.signingKey, signingKey)
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
+
+func TestCommandSide_ChangeEventWebhook(t *testing.T) {
+	type fields struct {
+		eventstore *eventstore.Eventstore
+	}
+	type args struct {
+		ctx     context.Context
+		webhook *domain.EventWebhook
+	}
+	type res struct {
+		want *domain.ObjectDetails
+		err  func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "id missing, invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				webhook: &domain.EventWebhook{
+					Name:       "name",
+					CallURL:    "https://example.com/events",
+					EventTypes: []string{"user.human.added"},
+				},
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "not found, not found error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				webhook: &domain.EventWebhook{
+					ID:         "webhookID",
+					Name:       "name",
+					CallURL:    "https://example.com/events",
+					EventTypes: []string{"user.human.added"},
+				},
+			},
+			res: res{
+				err: caos_errs.IsNotFound,
+			},
+		},
+		{
+			name: "no changes, precondition error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(eventWebhookAddedEvent(context.Background())),
+					),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				webhook: &domain.EventWebhook{
+					ID:         "webhookID",
+					Name:       "name",
+					CallURL:    "https://example.com/events",
+					EventTypes: []string{"user.human.added"},
+				},
+			},
+			res: res{
+				err: caos_errs.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "change event webhook, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(eventWebhookAddedEvent(context.Background())),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID("INSTANCE", func() eventstore.Command {
+								event, _ := instance.NewEventWebhookChangedEvent(
+									context.Background(),
+									&instance.NewAggregate("INSTANCE").Aggregate,
+									"webhookID",
+									[]instance.EventWebhookChanges{
+										instance.ChangeEventWebhookCallURL("https://example.com/changed"),
+										instance.ChangeEventWebhookEventTypes([]string{"user.human.added", "user.removed"}),
+									},
+								)
+								return event
+							}()),
+						},
+					),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				webhook: &domain.EventWebhook{
+					ID:         "webhookID",
+					Name:       "name",
+					CallURL:    "https://example.com/changed",
+					EventTypes: []string{"user.human.added", "user.removed"},
+				},
+			},
+			res: res{
+				want: &domain.ObjectDetails{
+					ResourceOwner: "INSTANCE",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore: tt.fields.eventstore,
+			}
+			got, err := r.ChangeEventWebhook(tt.args.ctx, tt.args.webhook)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
+
+func TestCommandSide_RegenerateEventWebhookSigningKey(t *testing.T) {
+	type fields struct {
+		eventstore *eventstore.Eventstore
+		newCode    cryptoCodeFunc
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	type res struct {
+		signingKey string
+		want       *domain.ObjectDetails
+		err        func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "not found, not found error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				id:  "webhookID",
+			},
+			res: res{
+				err: caos_errs.IsNotFound,
+			},
+		},
+		{
+			name: "regenerate signing key, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(eventWebhookAddedEvent(context.Background())),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID("INSTANCE", instance.NewEventWebhookSigningKeyChangedEvent(
+								context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								"webhookID",
+								eventWebhookSigningKey("newKey"),
+							)),
+						},
+					),
+				),
+				newCode: mockCode("newKey", time.Hour),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				id:  "webhookID",
+			},
+			res: res{
+				signingKey: "newKey",
+				want: &domain.ObjectDetails{
+					ResourceOwner: "INSTANCE",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore: tt.fields.eventstore,
+				newCode:    tt.fields.newCode,
+			}
+			signingKey, got, err := r.RegenerateEventWebhookSigningKey(tt.args.ctx, tt.args.id)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.signingKey, signingKey)
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
+
+func TestCommandSide_RemoveEventWebhook(t *testing.T) {
+	type fields struct {
+		eventstore *eventstore.Eventstore
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	type res struct {
+		want *domain.ObjectDetails
+		err  func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "id missing, invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "already removed, not found error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(eventWebhookAddedEvent(context.Background())),
+						eventFromEventPusher(instance.NewEventWebhookRemovedEvent(
+							context.Background(),
+							&instance.NewAggregate("INSTANCE").Aggregate,
+							"webhookID",
+						)),
+					),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				id:  "webhookID",
+			},
+			res: res{
+				err: caos_errs.IsNotFound,
+			},
+		},
+		{
+			name: "remove event webhook, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(eventWebhookAddedEvent(context.Background())),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID("INSTANCE", instance.NewEventWebhookRemovedEvent(
+								context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								"webhookID",
+							)),
+						},
+					),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "INSTANCE"),
+				id:  "webhookID",
+			},
+			res: res{
+				want: &domain.ObjectDetails{
+					ResourceOwner: "INSTANCE",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore: tt.fields.eventstore,
+			}
+			got, err := r.RemoveEventWebhook(tt.args.ctx, tt.args.id)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
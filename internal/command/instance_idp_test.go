@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
@@ -4292,6 +4293,34 @@ func TestCommandSide_AddInstanceLDAPIDP(t *testing.T) {
 				},
 			},
 		},
+		{
+			"invalid group mapping",
+			fields{
+				eventstore:  eventstoreExpect(t),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "id1"),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				provider: LDAPProvider{
+					Name:              "name",
+					Servers:           []string{"server"},
+					BindDN:            "binddn",
+					BaseDN:            "baseDN",
+					BindPassword:      "password",
+					UserBase:          "user",
+					UserObjectClasses: []string{"object"},
+					UserFilters:       []string{"filter"},
+					LDAPGroupSync: idp.LDAPGroupSync{
+						GroupMappings: []idp.LDAPGroupMapping{{Group: "cn=group", ProjectID: "project"}},
+					},
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "INST-Hw2sx", ""))
+				},
+			},
+		},
 		{
 			name: "ok",
 			fields: fields{
@@ -4319,6 +4348,7 @@ func TestCommandSide_AddInstanceLDAPIDP(t *testing.T) {
 									[]string{"filter"},
 									time.Second*30,
 									idp.LDAPAttributes{},
+									idp.LDAPGroupSync{},
 									idp.Options{},
 								)),
 						},
@@ -4388,6 +4418,15 @@ func TestCommandSide_AddInstanceLDAPIDP(t *testing.T) {
 										AvatarURLAttribute:         "avatarURL",
 										ProfileAttribute:           "profile",
 									},
+									idp.LDAPGroupSync{
+										GroupsAttribute:      "memberOf",
+										GroupBase:            "groups",
+										GroupMemberAttribute: "member",
+										NestedGroups:         true,
+										GroupMappings: []idp.LDAPGroupMapping{
+											{Group: "cn=group", ProjectID: "project", Roles: []string{"role"}},
+										},
+									},
 									idp.Options{
 										IsCreationAllowed: true,
 										IsLinkingAllowed:  true,
@@ -4429,6 +4468,15 @@ func TestCommandSide_AddInstanceLDAPIDP(t *testing.T) {
 						AvatarURLAttribute:         "avatarURL",
 						ProfileAttribute:           "profile",
 					},
+					LDAPGroupSync: idp.LDAPGroupSync{
+						GroupsAttribute:      "memberOf",
+						GroupBase:            "groups",
+						GroupMemberAttribute: "member",
+						NestedGroups:         true,
+						GroupMappings: []idp.LDAPGroupMapping{
+							{Group: "cn=group", ProjectID: "project", Roles: []string{"role"}},
+						},
+					},
 					IDPOptions: idp.Options{
 						IsCreationAllowed: true,
 						IsLinkingAllowed:  true,
@@ -4690,6 +4738,7 @@ func TestCommandSide_UpdateInstanceLDAPIDP(t *testing.T) {
 								[]string{"filter"},
 								time.Second*30,
 								idp.LDAPAttributes{},
+								idp.LDAPGroupSync{},
 								idp.Options{},
 							)),
 					),
@@ -4738,6 +4787,7 @@ func TestCommandSide_UpdateInstanceLDAPIDP(t *testing.T) {
 								[]string{"filter"},
 								time.Second*30,
 								idp.LDAPAttributes{},
+								idp.LDAPGroupSync{},
 								idp.Options{},
 							)),
 					),
@@ -4780,6 +4830,13 @@ func TestCommandSide_UpdateInstanceLDAPIDP(t *testing.T) {
 												AvatarURLAttribute:         stringPointer("new avatarURL"),
 												ProfileAttribute:           stringPointer("new profile"),
 											}),
+											idp.ChangeLDAPGroupSync(idp.LDAPGroupSyncChanges{
+												GroupsAttribute: stringPointer("memberOf"),
+												NestedGroups:    &t,
+												GroupMappings: &[]idp.LDAPGroupMapping{
+													{Group: "cn=group", ProjectID: "project", Roles: []string{"role"}},
+												},
+											}),
 											idp.ChangeLDAPOptions(idp.OptionChanges{
 												IsCreationAllowed: &t,
 												IsLinkingAllowed:  &t,
@@ -4825,6 +4882,13 @@ func TestCommandSide_UpdateInstanceLDAPIDP(t *testing.T) {
 						AvatarURLAttribute:         "new avatarURL",
 						ProfileAttribute:           "new profile",
 					},
+					LDAPGroupSync: idp.LDAPGroupSync{
+						GroupsAttribute: "memberOf",
+						NestedGroups:    true,
+						GroupMappings: []idp.LDAPGroupMapping{
+							{Group: "cn=group", ProjectID: "project", Roles: []string{"role"}},
+						},
+					},
 					IDPOptions: idp.Options{
 						IsCreationAllowed: true,
 						IsLinkingAllowed:  true,
@@ -4857,3 +4921,473 @@ func TestCommandSide_UpdateInstanceLDAPIDP(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandSide_AddInstanceSAMLIDP(t *testing.T) {
+	type fields struct {
+		eventstore   *eventstore.Eventstore
+		idGenerator  id.Generator
+		secretCrypto crypto.EncryptionAlgorithm
+		httpClient   *http.Client
+	}
+	type args struct {
+		ctx      context.Context
+		provider SAMLProvider
+	}
+	type res struct {
+		id   string
+		want *domain.ObjectDetails
+		err  func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"invalid name",
+			fields{
+				eventstore:  eventstoreExpect(t),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "id1"),
+			},
+			args{
+				ctx:      authz.WithInstanceID(context.Background(), "instance1"),
+				provider: SAMLProvider{},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "INST-Fsd2h", ""))
+				},
+			},
+		},
+		{
+			"missing metadata",
+			fields{
+				eventstore:  eventstoreExpect(t),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "id1"),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				provider: SAMLProvider{
+					Name: "name",
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "INST-Bv3wq", ""))
+				},
+			},
+		},
+		{
+			"invalid binding",
+			fields{
+				eventstore:  eventstoreExpect(t),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "id1"),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				provider: SAMLProvider{
+					Name:     "name",
+					Metadata: testSAMLMetadata,
+					Binding:  "urn:oasis:names:tc:SAML:2.0:bindings:SOAP",
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "INST-Dgz4t", ""))
+				},
+			},
+		},
+		{
+			"invalid metadata",
+			fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(),
+				),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "id1"),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				provider: SAMLProvider{
+					Name:     "name",
+					Metadata: []byte("<invalid"),
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "COMMAND-Bw31h", ""))
+				},
+			},
+		},
+		{
+			"metadata url not reachable",
+			fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(),
+				),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "id1"),
+				httpClient:  newTestClient(http.StatusNotFound, nil),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				provider: SAMLProvider{
+					Name:        "name",
+					MetadataURL: "https://idp.com/metadata",
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowPreconditionFailed(nil, "COMMAND-Sfg3r", ""))
+				},
+			},
+		},
+		{
+			name: "ok",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID(
+								"instance1",
+								instance.NewSAMLIDPAddedEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate,
+									"id1",
+									"name",
+									testSAMLMetadata,
+									&crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    []byte("key"),
+									},
+									[]byte("certificate"),
+									"",
+									false,
+									idp.Options{},
+								),
+							),
+						},
+					),
+				),
+				idGenerator:  id_mock.NewIDGeneratorExpectIDs(t, "id1"),
+				secretCrypto: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				provider: SAMLProvider{
+					Name:     "name",
+					Metadata: testSAMLMetadata,
+				},
+			},
+			res: res{
+				id:   "id1",
+				want: &domain.ObjectDetails{ResourceOwner: "instance1"},
+			},
+		},
+		{
+			name: "ok all set, metadata url",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID(
+								"instance1",
+								instance.NewSAMLIDPAddedEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate,
+									"id1",
+									"name",
+									testSAMLMetadata,
+									&crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    []byte("key"),
+									},
+									[]byte("certificate"),
+									domain.SAMLBindingRedirectURN,
+									true,
+									idp.Options{
+										IsCreationAllowed: true,
+										IsLinkingAllowed:  true,
+										IsAutoCreation:    true,
+										IsAutoUpdate:      true,
+									},
+								),
+							),
+						},
+					),
+				),
+				idGenerator:  id_mock.NewIDGeneratorExpectIDs(t, "id1"),
+				secretCrypto: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				httpClient:   newTestClient(http.StatusOK, testSAMLMetadata),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				provider: SAMLProvider{
+					Name:              "name",
+					MetadataURL:       "https://idp.com/metadata",
+					Binding:           domain.SAMLBindingRedirectURN,
+					WithSignedRequest: true,
+					IDPOptions: idp.Options{
+						IsCreationAllowed: true,
+						IsLinkingAllowed:  true,
+						IsAutoCreation:    true,
+						IsAutoUpdate:      true,
+					},
+				},
+			},
+			res: res{
+				id:   "id1",
+				want: &domain.ObjectDetails{ResourceOwner: "instance1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:          tt.fields.eventstore,
+				idGenerator:         tt.fields.idGenerator,
+				idpConfigEncryption: tt.fields.secretCrypto,
+				httpClient:          tt.fields.httpClient,
+				samlCertificateAndKeyGenerator: func(id string) ([]byte, []byte, error) {
+					return []byte("key"), []byte("certificate"), nil
+				},
+			}
+			id, got, err := c.AddInstanceSAMLProvider(tt.args.ctx, tt.args.provider)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.id, id)
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
+
+func TestCommandSide_UpdateInstanceSAMLIDP(t *testing.T) {
+	type fields struct {
+		eventstore *eventstore.Eventstore
+		httpClient *http.Client
+	}
+	type args struct {
+		ctx      context.Context
+		id       string
+		provider SAMLProvider
+	}
+	type res struct {
+		want *domain.ObjectDetails
+		err  func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"invalid id",
+			fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args{
+				ctx:      authz.WithInstanceID(context.Background(), "instance1"),
+				provider: SAMLProvider{},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "INST-Hb3sw", ""))
+				},
+			},
+		},
+		{
+			"invalid name",
+			fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args{
+				ctx:      authz.WithInstanceID(context.Background(), "instance1"),
+				id:       "id1",
+				provider: SAMLProvider{},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "INST-Vs2ga", ""))
+				},
+			},
+		},
+		{
+			"invalid binding",
+			fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				provider: SAMLProvider{
+					Name:    "name",
+					Binding: "binding",
+				},
+			},
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowInvalidArgument(nil, "INST-Wr5zg", ""))
+				},
+			},
+		},
+		{
+			name: "not found",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				provider: SAMLProvider{
+					Name: "name",
+				},
+			},
+			res: res{
+				err: func(err error) bool {
+					return errors.Is(err, caos_errors.ThrowNotFound(nil, "INST-Ds2sg", ""))
+				},
+			},
+		},
+		{
+			name: "no changes",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewSAMLIDPAddedEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate,
+								"id1",
+								"name",
+								testSAMLMetadata,
+								&crypto.CryptoValue{
+									CryptoType: crypto.TypeEncryption,
+									Algorithm:  "enc",
+									KeyID:      "id",
+									Crypted:    []byte("key"),
+								},
+								[]byte("certificate"),
+								"",
+								false,
+								idp.Options{},
+							)),
+					),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				provider: SAMLProvider{
+					Name: "name",
+				},
+			},
+			res: res{
+				want: &domain.ObjectDetails{ResourceOwner: "instance1"},
+			},
+		},
+		{
+			name: "change ok",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewSAMLIDPAddedEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate,
+								"id1",
+								"name",
+								[]byte("<old/>"),
+								&crypto.CryptoValue{
+									CryptoType: crypto.TypeEncryption,
+									Algorithm:  "enc",
+									KeyID:      "id",
+									Crypted:    []byte("key"),
+								},
+								[]byte("certificate"),
+								"",
+								false,
+								idp.Options{},
+							)),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusherWithInstanceID(
+								"instance1",
+								func() eventstore.Command {
+									t := true
+									event, _ := instance.NewSAMLIDPChangedEvent(context.Background(), &instance.NewAggregate("instance1").Aggregate,
+										"id1",
+										[]idp.SAMLIDPChanges{
+											idp.ChangeSAMLName("new name"),
+											idp.ChangeSAMLMetadata(testSAMLMetadata),
+											idp.ChangeSAMLBinding(domain.SAMLBindingPostURN),
+											idp.ChangeSAMLWithSignedRequest(true),
+											idp.ChangeSAMLOptions(idp.OptionChanges{
+												IsCreationAllowed: &t,
+												IsLinkingAllowed:  &t,
+												IsAutoCreation:    &t,
+												IsAutoUpdate:      &t,
+											}),
+										},
+									)
+									return event
+								}(),
+							),
+						},
+					),
+				),
+				httpClient: newTestClient(http.StatusOK, testSAMLMetadata),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+				id:  "id1",
+				provider: SAMLProvider{
+					Name:              "new name",
+					MetadataURL:       "https://idp.com/metadata",
+					Binding:           domain.SAMLBindingPostURN,
+					WithSignedRequest: true,
+					IDPOptions: idp.Options{
+						IsCreationAllowed: true,
+						IsLinkingAllowed:  true,
+						IsAutoCreation:    true,
+						IsAutoUpdate:      true,
+					},
+				},
+			},
+			res: res{
+				want: &domain.ObjectDetails{ResourceOwner: "instance1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.fields.eventstore,
+				httpClient: tt.fields.httpClient,
+			}
+			got, err := c.UpdateInstanceSAMLProvider(tt.args.ctx, tt.args.id, tt.args.provider)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
+
+var testSAMLMetadata = []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.com/metadata">
+	<md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+		<md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.com/sso"/>
+	</md:IDPSSODescriptor>
+</md:EntityDescriptor>`)
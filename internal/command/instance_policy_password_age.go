@@ -12,9 +12,9 @@ import (
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 )
 
-func (c *Commands) AddDefaultPasswordAgePolicy(ctx context.Context, expireWarnDays, maxAgeDays uint64) (*domain.ObjectDetails, error) {
+func (c *Commands) AddDefaultPasswordAgePolicy(ctx context.Context, expireWarnDays, maxAgeDays, minAgeDays, historyCount uint64) (*domain.ObjectDetails, error) {
 	instanceAgg := instance.NewAggregate(authz.GetInstance(ctx).InstanceID())
-	cmds, err := preparation.PrepareCommands(ctx, c.eventstore.Filter, prepareAddDefaultPasswordAgePolicy(instanceAgg, expireWarnDays, maxAgeDays))
+	cmds, err := preparation.PrepareCommands(ctx, c.eventstore.Filter, prepareAddDefaultPasswordAgePolicy(instanceAgg, expireWarnDays, maxAgeDays, minAgeDays, historyCount))
 	if err != nil {
 		return nil, err
 	}
@@ -26,6 +26,9 @@ func (c *Commands) AddDefaultPasswordAgePolicy(ctx context.Context, expireWarnDa
 }
 
 func (c *Commands) ChangeDefaultPasswordAgePolicy(ctx context.Context, policy *domain.PasswordAgePolicy) (*domain.PasswordAgePolicy, error) {
+	if err := policy.IsValid(); err != nil {
+		return nil, err
+	}
 	existingPolicy, err := c.defaultPasswordAgePolicyWriteModelByID(ctx)
 	if err != nil {
 		return nil, err
@@ -35,7 +38,7 @@ func (c *Commands) ChangeDefaultPasswordAgePolicy(ctx context.Context, policy *d
 	}
 
 	instanceAgg := InstanceAggregateFromWriteModel(&existingPolicy.PasswordAgePolicyWriteModel.WriteModel)
-	changedEvent, hasChanged := existingPolicy.NewChangedEvent(ctx, instanceAgg, policy.ExpireWarnDays, policy.MaxAgeDays)
+	changedEvent, hasChanged := existingPolicy.NewChangedEvent(ctx, instanceAgg, policy.ExpireWarnDays, policy.MaxAgeDays, policy.MinAgeDays, policy.HistoryCount)
 	if !hasChanged {
 		return nil, caos_errs.ThrowPreconditionFailed(nil, "INSTANCE-180sf", "Errors.IAM.PasswordAgePolicy.NotChanged")
 	}
@@ -52,6 +55,17 @@ func (c *Commands) ChangeDefaultPasswordAgePolicy(ctx context.Context, policy *d
 	return writeModelToPasswordAgePolicy(&existingPolicy.PasswordAgePolicyWriteModel), nil
 }
 
+func (c *Commands) getDefaultPasswordAgePolicy(ctx context.Context) (*domain.PasswordAgePolicy, error) {
+	policyWriteModel, err := c.defaultPasswordAgePolicyWriteModelByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !policyWriteModel.State.Exists() {
+		return nil, caos_errs.ThrowInvalidArgument(nil, "INSTANCE-Ohm1a", "Errors.IAM.PasswordAgePolicy.NotFound")
+	}
+	return writeModelToPasswordAgePolicy(&policyWriteModel.PasswordAgePolicyWriteModel), nil
+}
+
 func (c *Commands) defaultPasswordAgePolicyWriteModelByID(ctx context.Context) (policy *InstancePasswordAgePolicyWriteModel, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
@@ -67,9 +81,14 @@ func (c *Commands) defaultPasswordAgePolicyWriteModelByID(ctx context.Context) (
 func prepareAddDefaultPasswordAgePolicy(
 	a *instance.Aggregate,
 	expireWarnDays,
-	maxAgeDays uint64,
+	maxAgeDays,
+	minAgeDays,
+	historyCount uint64,
 ) preparation.Validation {
 	return func() (preparation.CreateCommands, error) {
+		if err := domain.ValidatePasswordHistoryCount(historyCount); err != nil {
+			return nil, err
+		}
 		return func(ctx context.Context, filter preparation.FilterToQueryReducer) ([]eventstore.Command, error) {
 			writeModel := NewInstancePasswordAgePolicyWriteModel(ctx)
 			events, err := filter(ctx, writeModel.Query())
@@ -87,6 +106,8 @@ func prepareAddDefaultPasswordAgePolicy(
 				instance.NewPasswordAgePolicyAddedEvent(ctx, &a.Aggregate,
 					expireWarnDays,
 					maxAgeDays,
+					minAgeDays,
+					historyCount,
 				),
 			}, nil
 		}, nil
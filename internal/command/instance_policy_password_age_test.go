@@ -24,6 +24,8 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 		ctx            context.Context
 		maxAgeDays     uint64
 		expireWarnDays uint64
+		minAgeDays     uint64
+		historyCount   uint64
 	}
 	type res struct {
 		want *domain.ObjectDetails
@@ -35,6 +37,23 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 		args   args
 		res    res
 	}{
+		{
+			name: "history count too high, invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+				),
+			},
+			args: args{
+				ctx:            context.Background(),
+				maxAgeDays:     365,
+				expireWarnDays: 10,
+				historyCount:   domain.MaxPasswordHistoryCount + 1,
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
 		{
 			name: "password age policy already existing, already exists error",
 			fields: fields{
@@ -46,6 +65,8 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								365,
 								10,
+								0,
+								0,
 							),
 						),
 					),
@@ -74,6 +95,8 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 									&instance.NewAggregate("INSTANCE").Aggregate,
 									365,
 									10,
+									1,
+									5,
 								),
 							),
 						},
@@ -84,6 +107,8 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 				ctx:            authz.WithInstanceID(context.Background(), "INSTANCE"),
 				expireWarnDays: 365,
 				maxAgeDays:     10,
+				minAgeDays:     1,
+				historyCount:   5,
 			},
 			res: res{
 				want: &domain.ObjectDetails{
@@ -97,7 +122,7 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 			r := &Commands{
 				eventstore: tt.fields.eventstore,
 			}
-			got, err := r.AddDefaultPasswordAgePolicy(tt.args.ctx, tt.args.expireWarnDays, tt.args.maxAgeDays)
+			got, err := r.AddDefaultPasswordAgePolicy(tt.args.ctx, tt.args.expireWarnDays, tt.args.maxAgeDays, tt.args.minAgeDays, tt.args.historyCount)
 			if tt.res.err == nil {
 				assert.NoError(t, err)
 			}
@@ -159,6 +184,8 @@ func TestCommandSide_ChangeDefaultPasswordAgePolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								365,
 								10,
+								0,
+								0,
 							),
 						),
 					),
@@ -186,6 +213,8 @@ func TestCommandSide_ChangeDefaultPasswordAgePolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								365,
 								10,
+								0,
+								0,
 							),
 						),
 					),
@@ -12,9 +12,9 @@ import (
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 )
 
-func (c *Commands) AddDefaultPasswordComplexityPolicy(ctx context.Context, minLength uint64, hasLowercase, hasUppercase, hasNumber, hasSymbol bool) (*domain.ObjectDetails, error) {
+func (c *Commands) AddDefaultPasswordComplexityPolicy(ctx context.Context, minLength uint64, hasLowercase, hasUppercase, hasNumber, hasSymbol, rejectBreached bool, maxLength uint64, forbidUsername, forbidEmailLocalPart, forbidOrgName bool, rules []domain.PasswordRule, bannedWords []string) (*domain.ObjectDetails, error) {
 	instanceAgg := instance.NewAggregate(authz.GetInstance(ctx).InstanceID())
-	cmds, err := preparation.PrepareCommands(ctx, c.eventstore.Filter, prepareAddDefaultPasswordComplexityPolicy(instanceAgg, minLength, hasLowercase, hasUppercase, hasNumber, hasSymbol))
+	cmds, err := preparation.PrepareCommands(ctx, c.eventstore.Filter, prepareAddDefaultPasswordComplexityPolicy(instanceAgg, minLength, hasLowercase, hasUppercase, hasNumber, hasSymbol, rejectBreached, maxLength, forbidUsername, forbidEmailLocalPart, forbidOrgName, rules, bannedWords))
 	if err != nil {
 		return nil, err
 	}
@@ -39,7 +39,7 @@ func (c *Commands) ChangeDefaultPasswordComplexityPolicy(ctx context.Context, po
 	}
 
 	instanceAgg := InstanceAggregateFromWriteModel(&existingPolicy.PasswordComplexityPolicyWriteModel.WriteModel)
-	changedEvent, hasChanged := existingPolicy.NewChangedEvent(ctx, instanceAgg, policy.MinLength, policy.HasLowercase, policy.HasUppercase, policy.HasNumber, policy.HasSymbol)
+	changedEvent, hasChanged := existingPolicy.NewChangedEvent(ctx, instanceAgg, policy.MinLength, policy.HasLowercase, policy.HasUppercase, policy.HasNumber, policy.HasSymbol, policy.RejectBreached, policy.MaxLength, policy.ForbidUsername, policy.ForbidEmailLocalPart, policy.ForbidOrgName, policy.Rules, policy.BannedWords)
 	if !hasChanged {
 		return nil, caos_errs.ThrowPreconditionFailed(nil, "INSTANCE-9jlsf", "Errors.IAM.PasswordComplexityPolicy.NotChanged")
 	}
@@ -60,12 +60,28 @@ func prepareAddDefaultPasswordComplexityPolicy(
 	hasLowercase,
 	hasUppercase,
 	hasNumber,
-	hasSymbol bool,
+	hasSymbol,
+	rejectBreached bool,
+	maxLength uint64,
+	forbidUsername,
+	forbidEmailLocalPart,
+	forbidOrgName bool,
+	rules []domain.PasswordRule,
+	bannedWords []string,
 ) preparation.Validation {
 	return func() (preparation.CreateCommands, error) {
 		if minLength == 0 || minLength > 72 {
 			return nil, caos_errs.ThrowInvalidArgument(nil, "INSTANCE-Lsp0e", "Errors.Instance.PasswordComplexityPolicy.MinLengthNotAllowed")
 		}
+		policy := &domain.PasswordComplexityPolicy{
+			MinLength:   minLength,
+			MaxLength:   maxLength,
+			Rules:       rules,
+			BannedWords: bannedWords,
+		}
+		if err := policy.IsValid(); err != nil {
+			return nil, err
+		}
 		return func(ctx context.Context, filter preparation.FilterToQueryReducer) ([]eventstore.Command, error) {
 			writeModel := NewInstancePasswordComplexityPolicyWriteModel(ctx)
 			events, err := filter(ctx, writeModel.Query())
@@ -86,6 +102,13 @@ func prepareAddDefaultPasswordComplexityPolicy(
 					hasUppercase,
 					hasNumber,
 					hasSymbol,
+					rejectBreached,
+					maxLength,
+					forbidUsername,
+					forbidEmailLocalPart,
+					forbidOrgName,
+					rules,
+					bannedWords,
 				),
 			}, nil
 		}, nil
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 
 	"github.com/zitadel/zitadel/internal/repository/instance"
@@ -59,7 +60,14 @@ func (wm *InstancePasswordComplexityPolicyWriteModel) NewChangedEvent(
 	hasLowercase,
 	hasUppercase,
 	hasNumber,
-	hasSymbol bool,
+	hasSymbol,
+	rejectBreached bool,
+	maxLength uint64,
+	forbidUsername,
+	forbidEmailLocalPart,
+	forbidOrgName bool,
+	rules []domain.PasswordRule,
+	bannedWords []string,
 ) (*instance.PasswordComplexityPolicyChangedEvent, bool) {
 
 	changes := make([]policy.PasswordComplexityPolicyChanges, 0)
@@ -78,6 +86,27 @@ func (wm *InstancePasswordComplexityPolicyWriteModel) NewChangedEvent(
 	if wm.HasSymbol != hasSymbol {
 		changes = append(changes, policy.ChangeHasSymbol(hasSymbol))
 	}
+	if wm.RejectBreached != rejectBreached {
+		changes = append(changes, policy.ChangeRejectBreached(rejectBreached))
+	}
+	if wm.MaxLength != maxLength {
+		changes = append(changes, policy.ChangeMaxLength(maxLength))
+	}
+	if wm.ForbidUsername != forbidUsername {
+		changes = append(changes, policy.ChangeForbidUsername(forbidUsername))
+	}
+	if wm.ForbidEmailLocalPart != forbidEmailLocalPart {
+		changes = append(changes, policy.ChangeForbidEmailLocalPart(forbidEmailLocalPart))
+	}
+	if wm.ForbidOrgName != forbidOrgName {
+		changes = append(changes, policy.ChangeForbidOrgName(forbidOrgName))
+	}
+	if passwordRulesChanged(wm.Rules, rules) {
+		changes = append(changes, policy.ChangeRules(rules))
+	}
+	if bannedWordsChanged(wm.BannedWords, bannedWords) {
+		changes = append(changes, policy.ChangeBannedWords(bannedWords))
+	}
 	if len(changes) == 0 {
 		return nil, false
 	}
@@ -21,12 +21,13 @@ func TestCommandSide_AddDefaultPasswordComplexityPolicy(t *testing.T) {
 		eventstore *eventstore.Eventstore
 	}
 	type args struct {
-		ctx          context.Context
-		minLength    uint64
-		hasLowercase bool
-		hasUppercase bool
-		hasNumber    bool
-		hasSymbol    bool
+		ctx            context.Context
+		minLength      uint64
+		hasLowercase   bool
+		hasUppercase   bool
+		hasNumber      bool
+		hasSymbol      bool
+		rejectBreached bool
 	}
 	type res struct {
 		want *domain.ObjectDetails
@@ -68,6 +69,8 @@ func TestCommandSide_AddDefaultPasswordComplexityPolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								8,
 								true, true, true, true,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -99,6 +102,8 @@ func TestCommandSide_AddDefaultPasswordComplexityPolicy(t *testing.T) {
 									&instance.NewAggregate("INSTANCE").Aggregate,
 									8,
 									true, true, true, true,
+									false,
+									0, false, false, false, nil, nil,
 								),
 							),
 						},
@@ -125,7 +130,7 @@ func TestCommandSide_AddDefaultPasswordComplexityPolicy(t *testing.T) {
 			r := &Commands{
 				eventstore: tt.fields.eventstore,
 			}
-			got, err := r.AddDefaultPasswordComplexityPolicy(tt.args.ctx, tt.args.minLength, tt.args.hasLowercase, tt.args.hasUppercase, tt.args.hasNumber, tt.args.hasSymbol)
+			got, err := r.AddDefaultPasswordComplexityPolicy(tt.args.ctx, tt.args.minLength, tt.args.hasLowercase, tt.args.hasUppercase, tt.args.hasNumber, tt.args.hasSymbol, tt.args.rejectBreached, 0, false, false, false, nil, nil)
 			if tt.res.err == nil {
 				assert.NoError(t, err)
 			}
@@ -211,6 +216,8 @@ func TestCommandSide_ChangeDefaultPasswordComplexityPolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								8,
 								true, true, true, true,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -241,6 +248,8 @@ func TestCommandSide_ChangeDefaultPasswordComplexityPolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								8,
 								true, true, true, true,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"time"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/eventstore"
@@ -56,7 +57,12 @@ func (wm *InstanceLockoutPolicyWriteModel) NewChangedEvent(
 	ctx context.Context,
 	aggregate *eventstore.Aggregate,
 	maxAttempts uint64,
-	showLockoutFailure bool) (*instance.LockoutPolicyChangedEvent, bool) {
+	showLockoutFailure bool,
+	maxOTPAttempts,
+	maxPasskeyAttempts,
+	maxIPAttempts uint64,
+	lockoutDuration,
+	progressiveDelay time.Duration) (*instance.LockoutPolicyChangedEvent, bool) {
 	changes := make([]policy.LockoutPolicyChanges, 0)
 	if wm.MaxPasswordAttempts != maxAttempts {
 		changes = append(changes, policy.ChangeMaxAttempts(maxAttempts))
@@ -64,6 +70,21 @@ func (wm *InstanceLockoutPolicyWriteModel) NewChangedEvent(
 	if wm.ShowLockOutFailures != showLockoutFailure {
 		changes = append(changes, policy.ChangeShowLockOutFailures(showLockoutFailure))
 	}
+	if wm.MaxOTPAttempts != maxOTPAttempts {
+		changes = append(changes, policy.ChangeMaxOTPAttempts(maxOTPAttempts))
+	}
+	if wm.MaxPasskeyAttempts != maxPasskeyAttempts {
+		changes = append(changes, policy.ChangeMaxPasskeyAttempts(maxPasskeyAttempts))
+	}
+	if wm.MaxIPAttempts != maxIPAttempts {
+		changes = append(changes, policy.ChangeMaxIPAttempts(maxIPAttempts))
+	}
+	if wm.LockoutDuration != lockoutDuration {
+		changes = append(changes, policy.ChangeLockoutDuration(lockoutDuration))
+	}
+	if wm.ProgressiveDelay != progressiveDelay {
+		changes = append(changes, policy.ChangeProgressiveDelay(progressiveDelay))
+	}
 	if len(changes) == 0 {
 		return nil, false
 	}
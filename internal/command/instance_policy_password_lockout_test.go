@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -24,6 +25,11 @@ func TestCommandSide_AddDefaultLockoutPolicy(t *testing.T) {
 		ctx                 context.Context
 		maxPasswordAttempts uint64
 		showLockOutFailures bool
+		maxOTPAttempts      uint64
+		maxPasskeyAttempts  uint64
+		maxIPAttempts       uint64
+		lockoutDuration     time.Duration
+		progressiveDelay    time.Duration
 	}
 	type res struct {
 		want *domain.ObjectDetails
@@ -46,6 +52,11 @@ func TestCommandSide_AddDefaultLockoutPolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								10,
 								true,
+								0,
+								0,
+								0,
+								0,
+								0,
 							),
 						),
 					),
@@ -74,6 +85,11 @@ func TestCommandSide_AddDefaultLockoutPolicy(t *testing.T) {
 									&instance.NewAggregate("INSTANCE").Aggregate,
 									10,
 									true,
+									5,
+									3,
+									20,
+									15*time.Minute,
+									time.Second,
 								),
 							),
 						},
@@ -84,6 +100,11 @@ func TestCommandSide_AddDefaultLockoutPolicy(t *testing.T) {
 				ctx:                 authz.WithInstanceID(context.Background(), "INSTANCE"),
 				maxPasswordAttempts: 10,
 				showLockOutFailures: true,
+				maxOTPAttempts:      5,
+				maxPasskeyAttempts:  3,
+				maxIPAttempts:       20,
+				lockoutDuration:     15 * time.Minute,
+				progressiveDelay:    time.Second,
 			},
 			res: res{
 				want: &domain.ObjectDetails{
@@ -97,7 +118,7 @@ func TestCommandSide_AddDefaultLockoutPolicy(t *testing.T) {
 			r := &Commands{
 				eventstore: tt.fields.eventstore,
 			}
-			got, err := r.AddDefaultLockoutPolicy(tt.args.ctx, tt.args.maxPasswordAttempts, tt.args.showLockOutFailures)
+			got, err := r.AddDefaultLockoutPolicy(tt.args.ctx, tt.args.maxPasswordAttempts, tt.args.showLockOutFailures, tt.args.maxOTPAttempts, tt.args.maxPasskeyAttempts, tt.args.maxIPAttempts, tt.args.lockoutDuration, tt.args.progressiveDelay)
 			if tt.res.err == nil {
 				assert.NoError(t, err)
 			}
@@ -159,6 +180,11 @@ func TestCommandSide_ChangeDefaultLockoutPolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								10,
 								true,
+								0,
+								0,
+								0,
+								0,
+								0,
 							),
 						),
 					),
@@ -186,6 +212,11 @@ func TestCommandSide_ChangeDefaultLockoutPolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								10,
 								true,
+								0,
+								0,
+								0,
+								0,
+								0,
 							),
 						),
 					),
@@ -216,6 +247,71 @@ func TestCommandSide_ChangeDefaultLockoutPolicy(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "change lockout duration and delays, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewLockoutPolicyAddedEvent(context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								10,
+								true,
+								0,
+								0,
+								0,
+								0,
+								0,
+							),
+						),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(
+								func() *instance.LockoutPolicyChangedEvent {
+									event, _ := instance.NewLockoutPolicyChangedEvent(context.Background(),
+										&instance.NewAggregate("INSTANCE").Aggregate,
+										[]policy.LockoutPolicyChanges{
+											policy.ChangeMaxOTPAttempts(5),
+											policy.ChangeMaxIPAttempts(50),
+											policy.ChangeLockoutDuration(30 * time.Minute),
+											policy.ChangeProgressiveDelay(time.Second),
+										},
+									)
+									return event
+								}(),
+							),
+						},
+					),
+				),
+			},
+			args: args{
+				ctx: context.Background(),
+				policy: &domain.LockoutPolicy{
+					MaxPasswordAttempts: 10,
+					ShowLockOutFailures: true,
+					MaxOTPAttempts:      5,
+					MaxIPAttempts:       50,
+					LockoutDuration:     30 * time.Minute,
+					ProgressiveDelay:    time.Second,
+				},
+			},
+			res: res{
+				want: &domain.LockoutPolicy{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID:   "INSTANCE",
+						ResourceOwner: "INSTANCE",
+					},
+					MaxPasswordAttempts: 10,
+					ShowLockOutFailures: true,
+					MaxOTPAttempts:      5,
+					MaxIPAttempts:       50,
+					LockoutDuration:     30 * time.Minute,
+					ProgressiveDelay:    time.Second,
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
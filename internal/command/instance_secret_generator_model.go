@@ -20,6 +20,7 @@ type InstanceSecretGeneratorConfigWriteModel struct {
 	IncludeUpperLetters bool
 	IncludeDigits       bool
 	IncludeSymbols      bool
+	Hashed              bool
 	State               domain.SecretGeneratorState
 }
 
@@ -46,6 +47,7 @@ func (wm *InstanceSecretGeneratorConfigWriteModel) Reduce() error {
 			wm.IncludeUpperLetters = e.IncludeUpperLetters
 			wm.IncludeDigits = e.IncludeDigits
 			wm.IncludeSymbols = e.IncludeSymbols
+			wm.Hashed = e.Hashed
 			wm.State = domain.SecretGeneratorStateActive
 		case *instance.SecretGeneratorChangedEvent:
 			if wm.GeneratorType != e.GeneratorType {
@@ -69,6 +71,9 @@ func (wm *InstanceSecretGeneratorConfigWriteModel) Reduce() error {
 			if e.IncludeSymbols != nil {
 				wm.IncludeSymbols = *e.IncludeSymbols
 			}
+			if e.Hashed != nil {
+				wm.Hashed = *e.Hashed
+			}
 		case *instance.SecretGeneratorRemovedEvent:
 			if wm.GeneratorType != e.GeneratorType {
 				continue
@@ -80,6 +85,7 @@ func (wm *InstanceSecretGeneratorConfigWriteModel) Reduce() error {
 			wm.IncludeUpperLetters = false
 			wm.IncludeDigits = false
 			wm.IncludeSymbols = false
+			wm.Hashed = false
 		}
 	}
 	return wm.WriteModel.Reduce()
@@ -107,7 +113,8 @@ func (wm *InstanceSecretGeneratorConfigWriteModel) NewChangedEvent(
 	includeLowerLetters,
 	includeUpperLetters,
 	includeDigits,
-	includeSymbols bool,
+	includeSymbols,
+	hashed bool,
 ) (*instance.SecretGeneratorChangedEvent, bool, error) {
 	changes := make([]instance.SecretGeneratorChanges, 0)
 	var err error
@@ -130,6 +137,9 @@ func (wm *InstanceSecretGeneratorConfigWriteModel) NewChangedEvent(
 	if wm.IncludeSymbols != includeSymbols {
 		changes = append(changes, instance.ChangeSecretGeneratorIncludeSymbols(includeSymbols))
 	}
+	if wm.Hashed != hashed {
+		changes = append(changes, instance.ChangeSecretGeneratorHashed(hashed))
+	}
 	if len(changes) == 0 {
 		return nil, false, nil
 	}
@@ -38,6 +38,9 @@ func prepareAddSecretGeneratorConfig(a *instance.Aggregate, typ domain.SecretGen
 		if config.Length < 1 {
 			return nil, errors.ThrowInvalidArgument(nil, "V2-jEqCt", "Errors.InvalidArgument")
 		}
+		if config.Hashed && !typ.Hashable() {
+			return nil, errors.ThrowInvalidArgument(nil, "V2-ooR6e", "Errors.SecretGenerator.HashingNotSupported")
+		}
 		return func(ctx context.Context, filter preparation.FilterToQueryReducer) ([]eventstore.Command, error) {
 			writeModel := NewInstanceSecretGeneratorConfigWriteModel(ctx, typ)
 			events, err := filter(ctx, writeModel.Query())
@@ -64,6 +67,7 @@ func prepareAddSecretGeneratorConfig(a *instance.Aggregate, typ domain.SecretGen
 					config.IncludeUpperLetters,
 					config.IncludeDigits,
 					config.IncludeSymbols,
+					config.Hashed,
 				),
 			}, nil
 		}, nil
@@ -82,6 +86,9 @@ func (c *Commands) ChangeSecretGeneratorConfig(ctx context.Context, generatorTyp
 	if generatorWriteModel.State == domain.SecretGeneratorStateUnspecified || generatorWriteModel.State == domain.SecretGeneratorStateRemoved {
 		return nil, errors.ThrowNotFound(nil, "COMMAND-3n9ls", "Errors.SecretGenerator.NotFound")
 	}
+	if config.Hashed && !generatorType.Hashable() {
+		return nil, errors.ThrowInvalidArgument(nil, "COMMAND-Thoo5", "Errors.SecretGenerator.HashingNotSupported")
+	}
 	instanceAgg := InstanceAggregateFromWriteModel(&generatorWriteModel.WriteModel)
 
 	changedEvent, hasChanged, err := generatorWriteModel.NewChangedEvent(
@@ -93,7 +100,8 @@ func (c *Commands) ChangeSecretGeneratorConfig(ctx context.Context, generatorTyp
 		config.IncludeLowerLetters,
 		config.IncludeUpperLetters,
 		config.IncludeDigits,
-		config.IncludeSymbols)
+		config.IncludeSymbols,
+		config.Hashed)
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +51,25 @@ func TestCommandSide_AddSecretGenerator(t *testing.T) {
 				err: caos_errs.IsErrorInvalidArgument,
 			},
 		},
+		{
+			name: "hashed codes of type not supported, invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+				),
+			},
+			args: args{
+				ctx: context.Background(),
+				generator: &crypto.GeneratorConfig{
+					Length: 4,
+					Hashed: true,
+				},
+				generatorType: domain.SecretGeneratorTypeAppSecret,
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
 		{
 			name: "secret generator config, error already exists",
 			fields: fields{
@@ -67,6 +86,7 @@ func TestCommandSide_AddSecretGenerator(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 					),
@@ -108,6 +128,7 @@ func TestCommandSide_AddSecretGenerator(t *testing.T) {
 									true,
 									true,
 									true,
+									false,
 								),
 							),
 						},
@@ -222,6 +243,7 @@ func TestCommandSide_ChangeSecretGenerator(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 						eventFromEventPusher(
@@ -257,6 +279,7 @@ func TestCommandSide_ChangeSecretGenerator(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 					),
@@ -295,6 +318,7 @@ func TestCommandSide_ChangeSecretGenerator(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 					),
@@ -332,6 +356,104 @@ func TestCommandSide_ChangeSecretGenerator(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "hashed codes of type not supported, invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewSecretGeneratorAddedEvent(
+								context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								domain.SecretGeneratorTypeVerifyDomain,
+								4,
+								time.Hour*1,
+								true,
+								true,
+								true,
+								true,
+								false,
+							),
+						),
+					),
+				),
+			},
+			args: args{
+				ctx: context.Background(),
+				generator: &crypto.GeneratorConfig{
+					Length:              4,
+					Expiry:              1 * time.Hour,
+					IncludeLowerLetters: true,
+					IncludeUpperLetters: true,
+					IncludeDigits:       true,
+					IncludeSymbols:      true,
+					Hashed:              true,
+				},
+				generatorType: domain.SecretGeneratorTypeVerifyDomain,
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "secret generator change hashed, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewSecretGeneratorAddedEvent(
+								context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								domain.SecretGeneratorTypePasswordResetCode,
+								4,
+								time.Hour*1,
+								true,
+								true,
+								true,
+								true,
+								false,
+							),
+						),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(
+								func() *instance.SecretGeneratorChangedEvent {
+									event, _ := instance.NewSecretGeneratorChangeEvent(context.Background(),
+										&instance.NewAggregate("INSTANCE").Aggregate,
+										domain.SecretGeneratorTypePasswordResetCode,
+										[]instance.SecretGeneratorChanges{
+											instance.ChangeSecretGeneratorHashed(true),
+										},
+									)
+									return event
+								}(),
+							),
+						},
+					),
+				),
+			},
+			args: args{
+				ctx: context.Background(),
+				generator: &crypto.GeneratorConfig{
+					Length:              4,
+					Expiry:              1 * time.Hour,
+					IncludeLowerLetters: true,
+					IncludeUpperLetters: true,
+					IncludeDigits:       true,
+					IncludeSymbols:      true,
+					Hashed:              true,
+				},
+				generatorType: domain.SecretGeneratorTypePasswordResetCode,
+			},
+			res: res{
+				want: &domain.ObjectDetails{
+					ResourceOwner: "INSTANCE",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -419,6 +541,7 @@ func TestCommandSide_RemoveSecretGenerator(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 						eventFromEventPusher(
@@ -454,6 +577,7 @@ func TestCommandSide_RemoveSecretGenerator(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 					),
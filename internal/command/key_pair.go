@@ -2,8 +2,8 @@ package command
 
 import (
 	"context"
+	gocrypto "crypto"
 	"crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"math/big"
 	"time"
@@ -15,7 +15,7 @@ import (
 )
 
 func (c *Commands) GenerateSigningKeyPair(ctx context.Context, algorithm string) error {
-	privateCrypto, publicCrypto, err := crypto.GenerateEncryptedKeyPair(c.keySize, c.keyAlgorithm)
+	privateCrypto, publicCrypto, err := crypto.GenerateEncryptedSigningKeyPair(algorithm, c.keySize, c.keyPairAlgorithm)
 	if err != nil {
 		return err
 	}
@@ -47,7 +47,7 @@ func (c *Commands) GenerateSAMLCACertificate(ctx context.Context, algorithm stri
 		return err
 	}
 
-	privateCrypto, publicCrypto, certificateCrypto, err := crypto.GenerateEncryptedKeyPairWithCACertificate(c.certKeySize, c.keyAlgorithm, c.certificateAlgorithm, &crypto.CertificateInformations{
+	privateCrypto, publicCrypto, certificateCrypto, err := crypto.GenerateEncryptedSigningKeyPairWithCACertificate(algorithm, c.certKeySize, c.keyPairAlgorithm, c.certificateAlgorithm, &crypto.CertificateInformations{
 		SerialNumber: randInt,
 		Organisation: []string{"ZITADEL"},
 		CommonName:   "ZITADEL SAML CA",
@@ -84,7 +84,7 @@ func (c *Commands) GenerateSAMLCACertificate(ctx context.Context, algorithm stri
 	return err
 }
 
-func (c *Commands) GenerateSAMLResponseCertificate(ctx context.Context, algorithm string, caPrivateKey *rsa.PrivateKey, caCertificate []byte) error {
+func (c *Commands) GenerateSAMLResponseCertificate(ctx context.Context, algorithm string, caPrivateKey gocrypto.Signer, caCertificate []byte) error {
 	now := time.Now().UTC()
 	after := now.Add(c.certificateLifetime)
 	randInt, err := rand.Int(rand.Reader, big.NewInt(1000))
@@ -92,7 +92,7 @@ func (c *Commands) GenerateSAMLResponseCertificate(ctx context.Context, algorith
 		return err
 	}
 
-	privateCrypto, publicCrypto, certificateCrypto, err := crypto.GenerateEncryptedKeyPairWithCertificate(c.certKeySize, c.keyAlgorithm, c.certificateAlgorithm, caPrivateKey, caCertificate, &crypto.CertificateInformations{
+	privateCrypto, publicCrypto, certificateCrypto, err := crypto.GenerateEncryptedSigningKeyPairWithCertificate(algorithm, c.certKeySize, c.keyPairAlgorithm, c.certificateAlgorithm, caPrivateKey, caCertificate, &crypto.CertificateInformations{
 		SerialNumber: randInt,
 		Organisation: []string{"ZITADEL"},
 		CommonName:   "ZITADEL SAML response",
@@ -130,14 +130,14 @@ func (c *Commands) GenerateSAMLResponseCertificate(ctx context.Context, algorith
 	return err
 }
 
-func (c *Commands) GenerateSAMLMetadataCertificate(ctx context.Context, algorithm string, caPrivateKey *rsa.PrivateKey, caCertificate []byte) error {
+func (c *Commands) GenerateSAMLMetadataCertificate(ctx context.Context, algorithm string, caPrivateKey gocrypto.Signer, caCertificate []byte) error {
 	now := time.Now().UTC()
 	after := now.Add(c.certificateLifetime)
 	randInt, err := rand.Int(rand.Reader, big.NewInt(1000))
 	if err != nil {
 		return err
 	}
-	privateCrypto, publicCrypto, certificateCrypto, err := crypto.GenerateEncryptedKeyPairWithCertificate(c.certKeySize, c.keyAlgorithm, c.certificateAlgorithm, caPrivateKey, caCertificate, &crypto.CertificateInformations{
+	privateCrypto, publicCrypto, certificateCrypto, err := crypto.GenerateEncryptedSigningKeyPairWithCertificate(algorithm, c.certKeySize, c.keyPairAlgorithm, c.certificateAlgorithm, caPrivateKey, caCertificate, &crypto.CertificateInformations{
 		SerialNumber: randInt,
 		Organisation: []string{"ZITADEL"},
 		CommonName:   "ZITADEL SAML metadata",
@@ -0,0 +1,458 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
+	"github.com/zitadel/zitadel/internal/idp"
+	"github.com/zitadel/zitadel/internal/idp/providers/ldap"
+)
+
+// ldapSyncUserID is the editor of the changes of the sync,
+// it marks the users deactivated by the sync, so only they are reactivated by it
+const ldapSyncUserID = "LDAP-SYNC"
+
+// LDAPSyncOptions configure the sync of the users of an LDAP identity provider
+type LDAPSyncOptions struct {
+	// PageSize is the amount of entries requested from the LDAP server per page
+	PageSize uint32
+	// MaxDeactivations is the maximum amount of users deactivated by a single sync,
+	// if more users are missing in the directory (e.g. because of a misconfigured base), none are deactivated.
+	// 0 disables the limit.
+	MaxDeactivations int
+	// DryRun only computes the summary, without any change to the users
+	DryRun bool
+}
+
+// LDAPSyncSummary is the diff between the directory of an LDAP identity provider and the users linked to it.
+// The users are listed by their id in the directory.
+type LDAPSyncSummary struct {
+	IDPID  string
+	DryRun bool
+
+	Added       []string
+	Updated     []string
+	Deactivated []string
+	Reactivated []string
+	Unchanged   int
+	// DeactivationAborted contains the users missing in the directory,
+	// which were not deactivated, because they exceed the maximum deactivations
+	DeactivationAborted []string
+	// Failed contains the users, which could not be synced, and the reason
+	Failed map[string]error
+}
+
+func (s *LDAPSyncSummary) record(users *[]string, externalUserID string, err error) {
+	if err != nil {
+		s.Failed[externalUserID] = err
+		return
+	}
+	*users = append(*users, externalUserID)
+}
+
+// LDAPSyncJob is the sync of the users of a single LDAP identity provider
+type LDAPSyncJob struct {
+	InstanceID string
+	IDPID      string
+}
+
+// LDAPSyncJobs returns the LDAP identity providers of all instances, which have to be synced.
+// Failing instances are logged and don't prevent the jobs of the others.
+func (c *Commands) LDAPSyncJobs(ctx context.Context) ([]*LDAPSyncJob, error) {
+	instanceIDs, err := c.activeInstanceIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*LDAPSyncJob, 0)
+	for _, instanceID := range instanceIDs {
+		idps := NewLDAPIDPsReadModel()
+		if err := c.eventstore.FilterToQueryReducer(ldapSyncContext(ctx, instanceID), idps); err != nil {
+			logging.WithFields("instance", instanceID).WithError(err).Warn("unable to read ldap identity providers")
+			continue
+		}
+		for _, idpID := range idps.IDPIDs {
+			jobs = append(jobs, &LDAPSyncJob{InstanceID: instanceID, IDPID: idpID})
+		}
+	}
+	return jobs, nil
+}
+
+// SyncLDAPJob syncs the users of the identity provider of the job, see [Commands.SyncLDAPUsers]
+func (c *Commands) SyncLDAPJob(ctx context.Context, job *LDAPSyncJob, options *LDAPSyncOptions) (*LDAPSyncSummary, error) {
+	return c.SyncLDAPUsers(ldapSyncContext(ctx, job.InstanceID), job.IDPID, options)
+}
+
+func ldapSyncContext(ctx context.Context, instanceID string) context.Context {
+	return authz.SetCtxData(authz.WithInstanceID(ctx, instanceID), authz.CtxData{UserID: ldapSyncUserID})
+}
+
+// SyncLDAPUsers pages through the directory of the LDAP identity provider and syncs the linked users:
+//   - users of the directory without a linked user are created, if auto creation is enabled
+//   - linked users are updated with the information of the directory, if auto update is enabled
+//   - linked users, which are no longer part of the directory, are deactivated and reactivated as soon as they reappear,
+//     users deactivated by anyone else than the sync are not reactivated
+//   - the claim mappings of the identity provider are applied to the users of the directory,
+//     the mapped metadata is set and the roles allowed by the mappings are synced (see [Commands.SyncUserGrantsByIDPClaims])
+//
+// On a dry run only the summary is computed, without any change to the users.
+func (c *Commands) SyncLDAPUsers(ctx context.Context, idpID string, options *LDAPSyncOptions) (*LDAPSyncSummary, error) {
+	if idpID == "" {
+		return nil, caos_errs.ThrowInvalidArgument(nil, "COMMAND-Ld9sw", "Errors.IDMissing")
+	}
+	writeModel, err := IDPProviderWriteModel(ctx, c.eventstore.Filter, idpID)
+	if err != nil {
+		return nil, err
+	}
+	if writeModel.IDPType != domain.IDPTypeLDAP {
+		return nil, caos_errs.ThrowPreconditionFailed(nil, "COMMAND-Lp3sd", "Errors.IDPConfig.NotLDAP")
+	}
+	provider, err := writeModel.ToProvider("", c.idpConfigEncryption)
+	if err != nil {
+		return nil, err
+	}
+	ldapProvider, ok := provider.(*ldap.Provider)
+	if !ok {
+		return nil, caos_errs.ThrowPreconditionFailed(nil, "COMMAND-Lp3se", "Errors.IDPConfig.NotLDAP")
+	}
+	mappings, err := c.idpClaimMappings(ctx, writeModel)
+	if err != nil {
+		return nil, err
+	}
+	directoryUsers, err := ldapProvider.Users(ctx, options.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return c.syncLDAPDirectoryUsers(ctx, writeModel, ldapProvider, mappings, directoryUsers, options)
+}
+
+// idpClaimMappings returns the claim mappings of the identity provider of the instance respectively the organisation
+func (c *Commands) idpClaimMappings(ctx context.Context, writeModel *AllIDPWriteModel) ([]*domain.IDPClaimMapping, error) {
+	if writeModel.Instance {
+		mappings := NewInstanceIDPClaimMappingsWriteModel(writeModel.ResourceOwner, writeModel.ID)
+		if err := c.eventstore.FilterToQueryReducer(ctx, mappings); err != nil {
+			return nil, err
+		}
+		return mappings.Mappings, nil
+	}
+	mappings := NewOrgIDPClaimMappingsWriteModel(writeModel.ResourceOwner, writeModel.ID)
+	if err := c.eventstore.FilterToQueryReducer(ctx, mappings); err != nil {
+		return nil, err
+	}
+	return mappings.Mappings, nil
+}
+
+// syncLDAPDirectoryUsers compares the users of the directory with the users linked to the identity provider
+func (c *Commands) syncLDAPDirectoryUsers(ctx context.Context, writeModel *AllIDPWriteModel, provider idp.Provider, mappings []*domain.IDPClaimMapping, directoryUsers []*ldap.User, options *LDAPSyncOptions) (*LDAPSyncSummary, error) {
+	dryRun := options.DryRun
+	idpID := writeModel.ID
+	links := NewIDPLinkedUsersReadModel(idpID)
+	if err := c.eventstore.FilterToQueryReducer(ctx, links); err != nil {
+		return nil, err
+	}
+
+	summary := &LDAPSyncSummary{
+		IDPID:  idpID,
+		DryRun: dryRun,
+		Failed: make(map[string]error),
+	}
+	managedRoles := idp.ManagedRoles(mappings)
+	inDirectory := make(map[string]bool, len(directoryUsers))
+	for _, directoryUser := range directoryUsers {
+		externalUserID := directoryUser.GetID()
+		inDirectory[externalUserID] = true
+		link, ok := links.Users[externalUserID]
+		if !ok && (!provider.IsCreationAllowed() || !provider.IsAutoCreation()) {
+			continue
+		}
+		mappedUser, err := idp.MapUser(directoryUser, mappings)
+		if err != nil {
+			summary.Failed[externalUserID] = err
+			continue
+		}
+		if !ok {
+			resourceOwner, err := c.ldapSyncResourceOwner(ctx, writeModel)
+			if err == nil && !dryRun {
+				err = c.addLDAPSyncUser(ctx, resourceOwner, idpID, mappedUser, managedRoles)
+			}
+			summary.record(&summary.Added, externalUserID, err)
+			continue
+		}
+		human, err := c.getHumanWriteModelByID(ctx, link.UserID, link.ResourceOwner)
+		if err != nil {
+			summary.Failed[externalUserID] = err
+			continue
+		}
+		c.syncLinkedLDAPUser(ctx, summary, human, mappedUser, managedRoles, provider.IsAutoUpdate(), dryRun)
+	}
+
+	externalUserIDs := make([]string, 0, len(links.Users))
+	for externalUserID := range links.Users {
+		externalUserIDs = append(externalUserIDs, externalUserID)
+	}
+	sort.Strings(externalUserIDs)
+	deactivations := make([]idpLinkedUser, 0)
+	deactivationIDs := make([]string, 0)
+	for _, externalUserID := range externalUserIDs {
+		if inDirectory[externalUserID] {
+			continue
+		}
+		link := links.Users[externalUserID]
+		human, err := c.getHumanWriteModelByID(ctx, link.UserID, link.ResourceOwner)
+		if err != nil {
+			summary.Failed[externalUserID] = err
+			continue
+		}
+		if human.UserState != domain.UserStateActive {
+			continue
+		}
+		deactivations = append(deactivations, link)
+		deactivationIDs = append(deactivationIDs, externalUserID)
+	}
+	if options.MaxDeactivations > 0 && len(deactivations) > options.MaxDeactivations {
+		summary.DeactivationAborted = deactivationIDs
+		return summary, nil
+	}
+	for i, link := range deactivations {
+		var err error
+		if !dryRun {
+			_, err = c.DeactivateUser(ctx, link.UserID, link.ResourceOwner)
+		}
+		summary.record(&summary.Deactivated, deactivationIDs[i], err)
+	}
+	return summary, nil
+}
+
+// ldapSyncResourceOwner returns the organisation of the identity provider,
+// respectively the default organisation if it's an identity provider of the instance
+func (c *Commands) ldapSyncResourceOwner(ctx context.Context, writeModel *AllIDPWriteModel) (string, error) {
+	if !writeModel.Instance {
+		return writeModel.ResourceOwner, nil
+	}
+	instanceWriteModel, err := c.getInstanceWriteModelByID(ctx, writeModel.ResourceOwner)
+	if err != nil {
+		return "", err
+	}
+	return instanceWriteModel.DefaultOrgID, nil
+}
+
+// addLDAPSyncUser creates the user with the mapped metadata and grants the mapped roles
+func (c *Commands) addLDAPSyncUser(ctx context.Context, resourceOwner, idpID string, directoryUser *idp.MappedUser, managedRoles map[string][]string) error {
+	human := &AddHuman{
+		Username:          directoryUser.GetPreferredUsername(),
+		FirstName:         directoryUser.GetFirstName(),
+		LastName:          directoryUser.GetLastName(),
+		NickName:          directoryUser.GetNickname(),
+		DisplayName:       directoryUser.GetDisplayName(),
+		Email:             Email{Address: directoryUser.GetEmail(), Verified: directoryUser.IsEmailVerified()},
+		PreferredLanguage: directoryUser.GetPreferredLanguage(),
+		Phone:             Phone{Number: directoryUser.GetPhone(), Verified: directoryUser.IsPhoneVerified()},
+		ExternalIDP:       true,
+		Links: []*AddLink{
+			{
+				IDPID:         idpID,
+				DisplayName:   directoryUser.GetPreferredUsername(),
+				IDPExternalID: directoryUser.GetID(),
+			},
+		},
+		Metadata: ldapSyncMetadataEntries(directoryUser.Metadata),
+	}
+	if err := c.AddHuman(ctx, resourceOwner, human, false); err != nil {
+		return err
+	}
+	if len(managedRoles) == 0 {
+		return nil
+	}
+	return c.SyncUserGrantsByIDPClaims(ctx, human.ID, resourceOwner, directoryUser.Roles, managedRoles, nil)
+}
+
+func ldapSyncMetadataEntries(metadata map[string][]byte) []*AddMetadataEntry {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]*AddMetadataEntry, len(keys))
+	for i, key := range keys {
+		entries[i] = &AddMetadataEntry{Key: key, Value: metadata[key]}
+	}
+	return entries
+}
+
+func (c *Commands) syncLinkedLDAPUser(ctx context.Context, summary *LDAPSyncSummary, human *HumanWriteModel, directoryUser *idp.MappedUser, managedRoles map[string][]string, autoUpdate, dryRun bool) {
+	externalUserID := directoryUser.GetID()
+	if !isUserStateExists(human.UserState) {
+		// the user was removed, but not its link
+		return
+	}
+	changed := false
+	if isUserStateInactive(human.UserState) {
+		deactivation := NewLDAPSyncDeactivationReadModel(human.AggregateID, human.ResourceOwner)
+		err := c.eventstore.FilterToQueryReducer(ctx, deactivation)
+		if err == nil && !deactivation.DeactivatedBySync {
+			// the user was deactivated by an administrator
+			summary.Unchanged++
+			return
+		}
+		changed = true
+		if err == nil && !dryRun {
+			_, err = c.ReactivateUser(ctx, human.AggregateID, human.ResourceOwner)
+		}
+		summary.record(&summary.Reactivated, externalUserID, err)
+	}
+	metadata, err := c.ldapSyncChangedMetadata(ctx, human, directoryUser.Metadata)
+	if err != nil {
+		summary.Failed[externalUserID] = err
+		return
+	}
+	profileChanged := autoUpdate && ldapSyncUserChanged(human, directoryUser)
+	if profileChanged || len(metadata) > 0 {
+		changed = true
+		if !dryRun && profileChanged {
+			err = c.updateLDAPSyncUser(ctx, human, directoryUser)
+		}
+		if err == nil && !dryRun && len(metadata) > 0 {
+			_, err = c.BulkSetUserMetadata(ctx, human.AggregateID, human.ResourceOwner, metadata...)
+		}
+		summary.record(&summary.Updated, externalUserID, err)
+	}
+	if !changed {
+		summary.Unchanged++
+	}
+	// the grants are synced in any case, as their changes aren't part of the summary
+	if dryRun || len(managedRoles) == 0 {
+		return
+	}
+	if err = c.syncLDAPUserRoles(ctx, human, directoryUser.Roles, managedRoles); err != nil {
+		summary.Failed[externalUserID] = err
+	}
+}
+
+// ldapSyncChangedMetadata returns the mapped metadata, which differs from the metadata of the user
+func (c *Commands) ldapSyncChangedMetadata(ctx context.Context, human *HumanWriteModel, mapped map[string][]byte) ([]*domain.Metadata, error) {
+	if len(mapped) == 0 {
+		return nil, nil
+	}
+	existing := NewUserMetadataListWriteModel(human.AggregateID, human.ResourceOwner)
+	if err := c.eventstore.FilterToQueryReducer(ctx, existing); err != nil {
+		return nil, err
+	}
+	changed := make([]*domain.Metadata, 0, len(mapped))
+	for _, entry := range ldapSyncMetadataEntries(mapped) {
+		if value, ok := existing.metadataList[entry.Key]; !ok || !bytes.Equal(value, entry.Value) {
+			changed = append(changed, &domain.Metadata{Key: entry.Key, Value: entry.Value})
+		}
+	}
+	return changed, nil
+}
+
+// syncLDAPUserRoles syncs the grants of the user with its mapped roles
+func (c *Commands) syncLDAPUserRoles(ctx context.Context, human *HumanWriteModel, roles, managedRoles map[string][]string) error {
+	grants := NewUserGrantIDsReadModel(human.AggregateID, human.ResourceOwner)
+	if err := c.eventstore.FilterToQueryReducer(ctx, grants); err != nil {
+		return err
+	}
+	return c.SyncUserGrantsByIDPClaims(ctx, human.AggregateID, human.ResourceOwner, roles, managedRoles, grants.GrantIDs)
+}
+
+func ldapSyncUserChanged(human *HumanWriteModel, directoryUser idp.User) bool {
+	return ldapSyncProfileChanged(human, directoryUser) ||
+		ldapSyncEmailChanged(human, directoryUser) ||
+		ldapSyncPhoneChanged(human, directoryUser)
+}
+
+// ldapSyncProfileChanged ignores incomplete profiles, as they can't be set on the user
+func ldapSyncProfileChanged(human *HumanWriteModel, directoryUser idp.User) bool {
+	if directoryUser.GetFirstName() == "" || directoryUser.GetLastName() == "" {
+		return false
+	}
+	return human.FirstName != directoryUser.GetFirstName() ||
+		human.LastName != directoryUser.GetLastName() ||
+		human.NickName != directoryUser.GetNickname() ||
+		(directoryUser.GetDisplayName() != "" && human.DisplayName != directoryUser.GetDisplayName()) ||
+		human.PreferredLanguage != directoryUser.GetPreferredLanguage()
+}
+
+// ldapSyncEmailChanged ignores if the same email isn't verified anymore
+func ldapSyncEmailChanged(human *HumanWriteModel, directoryUser idp.User) bool {
+	email := directoryUser.GetEmail().Normalize()
+	if email == "" {
+		return false
+	}
+	if email == human.Email {
+		return !human.IsEmailVerified && directoryUser.IsEmailVerified()
+	}
+	return true
+}
+
+// ldapSyncPhoneChanged ignores invalid numbers and if the same phone isn't verified anymore
+func ldapSyncPhoneChanged(human *HumanWriteModel, directoryUser idp.User) bool {
+	if directoryUser.GetPhone() == "" {
+		return false
+	}
+	phone, err := directoryUser.GetPhone().Normalize()
+	if err != nil {
+		return false
+	}
+	if phone == human.Phone {
+		return !human.IsPhoneVerified && directoryUser.IsPhoneVerified()
+	}
+	return true
+}
+
+func (c *Commands) updateLDAPSyncUser(ctx context.Context, human *HumanWriteModel, directoryUser idp.User) error {
+	objectRoot := models.ObjectRoot{AggregateID: human.AggregateID, ResourceOwner: human.ResourceOwner}
+	if ldapSyncProfileChanged(human, directoryUser) {
+		displayName := directoryUser.GetDisplayName()
+		if displayName == "" {
+			displayName = human.DisplayName
+		}
+		_, err := c.ChangeHumanProfile(ctx, &domain.Profile{
+			ObjectRoot:        objectRoot,
+			FirstName:         directoryUser.GetFirstName(),
+			LastName:          directoryUser.GetLastName(),
+			NickName:          directoryUser.GetNickname(),
+			DisplayName:       displayName,
+			PreferredLanguage: directoryUser.GetPreferredLanguage(),
+			Gender:            human.Gender,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if ldapSyncEmailChanged(human, directoryUser) {
+		emailCodeGenerator, _, err := secretGenerator(ctx, c.eventstore.Filter, domain.SecretGeneratorTypeVerifyEmailCode, c.userEncryption)
+		if err != nil {
+			return err
+		}
+		_, err = c.ChangeHumanEmail(ctx, &domain.Email{
+			ObjectRoot:      objectRoot,
+			EmailAddress:    directoryUser.GetEmail(),
+			IsEmailVerified: directoryUser.IsEmailVerified(),
+		}, emailCodeGenerator)
+		if err != nil {
+			return err
+		}
+	}
+	if ldapSyncPhoneChanged(human, directoryUser) {
+		phoneCodeGenerator, _, err := secretGenerator(ctx, c.eventstore.Filter, domain.SecretGeneratorTypeVerifyPhoneCode, c.userEncryption)
+		if err != nil {
+			return err
+		}
+		_, err = c.ChangeHumanPhone(ctx, &domain.Phone{
+			ObjectRoot:      objectRoot,
+			PhoneNumber:     directoryUser.GetPhone(),
+			IsPhoneVerified: directoryUser.IsPhoneVerified(),
+		}, human.ResourceOwner, phoneCodeGenerator)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
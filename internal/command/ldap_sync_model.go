@@ -0,0 +1,190 @@
+package command
+
+import (
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/repository/usergrant"
+)
+
+// LDAPIDPsReadModel contains the ids of all active LDAP identity providers of an instance (incl. the ones of organisations)
+type LDAPIDPsReadModel struct {
+	eventstore.WriteModel
+
+	IDPIDs []string
+}
+
+func NewLDAPIDPsReadModel() *LDAPIDPsReadModel {
+	return &LDAPIDPsReadModel{}
+}
+
+func (rm *LDAPIDPsReadModel) Reduce() error {
+	for _, event := range rm.Events {
+		switch e := event.(type) {
+		case *instance.LDAPIDPAddedEvent:
+			rm.IDPIDs = append(rm.IDPIDs, e.ID)
+		case *org.LDAPIDPAddedEvent:
+			rm.IDPIDs = append(rm.IDPIDs, e.ID)
+		case *instance.IDPRemovedEvent:
+			rm.remove(e.ID)
+		case *org.IDPRemovedEvent:
+			rm.remove(e.ID)
+		}
+	}
+	return rm.WriteModel.Reduce()
+}
+
+func (rm *LDAPIDPsReadModel) remove(id string) {
+	for i, idpID := range rm.IDPIDs {
+		if idpID == id {
+			rm.IDPIDs = append(rm.IDPIDs[:i], rm.IDPIDs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (rm *LDAPIDPsReadModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		EventTypes(
+			instance.LDAPIDPAddedEventType,
+			instance.IDPRemovedEventType,
+		).
+		Or().
+		AggregateTypes(org.AggregateType).
+		EventTypes(
+			org.LDAPIDPAddedEventType,
+			org.IDPRemovedEventType,
+		).
+		Builder()
+}
+
+type idpLinkedUser struct {
+	UserID        string
+	ResourceOwner string
+}
+
+// IDPLinkedUsersReadModel maps the ids of the users at the identity provider to the linked users
+type IDPLinkedUsersReadModel struct {
+	eventstore.WriteModel
+
+	IDPID string
+	Users map[string]idpLinkedUser
+}
+
+func NewIDPLinkedUsersReadModel(idpID string) *IDPLinkedUsersReadModel {
+	return &IDPLinkedUsersReadModel{
+		IDPID: idpID,
+		Users: make(map[string]idpLinkedUser),
+	}
+}
+
+func (rm *IDPLinkedUsersReadModel) Reduce() error {
+	for _, event := range rm.Events {
+		switch e := event.(type) {
+		case *user.UserIDPLinkAddedEvent:
+			rm.Users[e.ExternalUserID] = idpLinkedUser{
+				UserID:        e.Aggregate().ID,
+				ResourceOwner: e.Aggregate().ResourceOwner,
+			}
+		case *user.UserIDPLinkRemovedEvent:
+			delete(rm.Users, e.ExternalUserID)
+		case *user.UserIDPLinkCascadeRemovedEvent:
+			delete(rm.Users, e.ExternalUserID)
+		}
+	}
+	return rm.WriteModel.Reduce()
+}
+
+func (rm *IDPLinkedUsersReadModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(user.AggregateType).
+		EventTypes(
+			user.UserIDPLinkAddedType,
+			user.UserIDPLinkRemovedType,
+			user.UserIDPLinkCascadeRemovedType,
+		).
+		EventData(map[string]interface{}{"idpConfigId": rm.IDPID}).
+		Builder()
+}
+
+// LDAPSyncDeactivationReadModel checks if the user was deactivated by the sync of the LDAP users
+type LDAPSyncDeactivationReadModel struct {
+	eventstore.WriteModel
+
+	DeactivatedBySync bool
+}
+
+func NewLDAPSyncDeactivationReadModel(userID, resourceOwner string) *LDAPSyncDeactivationReadModel {
+	return &LDAPSyncDeactivationReadModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   userID,
+			ResourceOwner: resourceOwner,
+		},
+	}
+}
+
+func (rm *LDAPSyncDeactivationReadModel) Reduce() error {
+	for _, event := range rm.Events {
+		switch e := event.(type) {
+		case *user.UserDeactivatedEvent:
+			rm.DeactivatedBySync = e.EditorUser() == ldapSyncUserID
+		case *user.UserReactivatedEvent:
+			rm.DeactivatedBySync = false
+		}
+	}
+	return rm.WriteModel.Reduce()
+}
+
+func (rm *LDAPSyncDeactivationReadModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(rm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(user.AggregateType).
+		AggregateIDs(rm.AggregateID).
+		EventTypes(
+			user.UserDeactivatedType,
+			user.UserReactivatedType,
+		).
+		Builder()
+}
+
+// UserGrantIDsReadModel collects the ids of the user grants added to a user,
+// the state of the grants has to be checked by their write model
+type UserGrantIDsReadModel struct {
+	eventstore.WriteModel
+
+	UserID   string
+	GrantIDs []string
+}
+
+func NewUserGrantIDsReadModel(userID, resourceOwner string) *UserGrantIDsReadModel {
+	return &UserGrantIDsReadModel{
+		WriteModel: eventstore.WriteModel{
+			ResourceOwner: resourceOwner,
+		},
+		UserID: userID,
+	}
+}
+
+func (rm *UserGrantIDsReadModel) Reduce() error {
+	for _, event := range rm.Events {
+		if e, ok := event.(*usergrant.UserGrantAddedEvent); ok && e.UserID == rm.UserID {
+			rm.GrantIDs = append(rm.GrantIDs, e.Aggregate().ID)
+		}
+	}
+	return rm.WriteModel.Reduce()
+}
+
+func (rm *UserGrantIDsReadModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(rm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(usergrant.AggregateType).
+		EventTypes(usergrant.UserGrantAddedType).
+		EventData(map[string]interface{}{"userId": rm.UserID}).
+		Builder()
+}
@@ -0,0 +1,481 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/idp/providers/ldap"
+	"github.com/zitadel/zitadel/internal/repository/project"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/repository/usergrant"
+)
+
+func TestCommandSide_syncLDAPDirectoryUsers(t *testing.T) {
+	type fields struct {
+		eventstore *eventstore.Eventstore
+	}
+	type args struct {
+		ctx            context.Context
+		provider       *ldap.Provider
+		mappings       []*domain.IDPClaimMapping
+		directoryUsers []*ldap.User
+		options        *LDAPSyncOptions
+	}
+	type res struct {
+		want *LDAPSyncSummary
+		err  func(error) bool
+	}
+	writeModel := &AllIDPWriteModel{
+		ID:            "idp1",
+		ResourceOwner: "org1",
+		IDPType:       domain.IDPTypeLDAP,
+	}
+	provider := func(options ...ldap.ProviderOpts) *ldap.Provider {
+		return ldap.New("ldap", []string{"ldaps://localhost:636"}, "dc=example,dc=com", "cn=admin,dc=example,dc=com", "password", "uid", []string{"inetOrgPerson"}, nil, time.Second, "", options...)
+	}
+	directoryUser := func(id string) *ldap.User {
+		return ldap.NewUser(id, "firstname", "lastname", "", "", id, "", false, "", false, language.Und, "", "")
+	}
+	humanAdded := func(userID string) *repository.Event {
+		return eventFromEventPusher(
+			user.NewHumanAddedEvent(context.Background(),
+				&user.NewAggregate(userID, "org1").Aggregate,
+				"username",
+				"firstname",
+				"lastname",
+				"",
+				"firstname lastname",
+				language.Und,
+				domain.GenderUnspecified,
+				"email@test.ch",
+				true,
+			),
+		)
+	}
+	linkAdded := func(userID, externalUserID string) *repository.Event {
+		return eventFromEventPusher(
+			user.NewUserIDPLinkAddedEvent(context.Background(),
+				&user.NewAggregate(userID, "org1").Aggregate,
+				"idp1",
+				externalUserID,
+				externalUserID,
+			),
+		)
+	}
+	userDeactivated := func(userID string) *repository.Event {
+		return eventFromEventPusher(
+			user.NewUserDeactivatedEvent(context.Background(),
+				&user.NewAggregate(userID, "org1").Aggregate,
+			),
+		)
+	}
+	userReactivated := func(userID string) *repository.Event {
+		return eventFromEventPusher(
+			user.NewUserReactivatedEvent(context.Background(),
+				&user.NewAggregate(userID, "org1").Aggregate,
+			),
+		)
+	}
+	syncCtx := authz.SetCtxData(context.Background(), authz.CtxData{UserID: ldapSyncUserID})
+	userDeactivatedBySync := func(userID string) *repository.Event {
+		return eventFromEventPusher(
+			user.NewUserDeactivatedEvent(syncCtx,
+				&user.NewAggregate(userID, "org1").Aggregate,
+			),
+		)
+	}
+	mappings := []*domain.IDPClaimMapping{
+		{Claim: "preferred_username", Target: domain.IDPClaimMappingTargetMetadata, Key: "uid"},
+		{Claim: "department", Target: domain.IDPClaimMappingTargetRole, Key: "project1", Default: "viewer", Roles: []string{"admin", "viewer"}},
+	}
+	projectRoles := func() expect {
+		return expectFilter(
+			humanAdded("user1"),
+			eventFromEventPusher(
+				project.NewProjectAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"projectname1", true, true, true,
+					domain.PrivateLabelingSettingUnspecified,
+				),
+			),
+			eventFromEventPusher(
+				project.NewRoleAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"admin", "admin", "",
+				),
+			),
+			eventFromEventPusher(
+				project.NewRoleAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"viewer", "viewer", "",
+				),
+			),
+			eventFromEventPusher(
+				project.NewRoleAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"manual", "manual", "",
+				),
+			),
+		)
+	}
+	userGrantAdded := func(roleKeys ...string) *repository.Event {
+		return eventFromEventPusher(
+			usergrant.NewUserGrantAddedEvent(context.Background(),
+				&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+				"user1",
+				"project1",
+				"",
+				roleKeys,
+			),
+		)
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "dry run, summary only",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						linkAdded("user1", "ext1"),
+						linkAdded("user2", "ext2"),
+					),
+					expectFilter(
+						humanAdded("user1"),
+					),
+					expectFilter(
+						humanAdded("user2"),
+					),
+				),
+			},
+			args: args{
+				ctx:            context.Background(),
+				provider:       provider(ldap.WithCreationAllowed(), ldap.WithAutoCreation()),
+				directoryUsers: []*ldap.User{directoryUser("ext1"), directoryUser("new")},
+				options:        &LDAPSyncOptions{DryRun: true},
+			},
+			res: res{
+				want: &LDAPSyncSummary{
+					IDPID:       "idp1",
+					DryRun:      true,
+					Added:       []string{"new"},
+					Deactivated: []string{"ext2"},
+					Unchanged:   1,
+					Failed:      map[string]error{},
+				},
+			},
+		},
+		{
+			name: "creation not allowed, ignored",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(),
+				),
+			},
+			args: args{
+				ctx:            context.Background(),
+				provider:       provider(),
+				directoryUsers: []*ldap.User{directoryUser("new")},
+			},
+			res: res{
+				want: &LDAPSyncSummary{
+					IDPID:  "idp1",
+					Failed: map[string]error{},
+				},
+			},
+		},
+		{
+			name: "user deactivated by sync in directory, reactivated",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						linkAdded("user1", "ext1"),
+					),
+					expectFilter(
+						humanAdded("user1"),
+						userDeactivatedBySync("user1"),
+					),
+					expectFilter(
+						userDeactivatedBySync("user1"),
+					),
+					expectFilter(
+						humanAdded("user1"),
+						userDeactivatedBySync("user1"),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(
+								user.NewUserReactivatedEvent(context.Background(),
+									&user.NewAggregate("user1", "org1").Aggregate,
+								),
+							),
+						},
+					),
+				),
+			},
+			args: args{
+				ctx:            context.Background(),
+				provider:       provider(),
+				directoryUsers: []*ldap.User{directoryUser("ext1")},
+			},
+			res: res{
+				want: &LDAPSyncSummary{
+					IDPID:       "idp1",
+					Reactivated: []string{"ext1"},
+					Failed:      map[string]error{},
+				},
+			},
+		},
+		{
+			name: "user deactivated by administrator in directory, not reactivated",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						linkAdded("user1", "ext1"),
+					),
+					expectFilter(
+						humanAdded("user1"),
+						userDeactivatedBySync("user1"),
+						userReactivated("user1"),
+						userDeactivated("user1"),
+					),
+					expectFilter(
+						userDeactivatedBySync("user1"),
+						userReactivated("user1"),
+						userDeactivated("user1"),
+					),
+				),
+			},
+			args: args{
+				ctx:            context.Background(),
+				provider:       provider(),
+				directoryUsers: []*ldap.User{directoryUser("ext1")},
+			},
+			res: res{
+				want: &LDAPSyncSummary{
+					IDPID:     "idp1",
+					Unchanged: 1,
+					Failed:    map[string]error{},
+				},
+			},
+		},
+		{
+			name: "mapped metadata and roles, updated and managed roles synced",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						linkAdded("user1", "ext1"),
+					),
+					expectFilter(
+						humanAdded("user1"),
+					),
+					expectFilter(),
+					expectFilter(
+						humanAdded("user1"),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(
+								user.NewMetadataSetEvent(context.Background(),
+									&user.NewAggregate("user1", "org1").Aggregate,
+									"uid",
+									[]byte("ext1"),
+								),
+							),
+						},
+					),
+					expectFilter(
+						userGrantAdded("admin", "manual"),
+					),
+					projectRoles(),
+					expectFilter(
+						userGrantAdded("admin", "manual"),
+					),
+					projectRoles(),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(
+								usergrant.NewUserGrantChangedEvent(context.Background(),
+									&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+									[]string{"manual", "viewer"},
+								),
+							),
+						},
+					),
+				),
+			},
+			args: args{
+				ctx:            context.Background(),
+				provider:       provider(),
+				mappings:       mappings,
+				directoryUsers: []*ldap.User{directoryUser("ext1")},
+			},
+			res: res{
+				want: &LDAPSyncSummary{
+					IDPID:   "idp1",
+					Updated: []string{"ext1"},
+					Failed:  map[string]error{},
+				},
+			},
+		},
+		{
+			name: "mapped metadata and roles unchanged, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						linkAdded("user1", "ext1"),
+					),
+					expectFilter(
+						humanAdded("user1"),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							user.NewMetadataSetEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								"uid",
+								[]byte("ext1"),
+							),
+						),
+					),
+					expectFilter(
+						userGrantAdded("viewer", "manual"),
+					),
+					projectRoles(),
+					expectFilter(
+						userGrantAdded("viewer", "manual"),
+					),
+				),
+			},
+			args: args{
+				ctx:            context.Background(),
+				provider:       provider(),
+				mappings:       mappings,
+				directoryUsers: []*ldap.User{directoryUser("ext1")},
+			},
+			res: res{
+				want: &LDAPSyncSummary{
+					IDPID:     "idp1",
+					Unchanged: 1,
+					Failed:    map[string]error{},
+				},
+			},
+		},
+		{
+			name: "user removed from directory, deactivated",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						linkAdded("user1", "ext1"),
+					),
+					expectFilter(
+						humanAdded("user1"),
+					),
+					expectFilter(
+						humanAdded("user1"),
+					),
+					expectPush(
+						[]*repository.Event{
+							userDeactivated("user1"),
+						},
+					),
+				),
+			},
+			args: args{
+				ctx:      context.Background(),
+				provider: provider(),
+			},
+			res: res{
+				want: &LDAPSyncSummary{
+					IDPID:       "idp1",
+					Deactivated: []string{"ext1"},
+					Failed:      map[string]error{},
+				},
+			},
+		},
+		{
+			name: "more users removed from directory than maximum deactivations, aborted",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						linkAdded("user1", "ext1"),
+						linkAdded("user2", "ext2"),
+					),
+					expectFilter(
+						humanAdded("user1"),
+					),
+					expectFilter(
+						humanAdded("user2"),
+					),
+				),
+			},
+			args: args{
+				ctx:      context.Background(),
+				provider: provider(),
+				options:  &LDAPSyncOptions{MaxDeactivations: 1},
+			},
+			res: res{
+				want: &LDAPSyncSummary{
+					IDPID:               "idp1",
+					DeactivationAborted: []string{"ext1", "ext2"},
+					Failed:              map[string]error{},
+				},
+			},
+		},
+		{
+			name: "deactivated user not in directory, unchanged",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						linkAdded("user1", "ext1"),
+					),
+					expectFilter(
+						humanAdded("user1"),
+						userDeactivated("user1"),
+					),
+				),
+			},
+			args: args{
+				ctx:      context.Background(),
+				provider: provider(),
+			},
+			res: res{
+				want: &LDAPSyncSummary{
+					IDPID:  "idp1",
+					Failed: map[string]error{},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore: tt.fields.eventstore,
+			}
+			options := tt.args.options
+			if options == nil {
+				options = new(LDAPSyncOptions)
+			}
+			got, err := r.syncLDAPDirectoryUsers(tt.args.ctx, writeModel, tt.args.provider, tt.args.mappings, tt.args.directoryUsers, options)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
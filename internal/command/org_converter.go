@@ -24,12 +24,19 @@ func orgWriteModelToDomainPolicy(wm *OrgDomainPolicyWriteModel) *domain.DomainPo
 
 func orgWriteModelToPasswordComplexityPolicy(wm *OrgPasswordComplexityPolicyWriteModel) *domain.PasswordComplexityPolicy {
 	return &domain.PasswordComplexityPolicy{
-		ObjectRoot:   writeModelToObjectRoot(wm.PasswordComplexityPolicyWriteModel.WriteModel),
-		MinLength:    wm.MinLength,
-		HasLowercase: wm.HasLowercase,
-		HasUppercase: wm.HasUppercase,
-		HasNumber:    wm.HasNumber,
-		HasSymbol:    wm.HasSymbol,
+		ObjectRoot:           writeModelToObjectRoot(wm.PasswordComplexityPolicyWriteModel.WriteModel),
+		MinLength:            wm.MinLength,
+		HasLowercase:         wm.HasLowercase,
+		HasUppercase:         wm.HasUppercase,
+		HasNumber:            wm.HasNumber,
+		HasSymbol:            wm.HasSymbol,
+		RejectBreached:       wm.RejectBreached,
+		MaxLength:            wm.MaxLength,
+		ForbidUsername:       wm.ForbidUsername,
+		ForbidEmailLocalPart: wm.ForbidEmailLocalPart,
+		ForbidOrgName:        wm.ForbidOrgName,
+		Rules:                wm.Rules,
+		BannedWords:          wm.BannedWords,
 	}
 }
 
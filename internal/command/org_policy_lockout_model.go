@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"time"
 
 	"github.com/zitadel/zitadel/internal/eventstore"
 
@@ -57,7 +58,12 @@ func (wm *OrgLockoutPolicyWriteModel) NewChangedEvent(
 	ctx context.Context,
 	aggregate *eventstore.Aggregate,
 	maxAttempts uint64,
-	showLockoutFailure bool) (*org.LockoutPolicyChangedEvent, bool) {
+	showLockoutFailure bool,
+	maxOTPAttempts,
+	maxPasskeyAttempts,
+	maxIPAttempts uint64,
+	lockoutDuration,
+	progressiveDelay time.Duration) (*org.LockoutPolicyChangedEvent, bool) {
 	changes := make([]policy.LockoutPolicyChanges, 0)
 	if wm.MaxPasswordAttempts != maxAttempts {
 		changes = append(changes, policy.ChangeMaxAttempts(maxAttempts))
@@ -65,6 +71,21 @@ func (wm *OrgLockoutPolicyWriteModel) NewChangedEvent(
 	if wm.ShowLockOutFailures != showLockoutFailure {
 		changes = append(changes, policy.ChangeShowLockOutFailures(showLockoutFailure))
 	}
+	if wm.MaxOTPAttempts != maxOTPAttempts {
+		changes = append(changes, policy.ChangeMaxOTPAttempts(maxOTPAttempts))
+	}
+	if wm.MaxPasskeyAttempts != maxPasskeyAttempts {
+		changes = append(changes, policy.ChangeMaxPasskeyAttempts(maxPasskeyAttempts))
+	}
+	if wm.MaxIPAttempts != maxIPAttempts {
+		changes = append(changes, policy.ChangeMaxIPAttempts(maxIPAttempts))
+	}
+	if wm.LockoutDuration != lockoutDuration {
+		changes = append(changes, policy.ChangeLockoutDuration(lockoutDuration))
+	}
+	if wm.ProgressiveDelay != progressiveDelay {
+		changes = append(changes, policy.ChangeProgressiveDelay(progressiveDelay))
+	}
 	if len(changes) == 0 {
 		return nil, false
 	}
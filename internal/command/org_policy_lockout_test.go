@@ -63,6 +63,11 @@ func TestCommandSide_AddPasswordLockoutPolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								10,
 								true,
+								0,
+								0,
+								0,
+								0,
+								0,
 							),
 						),
 					),
@@ -93,6 +98,11 @@ func TestCommandSide_AddPasswordLockoutPolicy(t *testing.T) {
 									&org.NewAggregate("org1").Aggregate,
 									10,
 									true,
+									0,
+									0,
+									0,
+									0,
+									0,
 								),
 							),
 						},
@@ -206,6 +216,11 @@ func TestCommandSide_ChangePasswordLockoutPolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								10,
 								true,
+								0,
+								0,
+								0,
+								0,
+								0,
 							),
 						),
 					),
@@ -234,6 +249,11 @@ func TestCommandSide_ChangePasswordLockoutPolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								10,
 								true,
+								0,
+								0,
+								0,
+								0,
+								0,
 							),
 						),
 					),
@@ -344,6 +364,11 @@ func TestCommandSide_RemovePasswordLockoutPolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								10,
 								true,
+								0,
+								0,
+								0,
+								0,
+								0,
 							),
 						),
 					),
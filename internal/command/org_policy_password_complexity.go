@@ -54,7 +54,14 @@ func (c *Commands) AddPasswordComplexityPolicy(ctx context.Context, resourceOwne
 			policy.HasLowercase,
 			policy.HasUppercase,
 			policy.HasNumber,
-			policy.HasSymbol))
+			policy.HasSymbol,
+			policy.RejectBreached,
+			policy.MaxLength,
+			policy.ForbidUsername,
+			policy.ForbidEmailLocalPart,
+			policy.ForbidOrgName,
+			policy.Rules,
+			policy.BannedWords))
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +90,7 @@ func (c *Commands) ChangePasswordComplexityPolicy(ctx context.Context, resourceO
 	}
 
 	orgAgg := OrgAggregateFromWriteModel(&existingPolicy.PasswordComplexityPolicyWriteModel.WriteModel)
-	changedEvent, hasChanged := existingPolicy.NewChangedEvent(ctx, orgAgg, policy.MinLength, policy.HasLowercase, policy.HasUppercase, policy.HasNumber, policy.HasSymbol)
+	changedEvent, hasChanged := existingPolicy.NewChangedEvent(ctx, orgAgg, policy.MinLength, policy.HasLowercase, policy.HasUppercase, policy.HasNumber, policy.HasSymbol, policy.RejectBreached, policy.MaxLength, policy.ForbidUsername, policy.ForbidEmailLocalPart, policy.ForbidOrgName, policy.Rules, policy.BannedWords)
 	if !hasChanged {
 		return nil, caos_errs.ThrowPreconditionFailed(nil, "Org-DAs21", "Errors.Org.PasswordComplexityPolicy.NotChanged")
 	}
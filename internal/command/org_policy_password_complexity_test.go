@@ -66,6 +66,8 @@ func TestCommandSide_AddPasswordComplexityPolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								8,
 								true, true, true, true,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -99,6 +101,8 @@ func TestCommandSide_AddPasswordComplexityPolicy(t *testing.T) {
 									&org.NewAggregate("org1").Aggregate,
 									8,
 									true, true, true, true,
+									false,
+									0, false, false, false, nil, nil,
 								),
 							),
 						},
@@ -224,6 +228,8 @@ func TestCommandSide_ChangePasswordComplexityPolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								8,
 								true, true, true, true,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -255,6 +261,8 @@ func TestCommandSide_ChangePasswordComplexityPolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								8,
 								true, true, true, true,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -371,6 +379,8 @@ func TestCommandSide_RemovePasswordComplexityPolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								8,
 								true, true, true, true,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"reflect"
 	"regexp"
 
 	"github.com/zitadel/zitadel/internal/domain"
@@ -19,12 +20,20 @@ var (
 type PasswordComplexityPolicyWriteModel struct {
 	eventstore.WriteModel
 
-	MinLength    uint64
-	HasLowercase bool
-	HasUppercase bool
-	HasNumber    bool
-	HasSymbol    bool
-	State        domain.PolicyState
+	MinLength      uint64
+	HasLowercase   bool
+	HasUppercase   bool
+	HasNumber      bool
+	HasSymbol      bool
+	RejectBreached bool
+	State          domain.PolicyState
+
+	MaxLength            uint64
+	ForbidUsername       bool
+	ForbidEmailLocalPart bool
+	ForbidOrgName        bool
+	Rules                []domain.PasswordRule
+	BannedWords          []string
 }
 
 func (wm *PasswordComplexityPolicyWriteModel) Reduce() error {
@@ -36,6 +45,13 @@ func (wm *PasswordComplexityPolicyWriteModel) Reduce() error {
 			wm.HasUppercase = e.HasUppercase
 			wm.HasNumber = e.HasNumber
 			wm.HasSymbol = e.HasSymbol
+			wm.RejectBreached = e.RejectBreached
+			wm.MaxLength = e.MaxLength
+			wm.ForbidUsername = e.ForbidUsername
+			wm.ForbidEmailLocalPart = e.ForbidEmailLocalPart
+			wm.ForbidOrgName = e.ForbidOrgName
+			wm.Rules = e.Rules
+			wm.BannedWords = e.BannedWords
 			wm.State = domain.PolicyStateActive
 		case *policy.PasswordComplexityPolicyChangedEvent:
 			if e.MinLength != nil {
@@ -53,6 +69,27 @@ func (wm *PasswordComplexityPolicyWriteModel) Reduce() error {
 			if e.HasSymbol != nil {
 				wm.HasSymbol = *e.HasSymbol
 			}
+			if e.RejectBreached != nil {
+				wm.RejectBreached = *e.RejectBreached
+			}
+			if e.MaxLength != nil {
+				wm.MaxLength = *e.MaxLength
+			}
+			if e.ForbidUsername != nil {
+				wm.ForbidUsername = *e.ForbidUsername
+			}
+			if e.ForbidEmailLocalPart != nil {
+				wm.ForbidEmailLocalPart = *e.ForbidEmailLocalPart
+			}
+			if e.ForbidOrgName != nil {
+				wm.ForbidOrgName = *e.ForbidOrgName
+			}
+			if e.Rules != nil {
+				wm.Rules = *e.Rules
+			}
+			if e.BannedWords != nil {
+				wm.BannedWords = *e.BannedWords
+			}
 		case *policy.PasswordComplexityPolicyRemovedEvent:
 			wm.State = domain.PolicyStateRemoved
 		}
@@ -80,5 +117,14 @@ func (wm *PasswordComplexityPolicyWriteModel) Validate(password string) error {
 	if wm.HasSymbol && !hasSymbol(password) {
 		return errors.ThrowInvalidArgument(nil, "COMMA-ZDLwA", "Errors.User.PasswordComplexityPolicy.HasSymbol")
 	}
-	return nil
+	return writeModelToPasswordComplexityPolicy(wm).CheckRules(password)
+}
+
+// passwordRulesChanged and bannedWordsChanged don't treat nil and empty lists as a change
+func passwordRulesChanged(existing, rules []domain.PasswordRule) bool {
+	return (len(existing) > 0 || len(rules) > 0) && !reflect.DeepEqual(existing, rules)
+}
+
+func bannedWordsChanged(existing, bannedWords []string) bool {
+	return (len(existing) > 0 || len(bannedWords) > 0) && !reflect.DeepEqual(existing, bannedWords)
 }
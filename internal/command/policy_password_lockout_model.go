@@ -1,6 +1,8 @@
 package command
 
 import (
+	"time"
+
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/repository/policy"
@@ -11,6 +13,11 @@ type LockoutPolicyWriteModel struct {
 
 	MaxPasswordAttempts uint64
 	ShowLockOutFailures bool
+	MaxOTPAttempts      uint64
+	MaxPasskeyAttempts  uint64
+	MaxIPAttempts       uint64
+	LockoutDuration     time.Duration
+	ProgressiveDelay    time.Duration
 	State               domain.PolicyState
 }
 
@@ -20,6 +27,11 @@ func (wm *LockoutPolicyWriteModel) Reduce() error {
 		case *policy.LockoutPolicyAddedEvent:
 			wm.MaxPasswordAttempts = e.MaxPasswordAttempts
 			wm.ShowLockOutFailures = e.ShowLockOutFailures
+			wm.MaxOTPAttempts = e.MaxOTPAttempts
+			wm.MaxPasskeyAttempts = e.MaxPasskeyAttempts
+			wm.MaxIPAttempts = e.MaxIPAttempts
+			wm.LockoutDuration = e.LockoutDuration
+			wm.ProgressiveDelay = e.ProgressiveDelay
 			wm.State = domain.PolicyStateActive
 		case *policy.LockoutPolicyChangedEvent:
 			if e.MaxPasswordAttempts != nil {
@@ -28,6 +40,21 @@ func (wm *LockoutPolicyWriteModel) Reduce() error {
 			if e.ShowLockOutFailures != nil {
 				wm.ShowLockOutFailures = *e.ShowLockOutFailures
 			}
+			if e.MaxOTPAttempts != nil {
+				wm.MaxOTPAttempts = *e.MaxOTPAttempts
+			}
+			if e.MaxPasskeyAttempts != nil {
+				wm.MaxPasskeyAttempts = *e.MaxPasskeyAttempts
+			}
+			if e.MaxIPAttempts != nil {
+				wm.MaxIPAttempts = *e.MaxIPAttempts
+			}
+			if e.LockoutDuration != nil {
+				wm.LockoutDuration = *e.LockoutDuration
+			}
+			if e.ProgressiveDelay != nil {
+				wm.ProgressiveDelay = *e.ProgressiveDelay
+			}
 		case *policy.LockoutPolicyRemovedEvent:
 			wm.State = domain.PolicyStateRemoved
 		}
@@ -13,6 +13,7 @@ import (
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/id"
 	"github.com/zitadel/zitadel/internal/repository/session"
+	"github.com/zitadel/zitadel/internal/repository/user"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 )
 
@@ -24,11 +25,13 @@ type SessionCommands struct {
 	sessionWriteModel  *SessionWriteModel
 	passwordWriteModel *HumanPasswordWriteModel
 	intentWriteModel   *IDPIntentWriteModel
-	eventstore         *eventstore.Eventstore
-	userPasswordAlg    crypto.HashAlgorithm
-	intentAlg          crypto.EncryptionAlgorithm
-	createToken        func(sessionID string) (id string, token string, err error)
-	now                func() time.Time
+	// eventCommands are pushed together with the session events, e.g. to update the password hash
+	eventCommands   []eventstore.Command
+	eventstore      *eventstore.Eventstore
+	userPasswordAlg crypto.HashAlgorithm
+	intentAlg       crypto.EncryptionAlgorithm
+	createToken     func(sessionID string) (id string, token string, err error)
+	now             func() time.Time
 }
 
 func (c *Commands) NewSessionCommands(cmds []SessionCommand, session *SessionWriteModel) *SessionCommands {
@@ -71,13 +74,28 @@ func CheckPassword(password string) SessionCommand {
 		if cmd.passwordWriteModel.Secret == nil {
 			return caos_errs.ThrowPreconditionFailed(nil, "COMMAND-WEf3t", "Errors.User.Password.NotSet")
 		}
-		ctx, spanPasswordComparison := tracing.NewNamedSpan(ctx, "crypto.CompareHash")
-		err = crypto.CompareHash(cmd.passwordWriteModel.Secret, []byte(password), cmd.userPasswordAlg)
+		lockout, err := cmd.userLockout().check(ctx, cmd.passwordWriteModel.AggregateID, cmd.passwordWriteModel.ResourceOwner, domain.LockoutFactorPassword, remoteIP(ctx), nil)
+		if err != nil {
+			return err
+		}
+		userAgg := UserAggregateFromWriteModel(&cmd.passwordWriteModel.WriteModel)
+		ctx, spanPasswordComparison := tracing.NewNamedSpan(ctx, "crypto.VerifyPassword")
+		updated, err := crypto.VerifyPassword(cmd.passwordWriteModel.Secret, []byte(password), cmd.userPasswordAlg)
 		spanPasswordComparison.EndWithError(err)
 		if err != nil {
 			//TODO: maybe we want to reset the session in the future https://github.com/zitadel/zitadel/issues/5807
+			// the failed check has to be pushed regardless of the session, so the lockout policy applies
+			if pushErr := cmd.pushFailedCheck(ctx, user.NewHumanPasswordCheckFailedEvent(ctx, userAgg, nil), lockout.failed(ctx, userAgg)...); pushErr != nil {
+				return pushErr
+			}
 			return caos_errs.ThrowInvalidArgument(err, "COMMAND-SAF3g", "Errors.User.Password.Invalid")
 		}
+		if lockout.hasFailedChecks() {
+			cmd.eventCommands = append(cmd.eventCommands, user.NewHumanPasswordCheckSucceededEvent(ctx, userAgg, nil))
+		}
+		if updated != nil {
+			cmd.eventCommands = append(cmd.eventCommands, user.NewHumanPasswordHashUpdatedEvent(ctx, userAgg, updated))
+		}
 		cmd.sessionWriteModel.PasswordChecked(ctx, cmd.now())
 		return nil
 	}
@@ -129,6 +147,19 @@ func (s *SessionCommands) Exec(ctx context.Context) error {
 	return nil
 }
 
+func (s *SessionCommands) userLockout() *userLockout {
+	return &userLockout{
+		eventstore: s.eventstore,
+		now:        s.now,
+	}
+}
+
+// pushFailedCheck pushes the events of a failed check directly, as the session will not be updated
+func (s *SessionCommands) pushFailedCheck(ctx context.Context, failedCheck eventstore.Command, lockoutEvents ...eventstore.Command) error {
+	_, err := s.eventstore.Push(ctx, append([]eventstore.Command{failedCheck}, lockoutEvents...)...)
+	return err
+}
+
 func (s *SessionCommands) gethumanWriteModel(ctx context.Context) (*HumanWriteModel, error) {
 	if s.sessionWriteModel.UserID == "" {
 		return nil, caos_errs.ThrowPreconditionFailed(nil, "COMMAND-eeR2e", "Errors.User.UserIDMissing")
@@ -225,11 +256,12 @@ func (c *Commands) updateSession(ctx context.Context, checks *SessionCommands, m
 	if len(cmds) == 0 {
 		return sessionWriteModelToSessionChanged(checks.sessionWriteModel), nil
 	}
-	pushedEvents, err := c.eventstore.Push(ctx, cmds...)
+	pushedEvents, err := c.eventstore.Push(ctx, append(cmds, checks.eventCommands...)...)
 	if err != nil {
 		return nil, err
 	}
-	err = AppendAndReduce(checks.sessionWriteModel, pushedEvents...)
+	// only the events of the session are reduced
+	err = AppendAndReduce(checks.sessionWriteModel, pushedEvents[:len(cmds)]...)
 	if err != nil {
 		return nil, err
 	}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/zitadel/zitadel/internal/domain"
 	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/repository/user"
 )
 
 type humanPasskeys struct {
@@ -62,22 +63,33 @@ func (c *Commands) CheckPasskey(credentialAssertionData json.Marshaler) SessionC
 		if err != nil {
 			return caos_errs.ThrowInvalidArgument(err, "COMMAND-ohG2o", "todo")
 		}
+		lockout, err := cmd.userLockout().check(ctx, cmd.sessionWriteModel.UserID, "", domain.LockoutFactorPasskey, remoteIP(ctx), nil)
+		if err != nil {
+			return err
+		}
 		humanPasskeys, err := cmd.getHumanPasskeys(ctx)
 		if err != nil {
 			return err
 		}
+		userAgg := UserAggregateFromWriteModel(&lockout.writeModel.WriteModel)
 		webAuthN, err := cmd.sessionWriteModel.PasskeyChallenge.WebAuthNLogin(humanPasskeys.human, credentialAssertionData)
 		if err != nil {
 			return err
 		}
 		keyID, signCount, err := c.webauthnConfig.FinishLogin(ctx, humanPasskeys.human, webAuthN, credentialAssertionData, humanPasskeys.tokens...)
 		if err != nil && keyID == nil {
+			if pushErr := cmd.pushFailedCheck(ctx, user.NewHumanPasswordlessCheckFailedEvent(ctx, userAgg, nil), lockout.failed(ctx, userAgg)...); pushErr != nil {
+				return pushErr
+			}
 			return err
 		}
 		_, token := domain.GetTokenByKeyID(humanPasskeys.tokens, keyID)
 		if token == nil {
 			return caos_errs.ThrowPreconditionFailed(nil, "COMMAND-Aej7i", "Errors.User.WebAuthN.NotFound")
 		}
+		if lockout.hasFailedChecks() {
+			cmd.eventCommands = append(cmd.eventCommands, user.NewHumanPasswordlessCheckSucceededEvent(ctx, userAgg, nil))
+		}
 		cmd.sessionWriteModel.PasskeyChecked(ctx, cmd.now(), token.WebAuthNTokenID, signCount)
 		return nil
 	}
@@ -19,6 +19,7 @@ import (
 	"github.com/zitadel/zitadel/internal/id"
 	"github.com/zitadel/zitadel/internal/id/mock"
 	"github.com/zitadel/zitadel/internal/repository/idpintent"
+	"github.com/zitadel/zitadel/internal/repository/instance"
 	"github.com/zitadel/zitadel/internal/repository/session"
 	"github.com/zitadel/zitadel/internal/repository/user"
 )
@@ -507,6 +508,22 @@ func TestCommands_updateSession(t *testing.T) {
 									}, false, ""),
 							),
 						),
+						expectFilter(),
+						expectFilter(),
+						expectFilter(
+							eventFromEventPusher(
+								instance.NewLockoutPolicyAddedEvent(context.Background(),
+									&instance.NewAggregate("instance1").Aggregate,
+									0,
+									false,
+									0,
+									0,
+									0,
+									0,
+									0,
+								),
+							),
+						),
 					),
 					createToken: func(sessionID string) (string, string, error) {
 						return "tokenID",
@@ -532,6 +549,132 @@ func TestCommands_updateSession(t *testing.T) {
 				},
 			},
 		},
+		{
+			"set user, password not matching, max attempts reached - user locked temporarily",
+			fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args{
+				ctx: context.Background(),
+				checks: &SessionCommands{
+					sessionWriteModel: NewSessionWriteModel("sessionID", "org1"),
+					cmds: []SessionCommand{
+						CheckUser("userID"),
+						CheckPassword("wrong"),
+					},
+					eventstore: eventstoreExpect(t,
+						expectFilter(
+							eventFromEventPusher(
+								user.NewHumanAddedEvent(context.Background(), &user.NewAggregate("userID", "org1").Aggregate,
+									"username", "", "", "", "", language.English, domain.GenderUnspecified, "", false),
+							),
+							eventFromEventPusher(
+								user.NewHumanPasswordChangedEvent(context.Background(), &user.NewAggregate("userID", "org1").Aggregate,
+									&crypto.CryptoValue{
+										CryptoType: crypto.TypeHash,
+										Algorithm:  "hash",
+										KeyID:      "",
+										Crypted:    []byte("password"),
+									}, false, ""),
+							),
+						),
+						expectFilter(
+							eventFromEventPusher(
+								user.NewHumanPasswordCheckFailedEvent(context.Background(), &user.NewAggregate("userID", "org1").Aggregate, nil),
+							),
+						),
+						expectFilter(),
+						expectFilter(
+							eventFromEventPusher(
+								instance.NewLockoutPolicyAddedEvent(context.Background(),
+									&instance.NewAggregate("instance1").Aggregate,
+									2,
+									false,
+									0,
+									0,
+									0,
+									time.Hour,
+									0,
+								),
+							),
+						),
+						expectPush(
+							eventPusherToEvents(
+								user.NewHumanPasswordCheckFailedEvent(context.Background(), &user.NewAggregate("userID", "org1").Aggregate, nil),
+								user.NewUserLockedUntilEvent(context.Background(), &user.NewAggregate("userID", "org1").Aggregate, testNow.Add(time.Hour)),
+							),
+						),
+					),
+					userPasswordAlg: crypto.CreateMockHashAlg(gomock.NewController(t)),
+					now: func() time.Time {
+						return testNow
+					},
+				},
+			},
+			res{
+				err: caos_errs.ThrowInvalidArgument(nil, "COMMAND-SAF3g", "Errors.User.Password.Invalid"),
+			},
+		},
+		{
+			"set user, user locked, precondition error",
+			fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args{
+				ctx: context.Background(),
+				checks: &SessionCommands{
+					sessionWriteModel: NewSessionWriteModel("sessionID", "org1"),
+					cmds: []SessionCommand{
+						CheckUser("userID"),
+						CheckPassword("password"),
+					},
+					eventstore: eventstoreExpect(t,
+						expectFilter(
+							eventFromEventPusher(
+								user.NewHumanAddedEvent(context.Background(), &user.NewAggregate("userID", "org1").Aggregate,
+									"username", "", "", "", "", language.English, domain.GenderUnspecified, "", false),
+							),
+							eventFromEventPusher(
+								user.NewHumanPasswordChangedEvent(context.Background(), &user.NewAggregate("userID", "org1").Aggregate,
+									&crypto.CryptoValue{
+										CryptoType: crypto.TypeHash,
+										Algorithm:  "hash",
+										KeyID:      "",
+										Crypted:    []byte("password"),
+									}, false, ""),
+							),
+						),
+						expectFilter(
+							eventFromEventPusher(
+								user.NewUserLockedUntilEvent(context.Background(), &user.NewAggregate("userID", "org1").Aggregate, testNow.Add(time.Minute)),
+							),
+						),
+						expectFilter(),
+						expectFilter(
+							eventFromEventPusher(
+								instance.NewLockoutPolicyAddedEvent(context.Background(),
+									&instance.NewAggregate("instance1").Aggregate,
+									2,
+									false,
+									0,
+									0,
+									0,
+									time.Hour,
+									0,
+								),
+							),
+						),
+					),
+					userPasswordAlg: crypto.CreateMockHashAlg(gomock.NewController(t)),
+					now: func() time.Time {
+						return testNow
+					},
+				},
+			},
+			res{
+				err: caos_errs.ThrowPreconditionFailed(nil, "COMMAND-oL5ie", "Errors.User.Locked"),
+			},
+		},
 		{
 			"set user, intent not successful",
 			fields{
@@ -592,6 +735,7 @@ func TestCommands_updateSession(t *testing.T) {
 							),
 							eventFromEventPusher(
 								idpintent.NewSucceededEvent(context.Background(), &idpintent.NewAggregate("intent", "org1").Aggregate,
+									nil,
 									nil,
 									"idpUserID",
 									"idpUserName",
@@ -686,6 +830,7 @@ func TestCommands_updateSession(t *testing.T) {
 							),
 							eventFromEventPusher(
 								idpintent.NewSucceededEvent(context.Background(), &idpintent.NewAggregate("intent", "org1").Aggregate,
+									nil,
 									nil,
 									"idpUserID",
 									"idpUsername",
@@ -15,6 +15,7 @@ import (
 	"github.com/zitadel/zitadel/internal/errors"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
+	"github.com/zitadel/zitadel/internal/repository/idpintent"
 	"github.com/zitadel/zitadel/internal/repository/user"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 )
@@ -196,6 +197,15 @@ func (c *Commands) RemoveUser(ctx context.Context, userID, resourceOwner string,
 	userAgg := UserAggregateFromWriteModel(&existingUser.WriteModel)
 	events = append(events, user.NewUserRemovedEvent(ctx, userAgg, existingUser.UserName, existingUser.IDPLinks, domainPolicy.UserLoginMustBeDomain))
 
+	// the intents contain the personal data of the user at the identity provider
+	intents := newUserIDPIntentsWriteModel(userID, existingUser.IDPLinks)
+	if err = c.eventstore.FilterToQueryReducer(ctx, intents); err != nil {
+		return nil, err
+	}
+	for _, intent := range intents.Intents {
+		events = append(events, idpintent.NewUserRemovedEvent(ctx, intent))
+	}
+
 	for _, grantID := range cascadingGrantIDs {
 		removeEvent, _, err := c.removeUserGrant(ctx, grantID, "", true)
 		if err != nil {
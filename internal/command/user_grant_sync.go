@@ -0,0 +1,193 @@
+package command
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/idp"
+	"github.com/zitadel/zitadel/internal/repository/usergrant"
+)
+
+// SyncUserGrantsByLDAPGroups maps the groups of a user (provided by an LDAP identity provider) to user grants.
+// Only the roles of the mappings are managed, all other roles of the user grants are kept:
+// the grant of a mapped project is added, changed or removed, so that the user has exactly the mapped roles of its groups.
+// The userGrantIDs are the existing grants of the user, which are checked against the mapped projects.
+func (c *Commands) SyncUserGrantsByLDAPGroups(ctx context.Context, userID, resourceOwner string, mappings []idp.LDAPGroupMapping, groups, userGrantIDs []string) (err error) {
+	if userID == "" {
+		return caos_errs.ThrowInvalidArgument(nil, "COMMAND-Gq3lw", "Errors.IDMissing")
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+	managedRoles, desiredRoles := ldapGroupMappingRoles(mappings, groups)
+	return c.syncUserGrants(ctx, userID, resourceOwner, managedRoles, desiredRoles, userGrantIDs)
+}
+
+// SyncUserGrantsByIDPClaims sets the roles of a user mapped from the claims of an identity provider (see [domain.IDPClaimMappingTargetRole]).
+// The managedRoles are the role keys allowed by the mappings by the id of their project of the claim mappings):
+// the user has exactly the mapped roles of them, roles not existing in the project are ignored.
+// All other roles of the user grants are kept.
+// The userGrantIDs are the existing grants of the user, which are checked against the mapped projects.
+func (c *Commands) SyncUserGrantsByIDPClaims(ctx context.Context, userID, resourceOwner string, roles, managedRoles map[string][]string, userGrantIDs []string) (err error) {
+	if userID == "" {
+		return caos_errs.ThrowInvalidArgument(nil, "COMMAND-Gq4lx", "Errors.IDMissing")
+	}
+	existingRoles, err := c.existingManagedRoles(ctx, userID, resourceOwner, managedRoles)
+	if err != nil || len(existingRoles) == 0 {
+		return err
+	}
+	desiredRoles := make(map[string][]string, len(roles))
+	for projectID, roleKeys := range roles {
+		for _, roleKey := range roleKeys {
+			if listContainsID(existingRoles[projectID], roleKey) {
+				desiredRoles[projectID] = appendRoleKeys(desiredRoles[projectID], roleKey)
+			}
+		}
+	}
+	return c.syncUserGrants(ctx, userID, resourceOwner, existingRoles, desiredRoles, userGrantIDs)
+}
+
+// existingManagedRoles returns the managed roles, which exist in their project
+func (c *Commands) existingManagedRoles(ctx context.Context, userID, resourceOwner string, managedRoles map[string][]string) (map[string][]string, error) {
+	projectIDs := make([]string, 0, len(managedRoles))
+	for projectID := range managedRoles {
+		projectIDs = append(projectIDs, projectID)
+	}
+	sort.Strings(projectIDs)
+	existing := make(map[string][]string, len(managedRoles))
+	for _, projectID := range projectIDs {
+		preConditions := NewUserGrantPreConditionReadModel(userID, projectID, "", resourceOwner)
+		if err := c.eventstore.FilterToQueryReducer(ctx, preConditions); err != nil {
+			return nil, err
+		}
+		for _, roleKey := range managedRoles[projectID] {
+			if listContainsID(preConditions.ExistingRoleKeys, roleKey) {
+				existing[projectID] = appendRoleKeys(existing[projectID], roleKey)
+			}
+		}
+	}
+	return existing, nil
+}
+
+// syncUserGrants adds, changes or removes the grants of the managed projects,
+// so that the user has exactly the desired roles of the managed ones
+func (c *Commands) syncUserGrants(ctx context.Context, userID, resourceOwner string, managedRoles, desiredRoles map[string][]string, userGrantIDs []string) error {
+	existingGrants := make(map[string]*UserGrantWriteModel, len(userGrantIDs))
+	for _, grantID := range userGrantIDs {
+		grant, err := c.userGrantWriteModelByID(ctx, grantID, resourceOwner)
+		if err != nil {
+			return err
+		}
+		if grant.UserID != userID || grant.ProjectGrantID != "" || grant.State == domain.UserGrantStateUnspecified || grant.State == domain.UserGrantStateRemoved {
+			continue
+		}
+		if _, ok := managedRoles[grant.ProjectID]; ok {
+			existingGrants[grant.ProjectID] = grant
+		}
+	}
+
+	projectIDs := make([]string, 0, len(managedRoles))
+	for projectID := range managedRoles {
+		projectIDs = append(projectIDs, projectID)
+	}
+	sort.Strings(projectIDs)
+
+	cmds := make([]eventstore.Command, 0, len(projectIDs))
+	for _, projectID := range projectIDs {
+		cmd, err := c.syncUserGrant(ctx, userID, projectID, resourceOwner, existingGrants[projectID], managedRoles[projectID], desiredRoles[projectID])
+		if err != nil {
+			return err
+		}
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	_, err := c.eventstore.Push(ctx, cmds...)
+	return err
+}
+
+func (c *Commands) syncUserGrant(ctx context.Context, userID, projectID, resourceOwner string, existing *UserGrantWriteModel, managedRoles, desiredRoles []string) (eventstore.Command, error) {
+	if existing == nil {
+		if len(desiredRoles) == 0 {
+			return nil, nil
+		}
+		cmd, _, err := c.addUserGrant(ctx, &domain.UserGrant{UserID: userID, ProjectID: projectID, RoleKeys: desiredRoles}, resourceOwner)
+		return cmd, err
+	}
+	roleKeys := syncedRoleKeys(existing.RoleKeys, managedRoles, desiredRoles)
+	if equalRoleKeys(existing.RoleKeys, roleKeys) {
+		return nil, nil
+	}
+	userGrantAgg := UserGrantAggregateFromWriteModel(&existing.WriteModel)
+	if len(roleKeys) == 0 {
+		return usergrant.NewUserGrantRemovedEvent(ctx, userGrantAgg, userID, projectID, ""), nil
+	}
+	err := c.checkUserGrantPreCondition(ctx, &domain.UserGrant{UserID: userID, ProjectID: projectID, RoleKeys: roleKeys}, existing.ResourceOwner)
+	if err != nil {
+		return nil, err
+	}
+	return usergrant.NewUserGrantChangedEvent(ctx, userGrantAgg, roleKeys), nil
+}
+
+// ldapGroupMappingRoles returns all roles managed by the mappings
+// and the roles the user is entitled to by its groups, both by project
+func ldapGroupMappingRoles(mappings []idp.LDAPGroupMapping, groups []string) (managed, desired map[string][]string) {
+	managed = make(map[string][]string)
+	desired = make(map[string][]string)
+	for _, mapping := range mappings {
+		managed[mapping.ProjectID] = appendRoleKeys(managed[mapping.ProjectID], mapping.Roles...)
+		if containsGroup(groups, mapping.Group) {
+			desired[mapping.ProjectID] = appendRoleKeys(desired[mapping.ProjectID], mapping.Roles...)
+		}
+	}
+	return managed, desired
+}
+
+// syncedRoleKeys keeps all unmanaged roles of the existing grant and sets the managed ones according to the desired roles
+func syncedRoleKeys(existing, managed, desired []string) []string {
+	roleKeys := make([]string, 0, len(existing)+len(desired))
+	for _, roleKey := range existing {
+		if !listContainsID(managed, roleKey) || listContainsID(desired, roleKey) {
+			roleKeys = appendRoleKeys(roleKeys, roleKey)
+		}
+	}
+	return appendRoleKeys(roleKeys, desired...)
+}
+
+func appendRoleKeys(roleKeys []string, keys ...string) []string {
+	for _, key := range keys {
+		if !listContainsID(roleKeys, key) {
+			roleKeys = append(roleKeys, key)
+		}
+	}
+	return roleKeys
+}
+
+func equalRoleKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, key := range a {
+		if !listContainsID(b, key) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsGroup compares the groups case-insensitive, as distinguished names are
+func containsGroup(groups []string, group string) bool {
+	for _, g := range groups {
+		if strings.EqualFold(g, group) {
+			return true
+		}
+	}
+	return false
+}
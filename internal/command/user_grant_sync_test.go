@@ -0,0 +1,498 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/id"
+	id_mock "github.com/zitadel/zitadel/internal/id/mock"
+	"github.com/zitadel/zitadel/internal/repository/idp"
+	"github.com/zitadel/zitadel/internal/repository/project"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/repository/usergrant"
+)
+
+func TestCommandSide_SyncUserGrantsByLDAPGroups(t *testing.T) {
+	type fields struct {
+		eventstore  *eventstore.Eventstore
+		idGenerator id.Generator
+	}
+	type args struct {
+		ctx           context.Context
+		userID        string
+		resourceOwner string
+		mappings      []idp.LDAPGroupMapping
+		groups        []string
+		userGrantIDs  []string
+	}
+	type res struct {
+		err func(error) bool
+	}
+	mappings := []idp.LDAPGroupMapping{
+		{
+			Group:     "cn=admins,ou=groups,dc=example,dc=com",
+			ProjectID: "project1",
+			Roles:     []string{"rolekey1"},
+		},
+		{
+			Group:     "cn=users,ou=groups,dc=example,dc=com",
+			ProjectID: "project1",
+			Roles:     []string{"rolekey2"},
+		},
+	}
+	preConditions := func() expect {
+		return expectFilter(
+			eventFromEventPusher(
+				user.NewHumanAddedEvent(context.Background(),
+					&user.NewAggregate("user1", "org1").Aggregate,
+					"username1",
+					"firstname1",
+					"lastname1",
+					"nickname1",
+					"displayname1",
+					language.German,
+					domain.GenderMale,
+					"email1",
+					true,
+				),
+			),
+			eventFromEventPusher(
+				project.NewProjectAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"projectname1", true, true, true,
+					domain.PrivateLabelingSettingUnspecified,
+				),
+			),
+			eventFromEventPusher(
+				project.NewRoleAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"rolekey1",
+					"rolekey",
+					"",
+				),
+			),
+			eventFromEventPusher(
+				project.NewRoleAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"rolekey2",
+					"rolekey",
+					"",
+				),
+			),
+			eventFromEventPusher(
+				project.NewRoleAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"unmanaged",
+					"rolekey",
+					"",
+				),
+			),
+		)
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "missing userID, error",
+			fields: fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args: args{
+				ctx:           context.Background(),
+				resourceOwner: "org1",
+				mappings:      mappings,
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "no mappings, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				groups:        []string{"cn=admins,ou=groups,dc=example,dc=com"},
+			},
+		},
+		{
+			name: "group member without grant, added",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					preConditions(),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(usergrant.NewUserGrantAddedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								"user1",
+								"project1",
+								"",
+								[]string{"rolekey1"},
+							)),
+						},
+						uniqueConstraintsFromEventConstraint(usergrant.NewAddUserGrantUniqueConstraint("org1", "user1", "project1", "")),
+					),
+				),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "usergrant1"),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				mappings:      mappings,
+				groups:        []string{"CN=Admins,OU=Groups,DC=example,DC=com"},
+			},
+		},
+		{
+			name: "group membership unchanged, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							usergrant.NewUserGrantAddedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								"user1",
+								"project1",
+								"", []string{"unmanaged", "rolekey1"}),
+						),
+					),
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				mappings:      mappings,
+				groups:        []string{"cn=admins,ou=groups,dc=example,dc=com"},
+				userGrantIDs:  []string{"usergrant1"},
+			},
+		},
+		{
+			name: "group membership changed, unmanaged roles kept",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							usergrant.NewUserGrantAddedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								"user1",
+								"project1",
+								"", []string{"unmanaged", "rolekey1"}),
+						),
+					),
+					preConditions(),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(usergrant.NewUserGrantChangedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								[]string{"unmanaged", "rolekey2"},
+							)),
+						},
+					),
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				mappings:      mappings,
+				groups:        []string{"cn=users,ou=groups,dc=example,dc=com"},
+				userGrantIDs:  []string{"usergrant1"},
+			},
+		},
+		{
+			name: "group membership removed, grant removed",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							usergrant.NewUserGrantAddedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								"user1",
+								"project1",
+								"", []string{"rolekey1", "rolekey2"}),
+						),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(usergrant.NewUserGrantRemovedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								"user1",
+								"project1",
+								"",
+							)),
+						},
+						uniqueConstraintsFromEventConstraint(usergrant.NewRemoveUserGrantUniqueConstraint("org1", "user1", "project1", "")),
+					),
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				mappings:      mappings,
+				groups:        []string{"cn=others,ou=groups,dc=example,dc=com"},
+				userGrantIDs:  []string{"usergrant1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore:  tt.fields.eventstore,
+				idGenerator: tt.fields.idGenerator,
+			}
+			err := r.SyncUserGrantsByLDAPGroups(tt.args.ctx, tt.args.userID, tt.args.resourceOwner, tt.args.mappings, tt.args.groups, tt.args.userGrantIDs)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+		})
+	}
+}
+
+func TestCommandSide_SyncUserGrantsByIDPClaims(t *testing.T) {
+	type fields struct {
+		eventstore  *eventstore.Eventstore
+		idGenerator id.Generator
+	}
+	type args struct {
+		ctx           context.Context
+		userID        string
+		resourceOwner string
+		roles         map[string][]string
+		managedRoles  map[string][]string
+		userGrantIDs  []string
+	}
+	type res struct {
+		err func(error) bool
+	}
+	managedRoles := map[string][]string{
+		"project1": {"admin", "editor", "missing"},
+	}
+	projectRoles := func() expect {
+		return expectFilter(
+			eventFromEventPusher(
+				user.NewHumanAddedEvent(context.Background(),
+					&user.NewAggregate("user1", "org1").Aggregate,
+					"username1",
+					"firstname1",
+					"lastname1",
+					"nickname1",
+					"displayname1",
+					language.German,
+					domain.GenderMale,
+					"email1",
+					true,
+				),
+			),
+			eventFromEventPusher(
+				project.NewProjectAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"projectname1", true, true, true,
+					domain.PrivateLabelingSettingUnspecified,
+				),
+			),
+			eventFromEventPusher(
+				project.NewRoleAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"admin",
+					"admin",
+					"",
+				),
+			),
+			eventFromEventPusher(
+				project.NewRoleAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"editor",
+					"editor",
+					"",
+				),
+			),
+			eventFromEventPusher(
+				project.NewRoleAddedEvent(context.Background(),
+					&project.NewAggregate("project1", "org1").Aggregate,
+					"manual",
+					"manual",
+					"",
+				),
+			),
+		)
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "missing userID, error",
+			fields: fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args: args{
+				ctx:           context.Background(),
+				resourceOwner: "org1",
+				roles:         map[string][]string{"project1": {"admin"}},
+				managedRoles:  managedRoles,
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "no managed roles, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				roles:         map[string][]string{"project1": {"admin"}},
+			},
+		},
+		{
+			name: "unmanaged roles only, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					projectRoles(),
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				roles:         map[string][]string{"project1": {"manual", "missing"}, "project2": {"admin"}},
+				managedRoles:  managedRoles,
+			},
+		},
+		{
+			name: "role claim without grant, added",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					projectRoles(),
+					projectRoles(),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(usergrant.NewUserGrantAddedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								"user1",
+								"project1",
+								"",
+								[]string{"admin"},
+							)),
+						},
+						uniqueConstraintsFromEventConstraint(usergrant.NewAddUserGrantUniqueConstraint("org1", "user1", "project1", "")),
+					),
+				),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "usergrant1"),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				roles:         map[string][]string{"project1": {"admin", "manual"}},
+				managedRoles:  managedRoles,
+			},
+		},
+		{
+			name: "role claim missing, unmanaged roles kept",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					projectRoles(),
+					expectFilter(
+						eventFromEventPusher(
+							usergrant.NewUserGrantAddedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								"user1",
+								"project1",
+								"", []string{"editor", "manual"}),
+						),
+					),
+					projectRoles(),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(usergrant.NewUserGrantChangedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								[]string{"manual"},
+							)),
+						},
+					),
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				managedRoles:  managedRoles,
+				userGrantIDs:  []string{"usergrant1"},
+			},
+		},
+		{
+			name: "role claim missing, grant removed",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					projectRoles(),
+					expectFilter(
+						eventFromEventPusher(
+							usergrant.NewUserGrantAddedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								"user1",
+								"project1",
+								"", []string{"editor"}),
+						),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(usergrant.NewUserGrantRemovedEvent(context.Background(),
+								&usergrant.NewAggregate("usergrant1", "org1").Aggregate,
+								"user1",
+								"project1",
+								"",
+							)),
+						},
+						uniqueConstraintsFromEventConstraint(usergrant.NewRemoveUserGrantUniqueConstraint("org1", "user1", "project1", "")),
+					),
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				managedRoles:  managedRoles,
+				userGrantIDs:  []string{"usergrant1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore:  tt.fields.eventstore,
+				idGenerator: tt.fields.idGenerator,
+			}
+			err := r.SyncUserGrantsByIDPClaims(tt.args.ctx, tt.args.userID, tt.args.resourceOwner, tt.args.roles, tt.args.managedRoles, tt.args.userGrantIDs)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+		})
+	}
+}
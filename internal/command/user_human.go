@@ -210,7 +210,7 @@ func (c *Commands) AddHumanCommand(human *AddHuman, orgID string, passwordAlg cr
 				createCmd.AddPhoneData(human.Phone.Number)
 			}
 
-			if err := addHumanCommandPassword(ctx, filter, createCmd, human, passwordAlg); err != nil {
+			if err := addHumanCommandPassword(ctx, filter, createCmd, human, a.ResourceOwner, passwordAlg, c.breachedPasswords); err != nil {
 				return nil, err
 			}
 
@@ -316,9 +316,9 @@ func (c *Commands) addHumanCommandCheckID(ctx context.Context, filter preparatio
 	return nil
 }
 
-func addHumanCommandPassword(ctx context.Context, filter preparation.FilterToQueryReducer, createCmd humanCreationCommand, human *AddHuman, passwordAlg crypto.HashAlgorithm) (err error) {
+func addHumanCommandPassword(ctx context.Context, filter preparation.FilterToQueryReducer, createCmd humanCreationCommand, human *AddHuman, orgID string, passwordAlg crypto.HashAlgorithm, breachedPasswords domain.BreachedPasswordChecker) (err error) {
 	if human.Password != "" {
-		if err = humanValidatePassword(ctx, filter, human.Password); err != nil {
+		if err = humanValidatePassword(ctx, filter, human, orgID, breachedPasswords); err != nil {
 			return err
 		}
 
@@ -363,13 +363,42 @@ func userValidateDomain(ctx context.Context, a *user.Aggregate, username string,
 	return nil
 }
 
-func humanValidatePassword(ctx context.Context, filter preparation.FilterToQueryReducer, password string) error {
+func humanValidatePassword(ctx context.Context, filter preparation.FilterToQueryReducer, human *AddHuman, orgID string, breachedPasswords domain.BreachedPasswordChecker) error {
 	passwordComplexity, err := passwordComplexityPolicyWriteModel(ctx, filter)
 	if err != nil {
 		return err
 	}
+	if err = passwordComplexity.Validate(human.Password); err != nil {
+		return err
+	}
 
-	return passwordComplexity.Validate(password)
+	policy := writeModelToPasswordComplexityPolicy(passwordComplexity)
+	if policy.ForbidsUserAttributes() {
+		if err = humanValidatePasswordUserAttributes(ctx, filter, human, orgID, policy); err != nil {
+			return err
+		}
+	}
+	return policy.CheckBreached(ctx, human.Password, breachedPasswords)
+}
+
+func humanValidatePasswordUserAttributes(ctx context.Context, filter preparation.FilterToQueryReducer, human *AddHuman, orgID string, policy *domain.PasswordComplexityPolicy) error {
+	attributes := &domain.PasswordUserAttributes{
+		Username: human.Username,
+		Email:    string(human.Email.Address),
+	}
+	if policy.ForbidOrgName {
+		org := NewOrgWriteModel(orgID)
+		events, err := filter(ctx, org.Query())
+		if err != nil {
+			return err
+		}
+		org.AppendEvents(events...)
+		if err = org.Reduce(); err != nil {
+			return err
+		}
+		attributes.OrgName = org.Name
+	}
+	return policy.CheckUserAttributes(human.Password, attributes)
 }
 
 func (h *AddHuman) ensureDisplayName() {
@@ -578,6 +607,16 @@ func (c *Commands) createHuman(ctx context.Context, orgID string, human *domain.
 
 	human.EnsureDisplayName()
 	if human.Password != nil {
+		attributes := &domain.PasswordUserAttributes{
+			Username: human.Username,
+			Email:    string(human.EmailAddress),
+		}
+		if err := c.checkPasswordUserAttributes(ctx, pwPolicy, human.Password.SecretString, attributes, orgID); err != nil {
+			return nil, nil, err
+		}
+		if err := c.checkPasswordBreached(ctx, pwPolicy, human.Password.SecretString); err != nil {
+			return nil, nil, err
+		}
 		if err := human.HashPasswordIfExisting(pwPolicy, c.userPasswordAlg, human.Password.ChangeRequired); err != nil {
 			return nil, nil, err
 		}
@@ -103,6 +103,15 @@ func (wm *HumanWriteModel) Reduce() error {
 	return wm.WriteModel.Reduce()
 }
 
+func (wm *HumanWriteModel) SnapshotType() string {
+	return "command.human"
+}
+
+// SnapshotVersion must be increased if the reduced state changes
+func (wm *HumanWriteModel) SnapshotVersion() string {
+	return "v1"
+}
+
 func (wm *HumanWriteModel) Query() *eventstore.SearchQueryBuilder {
 	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
 		ResourceOwner(wm.ResourceOwner).
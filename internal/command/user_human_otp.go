@@ -159,13 +159,18 @@ func (c *Commands) HumanCheckMFAOTP(ctx context.Context, userID, code, resourceo
 	if existingOTP.State != domain.MFAStateReady {
 		return caos_errs.ThrowPreconditionFailed(nil, "COMMAND-3Mif9s", "Errors.User.MFA.OTP.NotReady")
 	}
+	lockout, err := c.newUserLockout().check(ctx, userID, resourceowner, domain.LockoutFactorOTP, remoteIP(ctx), nil)
+	if err != nil {
+		return err
+	}
 	userAgg := UserAggregateFromWriteModel(&existingOTP.WriteModel)
 	err = domain.VerifyMFAOTP(code, existingOTP.Secret, c.multifactors.OTP.CryptoMFA)
 	if err == nil {
 		_, err = c.eventstore.Push(ctx, user.NewHumanOTPCheckSucceededEvent(ctx, userAgg, authRequestDomainToAuthRequestInfo(authRequest)))
 		return err
 	}
-	_, pushErr := c.eventstore.Push(ctx, user.NewHumanOTPCheckFailedEvent(ctx, userAgg, authRequestDomainToAuthRequestInfo(authRequest)))
+	events := []eventstore.Command{user.NewHumanOTPCheckFailedEvent(ctx, userAgg, authRequestDomainToAuthRequestInfo(authRequest))}
+	_, pushErr := c.eventstore.Push(ctx, append(events, lockout.failed(ctx, userAgg)...)...)
 	logging.Log("COMMAND-9fj7s").OnError(pushErr).Error("error create password check failed event")
 	return err
 }
@@ -29,6 +29,8 @@ func (wm *HumanOTPWriteModel) Reduce() error {
 		case *user.HumanOTPAddedEvent:
 			wm.Secret = e.Secret
 			wm.State = domain.MFAStateNotReady
+		case *user.HumanOTPSecretReencryptedEvent:
+			wm.Secret = e.Secret
 		case *user.HumanOTPVerifiedEvent:
 			wm.State = domain.MFAStateReady
 		case *user.HumanOTPRemovedEvent:
@@ -48,6 +50,7 @@ func (wm *HumanOTPWriteModel) Query() *eventstore.SearchQueryBuilder {
 		EventTypes(user.HumanMFAOTPAddedType,
 			user.HumanMFAOTPVerifiedType,
 			user.HumanMFAOTPRemovedType,
+			user.HumanMFAOTPSecretReencryptedType,
 			user.UserRemovedType,
 			user.UserV1MFAOTPAddedType,
 			user.UserV1MFAOTPVerifiedType,
@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"time"
 
 	"github.com/zitadel/logging"
 
@@ -34,7 +35,7 @@ func (c *Commands) SetPassword(ctx context.Context, orgID, userID, passwordStrin
 		ChangeRequired: oneTime,
 	}
 	userAgg := UserAggregateFromWriteModel(&existingPassword.WriteModel)
-	passwordEvent, err := c.changePassword(ctx, "", password, userAgg, existingPassword)
+	passwordEvent, err := c.changePassword(ctx, "", password, userAgg, existingPassword, false)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +69,7 @@ func (c *Commands) SetPasswordWithVerifyCode(ctx context.Context, orgID, userID,
 		return nil, caos_errs.ThrowPreconditionFailed(nil, "COMMAND-2M9fs", "Errors.User.Code.NotFound")
 	}
 
-	err = crypto.VerifyCodeWithAlgorithm(existingCode.CodeCreationDate, existingCode.CodeExpiry, existingCode.Code, code, c.userEncryption)
+	err = verifyCryptoCode(ctx, c.eventstore.Filter, domain.SecretGeneratorTypePasswordResetCode, c.userEncryption, existingCode.CodeCreationDate, existingCode.CodeExpiry, existingCode.Code, code)
 	if err != nil {
 		return nil, err
 	}
@@ -78,7 +79,8 @@ func (c *Commands) SetPasswordWithVerifyCode(ctx context.Context, orgID, userID,
 		ChangeRequired: false,
 	}
 	userAgg := UserAggregateFromWriteModel(&existingCode.WriteModel)
-	passwordEvent, err := c.changePassword(ctx, userAgentID, password, userAgg, existingCode)
+	// the user forgot the password, so the minimum age must not prevent the reset
+	passwordEvent, err := c.changePassword(ctx, userAgentID, password, userAgg, existingCode, false)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +121,7 @@ func (c *Commands) ChangePassword(ctx context.Context, orgID, userID, oldPasswor
 	}
 
 	userAgg := UserAggregateFromWriteModel(&existingPassword.WriteModel)
-	command, err := c.changePassword(ctx, userAgentID, password, userAgg, existingPassword)
+	command, err := c.changePassword(ctx, userAgentID, password, userAgg, existingPassword, true)
 	if err != nil {
 		return nil, err
 	}
@@ -134,7 +136,9 @@ func (c *Commands) ChangePassword(ctx context.Context, orgID, userID, oldPasswor
 	return writeModelToObjectDetails(&existingPassword.WriteModel), nil
 }
 
-func (c *Commands) changePassword(ctx context.Context, userAgentID string, password *domain.Password, userAgg *eventstore.Aggregate, existingPassword *HumanPasswordWriteModel) (event eventstore.Command, err error) {
+// changePassword checks the new password against the policies of the organisation of the user.
+// The minimum age of the current password is only checked on changes by the user with the current password (checkMinAge)
+func (c *Commands) changePassword(ctx context.Context, userAgentID string, password *domain.Password, userAgg *eventstore.Aggregate, existingPassword *HumanPasswordWriteModel, checkMinAge bool) (event eventstore.Command, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
 
@@ -148,12 +152,95 @@ func (c *Commands) changePassword(ctx context.Context, userAgentID string, passw
 	if err != nil {
 		return nil, err
 	}
+	// the cheap policies are checked first,
+	// so the history (which compares the password with up to domain.MaxPasswordHistoryCount hashes) is only checked for valid passwords
+	if password.SecretString != "" {
+		if pwPolicy == nil {
+			return nil, caos_errs.ThrowPreconditionFailed(nil, "COMMAND-Ohx0e", "Errors.User.PasswordComplexityPolicy.NotFound")
+		}
+		if err := pwPolicy.Check(password.SecretString); err != nil {
+			return nil, err
+		}
+		if pwPolicy.ForbidsUserAttributes() {
+			human := NewHumanWriteModel(userAgg.ID, userAgg.ResourceOwner)
+			if err := c.eventstore.FilterToQueryReducer(ctx, human); err != nil {
+				return nil, err
+			}
+			attributes := &domain.PasswordUserAttributes{
+				Username: human.UserName,
+				Email:    string(human.Email),
+			}
+			if err := c.checkPasswordUserAttributes(ctx, pwPolicy, password.SecretString, attributes, userAgg.ResourceOwner); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.checkPasswordBreached(ctx, pwPolicy, password.SecretString); err != nil {
+			return nil, err
+		}
+		if err := c.checkPasswordHistory(ctx, userAgg.ResourceOwner, password.SecretString, existingPassword, checkMinAge); err != nil {
+			return nil, err
+		}
+	}
 	if err := password.HashPasswordIfExisting(pwPolicy, c.userPasswordAlg); err != nil {
 		return nil, err
 	}
 	return user.NewHumanPasswordChangedEvent(ctx, userAgg, password.SecretCrypto, password.ChangeRequired, userAgentID), nil
 }
 
+// checkPasswordHistory checks the password age policy of the organisation:
+// the new password must not be one of the recent passwords of the user
+// and the current password must be older than the minimum age (unless a change is required)
+func (c *Commands) checkPasswordHistory(ctx context.Context, resourceOwner, password string, existingPassword *HumanPasswordWriteModel, checkMinAge bool) (err error) {
+	if existingPassword.Secret == nil && len(existingPassword.PreviousSecrets) == 0 {
+		return nil
+	}
+	agePolicy, err := c.getOrgPasswordAgePolicy(ctx, resourceOwner)
+	if err != nil {
+		return err
+	}
+	if checkMinAge && existingPassword.Secret != nil && !existingPassword.SecretChangeRequired &&
+		!agePolicy.MinAgeReached(existingPassword.SecretChangeDate, time.Now()) {
+		return caos_errs.ThrowPreconditionFailed(nil, "COMMAND-Thai1", "Errors.User.Password.MinAgeNotReached")
+	}
+	ctx, span := tracing.NewNamedSpan(ctx, "crypto.CompareHash")
+	defer func() { span.EndWithError(err) }()
+	// policies stored before the history count was validated could exceed the maximum
+	historyCount := agePolicy.HistoryCount
+	if historyCount > domain.MaxPasswordHistoryCount {
+		historyCount = domain.MaxPasswordHistoryCount
+	}
+	for _, secret := range existingPassword.RecentSecrets(historyCount) {
+		if crypto.CompareHash(secret, []byte(password), c.userPasswordAlg) == nil {
+			return caos_errs.ThrowInvalidArgument(nil, "COMMAND-ieR5e", "Errors.User.Password.RecentlyUsed")
+		}
+	}
+	return nil
+}
+
+// checkPasswordUserAttributes checks the password against the attributes of the user and the name of the organisation,
+// if they are forbidden by the policy
+func (c *Commands) checkPasswordUserAttributes(ctx context.Context, policy *domain.PasswordComplexityPolicy, password string, attributes *domain.PasswordUserAttributes, orgID string) error {
+	if password == "" || policy == nil || !policy.ForbidsUserAttributes() {
+		return nil
+	}
+	if policy.ForbidOrgName {
+		org, err := c.getOrgWriteModelByID(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		attributes.OrgName = org.Name
+	}
+	return policy.CheckUserAttributes(password, attributes)
+}
+
+// checkPasswordBreached checks the password against the breached-password corpus, if required by the policy
+func (c *Commands) checkPasswordBreached(ctx context.Context, policy *domain.PasswordComplexityPolicy, password string) error {
+	if password == "" || policy == nil {
+		return nil
+	}
+	return policy.CheckBreached(ctx, password, c.breachedPasswords)
+}
+
 func (c *Commands) RequestSetPassword(ctx context.Context, userID, resourceOwner string, notifyType domain.NotificationType, passwordVerificationCode crypto.Generator) (objectDetails *domain.ObjectDetails, err error) {
 	if userID == "" {
 		return nil, caos_errs.ThrowInvalidArgument(nil, "COMMAND-M00oL", "Errors.User.UserIDMissing")
@@ -250,22 +337,26 @@ func (c *Commands) HumanCheckPassword(ctx context.Context, orgID, userID, passwo
 		return caos_errs.ThrowPreconditionFailed(nil, "COMMAND-3n77z", "Errors.User.Password.NotSet")
 	}
 
+	lockout, err := c.newUserLockout().check(ctx, userID, orgID, domain.LockoutFactorPassword, remoteIP(ctx), lockoutPolicy)
+	if err != nil {
+		return err
+	}
+
 	userAgg := UserAggregateFromWriteModel(&existingPassword.WriteModel)
-	ctx, spanPasswordComparison := tracing.NewNamedSpan(ctx, "crypto.CompareHash")
-	err = crypto.CompareHash(existingPassword.Secret, []byte(password), c.userPasswordAlg)
+	ctx, spanPasswordComparison := tracing.NewNamedSpan(ctx, "crypto.VerifyPassword")
+	updated, err := crypto.VerifyPassword(existingPassword.Secret, []byte(password), c.userPasswordAlg)
 	spanPasswordComparison.EndWithError(err)
 	if err == nil {
-		_, err = c.eventstore.Push(ctx, user.NewHumanPasswordCheckSucceededEvent(ctx, userAgg, authRequestDomainToAuthRequestInfo(authRequest)))
+		events := []eventstore.Command{user.NewHumanPasswordCheckSucceededEvent(ctx, userAgg, authRequestDomainToAuthRequestInfo(authRequest))}
+		if updated != nil {
+			events = append(events, user.NewHumanPasswordHashUpdatedEvent(ctx, userAgg, updated))
+		}
+		_, err = c.eventstore.Push(ctx, events...)
 		return err
 	}
 	events := make([]eventstore.Command, 0)
 	events = append(events, user.NewHumanPasswordCheckFailedEvent(ctx, userAgg, authRequestDomainToAuthRequestInfo(authRequest)))
-	if lockoutPolicy != nil && lockoutPolicy.MaxPasswordAttempts > 0 {
-		if existingPassword.PasswordCheckFailedCount+1 >= lockoutPolicy.MaxPasswordAttempts {
-			events = append(events, user.NewUserLockedEvent(ctx, userAgg))
-		}
-
-	}
+	events = append(events, lockout.failed(ctx, userAgg)...)
 	_, err = c.eventstore.Push(ctx, events...)
 	logging.Log("COMMAND-9fj7s").OnError(err).Error("error create password check failed event")
 	return caos_errs.ThrowInvalidArgument(nil, "COMMAND-452ad", "Errors.User.Password.Invalid")
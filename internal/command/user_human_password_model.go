@@ -15,6 +15,9 @@ type HumanPasswordWriteModel struct {
 
 	Secret               *crypto.CryptoValue
 	SecretChangeRequired bool
+	SecretChangeDate     time.Time
+	// PreviousSecrets are the hashes of the passwords before the current one, the most recent last
+	PreviousSecrets []*crypto.CryptoValue
 
 	Code                     *crypto.CryptoValue
 	CodeCreationDate         time.Time
@@ -37,11 +40,11 @@ func (wm *HumanPasswordWriteModel) Reduce() error {
 	for _, event := range wm.Events {
 		switch e := event.(type) {
 		case *user.HumanAddedEvent:
-			wm.Secret = e.Secret
+			wm.setSecret(e.Secret, e.CreationDate())
 			wm.SecretChangeRequired = e.ChangeRequired
 			wm.UserState = domain.UserStateActive
 		case *user.HumanRegisteredEvent:
-			wm.Secret = e.Secret
+			wm.setSecret(e.Secret, e.CreationDate())
 			wm.SecretChangeRequired = e.ChangeRequired
 			wm.UserState = domain.UserStateActive
 		case *user.HumanInitialCodeAddedEvent:
@@ -49,10 +52,12 @@ func (wm *HumanPasswordWriteModel) Reduce() error {
 		case *user.HumanInitializedCheckSucceededEvent:
 			wm.UserState = domain.UserStateActive
 		case *user.HumanPasswordChangedEvent:
-			wm.Secret = e.Secret
+			wm.setSecret(e.Secret, e.CreationDate())
 			wm.SecretChangeRequired = e.ChangeRequired
 			wm.Code = nil
 			wm.PasswordCheckFailedCount = 0
+		case *user.HumanPasswordHashUpdatedEvent:
+			wm.Secret = e.Secret
 		case *user.HumanPasswordCodeAddedEvent:
 			wm.Code = e.Code
 			wm.CodeCreationDate = e.CreationDate()
@@ -74,6 +79,40 @@ func (wm *HumanPasswordWriteModel) Reduce() error {
 	return wm.WriteModel.Reduce()
 }
 
+func (wm *HumanPasswordWriteModel) setSecret(secret *crypto.CryptoValue, changeDate time.Time) {
+	if wm.Secret != nil {
+		wm.PreviousSecrets = append(wm.PreviousSecrets, wm.Secret)
+		// the current password counts to the history as well
+		if len(wm.PreviousSecrets) >= domain.MaxPasswordHistoryCount {
+			wm.PreviousSecrets = wm.PreviousSecrets[len(wm.PreviousSecrets)-domain.MaxPasswordHistoryCount+1:]
+		}
+	}
+	wm.Secret = secret
+	wm.SecretChangeDate = changeDate
+}
+
+// RecentSecrets returns the hashes of the current and the previous passwords
+// up to the history count of the password age policy, the most recent first
+func (wm *HumanPasswordWriteModel) RecentSecrets(historyCount uint64) []*crypto.CryptoValue {
+	secrets := make([]*crypto.CryptoValue, 0, historyCount)
+	if wm.Secret != nil && historyCount > 0 {
+		secrets = append(secrets, wm.Secret)
+	}
+	for i := len(wm.PreviousSecrets) - 1; i >= 0 && uint64(len(secrets)) < historyCount; i-- {
+		secrets = append(secrets, wm.PreviousSecrets[i])
+	}
+	return secrets
+}
+
+func (wm *HumanPasswordWriteModel) SnapshotType() string {
+	return "command.human.password"
+}
+
+// SnapshotVersion must be increased if the reduced state changes
+func (wm *HumanPasswordWriteModel) SnapshotVersion() string {
+	return "v1"
+}
+
 func (wm *HumanPasswordWriteModel) Query() *eventstore.SearchQueryBuilder {
 	query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
 		AddQuery().
@@ -85,6 +124,7 @@ func (wm *HumanPasswordWriteModel) Query() *eventstore.SearchQueryBuilder {
 			user.HumanInitializedCheckSucceededType,
 			user.HumanPasswordChangedType,
 			user.HumanPasswordCodeAddedType,
+			user.HumanPasswordHashUpdatedType,
 			user.HumanEmailVerifiedType,
 			user.HumanPasswordCheckFailedType,
 			user.HumanPasswordCheckSucceededType,
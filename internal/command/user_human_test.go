@@ -27,11 +27,12 @@ import (
 
 func TestCommandSide_AddHuman(t *testing.T) {
 	type fields struct {
-		eventstore      *eventstore.Eventstore
-		idGenerator     id.Generator
-		userPasswordAlg crypto.HashAlgorithm
-		codeAlg         crypto.EncryptionAlgorithm
-		newCode         cryptoCodeFunc
+		eventstore        *eventstore.Eventstore
+		idGenerator       id.Generator
+		userPasswordAlg   crypto.HashAlgorithm
+		codeAlg           crypto.EncryptionAlgorithm
+		newCode           cryptoCodeFunc
+		breachedPasswords domain.BreachedPasswordChecker
 	}
 	type args struct {
 		ctx             context.Context
@@ -239,6 +240,7 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 					),
@@ -301,6 +303,61 @@ func TestCommandSide_AddHuman(t *testing.T) {
 				wantID: "user1",
 			},
 		},
+		{
+			name: "add human (with breached password), invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewDomainPolicyAddedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewPasswordComplexityPolicyAddedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								1,
+								false,
+								false,
+								false,
+								false,
+								true,
+								0, false, false, false, nil, nil,
+							),
+						),
+					),
+				),
+				idGenerator:       id_mock.NewIDGeneratorExpectIDs(t, "user1"),
+				userPasswordAlg:   crypto.CreateMockHashAlg(gomock.NewController(t)),
+				codeAlg:           crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				breachedPasswords: mockBreachedPasswords{"password": true},
+			},
+			args: args{
+				ctx:   context.Background(),
+				orgID: "org1",
+				human: &AddHuman{
+					Username:  "username",
+					Password:  "password",
+					FirstName: "firstname",
+					LastName:  "lastname",
+					Email: Email{
+						Address: "email@test.ch",
+					},
+					PreferredLanguage: language.English,
+				},
+				allowInitMail: true,
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
 		{
 			name: "add human (with password and initial code), ok",
 			fields: fields{
@@ -326,6 +383,8 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -341,6 +400,7 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 					),
@@ -417,6 +477,8 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -497,6 +559,8 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -578,6 +642,8 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -648,6 +714,8 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -718,6 +786,8 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -849,6 +919,8 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -940,6 +1012,8 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -955,6 +1029,7 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 					),
@@ -1042,6 +1117,7 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 					),
@@ -1130,6 +1206,7 @@ func TestCommandSide_AddHuman(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							),
 						),
 					),
@@ -1198,11 +1275,12 @@ func TestCommandSide_AddHuman(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Commands{
-				eventstore:      tt.fields.eventstore,
-				userPasswordAlg: tt.fields.userPasswordAlg,
-				userEncryption:  tt.fields.codeAlg,
-				idGenerator:     tt.fields.idGenerator,
-				newCode:         tt.fields.newCode,
+				eventstore:        tt.fields.eventstore,
+				userPasswordAlg:   tt.fields.userPasswordAlg,
+				userEncryption:    tt.fields.codeAlg,
+				idGenerator:       tt.fields.idGenerator,
+				newCode:           tt.fields.newCode,
+				breachedPasswords: tt.fields.breachedPasswords,
 			}
 			err := r.AddHuman(tt.args.ctx, tt.args.orgID, tt.args.human, tt.args.allowInitMail)
 			if tt.res.err == nil {
@@ -1224,9 +1302,10 @@ func TestCommandSide_AddHuman(t *testing.T) {
 
 func TestCommandSide_ImportHuman(t *testing.T) {
 	type fields struct {
-		eventstore      *eventstore.Eventstore
-		idGenerator     id.Generator
-		userPasswordAlg crypto.HashAlgorithm
+		eventstore        *eventstore.Eventstore
+		idGenerator       id.Generator
+		userPasswordAlg   crypto.HashAlgorithm
+		breachedPasswords domain.BreachedPasswordChecker
 	}
 	type args struct {
 		ctx                  context.Context
@@ -1361,6 +1440,8 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -1380,6 +1461,64 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 				err: caos_errs.IsErrorInvalidArgument,
 			},
 		},
+		{
+			name: "add human (with breached password), invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							org.NewDomainPolicyAddedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewPasswordComplexityPolicyAddedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								1,
+								false,
+								false,
+								false,
+								false,
+								true,
+								0, false, false, false, nil, nil,
+							),
+						),
+					),
+				),
+				idGenerator:       id_mock.NewIDGeneratorExpectIDs(t, "user1"),
+				userPasswordAlg:   crypto.CreateMockHashAlg(gomock.NewController(t)),
+				breachedPasswords: mockBreachedPasswords{"password": true},
+			},
+			args: args{
+				ctx:   context.Background(),
+				orgID: "org1",
+				human: &domain.Human{
+					Username: "username",
+					Password: &domain.Password{
+						SecretString:   "password",
+						ChangeRequired: true,
+					},
+					Profile: &domain.Profile{
+						FirstName:         "firstname",
+						LastName:          "lastname",
+						PreferredLanguage: language.English,
+					},
+					Email: &domain.Email{
+						EmailAddress: "email@test.ch",
+					},
+				},
+				secretGenerator: GetMockSecretGenerator(t),
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
 		{
 			name: "add human (with password and initial code), ok",
 			fields: fields{
@@ -1404,6 +1543,8 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -1495,6 +1636,8 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -1580,6 +1723,8 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -1687,6 +1832,8 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -1798,6 +1945,8 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -1905,6 +2054,8 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -2007,6 +2158,8 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -2116,9 +2269,10 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Commands{
-				eventstore:      tt.fields.eventstore,
-				idGenerator:     tt.fields.idGenerator,
-				userPasswordAlg: tt.fields.userPasswordAlg,
+				eventstore:        tt.fields.eventstore,
+				idGenerator:       tt.fields.idGenerator,
+				userPasswordAlg:   tt.fields.userPasswordAlg,
+				breachedPasswords: tt.fields.breachedPasswords,
 			}
 			gotHuman, gotCode, err := r.ImportHuman(tt.args.ctx, tt.args.orgID, tt.args.human, tt.args.passwordless, tt.args.links, tt.args.secretGenerator, tt.args.secretGenerator, tt.args.secretGenerator, tt.args.secretGenerator)
 			if tt.res.err == nil {
@@ -2137,9 +2291,10 @@ func TestCommandSide_ImportHuman(t *testing.T) {
 
 func TestCommandSide_RegisterHuman(t *testing.T) {
 	type fields struct {
-		eventstore      *eventstore.Eventstore
-		idGenerator     id.Generator
-		userPasswordAlg crypto.HashAlgorithm
+		eventstore        *eventstore.Eventstore
+		idGenerator       id.Generator
+		userPasswordAlg   crypto.HashAlgorithm
+		breachedPasswords domain.BreachedPasswordChecker
 	}
 	type args struct {
 		ctx             context.Context
@@ -2278,6 +2433,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -2326,6 +2483,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -2395,6 +2554,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -2464,6 +2625,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -2550,6 +2713,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -2694,6 +2859,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -2782,6 +2949,85 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "add human (with breached password), invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							org.NewDomainPolicyAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewPasswordComplexityPolicyAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								1,
+								false,
+								false,
+								false,
+								false,
+								true,
+								0, false, false, false, nil, nil,
+							),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewLoginPolicyAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								false,
+								true,
+								false,
+								false,
+								false,
+								false,
+								false,
+								false,
+								false,
+								domain.PasswordlessTypeNotAllowed,
+								"",
+								time.Hour*1,
+								time.Hour*2,
+								time.Hour*3,
+								time.Hour*4,
+								time.Hour*5,
+							),
+						),
+					),
+				),
+				idGenerator:       id_mock.NewIDGeneratorExpectIDs(t, "user1"),
+				userPasswordAlg:   crypto.CreateMockHashAlg(gomock.NewController(t)),
+				breachedPasswords: mockBreachedPasswords{"password": true},
+			},
+			args: args{
+				ctx:   context.Background(),
+				orgID: "org1",
+				human: &domain.Human{
+					Username: "username",
+					Password: &domain.Password{
+						SecretString: "password",
+					},
+					Profile: &domain.Profile{
+						FirstName: "firstname",
+						LastName:  "lastname",
+					},
+					Email: &domain.Email{
+						EmailAddress: "email@test.ch",
+					},
+				},
+				secretGenerator: GetMockSecretGenerator(t),
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
 		{
 			name: "add human (with password and initial code), ok",
 			fields: fields{
@@ -2806,6 +3052,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -2918,6 +3166,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -3024,6 +3274,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -3152,6 +3404,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -3275,6 +3529,8 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 								false,
 								false,
 								false,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						),
 					),
@@ -3407,9 +3663,10 @@ func TestCommandSide_RegisterHuman(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Commands{
-				eventstore:      tt.fields.eventstore,
-				idGenerator:     tt.fields.idGenerator,
-				userPasswordAlg: tt.fields.userPasswordAlg,
+				eventstore:        tt.fields.eventstore,
+				idGenerator:       tt.fields.idGenerator,
+				userPasswordAlg:   tt.fields.userPasswordAlg,
+				breachedPasswords: tt.fields.breachedPasswords,
 			}
 			got, err := r.RegisterHuman(tt.args.ctx, tt.args.orgID, tt.args.human, tt.args.link, tt.args.orgMemberRoles, tt.args.secretGenerator, tt.args.secretGenerator, tt.args.secretGenerator)
 			if tt.res.err == nil {
@@ -3792,7 +4049,8 @@ func newRegisterHumanEvent(username, password string, changeRequired bool, phone
 
 func TestAddHumanCommand(t *testing.T) {
 	type fields struct {
-		idGenerator id.Generator
+		idGenerator       id.Generator
+		breachedPasswords domain.BreachedPasswordChecker
 	}
 	type args struct {
 		human         *AddHuman
@@ -3896,6 +4154,8 @@ func TestAddHumanCommand(t *testing.T) {
 									true,
 									true,
 									true,
+									false,
+									0, false, false, false, nil, nil,
 								),
 							}, nil
 						}).
@@ -3905,6 +4165,60 @@ func TestAddHumanCommand(t *testing.T) {
 				CreateErr: caos_errs.ThrowInvalidArgument(nil, "COMMA-HuJf6", "Errors.User.PasswordComplexityPolicy.MinLength"),
 			},
 		},
+		{
+			name: "breached password",
+			fields: fields{
+				idGenerator:       id_mock.NewIDGeneratorExpectIDs(t, "id"),
+				breachedPasswords: mockBreachedPasswords{"password": true},
+			},
+			args: args{
+				human: &AddHuman{
+					Email:             Email{Address: "support@zitadel.com"},
+					PreferredLanguage: language.English,
+					FirstName:         "gigi",
+					LastName:          "giraffe",
+					Password:          "password",
+					Username:          "username",
+				},
+				orgID: "ro",
+				filter: NewMultiFilter().Append(
+					func(ctx context.Context, queryFactory *eventstore.SearchQueryBuilder) ([]eventstore.Event, error) {
+						return []eventstore.Event{}, nil
+					}).
+					Append(
+						func(ctx context.Context, queryFactory *eventstore.SearchQueryBuilder) ([]eventstore.Event, error) {
+							return []eventstore.Event{
+								org.NewDomainPolicyAddedEvent(
+									ctx,
+									&org.NewAggregate("id").Aggregate,
+									true,
+									true,
+									true,
+								),
+							}, nil
+						}).
+					Append(
+						func(ctx context.Context, queryFactory *eventstore.SearchQueryBuilder) ([]eventstore.Event, error) {
+							return []eventstore.Event{
+								org.NewPasswordComplexityPolicyAddedEvent(
+									ctx,
+									&org.NewAggregate("id").Aggregate,
+									2,
+									false,
+									false,
+									false,
+									false,
+									true,
+									0, false, false, false, nil, nil,
+								),
+							}, nil
+						}).
+					Filter(),
+			},
+			want: Want{
+				CreateErr: caos_errs.ThrowInvalidArgument(nil, "DOMAIN-oaK3e", "Errors.User.PasswordComplexityPolicy.Breached"),
+			},
+		},
 		{
 			name: "correct",
 			fields: fields{
@@ -3949,6 +4263,8 @@ func TestAddHumanCommand(t *testing.T) {
 									false,
 									false,
 									false,
+									false,
+									0, false, false, false, nil, nil,
 								),
 							}, nil
 						}).
@@ -3986,7 +4302,8 @@ func TestAddHumanCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Commands{
-				idGenerator: tt.fields.idGenerator,
+				idGenerator:       tt.fields.idGenerator,
+				breachedPasswords: tt.fields.breachedPasswords,
 			}
 			AssertValidation(t, context.Background(), c.AddHumanCommand(tt.args.human, tt.args.orgID, tt.args.passwordAlg, tt.args.codeAlg, tt.args.allowInitMail), tt.args.filter, tt.want)
 		})
@@ -406,6 +406,10 @@ func (c *Commands) HumanFinishPasswordlessLogin(ctx context.Context, userID, res
 	if err != nil {
 		return err
 	}
+	lockout, err := c.newUserLockout().check(ctx, userID, resourceOwner, domain.LockoutFactorPasskey, remoteIP(ctx), nil)
+	if err != nil {
+		return err
+	}
 
 	passwordlessTokens, err := c.getHumanPasswordlessTokens(ctx, userID, resourceOwner)
 	if err != nil {
@@ -418,13 +422,14 @@ func (c *Commands) HumanFinishPasswordlessLogin(ctx context.Context, userID, res
 			logging.WithFields("userID", userID, "resourceOwner", resourceOwner).WithError(err).Warn("missing userAggregate for pushing failed passwordless check event")
 			return err
 		}
-		_, pushErr := c.eventstore.Push(ctx,
+		events := []eventstore.Command{
 			usr_repo.NewHumanPasswordlessCheckFailedEvent(
 				ctx,
 				userAgg,
 				authRequestDomainToAuthRequestInfo(authRequest),
 			),
-		)
+		}
+		_, pushErr := c.eventstore.Push(ctx, append(events, lockout.failed(ctx, userAgg)...)...)
 		logging.WithFields("userID", userID, "resourceOwner", resourceOwner).OnError(pushErr).Warn("could not push failed passwordless check event")
 		return err
 	}
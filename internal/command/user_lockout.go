@@ -0,0 +1,165 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	http_utils "github.com/zitadel/zitadel/internal/api/http"
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/lockout"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+// ReleaseExpiredUserLock unlocks the user if the lockout duration of the lock has passed.
+// It returns if the user was unlocked.
+func (c *Commands) ReleaseExpiredUserLock(ctx context.Context, userID, resourceOwner string) (bool, error) {
+	if userID == "" {
+		return false, caos_errs.ThrowInvalidArgument(nil, "COMMAND-Ood8u", "Errors.User.UserIDMissing")
+	}
+	writeModel := NewUserLockoutWriteModel(userID, resourceOwner)
+	if err := c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return false, err
+	}
+	if !writeModel.LockExpired(time.Now()) {
+		return false, nil
+	}
+	_, err := c.eventstore.Push(ctx, user.NewUserUnlockedEvent(ctx, UserAggregateFromWriteModel(&writeModel.WriteModel)))
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Commands) newUserLockout() *userLockout {
+	return &userLockout{
+		eventstore: c.eventstore,
+		now:        time.Now,
+	}
+}
+
+// userLockout enforces the lockout policy on the checks of a user
+type userLockout struct {
+	eventstore *eventstore.Eventstore
+	now        func() time.Time
+}
+
+// userLockoutCheck is the lockout state of a user for the check of a single factor
+type userLockoutCheck struct {
+	writeModel *UserLockoutWriteModel
+	policy     *domain.LockoutPolicy
+	factor     domain.LockoutFactor
+	remoteIP   string
+	now        time.Time
+}
+
+// check returns an error if the user or the source IP is not allowed to check the factor (yet).
+// An expired lock of the user is released.
+// If the policy is nil, the lockout policy of the organisation of the user is used.
+func (l *userLockout) check(ctx context.Context, userID, resourceOwner string, factor domain.LockoutFactor, remoteIP string, policy *domain.LockoutPolicy) (_ *userLockoutCheck, err error) {
+	writeModel := NewUserLockoutWriteModel(userID, resourceOwner)
+	if err = l.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		if resourceOwner == "" {
+			resourceOwner = writeModel.ResourceOwner
+		}
+		policy, err = lockoutPolicyByOrg(ctx, l.eventstore, resourceOwner)
+		if err != nil {
+			return nil, err
+		}
+	}
+	lockoutCheck := &userLockoutCheck{
+		writeModel: writeModel,
+		policy:     policy,
+		factor:     factor,
+		remoteIP:   remoteIP,
+		now:        l.now(),
+	}
+	if err = l.checkIP(ctx, lockoutCheck); err != nil {
+		return nil, err
+	}
+	if writeModel.Locked {
+		if !writeModel.LockExpired(lockoutCheck.now) {
+			return nil, caos_errs.ThrowPreconditionFailed(nil, "COMMAND-oL5ie", "Errors.User.Locked")
+		}
+		pushedEvents, err := l.eventstore.Push(ctx, user.NewUserUnlockedEvent(ctx, UserAggregateFromWriteModel(&writeModel.WriteModel)))
+		if err != nil {
+			return nil, err
+		}
+		if err = AppendAndReduce(writeModel, pushedEvents...); err != nil {
+			return nil, err
+		}
+	}
+	counter := writeModel.counter(factor)
+	if delay := policy.Delay(counter.FailedCount); delay > 0 && lockoutCheck.now.Before(counter.LastFailed.Add(delay)) {
+		return nil, caos_errs.ThrowPreconditionFailed(nil, "COMMAND-ahX4u", "Errors.User.Lockout.Delayed")
+	}
+	return lockoutCheck, nil
+}
+
+// checkIP returns an error if the maximum failed checks of the source IP are reached within the lockout duration
+func (l *userLockout) checkIP(ctx context.Context, lockoutCheck *userLockoutCheck) error {
+	if lockoutCheck.remoteIP == "" || lockoutCheck.policy.MaxIPAttempts == 0 {
+		return nil
+	}
+	writeModel := NewIPLockoutWriteModel(lockoutCheck.remoteIP, lockoutCheck.now.Add(-lockoutCheck.policy.IPLockoutDuration()))
+	if err := l.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return err
+	}
+	if writeModel.FailedCount >= lockoutCheck.policy.MaxIPAttempts {
+		return caos_errs.ThrowPreconditionFailed(nil, "COMMAND-Eiqu0", "Errors.User.Lockout.IPThrottled")
+	}
+	return nil
+}
+
+// failed returns the events to push with the failed check event:
+// the failed check of the source IP and the lock of the user if the maximum attempts of the factor are reached
+func (c *userLockoutCheck) failed(ctx context.Context, userAgg *eventstore.Aggregate) []eventstore.Command {
+	var events []eventstore.Command
+	if c.remoteIP != "" && c.policy.MaxIPAttempts > 0 {
+		events = append(events, lockout.NewIPCheckFailedEvent(ctx, &lockout.NewIPAggregate(ctx, c.remoteIP).Aggregate))
+	}
+	maxAttempts := c.policy.MaxAttempts(c.factor)
+	if maxAttempts == 0 || c.writeModel.counter(c.factor).FailedCount+1 < maxAttempts {
+		return events
+	}
+	if lockedUntil := c.policy.LockedUntil(c.now); !lockedUntil.IsZero() {
+		return append(events, user.NewUserLockedUntilEvent(ctx, userAgg, lockedUntil))
+	}
+	return append(events, user.NewUserLockedEvent(ctx, userAgg))
+}
+
+// hasFailedChecks returns true if there were failed checks of the factor,
+// which have to be reset by a succeeded check event
+func (c *userLockoutCheck) hasFailedChecks() bool {
+	return c.writeModel.counter(c.factor).FailedCount > 0
+}
+
+func lockoutPolicyByOrg(ctx context.Context, es *eventstore.Eventstore, orgID string) (*domain.LockoutPolicy, error) {
+	orgPolicy := NewOrgLockoutPolicyWriteModel(orgID)
+	if err := es.FilterToQueryReducer(ctx, orgPolicy); err != nil {
+		return nil, err
+	}
+	if orgPolicy.State == domain.PolicyStateActive {
+		return writeModelToLockoutPolicy(&orgPolicy.LockoutPolicyWriteModel), nil
+	}
+	instancePolicy := NewInstanceLockoutPolicyWriteModel(ctx)
+	if err := es.FilterToQueryReducer(ctx, instancePolicy); err != nil {
+		return nil, err
+	}
+	if instancePolicy.State != domain.PolicyStateActive {
+		return nil, caos_errs.ThrowNotFound(nil, "COMMAND-Hah3i", "Errors.IAM.LockoutPolicy.NotFound")
+	}
+	policy := writeModelToLockoutPolicy(&instancePolicy.LockoutPolicyWriteModel)
+	policy.Default = true
+	return policy, nil
+}
+
+// remoteIP returns the source IP of the check, resolved by the trusted proxies of the request.
+// The X-Forwarded-For header is never read directly, as it can be set by the client.
+func remoteIP(ctx context.Context) string {
+	return http_utils.ClientIPFromCtx(ctx)
+}
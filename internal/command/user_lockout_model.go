@@ -0,0 +1,164 @@
+package command
+
+import (
+	"time"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/lockout"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+type lockoutCounter struct {
+	FailedCount uint64
+	LastFailed  time.Time
+}
+
+func (c *lockoutCounter) failed(date time.Time) {
+	c.FailedCount++
+	c.LastFailed = date
+}
+
+func (c *lockoutCounter) reset() {
+	c.FailedCount = 0
+	c.LastFailed = time.Time{}
+}
+
+// UserLockoutWriteModel counts the consecutive failed checks of the factors of the lockout policy.
+// The added events are only queried for the resource owner of the user.
+type UserLockoutWriteModel struct {
+	eventstore.WriteModel
+
+	Locked bool
+	// LockedUntil is the zero time, if the user stays locked until unlocked by an administrator
+	LockedUntil time.Time
+
+	Password lockoutCounter
+	OTP      lockoutCounter
+	Passkey  lockoutCounter
+}
+
+func NewUserLockoutWriteModel(userID, resourceOwner string) *UserLockoutWriteModel {
+	return &UserLockoutWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   userID,
+			ResourceOwner: resourceOwner,
+		},
+	}
+}
+
+func (wm *UserLockoutWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *user.UserLockedEvent:
+			wm.Locked = true
+			wm.LockedUntil = time.Time{}
+			if e.LockedUntil != nil {
+				wm.LockedUntil = *e.LockedUntil
+			}
+		case *user.UserUnlockedEvent:
+			wm.Locked = false
+			wm.LockedUntil = time.Time{}
+			wm.Password.reset()
+			wm.OTP.reset()
+			wm.Passkey.reset()
+		case *user.HumanPasswordCheckFailedEvent:
+			wm.Password.failed(e.CreationDate())
+		case *user.HumanPasswordCheckSucceededEvent,
+			*user.HumanPasswordChangedEvent:
+			wm.Password.reset()
+		case *user.HumanOTPCheckFailedEvent:
+			wm.OTP.failed(e.CreationDate())
+		case *user.HumanOTPCheckSucceededEvent:
+			wm.OTP.reset()
+		case *user.HumanPasswordlessCheckFailedEvent:
+			wm.Passkey.failed(e.CreationDate())
+		case *user.HumanPasswordlessCheckSucceededEvent:
+			wm.Passkey.reset()
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *UserLockoutWriteModel) Query() *eventstore.SearchQueryBuilder {
+	query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(user.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(user.HumanAddedType,
+			user.HumanRegisteredType,
+			user.UserLockedType,
+			user.UserUnlockedType,
+			user.HumanPasswordCheckFailedType,
+			user.HumanPasswordCheckSucceededType,
+			user.HumanPasswordChangedType,
+			user.HumanMFAOTPCheckFailedType,
+			user.HumanMFAOTPCheckSucceededType,
+			user.HumanPasswordlessTokenCheckFailedType,
+			user.HumanPasswordlessTokenCheckSucceededType,
+			user.UserV1AddedType,
+			user.UserV1RegisteredType,
+			user.UserV1PasswordCheckFailedType,
+			user.UserV1PasswordCheckSucceededType,
+			user.UserV1PasswordChangedType,
+			user.UserV1MFAOTPCheckFailedType,
+			user.UserV1MFAOTPCheckSucceededType).
+		Builder()
+
+	if wm.ResourceOwner != "" {
+		query.ResourceOwner(wm.ResourceOwner)
+	}
+	return query
+}
+
+func (wm *UserLockoutWriteModel) counter(factor domain.LockoutFactor) *lockoutCounter {
+	switch factor {
+	case domain.LockoutFactorOTP:
+		return &wm.OTP
+	case domain.LockoutFactorPasskey:
+		return &wm.Passkey
+	default:
+		return &wm.Password
+	}
+}
+
+// LockExpired returns true if the user is locked and the lockout duration has passed
+func (wm *UserLockoutWriteModel) LockExpired(now time.Time) bool {
+	return wm.Locked && !wm.LockedUntil.IsZero() && !now.Before(wm.LockedUntil)
+}
+
+// IPLockoutWriteModel counts the failed checks of a source IP since the start of the lockout window
+type IPLockoutWriteModel struct {
+	eventstore.WriteModel
+
+	since       time.Time
+	FailedCount uint64
+}
+
+func NewIPLockoutWriteModel(ip string, since time.Time) *IPLockoutWriteModel {
+	return &IPLockoutWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID: ip,
+		},
+		since: since,
+	}
+}
+
+func (wm *IPLockoutWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		if _, ok := event.(*lockout.IPCheckFailedEvent); ok && event.CreationDate().After(wm.since) {
+			wm.FailedCount++
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *IPLockoutWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(lockout.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(lockout.IPCheckFailedEventType).
+		CreationDateAfter(wm.since).
+		Builder()
+}
@@ -0,0 +1,267 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/repository/lockout"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+func TestCommandSide_ReleaseExpiredUserLock(t *testing.T) {
+	type fields struct {
+		eventstore *eventstore.Eventstore
+	}
+	type args struct {
+		ctx           context.Context
+		userID        string
+		resourceOwner string
+	}
+	type res struct {
+		want bool
+		err  func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "userid missing, invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(t),
+			},
+			args: args{
+				ctx:           context.Background(),
+				resourceOwner: "org1",
+			},
+			res: res{
+				err: caos_errs.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "user not locked, false",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(),
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+			},
+			res: res{
+				want: false,
+			},
+		},
+		{
+			name: "user locked until unlocked by an administrator, false",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							user.NewUserLockedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+							),
+						),
+					),
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+			},
+			res: res{
+				want: false,
+			},
+		},
+		{
+			name: "lockout duration not passed, false",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							user.NewUserLockedUntilEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								time.Now().Add(time.Hour),
+							),
+						),
+					),
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+			},
+			res: res{
+				want: false,
+			},
+		},
+		{
+			name: "lockout duration passed, unlocked",
+			fields: fields{
+				eventstore: eventstoreExpect(t,
+					expectFilter(
+						eventFromEventPusher(
+							user.NewUserLockedUntilEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								time.Now().Add(-time.Minute),
+							),
+						),
+					),
+					expectPush(
+						[]*repository.Event{
+							eventFromEventPusher(
+								user.NewUserUnlockedEvent(context.Background(),
+									&user.NewAggregate("user1", "org1").Aggregate,
+								),
+							),
+						},
+					),
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+			},
+			res: res{
+				want: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore: tt.fields.eventstore,
+			}
+			got, err := r.ReleaseExpiredUserLock(tt.args.ctx, tt.args.userID, tt.args.resourceOwner)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
+
+func Test_userLockout_checkIP(t *testing.T) {
+	ctx := context.Background()
+	userAgg := &user.NewAggregate("user1", "org1").Aggregate
+	ipAgg := &lockout.NewIPAggregate(ctx, "10.0.0.1").Aggregate
+	policy := &domain.LockoutPolicy{MaxIPAttempts: 2}
+	humanAdded := expectFilter(
+		eventFromEventPusher(
+			user.NewHumanAddedEvent(ctx,
+				userAgg,
+				"username",
+				"firstname",
+				"lastname",
+				"nickname",
+				"displayname",
+				language.German,
+				domain.GenderUnspecified,
+				"email@test.ch",
+				true,
+			),
+		),
+	)
+	type args struct {
+		remoteIP string
+		policy   *domain.LockoutPolicy
+	}
+	type res struct {
+		failedEvents []eventstore.Command
+		err          func(error) bool
+	}
+	tests := []struct {
+		name       string
+		eventstore *eventstore.Eventstore
+		args       args
+		res        res
+	}{
+		{
+			name:       "no remote ip, not throttled",
+			eventstore: eventstoreExpect(t, humanAdded),
+			args: args{
+				policy: policy,
+			},
+		},
+		{
+			name:       "no maximum attempts, not throttled",
+			eventstore: eventstoreExpect(t, humanAdded),
+			args: args{
+				remoteIP: "10.0.0.1",
+				policy:   &domain.LockoutPolicy{},
+			},
+		},
+		{
+			name: "maximum attempts of ip reached, throttled",
+			eventstore: eventstoreExpect(t,
+				humanAdded,
+				expectFilter(
+					eventFromEventPusherWithCreationDateNow(lockout.NewIPCheckFailedEvent(ctx, ipAgg)),
+					eventFromEventPusherWithCreationDateNow(lockout.NewIPCheckFailedEvent(ctx, ipAgg)),
+				),
+			),
+			args: args{
+				remoteIP: "10.0.0.1",
+				policy:   policy,
+			},
+			res: res{
+				err: caos_errs.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "failed checks of ip outside the window, failed check counted",
+			eventstore: eventstoreExpect(t,
+				humanAdded,
+				expectFilter(
+					eventFromEventPusher(lockout.NewIPCheckFailedEvent(ctx, ipAgg)),
+					eventFromEventPusherWithCreationDateNow(lockout.NewIPCheckFailedEvent(ctx, ipAgg)),
+				),
+			),
+			args: args{
+				remoteIP: "10.0.0.1",
+				policy:   policy,
+			},
+			res: res{
+				failedEvents: []eventstore.Command{
+					lockout.NewIPCheckFailedEvent(ctx, ipAgg),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &userLockout{
+				eventstore: tt.eventstore,
+				now:        time.Now,
+			}
+			got, err := l.check(ctx, "user1", "org1", domain.LockoutFactorPassword, tt.args.remoteIP, tt.args.policy)
+			if tt.res.err != nil {
+				assert.True(t, tt.res.err(err), "got wrong err: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.res.failedEvents, got.failed(ctx, userAgg))
+		})
+	}
+}
@@ -57,6 +57,8 @@ func Test_customPasswordComplexityPolicy(t *testing.T) {
 							true,
 							true,
 							true,
+							false,
+							0, false, false, false, nil, nil,
 						),
 					}, nil
 				},
@@ -134,6 +136,8 @@ func Test_defaultPasswordComplexityPolicy(t *testing.T) {
 							true,
 							true,
 							true,
+							false,
+							0, false, false, false, nil, nil,
 						),
 					}, nil
 				},
@@ -202,6 +206,8 @@ func Test_passwordComplexityPolicy(t *testing.T) {
 							true,
 							true,
 							true,
+							false,
+							0, false, false, false, nil, nil,
 						),
 					}, nil
 				},
@@ -253,6 +259,8 @@ func Test_passwordComplexityPolicy(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
+								0, false, false, false, nil, nil,
 							),
 						}, nil
 					}).
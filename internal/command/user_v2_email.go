@@ -54,11 +54,10 @@ func (c *Commands) ChangeUserEmailVerified(ctx context.Context, userID, resource
 }
 
 func (c *Commands) changeUserEmailWithCode(ctx context.Context, userID, resourceOwner, email string, alg crypto.EncryptionAlgorithm, returnCode bool, urlTmpl string) (*domain.Email, error) {
-	config, err := secretGeneratorConfig(ctx, c.eventstore.Filter, domain.SecretGeneratorTypeVerifyEmailCode)
+	gen, _, err := secretGenerator(ctx, c.eventstore.Filter, domain.SecretGeneratorTypeVerifyEmailCode, alg)
 	if err != nil {
 		return nil, err
 	}
-	gen := crypto.NewEncryptionGenerator(*config, alg)
 	return c.changeUserEmailWithGenerator(ctx, userID, resourceOwner, email, gen, returnCode, urlTmpl)
 }
 
@@ -86,11 +85,10 @@ func (c *Commands) changeUserEmailWithGenerator(ctx context.Context, userID, res
 }
 
 func (c *Commands) VerifyUserEmail(ctx context.Context, userID, resourceOwner, code string, alg crypto.EncryptionAlgorithm) (*domain.ObjectDetails, error) {
-	config, err := secretGeneratorConfig(ctx, c.eventstore.Filter, domain.SecretGeneratorTypeVerifyEmailCode)
+	gen, _, err := secretGenerator(ctx, c.eventstore.Filter, domain.SecretGeneratorTypeVerifyEmailCode, alg)
 	if err != nil {
 		return nil, err
 	}
-	gen := crypto.NewEncryptionGenerator(*config, alg)
 	return c.verifyUserEmailWithGenerator(ctx, userID, resourceOwner, code, gen)
 }
 
@@ -165,9 +163,14 @@ func (c *UserEmailEvents) SetVerified(ctx context.Context) {
 }
 
 // AddGeneratedCode generates a new encrypted code and sets it to the email address.
-// When returnCode a plain text of the code will be returned from Push.
+// When returnCode a plain text of the code will be returned from Push,
+// in which case the code is hashed if the generator requires it.
 func (c *UserEmailEvents) AddGeneratedCode(ctx context.Context, gen crypto.Generator, urlTmpl string, returnCode bool) error {
-	value, plain, err := crypto.NewCode(gen)
+	newCode := crypto.NewCode
+	if returnCode {
+		newCode = crypto.NewReturnedCode
+	}
+	value, plain, err := newCode(gen)
 	if err != nil {
 		return err
 	}
@@ -48,6 +48,7 @@ func TestCommands_ChangeUserEmail(t *testing.T) {
 								&instance.NewAggregate("inst1").Aggregate,
 								domain.SecretGeneratorTypeVerifyEmailCode,
 								12, time.Minute, true, true, true, true,
+								false,
 							),
 						),
 					),
@@ -88,6 +89,7 @@ func TestCommands_ChangeUserEmail(t *testing.T) {
 								&instance.NewAggregate("inst1").Aggregate,
 								domain.SecretGeneratorTypeVerifyEmailCode,
 								12, time.Minute, true, true, true, true,
+								false,
 							),
 						),
 					),
@@ -128,6 +130,7 @@ func TestCommands_ChangeUserEmail(t *testing.T) {
 								&instance.NewAggregate("inst1").Aggregate,
 								domain.SecretGeneratorTypeVerifyEmailCode,
 								12, time.Minute, true, true, true, true,
+								false,
 							),
 						),
 					),
@@ -212,6 +215,7 @@ func TestCommands_ChangeUserEmailURLTemplate(t *testing.T) {
 								&instance.NewAggregate("inst1").Aggregate,
 								domain.SecretGeneratorTypeVerifyEmailCode,
 								12, time.Minute, true, true, true, true,
+								false,
 							),
 						),
 					),
@@ -253,6 +257,7 @@ func TestCommands_ChangeUserEmailURLTemplate(t *testing.T) {
 								&instance.NewAggregate("inst1").Aggregate,
 								domain.SecretGeneratorTypeVerifyEmailCode,
 								12, time.Minute, true, true, true, true,
+								false,
 							),
 						),
 					),
@@ -324,6 +329,7 @@ func TestCommands_ChangeUserEmailReturnCode(t *testing.T) {
 								&instance.NewAggregate("inst1").Aggregate,
 								domain.SecretGeneratorTypeVerifyEmailCode,
 								12, time.Minute, true, true, true, true,
+								false,
 							),
 						),
 					),
@@ -364,6 +370,7 @@ func TestCommands_ChangeUserEmailReturnCode(t *testing.T) {
 								&instance.NewAggregate("inst1").Aggregate,
 								domain.SecretGeneratorTypeVerifyEmailCode,
 								12, time.Minute, true, true, true, true,
+								false,
 							),
 						),
 					),
@@ -928,6 +935,7 @@ func TestCommands_VerifyUserEmail(t *testing.T) {
 								&instance.NewAggregate("inst1").Aggregate,
 								domain.SecretGeneratorTypeVerifyEmailCode,
 								12, time.Minute, true, true, true, true,
+								false,
 							),
 						),
 					),
@@ -951,6 +959,7 @@ func TestCommands_VerifyUserEmail(t *testing.T) {
 								&instance.NewAggregate("inst1").Aggregate,
 								domain.SecretGeneratorTypeVerifyEmailCode,
 								12, time.Minute, true, true, true, true,
+								false,
 							),
 						),
 					),
@@ -990,6 +999,7 @@ func TestCommands_VerifyUserEmail(t *testing.T) {
 								&instance.NewAggregate("inst1").Aggregate,
 								domain.SecretGeneratorTypeVerifyEmailCode,
 								12, time.Minute, true, true, true, true,
+								false,
 							),
 						),
 					),
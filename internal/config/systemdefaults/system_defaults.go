@@ -3,15 +3,18 @@ package systemdefaults
 import (
 	"time"
 
+	"github.com/zitadel/zitadel/internal/breach"
 	"github.com/zitadel/zitadel/internal/crypto"
 )
 
 type SystemDefaults struct {
 	SecretGenerators   SecretGenerators
+	PasswordHasher     crypto.PasswordHashConfig
 	Multifactors       MultifactorConfig
 	DomainVerification DomainVerification
 	Notifications      Notifications
 	KeyConfig          KeyConfig
+	BreachedPasswords  breach.Config
 }
 
 type SecretGenerators struct {
@@ -0,0 +1,132 @@
+package crypto
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+var _ HashAlgorithm = (*Argon2id)(nil)
+
+const (
+	argon2idID    = "argon2id"
+	argon2iID     = "argon2i"
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+
+	// the parameters of verified hashes are limited, so a hash can't exhaust the resources
+	argon2MaxTime    = 16
+	argon2MaxMemory  = 1 << 20 // KiB
+	argon2MaxThreads = 32
+)
+
+type Argon2Config struct {
+	// Time is the number of passes over the memory
+	Time uint32
+	// Memory is the memory used in KiB
+	Memory uint32
+	// Threads is the degree of parallelism
+	Threads uint8
+}
+
+// Argon2id hashes with argon2id and verifies argon2id and argon2i hashes in PHC string format
+type Argon2id struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// NewArgon2id validates the config with the limits of verified hashes
+func NewArgon2id(config Argon2Config) (*Argon2id, error) {
+	if config.Time < 1 || config.Time > argon2MaxTime {
+		return nil, errors.ThrowInvalidArgumentf(nil, "CRYPT-eeT7o", "argon2id time must be between 1 and %d", argon2MaxTime)
+	}
+	if config.Threads < 1 || config.Threads > argon2MaxThreads {
+		return nil, errors.ThrowInvalidArgumentf(nil, "CRYPT-Shoo9", "argon2id threads must be between 1 and %d", argon2MaxThreads)
+	}
+	// argon2 requires at least 8 KiB per thread
+	if config.Memory < 8*uint32(config.Threads) || config.Memory > argon2MaxMemory {
+		return nil, errors.ThrowInvalidArgumentf(nil, "CRYPT-ua9Ei", "argon2id memory must be between %d and %d KiB", 8*uint32(config.Threads), argon2MaxMemory)
+	}
+	return &Argon2id{
+		time:    config.Time,
+		memory:  config.Memory,
+		threads: config.Threads,
+	}, nil
+}
+
+func (a *Argon2id) Algorithm() string {
+	return HashNameArgon2id
+}
+
+func (a *Argon2id) Hash(value []byte) ([]byte, error) {
+	salt, err := newSalt(argon2SaltLen)
+	if err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey(value, salt, a.time, a.memory, a.threads, argon2KeyLen)
+	return []byte(fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2idID, argon2.Version, a.memory, a.time, a.threads, encodePHCBase64(salt), encodePHCBase64(key))), nil
+}
+
+func (a *Argon2id) CompareHash(hashed, value []byte) error {
+	phc, memory, time, threads, err := parseArgon2(hashed)
+	if err != nil {
+		return err
+	}
+	keyLen := uint32(len(phc.hash))
+	if phc.id == argon2iID {
+		return phc.compare(argon2.Key(value, phc.salt, time, memory, threads, keyLen))
+	}
+	return phc.compare(argon2.IDKey(value, phc.salt, time, memory, threads, keyLen))
+}
+
+func (a *Argon2id) CheckHash(hashed []byte) error {
+	_, _, _, _, err := parseArgon2(hashed)
+	return err
+}
+
+func (a *Argon2id) Identify(hashed []byte) bool {
+	return bytes.HasPrefix(hashed, []byte("$"+argon2idID+"$")) || bytes.HasPrefix(hashed, []byte("$"+argon2iID+"$"))
+}
+
+// Outdated returns true if the hash wasn't created with argon2id and the configured parameters
+func (a *Argon2id) Outdated(hashed []byte) bool {
+	phc, memory, time, threads, err := parseArgon2(hashed)
+	if err != nil {
+		return true
+	}
+	return phc.id != argon2idID || memory != a.memory || time != a.time || threads != a.threads
+}
+
+func parseArgon2(hashed []byte) (phc *phcHash, memory, time uint32, threads uint8, err error) {
+	phc, err = parsePHC(hashed)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	if phc.id != argon2idID && phc.id != argon2iID {
+		return nil, 0, 0, 0, errors.ThrowInvalidArgument(nil, "CRYPT-Ahgh4", "hash is not an argon2 hash")
+	}
+	if phc.version != "" && phc.version != fmt.Sprint(argon2.Version) {
+		return nil, 0, 0, 0, errors.ThrowInvalidArgument(nil, "CRYPT-Ep8ai", "unsupported argon2 version")
+	}
+	if err = phc.checkDigest(); err != nil {
+		return nil, 0, 0, 0, err
+	}
+	t, err := phc.uintParam("t", 1, argon2MaxTime)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	p, err := phc.uintParam("p", 1, argon2MaxThreads)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	// argon2 requires at least 8 KiB per thread
+	m, err := phc.uintParam("m", 8*p, argon2MaxMemory)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	return phc, uint32(m), uint32(t), uint8(p), nil
+}
@@ -1,11 +1,18 @@
 package crypto
 
 import (
+	"bytes"
+
 	"golang.org/x/crypto/bcrypt"
+
+	"github.com/zitadel/zitadel/internal/errors"
 )
 
 var _ HashAlgorithm = (*BCrypt)(nil)
 
+// bcryptHashLen is the length of encoded bcrypt hashes
+const bcryptHashLen = 60
+
 type BCrypt struct {
 	cost int
 }
@@ -15,7 +22,7 @@ func NewBCrypt(cost int) *BCrypt {
 }
 
 func (b *BCrypt) Algorithm() string {
-	return "bcrypt"
+	return HashNameBCrypt
 }
 
 func (b *BCrypt) Hash(value []byte) ([]byte, error) {
@@ -25,3 +32,22 @@ func (b *BCrypt) Hash(value []byte) ([]byte, error) {
 func (b *BCrypt) CompareHash(hashed, value []byte) error {
 	return bcrypt.CompareHashAndPassword(hashed, value)
 }
+
+func (b *BCrypt) CheckHash(hashed []byte) error {
+	if _, err := bcrypt.Cost(hashed); err != nil || len(hashed) != bcryptHashLen {
+		return errors.ThrowInvalidArgument(err, "CRYPT-Shai4", "invalid bcrypt hash")
+	}
+	return nil
+}
+
+func (b *BCrypt) Identify(hashed []byte) bool {
+	return bytes.HasPrefix(hashed, []byte("$2a$")) ||
+		bytes.HasPrefix(hashed, []byte("$2b$")) ||
+		bytes.HasPrefix(hashed, []byte("$2y$"))
+}
+
+// Outdated returns true if the hash wasn't created with the configured cost
+func (b *BCrypt) Outdated(hashed []byte) bool {
+	cost, err := bcrypt.Cost(hashed)
+	return err != nil || cost != b.cost
+}
@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
 	"time"
 
 	"github.com/zitadel/zitadel/internal/errors"
@@ -21,6 +22,9 @@ type GeneratorConfig struct {
 	IncludeUpperLetters bool
 	IncludeDigits       bool
 	IncludeSymbols      bool
+	// Hashed codes are stored as hash, codes which are sent are additionally
+	// kept as encrypted copy until they are sent or expired (see NewCode)
+	Hashed bool
 }
 
 type Generator interface {
@@ -80,6 +84,74 @@ func NewHashGenerator(config GeneratorConfig, algorithm HashAlgorithm) Generator
 	}
 }
 
+// CodeCopier is implemented by algorithms which keep the encrypted copies of hashed codes until they are sent
+type CodeCopier interface {
+	CodeCopies() CodeCopyStorage
+}
+
+// codeGenerator encrypts the codes or hashes them if the config requires hashed codes
+type codeGenerator struct {
+	encryptionGenerator
+	// hasher is the hash algorithm of the instance, it's nil if the algorithm doesn't support hashing
+	hasher     HashAlgorithm
+	copies     CodeCopyStorage
+	instanceID string
+	hashed     bool
+}
+
+// NewCodeGenerator returns a generator which encrypts the codes.
+// If the config requires hashed codes, they are hashed by the key of the instance.
+// Codes of both kinds are verified by VerifyCode regardless of the current config.
+func NewCodeGenerator(config GeneratorConfig, algorithm EncryptionAlgorithm, instanceID string) (Generator, error) {
+	gen := &codeGenerator{
+		encryptionGenerator: encryptionGenerator{
+			newGenerator(config),
+			algorithm,
+		},
+		instanceID: instanceID,
+		hashed:     config.Hashed,
+	}
+	if hasher, ok := algorithm.(InstanceHasher); ok {
+		gen.hasher = hasher.InstanceHasher(instanceID)
+	}
+	if copier, ok := algorithm.(CodeCopier); ok {
+		gen.copies = copier.CodeCopies()
+	}
+	if gen.hashed && (gen.hasher == nil || gen.copies == nil) {
+		return nil, errors.ThrowPreconditionFailed(nil, "CODE-Ooh4i", "Errors.SecretGenerator.HashingNotSupported")
+	}
+	return gen, nil
+}
+
+// newSentCode hashes the code and stores an encrypted copy of it,
+// which the notification handler decrypts to send the code (see DecryptCode)
+func (g *codeGenerator) newSentCode() (*CryptoValue, string, error) {
+	crypted, code, err := newCode(g, g.alg)
+	if err != nil {
+		return nil, "", err
+	}
+	hashed, err := Hash([]byte(code), g.hasher)
+	if err != nil {
+		return nil, "", err
+	}
+	var expiration time.Time
+	if g.expiry > 0 {
+		expiration = time.Now().UTC().Add(g.expiry)
+	}
+	if err = g.copies.StoreCodeCopy(g.instanceID, hashed.Crypted, crypted, expiration); err != nil {
+		return nil, "", err
+	}
+	return hashed, code, nil
+}
+
+// verificationAlg returns the algorithm which created the code
+func (g *codeGenerator) verificationAlg(cryptoCode *CryptoValue) Crypto {
+	if cryptoCode != nil && cryptoCode.CryptoType == TypeHash && g.hasher != nil {
+		return g.hasher
+	}
+	return g.alg
+}
+
 func newGenerator(config GeneratorConfig) generator {
 	var runes []rune
 	if config.IncludeLowerLetters {
@@ -101,18 +173,65 @@ func newGenerator(config GeneratorConfig) generator {
 	}
 }
 
+// NewCode generates a code which can be sent.
+// If the generator requires hashed codes, the hash is returned and an encrypted copy is kept until the code is sent.
 func NewCode(g Generator) (*CryptoValue, string, error) {
+	if gen, ok := g.(*codeGenerator); ok && gen.hashed {
+		return gen.newSentCode()
+	}
+	return newCode(g, g.Alg())
+}
+
+// NewReturnedCode generates a code which is returned to the caller and never sent.
+// It's hashed without a copy if the generator requires hashed codes, otherwise it's created as by NewCode.
+func NewReturnedCode(g Generator) (*CryptoValue, string, error) {
+	if gen, ok := g.(*codeGenerator); ok && gen.hashed {
+		return newCode(g, gen.hasher)
+	}
+	return newCode(g, g.Alg())
+}
+
+func newCode(g Generator, alg Crypto) (*CryptoValue, string, error) {
 	code, err := GenerateRandomString(g.Length(), g.Runes())
 	if err != nil {
 		return nil, "", err
 	}
-	crypto, err := Crypt([]byte(code), g.Alg())
+	crypto, err := Crypt([]byte(code), alg)
 	if err != nil {
 		return nil, "", err
 	}
 	return crypto, code, nil
 }
 
+// DecryptCode returns the plain code to send it.
+// Hashed codes are decrypted from their copy, a not found error is returned if the copy was deleted or is expired.
+func DecryptCode(instanceID string, code *CryptoValue, alg EncryptionAlgorithm) (string, error) {
+	if code == nil || code.CryptoType != TypeHash {
+		return DecryptString(code, alg)
+	}
+	copier, ok := alg.(CodeCopier)
+	if !ok || copier.CodeCopies() == nil {
+		return "", errors.ThrowPreconditionFailed(nil, "CODE-oGh3a", "Errors.SecretGenerator.HashingNotSupported")
+	}
+	crypted, err := copier.CodeCopies().ReadCodeCopy(instanceID, code.Crypted)
+	if err != nil {
+		return "", err
+	}
+	return DecryptString(crypted, alg)
+}
+
+// DeleteCodeCopy deletes the encrypted copy of a hashed code after it was sent
+func DeleteCodeCopy(instanceID string, code *CryptoValue, alg EncryptionAlgorithm) error {
+	if code == nil || code.CryptoType != TypeHash {
+		return nil
+	}
+	copier, ok := alg.(CodeCopier)
+	if !ok || copier.CodeCopies() == nil {
+		return nil
+	}
+	return copier.CodeCopies().DeleteCodeCopy(instanceID, code.Crypted)
+}
+
 func IsCodeExpired(creationDate time.Time, expiry time.Duration) bool {
 	if expiry == 0 {
 		return false
@@ -120,19 +239,32 @@ func IsCodeExpired(creationDate time.Time, expiry time.Duration) bool {
 	return creationDate.Add(expiry).Before(time.Now().UTC())
 }
 
+// VerifyCode verifies the code by the algorithm which created it,
+// so encrypted and hashed codes remain valid if the config of the generator changes
 func VerifyCode(creationDate time.Time, expiry time.Duration, cryptoCode *CryptoValue, verificationCode string, g Generator) error {
+	if gen, ok := g.(*codeGenerator); ok {
+		return VerifyCodeWithAlgorithm(creationDate, expiry, cryptoCode, verificationCode, gen.verificationAlg(cryptoCode))
+	}
 	return VerifyCodeWithAlgorithm(creationDate, expiry, cryptoCode, verificationCode, g.Alg())
 }
 
+// VerifyCodeWithAlgorithm verifies the code if the algorithm matches the crypto type of the stored code
 func VerifyCodeWithAlgorithm(creationDate time.Time, expiry time.Duration, cryptoCode *CryptoValue, verificationCode string, algorithm Crypto) error {
 	if IsCodeExpired(creationDate, expiry) {
 		return errors.ThrowPreconditionFailed(nil, "CODE-QvUQ4P", "Errors.User.Code.Expired")
 	}
-	switch alg := algorithm.(type) {
-	case EncryptionAlgorithm:
-		return verifyEncryptedCode(cryptoCode, verificationCode, alg)
-	case HashAlgorithm:
-		return verifyHashedCode(cryptoCode, verificationCode, alg)
+	if cryptoCode == nil {
+		return errors.ThrowInvalidArgument(nil, "CODE-Ahng6", "Errors.User.Code.CryptoCodeNil")
+	}
+	switch cryptoCode.CryptoType {
+	case TypeEncryption:
+		if alg, ok := algorithm.(EncryptionAlgorithm); ok {
+			return verifyEncryptedCode(cryptoCode, verificationCode, alg)
+		}
+	case TypeHash:
+		if alg, ok := algorithm.(HashAlgorithm); ok {
+			return verifyHashedCode(cryptoCode, verificationCode, alg)
+		}
 	}
 	return errors.ThrowInvalidArgument(nil, "CODE-fW2gNa", "Errors.User.Code.GeneratorAlgNotSupported")
 }
@@ -168,7 +300,7 @@ func verifyEncryptedCode(cryptoCode *CryptoValue, verificationCode string, alg E
 		return err
 	}
 
-	if code != verificationCode {
+	if subtle.ConstantTimeCompare([]byte(code), []byte(verificationCode)) != 1 {
 		return errors.ThrowInvalidArgument(nil, "CODE-woT0xc", "Errors.User.Code.Invalid")
 	}
 	return nil
@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
@@ -67,3 +68,28 @@ func createMockGenerator(t *testing.T, crypto Crypto) Generator {
 	mGenerator.EXPECT().Alg().AnyTimes().Return(crypto)
 	return mGenerator
 }
+
+// CreateMockCodeCopyStorage returns a CodeCopyStorage which keeps the copies in memory
+func CreateMockCodeCopyStorage() CodeCopyStorage {
+	return make(mockCodeCopyStorage)
+}
+
+type mockCodeCopyStorage map[string]*CryptoValue
+
+func (m mockCodeCopyStorage) StoreCodeCopy(instanceID string, hash []byte, crypted *CryptoValue, _ time.Time) error {
+	m[instanceID+string(hash)] = crypted
+	return nil
+}
+
+func (m mockCodeCopyStorage) ReadCodeCopy(instanceID string, hash []byte) (*CryptoValue, error) {
+	crypted, ok := m[instanceID+string(hash)]
+	if !ok {
+		return nil, errors.ThrowNotFound(nil, "id", "copy not found")
+	}
+	return crypted, nil
+}
+
+func (m mockCodeCopyStorage) DeleteCodeCopy(instanceID string, hash []byte) error {
+	delete(m, instanceID+string(hash))
+	return nil
+}
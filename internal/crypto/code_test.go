@@ -86,6 +86,21 @@ func TestVerifyCode(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"hashed code, encryption alg err",
+			args{
+				creationDate: time.Now(),
+				expiry:       5 * time.Minute,
+				cryptoCode: &CryptoValue{
+					CryptoType: TypeHash,
+					Algorithm:  "hash",
+					Crypted:    []byte("code"),
+				},
+				verificationCode: "code",
+				g:                createMockGenerator(t, CreateMockEncryptionAlg(gomock.NewController(t))),
+			},
+			true,
+		},
 		{
 			"encryption alg ok",
 			args{
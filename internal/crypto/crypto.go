@@ -120,6 +120,10 @@ func Hash(value []byte, alg HashAlgorithm) (*CryptoValue, error) {
 }
 
 func CompareHash(value *CryptoValue, comparer []byte, alg HashAlgorithm) error {
+	// the password hasher identifies the algorithm by the hash itself
+	if hasher, ok := alg.(*PasswordHasher); ok {
+		return hasher.CompareHash(value.Crypted, comparer)
+	}
 	if value.Algorithm != alg.Algorithm() {
 		return errors.ThrowInvalidArgument(nil, "CRYPT-HF32f", "value was hashed with a different algorithm")
 	}
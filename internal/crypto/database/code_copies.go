@@ -0,0 +1,91 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+var _ crypto.CodeCopyStorage = (*database)(nil)
+
+const (
+	CodeCopiesTable          = "system.code_copies"
+	codeCopiesInstanceIDCol  = "instance_id"
+	codeCopiesHashCol        = "hash"
+	codeCopiesCopyCol        = "copy"
+	codeCopiesExpiresAtCol   = "expires_at"
+	codeCopiesNotExpiredExpr = "(" + codeCopiesExpiresAtCol + " IS NULL OR " + codeCopiesExpiresAtCol + " > now())"
+
+	codeCopiesDeleteExpiredStmt = "DELETE FROM " + CodeCopiesTable +
+		" WHERE " + codeCopiesInstanceIDCol + " = $1 AND " + codeCopiesExpiresAtCol + " <= now()"
+	codeCopiesUpsertStmt = "INSERT INTO " + CodeCopiesTable + " (" + codeCopiesInstanceIDCol + ", " + codeCopiesHashCol + ", " + codeCopiesCopyCol + ", " + codeCopiesExpiresAtCol + ")" +
+		" VALUES ($1, $2, $3, $4)" +
+		" ON CONFLICT (" + codeCopiesInstanceIDCol + ", " + codeCopiesHashCol + ") DO UPDATE SET " +
+		codeCopiesCopyCol + " = EXCLUDED." + codeCopiesCopyCol + ", " + codeCopiesExpiresAtCol + " = EXCLUDED." + codeCopiesExpiresAtCol
+)
+
+func (d *database) StoreCodeCopy(instanceID string, hash []byte, crypted *crypto.CryptoValue, expiration time.Time) error {
+	tx, err := d.client.Begin()
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to store code copy")
+	}
+	if _, err = tx.Exec(codeCopiesDeleteExpiredStmt, instanceID); err != nil {
+		tx.Rollback()
+		return caos_errs.ThrowInternal(err, "", "unable to delete expired code copies")
+	}
+	expiresAt := sql.NullTime{Time: expiration, Valid: !expiration.IsZero()}
+	if _, err = tx.Exec(codeCopiesUpsertStmt, instanceID, hash, crypted, expiresAt); err != nil {
+		tx.Rollback()
+		return caos_errs.ThrowInternal(err, "", "unable to store code copy")
+	}
+	if err = tx.Commit(); err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to store code copy")
+	}
+	return nil
+}
+
+func (d *database) ReadCodeCopy(instanceID string, hash []byte) (*crypto.CryptoValue, error) {
+	stmt, args, err := sq.Select(codeCopiesCopyCol).
+		From(CodeCopiesTable).
+		Where(sq.Eq{
+			codeCopiesInstanceIDCol: instanceID,
+			codeCopiesHashCol:       hash,
+		}).
+		Where(codeCopiesNotExpiredExpr).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, caos_errs.ThrowInternal(err, "", "unable to read code copy")
+	}
+	crypted := new(crypto.CryptoValue)
+	err = d.client.QueryRow(stmt, args...).Scan(crypted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, caos_errs.ThrowNotFound(err, "", "code copy not found")
+	}
+	if err != nil {
+		return nil, caos_errs.ThrowInternal(err, "", "unable to read code copy")
+	}
+	return crypted, nil
+}
+
+func (d *database) DeleteCodeCopy(instanceID string, hash []byte) error {
+	stmt, args, err := sq.Delete(CodeCopiesTable).
+		Where(sq.Eq{
+			codeCopiesInstanceIDCol: instanceID,
+			codeCopiesHashCol:       hash,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to delete code copy")
+	}
+	if _, err = d.client.Exec(stmt, args...); err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to delete code copy")
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+func Test_database_StoreCodeCopy(t *testing.T) {
+	expiration := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		client     db
+		expiration time.Time
+		err        func(error) bool
+	}{
+		{
+			"delete expired fails, error",
+			dbMock(t,
+				expectBegin(nil),
+				expectExec(codeCopiesDeleteExpiredStmt, sql.ErrConnDone, "instance1"),
+				expectRollback(nil),
+			),
+			expiration,
+			func(err error) bool {
+				return errors.Is(err, sql.ErrConnDone)
+			},
+		},
+		{
+			"stored with expiration",
+			dbMock(t,
+				expectBegin(nil),
+				expectExec(codeCopiesDeleteExpiredStmt, nil, "instance1"),
+				expectExec(codeCopiesUpsertStmt, nil, "instance1", []byte("hash"), sqlmock.AnyArg(), expiration),
+				expectCommit(nil),
+			),
+			expiration,
+			nil,
+		},
+		{
+			"stored without expiration",
+			dbMock(t,
+				expectBegin(nil),
+				expectExec(codeCopiesDeleteExpiredStmt, nil, "instance1"),
+				expectExec(codeCopiesUpsertStmt, nil, "instance1", []byte("hash"), sqlmock.AnyArg(), nil),
+				expectCommit(nil),
+			),
+			time.Time{},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &database{client: tt.client.db}
+			err := d.StoreCodeCopy("instance1", []byte("hash"), &crypto.CryptoValue{CryptoType: crypto.TypeEncryption, Crypted: []byte("code")}, tt.expiration)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else if !tt.err(err) {
+				t.Errorf("got wrong err: %v", err)
+			}
+			if err := tt.client.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_database_ReadCodeCopy(t *testing.T) {
+	type res struct {
+		copy *crypto.CryptoValue
+		err  func(error) bool
+	}
+	tests := []struct {
+		name   string
+		client db
+		res    res
+	}{
+		{
+			"copy not found err",
+			dbMock(t, expectQuery(
+				"SELECT copy FROM system.code_copies WHERE hash = $1 AND instance_id = $2 AND (expires_at IS NULL OR expires_at > now())",
+				nil,
+				nil,
+				[]byte("hash"), "instance1",
+			)),
+			res{
+				err: caos_errs.IsNotFound,
+			},
+		},
+		{
+			"copy ok",
+			dbMock(t, expectQuery(
+				"SELECT copy FROM system.code_copies WHERE hash = $1 AND instance_id = $2 AND (expires_at IS NULL OR expires_at > now())",
+				[]string{"copy"},
+				[][]driver.Value{
+					{
+						[]byte(`{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"Y29kZQ=="}`),
+					},
+				},
+				[]byte("hash"), "instance1",
+			)),
+			res{
+				copy: &crypto.CryptoValue{
+					CryptoType: crypto.TypeEncryption,
+					Algorithm:  "enc",
+					KeyID:      "id",
+					Crypted:    []byte("code"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &database{client: tt.client.db}
+			got, err := d.ReadCodeCopy("instance1", []byte("hash"))
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.res.copy, got)
+			} else if !tt.res.err(err) {
+				t.Errorf("got wrong err: %v", err)
+			}
+			if err := tt.client.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_database_DeleteCodeCopy(t *testing.T) {
+	client := dbMock(t, expectExec("DELETE FROM system.code_copies WHERE hash = $1 AND instance_id = $2", nil, []byte("hash"), "instance1"))
+	d := &database{client: client.db}
+	assert.NoError(t, d.DeleteCodeCopy("instance1", []byte("hash")))
+	if err := client.mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
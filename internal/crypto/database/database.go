@@ -2,6 +2,8 @@ package database
 
 import (
 	"database/sql"
+	"encoding/base64"
+	"errors"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -34,6 +36,32 @@ func NewKeyStorage(client *sql.DB, masterKey string) (*database, error) {
 	}, nil
 }
 
+// NewKMSKeyStorage returns a key storage which wraps the encryption keys
+// by the key management service (KMS) instead of the masterkey
+func NewKMSKeyStorage(client *sql.DB, wrapper crypto.KeyWrapper) *database {
+	return &database{
+		client: client,
+		encrypt: func(key, _ string) (string, error) {
+			wrapped, err := wrapper.WrapKey([]byte(key))
+			if err != nil {
+				return "", err
+			}
+			return base64.URLEncoding.EncodeToString(wrapped), nil
+		},
+		decrypt: func(encryptedKey, _ string) (string, error) {
+			wrapped, err := base64.URLEncoding.DecodeString(encryptedKey)
+			if err != nil {
+				return "", err
+			}
+			key, err := wrapper.UnwrapKey(wrapped)
+			if err != nil {
+				return "", err
+			}
+			return string(key), nil
+		},
+	}
+}
+
 func (d *database) ReadKeys() (crypto.Keys, error) {
 	keys := make(map[string]string)
 	stmt, args, err := sq.Select(encryptionKeysIDCol, encryptionKeysKeyCol).
@@ -82,6 +110,9 @@ func (d *database) ReadKey(id string) (*crypto.Key, error) {
 	}
 	var encryptionKey string
 	err = row.Scan(&encryptionKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, caos_errs.ThrowNotFound(err, "", "key not found")
+	}
 	if err != nil {
 		return nil, caos_errs.ThrowInternal(err, "", "unable to read key")
 	}
@@ -125,6 +156,37 @@ func (d *database) CreateKeys(keys ...*crypto.Key) error {
 	return nil
 }
 
+func (d *database) UpdateKeys(keys ...*crypto.Key) error {
+	tx, err := d.client.Begin()
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to update keys")
+	}
+	for _, key := range keys {
+		encryptionKey, err := d.encrypt(key.Value, d.masterKey)
+		if err != nil {
+			tx.Rollback()
+			return caos_errs.ThrowInternal(err, "", "unable to encrypt key")
+		}
+		stmt, args, err := sq.Update(EncryptionKeysTable).
+			Set(encryptionKeysKeyCol, encryptionKey).
+			Where(sq.Eq{encryptionKeysIDCol: key.ID}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			tx.Rollback()
+			return caos_errs.ThrowInternal(err, "", "unable to update keys")
+		}
+		if _, err = tx.Exec(stmt, args...); err != nil {
+			tx.Rollback()
+			return caos_errs.ThrowInternal(err, "", "unable to update keys")
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to update keys")
+	}
+	return nil
+}
+
 func checkMasterKeyLength(masterKey string) error {
 	if length := len([]byte(masterKey)); length != 32 {
 		return caos_errs.ThrowInternalf(nil, "", "masterkey must be 32 bytes, but is %d", length)
@@ -186,7 +186,7 @@ func Test_database_ReadKey(t *testing.T) {
 				id: "id1",
 			},
 			res{
-				err: caos_errs.IsInternal,
+				err: caos_errs.IsNotFound,
 			},
 		},
 		{
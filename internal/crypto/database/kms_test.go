@@ -0,0 +1,60 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+)
+
+// prefixWrapper wraps the keys by prefixing them
+type prefixWrapper struct{}
+
+func (prefixWrapper) WrapKey(key []byte) ([]byte, error) {
+	return append([]byte("wrapped:"), key...), nil
+}
+
+func (prefixWrapper) UnwrapKey(wrapped []byte) ([]byte, error) {
+	return wrapped[len("wrapped:"):], nil
+}
+
+func wrappedKey(key string) string {
+	return base64.URLEncoding.EncodeToString([]byte("wrapped:" + key))
+}
+
+func Test_kmsKeyStorage_ReadKey(t *testing.T) {
+	client := dbMock(t, expectQuery(
+		"SELECT key FROM system.encryption_keys WHERE id = $1",
+		[]string{"key"},
+		[][]driver.Value{
+			{wrappedKey("key")},
+		},
+		"id1",
+	))
+	key, err := NewKMSKeyStorage(client.db, prefixWrapper{}).ReadKey("id1")
+	assert.NoError(t, err)
+	assert.Equal(t, &crypto.Key{ID: "id1", Value: "key"}, key)
+	if err := client.mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_kmsKeyStorage_UpdateKeys(t *testing.T) {
+	client := dbMock(t,
+		expectBegin(nil),
+		expectExec("UPDATE system.encryption_keys SET key = $1 WHERE id = $2", nil, wrappedKey("key1"), "id1"),
+		expectExec("UPDATE system.encryption_keys SET key = $1 WHERE id = $2", nil, wrappedKey("key2"), "id2"),
+		expectCommit(nil),
+	)
+	err := NewKMSKeyStorage(client.db, prefixWrapper{}).UpdateKeys(
+		&crypto.Key{ID: "id1", Value: "key1"},
+		&crypto.Key{ID: "id2", Value: "key2"},
+	)
+	assert.NoError(t, err)
+	if err := client.mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
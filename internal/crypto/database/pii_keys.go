@@ -0,0 +1,113 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	z_db "github.com/zitadel/zitadel/internal/database"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+var _ crypto.PIIKeyStorage = (*database)(nil)
+
+const (
+	PIIKeysTable            = "system.pii_keys"
+	piiKeysInstanceIDCol    = "instance_id"
+	piiKeysAggregateTypeCol = "aggregate_type"
+	piiKeysAggregateIDCol   = "aggregate_id"
+	piiKeysKeyCol           = "key"
+
+	// the existing key is kept, so concurrently created keys resolve to the first one
+	piiKeysEnsureStmt = "INSERT INTO " + PIIKeysTable + " (" + piiKeysInstanceIDCol + ", " + piiKeysAggregateTypeCol + ", " + piiKeysAggregateIDCol + ", " + piiKeysKeyCol + ")" +
+		" VALUES ($1, $2, $3, $4)" +
+		" ON CONFLICT (" + piiKeysInstanceIDCol + ", " + piiKeysAggregateIDCol + ") DO UPDATE SET " + piiKeysKeyCol + " = pii_keys." + piiKeysKeyCol +
+		" RETURNING " + piiKeysKeyCol
+	piiKeysDeleteErasedStmt = "DELETE FROM " + PIIKeysTable + " k" +
+		" WHERE EXISTS (SELECT 1 FROM eventstore.events e" +
+		" WHERE e.instance_id = k." + piiKeysInstanceIDCol +
+		" AND e.aggregate_type = k." + piiKeysAggregateTypeCol +
+		" AND e.aggregate_id = k." + piiKeysAggregateIDCol +
+		" AND e.event_type = ANY($1))"
+)
+
+func (d *database) ReadPIIKey(instanceID, aggregateID string) (string, error) {
+	stmt, args, err := sq.Select(piiKeysKeyCol).
+		From(PIIKeysTable).
+		Where(sq.Eq{
+			piiKeysInstanceIDCol:  instanceID,
+			piiKeysAggregateIDCol: aggregateID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return "", caos_errs.ThrowInternal(err, "", "unable to read pii key")
+	}
+	var encryptedKey string
+	err = d.client.QueryRow(stmt, args...).Scan(&encryptedKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", caos_errs.ThrowNotFound(err, "", "pii key not found")
+	}
+	if err != nil {
+		return "", caos_errs.ThrowInternal(err, "", "unable to read pii key")
+	}
+	key, err := d.decrypt(encryptedKey, d.masterKey)
+	if err != nil {
+		return "", caos_errs.ThrowInternal(err, "", "unable to decrypt pii key")
+	}
+	return key, nil
+}
+
+func (d *database) EnsurePIIKey(instanceID, aggregateType, aggregateID, key string) (string, error) {
+	encryptedKey, err := d.encrypt(key, d.masterKey)
+	if err != nil {
+		return "", caos_errs.ThrowInternal(err, "", "unable to encrypt pii key")
+	}
+	var storedKey string
+	err = d.client.QueryRow(piiKeysEnsureStmt, instanceID, aggregateType, aggregateID, encryptedKey).Scan(&storedKey)
+	if err != nil {
+		return "", caos_errs.ThrowInternal(err, "", "unable to store pii key")
+	}
+	if storedKey == encryptedKey {
+		return key, nil
+	}
+	key, err = d.decrypt(storedKey, d.masterKey)
+	if err != nil {
+		return "", caos_errs.ThrowInternal(err, "", "unable to decrypt pii key")
+	}
+	return key, nil
+}
+
+func (d *database) DeletePIIKey(instanceID, aggregateID string) error {
+	stmt, args, err := sq.Delete(PIIKeysTable).
+		Where(sq.Eq{
+			piiKeysInstanceIDCol:  instanceID,
+			piiKeysAggregateIDCol: aggregateID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to delete pii key")
+	}
+	if _, err = d.client.Exec(stmt, args...); err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to delete pii key")
+	}
+	return nil
+}
+
+func (d *database) DeleteErasedPIIKeys(erasureTypes []string) (int64, error) {
+	if len(erasureTypes) == 0 {
+		return 0, nil
+	}
+	result, err := d.client.Exec(piiKeysDeleteErasedStmt, z_db.StringArray(erasureTypes))
+	if err != nil {
+		return 0, caos_errs.ThrowInternal(err, "", "unable to delete erased pii keys")
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, caos_errs.ThrowInternal(err, "", "unable to delete erased pii keys")
+	}
+	return deleted, nil
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	z_db "github.com/zitadel/zitadel/internal/database"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+func Test_database_ReadPIIKey(t *testing.T) {
+	type res struct {
+		key string
+		err func(error) bool
+	}
+	tests := []struct {
+		name   string
+		client db
+		res    res
+	}{
+		{
+			"query fails, error",
+			dbMock(t, expectQueryErr("SELECT key FROM system.pii_keys WHERE aggregate_id = $1 AND instance_id = $2", sql.ErrConnDone, "user1", "instance1")),
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, sql.ErrConnDone)
+				},
+			},
+		},
+		{
+			"key not found err",
+			dbMock(t, expectQuery(
+				"SELECT key FROM system.pii_keys WHERE aggregate_id = $1 AND instance_id = $2",
+				nil,
+				nil,
+				"user1", "instance1",
+			)),
+			res{
+				err: caos_errs.IsNotFound,
+			},
+		},
+		{
+			"key ok",
+			dbMock(t, expectQuery(
+				"SELECT key FROM system.pii_keys WHERE aggregate_id = $1 AND instance_id = $2",
+				[]string{"key"},
+				[][]driver.Value{
+					{
+						"encrypted:key1",
+					},
+				},
+				"user1", "instance1",
+			)),
+			res{
+				key: "key1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &database{
+				client:    tt.client.db,
+				masterKey: "masterKey",
+				decrypt:   testDecrypt,
+			}
+			got, err := d.ReadPIIKey("instance1", "user1")
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.res.key, got)
+			} else if !tt.res.err(err) {
+				t.Errorf("got wrong err: %v", err)
+			}
+			if err := tt.client.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_database_EnsurePIIKey(t *testing.T) {
+	type res struct {
+		key string
+		err func(error) bool
+	}
+	tests := []struct {
+		name   string
+		client db
+		res    res
+	}{
+		{
+			"insert fails, error",
+			dbMock(t, expectQueryErr(piiKeysEnsureStmt, sql.ErrConnDone, "instance1", "user", "user1", "encrypted:key1")),
+			res{
+				err: func(err error) bool {
+					return errors.Is(err, sql.ErrConnDone)
+				},
+			},
+		},
+		{
+			"new key",
+			dbMock(t, expectQuery(
+				piiKeysEnsureStmt,
+				[]string{"key"},
+				[][]driver.Value{
+					{
+						"encrypted:key1",
+					},
+				},
+				"instance1", "user", "user1", "encrypted:key1",
+			)),
+			res{
+				key: "key1",
+			},
+		},
+		{
+			"existing key",
+			dbMock(t, expectQuery(
+				piiKeysEnsureStmt,
+				[]string{"key"},
+				[][]driver.Value{
+					{
+						"encrypted:existing",
+					},
+				},
+				"instance1", "user", "user1", "encrypted:key1",
+			)),
+			res{
+				key: "existing",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &database{
+				client:    tt.client.db,
+				masterKey: "masterKey",
+				encrypt:   testEncrypt,
+				decrypt:   testDecrypt,
+			}
+			got, err := d.EnsurePIIKey("instance1", "user", "user1", "key1")
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.res.key, got)
+			} else if !tt.res.err(err) {
+				t.Errorf("got wrong err: %v", err)
+			}
+			if err := tt.client.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_database_DeletePIIKey(t *testing.T) {
+	client := dbMock(t, expectExec("DELETE FROM system.pii_keys WHERE aggregate_id = $1 AND instance_id = $2", nil, "user1", "instance1"))
+	d := &database{client: client.db}
+	assert.NoError(t, d.DeletePIIKey("instance1", "user1"))
+	if err := client.mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_database_DeleteErasedPIIKeys(t *testing.T) {
+	t.Run("no erasure types", func(t *testing.T) {
+		client := dbMock(t)
+		d := &database{client: client.db}
+		deleted, err := d.DeleteErasedPIIKeys(nil)
+		assert.NoError(t, err)
+		assert.Zero(t, deleted)
+		if err := client.mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("deleted", func(t *testing.T) {
+		client := dbMock(t, expectExec(piiKeysDeleteErasedStmt, nil, z_db.StringArray{"user.removed"}))
+		d := &database{client: client.db}
+		deleted, err := d.DeleteErasedPIIKeys([]string{"user.removed"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		if err := client.mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func testEncrypt(key, _ string) (string, error) {
+	return "encrypted:" + key, nil
+}
+
+func testDecrypt(encryptedKey, _ string) (string, error) {
+	return encryptedKey[len("encrypted:"):], nil
+}
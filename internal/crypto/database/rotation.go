@@ -0,0 +1,204 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	z_db "github.com/zitadel/zitadel/internal/database"
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+var _ crypto.KeyRotationStorage = (*database)(nil)
+
+const (
+	EncryptionKeyRotationsTable          = "system.encryption_key_rotations"
+	keyRotationsPurposeCol               = "purpose"
+	keyRotationsKeyIDCol                 = "key_id"
+	keyRotationsPreviousKeyIDsCol        = "previous_key_ids"
+	keyRotationsRotatedAtCol             = "rotated_at"
+	keyRotationsConfirmedAtCol           = "confirmed_at"
+	keyRotationsRemainingCol             = "remaining"
+	keyRotationsCheckedAtCol             = "checked_at"
+	keyRotationsRemovePreviousKeyIDsStmt = "UPDATE " + EncryptionKeyRotationsTable + " SET " + keyRotationsPreviousKeyIDsCol + " = array_remove(" + keyRotationsPreviousKeyIDsCol + ", $1)"
+
+	EncryptionKeyNodesTable = "system.encryption_key_nodes"
+	keyNodesNodeIDCol       = "node_id"
+	keyNodesPurposeCol      = "purpose"
+	keyNodesKeyIDCol        = "key_id"
+	keyNodesConfirmedAtCol  = "confirmed_at"
+	keyNodesConfirmStmt     = "INSERT INTO " + EncryptionKeyNodesTable + " (" + keyNodesNodeIDCol + ", " + keyNodesPurposeCol + ", " + keyNodesKeyIDCol + ", " + keyNodesConfirmedAtCol + ") VALUES ($1, $2, $3, $4)" +
+		" ON CONFLICT (" + keyNodesNodeIDCol + ", " + keyNodesPurposeCol + ") DO UPDATE SET " + keyNodesKeyIDCol + " = EXCLUDED." + keyNodesKeyIDCol + ", " + keyNodesConfirmedAtCol + " = EXCLUDED." + keyNodesConfirmedAtCol
+	keyNodesDeleteExpiredStmt = "DELETE FROM " + EncryptionKeyNodesTable + " WHERE " + keyNodesConfirmedAtCol + " < $1"
+	keyNodesUnconfirmedStmt   = "SELECT count(*) FROM " + EncryptionKeyNodesTable + " WHERE " + keyNodesPurposeCol + " = $1 AND " + keyNodesKeyIDCol + " <> $2 AND " + keyNodesConfirmedAtCol + " >= $3"
+)
+
+func (d *database) ReadKeyRotations() (crypto.KeyRotations, error) {
+	stmt, args, err := sq.Select(
+		keyRotationsPurposeCol,
+		keyRotationsKeyIDCol,
+		keyRotationsPreviousKeyIDsCol,
+		keyRotationsRotatedAtCol,
+		keyRotationsConfirmedAtCol,
+		keyRotationsRemainingCol,
+		keyRotationsCheckedAtCol,
+	).
+		From(EncryptionKeyRotationsTable).
+		ToSql()
+	if err != nil {
+		return nil, caos_errs.ThrowInternal(err, "", "unable to read key rotations")
+	}
+	rows, err := d.client.Query(stmt, args...)
+	if err != nil {
+		return nil, caos_errs.ThrowInternal(err, "", "unable to read key rotations")
+	}
+	defer rows.Close()
+	rotations := make(crypto.KeyRotations)
+	for rows.Next() {
+		var (
+			rotation    = new(crypto.KeyRotation)
+			previous    z_db.StringArray
+			confirmedAt sql.NullTime
+			remaining   sql.NullInt64
+			checkedAt   sql.NullTime
+		)
+		err = rows.Scan(&rotation.Purpose, &rotation.KeyID, &previous, &rotation.RotatedAt, &confirmedAt, &remaining, &checkedAt)
+		if err != nil {
+			return nil, caos_errs.ThrowInternal(err, "", "unable to read key rotations")
+		}
+		rotation.PreviousKeyIDs = previous
+		rotation.ConfirmedAt = confirmedAt.Time
+		rotation.Remaining = uint64(remaining.Int64)
+		rotation.CheckedAt = checkedAt.Time
+		rotations[rotation.Purpose] = rotation
+	}
+	if err = rows.Err(); err != nil {
+		return nil, caos_errs.ThrowInternal(err, "", "unable to read key rotations")
+	}
+	return rotations, nil
+}
+
+func (d *database) SetKeyRotation(rotation *crypto.KeyRotation) error {
+	stmt, args, err := sq.Insert(EncryptionKeyRotationsTable).
+		Columns(
+			keyRotationsPurposeCol,
+			keyRotationsKeyIDCol,
+			keyRotationsPreviousKeyIDsCol,
+			keyRotationsRotatedAtCol,
+			keyRotationsConfirmedAtCol,
+			keyRotationsRemainingCol,
+			keyRotationsCheckedAtCol,
+		).
+		Values(rotation.Purpose, rotation.KeyID, z_db.StringArray(rotation.PreviousKeyIDs), rotation.RotatedAt, nil, nil, nil).
+		Suffix("ON CONFLICT (" + keyRotationsPurposeCol + ") DO UPDATE SET " +
+			keyRotationsKeyIDCol + " = EXCLUDED." + keyRotationsKeyIDCol + ", " +
+			keyRotationsPreviousKeyIDsCol + " = EXCLUDED." + keyRotationsPreviousKeyIDsCol + ", " +
+			keyRotationsRotatedAtCol + " = EXCLUDED." + keyRotationsRotatedAtCol + ", " +
+			keyRotationsConfirmedAtCol + " = NULL, " +
+			keyRotationsRemainingCol + " = NULL, " +
+			keyRotationsCheckedAtCol + " = NULL").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to set key rotation")
+	}
+	if _, err = d.client.Exec(stmt, args...); err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to set key rotation")
+	}
+	return nil
+}
+
+func (d *database) SetKeyRotationProgress(purpose crypto.KeyPurpose, remaining uint64) error {
+	stmt, args, err := sq.Update(EncryptionKeyRotationsTable).
+		Set(keyRotationsRemainingCol, remaining).
+		Set(keyRotationsCheckedAtCol, time.Now()).
+		Where(sq.Eq{keyRotationsPurposeCol: purpose}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to set key rotation progress")
+	}
+	if _, err = d.client.Exec(stmt, args...); err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to set key rotation progress")
+	}
+	return nil
+}
+
+func (d *database) ConfirmKeyRotations(nodeID string, keyIDs map[crypto.KeyPurpose]string, expiredBefore time.Time) error {
+	tx, err := d.client.Begin()
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to confirm key rotations")
+	}
+	confirmedAt := time.Now()
+	for _, purpose := range crypto.KeyPurposes {
+		keyID, ok := keyIDs[purpose]
+		if !ok {
+			continue
+		}
+		if _, err = tx.Exec(keyNodesConfirmStmt, nodeID, purpose, keyID, confirmedAt); err != nil {
+			tx.Rollback()
+			return caos_errs.ThrowInternal(err, "", "unable to confirm key rotations")
+		}
+	}
+	if _, err = tx.Exec(keyNodesDeleteExpiredStmt, expiredBefore); err != nil {
+		tx.Rollback()
+		return caos_errs.ThrowInternal(err, "", "unable to confirm key rotations")
+	}
+	if err = tx.Commit(); err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to confirm key rotations")
+	}
+	return nil
+}
+
+func (d *database) UnconfirmedKeyRotationNodes(purpose crypto.KeyPurpose, keyID string, since time.Time) (uint64, error) {
+	var count uint64
+	if err := d.client.QueryRow(keyNodesUnconfirmedStmt, purpose, keyID, since).Scan(&count); err != nil {
+		return 0, caos_errs.ThrowInternal(err, "", "unable to read key rotation confirmations")
+	}
+	return count, nil
+}
+
+func (d *database) SetKeyRotationConfirmed(purpose crypto.KeyPurpose) error {
+	stmt, args, err := sq.Update(EncryptionKeyRotationsTable).
+		Set(keyRotationsConfirmedAtCol, time.Now()).
+		Where(sq.Eq{keyRotationsPurposeCol: purpose}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to confirm key rotation")
+	}
+	if _, err = d.client.Exec(stmt, args...); err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to confirm key rotation")
+	}
+	return nil
+}
+
+func (d *database) DeleteRotatedKeys(ids ...string) error {
+	stmt, args, err := sq.Delete(EncryptionKeysTable).
+		Where(sq.Eq{encryptionKeysIDCol: ids}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to delete keys")
+	}
+	tx, err := d.client.Begin()
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to delete keys")
+	}
+	if _, err = tx.Exec(stmt, args...); err != nil {
+		tx.Rollback()
+		return caos_errs.ThrowInternal(err, "", "unable to delete keys")
+	}
+	for _, id := range ids {
+		if _, err = tx.Exec(keyRotationsRemovePreviousKeyIDsStmt, id); err != nil {
+			tx.Rollback()
+			return caos_errs.ThrowInternal(err, "", "unable to delete keys")
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return caos_errs.ThrowInternal(err, "", "unable to delete keys")
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+)
+
+func Test_database_ReadKeyRotations(t *testing.T) {
+	rotatedAt := time.Now().Add(-time.Hour)
+	confirmedAt := rotatedAt.Add(time.Minute)
+	checkedAt := time.Now()
+	tests := []struct {
+		name   string
+		client db
+		want   crypto.KeyRotations
+		err    func(error) bool
+	}{
+		{
+			name:   "query fails, error",
+			client: dbMock(t, expectQueryErr("SELECT purpose, key_id, previous_key_ids, rotated_at, confirmed_at, remaining, checked_at FROM system.encryption_key_rotations", sql.ErrConnDone)),
+			err: func(err error) bool {
+				return errors.Is(err, sql.ErrConnDone)
+			},
+		},
+		{
+			name: "rotations ok",
+			client: dbMock(t, expectQuery(
+				"SELECT purpose, key_id, previous_key_ids, rotated_at, confirmed_at, remaining, checked_at FROM system.encryption_key_rotations",
+				[]string{"purpose", "key_id", "previous_key_ids", "rotated_at", "confirmed_at", "remaining", "checked_at"},
+				[][]driver.Value{
+					{"otp", "otpKey2", "{otpKey}", rotatedAt, nil, nil, nil},
+					{"smtp", "smtpKey2", "{smtpKey,smtpKey1}", rotatedAt, confirmedAt, 0, checkedAt},
+				},
+			)),
+			want: crypto.KeyRotations{
+				crypto.KeyPurposeOTP: {
+					Purpose:        crypto.KeyPurposeOTP,
+					KeyID:          "otpKey2",
+					PreviousKeyIDs: []string{"otpKey"},
+					RotatedAt:      rotatedAt,
+				},
+				crypto.KeyPurposeSMTP: {
+					Purpose:        crypto.KeyPurposeSMTP,
+					KeyID:          "smtpKey2",
+					PreviousKeyIDs: []string{"smtpKey", "smtpKey1"},
+					RotatedAt:      rotatedAt,
+					ConfirmedAt:    confirmedAt,
+					CheckedAt:      checkedAt,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &database{
+				client: tt.client.db,
+			}
+			got, err := d.ReadKeyRotations()
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			} else if !tt.err(err) {
+				t.Errorf("got wrong err: %v", err)
+			}
+			if err := tt.client.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_database_SetKeyRotation(t *testing.T) {
+	rotatedAt := time.Now()
+	client := dbMock(t,
+		expectExec("INSERT INTO system.encryption_key_rotations (purpose,key_id,previous_key_ids,rotated_at,confirmed_at,remaining,checked_at) VALUES ($1,$2,$3,$4,$5,$6,$7) ON CONFLICT (purpose) DO UPDATE SET key_id = EXCLUDED.key_id, previous_key_ids = EXCLUDED.previous_key_ids, rotated_at = EXCLUDED.rotated_at, confirmed_at = NULL, remaining = NULL, checked_at = NULL",
+			nil,
+			crypto.KeyPurposeOTP, "otpKey2", "{otpKey}", rotatedAt, nil, nil, nil,
+		),
+	)
+	d := &database{
+		client: client.db,
+	}
+	err := d.SetKeyRotation(&crypto.KeyRotation{
+		Purpose:        crypto.KeyPurposeOTP,
+		KeyID:          "otpKey2",
+		PreviousKeyIDs: []string{"otpKey"},
+		RotatedAt:      rotatedAt,
+	})
+	assert.NoError(t, err)
+	if err := client.mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_database_ConfirmKeyRotations(t *testing.T) {
+	expiredBefore := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name   string
+		client db
+		err    bool
+	}{
+		{
+			name: "confirm fails, rollback",
+			client: dbMock(t,
+				expectBegin(nil),
+				expectExec(keyNodesConfirmStmt, sql.ErrConnDone, "node1", crypto.KeyPurposeOTP, "otpKey2", sqlmock.AnyArg()),
+				expectRollback(nil),
+			),
+			err: true,
+		},
+		{
+			name: "confirm ok",
+			client: dbMock(t,
+				expectBegin(nil),
+				expectExec(keyNodesConfirmStmt, nil, "node1", crypto.KeyPurposeOTP, "otpKey2", sqlmock.AnyArg()),
+				expectExec(keyNodesConfirmStmt, nil, "node1", crypto.KeyPurposeSMTP, "smtpKey", sqlmock.AnyArg()),
+				expectExec(keyNodesDeleteExpiredStmt, nil, expiredBefore),
+				expectCommit(nil),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &database{
+				client: tt.client.db,
+			}
+			err := d.ConfirmKeyRotations("node1", map[crypto.KeyPurpose]string{
+				crypto.KeyPurposeSMTP: "smtpKey",
+				crypto.KeyPurposeOTP:  "otpKey2",
+			}, expiredBefore)
+			assert.Equal(t, tt.err, err != nil, "unexpected error: %v", err)
+			if err := tt.client.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_database_UnconfirmedKeyRotationNodes(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	client := dbMock(t,
+		expectQuery(keyNodesUnconfirmedStmt, []string{"nodes"}, [][]driver.Value{{2}}, crypto.KeyPurposeOTP, "otpKey2", since),
+	)
+	d := &database{
+		client: client.db,
+	}
+	got, err := d.UnconfirmedKeyRotationNodes(crypto.KeyPurposeOTP, "otpKey2", since)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), got)
+	if err := client.mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_database_SetKeyRotationConfirmed(t *testing.T) {
+	client := dbMock(t,
+		expectExec("UPDATE system.encryption_key_rotations SET confirmed_at = $1 WHERE purpose = $2", nil, sqlmock.AnyArg(), crypto.KeyPurposeOTP),
+	)
+	d := &database{
+		client: client.db,
+	}
+	assert.NoError(t, d.SetKeyRotationConfirmed(crypto.KeyPurposeOTP))
+	if err := client.mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_database_DeleteRotatedKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		client db
+		err    bool
+	}{
+		{
+			name: "update fails, rollback",
+			client: dbMock(t,
+				expectBegin(nil),
+				expectExec("DELETE FROM system.encryption_keys WHERE id IN ($1,$2)", nil, "id1", "id2"),
+				expectExec(keyRotationsRemovePreviousKeyIDsStmt, sql.ErrConnDone, "id1"),
+				expectRollback(nil),
+			),
+			err: true,
+		},
+		{
+			name: "delete ok",
+			client: dbMock(t,
+				expectBegin(nil),
+				expectExec("DELETE FROM system.encryption_keys WHERE id IN ($1,$2)", nil, "id1", "id2"),
+				expectExec(keyRotationsRemovePreviousKeyIDsStmt, nil, "id1"),
+				expectExec(keyRotationsRemovePreviousKeyIDsStmt, nil, "id2"),
+				expectCommit(nil),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &database{
+				client: tt.client.db,
+			}
+			err := d.DeleteRotatedKeys("id1", "id2")
+			assert.Equal(t, tt.err, err != nil, "unexpected error: %v", err)
+			if err := tt.client.mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+// InstanceHasher is implemented by algorithms which are able to hash values with a key of the instance
+type InstanceHasher interface {
+	InstanceHasher(instanceID string) HashAlgorithm
+}
+
+// HMACHasher hashes one-time codes with HMAC-SHA256.
+// The key of every instance is derived from the key of the hasher,
+// so a hashed code of one instance can't be compared on another one.
+type HMACHasher struct {
+	key []byte
+}
+
+func NewHMACHasher(key []byte) *HMACHasher {
+	return &HMACHasher{key: key}
+}
+
+func (h *HMACHasher) InstanceHasher(instanceID string) HashAlgorithm {
+	return &instanceHMAC{key: macSHA256(h.key, []byte(instanceID))}
+}
+
+type instanceHMAC struct {
+	key []byte
+}
+
+func (h *instanceHMAC) Algorithm() string {
+	return "hmac-sha256"
+}
+
+func (h *instanceHMAC) Hash(value []byte) ([]byte, error) {
+	return macSHA256(h.key, value), nil
+}
+
+func (h *instanceHMAC) CompareHash(hashed, comparer []byte) error {
+	if !hmac.Equal(hashed, macSHA256(h.key, comparer)) {
+		return errors.ThrowInvalidArgument(nil, "CRYPT-ahN6i", "Errors.User.Code.Invalid")
+	}
+	return nil
+}
+
+func macSHA256(key, value []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(value)
+	return mac.Sum(nil)
+}
+
+// CodeAlgorithm encrypts the one-time codes
+// and hashes them by the HMACHasher if the secret generator of the code requires hashed codes.
+// The encrypted copies of hashed codes are kept in the CodeCopyStorage until they are sent.
+type CodeAlgorithm struct {
+	EncryptionAlgorithm
+	hasher *HMACHasher
+	copies CodeCopyStorage
+}
+
+// NewCodeAlgorithm returns an algorithm which can't hash codes if the copies are nil
+func NewCodeAlgorithm(encryption EncryptionAlgorithm, hasher *HMACHasher, copies CodeCopyStorage) *CodeAlgorithm {
+	return &CodeAlgorithm{
+		EncryptionAlgorithm: encryption,
+		hasher:              hasher,
+		copies:              copies,
+	}
+}
+
+func (c *CodeAlgorithm) InstanceHasher(instanceID string) HashAlgorithm {
+	return c.hasher.InstanceHasher(instanceID)
+}
+
+func (c *CodeAlgorithm) CodeCopies() CodeCopyStorage {
+	return c.copies
+}
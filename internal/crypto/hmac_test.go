@@ -0,0 +1,144 @@
+package crypto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+func TestHMACHasher_InstanceHasher(t *testing.T) {
+	hasher := NewHMACHasher([]byte("key"))
+	instance1 := hasher.InstanceHasher("instance1")
+
+	hashed, err := Hash([]byte("code"), instance1)
+	require.NoError(t, err)
+	assert.Equal(t, TypeHash, hashed.CryptoType)
+	assert.Equal(t, "hmac-sha256", hashed.Algorithm)
+	assert.NotEqual(t, []byte("code"), hashed.Crypted)
+
+	assert.NoError(t, CompareHash(hashed, []byte("code"), hasher.InstanceHasher("instance1")))
+	assert.True(t, errors.IsErrorInvalidArgument(CompareHash(hashed, []byte("wrong"), instance1)))
+	assert.True(t, errors.IsErrorInvalidArgument(CompareHash(hashed, []byte("code"), hasher.InstanceHasher("instance2"))))
+	assert.True(t, errors.IsErrorInvalidArgument(CompareHash(hashed, []byte("code"), NewHMACHasher([]byte("other")).InstanceHasher("instance1"))))
+}
+
+func TestNewCodeGenerator(t *testing.T) {
+	config := GeneratorConfig{
+		Length:        6,
+		Expiry:        time.Hour,
+		IncludeDigits: true,
+	}
+	hashedConfig := config
+	hashedConfig.Hashed = true
+	encryption := CreateMockEncryptionAlg(gomock.NewController(t))
+	codeAlgorithm := NewCodeAlgorithm(encryption, NewHMACHasher([]byte("key")), CreateMockCodeCopyStorage())
+
+	tests := []struct {
+		name      string
+		config    GeneratorConfig
+		algorithm EncryptionAlgorithm
+		want      Generator
+		wantErr   func(error) bool
+	}{
+		{
+			name:      "encrypted",
+			config:    config,
+			algorithm: codeAlgorithm,
+			want:      &codeGenerator{},
+		},
+		{
+			name:      "encrypted, hashing not supported by algorithm",
+			config:    config,
+			algorithm: encryption,
+			want:      &codeGenerator{},
+		},
+		{
+			name:      "hashed",
+			config:    hashedConfig,
+			algorithm: codeAlgorithm,
+			want:      &codeGenerator{},
+		},
+		{
+			name:      "hashed, not supported by algorithm",
+			config:    hashedConfig,
+			algorithm: encryption,
+			wantErr:   errors.IsPreconditionFailed,
+		},
+		{
+			name:      "hashed, no storage of the copies",
+			config:    hashedConfig,
+			algorithm: NewCodeAlgorithm(encryption, NewHMACHasher([]byte("key")), nil),
+			wantErr:   errors.IsPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCodeGenerator(tt.config, tt.algorithm, "instance1")
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewCodeGenerator_verify(t *testing.T) {
+	codeAlgorithm := NewCodeAlgorithm(CreateMockEncryptionAlg(gomock.NewController(t)), NewHMACHasher([]byte("key")), CreateMockCodeCopyStorage())
+	config := GeneratorConfig{Length: 6, IncludeDigits: true}
+	hashedConfig := config
+	hashedConfig.Hashed = true
+	generator, err := NewCodeGenerator(hashedConfig, codeAlgorithm, "instance1")
+	require.NoError(t, err)
+	unhashedGenerator, err := NewCodeGenerator(config, codeAlgorithm, "instance1")
+	require.NoError(t, err)
+	otherInstance, err := NewCodeGenerator(hashedConfig, codeAlgorithm, "instance2")
+	require.NoError(t, err)
+
+	// sent codes are hashed and the notification handler decrypts their copy
+	sent, code, err := NewCode(generator)
+	require.NoError(t, err)
+	assert.Equal(t, TypeHash, sent.CryptoType)
+	assert.NoError(t, VerifyCode(time.Now(), 0, sent, code, generator))
+	assert.NoError(t, VerifyCode(time.Now(), 0, sent, code, unhashedGenerator))
+	decrypted, err := DecryptCode("instance1", sent, codeAlgorithm)
+	require.NoError(t, err)
+	assert.Equal(t, code, decrypted)
+	_, err = DecryptCode("instance2", sent, codeAlgorithm)
+	assert.True(t, errors.IsNotFound(err))
+	require.NoError(t, DeleteCodeCopy("instance1", sent, codeAlgorithm))
+	_, err = DecryptCode("instance1", sent, codeAlgorithm)
+	assert.True(t, errors.IsNotFound(err))
+	// the code remains valid after the copy is deleted
+	assert.NoError(t, VerifyCode(time.Now(), 0, sent, code, generator))
+
+	crypted, code, err := NewCode(unhashedGenerator)
+	require.NoError(t, err)
+	assert.Equal(t, TypeEncryption, crypted.CryptoType)
+	assert.NoError(t, VerifyCode(time.Now(), 0, crypted, code, generator))
+	decrypted, err = DecryptCode("instance1", crypted, codeAlgorithm)
+	require.NoError(t, err)
+	assert.Equal(t, code, decrypted)
+
+	returned, code, err := NewReturnedCode(generator)
+	require.NoError(t, err)
+	assert.Equal(t, TypeHash, returned.CryptoType)
+	_, err = DecryptCode("instance1", returned, codeAlgorithm)
+	assert.True(t, errors.IsNotFound(err))
+
+	assert.NoError(t, VerifyCode(time.Now(), 0, returned, code, generator))
+	assert.Error(t, VerifyCode(time.Now(), 0, returned, code+"1", generator))
+	// the hashed code remains valid if hashing is disabled afterwards
+	assert.NoError(t, VerifyCode(time.Now(), 0, returned, code, unhashedGenerator))
+	assert.Error(t, VerifyCode(time.Now(), 0, returned, code, otherInstance))
+
+	returned, _, err = NewReturnedCode(unhashedGenerator)
+	require.NoError(t, err)
+	assert.Equal(t, TypeEncryption, returned.CryptoType)
+}
@@ -0,0 +1,182 @@
+package crypto
+
+import (
+	"time"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+// KeyPurpose identifies the encryption keys of a kind of secret which can be rotated
+type KeyPurpose string
+
+const (
+	KeyPurposeOTP       KeyPurpose = "otp"
+	KeyPurposeSMS       KeyPurpose = "sms"
+	KeyPurposeSMTP      KeyPurpose = "smtp"
+	KeyPurposeIDPConfig KeyPurpose = "idpconfig"
+	KeyPurposeOIDC      KeyPurpose = "oidc"
+)
+
+var KeyPurposes = []KeyPurpose{
+	KeyPurposeOTP,
+	KeyPurposeSMS,
+	KeyPurposeSMTP,
+	KeyPurposeIDPConfig,
+	KeyPurposeOIDC,
+}
+
+func ParseKeyPurpose(purpose string) (KeyPurpose, error) {
+	for _, p := range KeyPurposes {
+		if string(p) == purpose {
+			return p, nil
+		}
+	}
+	return "", errors.ThrowInvalidArgumentf(nil, "CRYPT-Ahx2o", "unknown key purpose %q", purpose)
+}
+
+// KeyRotation marks KeyID as the current encryption key of the purpose.
+// The keys which were used before remain decryption keys
+// until all values are re-encrypted with the current key.
+type KeyRotation struct {
+	Purpose        KeyPurpose
+	KeyID          string
+	PreviousKeyIDs []string
+	RotatedAt      time.Time
+	// ConfirmedAt is set as soon as all running processes use KeyID,
+	// the secrets aren't re-encrypted before, so no process is left unable to decrypt them
+	ConfirmedAt time.Time
+	// Remaining is the amount of values which were still encrypted with a previous key at CheckedAt
+	Remaining uint64
+	CheckedAt time.Time
+}
+
+// NewKeyRotation rotates the encryption key of the purpose to keyID.
+// The keys of the config and of the current rotation become previous keys.
+func NewKeyRotation(purpose KeyPurpose, keyID string, config *KeyConfig, current *KeyRotation) (*KeyRotation, error) {
+	if keyID == "" {
+		return nil, errors.ThrowInvalidArgument(nil, "CRYPT-ieV6u", "key id must not be empty")
+	}
+	previous := current.Apply(config)
+	if previous.EncryptionKeyID == keyID {
+		return nil, errors.ThrowPreconditionFailedf(nil, "CRYPT-Oow4e", "key %s is already the current key of %s", keyID, purpose)
+	}
+	rotation := &KeyRotation{
+		Purpose:   purpose,
+		KeyID:     keyID,
+		RotatedAt: time.Now(),
+	}
+	for _, id := range append([]string{previous.EncryptionKeyID}, previous.DecryptionKeyIDs...) {
+		rotation.addPreviousKeyID(id)
+	}
+	return rotation, nil
+}
+
+func (r *KeyRotation) addPreviousKeyID(id string) {
+	if id == "" || id == r.KeyID {
+		return
+	}
+	for _, previous := range r.PreviousKeyIDs {
+		if previous == id {
+			return
+		}
+	}
+	r.PreviousKeyIDs = append(r.PreviousKeyIDs, id)
+}
+
+// Apply returns the config which encrypts with the rotated key
+// and still decrypts values of all previous keys.
+// If r is nil the config is returned unchanged.
+func (r *KeyRotation) Apply(config *KeyConfig) *KeyConfig {
+	if r == nil || config == nil {
+		return config
+	}
+	rotated := &KeyConfig{
+		EncryptionKeyID: r.KeyID,
+	}
+	for _, id := range append(append([]string{config.EncryptionKeyID}, config.DecryptionKeyIDs...), r.PreviousKeyIDs...) {
+		if id == "" || id == r.KeyID || containsKeyID(rotated.DecryptionKeyIDs, id) {
+			continue
+		}
+		rotated.DecryptionKeyIDs = append(rotated.DecryptionKeyIDs, id)
+	}
+	return rotated
+}
+
+// ApplyDecryption returns the config which still encrypts with the configured key
+// but also decrypts values of the rotated and all previous keys.
+// It's used where values without key id are encrypted as well, e.g. the oidc tokens,
+// which would become invalid if their key changed
+func (r *KeyRotation) ApplyDecryption(config *KeyConfig) *KeyConfig {
+	rotated := r.Apply(config)
+	if rotated == config {
+		return config
+	}
+	decryption := &KeyConfig{
+		EncryptionKeyID:  config.EncryptionKeyID,
+		DecryptionKeyIDs: []string{rotated.EncryptionKeyID},
+	}
+	for _, id := range rotated.DecryptionKeyIDs {
+		if id != config.EncryptionKeyID {
+			decryption.DecryptionKeyIDs = append(decryption.DecryptionKeyIDs, id)
+		}
+	}
+	return decryption
+}
+
+// Confirmed returns true if all running processes used the rotated key
+// when the rotation was checked last
+func (r *KeyRotation) Confirmed() bool {
+	return !r.ConfirmedAt.IsZero() && !r.ConfirmedAt.Before(r.RotatedAt)
+}
+
+// Done returns true if no values were encrypted with previous keys
+// at the last check after the confirmation of the rotation, so the previous keys can be deleted
+func (r *KeyRotation) Done() bool {
+	return r.Confirmed() && !r.CheckedAt.IsZero() && r.CheckedAt.After(r.ConfirmedAt) && r.Remaining == 0
+}
+
+func containsKeyID(ids []string, id string) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
+
+type KeyRotations map[KeyPurpose]*KeyRotation
+
+// DeletableKeyID returns true if the key is only a previous key of finished rotations
+func (r KeyRotations) DeletableKeyID(id string) bool {
+	var previous bool
+	for _, rotation := range r {
+		if rotation.KeyID == id {
+			return false
+		}
+		if !containsKeyID(rotation.PreviousKeyIDs, id) {
+			continue
+		}
+		if !rotation.Done() {
+			return false
+		}
+		previous = true
+	}
+	return previous
+}
+
+type KeyRotationStorage interface {
+	ReadKeyRotations() (KeyRotations, error)
+	// SetKeyRotation stores the rotation as the current one of its purpose and resets its progress
+	SetKeyRotation(rotation *KeyRotation) error
+	SetKeyRotationProgress(purpose KeyPurpose, remaining uint64) error
+	// ConfirmKeyRotations stores the current key of each purpose the process (node) uses
+	// and removes the confirmations of the processes which didn't confirm since expiredBefore
+	ConfirmKeyRotations(nodeID string, keyIDs map[KeyPurpose]string, expiredBefore time.Time) error
+	// UnconfirmedKeyRotationNodes returns the amount of processes which confirmed another key
+	// than keyID of the purpose since the given time
+	UnconfirmedKeyRotationNodes(purpose KeyPurpose, keyID string, since time.Time) (uint64, error)
+	// SetKeyRotationConfirmed marks the rotation of the purpose as confirmed by all running processes
+	SetKeyRotationConfirmed(purpose KeyPurpose) error
+	// DeleteRotatedKeys deletes the keys and removes them from the previous keys of the rotations
+	DeleteRotatedKeys(ids ...string) error
+}
@@ -0,0 +1,204 @@
+package crypto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+func TestNewKeyRotation(t *testing.T) {
+	config := &KeyConfig{EncryptionKeyID: "otpKey", DecryptionKeyIDs: []string{"oldKey"}}
+	type args struct {
+		keyID   string
+		current *KeyRotation
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantPrevious []string
+		wantErr      func(error) bool
+	}{
+		{
+			name:    "empty key id, error",
+			args:    args{},
+			wantErr: errors.IsErrorInvalidArgument,
+		},
+		{
+			name:    "configured key, error",
+			args:    args{keyID: "otpKey"},
+			wantErr: errors.IsPreconditionFailed,
+		},
+		{
+			name: "current rotated key, error",
+			args: args{
+				keyID:   "otpKey2",
+				current: &KeyRotation{KeyID: "otpKey2", PreviousKeyIDs: []string{"otpKey", "oldKey"}},
+			},
+			wantErr: errors.IsPreconditionFailed,
+		},
+		{
+			name:         "first rotation",
+			args:         args{keyID: "otpKey2"},
+			wantPrevious: []string{"otpKey", "oldKey"},
+		},
+		{
+			name: "rotation of rotated key",
+			args: args{
+				keyID:   "otpKey3",
+				current: &KeyRotation{KeyID: "otpKey2", PreviousKeyIDs: []string{"otpKey", "oldKey"}},
+			},
+			wantPrevious: []string{"otpKey2", "otpKey", "oldKey"},
+		},
+		{
+			name: "rotation back to previous key",
+			args: args{
+				keyID:   "otpKey",
+				current: &KeyRotation{KeyID: "otpKey2", PreviousKeyIDs: []string{"otpKey", "oldKey"}},
+			},
+			wantPrevious: []string{"otpKey2", "oldKey"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewKeyRotation(KeyPurposeOTP, tt.args.keyID, config, tt.args.current)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.args.keyID, got.KeyID)
+			assert.Equal(t, tt.wantPrevious, got.PreviousKeyIDs)
+		})
+	}
+}
+
+func TestKeyRotation_Apply(t *testing.T) {
+	config := &KeyConfig{EncryptionKeyID: "oidcKey", DecryptionKeyIDs: []string{"oldKey"}}
+	rotation := &KeyRotation{KeyID: "oidcKey3", PreviousKeyIDs: []string{"oidcKey2", "oidcKey"}}
+
+	var noRotation *KeyRotation
+	assert.Same(t, config, noRotation.Apply(config))
+	assert.Same(t, config, noRotation.ApplyDecryption(config))
+
+	assert.Equal(t, &KeyConfig{
+		EncryptionKeyID:  "oidcKey3",
+		DecryptionKeyIDs: []string{"oidcKey", "oldKey", "oidcKey2"},
+	}, rotation.Apply(config))
+	assert.Equal(t, &KeyConfig{
+		EncryptionKeyID:  "oidcKey",
+		DecryptionKeyIDs: []string{"oidcKey3", "oldKey", "oidcKey2"},
+	}, rotation.ApplyDecryption(config))
+}
+
+func TestKeyRotation_Done(t *testing.T) {
+	rotatedAt := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name     string
+		rotation *KeyRotation
+		want     bool
+	}{
+		{
+			name: "not checked",
+			rotation: &KeyRotation{
+				RotatedAt:   rotatedAt,
+				ConfirmedAt: rotatedAt.Add(time.Second),
+			},
+			want: false,
+		},
+		{
+			name: "not confirmed by all processes",
+			rotation: &KeyRotation{
+				RotatedAt: rotatedAt,
+				CheckedAt: rotatedAt.Add(time.Minute),
+			},
+			want: false,
+		},
+		{
+			name: "confirmed before rotation",
+			rotation: &KeyRotation{
+				RotatedAt:   rotatedAt,
+				ConfirmedAt: rotatedAt.Add(-time.Second),
+				CheckedAt:   rotatedAt.Add(time.Minute),
+			},
+			want: false,
+		},
+		{
+			name: "checked before confirmation",
+			rotation: &KeyRotation{
+				RotatedAt:   rotatedAt,
+				ConfirmedAt: rotatedAt.Add(2 * time.Minute),
+				CheckedAt:   rotatedAt.Add(time.Minute),
+			},
+			want: false,
+		},
+		{
+			name: "secrets remaining",
+			rotation: &KeyRotation{
+				RotatedAt:   rotatedAt,
+				ConfirmedAt: rotatedAt.Add(time.Second),
+				CheckedAt:   rotatedAt.Add(time.Minute),
+				Remaining:   1,
+			},
+			want: false,
+		},
+		{
+			name: "done",
+			rotation: &KeyRotation{
+				RotatedAt:   rotatedAt,
+				ConfirmedAt: rotatedAt.Add(time.Second),
+				CheckedAt:   rotatedAt.Add(time.Minute),
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rotation.Done())
+		})
+	}
+}
+
+func TestKeyRotations_DeletableKeyID(t *testing.T) {
+	rotatedAt := time.Now().Add(-time.Hour)
+	rotations := KeyRotations{
+		KeyPurposeOTP: {
+			KeyID:          "otpKey2",
+			PreviousKeyIDs: []string{"otpKey", "sharedKey"},
+			RotatedAt:      rotatedAt,
+			ConfirmedAt:    rotatedAt.Add(time.Second),
+			CheckedAt:      rotatedAt.Add(time.Minute),
+		},
+		KeyPurposeSMS: {
+			KeyID:          "smsKey2",
+			PreviousKeyIDs: []string{"smsKey", "sharedKey"},
+			RotatedAt:      rotatedAt,
+			ConfirmedAt:    rotatedAt.Add(time.Second),
+			Remaining:      2,
+			CheckedAt:      rotatedAt.Add(time.Minute),
+		},
+		KeyPurposeSMTP: {
+			KeyID:          "smtpKey2",
+			PreviousKeyIDs: []string{"smtpKey"},
+			RotatedAt:      rotatedAt,
+		},
+	}
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"otpKey", true},
+		{"otpKey2", false},
+		{"smsKey", false},
+		{"sharedKey", false},
+		{"smtpKey", false},
+		{"unknownKey", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			assert.Equal(t, tt.want, rotations.DeletableKeyID(tt.id))
+		})
+	}
+}
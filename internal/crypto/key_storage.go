@@ -1,7 +1,38 @@
 package crypto
 
+import "time"
+
 type KeyStorage interface {
 	ReadKeys() (Keys, error)
 	ReadKey(id string) (*Key, error)
 	CreateKeys(...*Key) error
 }
+
+// KeyWrapper wraps (encrypts) and unwraps (decrypts) the encryption keys
+// with a key which never leaves the key management service (KMS)
+type KeyWrapper interface {
+	WrapKey(key []byte) ([]byte, error)
+	UnwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// PIIKeyStorage stores the data keys which encrypt the personal data of an aggregate
+type PIIKeyStorage interface {
+	// ReadPIIKey returns a not found error if the aggregate has no key (anymore)
+	ReadPIIKey(instanceID, aggregateID string) (string, error)
+	// EnsurePIIKey stores the key if the aggregate has no key yet and returns the stored key of the aggregate
+	EnsurePIIKey(instanceID, aggregateType, aggregateID, key string) (string, error)
+	DeletePIIKey(instanceID, aggregateID string) error
+	// DeleteErasedPIIKeys deletes the keys of all aggregates with an event of the erasure types
+	DeleteErasedPIIKeys(erasureTypes []string) (int64, error)
+}
+
+// CodeCopyStorage stores encrypted copies of hashed one-time codes,
+// so the codes can be sent until they expire even though only their hash is stored in the event
+type CodeCopyStorage interface {
+	// StoreCodeCopy stores the copy of the hashed code and deletes the expired copies of the instance,
+	// a zero expiration keeps the copy until it's deleted
+	StoreCodeCopy(instanceID string, hash []byte, crypted *CryptoValue, expiration time.Time) error
+	// ReadCodeCopy returns a not found error if the copy was deleted or is expired
+	ReadCodeCopy(instanceID string, hash []byte) (*CryptoValue, error)
+	DeleteCodeCopy(instanceID string, hash []byte) error
+}
@@ -0,0 +1,113 @@
+package kms
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+var _ crypto.KeyWrapper = (*Emulator)(nil)
+
+type EmulatorConfig struct {
+	// Path to the YAML file of the kms keys (keyID: base64 encoded 32 byte key)
+	Path string
+	// KeyID of the kms key which wraps new encryption keys
+	KeyID string
+}
+
+// Emulator is a local stand-in for an external KMS, e.g. for tests and development.
+// It wraps the encryption keys with AES-GCM.
+// Keys of the file other than the configured one are only used for unwrapping,
+// which allows the rotation of the kms key.
+type Emulator struct {
+	keyID string
+	keys  map[string][]byte
+}
+
+func NewEmulator(config *EmulatorConfig) (*Emulator, error) {
+	if config == nil || config.Path == "" {
+		return nil, errors.ThrowInvalidArgument(nil, "KMS-aeB4u", "path of the kms emulator keys missing")
+	}
+	data, err := os.ReadFile(config.Path)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "KMS-Ohr5i", "unable to read kms emulator keys")
+	}
+	keys := make(map[string][]byte)
+	if err = yaml.Unmarshal(data, &keys); err != nil {
+		return nil, errors.ThrowInternal(err, "KMS-ohG2i", "unable to read kms emulator keys")
+	}
+	return newEmulator(config.KeyID, keys)
+}
+
+func newEmulator(keyID string, keys map[string][]byte) (*Emulator, error) {
+	if len(keyID) == 0 || len(keyID) > 255 {
+		return nil, errors.ThrowInvalidArgument(nil, "KMS-Shei3", "kms key id must be between 1 and 255 characters")
+	}
+	if _, ok := keys[keyID]; !ok {
+		return nil, errors.ThrowNotFoundf(nil, "KMS-eiTh7", "kms key %s not found", keyID)
+	}
+	for id, key := range keys {
+		if len(key) != 32 {
+			return nil, errors.ThrowInvalidArgumentf(nil, "KMS-Dai9o", "kms key %s must be 32 bytes, but is %d", id, len(key))
+		}
+	}
+	return &Emulator{
+		keyID: keyID,
+		keys:  keys,
+	}, nil
+}
+
+// WrapKey encrypts the key with the configured kms key.
+// The wrapped key is prefixed with the id of the kms key.
+func (e *Emulator) WrapKey(key []byte) ([]byte, error) {
+	aead, err := e.aead(e.keyID)
+	if err != nil {
+		return nil, err
+	}
+	prefix := append([]byte{byte(len(e.keyID))}, e.keyID...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.ThrowInternal(err, "KMS-Ahm1u", "unable to wrap key")
+	}
+	wrapped := make([]byte, 0, len(prefix)+len(nonce)+len(key)+aead.Overhead())
+	wrapped = append(append(wrapped, prefix...), nonce...)
+	return aead.Seal(wrapped, nonce, key, prefix), nil
+}
+
+func (e *Emulator) UnwrapKey(wrapped []byte) ([]byte, error) {
+	if len(wrapped) == 0 || len(wrapped) < 1+int(wrapped[0]) {
+		return nil, errors.ThrowInvalidArgument(nil, "KMS-Uu4ie", "invalid wrapped key")
+	}
+	prefix, rest := wrapped[:1+int(wrapped[0])], wrapped[1+int(wrapped[0]):]
+	aead, err := e.aead(string(prefix[1:]))
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, errors.ThrowInvalidArgument(nil, "KMS-ie8Ah", "invalid wrapped key")
+	}
+	key, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], prefix)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "KMS-Vo3ae", "unable to unwrap key")
+	}
+	return key, nil
+}
+
+func (e *Emulator) aead(keyID string) (cipher.AEAD, error) {
+	key, ok := e.keys[keyID]
+	if !ok {
+		return nil, errors.ThrowNotFoundf(nil, "KMS-iu7Ee", "kms key %s not found", keyID)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "KMS-Eo0ph", "invalid kms key")
+	}
+	return cipher.NewGCM(block)
+}
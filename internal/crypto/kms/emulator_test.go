@@ -0,0 +1,64 @@
+package kms
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+func TestNewEmulator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	// the keys are base64 encoded 32 bytes
+	err := os.WriteFile(path, []byte(`
+kmsKey: MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE=
+shortKey: MDEyMzQ1Njc4OQ==
+`), 0600)
+	assert.NoError(t, err)
+
+	_, err = NewEmulator(&EmulatorConfig{KeyID: "kmsKey"})
+	assert.True(t, errors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+	_, err = NewEmulator(&EmulatorConfig{Path: path, KeyID: "unknownKey"})
+	assert.True(t, errors.IsNotFound(err), "unexpected error: %v", err)
+	_, err = NewEmulator(&EmulatorConfig{Path: path, KeyID: "kmsKey"})
+	assert.True(t, errors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+}
+
+func TestEmulator_WrapKey(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+	previous, err := newEmulator("old", map[string][]byte{"old": oldKey})
+	assert.NoError(t, err)
+	emulator, err := newEmulator("new", map[string][]byte{"old": oldKey, "new": newKey})
+	assert.NoError(t, err)
+
+	wrapped, err := emulator.WrapKey([]byte("encryptionKey"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(wrapped), "encryptionKey")
+	key, err := emulator.UnwrapKey(wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("encryptionKey"), key)
+
+	// keys wrapped by a previous kms key can still be unwrapped
+	wrapped, err = previous.WrapKey([]byte("previousKey"))
+	assert.NoError(t, err)
+	key, err = emulator.UnwrapKey(wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("previousKey"), key)
+
+	// but not the other way round
+	wrapped, err = emulator.WrapKey([]byte("encryptionKey"))
+	assert.NoError(t, err)
+	_, err = previous.UnwrapKey(wrapped)
+	assert.True(t, errors.IsNotFound(err), "unexpected error: %v", err)
+
+	wrapped[len(wrapped)-1] ^= 1
+	_, err = emulator.UnwrapKey(wrapped)
+	assert.True(t, errors.IsInternal(err), "unexpected error: %v", err)
+	_, err = emulator.UnwrapKey([]byte{10, 'n'})
+	assert.True(t, errors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+}
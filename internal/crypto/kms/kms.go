@@ -0,0 +1,36 @@
+package kms
+
+import (
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+const (
+	TypeEmulator = "emulator"
+)
+
+// Config of the key management service (KMS) which wraps the encryption keys
+// instead of the masterkey
+type Config struct {
+	// Type of the KMS, the keys are wrapped by the masterkey if empty
+	Type     string
+	Emulator *EmulatorConfig
+}
+
+// Enabled returns if the encryption keys are wrapped by a KMS
+func (c *Config) Enabled() bool {
+	return c != nil && c.Type != ""
+}
+
+func (c *Config) NewKeyWrapper() (crypto.KeyWrapper, error) {
+	switch c.Type {
+	case TypeEmulator:
+		emulator, err := NewEmulator(c.Emulator)
+		if err != nil {
+			return nil, err
+		}
+		return emulator, nil
+	default:
+		return nil, errors.ThrowInvalidArgumentf(nil, "KMS-Ue7ah", "unknown kms type %q", c.Type)
+	}
+}
@@ -0,0 +1,151 @@
+package crypto
+
+import (
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+const (
+	HashNameBCrypt   = "bcrypt"
+	HashNameArgon2id = "argon2id"
+	HashNameScrypt   = "scrypt"
+	HashNamePBKDF2   = "pbkdf2"
+	HashNameSHA      = "sha"
+)
+
+var _ HashAlgorithm = (*PasswordHasher)(nil)
+
+// PasswordHashConfig defines the preferred algorithm to hash passwords
+// bcrypt uses the cost of SecretGenerators.PasswordSaltCost
+type PasswordHashConfig struct {
+	// Algorithm is one of bcrypt (default), argon2id, scrypt or pbkdf2
+	Algorithm string
+	Argon2id  Argon2Config
+	Scrypt    ScryptConfig
+	PBKDF2    PBKDF2Config
+}
+
+// passwordVerifier verifies passwords against the hashes it recognises by their encoding
+type passwordVerifier interface {
+	Algorithm() string
+	Identify(hashed []byte) bool
+	// CheckHash validates the encoding and the parameters of the hash
+	CheckHash(hashed []byte) error
+	CompareHash(hashed, value []byte) error
+}
+
+// passwordVerifiers are the algorithms of the hashes which can be verified
+var passwordVerifiers = []passwordVerifier{new(BCrypt), new(Argon2id), new(Scrypt), new(PBKDF2), SHA{}}
+
+// outdatedChecker is implemented by algorithms
+// which can tell if a hash was created with other parameters than the configured ones
+type outdatedChecker interface {
+	Outdated(hashed []byte) bool
+}
+
+// PasswordHasher hashes new passwords with the preferred algorithm
+// and verifies passwords against hashes of all supported algorithms
+type PasswordHasher struct {
+	preferred HashAlgorithm
+	verifiers []passwordVerifier
+}
+
+func NewPasswordHasher(config *PasswordHashConfig, bcryptCost int) (*PasswordHasher, error) {
+	hasher := &PasswordHasher{
+		verifiers: passwordVerifiers,
+	}
+	var err error
+	switch config.Algorithm {
+	case "", HashNameBCrypt:
+		hasher.preferred = NewBCrypt(bcryptCost)
+	case HashNameArgon2id:
+		hasher.preferred, err = NewArgon2id(config.Argon2id)
+	case HashNameScrypt:
+		hasher.preferred, err = NewScrypt(config.Scrypt)
+	case HashNamePBKDF2:
+		hasher.preferred, err = NewPBKDF2(config.PBKDF2)
+	default:
+		return nil, errors.ThrowInvalidArgumentf(nil, "CRYPT-Eip2a", "unsupported password hash algorithm %q", config.Algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hasher, nil
+}
+
+func (h *PasswordHasher) Algorithm() string {
+	return h.preferred.Algorithm()
+}
+
+// Hash hashes the value with the preferred algorithm
+func (h *PasswordHasher) Hash(value []byte) ([]byte, error) {
+	return h.preferred.Hash(value)
+}
+
+// CompareHash compares the value with a hash of any supported algorithm
+func (h *PasswordHasher) CompareHash(hashed, value []byte) error {
+	verifier := h.verifier(hashed)
+	if verifier == nil {
+		return errors.ThrowInvalidArgument(nil, "CRYPT-Ooj7e", "unsupported password hash")
+	}
+	return verifier.CompareHash(hashed, value)
+}
+
+// Verify compares the password with the hashed value.
+// If the hash wasn't created by the preferred algorithm and its current parameters,
+// the password is hashed again and the new value is returned
+func (h *PasswordHasher) Verify(value *CryptoValue, password []byte) (updated *CryptoValue, err error) {
+	if err = h.CompareHash(value.Crypted, password); err != nil {
+		return nil, err
+	}
+	if !h.outdated(value) {
+		return nil, nil
+	}
+	return Hash(password, h)
+}
+
+func (h *PasswordHasher) outdated(value *CryptoValue) bool {
+	if value.Algorithm != h.preferred.Algorithm() {
+		return true
+	}
+	if verifier, ok := h.preferred.(passwordVerifier); ok && !verifier.Identify(value.Crypted) {
+		return true
+	}
+	if checker, ok := h.preferred.(outdatedChecker); ok {
+		return checker.Outdated(value.Crypted)
+	}
+	return false
+}
+
+func (h *PasswordHasher) verifier(hashed []byte) passwordVerifier {
+	for _, verifier := range h.verifiers {
+		if verifier.Identify(hashed) {
+			return verifier
+		}
+	}
+	return nil
+}
+
+// CheckPasswordHash validates an imported hash of the algorithm,
+// so only hashes which can be verified with reasonable resources are stored
+func CheckPasswordHash(algorithm string, hashed []byte) error {
+	for _, verifier := range passwordVerifiers {
+		if verifier.Algorithm() != algorithm {
+			continue
+		}
+		if !verifier.Identify(hashed) {
+			return errors.ThrowInvalidArgumentf(nil, "CRYPT-ahR6i", "hash is not a %s hash", algorithm)
+		}
+		return verifier.CheckHash(hashed)
+	}
+	return errors.ThrowInvalidArgumentf(nil, "CRYPT-Aet5u", "unsupported password hash algorithm %q", algorithm)
+}
+
+// VerifyPassword compares the password with the hashed value.
+// If the algorithm is a [PasswordHasher], hashes of all supported algorithms are verified
+// and the re-hashed password is returned if the preferred algorithm changed
+func VerifyPassword(value *CryptoValue, password []byte, alg HashAlgorithm) (updated *CryptoValue, err error) {
+	if hasher, ok := alg.(*PasswordHasher); ok {
+		return hasher.Verify(value, password)
+	}
+	return nil, CompareHash(value, password, alg)
+}
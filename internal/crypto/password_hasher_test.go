@@ -0,0 +1,412 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+func testPasswordHasher(t *testing.T, algorithm string) *PasswordHasher {
+	t.Helper()
+	hasher, err := NewPasswordHasher(&PasswordHashConfig{
+		Algorithm: algorithm,
+		Argon2id:  Argon2Config{Time: 1, Memory: 64, Threads: 1},
+		Scrypt:    ScryptConfig{Cost: 10, BlockSize: 8, Parallelism: 1},
+		PBKDF2:    PBKDF2Config{Iterations: 1000, Hash: "sha256"},
+	}, 4)
+	if err != nil {
+		t.Fatalf("unable to create hasher: %v", err)
+	}
+	return hasher
+}
+
+func TestPasswordHasher_Hash(t *testing.T) {
+	for _, algorithm := range []string{HashNameBCrypt, HashNameArgon2id, HashNameScrypt, HashNamePBKDF2} {
+		t.Run(algorithm, func(t *testing.T) {
+			hasher := testPasswordHasher(t, algorithm)
+			value, err := Hash([]byte("Password1!"), hasher)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value.Algorithm != algorithm {
+				t.Errorf("expected algorithm %s, got %s", algorithm, value.Algorithm)
+			}
+			updated, err := hasher.Verify(value, []byte("Password1!"))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if updated != nil {
+				t.Errorf("hash of the preferred algorithm must not be updated")
+			}
+			if _, err = hasher.Verify(value, []byte("password")); err == nil {
+				t.Errorf("wrong password must not be verified")
+			}
+		})
+	}
+}
+
+func TestPasswordHasher_Verify(t *testing.T) {
+	tests := []struct {
+		name       string
+		preferred  string
+		value      *CryptoValue
+		wantErr    bool
+		wantUpdate bool
+	}{
+		{
+			name:      "passlib pbkdf2 sha256",
+			preferred: HashNamePBKDF2,
+			value:     FillHash([]byte("$pbkdf2-sha256$1000$c2FsdHNhbHRzYWx0c2FsdA$ChY5wiklPMJbg8m9hIWGFSTp2vVY2CO4p9/OcxOxSrI"), NewBCrypt(4)),
+			// imported hashes are labeled with the algorithm at the time of the import
+			wantUpdate: true,
+		},
+		{
+			name:       "passlib pbkdf2 sha1",
+			preferred:  HashNameBCrypt,
+			value:      &CryptoValue{Algorithm: HashNamePBKDF2, Crypted: []byte("$pbkdf2$1000$c2FsdHNhbHRzYWx0c2FsdA$jErnKSGy/BZCi9suPtARlunqjdg")},
+			wantUpdate: true,
+		},
+		{
+			name:      "phc pbkdf2 sha512",
+			preferred: HashNamePBKDF2,
+			value:     &CryptoValue{Algorithm: HashNamePBKDF2, Crypted: []byte("$pbkdf2-sha512$i=1000$c2FsdHNhbHRzYWx0c2FsdA$oBTUN9EDdUI4aikPas577t2ZPmmCwkdQ+51OQg4RgpuHUBullZVOmpOtPbhMmwR/XVcKELyegD16TwKha8Yqrw")},
+			// configured hash is sha256
+			wantUpdate: true,
+		},
+		{
+			name:      "scrypt with current parameters",
+			preferred: HashNameScrypt,
+			value:     &CryptoValue{Algorithm: HashNameScrypt, Crypted: []byte("$scrypt$ln=10,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$5lGCG1dS2YfNexFQzDNEQ3IuHXaUmRyHJr4h+Z0NkVs")},
+		},
+		{
+			name:       "salted sha1",
+			preferred:  HashNameArgon2id,
+			value:      &CryptoValue{Algorithm: HashNameSHA, Crypted: []byte("{SSHA}C54EwcBo0HMKHxLSZo1LYvHBptBzYWx0c2FsdA==")},
+			wantUpdate: true,
+		},
+		{
+			name:       "salted sha256",
+			preferred:  HashNameBCrypt,
+			value:      &CryptoValue{Algorithm: HashNameSHA, Crypted: []byte("{SSHA256}URoUn1+5bkv/3EBYL6oLz2T5WkU8YULw4mnw84s/hJRzYWx0c2FsdHNhbHRzYWx0")},
+			wantUpdate: true,
+		},
+		{
+			name:       "unsalted sha1",
+			preferred:  HashNameBCrypt,
+			value:      &CryptoValue{Algorithm: HashNameSHA, Crypted: []byte("{SHA}MsqfwaD1tjMOP0yMG77N6b7blXM=")},
+			wantUpdate: true,
+		},
+		{
+			name:       "bcrypt with other cost",
+			preferred:  HashNameBCrypt,
+			value:      mustHash(t, NewBCrypt(5)),
+			wantUpdate: true,
+		},
+		{
+			name:       "bcrypt to argon2id",
+			preferred:  HashNameArgon2id,
+			value:      mustHash(t, NewBCrypt(4)),
+			wantUpdate: true,
+		},
+		{
+			name:      "wrong password",
+			preferred: HashNameBCrypt,
+			value:     &CryptoValue{Algorithm: HashNameScrypt, Crypted: []byte("$scrypt$ln=10,r=8,p=2$c2FsdHNhbHRzYWx0c2FsdA$5lGCG1dS2YfNexFQzDNEQ3IuHXaUmRyHJr4h+Z0NkVs")},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown format",
+			preferred: HashNameBCrypt,
+			value:     &CryptoValue{Algorithm: "md5", Crypted: []byte("5f4dcc3b5aa765d61d8327deb882cf99")},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher := testPasswordHasher(t, tt.preferred)
+			updated, err := VerifyPassword(tt.value, []byte("Password1!"), hasher)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (updated != nil) != tt.wantUpdate {
+				t.Fatalf("expected update %t, got %v", tt.wantUpdate, updated)
+			}
+			if updated == nil {
+				return
+			}
+			if updated.Algorithm != tt.preferred {
+				t.Errorf("expected algorithm %s, got %s", tt.preferred, updated.Algorithm)
+			}
+			if again, err := hasher.Verify(updated, []byte("Password1!")); err != nil || again != nil {
+				t.Errorf("updated hash must be verified without update: %v", err)
+			}
+		})
+	}
+}
+
+func TestArgon2id_CompareHash(t *testing.T) {
+	// reference hash of the argon2 command line utility
+	hashed := []byte("$argon2i$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG")
+	argon2 := new(Argon2id)
+	if err := argon2.CompareHash(hashed, []byte("password")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := argon2.CompareHash(hashed, []byte("passw0rd")); err == nil {
+		t.Error("wrong password must not be verified")
+	}
+}
+
+func TestCheckPasswordHash(t *testing.T) {
+	// 16 bytes, the minimal digest length
+	const digest = "c2FsdHNhbHRzYWx0c2FsdA"
+	tests := []struct {
+		name      string
+		algorithm string
+		hashed    string
+		wantErr   bool
+	}{
+		{
+			name:      "argon2id",
+			algorithm: HashNameArgon2id,
+			hashed:    "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$" + digest,
+		},
+		{
+			name:      "argon2id empty digest",
+			algorithm: HashNameArgon2id,
+			hashed:    "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$",
+			wantErr:   true,
+		},
+		{
+			name:      "argon2id short digest",
+			algorithm: HashNameArgon2id,
+			hashed:    "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA",
+			wantErr:   true,
+		},
+		{
+			name:      "argon2id zero parallelism",
+			algorithm: HashNameArgon2id,
+			hashed:    "$argon2id$v=19$m=65536,t=3,p=0$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "argon2id zero time",
+			algorithm: HashNameArgon2id,
+			hashed:    "$argon2id$v=19$m=65536,t=0,p=4$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "argon2id too much time",
+			algorithm: HashNameArgon2id,
+			hashed:    "$argon2id$v=19$m=65536,t=4294967295,p=4$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "argon2id too much memory",
+			algorithm: HashNameArgon2id,
+			hashed:    "$argon2id$v=19$m=4294967295,t=3,p=4$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "argon2id zero memory",
+			algorithm: HashNameArgon2id,
+			hashed:    "$argon2id$v=19$m=0,t=3,p=4$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "scrypt",
+			algorithm: HashNameScrypt,
+			hashed:    "$scrypt$ln=10,r=8,p=1$c2FsdA$" + digest,
+		},
+		{
+			name:      "scrypt empty digest",
+			algorithm: HashNameScrypt,
+			hashed:    "$scrypt$ln=10,r=8,p=1$c2FsdA$",
+			wantErr:   true,
+		},
+		{
+			name:      "scrypt zero cost",
+			algorithm: HashNameScrypt,
+			hashed:    "$scrypt$ln=0,r=8,p=1$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "scrypt too much cost",
+			algorithm: HashNameScrypt,
+			hashed:    "$scrypt$ln=63,r=8,p=1$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "scrypt too much memory",
+			algorithm: HashNameScrypt,
+			hashed:    "$scrypt$ln=20,r=32,p=1$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "scrypt zero block size",
+			algorithm: HashNameScrypt,
+			hashed:    "$scrypt$ln=10,r=0,p=1$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "scrypt zero parallelism",
+			algorithm: HashNameScrypt,
+			hashed:    "$scrypt$ln=10,r=8,p=0$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "scrypt too much parallelism",
+			algorithm: HashNameScrypt,
+			hashed:    "$scrypt$ln=10,r=8,p=1024$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "pbkdf2",
+			algorithm: HashNamePBKDF2,
+			hashed:    "$pbkdf2-sha256$i=1000$c2FsdA$" + digest,
+		},
+		{
+			name:      "pbkdf2 empty digest",
+			algorithm: HashNamePBKDF2,
+			hashed:    "$pbkdf2-sha256$i=1000$c2FsdA$",
+			wantErr:   true,
+		},
+		{
+			name:      "pbkdf2 zero iterations",
+			algorithm: HashNamePBKDF2,
+			hashed:    "$pbkdf2-sha256$i=0$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "pbkdf2 too many iterations",
+			algorithm: HashNamePBKDF2,
+			hashed:    "$pbkdf2-sha256$i=2147483647$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "pbkdf2 unsupported hash",
+			algorithm: HashNamePBKDF2,
+			hashed:    "$pbkdf2-md5$i=1000$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "bcrypt",
+			algorithm: HashNameBCrypt,
+			hashed:    string(mustHash(t, NewBCrypt(4)).Crypted),
+		},
+		{
+			name:      "bcrypt truncated",
+			algorithm: HashNameBCrypt,
+			hashed:    "$2a$04$",
+			wantErr:   true,
+		},
+		{
+			name:      "sha",
+			algorithm: HashNameSHA,
+			hashed:    "{SSHA}C54EwcBo0HMKHxLSZo1LYvHBptBzYWx0c2FsdA==",
+		},
+		{
+			name:      "sha short digest",
+			algorithm: HashNameSHA,
+			hashed:    "{SHA}aGFzaA==",
+			wantErr:   true,
+		},
+		{
+			name:      "other algorithm",
+			algorithm: HashNameArgon2id,
+			hashed:    "$scrypt$ln=10,r=8,p=1$c2FsdA$" + digest,
+			wantErr:   true,
+		},
+		{
+			name:      "unsupported algorithm",
+			algorithm: "md5",
+			hashed:    "5f4dcc3b5aa765d61d8327deb882cf99",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPasswordHash(tt.algorithm, []byte(tt.hashed))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil && !errors.IsErrorInvalidArgument(err) {
+				t.Errorf("expected invalid argument, got %v", err)
+			}
+			// invalid hashes must be rejected on comparison as well
+			hasher := testPasswordHasher(t, HashNameBCrypt)
+			if err = hasher.CompareHash([]byte(tt.hashed), []byte("Password1!")); tt.wantErr && err == nil {
+				t.Error("invalid hash must not be compared")
+			}
+		})
+	}
+}
+
+func TestNewPasswordHasher_unsupported(t *testing.T) {
+	if _, err := NewPasswordHasher(&PasswordHashConfig{Algorithm: "md5"}, 4); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+	if _, err := NewPasswordHasher(&PasswordHashConfig{Algorithm: HashNamePBKDF2, PBKDF2: PBKDF2Config{Iterations: 1000, Hash: "md5"}}, 4); err == nil {
+		t.Error("expected error for unsupported pbkdf2 hash")
+	}
+}
+
+func TestNewPasswordHasher_invalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *PasswordHashConfig
+	}{
+		{
+			name:   "argon2id without time",
+			config: &PasswordHashConfig{Algorithm: HashNameArgon2id, Argon2id: Argon2Config{Time: 0, Memory: 64, Threads: 1}},
+		},
+		{
+			name:   "argon2id without threads",
+			config: &PasswordHashConfig{Algorithm: HashNameArgon2id, Argon2id: Argon2Config{Time: 1, Memory: 64, Threads: 0}},
+		},
+		{
+			name:   "argon2id memory below 8 KiB per thread",
+			config: &PasswordHashConfig{Algorithm: HashNameArgon2id, Argon2id: Argon2Config{Time: 1, Memory: 8, Threads: 2}},
+		},
+		{
+			name:   "argon2id memory above limit",
+			config: &PasswordHashConfig{Algorithm: HashNameArgon2id, Argon2id: Argon2Config{Time: 1, Memory: argon2MaxMemory + 1, Threads: 1}},
+		},
+		{
+			name:   "scrypt without cost",
+			config: &PasswordHashConfig{Algorithm: HashNameScrypt, Scrypt: ScryptConfig{Cost: 0, BlockSize: 8, Parallelism: 1}},
+		},
+		{
+			name:   "scrypt without block size",
+			config: &PasswordHashConfig{Algorithm: HashNameScrypt, Scrypt: ScryptConfig{Cost: 10, BlockSize: 0, Parallelism: 1}},
+		},
+		{
+			name:   "scrypt without parallelism",
+			config: &PasswordHashConfig{Algorithm: HashNameScrypt, Scrypt: ScryptConfig{Cost: 10, BlockSize: 8, Parallelism: 0}},
+		},
+		{
+			name:   "scrypt memory above limit",
+			config: &PasswordHashConfig{Algorithm: HashNameScrypt, Scrypt: ScryptConfig{Cost: scryptMaxCost, BlockSize: scryptMaxBlockSize, Parallelism: 1}},
+		},
+		{
+			name:   "pbkdf2 without iterations",
+			config: &PasswordHashConfig{Algorithm: HashNamePBKDF2, PBKDF2: PBKDF2Config{Iterations: 0, Hash: "sha256"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPasswordHasher(tt.config, 4)
+			if !errors.IsErrorInvalidArgument(err) {
+				t.Errorf("expected invalid argument, got %v", err)
+			}
+		})
+	}
+}
+
+func mustHash(t *testing.T, alg HashAlgorithm) *CryptoValue {
+	t.Helper()
+	value, err := Hash([]byte("Password1!"), alg)
+	if err != nil {
+		t.Fatalf("unable to hash: %v", err)
+	}
+	return value
+}
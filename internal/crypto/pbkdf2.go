@@ -0,0 +1,138 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+var _ HashAlgorithm = (*PBKDF2)(nil)
+
+const (
+	pbkdf2ID      = "pbkdf2"
+	pbkdf2SaltLen = 16
+	// pbkdf2MaxIterations limits the iterations of verified hashes, so a hash can't exhaust the cpu
+	pbkdf2MaxIterations = 5000000
+)
+
+type PBKDF2Config struct {
+	Iterations int
+	// Hash is the pseudorandom function: sha1, sha256 or sha512
+	Hash string
+}
+
+// PBKDF2 hashes and verifies pbkdf2 hashes in PHC string format
+// as well as the format of passlib (e.g. $pbkdf2-sha256$29000$<salt>$<hash>)
+type PBKDF2 struct {
+	iterations int
+	hash       string
+}
+
+func NewPBKDF2(config PBKDF2Config) (*PBKDF2, error) {
+	if config.Iterations < 1 || config.Iterations > pbkdf2MaxIterations {
+		return nil, errors.ThrowInvalidArgumentf(nil, "CRYPT-Ov2ah", "pbkdf2 iterations must be between 1 and %d", pbkdf2MaxIterations)
+	}
+	if _, _, err := pbkdf2Hash(config.Hash); err != nil {
+		return nil, err
+	}
+	return &PBKDF2{
+		iterations: config.Iterations,
+		hash:       config.Hash,
+	}, nil
+}
+
+func (p *PBKDF2) Algorithm() string {
+	return HashNamePBKDF2
+}
+
+func (p *PBKDF2) Hash(value []byte) ([]byte, error) {
+	salt, err := newSalt(pbkdf2SaltLen)
+	if err != nil {
+		return nil, err
+	}
+	newHash, keyLen, err := pbkdf2Hash(p.hash)
+	if err != nil {
+		return nil, err
+	}
+	key := pbkdf2.Key(value, salt, p.iterations, keyLen, newHash)
+	return []byte(fmt.Sprintf("$%s-%s$i=%d$%s$%s", pbkdf2ID, p.hash, p.iterations, encodePHCBase64(salt), encodePHCBase64(key))), nil
+}
+
+func (p *PBKDF2) CompareHash(hashed, value []byte) error {
+	phc, hashName, iterations, err := parsePBKDF2(hashed)
+	if err != nil {
+		return err
+	}
+	newHash, _, err := pbkdf2Hash(hashName)
+	if err != nil {
+		return err
+	}
+	return phc.compare(pbkdf2.Key(value, phc.salt, iterations, len(phc.hash), newHash))
+}
+
+func (p *PBKDF2) CheckHash(hashed []byte) error {
+	_, hashName, _, err := parsePBKDF2(hashed)
+	if err != nil {
+		return err
+	}
+	_, _, err = pbkdf2Hash(hashName)
+	return err
+}
+
+func (p *PBKDF2) Identify(hashed []byte) bool {
+	return bytes.HasPrefix(hashed, []byte("$"+pbkdf2ID+"$")) || bytes.HasPrefix(hashed, []byte("$"+pbkdf2ID+"-"))
+}
+
+// Outdated returns true if the hash wasn't created with the configured parameters
+func (p *PBKDF2) Outdated(hashed []byte) bool {
+	_, hashName, iterations, err := parsePBKDF2(hashed)
+	return err != nil || hashName != p.hash || iterations != p.iterations
+}
+
+func parsePBKDF2(hashed []byte) (phc *phcHash, hashName string, iterations int, err error) {
+	phc, err = parsePHC(hashed)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	// passlib names pbkdf2 with sha1 just pbkdf2
+	hashName = "sha1"
+	if phc.id != pbkdf2ID {
+		if !strings.HasPrefix(phc.id, pbkdf2ID+"-") {
+			return nil, "", 0, errors.ThrowInvalidArgument(nil, "CRYPT-Ahph5", "hash is not a pbkdf2 hash")
+		}
+		hashName = strings.TrimPrefix(phc.id, pbkdf2ID+"-")
+	}
+	rounds := phc.rawParams
+	if rounds == "" {
+		rounds = phc.params["i"]
+	}
+	iterations, err = strconv.Atoi(rounds)
+	if err != nil || iterations < 1 || iterations > pbkdf2MaxIterations {
+		return nil, "", 0, errors.ThrowInvalidArgument(err, "CRYPT-Ooph9", "invalid iterations in pbkdf2 hash")
+	}
+	if err = phc.checkDigest(); err != nil {
+		return nil, "", 0, err
+	}
+	return phc, hashName, iterations, nil
+}
+
+func pbkdf2Hash(name string) (func() hash.Hash, int, error) {
+	switch name {
+	case "sha1":
+		return sha1.New, sha1.Size, nil
+	case "sha256":
+		return sha256.New, sha256.Size, nil
+	case "sha512":
+		return sha512.New, sha512.Size, nil
+	}
+	return nil, 0, errors.ThrowInvalidArgumentf(nil, "CRYPT-Yoo4a", "unsupported pbkdf2 hash %q", name)
+}
@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+// minDigestLen is the minimal length of the derived key of verified hashes
+const minDigestLen = 16
+
+// phcHash is a hash in the PHC string format:
+// $<id>[$v=<version>][$<param>=<value>(,<param>=<value>)*][$<salt>[$<hash>]]
+// see https://github.com/P-H-C/phc-string-format/blob/master/phc-sf-spec.md
+type phcHash struct {
+	id      string
+	version string
+	params  map[string]string
+	// rawParams contains the parameter segment if it doesn't consist of key value pairs
+	// e.g. the rounds of passlib pbkdf2 hashes
+	rawParams string
+	salt      []byte
+	hash      []byte
+}
+
+func parsePHC(encoded []byte) (*phcHash, error) {
+	parts := strings.Split(string(encoded), "$")
+	if len(parts) < 3 || parts[0] != "" || parts[1] == "" {
+		return nil, errors.ThrowInvalidArgument(nil, "CRYPT-Oosh3", "invalid phc string")
+	}
+	phc := &phcHash{
+		id:     parts[1],
+		params: make(map[string]string),
+	}
+	parts = parts[2:]
+	if strings.HasPrefix(parts[0], "v=") {
+		phc.version = strings.TrimPrefix(parts[0], "v=")
+		parts = parts[1:]
+	}
+	if len(parts) > 2 {
+		for _, param := range strings.Split(parts[0], ",") {
+			key, value, ok := strings.Cut(param, "=")
+			if !ok {
+				phc.rawParams = parts[0]
+				break
+			}
+			phc.params[key] = value
+		}
+		parts = parts[1:]
+	}
+	if len(parts) != 2 {
+		return nil, errors.ThrowInvalidArgument(nil, "CRYPT-ahT6o", "invalid phc string")
+	}
+	var err error
+	if phc.salt, err = decodePHCBase64(parts[0]); err != nil {
+		return nil, errors.ThrowInvalidArgument(err, "CRYPT-Iep2o", "invalid salt in phc string")
+	}
+	if phc.hash, err = decodePHCBase64(parts[1]); err != nil {
+		return nil, errors.ThrowInvalidArgument(err, "CRYPT-eiN1u", "invalid hash in phc string")
+	}
+	return phc, nil
+}
+
+// uintParam returns the parameter if it's within min and max
+func (p *phcHash) uintParam(name string, min, max uint64) (uint64, error) {
+	value, err := strconv.ParseUint(p.params[name], 10, 64)
+	if err != nil {
+		return 0, errors.ThrowInvalidArgumentf(err, "CRYPT-Lae4u", "invalid parameter %s in phc string", name)
+	}
+	if value < min || value > max {
+		return 0, errors.ThrowInvalidArgumentf(nil, "CRYPT-ooG4e", "parameter %s in phc string must be between %d and %d", name, min, max)
+	}
+	return value, nil
+}
+
+// checkDigest prevents the comparison with empty or short digests, which match too easily
+func (p *phcHash) checkDigest() error {
+	if len(p.hash) < minDigestLen {
+		return errors.ThrowInvalidArgument(nil, "CRYPT-Ci7ai", "digest in phc string is too short")
+	}
+	return nil
+}
+
+// compare checks the derived key against the hash of the phc string in constant time
+func (p *phcHash) compare(derived []byte) error {
+	if subtle.ConstantTimeCompare(p.hash, derived) != 1 {
+		return errors.ThrowInvalidArgument(nil, "CRYPT-Ohd7u", "hash and password don't match")
+	}
+	return nil
+}
+
+// decodePHCBase64 decodes the standard base64 encoding without padding of the PHC format
+// as well as the adapted encoding of passlib which uses '.' instead of '+'
+func decodePHCBase64(encoded string) ([]byte, error) {
+	encoded = strings.TrimRight(strings.ReplaceAll(encoded, ".", "+"), "=")
+	return base64.RawStdEncoding.DecodeString(encoded)
+}
+
+func encodePHCBase64(value []byte) string {
+	return base64.RawStdEncoding.EncodeToString(value)
+}
+
+func newSalt(length int) ([]byte, error) {
+	salt := make([]byte, length)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.ThrowInternal(err, "CRYPT-Xoo5a", "unable to generate salt")
+	}
+	return salt, nil
+}
@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -72,6 +73,24 @@ func GenerateCertificate(bits int, caPrivateKey *rsa.PrivateKey, ca []byte, info
 }
 
 func generateCertificate(bits int, caPrivateKey *rsa.PrivateKey, ca []byte, informations *CertificateInformations) (*rsa.PrivateKey, *rsa.PublicKey, []byte, error) {
+	certPrivKey, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var caSigner crypto.Signer
+	if caPrivateKey != nil {
+		caSigner = caPrivateKey
+	}
+	certPem, err := createCertificate(certPrivKey, caSigner, ca, informations)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return certPrivKey, &certPrivKey.PublicKey, certPem, nil
+}
+
+// createCertificate creates the certificate of the private key signed by the CA,
+// the certificate is self-signed if no CA is passed
+func createCertificate(privateKey, caPrivateKey crypto.Signer, ca []byte, informations *CertificateInformations) ([]byte, error) {
 	notBefore := time.Now()
 	if !informations.NotBefore.IsZero() {
 		notBefore = informations.NotBefore
@@ -88,43 +107,35 @@ func generateCertificate(bits int, caPrivateKey *rsa.PrivateKey, ca []byte, info
 		ExtKeyUsage: informations.ExtKeyUsage,
 	}
 
-	certPrivKey, err := rsa.GenerateKey(rand.Reader, bits)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	certBytes := make([]byte, 0)
+	var (
+		certBytes []byte
+		err       error
+	)
 	if ca == nil {
 		cert.IsCA = true
 		cert.BasicConstraintsValid = true
 
-		certBytes, err = x509.CreateCertificate(rand.Reader, cert, cert, &certPrivKey.PublicKey, certPrivKey)
+		certBytes, err = x509.CreateCertificate(rand.Reader, cert, cert, privateKey.Public(), privateKey)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, err
 		}
 	} else {
 		caCert, err := x509.ParseCertificate(ca)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, err
 		}
 
-		certBytes, err = x509.CreateCertificate(rand.Reader, cert, caCert, &certPrivKey.PublicKey, caPrivateKey)
+		certBytes, err = x509.CreateCertificate(rand.Reader, cert, caCert, privateKey.Public(), caPrivateKey)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, err
 		}
 	}
 
 	x509Cert, err := x509.ParseCertificate(certBytes)
 	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	certPem, err := CertificateToBytes(x509Cert)
-	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
-
-	return certPrivKey, &certPrivKey.PublicKey, certPem, nil
+	return CertificateToBytes(x509Cert)
 }
 
 func PrivateKeyToBytes(priv *rsa.PrivateKey) []byte {
@@ -0,0 +1,134 @@
+package crypto
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+var _ HashAlgorithm = (*Scrypt)(nil)
+
+const (
+	scryptID      = "scrypt"
+	scryptSaltLen = 16
+	scryptKeyLen  = 32
+
+	// the parameters of verified hashes are limited, so a hash can't exhaust the resources
+	scryptMaxCost        = 20
+	scryptMaxBlockSize   = 32
+	scryptMaxParallelism = 16
+	// scryptMaxMemory limits the memory of 128 * r * N bytes
+	scryptMaxMemory = 1 << 30
+)
+
+type ScryptConfig struct {
+	// Cost is the base 2 logarithm of the CPU/memory cost parameter N
+	Cost uint8
+	// BlockSize is the block size parameter r
+	BlockSize int
+	// Parallelism is the parallelization parameter p
+	Parallelism int
+}
+
+// Scrypt hashes and verifies scrypt hashes in PHC string format
+type Scrypt struct {
+	cost        uint8
+	blockSize   int
+	parallelism int
+}
+
+// NewScrypt validates the config with the limits of verified hashes
+func NewScrypt(config ScryptConfig) (*Scrypt, error) {
+	if config.Cost < 1 || config.Cost > scryptMaxCost {
+		return nil, errors.ThrowInvalidArgumentf(nil, "CRYPT-Ohr3a", "scrypt cost must be between 1 and %d", scryptMaxCost)
+	}
+	if config.BlockSize < 1 || config.BlockSize > scryptMaxBlockSize {
+		return nil, errors.ThrowInvalidArgumentf(nil, "CRYPT-Iej5u", "scrypt block size must be between 1 and %d", scryptMaxBlockSize)
+	}
+	if config.Parallelism < 1 || config.Parallelism > scryptMaxParallelism {
+		return nil, errors.ThrowInvalidArgumentf(nil, "CRYPT-thai4", "scrypt parallelism must be between 1 and %d", scryptMaxParallelism)
+	}
+	if 128*config.BlockSize<<config.Cost > scryptMaxMemory {
+		return nil, errors.ThrowInvalidArgument(nil, "CRYPT-Xei0a", "scrypt parameters exceed the memory limit")
+	}
+	return &Scrypt{
+		cost:        config.Cost,
+		blockSize:   config.BlockSize,
+		parallelism: config.Parallelism,
+	}, nil
+}
+
+func (s *Scrypt) Algorithm() string {
+	return HashNameScrypt
+}
+
+func (s *Scrypt) Hash(value []byte) ([]byte, error) {
+	salt, err := newSalt(scryptSaltLen)
+	if err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(value, salt, 1<<s.cost, s.blockSize, s.parallelism, scryptKeyLen)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "CRYPT-ooX6e", "unable to hash with scrypt")
+	}
+	return []byte(fmt.Sprintf("$%s$ln=%d,r=%d,p=%d$%s$%s", scryptID, s.cost, s.blockSize, s.parallelism, encodePHCBase64(salt), encodePHCBase64(key))), nil
+}
+
+func (s *Scrypt) CompareHash(hashed, value []byte) error {
+	phc, cost, blockSize, parallelism, err := parseScrypt(hashed)
+	if err != nil {
+		return err
+	}
+	key, err := scrypt.Key(value, phc.salt, 1<<cost, blockSize, parallelism, len(phc.hash))
+	if err != nil {
+		return errors.ThrowInvalidArgument(err, "CRYPT-Ied7a", "invalid scrypt parameters")
+	}
+	return phc.compare(key)
+}
+
+func (s *Scrypt) CheckHash(hashed []byte) error {
+	_, _, _, _, err := parseScrypt(hashed)
+	return err
+}
+
+func (s *Scrypt) Identify(hashed []byte) bool {
+	return bytes.HasPrefix(hashed, []byte("$"+scryptID+"$"))
+}
+
+// Outdated returns true if the hash wasn't created with the configured parameters
+func (s *Scrypt) Outdated(hashed []byte) bool {
+	_, cost, blockSize, parallelism, err := parseScrypt(hashed)
+	return err != nil || cost != s.cost || blockSize != s.blockSize || parallelism != s.parallelism
+}
+
+func parseScrypt(hashed []byte) (phc *phcHash, cost uint8, blockSize, parallelism int, err error) {
+	phc, err = parsePHC(hashed)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	if phc.id != scryptID {
+		return nil, 0, 0, 0, errors.ThrowInvalidArgument(nil, "CRYPT-ieX4o", "hash is not a scrypt hash")
+	}
+	if err = phc.checkDigest(); err != nil {
+		return nil, 0, 0, 0, err
+	}
+	ln, err := phc.uintParam("ln", 1, scryptMaxCost)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	r, err := phc.uintParam("r", 1, scryptMaxBlockSize)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	p, err := phc.uintParam("p", 1, scryptMaxParallelism)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	if 128*r<<ln > scryptMaxMemory {
+		return nil, 0, 0, 0, errors.ThrowInvalidArgument(nil, "CRYPT-Eu6ai", "scrypt parameters exceed the memory limit")
+	}
+	return phc, uint8(ln), int(r), int(p), nil
+}
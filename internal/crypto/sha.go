@@ -0,0 +1,78 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"hash"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+// shaSchemes are the LDAP password schemes (RFC 2307) of (salted) sha hashes
+var shaSchemes = []struct {
+	prefix string
+	hash   func() hash.Hash
+	size   int
+	salted bool
+}{
+	{prefix: "{SSHA512}", hash: sha512.New, size: sha512.Size, salted: true},
+	{prefix: "{SSHA256}", hash: sha256.New, size: sha256.Size, salted: true},
+	{prefix: "{SSHA}", hash: sha1.New, size: sha1.Size, salted: true},
+	{prefix: "{SHA512}", hash: sha512.New, size: sha512.Size},
+	{prefix: "{SHA256}", hash: sha256.New, size: sha256.Size},
+	{prefix: "{SHA}", hash: sha1.New, size: sha1.Size},
+}
+
+// SHA verifies legacy (salted) sha hashes in the LDAP format, e.g. {SSHA256}<base64 of hash and salt>.
+// It's only used to verify imported hashes and can't create new ones
+type SHA struct{}
+
+func (SHA) Algorithm() string {
+	return HashNameSHA
+}
+
+func (SHA) Identify(hashed []byte) bool {
+	for _, scheme := range shaSchemes {
+		if bytes.HasPrefix(hashed, []byte(scheme.prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (SHA) CompareHash(hashed, value []byte) error {
+	newHash, digest, salt, err := parseSHA(hashed)
+	if err != nil {
+		return err
+	}
+	h := newHash()
+	h.Write(value)
+	h.Write(salt)
+	if subtle.ConstantTimeCompare(digest, h.Sum(nil)) != 1 {
+		return errors.ThrowInvalidArgument(nil, "CRYPT-aiZ3o", "hash and password don't match")
+	}
+	return nil
+}
+
+func (SHA) CheckHash(hashed []byte) error {
+	_, _, _, err := parseSHA(hashed)
+	return err
+}
+
+func parseSHA(hashed []byte) (newHash func() hash.Hash, digest, salt []byte, err error) {
+	for _, scheme := range shaSchemes {
+		if !bytes.HasPrefix(hashed, []byte(scheme.prefix)) {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(string(hashed[len(scheme.prefix):]))
+		if err != nil || len(decoded) < scheme.size || !scheme.salted && len(decoded) != scheme.size {
+			return nil, nil, nil, errors.ThrowInvalidArgument(err, "CRYPT-Nee4u", "invalid sha hash")
+		}
+		return scheme.hash, decoded[:scheme.size], decoded[scheme.size:], nil
+	}
+	return nil, nil, nil, errors.ThrowInvalidArgument(nil, "CRYPT-oe1Ie", "hash is not a sha hash")
+}
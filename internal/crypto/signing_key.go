@@ -0,0 +1,227 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+// signature algorithms of the signing keys as defined in RFC 7518 and RFC 8037
+const (
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmPS256 = "PS256"
+	SigningAlgorithmES256 = "ES256"
+	SigningAlgorithmES384 = "ES384"
+	SigningAlgorithmEdDSA = "EdDSA"
+)
+
+var SigningAlgorithms = []string{
+	SigningAlgorithmRS256,
+	SigningAlgorithmPS256,
+	SigningAlgorithmES256,
+	SigningAlgorithmES384,
+	SigningAlgorithmEdDSA,
+}
+
+func IsSigningAlgorithm(algorithm string) bool {
+	for _, alg := range SigningAlgorithms {
+		if alg == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateSigningKeyPair generates a key pair for the signature algorithm,
+// the bits are only used for the RSA based algorithms
+func GenerateSigningKeyPair(algorithm string, bits int) (crypto.Signer, crypto.PublicKey, error) {
+	var (
+		privateKey crypto.Signer
+		err        error
+	)
+	switch algorithm {
+	case SigningAlgorithmRS256, SigningAlgorithmPS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, bits)
+	case SigningAlgorithmES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case SigningAlgorithmES384:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case SigningAlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, nil, errors.ThrowInvalidArgumentf(nil, "CRYPT-Ohqu3", "unsupported signing algorithm %q", algorithm)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, privateKey.Public(), nil
+}
+
+func GenerateEncryptedSigningKeyPair(algorithm string, bits int, alg EncryptionAlgorithm) (*CryptoValue, *CryptoValue, error) {
+	privateKey, publicKey, err := GenerateSigningKeyPair(algorithm, bits)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encryptSigningKeyPair(privateKey, publicKey, alg)
+}
+
+func encryptSigningKeyPair(privateKey crypto.Signer, publicKey crypto.PublicKey, alg EncryptionAlgorithm) (*CryptoValue, *CryptoValue, error) {
+	privateKeyBytes, err := PrivateSigningKeyToBytes(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptedPrivateKey, err := Encrypt(privateKeyBytes, alg)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKeyBytes, err := PublicSigningKeyToBytes(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptedPublicKey, err := Encrypt(publicKeyBytes, alg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encryptedPrivateKey, encryptedPublicKey, nil
+}
+
+// GenerateEncryptedSigningKeyPairWithCACertificate generates a key pair of the signature algorithm with a self-signed CA certificate
+func GenerateEncryptedSigningKeyPairWithCACertificate(algorithm string, bits int, keyAlg, certAlg EncryptionAlgorithm, informations *CertificateInformations) (*CryptoValue, *CryptoValue, *CryptoValue, error) {
+	return generateEncryptedSigningKeyPairWithCertificate(algorithm, bits, keyAlg, certAlg, nil, nil, informations)
+}
+
+// GenerateEncryptedSigningKeyPairWithCertificate generates a key pair of the signature algorithm with a certificate signed by the CA,
+// the key of the CA might be of another algorithm
+func GenerateEncryptedSigningKeyPairWithCertificate(algorithm string, bits int, keyAlg, certAlg EncryptionAlgorithm, caPrivateKey crypto.Signer, caCertificate []byte, informations *CertificateInformations) (*CryptoValue, *CryptoValue, *CryptoValue, error) {
+	if caPrivateKey == nil || caCertificate == nil {
+		return nil, nil, nil, errors.ThrowInvalidArgument(nil, "CRYPT-oHa4i", "ca certificate and key required")
+	}
+	return generateEncryptedSigningKeyPairWithCertificate(algorithm, bits, keyAlg, certAlg, caPrivateKey, caCertificate, informations)
+}
+
+func generateEncryptedSigningKeyPairWithCertificate(algorithm string, bits int, keyAlg, certAlg EncryptionAlgorithm, caPrivateKey crypto.Signer, caCertificate []byte, informations *CertificateInformations) (*CryptoValue, *CryptoValue, *CryptoValue, error) {
+	privateKey, publicKey, err := GenerateSigningKeyPair(algorithm, bits)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	certificate, err := createCertificate(privateKey, caPrivateKey, caCertificate, informations)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	encryptedPrivateKey, encryptedPublicKey, err := encryptSigningKeyPair(privateKey, publicKey, keyAlg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	encryptedCertificate, err := Encrypt(certificate, certAlg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return encryptedPrivateKey, encryptedPublicKey, encryptedCertificate, nil
+}
+
+// PrivateSigningKeyToBytes encodes RSA keys as PKCS #1 as before and all other keys as PKCS #8
+func PrivateSigningKeyToBytes(privateKey crypto.Signer) ([]byte, error) {
+	if rsaKey, ok := privateKey.(*rsa.PrivateKey); ok {
+		return PrivateKeyToBytes(rsaKey), nil
+	}
+	data, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: data,
+	}), nil
+}
+
+func PublicSigningKeyToBytes(publicKey crypto.PublicKey) ([]byte, error) {
+	if rsaKey, ok := publicKey.(*rsa.PublicKey); ok {
+		return PublicKeyToBytes(rsaKey)
+	}
+	data, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: data,
+	}), nil
+}
+
+func BytesToPrivateSigningKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrEmpty
+	}
+	if block.Type == "RSA PRIVATE KEY" {
+		return BytesToPrivateKey(data)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.ThrowInternal(nil, "CRYPT-Eiw1o", "private key is not a signing key")
+	}
+	return signer, nil
+}
+
+func BytesToPublicSigningKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrEmpty
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// IsSigningKeyOfAlgorithm checks if the public key can verify signatures of the algorithm
+func IsSigningKeyOfAlgorithm(publicKey crypto.PublicKey, algorithm string) bool {
+	switch algorithm {
+	case SigningAlgorithmRS256, SigningAlgorithmPS256:
+		_, ok := publicKey.(*rsa.PublicKey)
+		return ok
+	case SigningAlgorithmES256, SigningAlgorithmES384:
+		_, ok := publicKey.(*ecdsa.PublicKey)
+		return ok
+	case SigningAlgorithmEdDSA:
+		_, ok := publicKey.(ed25519.PublicKey)
+		return ok
+	default:
+		return false
+	}
+}
+
+// FindSigningKey returns the signing key for the key id and the algorithm of a signature.
+// Unlike the oidc library it matches the ed25519 keys of EdDSA signatures.
+// If the signature has no key id, the key is only returned if it's the only candidate
+func FindSigningKey(keyID, algorithm string, keys ...jose.JSONWebKey) (jose.JSONWebKey, error) {
+	var candidates []jose.JSONWebKey
+	for _, key := range keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if !IsSigningKeyOfAlgorithm(key.Key, algorithm) {
+			continue
+		}
+		if keyID != "" && key.KeyID == keyID {
+			return key, nil
+		}
+		if keyID == "" || key.KeyID == "" {
+			candidates = append(candidates, key)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	return jose.JSONWebKey{}, errors.ThrowNotFoundf(nil, "CRYPT-aeX7u", "no signing key found for key id %q and algorithm %q", keyID, algorithm)
+}
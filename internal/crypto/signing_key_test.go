@@ -0,0 +1,161 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+func TestGenerateSigningKeyPair(t *testing.T) {
+	tests := []struct {
+		algorithm   string
+		wantPrivate interface{}
+		wantErr     func(error) bool
+	}{
+		{SigningAlgorithmRS256, &rsa.PrivateKey{}, nil},
+		{SigningAlgorithmPS256, &rsa.PrivateKey{}, nil},
+		{SigningAlgorithmES256, &ecdsa.PrivateKey{}, nil},
+		{SigningAlgorithmES384, &ecdsa.PrivateKey{}, nil},
+		{SigningAlgorithmEdDSA, ed25519.PrivateKey{}, nil},
+		{"HS256", nil, errors.IsErrorInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			privateKey, publicKey, err := GenerateSigningKeyPair(tt.algorithm, 2048)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.wantPrivate, privateKey)
+
+			privateKeyBytes, err := PrivateSigningKeyToBytes(privateKey)
+			require.NoError(t, err)
+			parsedPrivateKey, err := BytesToPrivateSigningKey(privateKeyBytes)
+			require.NoError(t, err)
+			assert.Equal(t, privateKey.Public(), parsedPrivateKey.Public())
+
+			publicKeyBytes, err := PublicSigningKeyToBytes(publicKey)
+			require.NoError(t, err)
+			parsedPublicKey, err := BytesToPublicSigningKey(publicKeyBytes)
+			require.NoError(t, err)
+			assert.True(t, publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(parsedPublicKey))
+		})
+	}
+}
+
+func TestBytesToPrivateSigningKey_rsa(t *testing.T) {
+	// keys generated before the support of other algorithms are PKCS #1 encoded
+	privateKey, _, err := GenerateKeyPair(2048)
+	require.NoError(t, err)
+	parsed, err := BytesToPrivateSigningKey(PrivateKeyToBytes(privateKey))
+	require.NoError(t, err)
+	assert.Equal(t, privateKey.Public(), parsed.Public())
+}
+
+func TestFindSigningKey(t *testing.T) {
+	keys := make([]jose.JSONWebKey, len(SigningAlgorithms))
+	for i, algorithm := range SigningAlgorithms {
+		_, publicKey, err := GenerateSigningKeyPair(algorithm, 2048)
+		require.NoError(t, err)
+		keys[i] = jose.JSONWebKey{KeyID: algorithm, Algorithm: algorithm, Use: "sig", Key: publicKey}
+	}
+	for _, algorithm := range SigningAlgorithms {
+		t.Run(algorithm, func(t *testing.T) {
+			key, err := FindSigningKey(algorithm, algorithm, keys...)
+			require.NoError(t, err)
+			assert.Equal(t, algorithm, key.KeyID)
+		})
+	}
+	t.Run("key of other algorithm, error", func(t *testing.T) {
+		_, err := FindSigningKey(SigningAlgorithmEdDSA, SigningAlgorithmES256, keys...)
+		assert.True(t, errors.IsNotFound(err))
+	})
+	t.Run("without key id, single candidate", func(t *testing.T) {
+		key, err := FindSigningKey("", SigningAlgorithmEdDSA, keys...)
+		require.NoError(t, err)
+		assert.Equal(t, SigningAlgorithmEdDSA, key.KeyID)
+	})
+	t.Run("without key id, multiple candidates, error", func(t *testing.T) {
+		_, err := FindSigningKey("", SigningAlgorithmRS256, keys...)
+		assert.True(t, errors.IsNotFound(err))
+	})
+	t.Run("encryption key, error", func(t *testing.T) {
+		encryptionKey := keys[4]
+		encryptionKey.Use = "enc"
+		_, err := FindSigningKey(SigningAlgorithmEdDSA, SigningAlgorithmEdDSA, encryptionKey)
+		assert.True(t, errors.IsNotFound(err))
+	})
+}
+
+func TestGenerateEncryptedSigningKeyPairWithCertificate(t *testing.T) {
+	alg := CreateMockEncryptionAlg(gomock.NewController(t))
+	informations := func(name string) *CertificateInformations {
+		return &CertificateInformations{
+			SerialNumber: big.NewInt(1),
+			CommonName:   name,
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		}
+	}
+	decryptCertificate := func(t *testing.T, value *CryptoValue) *x509.Certificate {
+		t.Helper()
+		data, err := Decrypt(value, alg)
+		require.NoError(t, err)
+		der, err := BytesToCertificate(data)
+		require.NoError(t, err)
+		certificate, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		return certificate
+	}
+	for _, caAlgorithm := range SigningAlgorithms {
+		for _, algorithm := range SigningAlgorithms {
+			t.Run(caAlgorithm+" "+algorithm, func(t *testing.T) {
+				caPrivateCrypto, _, caCertificateCrypto, err := GenerateEncryptedSigningKeyPairWithCACertificate(caAlgorithm, 2048, alg, alg, informations("ca"))
+				require.NoError(t, err)
+				caPrivateKeyData, err := Decrypt(caPrivateCrypto, alg)
+				require.NoError(t, err)
+				caPrivateKey, err := BytesToPrivateSigningKey(caPrivateKeyData)
+				require.NoError(t, err)
+				caCertificateData, err := Decrypt(caCertificateCrypto, alg)
+				require.NoError(t, err)
+				caCertificate, err := BytesToCertificate(caCertificateData)
+				require.NoError(t, err)
+
+				privateCrypto, publicCrypto, certificateCrypto, err := GenerateEncryptedSigningKeyPairWithCertificate(algorithm, 2048, alg, alg, caPrivateKey, caCertificate, informations("leaf"))
+				require.NoError(t, err)
+
+				certificate := decryptCertificate(t, certificateCrypto)
+				assert.NoError(t, certificate.CheckSignatureFrom(decryptCertificate(t, caCertificateCrypto)))
+				assert.True(t, IsSigningKeyOfAlgorithm(certificate.PublicKey, algorithm))
+
+				privateKeyData, err := Decrypt(privateCrypto, alg)
+				require.NoError(t, err)
+				privateKey, err := BytesToPrivateSigningKey(privateKeyData)
+				require.NoError(t, err)
+				publicKeyData, err := Decrypt(publicCrypto, alg)
+				require.NoError(t, err)
+				publicKey, err := BytesToPublicSigningKey(publicKeyData)
+				require.NoError(t, err)
+				assert.True(t, publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(privateKey.Public()))
+				assert.True(t, publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(certificate.PublicKey))
+			})
+		}
+	}
+	t.Run("without ca, error", func(t *testing.T) {
+		_, _, _, err := GenerateEncryptedSigningKeyPairWithCertificate(SigningAlgorithmES256, 0, alg, alg, nil, nil, informations("leaf"))
+		assert.True(t, errors.IsErrorInvalidArgument(err))
+	})
+}
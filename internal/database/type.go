@@ -93,3 +93,26 @@ func (m Map[V]) Value() (driver.Value, error) {
 	}
 	return json.Marshal(m)
 }
+
+// JSONArray is a list stored as JSONB
+type JSONArray[V any] []V
+
+// Scan implements the [database/sql.Scanner] interface.
+func (a *JSONArray[V]) Scan(src any) error {
+	bytea := new(pgtype.Bytea)
+	if err := bytea.Scan(src); err != nil {
+		return err
+	}
+	if len(bytea.Bytes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bytea.Bytes, a)
+}
+
+// Value implements the [database/sql/driver.Valuer] interface.
+func (a JSONArray[V]) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(a)
+}
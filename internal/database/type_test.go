@@ -117,3 +117,109 @@ func TestMap_Value(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONArray_Scan(t *testing.T) {
+	type args struct {
+		src any
+	}
+	type res[V any] struct {
+		want JSONArray[V]
+		err  bool
+	}
+	type testCase[V any] struct {
+		name string
+		a    JSONArray[V]
+		args args
+		res[V]
+	}
+	tests := []testCase[string]{
+		{
+			"invalid",
+			JSONArray[string]{},
+			args{src: "invalid"},
+			res[string]{
+				want: JSONArray[string]{},
+				err:  true,
+			},
+		},
+		{
+			"null",
+			JSONArray[string]{},
+			args{src: nil},
+			res[string]{
+				want: JSONArray[string]{},
+			},
+		},
+		{
+			"empty",
+			JSONArray[string]{},
+			args{src: []byte(`[]`)},
+			res[string]{
+				want: JSONArray[string]{},
+			},
+		},
+		{
+			"set",
+			JSONArray[string]{},
+			args{src: []byte(`["first", "second"]`)},
+			res[string]{
+				want: JSONArray[string]{"first", "second"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.a.Scan(tt.args.src); (err != nil) != tt.res.err {
+				t.Errorf("Scan() error = %v, wantErr %v", err, tt.res.err)
+			}
+			assert.Equal(t, tt.res.want, tt.a)
+		})
+	}
+}
+
+func TestJSONArray_Value(t *testing.T) {
+	type res struct {
+		want driver.Value
+		err  bool
+	}
+	type testCase[V any] struct {
+		name string
+		a    JSONArray[V]
+		res  res
+	}
+	tests := []testCase[string]{
+		{
+			"nil",
+			nil,
+			res{
+				want: nil,
+			},
+		},
+		{
+			"empty",
+			JSONArray[string]{},
+			res{
+				want: nil,
+			},
+		},
+		{
+			"set",
+			JSONArray[string]{"first", "second"},
+			res{
+				want: driver.Value([]byte(`["first","second"]`)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Value()
+			if tt.res.err {
+				assert.Error(t, err)
+			}
+			if !tt.res.err {
+				require.NoError(t, err)
+				assert.Equalf(t, tt.res.want, got, "Value()")
+			}
+		})
+	}
+}
@@ -71,6 +71,12 @@ type ExternalUser struct {
 	Phone             PhoneNumber
 	IsPhoneVerified   bool
 	Metadatas         []*Metadata
+	// Groups are the groups of the user provided by an LDAP identity provider,
+	// they're kept until the user is created or linked to map them to user grants
+	Groups []string
+	// Roles are the project roles of the user mapped from the claims of the identity provider,
+	// they're kept until the user is created or linked to map them to user grants
+	Roles map[string][]string
 }
 
 type Prompt int32
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"net/url"
+)
+
+type EventWebhookState int32
+
+const (
+	EventWebhookStateUnspecified EventWebhookState = iota
+	EventWebhookStateActive
+	EventWebhookStateRemoved
+)
+
+func (s EventWebhookState) Exists() bool {
+	return s != EventWebhookStateUnspecified && s != EventWebhookStateRemoved
+}
+
+// EventWebhookDeliveryState is the state of a delivery of an event to an event webhook
+type EventWebhookDeliveryState int32
+
+const (
+	EventWebhookDeliveryStateUnspecified EventWebhookDeliveryState = iota
+	// EventWebhookDeliveryStatePending deliveries are (re)tried by the delivery worker
+	EventWebhookDeliveryStatePending
+	// EventWebhookDeliveryStateFailed deliveries exhausted their attempts and are kept as dead letters
+	EventWebhookDeliveryStateFailed
+)
+
+// EventWebhook delivers the events of the instance with one of the EventTypes to the CallURL
+type EventWebhook struct {
+	ID         string
+	Name       string
+	CallURL    string
+	EventTypes []string
+}
+
+func (w *EventWebhook) IsValid() bool {
+	if w.Name == "" || len(w.EventTypes) == 0 {
+		return false
+	}
+	for _, eventType := range w.EventTypes {
+		if eventType == "" {
+			return false
+		}
+	}
+	callURL, err := url.Parse(w.CallURL)
+	return err == nil && (callURL.Scheme == "https" || callURL.Scheme == "http") && callURL.Host != ""
+}
@@ -36,6 +36,7 @@ const (
 	IDPTypeGitLab
 	IDPTypeGitLabSelfHosted
 	IDPTypeGoogle
+	IDPTypeSAML
 )
 
 func (t IDPType) GetCSSClass() string {
@@ -53,7 +54,8 @@ func (t IDPType) GetCSSClass() string {
 		IDPTypeJWT,
 		IDPTypeOAuth,
 		IDPTypeLDAP,
-		IDPTypeAzureAD:
+		IDPTypeAzureAD,
+		IDPTypeSAML:
 		fallthrough
 	default:
 		return ""
@@ -84,7 +86,8 @@ func (t IDPType) DisplayName() string {
 		IDPTypeLDAP,
 		IDPTypeAzureAD,
 		IDPTypeGitHubEnterprise,
-		IDPTypeGitLabSelfHosted:
+		IDPTypeGitLabSelfHosted,
+		IDPTypeSAML:
 		fallthrough
 	default:
 		// we should never get here, so log it
@@ -93,6 +96,50 @@ func (t IDPType) DisplayName() string {
 	}
 }
 
+type SAMLBinding int32
+
+const (
+	SAMLBindingUnspecified SAMLBinding = iota
+	SAMLBindingPost
+	SAMLBindingRedirect
+
+	samlBindingCount
+)
+
+const (
+	SAMLBindingPostURN     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	SAMLBindingRedirectURN = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+)
+
+func (b SAMLBinding) Valid() bool {
+	return b > SAMLBindingUnspecified && b < samlBindingCount
+}
+
+// URN returns the identifier of the binding used in the SAML messages and metadata
+func (b SAMLBinding) URN() string {
+	switch b {
+	case SAMLBindingPost:
+		return SAMLBindingPostURN
+	case SAMLBindingRedirect:
+		return SAMLBindingRedirectURN
+	case SAMLBindingUnspecified:
+		fallthrough
+	default:
+		return ""
+	}
+}
+
+func SAMLBindingFromURN(urn string) SAMLBinding {
+	switch urn {
+	case SAMLBindingPostURN:
+		return SAMLBindingPost
+	case SAMLBindingRedirectURN:
+		return SAMLBindingRedirect
+	default:
+		return SAMLBindingUnspecified
+	}
+}
+
 type IDPIntentState int32
 
 const (
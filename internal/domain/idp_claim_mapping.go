@@ -0,0 +1,85 @@
+package domain
+
+import "strings"
+
+// IDPClaimMappingTarget defines which information of the user is set by an [IDPClaimMapping]
+type IDPClaimMappingTarget int32
+
+const (
+	IDPClaimMappingTargetUnspecified IDPClaimMappingTarget = iota
+	IDPClaimMappingTargetFirstName
+	IDPClaimMappingTargetLastName
+	IDPClaimMappingTargetDisplayName
+	IDPClaimMappingTargetNickName
+	IDPClaimMappingTargetPreferredUsername
+	IDPClaimMappingTargetEmail
+	IDPClaimMappingTargetEmailVerified
+	IDPClaimMappingTargetPhone
+	IDPClaimMappingTargetPhoneVerified
+	IDPClaimMappingTargetPreferredLanguage
+	IDPClaimMappingTargetAvatarURL
+	IDPClaimMappingTargetProfile
+	IDPClaimMappingTargetMetadata
+	IDPClaimMappingTargetRole
+
+	idpClaimMappingTargetCount
+)
+
+func (t IDPClaimMappingTarget) Valid() bool {
+	return t > IDPClaimMappingTargetUnspecified && t < idpClaimMappingTargetCount
+}
+
+// IDPClaimTransform is applied to the value(s) of a claim before it is mapped
+type IDPClaimTransform int32
+
+const (
+	IDPClaimTransformUnspecified IDPClaimTransform = iota
+	IDPClaimTransformLowercase
+	IDPClaimTransformUppercase
+	IDPClaimTransformTrim
+	// IDPClaimTransformSplit splits every value by the separator of the mapping (default `,`)
+	IDPClaimTransformSplit
+
+	idpClaimTransformCount
+)
+
+func (t IDPClaimTransform) Valid() bool {
+	return t > IDPClaimTransformUnspecified && t < idpClaimTransformCount
+}
+
+// IDPClaimMapping maps a claim (respectively attribute) of the user information of an identity provider
+// to a profile field, a metadata entry or the roles of a project.
+//
+// The Claim is either the name of the claim, a path of nested claims separated by dots (e.g. `address.country`)
+// or a JSONPath starting with `$` (e.g. `$.groups[*]` or `$['https://example.com/roles']`).
+// The Key is the metadata key (target metadata) or the id of the project (target role), whose roles are the values of the claim.
+// The Roles are the role keys of the project, which are managed by a mapping with target role:
+// values of the claim, which aren't part of it, are ignored and all other roles of the user are kept.
+// If the claim is missing or empty, the Default is used.
+type IDPClaimMapping struct {
+	Claim      string                `json:"claim"`
+	Target     IDPClaimMappingTarget `json:"target"`
+	Key        string                `json:"key,omitempty"`
+	Default    string                `json:"default,omitempty"`
+	Transforms []IDPClaimTransform   `json:"transforms,omitempty"`
+	Separator  string                `json:"separator,omitempty"`
+	Roles      []string              `json:"roles,omitempty"`
+}
+
+func (m *IDPClaimMapping) IsValid() bool {
+	if m == nil || strings.TrimSpace(m.Claim) == "" || !m.Target.Valid() {
+		return false
+	}
+	if (m.Target == IDPClaimMappingTargetMetadata || m.Target == IDPClaimMappingTargetRole) && strings.TrimSpace(m.Key) == "" {
+		return false
+	}
+	if m.Target == IDPClaimMappingTargetRole && len(m.Roles) == 0 {
+		return false
+	}
+	for _, transform := range m.Transforms {
+		if !transform.Valid() {
+			return false
+		}
+	}
+	return true
+}
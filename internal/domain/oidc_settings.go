@@ -16,6 +16,8 @@ type OIDCSettings struct {
 	IdTokenLifetime            time.Duration
 	RefreshTokenIdleExpiration time.Duration
 	RefreshTokenExpiration     time.Duration
+	// SigningKeyAlgorithm of the signing keys, the default of the system is used if empty
+	SigningKeyAlgorithm string
 }
 
 type OIDCSettingsState int32
@@ -1,12 +1,42 @@
 package domain
 
 import (
+	"time"
+
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
 )
 
+// MaxPasswordHistoryCount limits the passwords to compare a new password with,
+// every comparison costs the time of a password hash
+const MaxPasswordHistoryCount = 10
+
 type PasswordAgePolicy struct {
 	models.ObjectRoot
 
 	MaxAgeDays     uint64
 	ExpireWarnDays uint64
+	// MinAgeDays defines the time a password has to be used before the user can change it again
+	MinAgeDays uint64
+	// HistoryCount defines the number of recent passwords (the current included) which can't be reused
+	HistoryCount uint64
+}
+
+func (p *PasswordAgePolicy) IsValid() error {
+	return ValidatePasswordHistoryCount(p.HistoryCount)
+}
+
+func ValidatePasswordHistoryCount(historyCount uint64) error {
+	if historyCount > MaxPasswordHistoryCount {
+		return caos_errs.ThrowInvalidArgument(nil, "DOMAIN-Aeph3", "Errors.User.PasswordAgePolicy.HistoryCountNotAllowed")
+	}
+	return nil
+}
+
+// MinAgeReached returns false if the password, which was changed at changeDate, is too young to be changed again
+func (p *PasswordAgePolicy) MinAgeReached(changeDate, now time.Time) bool {
+	if p.MinAgeDays == 0 || changeDate.IsZero() {
+		return true
+	}
+	return !now.Before(changeDate.Add(time.Duration(p.MinAgeDays) * 24 * time.Hour))
 }
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordAgePolicy_MinAgeReached(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		minAgeDays uint64
+		changeDate time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"no minimum age, true",
+			args{
+				minAgeDays: 0,
+				changeDate: now,
+			},
+			true,
+		},
+		{
+			"never changed, true",
+			args{
+				minAgeDays: 1,
+			},
+			true,
+		},
+		{
+			"changed too recently, false",
+			args{
+				minAgeDays: 1,
+				changeDate: now.Add(-23 * time.Hour),
+			},
+			false,
+		},
+		{
+			"minimum age reached, true",
+			args{
+				minAgeDays: 1,
+				changeDate: now.Add(-24 * time.Hour),
+			},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &PasswordAgePolicy{MinAgeDays: tt.args.minAgeDays}
+			assert.Equal(t, tt.want, policy.MinAgeReached(tt.args.changeDate, now))
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"context"
 	"regexp"
+	"strings"
 
 	caos_errs "github.com/zitadel/zitadel/internal/errors"
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
@@ -22,14 +24,76 @@ type PasswordComplexityPolicy struct {
 	HasUppercase bool
 	HasNumber    bool
 	HasSymbol    bool
+	// RejectBreached rejects passwords which are part of a breached-password corpus
+	RejectBreached bool
+	// MaxLength is the maximum length of the password, 0 is unlimited
+	MaxLength uint64
+	// ForbidUsername, ForbidEmailLocalPart and ForbidOrgName reject passwords containing the attribute of the user
+	ForbidUsername       bool
+	ForbidEmailLocalPart bool
+	ForbidOrgName        bool
+	Rules                []PasswordRule
+	// BannedWords is the dictionary of words (case-insensitive), which must not be part of the password
+	BannedWords []string
 
 	Default bool
 }
 
+type PasswordRuleType int32
+
+const (
+	PasswordRuleTypeUnspecified PasswordRuleType = iota
+	// PasswordRuleTypeAllow requires the password to match the pattern
+	PasswordRuleTypeAllow
+	// PasswordRuleTypeDeny rejects passwords matching the pattern
+	PasswordRuleTypeDeny
+
+	passwordRuleTypeCount
+)
+
+func (t PasswordRuleType) Valid() bool {
+	return t > PasswordRuleTypeUnspecified && t < passwordRuleTypeCount
+}
+
+// PasswordRule is a custom rule of the password complexity policy.
+// The pattern is a regular expression in RE2 syntax.
+type PasswordRule struct {
+	Type        PasswordRuleType `json:"type"`
+	Pattern     string           `json:"pattern"`
+	Description string           `json:"description,omitempty"`
+}
+
+// PasswordUserAttributes are the attributes of a user, which might be forbidden as part of the password
+type PasswordUserAttributes struct {
+	Username string
+	Email    string
+	OrgName  string
+}
+
+// minForbiddenAttributeLength prevents short attributes (e.g. a single character username)
+// from rejecting almost any password
+const minForbiddenAttributeLength = 3
+
 func (p *PasswordComplexityPolicy) IsValid() error {
 	if p.MinLength == 0 || p.MinLength > 72 {
 		return caos_errs.ThrowInvalidArgument(nil, "MODEL-Lsp0e", "Errors.User.PasswordComplexityPolicy.MinLengthNotAllowed")
 	}
+	if p.MaxLength != 0 && p.MaxLength < p.MinLength {
+		return caos_errs.ThrowInvalidArgument(nil, "MODEL-Ub2ie", "Errors.User.PasswordComplexityPolicy.MaxLengthNotAllowed")
+	}
+	for _, rule := range p.Rules {
+		if !rule.Type.Valid() || rule.Pattern == "" {
+			return caos_errs.ThrowInvalidArgument(nil, "MODEL-ahP8i", "Errors.User.PasswordComplexityPolicy.InvalidRule")
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return caos_errs.ThrowInvalidArgument(err, "MODEL-Ais2o", "Errors.User.PasswordComplexityPolicy.InvalidRule")
+		}
+	}
+	for _, word := range p.BannedWords {
+		if strings.TrimSpace(word) == "" {
+			return caos_errs.ThrowInvalidArgument(nil, "MODEL-Quo1e", "Errors.User.PasswordComplexityPolicy.InvalidBannedWord")
+		}
+	}
 	return nil
 }
 
@@ -53,5 +117,90 @@ func (p *PasswordComplexityPolicy) Check(password string) error {
 	if p.HasSymbol && !hasSymbol(password) {
 		return caos_errs.ThrowInvalidArgument(nil, "DOMAIN-ZDLwA", "Errors.User.PasswordComplexityPolicy.HasSymbol")
 	}
+	return p.CheckRules(password)
+}
+
+// CheckRules checks the maximum length, the custom rules and the banned words of the policy
+func (p *PasswordComplexityPolicy) CheckRules(password string) error {
+	if p.MaxLength != 0 && uint64(len(password)) > p.MaxLength {
+		return caos_errs.ThrowInvalidArgument(nil, "DOMAIN-Eeph4", "Errors.User.PasswordComplexityPolicy.MaxLength")
+	}
+	for _, rule := range p.Rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return caos_errs.ThrowInternal(err, "DOMAIN-ohW9i", "Errors.User.PasswordComplexityPolicy.InvalidRule")
+		}
+		matches := pattern.MatchString(password)
+		if rule.Type == PasswordRuleTypeAllow && !matches {
+			return caos_errs.ThrowInvalidArgument(nil, "DOMAIN-Wae4u", "Errors.User.PasswordComplexityPolicy.AllowRule")
+		}
+		if rule.Type == PasswordRuleTypeDeny && matches {
+			return caos_errs.ThrowInvalidArgument(nil, "DOMAIN-ooX4a", "Errors.User.PasswordComplexityPolicy.DenyRule")
+		}
+	}
+	lowerPassword := strings.ToLower(password)
+	for _, word := range p.BannedWords {
+		if strings.Contains(lowerPassword, strings.ToLower(word)) {
+			return caos_errs.ThrowInvalidArgument(nil, "DOMAIN-Ung6e", "Errors.User.PasswordComplexityPolicy.BannedWord")
+		}
+	}
+	return nil
+}
+
+// ForbidsUserAttributes returns true if any attribute of the user must not be part of the password
+func (p *PasswordComplexityPolicy) ForbidsUserAttributes() bool {
+	return p.ForbidUsername || p.ForbidEmailLocalPart || p.ForbidOrgName
+}
+
+// CheckUserAttributes returns an error if the password contains (case-insensitive) an attribute of the user forbidden by the policy
+func (p *PasswordComplexityPolicy) CheckUserAttributes(password string, attributes *PasswordUserAttributes) error {
+	if attributes == nil {
+		return nil
+	}
+	lowerPassword := strings.ToLower(password)
+	if p.ForbidUsername && containsAttribute(lowerPassword, attributes.Username) {
+		return caos_errs.ThrowInvalidArgument(nil, "DOMAIN-Lah7e", "Errors.User.PasswordComplexityPolicy.ContainsUsername")
+	}
+	if p.ForbidEmailLocalPart {
+		localPart, _, _ := strings.Cut(attributes.Email, "@")
+		if containsAttribute(lowerPassword, localPart) {
+			return caos_errs.ThrowInvalidArgument(nil, "DOMAIN-eeC1u", "Errors.User.PasswordComplexityPolicy.ContainsEmail")
+		}
+	}
+	if p.ForbidOrgName && containsAttribute(lowerPassword, attributes.OrgName) {
+		return caos_errs.ThrowInvalidArgument(nil, "DOMAIN-Aith8", "Errors.User.PasswordComplexityPolicy.ContainsOrgName")
+	}
+	return nil
+}
+
+func containsAttribute(lowerPassword, attribute string) bool {
+	attribute = strings.ToLower(strings.TrimSpace(attribute))
+	if len(attribute) < minForbiddenAttributeLength {
+		return false
+	}
+	return strings.Contains(lowerPassword, attribute)
+}
+
+// BreachedPasswordChecker checks if a password is part of a breached-password corpus
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// CheckBreached returns an error if the policy rejects breached passwords and the password is breached.
+// If the policy requires the check but no checker is configured, the password is rejected as well.
+func (p *PasswordComplexityPolicy) CheckBreached(ctx context.Context, password string, checker BreachedPasswordChecker) error {
+	if !p.RejectBreached {
+		return nil
+	}
+	if checker == nil {
+		return caos_errs.ThrowPreconditionFailed(nil, "DOMAIN-Ahgh4", "Errors.User.PasswordComplexityPolicy.BreachCheckUnavailable")
+	}
+	breached, err := checker.IsBreached(ctx, password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		return caos_errs.ThrowInvalidArgument(nil, "DOMAIN-oaK3e", "Errors.User.PasswordComplexityPolicy.Breached")
+	}
 	return nil
 }
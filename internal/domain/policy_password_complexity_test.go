@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+func TestPasswordComplexityPolicy_IsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *PasswordComplexityPolicy
+		wantErr bool
+	}{
+		{
+			"min length only, ok",
+			&PasswordComplexityPolicy{MinLength: 8},
+			false,
+		},
+		{
+			"max length lower than min length, error",
+			&PasswordComplexityPolicy{MinLength: 8, MaxLength: 6},
+			true,
+		},
+		{
+			"max length, ok",
+			&PasswordComplexityPolicy{MinLength: 8, MaxLength: 64},
+			false,
+		},
+		{
+			"rule without type, error",
+			&PasswordComplexityPolicy{MinLength: 8, Rules: []PasswordRule{{Pattern: "[a-z]"}}},
+			true,
+		},
+		{
+			"rule without pattern, error",
+			&PasswordComplexityPolicy{MinLength: 8, Rules: []PasswordRule{{Type: PasswordRuleTypeAllow}}},
+			true,
+		},
+		{
+			"rule with invalid pattern, error",
+			&PasswordComplexityPolicy{MinLength: 8, Rules: []PasswordRule{{Type: PasswordRuleTypeDeny, Pattern: "(a"}}},
+			true,
+		},
+		{
+			"empty banned word, error",
+			&PasswordComplexityPolicy{MinLength: 8, BannedWords: []string{"zitadel", " "}},
+			true,
+		},
+		{
+			"rules and banned words, ok",
+			&PasswordComplexityPolicy{
+				MinLength:   8,
+				Rules:       []PasswordRule{{Type: PasswordRuleTypeDeny, Pattern: `(?i)qwerty`}},
+				BannedWords: []string{"zitadel"},
+			},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.IsValid()
+			if tt.wantErr {
+				assert.True(t, caos_errs.IsErrorInvalidArgument(err), "want invalid argument, got: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPasswordComplexityPolicy_CheckRules(t *testing.T) {
+	policy := &PasswordComplexityPolicy{
+		MaxLength: 16,
+		Rules: []PasswordRule{
+			{Type: PasswordRuleTypeAllow, Pattern: `^[[:ascii:]]+$`},
+			{Type: PasswordRuleTypeDeny, Pattern: `(?i)abc`},
+		},
+		BannedWords: []string{"Zitadel"},
+	}
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{"valid password", "Correct-Horse1", false},
+		{"too long", "Correct-Horse-Battery", true},
+		{"allow rule not matched", "Pässword-1", true},
+		{"deny rule matched", "xABCx-Horse", true},
+		{"banned word, case-insensitive", "my-zitadel-pw", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.CheckRules(tt.password)
+			if tt.wantErr {
+				assert.True(t, caos_errs.IsErrorInvalidArgument(err), "want invalid argument, got: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPasswordComplexityPolicy_CheckUserAttributes(t *testing.T) {
+	attributes := &PasswordUserAttributes{
+		Username: "Gigi",
+		Email:    "giraffe@zitadel.ch",
+		OrgName:  "ZITADEL",
+	}
+	tests := []struct {
+		name       string
+		policy     *PasswordComplexityPolicy
+		password   string
+		attributes *PasswordUserAttributes
+		wantErr    bool
+	}{
+		{
+			"nothing forbidden, ok",
+			&PasswordComplexityPolicy{},
+			"gigi-giraffe-zitadel",
+			attributes,
+			false,
+		},
+		{
+			"username forbidden, error",
+			&PasswordComplexityPolicy{ForbidUsername: true},
+			"my-GIGI-password",
+			attributes,
+			true,
+		},
+		{
+			"email local part forbidden, error",
+			&PasswordComplexityPolicy{ForbidEmailLocalPart: true},
+			"Giraffe-123",
+			attributes,
+			true,
+		},
+		{
+			"email domain is allowed, ok",
+			&PasswordComplexityPolicy{ForbidEmailLocalPart: true},
+			"zitadel.ch-123",
+			attributes,
+			false,
+		},
+		{
+			"org name forbidden, error",
+			&PasswordComplexityPolicy{ForbidOrgName: true},
+			"zitadel-123",
+			attributes,
+			true,
+		},
+		{
+			"short attributes are ignored, ok",
+			&PasswordComplexityPolicy{ForbidUsername: true},
+			"my-password",
+			&PasswordUserAttributes{Username: "my"},
+			false,
+		},
+		{
+			"no attributes, ok",
+			&PasswordComplexityPolicy{ForbidUsername: true},
+			"gigi",
+			nil,
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.CheckUserAttributes(tt.password, tt.attributes)
+			if tt.wantErr {
+				assert.True(t, caos_errs.IsErrorInvalidArgument(err), "want invalid argument, got: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
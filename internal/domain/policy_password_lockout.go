@@ -1,13 +1,86 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
 )
 
+const (
+	// DefaultIPLockoutDuration is the time a source IP is throttled,
+	// if the lockout policy locks users until they are unlocked by an administrator
+	DefaultIPLockoutDuration = time.Hour
+	// MaxProgressiveDelay caps the delay between two failed checks
+	MaxProgressiveDelay = 15 * time.Minute
+)
+
 type LockoutPolicy struct {
 	models.ObjectRoot
 
 	Default             bool
 	MaxPasswordAttempts uint64
 	ShowLockOutFailures bool
+	MaxOTPAttempts      uint64
+	MaxPasskeyAttempts  uint64
+	// MaxIPAttempts is the number of failed checks of any user of a source IP,
+	// before the IP is throttled for the lockout duration
+	MaxIPAttempts uint64
+	// LockoutDuration is the time after which a locked user is unlocked automatically.
+	// If it's 0, the user stays locked until unlocked by an administrator
+	LockoutDuration time.Duration
+	// ProgressiveDelay is the time a user has to wait after a failed check.
+	// The delay is doubled with every further failed check
+	ProgressiveDelay time.Duration
+}
+
+type LockoutFactor int32
+
+const (
+	LockoutFactorPassword LockoutFactor = iota
+	LockoutFactorOTP
+	LockoutFactorPasskey
+)
+
+// MaxAttempts returns the number of failed checks of the factor before the user is locked, 0 is unlimited
+func (p *LockoutPolicy) MaxAttempts(factor LockoutFactor) uint64 {
+	switch factor {
+	case LockoutFactorOTP:
+		return p.MaxOTPAttempts
+	case LockoutFactorPasskey:
+		return p.MaxPasskeyAttempts
+	default:
+		return p.MaxPasswordAttempts
+	}
+}
+
+// LockedUntil returns the end of a lockout starting now,
+// the zero time if the user is locked until unlocked by an administrator
+func (p *LockoutPolicy) LockedUntil(now time.Time) time.Time {
+	if p.LockoutDuration <= 0 {
+		return time.Time{}
+	}
+	return now.Add(p.LockoutDuration)
+}
+
+// Delay returns the time to wait before the next check after the given number of consecutive failed checks
+func (p *LockoutPolicy) Delay(failedAttempts uint64) time.Duration {
+	if p.ProgressiveDelay <= 0 || failedAttempts == 0 {
+		return 0
+	}
+	delay := p.ProgressiveDelay
+	for i := uint64(1); i < failedAttempts && delay < MaxProgressiveDelay; i++ {
+		delay *= 2
+	}
+	if delay > MaxProgressiveDelay {
+		return MaxProgressiveDelay
+	}
+	return delay
+}
+
+// IPLockoutDuration returns the time failed checks of a source IP are counted and the IP is throttled for
+func (p *LockoutPolicy) IPLockoutDuration() time.Duration {
+	if p.LockoutDuration <= 0 {
+		return DefaultIPLockoutDuration
+	}
+	return p.LockoutDuration
 }
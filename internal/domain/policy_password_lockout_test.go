@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockoutPolicy_Delay(t *testing.T) {
+	type args struct {
+		progressiveDelay time.Duration
+		failedAttempts   uint64
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Duration
+	}{
+		{
+			"no delay, 0",
+			args{
+				progressiveDelay: 0,
+				failedAttempts:   3,
+			},
+			0,
+		},
+		{
+			"no failed attempt, 0",
+			args{
+				progressiveDelay: time.Second,
+				failedAttempts:   0,
+			},
+			0,
+		},
+		{
+			"first failed attempt, delay",
+			args{
+				progressiveDelay: time.Second,
+				failedAttempts:   1,
+			},
+			time.Second,
+		},
+		{
+			"third failed attempt, doubled twice",
+			args{
+				progressiveDelay: time.Second,
+				failedAttempts:   3,
+			},
+			4 * time.Second,
+		},
+		{
+			"many failed attempts, max delay",
+			args{
+				progressiveDelay: time.Second,
+				failedAttempts:   100,
+			},
+			MaxProgressiveDelay,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &LockoutPolicy{ProgressiveDelay: tt.args.progressiveDelay}
+			assert.Equal(t, tt.want, policy.Delay(tt.args.failedAttempts))
+		})
+	}
+}
+
+func TestLockoutPolicy_LockedUntil(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	permanent := &LockoutPolicy{}
+	assert.True(t, permanent.LockedUntil(now).IsZero())
+	assert.Equal(t, DefaultIPLockoutDuration, permanent.IPLockoutDuration())
+
+	temporary := &LockoutPolicy{LockoutDuration: 10 * time.Minute}
+	assert.Equal(t, now.Add(10*time.Minute), temporary.LockedUntil(now))
+	assert.Equal(t, 10*time.Minute, temporary.IPLockoutDuration())
+}
@@ -0,0 +1,11 @@
+package domain
+
+// ProjectionRebuildState is the state of a rebuild of a projection into shadow tables
+type ProjectionRebuildState int32
+
+const (
+	ProjectionRebuildStateUnspecified ProjectionRebuildState = iota
+	ProjectionRebuildStateRunning
+	ProjectionRebuildStateDone
+	ProjectionRebuildStateFailed
+)
@@ -19,6 +19,22 @@ func (t SecretGeneratorType) Valid() bool {
 	return t > SecretGeneratorTypeUnspecified && t < secretGeneratorTypeCount
 }
 
+// Hashable returns if the one-time codes of the type are only verified and never shown again,
+// so they can be stored as hash. Sent codes are kept as encrypted copy until they are sent.
+// Other secrets, e.g. the app secrets or the domain verification token, use their own algorithms.
+func (t SecretGeneratorType) Hashable() bool {
+	switch t {
+	case SecretGeneratorTypeInitCode,
+		SecretGeneratorTypeVerifyEmailCode,
+		SecretGeneratorTypeVerifyPhoneCode,
+		SecretGeneratorTypePasswordResetCode,
+		SecretGeneratorTypePasswordlessInitCode:
+		return true
+	default:
+		return false
+	}
+}
+
 type SecretGeneratorState int32
 
 const (
@@ -0,0 +1,454 @@
+// Package archive moves events which are no longer required from the eventstore into cold storage.
+// Archived events are compressed as JSON lines and can be imported again.
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	crypto_db "github.com/zitadel/zitadel/internal/crypto/database"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/repository/idpintent"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/project"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+const (
+	eventColumns = "id, event_type, aggregate_type, aggregate_id, aggregate_version, event_sequence, previous_aggregate_sequence," +
+		" previous_aggregate_type_sequence, creation_date, event_data, editor_user, editor_service, resource_owner, instance_id"
+
+	removedInstancesStmt = "SELECT DISTINCT instance_id FROM eventstore.events" +
+		" WHERE aggregate_type = $1 AND event_type = $2 AND creation_date < $3"
+	removedAggregatesStmt = "SELECT DISTINCT instance_id, aggregate_type, aggregate_id FROM eventstore.events" +
+		" WHERE event_type = ANY($1) AND creation_date < $2"
+
+	instanceEventsStmt = "SELECT " + eventColumns + " FROM eventstore.events" +
+		" WHERE instance_id = $1 ORDER BY event_sequence"
+	aggregateEventsStmt = "SELECT " + eventColumns + " FROM eventstore.events" +
+		" WHERE instance_id = $1 AND aggregate_type = $2 AND aggregate_id = ANY($3) ORDER BY event_sequence"
+
+	deleteInstanceEventsStmt = "DELETE FROM eventstore.events" +
+		" WHERE instance_id = $1 AND event_sequence <= $2"
+	deleteInstanceSnapshotsStmt = "DELETE FROM eventstore.snapshots" +
+		" WHERE instance_id = $1"
+	deleteAggregateEventsStmt = "DELETE FROM eventstore.events" +
+		" WHERE instance_id = $1 AND aggregate_type = $2 AND aggregate_id = ANY($3) AND event_sequence <= $4"
+	deleteAggregateSnapshotsStmt = "DELETE FROM eventstore.snapshots" +
+		" WHERE instance_id = $1 AND aggregate_id = ANY($2)"
+
+	// repairChainStmt links the events of the aggregate type from $3 up to the first event after $4 to their actual predecessor,
+	// because the predecessor of the events changes if events between $3 and $4 are archived or imported.
+	// Only the events of this range and the event before it are read, the remaining events are not affected
+	repairChainStmt = "UPDATE eventstore.events AS e SET previous_aggregate_type_sequence = c.previous_sequence" +
+		" FROM (SELECT event_sequence, LAG(event_sequence) OVER (ORDER BY event_sequence) AS previous_sequence" +
+		" FROM eventstore.events WHERE instance_id = $1 AND aggregate_type = $2" +
+		" AND event_sequence >= COALESCE((SELECT MAX(event_sequence) FROM eventstore.events WHERE instance_id = $1 AND aggregate_type = $2 AND event_sequence < $3), 0)" +
+		" AND event_sequence <= COALESCE((SELECT MIN(event_sequence) FROM eventstore.events WHERE instance_id = $1 AND aggregate_type = $2 AND event_sequence > $4), $4)) AS c" +
+		" WHERE e.instance_id = $1 AND e.aggregate_type = $2 AND e.event_sequence = c.event_sequence" +
+		" AND e.event_sequence >= $3 AND e.previous_aggregate_type_sequence IS DISTINCT FROM c.previous_sequence"
+
+	// erasePIIKeysStmt deletes the personal data keys of the aggregates with erasing events up to the sequence,
+	// the keys would not be erased anymore as soon as the erasing events are archived
+	erasePIIKeysStmt = "DELETE FROM " + crypto_db.PIIKeysTable + " AS k" +
+		" WHERE k.instance_id = $1 AND EXISTS (SELECT 1 FROM eventstore.events AS e" +
+		" WHERE e.instance_id = k.instance_id AND e.aggregate_type = k.aggregate_type AND e.aggregate_id = k.aggregate_id" +
+		" AND e.event_type = ANY($2) AND e.event_sequence <= $3)"
+
+	importEventStmt = "INSERT INTO eventstore.events (" + eventColumns + ")" +
+		" VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)" +
+		" ON CONFLICT DO NOTHING"
+)
+
+// removedAggregateTypes are the event types which end the lifecycle of an aggregate
+var removedAggregateTypes = []string{
+	string(org.OrgRemovedEventType),
+	string(project.ProjectRemovedType),
+	string(user.UserRemovedType),
+}
+
+// erasureTypes are the event types which erase the personal data of their aggregate
+var erasureTypes = []string{
+	string(idpintent.UserRemovedEventType),
+	string(user.UserRemovedType),
+}
+
+// Unit is a set of events archived into one file
+type Unit struct {
+	InstanceID string
+	// AggregateType and AggregateIDs are empty if all events of the instance are archived
+	AggregateType string
+	AggregateIDs  []string
+}
+
+func (u *Unit) name(before time.Time) string {
+	name := "instance"
+	if u.AggregateType != "" {
+		name = u.AggregateType
+	}
+	return name + "-" + before.UTC().Format("20060102T150405Z") + ".jsonl.gz"
+}
+
+// Archive describes a written archive
+type Archive struct {
+	*Unit
+	Name        string
+	Events      uint64
+	MinSequence uint64
+	MaxSequence uint64
+}
+
+type Archiver struct {
+	client *sql.DB
+	store  Store
+}
+
+func NewArchiver(client *sql.DB, store Store) *Archiver {
+	return &Archiver{
+		client: client,
+		store:  store,
+	}
+}
+
+// Units returns the events to archive.
+// All events of instances removed before the cutoff are archived
+// and, if aggregates is set, the events of aggregates removed before the cutoff
+func (a *Archiver) Units(ctx context.Context, before time.Time, aggregates bool) ([]*Unit, error) {
+	instanceIDs, err := a.removedInstances(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+	units := make([]*Unit, 0, len(instanceIDs))
+	removedInstances := make(map[string]struct{}, len(instanceIDs))
+	for _, instanceID := range instanceIDs {
+		removedInstances[instanceID] = struct{}{}
+		units = append(units, &Unit{InstanceID: instanceID})
+	}
+	if !aggregates {
+		return units, nil
+	}
+
+	rows, err := a.client.QueryContext(ctx, removedAggregatesStmt, database.StringArray(removedAggregateTypes), before)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-Phie7", "unable to query removed aggregates")
+	}
+	defer rows.Close()
+
+	aggregateUnits := make(map[[2]string]*Unit)
+	for rows.Next() {
+		var instanceID, aggregateType, aggregateID string
+		if err = rows.Scan(&instanceID, &aggregateType, &aggregateID); err != nil {
+			return nil, errors.ThrowInternal(err, "ARCHI-xoo3U", "unable to query removed aggregates")
+		}
+		// the events are already archived with their instance
+		if _, ok := removedInstances[instanceID]; ok {
+			continue
+		}
+		key := [2]string{instanceID, aggregateType}
+		unit, ok := aggregateUnits[key]
+		if !ok {
+			unit = &Unit{InstanceID: instanceID, AggregateType: aggregateType}
+			aggregateUnits[key] = unit
+			units = append(units, unit)
+		}
+		unit.AggregateIDs = append(unit.AggregateIDs, aggregateID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-Gu4ae", "unable to query removed aggregates")
+	}
+	return units, nil
+}
+
+func (a *Archiver) removedInstances(ctx context.Context, before time.Time) ([]string, error) {
+	rows, err := a.client.QueryContext(ctx, removedInstancesStmt, instance.AggregateType, instance.InstanceRemovedEventType, before)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-ahk2E", "unable to query removed instances")
+	}
+	defer rows.Close()
+
+	instanceIDs := make([]string, 0)
+	for rows.Next() {
+		var instanceID string
+		if err = rows.Scan(&instanceID); err != nil {
+			return nil, errors.ThrowInternal(err, "ARCHI-Ohz5i", "unable to query removed instances")
+		}
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-oo2Ae", "unable to query removed instances")
+	}
+	sort.Strings(instanceIDs)
+	return instanceIDs, nil
+}
+
+// Archive exports the events of the unit to the store, verifies the stored archive
+// and deletes the exported events from the eventstore
+func (a *Archiver) Archive(ctx context.Context, unit *Unit, before time.Time) (_ *Archive, err error) {
+	file, err := os.CreateTemp("", "eventarchive-*.jsonl.gz")
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-cah7E", "unable to create temporary archive")
+	}
+	defer func() {
+		file.Close()
+		logging.WithFields("file", file.Name()).OnError(os.Remove(file.Name())).Warn("unable to remove temporary archive")
+	}()
+
+	archive := &Archive{Unit: unit, Name: unit.name(before)}
+	checksum, err := a.export(ctx, unit, file, archive)
+	if err != nil {
+		return nil, err
+	}
+	if archive.Events == 0 {
+		return archive, nil
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-ieG5e", "unable to read temporary archive")
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-Xah1o", "unable to read temporary archive")
+	}
+	if err = a.store.Write(ctx, unit.InstanceID, archive.Name, file, size); err != nil {
+		return nil, err
+	}
+	if err = a.verify(ctx, archive, checksum); err != nil {
+		return nil, err
+	}
+	return archive, a.delete(ctx, archive)
+}
+
+func (a *Archiver) export(ctx context.Context, unit *Unit, w io.Writer, archive *Archive) (*Checksum, error) {
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if unit.AggregateType == "" {
+		rows, err = a.client.QueryContext(ctx, instanceEventsStmt, unit.InstanceID)
+	} else {
+		rows, err = a.client.QueryContext(ctx, aggregateEventsStmt, unit.InstanceID, unit.AggregateType, database.StringArray(unit.AggregateIDs))
+	}
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-Ua1ie", "unable to query events")
+	}
+	defer rows.Close()
+
+	writer := NewWriter(w)
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		if err = writer.Write(event); err != nil {
+			return nil, err
+		}
+		if archive.MinSequence == 0 || event.Sequence < archive.MinSequence {
+			archive.MinSequence = event.Sequence
+		}
+		if event.Sequence > archive.MaxSequence {
+			archive.MaxSequence = event.Sequence
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-iPh4a", "unable to query events")
+	}
+	checksum, err := writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	archive.Events = checksum.Events
+	return checksum, nil
+}
+
+func (a *Archiver) verify(ctx context.Context, archive *Archive, checksum *Checksum) error {
+	stored, err := a.store.Read(ctx, archive.InstanceID, archive.Name)
+	if err != nil {
+		return err
+	}
+	defer stored.Close()
+	return Verify(stored, checksum)
+}
+
+func (a *Archiver) delete(ctx context.Context, archive *Archive) (err error) {
+	tx, err := a.client.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.ThrowInternal(err, "ARCHI-Sei0u", "unable to begin transaction")
+	}
+	defer func() {
+		if err != nil {
+			logging.OnError(tx.Rollback()).Debug("unable to rollback")
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = errors.ThrowInternal(err, "ARCHI-ooQu7", "unable to commit deletion of archived events")
+		}
+	}()
+
+	// the keys are erased before the erasing events are deleted
+	if _, err = tx.ExecContext(ctx, erasePIIKeysStmt, archive.InstanceID, database.StringArray(erasureTypes), archive.MaxSequence); err != nil {
+		return errors.ThrowInternal(err, "ARCHI-Uv2ee", "unable to erase personal data keys")
+	}
+	if archive.AggregateType == "" {
+		if _, err = tx.ExecContext(ctx, deleteInstanceEventsStmt, archive.InstanceID, archive.MaxSequence); err != nil {
+			return errors.ThrowInternal(err, "ARCHI-Iet4o", "unable to delete archived events")
+		}
+		if _, err = tx.ExecContext(ctx, deleteInstanceSnapshotsStmt, archive.InstanceID); err != nil {
+			return errors.ThrowInternal(err, "ARCHI-woh3N", "unable to delete snapshots")
+		}
+		return nil
+	}
+	aggregateIDs := database.StringArray(archive.AggregateIDs)
+	if _, err = tx.ExecContext(ctx, deleteAggregateEventsStmt, archive.InstanceID, archive.AggregateType, aggregateIDs, archive.MaxSequence); err != nil {
+		return errors.ThrowInternal(err, "ARCHI-aeX1a", "unable to delete archived events")
+	}
+	if _, err = tx.ExecContext(ctx, deleteAggregateSnapshotsStmt, archive.InstanceID, aggregateIDs); err != nil {
+		return errors.ThrowInternal(err, "ARCHI-Eeh5k", "unable to delete snapshots")
+	}
+	// the remaining events of the aggregate type must not reference the archived events,
+	// otherwise projections replaying the aggregate type wait for them forever
+	if _, err = tx.ExecContext(ctx, repairChainStmt, archive.InstanceID, archive.AggregateType, archive.MinSequence, archive.MaxSequence); err != nil {
+		return errors.ThrowInternal(err, "ARCHI-ahN4o", "unable to repair the sequences of the remaining events")
+	}
+	return nil
+}
+
+// Import inserts the events of the archive into the eventstore.
+// Events which still exist in the eventstore are skipped.
+// Snapshots of the imported aggregates are removed because they don't contain the imported events
+// and the following events of the aggregate types are linked to the imported events again.
+// The imported events keep their sequences, which are below the current sequences of the projections,
+// so they are only reduced if the projections are rebuilt.
+// It returns the count of imported events
+func (a *Archiver) Import(ctx context.Context, instanceID, name string) (imported uint64, err error) {
+	archive, err := a.store.Read(ctx, instanceID, name)
+	if err != nil {
+		return 0, err
+	}
+	defer archive.Close()
+
+	tx, err := a.client.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, errors.ThrowInternal(err, "ARCHI-Aix7u", "unable to begin transaction")
+	}
+	defer func() {
+		if err != nil {
+			logging.OnError(tx.Rollback()).Debug("unable to rollback")
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = errors.ThrowInternal(err, "ARCHI-ooj6U", "unable to commit imported events")
+		}
+	}()
+
+	// imported aggregate ids by instance
+	aggregateIDs := make(map[string]map[string]struct{})
+	// lowest and highest imported sequence by instance and aggregate type
+	minSequences := make(map[[2]string]uint64)
+	maxSequences := make(map[[2]string]uint64)
+	_, err = Read(archive, func(event *Event) error {
+		var data interface{}
+		if len(event.Data) > 0 {
+			data = []byte(event.Data)
+		}
+		result, err := tx.ExecContext(ctx, importEventStmt,
+			event.ID,
+			event.Type,
+			event.AggregateType,
+			event.AggregateID,
+			event.AggregateVersion,
+			event.Sequence,
+			event.PreviousAggregateSequence,
+			event.PreviousAggregateTypeSequence,
+			event.CreationDate,
+			data,
+			event.EditorUser,
+			event.EditorService,
+			event.ResourceOwner,
+			event.InstanceID,
+		)
+		if err != nil {
+			return errors.ThrowInternal(err, "ARCHI-Lah4u", "unable to import event")
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			imported++
+			if aggregateIDs[event.InstanceID] == nil {
+				aggregateIDs[event.InstanceID] = make(map[string]struct{})
+			}
+			aggregateIDs[event.InstanceID][event.AggregateID] = struct{}{}
+			key := [2]string{event.InstanceID, event.AggregateType}
+			if sequence, ok := minSequences[key]; !ok || event.Sequence < sequence {
+				minSequences[key] = event.Sequence
+			}
+			if event.Sequence > maxSequences[key] {
+				maxSequences[key] = event.Sequence
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return imported, err
+	}
+	for instance, ids := range aggregateIDs {
+		snapshotAggregateIDs := make(database.StringArray, 0, len(ids))
+		for id := range ids {
+			snapshotAggregateIDs = append(snapshotAggregateIDs, id)
+		}
+		if _, err = tx.ExecContext(ctx, deleteAggregateSnapshotsStmt, instance, snapshotAggregateIDs); err != nil {
+			return imported, errors.ThrowInternal(err, "ARCHI-ieP4e", "unable to delete snapshots of imported aggregates")
+		}
+	}
+	for key, sequence := range minSequences {
+		if _, err = tx.ExecContext(ctx, repairChainStmt, key[0], key[1], sequence, maxSequences[key]); err != nil {
+			return imported, errors.ThrowInternal(err, "ARCHI-Aeg2u", "unable to repair the sequences of the following events")
+		}
+	}
+	return imported, nil
+}
+
+func scanEvent(rows *sql.Rows) (*Event, error) {
+	event := new(Event)
+	var (
+		previousAggregateSequence     sql.NullInt64
+		previousAggregateTypeSequence sql.NullInt64
+		data                          []byte
+	)
+	err := rows.Scan(
+		&event.ID,
+		&event.Type,
+		&event.AggregateType,
+		&event.AggregateID,
+		&event.AggregateVersion,
+		&event.Sequence,
+		&previousAggregateSequence,
+		&previousAggregateTypeSequence,
+		&event.CreationDate,
+		&data,
+		&event.EditorUser,
+		&event.EditorService,
+		&event.ResourceOwner,
+		&event.InstanceID,
+	)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-Ohy2a", "unable to scan event")
+	}
+	if previousAggregateSequence.Valid {
+		sequence := uint64(previousAggregateSequence.Int64)
+		event.PreviousAggregateSequence = &sequence
+	}
+	if previousAggregateTypeSequence.Valid {
+		sequence := uint64(previousAggregateTypeSequence.Int64)
+		event.PreviousAggregateTypeSequence = &sequence
+	}
+	if len(data) > 0 {
+		event.Data = data
+	}
+	return event, nil
+}
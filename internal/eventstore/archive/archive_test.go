@@ -0,0 +1,381 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/static"
+)
+
+var eventRowColumns = []string{"id", "event_type", "aggregate_type", "aggregate_id", "aggregate_version", "event_sequence", "previous_aggregate_sequence",
+	"previous_aggregate_type_sequence", "creation_date", "event_data", "editor_user", "editor_service", "resource_owner", "instance_id"}
+
+func eventRows(events []*Event) *sqlmock.Rows {
+	rows := sqlmock.NewRows(eventRowColumns)
+	for _, event := range events {
+		var previousAggregateSequence, previousAggregateTypeSequence, data driver.Value
+		if event.PreviousAggregateSequence != nil {
+			previousAggregateSequence = int64(*event.PreviousAggregateSequence)
+		}
+		if event.PreviousAggregateTypeSequence != nil {
+			previousAggregateTypeSequence = int64(*event.PreviousAggregateTypeSequence)
+		}
+		if len(event.Data) > 0 {
+			data = []byte(event.Data)
+		}
+		rows.AddRow(event.ID, event.Type, event.AggregateType, event.AggregateID, event.AggregateVersion, int64(event.Sequence), previousAggregateSequence,
+			previousAggregateTypeSequence, event.CreationDate, data, event.EditorUser, event.EditorService, event.ResourceOwner, event.InstanceID)
+	}
+	return rows
+}
+
+func TestArchiver_Units(t *testing.T) {
+	before := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		aggregates bool
+		expect     func(sqlmock.Sqlmock)
+		want       []*Unit
+	}{
+		{
+			name: "removed instances",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(removedInstancesStmt)).
+					WithArgs("instance", "instance.removed", before).
+					WillReturnRows(sqlmock.NewRows([]string{"instance_id"}).AddRow("instance2").AddRow("instance1"))
+			},
+			want: []*Unit{
+				{InstanceID: "instance1"},
+				{InstanceID: "instance2"},
+			},
+		},
+		{
+			name:       "removed aggregates",
+			aggregates: true,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(removedInstancesStmt)).
+					WithArgs("instance", "instance.removed", before).
+					WillReturnRows(sqlmock.NewRows([]string{"instance_id"}).AddRow("instance1"))
+				mock.ExpectQuery(regexp.QuoteMeta(removedAggregatesStmt)).
+					WithArgs(sqlmock.AnyArg(), before).
+					WillReturnRows(sqlmock.NewRows([]string{"instance_id", "aggregate_type", "aggregate_id"}).
+						AddRow("instance1", "user", "user1").
+						AddRow("instance2", "user", "user2").
+						AddRow("instance2", "project", "project1").
+						AddRow("instance2", "user", "user3"),
+					)
+			},
+			want: []*Unit{
+				{InstanceID: "instance1"},
+				{InstanceID: "instance2", AggregateType: "user", AggregateIDs: []string{"user2", "user3"}},
+				{InstanceID: "instance2", AggregateType: "project", AggregateIDs: []string{"project1"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("unable to create mock: %v", err)
+			}
+			defer client.Close()
+			tt.expect(mock)
+
+			got, err := NewArchiver(client, nil).Units(context.Background(), before, tt.aggregates)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Units() = %+v, want %+v", got, tt.want)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestArchiver_Archive(t *testing.T) {
+	before := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	events := testEvents()
+	tests := []struct {
+		name    string
+		unit    *Unit
+		expect  func(sqlmock.Sqlmock)
+		want    *Archive
+		isErr   func(error) bool
+		written bool
+	}{
+		{
+			name: "instance",
+			unit: &Unit{InstanceID: "instance"},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(instanceEventsStmt)).
+					WithArgs("instance").
+					WillReturnRows(eventRows(events))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(erasePIIKeysStmt)).
+					WithArgs("instance", database.StringArray(erasureTypes), uint64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(deleteInstanceEventsStmt)).
+					WithArgs("instance", uint64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta(deleteInstanceSnapshotsStmt)).
+					WithArgs("instance").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			want: &Archive{
+				Unit:        &Unit{InstanceID: "instance"},
+				Name:        "instance-20220601T000000Z.jsonl.gz",
+				Events:      2,
+				MinSequence: 1,
+				MaxSequence: 2,
+			},
+			written: true,
+		},
+		{
+			name: "aggregates",
+			unit: &Unit{InstanceID: "instance", AggregateType: "user", AggregateIDs: []string{"user1"}},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(aggregateEventsStmt)).
+					WithArgs("instance", "user", sqlmock.AnyArg()).
+					WillReturnRows(eventRows(events))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(erasePIIKeysStmt)).
+					WithArgs("instance", database.StringArray(erasureTypes), uint64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(deleteAggregateEventsStmt)).
+					WithArgs("instance", "user", sqlmock.AnyArg(), uint64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta(deleteAggregateSnapshotsStmt)).
+					WithArgs("instance", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(repairChainStmt)).
+					WithArgs("instance", "user", uint64(1), uint64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+			want: &Archive{
+				Unit:        &Unit{InstanceID: "instance", AggregateType: "user", AggregateIDs: []string{"user1"}},
+				Name:        "user-20220601T000000Z.jsonl.gz",
+				Events:      2,
+				MinSequence: 1,
+				MaxSequence: 2,
+			},
+			written: true,
+		},
+		{
+			name: "no events",
+			unit: &Unit{InstanceID: "instance"},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(instanceEventsStmt)).
+					WithArgs("instance").
+					WillReturnRows(eventRows(nil))
+			},
+			want: &Archive{
+				Unit: &Unit{InstanceID: "instance"},
+				Name: "instance-20220601T000000Z.jsonl.gz",
+			},
+		},
+		{
+			name: "delete fails",
+			unit: &Unit{InstanceID: "instance"},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(instanceEventsStmt)).
+					WithArgs("instance").
+					WillReturnRows(eventRows(events))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(erasePIIKeysStmt)).
+					WithArgs("instance", database.StringArray(erasureTypes), uint64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(deleteInstanceEventsStmt)).
+					WithArgs("instance", uint64(2)).
+					WillReturnError(os.ErrDeadlineExceeded)
+				mock.ExpectRollback()
+			},
+			isErr:   errors.IsInternal,
+			written: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("unable to create mock: %v", err)
+			}
+			defer client.Close()
+			tt.expect(mock)
+			dir := t.TempDir()
+
+			got, err := NewArchiver(client, NewFilesystemStore(dir)).Archive(context.Background(), tt.unit, before)
+			if tt.isErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.isErr != nil && !tt.isErr(err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.isErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Archive() = %+v, want %+v", got, tt.want)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+
+			files, _ := filepath.Glob(filepath.Join(dir, tt.unit.InstanceID, "*"))
+			if tt.written != (len(files) == 1) {
+				t.Errorf("unexpected archive files: %v", files)
+			}
+		})
+	}
+}
+
+func TestArchiver_Import(t *testing.T) {
+	events := testEvents()
+	dir := t.TempDir()
+	store := NewFilesystemStore(dir)
+	buf, _ := writeArchive(t, events)
+	if err := store.Write(context.Background(), "instance", "archive.jsonl.gz", buf, int64(buf.Len())); err != nil {
+		t.Fatalf("unable to write archive: %v", err)
+	}
+
+	client, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("unable to create mock: %v", err)
+	}
+	defer client.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(importEventStmt)).
+		WithArgs(events[0].ID, events[0].Type, events[0].AggregateType, events[0].AggregateID, events[0].AggregateVersion, events[0].Sequence,
+			nil, nil, events[0].CreationDate, []byte(events[0].Data), events[0].EditorUser, events[0].EditorService, events[0].ResourceOwner, events[0].InstanceID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// the second event still exists
+	mock.ExpectExec(regexp.QuoteMeta(importEventStmt)).
+		WithArgs(events[1].ID, events[1].Type, events[1].AggregateType, events[1].AggregateID, events[1].AggregateVersion, events[1].Sequence,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), events[1].CreationDate, nil, events[1].EditorUser, events[1].EditorService, events[1].ResourceOwner, events[1].InstanceID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// snapshots don't contain the imported events
+	mock.ExpectExec(regexp.QuoteMeta(deleteAggregateSnapshotsStmt)).
+		WithArgs("instance", database.StringArray{"user1"}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// the following events reference the imported events again
+	mock.ExpectExec(regexp.QuoteMeta(repairChainStmt)).
+		WithArgs("instance", "user", uint64(1), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	imported, err := NewArchiver(client, store).Import(context.Background(), "instance", "archive.jsonl.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if imported != 1 {
+		t.Errorf("expected 1 imported event, got %d", imported)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFilesystemStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFilesystemStore(t.TempDir())
+
+	if _, err := store.Read(ctx, "instance", "missing"); !errors.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+
+	buf, checksum := writeArchive(t, testEvents())
+	if err := store.Write(ctx, "instance", "archive.jsonl.gz", buf, int64(buf.Len())); err != nil {
+		t.Fatalf("unable to write archive: %v", err)
+	}
+	archive, err := store.Read(ctx, "instance", "archive.jsonl.gz")
+	if err != nil {
+		t.Fatalf("unable to read archive: %v", err)
+	}
+	defer archive.Close()
+	if err = Verify(archive, checksum); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// objectStorage stores the objects in memory
+type objectStorage struct {
+	static.Storage
+	objects map[string][]byte
+	streams int
+}
+
+func (s *objectStorage) GetObject(_ context.Context, instanceID, resourceOwner, name string) ([]byte, func() (*static.Asset, error), error) {
+	object, ok := s.objects[instanceID+"/"+resourceOwner+"/"+name]
+	if !ok {
+		return nil, nil, errors.ThrowNotFound(nil, "ARCHI-Ahc0e", "not found")
+	}
+	return object, nil, nil
+}
+
+// streamingStorage streams the objects
+type streamingStorage struct {
+	*objectStorage
+}
+
+func (s *streamingStorage) ReadObject(ctx context.Context, instanceID, resourceOwner, name string) (io.ReadCloser, error) {
+	object, _, err := s.GetObject(ctx, instanceID, resourceOwner, name)
+	if err != nil {
+		return nil, err
+	}
+	s.streams++
+	return io.NopCloser(bytes.NewReader(object)), nil
+}
+
+func TestStaticStore_Read(t *testing.T) {
+	ctx := context.Background()
+	buf, checksum := writeArchive(t, testEvents())
+	objects := map[string][]byte{bucket + "/instance/archive.jsonl.gz": buf.Bytes()}
+	tests := []struct {
+		name    string
+		storage static.Storage
+		streams int
+	}{
+		{
+			name:    "streamed",
+			storage: &streamingStorage{objectStorage: &objectStorage{objects: objects}},
+			streams: 1,
+		},
+		{
+			name:    "buffered",
+			storage: &objectStorage{objects: objects},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStaticStore(tt.storage)
+			if _, err := store.Read(ctx, "instance", "missing"); !errors.IsNotFound(err) {
+				t.Errorf("expected not found, got %v", err)
+			}
+			archive, err := store.Read(ctx, "instance", "archive.jsonl.gz")
+			if err != nil {
+				t.Fatalf("unable to read archive: %v", err)
+			}
+			defer archive.Close()
+			if err = Verify(archive, checksum); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if streaming, ok := tt.storage.(*streamingStorage); ok && streaming.streams != tt.streams {
+				t.Errorf("expected %d streams, got %d", tt.streams, streaming.streams)
+			}
+		})
+	}
+}
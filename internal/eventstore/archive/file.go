@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+// Event is a row of the eventstore.events table as it is stored in an archive
+type Event struct {
+	ID                            string          `json:"id"`
+	InstanceID                    string          `json:"instanceId"`
+	AggregateType                 string          `json:"aggregateType"`
+	AggregateID                   string          `json:"aggregateId"`
+	AggregateVersion              string          `json:"aggregateVersion"`
+	Sequence                      uint64          `json:"sequence"`
+	PreviousAggregateSequence     *uint64         `json:"previousAggregateSequence,omitempty"`
+	PreviousAggregateTypeSequence *uint64         `json:"previousAggregateTypeSequence,omitempty"`
+	CreationDate                  time.Time       `json:"creationDate"`
+	Type                          string          `json:"eventType"`
+	Data                          json.RawMessage `json:"eventData,omitempty"`
+	EditorUser                    string          `json:"editorUser"`
+	EditorService                 string          `json:"editorService"`
+	ResourceOwner                 string          `json:"resourceOwner"`
+}
+
+// Checksum identifies the content of an archive
+// independent of the compression
+type Checksum struct {
+	Events uint64
+	SHA256 [sha256.Size]byte
+}
+
+// Writer writes the events gzip compressed as JSON lines
+type Writer struct {
+	gzip   *gzip.Writer
+	hash   hash.Hash
+	events uint64
+	out    io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	compressed := gzip.NewWriter(w)
+	hash := sha256.New()
+	return &Writer{
+		gzip: compressed,
+		hash: hash,
+		out:  io.MultiWriter(compressed, hash),
+	}
+}
+
+func (w *Writer) Write(event *Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return errors.ThrowInternal(err, "ARCHI-ahG4u", "unable to marshal event")
+	}
+	if _, err = w.out.Write(append(line, '\n')); err != nil {
+		return errors.ThrowInternal(err, "ARCHI-Ko3ei", "unable to write event")
+	}
+	w.events++
+	return nil
+}
+
+// Close flushes the compressed content and returns the checksum of the written events
+func (w *Writer) Close() (*Checksum, error) {
+	if err := w.gzip.Close(); err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-ooR2h", "unable to close archive")
+	}
+	checksum := &Checksum{Events: w.events}
+	copy(checksum.SHA256[:], w.hash.Sum(nil))
+	return checksum, nil
+}
+
+// Read decompresses the archive and calls reduce for each event in the archive
+func Read(r io.Reader, reduce func(*Event) error) (*Checksum, error) {
+	compressed, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.ThrowInvalidArgument(err, "ARCHI-Nai4o", "invalid archive")
+	}
+	defer compressed.Close()
+
+	hash := sha256.New()
+	reader := bufio.NewReader(io.TeeReader(compressed, hash))
+	checksum := new(Checksum)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			event := new(Event)
+			if jsonErr := json.Unmarshal(line, event); jsonErr != nil {
+				return nil, errors.ThrowInvalidArgument(jsonErr, "ARCHI-aeR4e", "invalid event in archive")
+			}
+			checksum.Events++
+			if reduce != nil {
+				if err := reduce(event); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.ThrowInvalidArgument(err, "ARCHI-Ahf1u", "invalid archive")
+		}
+	}
+	copy(checksum.SHA256[:], hash.Sum(nil))
+	return checksum, nil
+}
+
+// Verify checks if the archive contains exactly the content of the checksum
+func Verify(r io.Reader, expected *Checksum) error {
+	checksum, err := Read(r, nil)
+	if err != nil {
+		return err
+	}
+	if *checksum != *expected {
+		return errors.ThrowInternalf(nil, "ARCHI-eeD6u", "archive differs from export: %d of %d events", checksum.Events, expected.Events)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+func testEvents() []*Event {
+	previous := uint64(1)
+	return []*Event{
+		{
+			ID:               "d1a1b1c1-0000-0000-0000-000000000001",
+			InstanceID:       "instance",
+			AggregateType:    "user",
+			AggregateID:      "user1",
+			AggregateVersion: "v1",
+			Sequence:         1,
+			CreationDate:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			Type:             "user.human.added",
+			Data:             json.RawMessage(`{"userName":"gigi"}`),
+			EditorUser:       "editor",
+			EditorService:    "management",
+			ResourceOwner:    "org",
+		},
+		{
+			ID:                            "d1a1b1c1-0000-0000-0000-000000000002",
+			InstanceID:                    "instance",
+			AggregateType:                 "user",
+			AggregateID:                   "user1",
+			AggregateVersion:              "v1",
+			Sequence:                      2,
+			PreviousAggregateSequence:     &previous,
+			PreviousAggregateTypeSequence: &previous,
+			CreationDate:                  time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC),
+			Type:                          "user.removed",
+			EditorUser:                    "editor",
+			EditorService:                 "management",
+			ResourceOwner:                 "org",
+		},
+	}
+}
+
+func writeArchive(t *testing.T, events []*Event) (*bytes.Buffer, *Checksum) {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	writer := NewWriter(buf)
+	for _, event := range events {
+		if err := writer.Write(event); err != nil {
+			t.Fatalf("unable to write event: %v", err)
+		}
+	}
+	checksum, err := writer.Close()
+	if err != nil {
+		t.Fatalf("unable to close archive: %v", err)
+	}
+	return buf, checksum
+}
+
+func TestWriter_Read(t *testing.T) {
+	events := testEvents()
+	buf, written := writeArchive(t, events)
+	if written.Events != uint64(len(events)) {
+		t.Errorf("expected %d events written, got %d", len(events), written.Events)
+	}
+
+	read := make([]*Event, 0, len(events))
+	checksum, err := Read(buf, func(event *Event) error {
+		read = append(read, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *checksum != *written {
+		t.Errorf("checksum of read archive %v differs from written %v", checksum, written)
+	}
+	if !reflect.DeepEqual(read, events) {
+		t.Errorf("read events %+v differ from written %+v", read, events)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	events := testEvents()
+	tests := []struct {
+		name    string
+		archive func() *bytes.Buffer
+		isErr   func(error) bool
+	}{
+		{
+			name: "equal",
+			archive: func() *bytes.Buffer {
+				buf, _ := writeArchive(t, events)
+				return buf
+			},
+		},
+		{
+			name: "missing event",
+			archive: func() *bytes.Buffer {
+				buf, _ := writeArchive(t, events[:1])
+				return buf
+			},
+			isErr: errors.IsInternal,
+		},
+		{
+			name: "changed event",
+			archive: func() *bytes.Buffer {
+				changed := *events[1]
+				changed.EditorUser = "someone"
+				buf, _ := writeArchive(t, []*Event{events[0], &changed})
+				return buf
+			},
+			isErr: errors.IsInternal,
+		},
+		{
+			name: "not compressed",
+			archive: func() *bytes.Buffer {
+				return bytes.NewBufferString(`{"id":"1"}`)
+			},
+			isErr: errors.IsErrorInvalidArgument,
+		},
+	}
+	_, expected := writeArchive(t, events)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.archive(), expected)
+			if tt.isErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.isErr != nil && !tt.isErr(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
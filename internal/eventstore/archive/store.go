@@ -0,0 +1,132 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/static"
+	"github.com/zitadel/zitadel/internal/static/s3"
+)
+
+const (
+	contentType = "application/gzip"
+	// bucket is the name of the bucket in the static storage
+	// the archives are stored per instance as resource owner
+	bucket = "eventarchive"
+)
+
+// Store persists the archives outside of the database
+type Store interface {
+	Write(ctx context.Context, instanceID, name string, archive io.Reader, size int64) error
+	Read(ctx context.Context, instanceID, name string) (io.ReadCloser, error)
+}
+
+type Config struct {
+	Filesystem *FilesystemConfig
+	S3         *s3.Config
+}
+
+type FilesystemConfig struct {
+	// Path is the directory the archives are written to
+	Path string
+}
+
+func (c *Config) NewStore() (Store, error) {
+	if c == nil {
+		return nil, errors.ThrowPreconditionFailed(nil, "ARCHI-Iev3a", "no event archive configured")
+	}
+	if c.S3 != nil && c.S3.Endpoint != "" {
+		storage, err := c.S3.NewStorage()
+		if err != nil {
+			return nil, err
+		}
+		return NewStaticStore(storage), nil
+	}
+	if c.Filesystem != nil && c.Filesystem.Path != "" {
+		return NewFilesystemStore(c.Filesystem.Path), nil
+	}
+	return nil, errors.ThrowPreconditionFailed(nil, "ARCHI-Eiqu0", "no event archive configured")
+}
+
+var _ Store = (*FilesystemStore)(nil)
+
+// FilesystemStore stores the archives in the directory of the instance
+type FilesystemStore struct {
+	path string
+}
+
+func NewFilesystemStore(path string) *FilesystemStore {
+	return &FilesystemStore{path: path}
+}
+
+func (s *FilesystemStore) Write(_ context.Context, instanceID, name string, archive io.Reader, _ int64) (err error) {
+	dir := filepath.Join(s.path, instanceID)
+	if err = os.MkdirAll(dir, 0o750); err != nil {
+		return errors.ThrowInternal(err, "ARCHI-Bei1o", "unable to create archive directory")
+	}
+	// the archive is only visible under its name as soon as it's complete
+	file, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return errors.ThrowInternal(err, "ARCHI-Uf6ei", "unable to create archive")
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+	if _, err = io.Copy(file, archive); err != nil {
+		file.Close()
+		return errors.ThrowInternal(err, "ARCHI-ieC0o", "unable to write archive")
+	}
+	if err = file.Close(); err != nil {
+		return errors.ThrowInternal(err, "ARCHI-Oog7a", "unable to write archive")
+	}
+	if err = os.Rename(file.Name(), filepath.Join(dir, name)); err != nil {
+		return errors.ThrowInternal(err, "ARCHI-yai4E", "unable to write archive")
+	}
+	return nil
+}
+
+func (s *FilesystemStore) Read(_ context.Context, instanceID, name string) (io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(s.path, instanceID, name))
+	if os.IsNotExist(err) {
+		return nil, errors.ThrowNotFound(err, "ARCHI-Ooj1a", "archive not found")
+	}
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "ARCHI-ahV8i", "unable to read archive")
+	}
+	return file, nil
+}
+
+var _ Store = (*StaticStore)(nil)
+
+// StaticStore stores the archives in a static storage like S3
+type StaticStore struct {
+	storage static.Storage
+}
+
+func NewStaticStore(storage static.Storage) *StaticStore {
+	return &StaticStore{storage: storage}
+}
+
+func (s *StaticStore) Write(ctx context.Context, instanceID, name string, archive io.Reader, size int64) error {
+	_, err := s.storage.PutObject(ctx, bucket, "", instanceID, name, contentType, static.ObjectTypeEventArchive, archive, size)
+	return err
+}
+
+// Read streams the archive if the storage supports it,
+// otherwise the whole archive is read into memory
+func (s *StaticStore) Read(ctx context.Context, instanceID, name string) (io.ReadCloser, error) {
+	if reader, ok := s.storage.(static.ObjectReader); ok {
+		return reader.ReadObject(ctx, bucket, instanceID, name)
+	}
+	archive, _, err := s.storage.GetObject(ctx, bucket, instanceID, name)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(archive)), nil
+}
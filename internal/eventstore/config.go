@@ -3,7 +3,9 @@ package eventstore
 import (
 	"time"
 
+	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore/pii"
 	"github.com/zitadel/zitadel/internal/eventstore/repository"
 	z_sql "github.com/zitadel/zitadel/internal/eventstore/repository/sql"
 )
@@ -12,6 +14,10 @@ type Config struct {
 	PushTimeout              time.Duration
 	Client                   *database.DB
 	AllowOrderByCreationDate bool
+	Snapshots                SnapshotConfig
+	PII                      pii.Config
+	// PIIKeyStorage stores the keys of the personal data in event payloads
+	PIIKeyStorage crypto.PIIKeyStorage
 
 	repo repository.Repository
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/pii"
 	"github.com/zitadel/zitadel/internal/eventstore/repository"
 )
 
@@ -20,9 +21,12 @@ type Eventstore struct {
 	repo              repository.Repository
 	interceptorMutex  sync.Mutex
 	eventInterceptors map[EventType]eventTypeInterceptors
+	upcasters         map[upcasterKey]*Upcaster
 	eventTypes        []string
 	aggregateTypes    []string
 	PushTimeout       time.Duration
+	snapshots         SnapshotConfig
+	pii               *pii.Crypto
 }
 
 type eventTypeInterceptors struct {
@@ -33,8 +37,11 @@ func NewEventstore(config *Config) *Eventstore {
 	return &Eventstore{
 		repo:              config.repo,
 		eventInterceptors: map[EventType]eventTypeInterceptors{},
+		upcasters:         map[upcasterKey]*Upcaster{},
 		interceptorMutex:  sync.Mutex{},
 		PushTimeout:       config.PushTimeout,
+		snapshots:         config.Snapshots,
+		pii:               pii.New(config.PII, config.PIIKeyStorage),
 	}
 }
 
@@ -58,6 +65,10 @@ func (es *Eventstore) Push(ctx context.Context, cmds ...Command) ([]Event, error
 		defer cancel()
 	}
 
+	if err = es.encryptPII(events); err != nil {
+		return nil, err
+	}
+
 	err = es.repo.Push(ctx, events, constraints...)
 	if err != nil {
 		return nil, err
@@ -67,6 +78,7 @@ func (es *Eventstore) Push(ctx context.Context, cmds ...Command) ([]Event, error
 	if err != nil {
 		return nil, err
 	}
+	es.erasePII(ctx, events)
 
 	go notify(eventReaders)
 	return eventReaders, nil
@@ -158,10 +170,20 @@ func (es *Eventstore) Filter(ctx context.Context, queryFactory *SearchQueryBuild
 func (es *Eventstore) mapEvents(events []*repository.Event) (mappedEvents []Event, err error) {
 	mappedEvents = make([]Event, len(events))
 
+	// the keys are read before locking the interceptors
+	events, err = es.decryptPII(events)
+	if err != nil {
+		return nil, err
+	}
+
 	es.interceptorMutex.Lock()
 	defer es.interceptorMutex.Unlock()
 
 	for i, event := range events {
+		event, err = es.upcast(event)
+		if err != nil {
+			return nil, err
+		}
 		interceptors, ok := es.eventInterceptors[EventType(event.Type)]
 		if !ok || interceptors.eventMapper == nil {
 			mappedEvents[i] = BaseEventFromRepo(event)
@@ -187,7 +209,15 @@ type reducer interface {
 }
 
 // FilterToReducer filters the events based on the search query, appends all events to the reducer and calls it's reduce function
+// if the reducer implements Snapshotter only the events after the latest snapshot are filtered
 func (es *Eventstore) FilterToReducer(ctx context.Context, searchQuery *SearchQueryBuilder, r reducer) error {
+	if snapshotter, ok := r.(Snapshotter); ok {
+		return es.filterToSnapshotter(ctx, searchQuery, snapshotter)
+	}
+	return es.filterToReducer(ctx, searchQuery, r)
+}
+
+func (es *Eventstore) filterToReducer(ctx context.Context, searchQuery *SearchQueryBuilder, r reducer) error {
 	events, err := es.Filter(ctx, searchQuery)
 	if err != nil {
 		return err
@@ -224,14 +254,9 @@ type QueryReducer interface {
 
 // FilterToQueryReducer filters the events based on the search query of the query function,
 // appends all events to the reducer and calls it's reduce function
+// if the reducer implements Snapshotter only the events after the latest snapshot are filtered
 func (es *Eventstore) FilterToQueryReducer(ctx context.Context, r QueryReducer) error {
-	events, err := es.Filter(ctx, r.Query())
-	if err != nil {
-		return err
-	}
-	r.AppendEvents(events...)
-
-	return r.Reduce()
+	return es.FilterToReducer(ctx, r.Query(), r)
 }
 
 // RegisterFilterEventMapper registers a function for mapping an eventstore event to an event
@@ -688,11 +688,13 @@ func TestEventstore_aggregatesToEvents(t *testing.T) {
 }
 
 type testRepo struct {
-	events    []*repository.Event
-	sequence  uint64
-	instances []string
-	err       error
-	t         *testing.T
+	events         []*repository.Event
+	sequence       uint64
+	instances      []string
+	snapshot       *repository.Snapshot
+	pushedSnapshot *repository.Snapshot
+	err            error
+	t              *testing.T
 }
 
 func (repo *testRepo) Health(ctx context.Context) error {
@@ -743,6 +745,32 @@ func (repo *testRepo) InstanceIDs(ctx context.Context, queryFactory *repository.
 	return repo.instances, nil
 }
 
+func (repo *testRepo) Snapshot(ctx context.Context, instanceID, snapshotType, aggregateID, query string) (*repository.Snapshot, error) {
+	if repo.err != nil {
+		return nil, repo.err
+	}
+	if repo.snapshot == nil || repo.snapshot.Query != query {
+		return nil, nil
+	}
+	return repo.snapshot, nil
+}
+
+func (repo *testRepo) PushSnapshot(ctx context.Context, snapshot *repository.Snapshot) error {
+	if repo.err != nil {
+		return repo.err
+	}
+	repo.pushedSnapshot = snapshot
+	return nil
+}
+
+func (repo *testRepo) DeleteSnapshots(ctx context.Context, instanceID, aggregateID string) error {
+	if repo.err != nil {
+		return repo.err
+	}
+	repo.pushedSnapshot = nil
+	return nil
+}
+
 func TestEventstore_Push(t *testing.T) {
 	type args struct {
 		events []Command
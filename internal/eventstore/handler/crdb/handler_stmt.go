@@ -36,6 +36,8 @@ type StatementHandler struct {
 	*handler.ProjectionHandler
 	Locker
 
+	// config is used to create the shadow handler of a rebuild
+	config                  StatementHandlerConfig
 	client                  *database.DB
 	sequenceTable           string
 	currentSequenceStmt     string
@@ -77,6 +79,7 @@ func NewStatementHandler(
 	}
 
 	h := StatementHandler{
+		config:                     config,
 		client:                     config.Client,
 		sequenceTable:              config.SequenceTable,
 		maxFailureCount:            config.MaxFailureCount,
@@ -253,6 +256,11 @@ stmts:
 				continue stmts
 			}
 			if stmt.PreviousSequence > 0 && stmt.PreviousSequence != sequence.sequence && stmt.InstanceID == sequence.instanceID {
+				// the events between the previous and the current sequence were reduced and archived afterwards
+				if stmt.PreviousSequence < sequence.sequence {
+					logging.WithFields("projection", h.ProjectionName, "aggregateType", stmt.AggregateType, "sequence", stmt.Sequence, "prevSeq", stmt.PreviousSequence, "currentSeq", sequence.sequence).Debug("previous events archived")
+					continue
+				}
 				logging.WithFields("projection", h.ProjectionName, "aggregateType", stmt.AggregateType, "sequence", stmt.Sequence, "prevSeq", stmt.PreviousSequence, "currentSeq", sequence.sequence).Warn("sequences do not match")
 				break stmts
 			}
@@ -1135,6 +1135,93 @@ func TestStatementHandler_executeStmts(t *testing.T) {
 				idx: 3,
 			},
 		},
+		{
+			name: "replay across archived aggregate",
+			fields: fields{
+				projectionName: "my_projection",
+			},
+			args: args{
+				// the events 3 to 5 were archived and the chain was repaired
+				stmts: []*handler.Statement{
+					NewCreateStatement(
+						&testEvent{
+							aggregateType:    "agg",
+							sequence:         6,
+							previousSequence: 2,
+						},
+						[]handler.Column{
+							{
+								Name:  "col",
+								Value: "val",
+							},
+						}),
+					NewCreateStatement(
+						&testEvent{
+							aggregateType:    "agg",
+							sequence:         7,
+							previousSequence: 6,
+						},
+						[]handler.Column{
+							{
+								Name:  "col",
+								Value: "val",
+							},
+						}),
+				},
+				sequences: currentSequences{
+					"agg": []*instanceSequence{
+						{sequence: 2},
+					},
+				},
+			},
+			want: want{
+				expectations: []mockExpectation{
+					expectSavePoint(),
+					expectCreate("my_projection", []string{"col"}, []string{"$1"}),
+					expectSavePointRelease(),
+					expectSavePoint(),
+					expectCreate("my_projection", []string{"col"}, []string{"$1"}),
+					expectSavePointRelease(),
+				},
+				idx: 1,
+			},
+		},
+		{
+			name: "archived aggregate already reduced",
+			fields: fields{
+				projectionName: "my_projection",
+			},
+			args: args{
+				// the reduced events 3 to 5 were archived and the chain was repaired
+				stmts: []*handler.Statement{
+					NewCreateStatement(
+						&testEvent{
+							aggregateType:    "agg",
+							sequence:         6,
+							previousSequence: 2,
+						},
+						[]handler.Column{
+							{
+								Name:  "col",
+								Value: "val",
+							},
+						}),
+				},
+				sequences: currentSequences{
+					"agg": []*instanceSequence{
+						{sequence: 5},
+					},
+				},
+			},
+			want: want{
+				expectations: []mockExpectation{
+					expectSavePoint(),
+					expectCreate("my_projection", []string{"col"}, []string{"$1"}),
+					expectSavePointRelease(),
+				},
+				idx: 0,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
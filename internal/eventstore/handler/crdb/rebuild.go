@@ -0,0 +1,372 @@
+package crdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+const (
+	// RebuildSuffix is appended to the projection name for the shadow tables of a rebuild
+	RebuildSuffix = "_rebuild"
+
+	relationsStmt                  = "SELECT table_name, table_type FROM information_schema.tables WHERE table_schema = $1 AND table_name LIKE $2"
+	indicesStmt                    = "SELECT indexname FROM pg_indexes WHERE schemaname = $1 AND tablename = $2"
+	lockCurrentSequencesStmtFormat = "SELECT current_sequence FROM %s WHERE projection_name = $1 FOR UPDATE"
+	deleteProjectionStmtFormat     = "DELETE FROM %s WHERE projection_name = $1"
+	renameProjectionStmtFormat     = "UPDATE %s SET projection_name = $1 WHERE projection_name = $2"
+
+	tableTypeView = "VIEW"
+
+	minRebuildLockDuration = 10 * time.Second
+	lockRetryInterval      = time.Second
+)
+
+type relation struct {
+	name   string
+	isView bool
+}
+
+// RebuildProgress is the progress of a rebuild, it's reported after every replayed bulk of instances
+type RebuildProgress struct {
+	// Round is the count of replays over all instances, the shadow projection caught up
+	// as soon as a round reduced all events of each instance in a single bulk
+	Round int
+	// ReplayedInstances is the count of instances replayed in the current round
+	ReplayedInstances int
+	// Instances is the count of instances to replay
+	Instances int
+	// Swapped is true as soon as the shadow tables replaced the tables of the projection
+	Swapped bool
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Rebuild replays all events into shadow tables of the projection
+// and replaces the tables of the projection with the shadow tables as soon as they caught up.
+// The projection stays available during the rebuild.
+//
+// The progress is stored as current sequences of the shadow projection (projection name with RebuildSuffix),
+// if a rebuild is interrupted the next rebuild continues where it stopped.
+// Reducers must only write to tables of their own projection, because only these tables are rebuilt.
+// progress is called after every replayed bulk of instances and after the swap, it can be nil.
+func (h *StatementHandler) Rebuild(ctx context.Context, progress func(RebuildProgress)) error {
+	if progress == nil {
+		progress = func(RebuildProgress) {}
+	}
+	if h.reduceScheduledPseudoEvent {
+		return errors.ThrowPreconditionFailed(nil, "CRDB-Ohg4u", "projections of scheduled events cannot be rebuilt")
+	}
+	schema, table := splitTableName(h.ProjectionName)
+	relations, err := h.relations(ctx, h.client, schema, table)
+	if err != nil {
+		return err
+	}
+	for _, relation := range relations {
+		if relation.name == table && relation.isView {
+			return errors.ThrowPreconditionFailed(nil, "CRDB-Eeth9", "projections based on views cannot be rebuilt")
+		}
+	}
+
+	// cancels the background tasks of the shadow handler
+	shadowCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	shadow := h.shadow(shadowCtx)
+	if err = shadow.Init(ctx); err != nil {
+		return err
+	}
+
+	instanceIDs, err := h.Eventstore.InstanceIDs(ctx, eventstore.NewSearchQueryBuilder(eventstore.ColumnsInstanceIDs).AddQuery().ExcludedInstanceID("").Builder())
+	if err != nil {
+		return err
+	}
+	state := RebuildProgress{Instances: len(instanceIDs)}
+	for caughtUp := false; !caughtUp; {
+		state.Round++
+		caughtUp, err = shadow.replay(ctx, instanceIDs, func(replayed int) {
+			state.ReplayedInstances = replayed
+			progress(state)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	locker := NewLocker(h.client.DB, h.config.LockTable, h.ProjectionName)
+	if err = h.lockedSwap(ctx, locker, instanceIDs, shadow.ProjectionName); err != nil {
+		return err
+	}
+	state.Swapped = true
+	progress(state)
+	return nil
+}
+
+func (h *StatementHandler) shadow(ctx context.Context) *StatementHandler {
+	config := h.config
+	config.ProjectionName = h.ProjectionName + RebuildSuffix
+	shadow := NewStatementHandler(ctx, config)
+	return &shadow
+}
+
+// replay reduces the events of all instances into the projection
+// the projection caught up if all events were reduced in a single bulk per instance
+func (h *StatementHandler) replay(ctx context.Context, instanceIDs []string, replayed func(int)) (caughtUp bool, err error) {
+	concurrentInstances := int(h.config.ConcurrentInstances)
+	if concurrentInstances < 1 {
+		concurrentInstances = 1
+	}
+	caughtUp = true
+	for i := 0; i < len(instanceIDs); i += concurrentInstances {
+		max := i + concurrentInstances
+		if max > len(instanceIDs) {
+			max = len(instanceIDs)
+		}
+		instances := instanceIDs[i:max]
+		bulks, err := h.replayInstances(ctx, instances)
+		if err != nil {
+			return false, err
+		}
+		caughtUp = caughtUp && bulks <= 1
+		logging.WithFields("projection", h.ProjectionName, "instances", max, "total", len(instanceIDs)).Info("rebuild progress")
+		replayed(max)
+	}
+	return caughtUp, nil
+}
+
+func (h *StatementHandler) replayInstances(ctx context.Context, instances []string) (bulks int, err error) {
+	lockCtx, cancelLock := context.WithCancel(ctx)
+	defer cancelLock()
+	errs := h.Lock(lockCtx, h.rebuildLockDuration(), instances...)
+	if err, ok := <-errs; err != nil || !ok {
+		return 0, errors.ThrowPreconditionFailed(err, "CRDB-ieL5o", "unable to lock shadow projection, is another rebuild running?")
+	}
+	go cancelOnLockErr(lockCtx, errs, cancelLock)
+	defer func() {
+		unlockErr := h.Unlock(instances...)
+		logging.WithFields("projection", h.ProjectionName).OnError(unlockErr).Warn("unable to unlock")
+	}()
+
+	for hasLimitExceeded := true; hasLimitExceeded; bulks++ {
+		var events []eventstore.Event
+		events, hasLimitExceeded, err = h.FetchEvents(lockCtx, instances...)
+		if err != nil {
+			return bulks, err
+		}
+		if len(events) == 0 {
+			return bulks, nil
+		}
+		if _, err = h.Process(lockCtx, events...); err != nil {
+			return bulks, err
+		}
+	}
+	return bulks, nil
+}
+
+func (h *StatementHandler) rebuildLockDuration() time.Duration {
+	if h.config.RequeueEvery < minRebuildLockDuration {
+		return minRebuildLockDuration
+	}
+	return h.config.RequeueEvery
+}
+
+// lockedSwap swaps the tables while the projection is locked for all instances,
+// so the running handlers of the projection don't reduce events during the swap.
+// It waits until the running handlers release the lock.
+func (h *StatementHandler) lockedSwap(ctx context.Context, locker Locker, instanceIDs []string, shadowName string) error {
+	if len(instanceIDs) == 0 {
+		return h.swap(ctx, shadowName)
+	}
+	lockCtx, cancelLock := context.WithCancel(ctx)
+	defer cancelLock()
+	retry := time.NewTimer(0)
+	defer retry.Stop()
+	for {
+		select {
+		case <-retry.C:
+		case <-ctx.Done():
+			return errors.ThrowDeadlineExceeded(ctx.Err(), "CRDB-Ahl4u", "unable to lock projection for swap")
+		}
+		attemptCtx, cancelAttempt := context.WithCancel(lockCtx)
+		errs := locker.Lock(attemptCtx, h.rebuildLockDuration(), instanceIDs...)
+		err, ok := <-errs
+		if err == nil && ok {
+			go cancelOnLockErr(attemptCtx, errs, cancelLock)
+			defer func() {
+				cancelAttempt()
+				unlockErr := locker.Unlock(instanceIDs...)
+				logging.WithFields("projection", h.ProjectionName).OnError(unlockErr).Warn("unable to unlock")
+			}()
+			return h.swap(attemptCtx, shadowName)
+		}
+		cancelAttempt()
+		logging.WithFields("projection", h.ProjectionName).OnError(err).Debug("projection locked, swap delayed")
+		retry.Reset(lockRetryInterval)
+	}
+}
+
+func cancelOnLockErr(ctx context.Context, errs <-chan error, cancel func()) {
+	for {
+		select {
+		case err := <-errs:
+			if err != nil {
+				cancel()
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// swap replaces the tables and the state of the projection with the tables and the state of the shadow projection
+// the current sequences of the projection are locked, so the running handlers of the projection continue
+// on the swapped tables
+func (h *StatementHandler) swap(ctx context.Context, shadowName string) (err error) {
+	tx, err := h.client.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.ThrowInternal(err, "CRDB-ieZ4e", "begin failed")
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			logging.OnError(rollbackErr).Debug("rollback failed")
+		}
+	}()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(lockCurrentSequencesStmtFormat, h.config.SequenceTable), h.ProjectionName)
+	if err != nil {
+		return errors.ThrowInternal(err, "CRDB-Chai8", "unable to lock current sequences")
+	}
+	// the rows are locked as soon as they are read
+	for rows.Next() {
+	}
+	if err = rows.Close(); err != nil {
+		return errors.ThrowInternal(err, "CRDB-oo9Ie", "unable to lock current sequences")
+	}
+
+	schema, table := splitTableName(h.ProjectionName)
+	_, shadowTable := splitTableName(shadowName)
+	relations, err := h.relations(ctx, tx, schema, shadowTable)
+	if err != nil {
+		return err
+	}
+	for _, relation := range relations {
+		if _, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS "+schema+"."+table+strings.TrimPrefix(relation.name, shadowTable)+" CASCADE"); err != nil {
+			return errors.ThrowInternal(err, "CRDB-ua8Ae", "unable to drop table")
+		}
+	}
+	for _, relation := range relations {
+		if err = h.renameTable(ctx, tx, schema, relation.name, table+strings.TrimPrefix(relation.name, shadowTable)); err != nil {
+			return err
+		}
+	}
+
+	for _, stmt := range []struct {
+		format string
+		args   []interface{}
+	}{
+		{format: deleteProjectionStmtFormat, args: []interface{}{h.ProjectionName}},
+		{format: renameProjectionStmtFormat, args: []interface{}{h.ProjectionName, shadowName}},
+	} {
+		for _, stateTable := range []string{h.config.SequenceTable, h.config.FailedEventsTable} {
+			if _, err = tx.ExecContext(ctx, fmt.Sprintf(stmt.format, stateTable), stmt.args...); err != nil {
+				return errors.ThrowInternal(err, "CRDB-Eek3i", "unable to move state of shadow projection")
+			}
+		}
+	}
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf(deleteProjectionStmtFormat, h.config.LockTable), shadowName); err != nil {
+		return errors.ThrowInternal(err, "CRDB-iTh9a", "unable to remove locks of shadow projection")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.ThrowInternal(err, "CRDB-Oof8a", "commit failed")
+	}
+	return nil
+}
+
+// renameTable renames the table and on postgres its indices
+// because index names are unique per schema on postgres
+func (h *StatementHandler) renameTable(ctx context.Context, tx *sql.Tx, schema, from, to string) error {
+	if h.client.Type() != "postgres" {
+		_, err := tx.ExecContext(ctx, "ALTER TABLE "+schema+"."+from+" RENAME TO "+schema+"."+to)
+		if err != nil {
+			return errors.ThrowInternal(err, "CRDB-Aex1u", "unable to rename table")
+		}
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "ALTER TABLE "+schema+"."+from+" RENAME TO "+to); err != nil {
+		return errors.ThrowInternal(err, "CRDB-Aex1u", "unable to rename table")
+	}
+	rows, err := tx.QueryContext(ctx, indicesStmt, schema, to)
+	if err != nil {
+		return errors.ThrowInternal(err, "CRDB-ahN3d", "unable to query indices")
+	}
+	var indices []string
+	for rows.Next() {
+		var index string
+		if err = rows.Scan(&index); err != nil {
+			rows.Close()
+			return errors.ThrowInternal(err, "CRDB-Xai8o", "scan failed")
+		}
+		if strings.HasPrefix(index, from) {
+			indices = append(indices, index)
+		}
+	}
+	if err = rows.Close(); err != nil {
+		return errors.ThrowInternal(err, "CRDB-Jah2o", "close rows failed")
+	}
+	for _, index := range indices {
+		if _, err = tx.ExecContext(ctx, "ALTER INDEX "+schema+"."+index+" RENAME TO "+to+strings.TrimPrefix(index, from)); err != nil {
+			return errors.ThrowInternal(err, "CRDB-Ooy6u", "unable to rename index")
+		}
+	}
+	return nil
+}
+
+// relations returns the table or view and all suffixed tables of the given name
+func (h *StatementHandler) relations(ctx context.Context, q queryer, schema, name string) ([]*relation, error) {
+	rows, err := q.QueryContext(ctx, relationsStmt, schema, name+"%")
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "CRDB-oeG0a", "unable to query tables")
+	}
+	defer rows.Close()
+
+	var relations []*relation
+	for rows.Next() {
+		var tableName, tableType string
+		if err = rows.Scan(&tableName, &tableType); err != nil {
+			return nil, errors.ThrowInternal(err, "CRDB-Ahb3e", "scan failed")
+		}
+		if tableName != name && !isSuffixedTable(tableName, name) {
+			continue
+		}
+		relations = append(relations, &relation{name: tableName, isView: tableType == tableTypeView})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.ThrowInternal(err, "CRDB-ohZ5i", "errors in scanning rows")
+	}
+	return relations, nil
+}
+
+// isSuffixedTable checks if the table is a suffixed table of the projection
+// shadow tables of a rebuild are not part of the projection
+func isSuffixedTable(tableName, projectionTable string) bool {
+	suffix := strings.TrimPrefix(tableName, projectionTable+"_")
+	return suffix != tableName && !strings.HasPrefix("_"+suffix, RebuildSuffix)
+}
+
+func splitTableName(name string) (schema, table string) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return "public", name
+	}
+	return name[:i], name[i+1:]
+}
@@ -0,0 +1,262 @@
+package crdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/handler"
+)
+
+// testLocker returns the errors in order, the last error is returned for all further attempts
+type testLocker struct {
+	mu       sync.Mutex
+	errs     []error
+	attempts int
+	unlocked []string
+}
+
+func (l *testLocker) Lock(ctx context.Context, _ time.Duration, _ ...string) <-chan error {
+	l.mu.Lock()
+	err := l.errs[len(l.errs)-1]
+	if l.attempts < len(l.errs) {
+		err = l.errs[l.attempts]
+	}
+	l.attempts++
+	l.mu.Unlock()
+
+	errs := make(chan error)
+	go func() {
+		select {
+		case errs <- err:
+		case <-ctx.Done():
+		}
+		<-ctx.Done()
+		close(errs)
+	}()
+	return errs
+}
+
+func (l *testLocker) Unlock(instanceIDs ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.unlocked = append(l.unlocked, instanceIDs...)
+	return nil
+}
+
+func expectSwap(m sqlmock.Sqlmock) {
+	m.ExpectBegin()
+	m.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(lockCurrentSequencesStmtFormat, "projections.current_sequences"))).
+		WithArgs("projections.test").
+		WillReturnRows(sqlmock.NewRows([]string{"current_sequence"}).AddRow(10))
+	m.ExpectQuery(regexp.QuoteMeta(relationsStmt)).
+		WithArgs("projections", "test_rebuild%").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "table_type"}).
+			AddRow("test_rebuild", "BASE TABLE").
+			AddRow("test_rebuild_suffix", "BASE TABLE"))
+	m.ExpectExec(regexp.QuoteMeta("DROP TABLE IF EXISTS projections.test CASCADE")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(regexp.QuoteMeta("DROP TABLE IF EXISTS projections.test_suffix CASCADE")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(regexp.QuoteMeta("ALTER TABLE projections.test_rebuild RENAME TO projections.test")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(regexp.QuoteMeta("ALTER TABLE projections.test_rebuild_suffix RENAME TO projections.test_suffix")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	for _, table := range []string{"projections.current_sequences", "projections.failed_events"} {
+		m.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(deleteProjectionStmtFormat, table))).
+			WithArgs("projections.test").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	for _, table := range []string{"projections.current_sequences", "projections.failed_events"} {
+		m.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(renameProjectionStmtFormat, table))).
+			WithArgs("projections.test", "projections.test_rebuild").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	m.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(deleteProjectionStmtFormat, "projections.locks"))).
+		WithArgs("projections.test_rebuild").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectCommit()
+}
+
+func TestStatementHandler_lockedSwap(t *testing.T) {
+	type args struct {
+		lockErrs []error
+		timeout  time.Duration
+	}
+	type want struct {
+		expectations []mockExpectation
+		attempts     int
+		unlocked     []string
+		isErr        func(error) bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "locked, swapped",
+			args: args{
+				lockErrs: []error{nil},
+				timeout:  time.Minute,
+			},
+			want: want{
+				expectations: []mockExpectation{expectSwap},
+				attempts:     1,
+				unlocked:     []string{"instance1", "instance2"},
+			},
+		},
+		{
+			name: "locked by running handler, swapped after release",
+			args: args{
+				lockErrs: []error{errors.ThrowAlreadyExists(nil, "CRDB-mmi4J", "projection already locked"), nil},
+				timeout:  time.Minute,
+			},
+			want: want{
+				expectations: []mockExpectation{expectSwap},
+				attempts:     2,
+				unlocked:     []string{"instance1", "instance2"},
+			},
+		},
+		{
+			name: "never locked, not swapped",
+			args: args{
+				lockErrs: []error{errors.ThrowAlreadyExists(nil, "CRDB-mmi4J", "projection already locked")},
+				timeout:  100 * time.Millisecond,
+			},
+			want: want{
+				attempts: 1,
+				isErr:    errors.IsDeadlineExceeded,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			for _, expectation := range tt.want.expectations {
+				expectation(mock)
+			}
+			h := &StatementHandler{
+				ProjectionHandler: &handler.ProjectionHandler{ProjectionName: "projections.test"},
+				client:            &database.DB{DB: client, Database: new(cockroach.Config)},
+				config: StatementHandlerConfig{
+					SequenceTable:     "projections.current_sequences",
+					LockTable:         "projections.locks",
+					FailedEventsTable: "projections.failed_events",
+				},
+			}
+			locker := &testLocker{errs: tt.args.lockErrs}
+			ctx, cancel := context.WithTimeout(context.Background(), tt.args.timeout)
+			defer cancel()
+
+			err = h.lockedSwap(ctx, locker, []string{"instance1", "instance2"}, "projections.test_rebuild")
+			if tt.want.isErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want.isErr != nil && !tt.want.isErr(err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+			if locker.attempts != tt.want.attempts {
+				t.Errorf("expected %d lock attempts, got %d", tt.want.attempts, locker.attempts)
+			}
+			if fmt.Sprint(locker.unlocked) != fmt.Sprint(tt.want.unlocked) {
+				t.Errorf("expected unlocked instances %v, got %v", tt.want.unlocked, locker.unlocked)
+			}
+		})
+	}
+}
+
+func Test_isSuffixedTable(t *testing.T) {
+	type args struct {
+		tableName       string
+		projectionTable string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "same table",
+			args: args{tableName: "users8", projectionTable: "users8"},
+			want: false,
+		},
+		{
+			name: "suffixed table",
+			args: args{tableName: "users8_humans", projectionTable: "users8"},
+			want: true,
+		},
+		{
+			name: "other projection",
+			args: args{tableName: "users80", projectionTable: "users8"},
+			want: false,
+		},
+		{
+			name: "shadow table",
+			args: args{tableName: "users8_rebuild", projectionTable: "users8"},
+			want: false,
+		},
+		{
+			name: "suffixed shadow table",
+			args: args{tableName: "users8_rebuild_humans", projectionTable: "users8"},
+			want: false,
+		},
+		{
+			name: "suffixed table of shadow",
+			args: args{tableName: "users8_rebuild_humans", projectionTable: "users8_rebuild"},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSuffixedTable(tt.args.tableName, tt.args.projectionTable); got != tt.want {
+				t.Errorf("isSuffixedTable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_splitTableName(t *testing.T) {
+	tests := []struct {
+		name       string
+		tableName  string
+		wantSchema string
+		wantTable  string
+	}{
+		{
+			name:       "with schema",
+			tableName:  "projections.users8",
+			wantSchema: "projections",
+			wantTable:  "users8",
+		},
+		{
+			name:       "without schema",
+			tableName:  "users8",
+			wantSchema: "public",
+			wantTable:  "users8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, table := splitTableName(tt.tableName)
+			if schema != tt.wantSchema || table != tt.wantTable {
+				t.Errorf("splitTableName() = %s, %s, want %s, %s", schema, table, tt.wantSchema, tt.wantTable)
+			}
+		})
+	}
+}
@@ -100,7 +100,12 @@ func NewProjectionHandler(
 	}
 
 	go func() {
-		<-initialized
+		select {
+		case <-initialized:
+		case <-ctx.Done():
+			// the handler was never started
+			return
+		}
 		if !h.reduceScheduledPseudoEvent {
 			go h.subscribe(ctx)
 		}
@@ -110,6 +115,11 @@ func NewProjectionHandler(
 	return h
 }
 
+// Name returns the name of the projection
+func (h *ProjectionHandler) Name() string {
+	return h.ProjectionName
+}
+
 // Trigger handles all events for the provided instances (or current instance from context if non specified)
 // by calling FetchEvents and Process until the amount of events is smaller than the BulkLimit
 func (h *ProjectionHandler) Trigger(ctx context.Context, instances ...string) error {
@@ -0,0 +1,291 @@
+package eventstore_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/repository/action"
+	"github.com/zitadel/zitadel/internal/repository/idpintent"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/keypair"
+	"github.com/zitadel/zitadel/internal/repository/lockout"
+	"github.com/zitadel/zitadel/internal/repository/milestone"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/project"
+	"github.com/zitadel/zitadel/internal/repository/quota"
+	"github.com/zitadel/zitadel/internal/repository/session"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/repository/usergrant"
+)
+
+// filterRepo returns the given events on every filter
+type filterRepo struct {
+	repository.Repository
+	events []*repository.Event
+}
+
+func (repo *filterRepo) Filter(context.Context, *repository.SearchQuery) ([]*repository.Event, error) {
+	return repo.events, nil
+}
+
+// TestEventMappers_roundTrip ensures the payload of every registered event type
+// is the same after it was mapped, pushed and mapped again
+func TestEventMappers_roundTrip(t *testing.T) {
+	repo := new(filterRepo)
+	es := eventstore.NewEventstore(eventstore.TestConfig(repo))
+	action.RegisterEventMappers(es)
+	idpintent.RegisterEventMappers(es)
+	instance.RegisterEventMappers(es)
+	keypair.RegisterEventMappers(es)
+	lockout.RegisterEventMappers(es)
+	milestone.RegisterEventMappers(es)
+	org.RegisterEventMappers(es)
+	project.RegisterEventMappers(es)
+	quota.RegisterEventMappers(es)
+	session.RegisterEventMappers(es)
+	user.RegisterEventMappers(es)
+	usergrant.RegisterEventMappers(es)
+
+	for _, eventType := range es.EventTypes() {
+		t.Run(eventType, func(t *testing.T) {
+			mapped := filterEvent(t, es, repo, &repository.Event{
+				Type:        repository.EventType(eventType),
+				AggregateID: "aggregateID",
+				Data:        []byte("{}"),
+			})
+			cmd, ok := mapped.(eventstore.Command)
+			if !ok {
+				return
+			}
+			data, err := eventstore.EventData(cmd)
+			if err != nil {
+				t.Fatalf("unable to get data of mapped event: %v", err)
+			}
+			if data == nil {
+				data = []byte("{}")
+			}
+			remapped := filterEvent(t, es, repo, &repository.Event{
+				Type:        repository.EventType(eventType),
+				AggregateID: "aggregateID",
+				Data:        data,
+			})
+			remappedData, err := eventstore.EventData(remapped.(eventstore.Command))
+			if err != nil {
+				t.Fatalf("unable to get data of remapped event: %v", err)
+			}
+			if remappedData == nil {
+				remappedData = []byte("{}")
+			}
+			if !bytes.Equal(data, remappedData) {
+				t.Errorf("round trip changed the payload: got %s, want %s", remappedData, data)
+			}
+		})
+	}
+}
+
+// TestEventMappers_upcast ensures payloads of previous aggregate versions are mapped
+func TestEventMappers_upcast(t *testing.T) {
+	repo := new(filterRepo)
+	es := eventstore.NewEventstore(eventstore.TestConfig(repo))
+	// the commands and the queries register the mappers on the same eventstore
+	user.RegisterEventMappers(es)
+	user.RegisterEventMappers(es)
+
+	tests := []struct {
+		name      string
+		eventType repository.EventType
+		version   repository.Version
+		data      string
+		want      language.Tag
+	}{
+		{
+			name:      "human added v1, free text language removed",
+			eventType: repository.EventType(user.HumanAddedType),
+			version:   "v1",
+			data:      `{"userName":"gigi","preferredLanguage":"Deutsch (Schweiz)"}`,
+			want:      language.Und,
+		},
+		{
+			name:      "human added v1, valid language kept",
+			eventType: repository.EventType(user.HumanAddedType),
+			version:   "v1",
+			data:      `{"userName":"gigi","preferredLanguage":"de-CH"}`,
+			want:      language.MustParse("de-CH"),
+		},
+		{
+			name:      "user added v1, free text language removed",
+			eventType: repository.EventType(user.UserV1AddedType),
+			version:   "v1",
+			data:      `{"userName":"gigi","preferredLanguage":"german"}`,
+			want:      language.Und,
+		},
+		{
+			name:      "human registered v1, free text language removed",
+			eventType: repository.EventType(user.HumanRegisteredType),
+			version:   "v1",
+			data:      `{"userName":"gigi","preferredLanguage":""}`,
+			want:      language.Und,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapped := filterEvent(t, es, repo, &repository.Event{
+				Type:        tt.eventType,
+				Version:     tt.version,
+				AggregateID: "aggregateID",
+				Data:        []byte(tt.data),
+			})
+			var got language.Tag
+			switch e := mapped.(type) {
+			case *user.HumanAddedEvent:
+				got = e.PreferredLanguage
+			case *user.HumanRegisteredEvent:
+				got = e.PreferredLanguage
+			default:
+				t.Fatalf("unexpected event %T", mapped)
+			}
+			if got != tt.want {
+				t.Errorf("preferred language = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEventMappers_upcastRoundTrip ensures legacy payloads of the first aggregate version are upcasted
+// and the upcasted payload is the same after it was pushed in the current version and mapped again
+func TestEventMappers_upcastRoundTrip(t *testing.T) {
+	repo := new(filterRepo)
+	es := eventstore.NewEventstore(eventstore.TestConfig(repo))
+	user.RegisterEventMappers(es)
+
+	// payload of human users as stored by the first aggregate version,
+	// the preferred language was entered as free text
+	legacy := `{
+		"userName": "gigi",
+		"firstName": "Gigi",
+		"lastName": "Giraffe",
+		"nickName": "gigi",
+		"displayName": "Gigi Giraffe",
+		"preferredLanguage": "Deutsch (Schweiz)",
+		"gender": 1,
+		"email": "gigi@zitadel.com",
+		"phone": "+41791234567",
+		"country": "CH",
+		"locality": "Bern",
+		"postalCode": "3000",
+		"region": "BE",
+		"streetAddress": "Bundesplatz 3"
+	}`
+	tests := []struct {
+		name      string
+		eventType repository.EventType
+		data      string
+		language  language.Tag
+	}{
+		{
+			name:      "user added v1",
+			eventType: repository.EventType(user.UserV1AddedType),
+			data:      legacy,
+			language:  language.Und,
+		},
+		{
+			name:      "user registered v1",
+			eventType: repository.EventType(user.UserV1RegisteredType),
+			data:      legacy,
+			language:  language.Und,
+		},
+		{
+			name:      "human added v1",
+			eventType: repository.EventType(user.HumanAddedType),
+			data:      legacy,
+			language:  language.Und,
+		},
+		{
+			name:      "human registered v1",
+			eventType: repository.EventType(user.HumanRegisteredType),
+			data:      legacy,
+			language:  language.Und,
+		},
+		{
+			name:      "human added v1, well-formed language",
+			eventType: repository.EventType(user.HumanAddedType),
+			data:      strings.Replace(legacy, "Deutsch (Schweiz)", "de-CH", 1),
+			language:  language.MustParse("de-CH"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapped := filterEvent(t, es, repo, &repository.Event{
+				Type:        tt.eventType,
+				Version:     "v1",
+				AggregateID: "aggregateID",
+				Data:        []byte(tt.data),
+			})
+			assertUpcastedHuman(t, mapped, tt.language)
+
+			data, err := eventstore.EventData(mapped.(eventstore.Command))
+			if err != nil {
+				t.Fatalf("unable to get data of upcasted event: %v", err)
+			}
+			remapped := filterEvent(t, es, repo, &repository.Event{
+				Type:        tt.eventType,
+				Version:     user.AggregateVersion,
+				AggregateID: "aggregateID",
+				Data:        data,
+			})
+			assertUpcastedHuman(t, remapped, tt.language)
+			remappedData, err := eventstore.EventData(remapped.(eventstore.Command))
+			if err != nil {
+				t.Fatalf("unable to get data of remapped event: %v", err)
+			}
+			if !bytes.Equal(data, remappedData) {
+				t.Errorf("round trip changed the payload: got %s, want %s", remappedData, data)
+			}
+		})
+	}
+}
+
+func assertUpcastedHuman(t *testing.T, event eventstore.Event, preferredLanguage language.Tag) {
+	t.Helper()
+	type human struct {
+		userName, firstName, lastName, displayName string
+		preferredLanguage                          language.Tag
+		gender                                     domain.Gender
+		email                                      domain.EmailAddress
+		phone                                      domain.PhoneNumber
+		country, streetAddress                     string
+	}
+	var got human
+	switch e := event.(type) {
+	case *user.HumanAddedEvent:
+		got = human{e.UserName, e.FirstName, e.LastName, e.DisplayName, e.PreferredLanguage, e.Gender, e.EmailAddress, e.PhoneNumber, e.Country, e.StreetAddress}
+	case *user.HumanRegisteredEvent:
+		got = human{e.UserName, e.FirstName, e.LastName, e.DisplayName, e.PreferredLanguage, e.Gender, e.EmailAddress, e.PhoneNumber, e.Country, e.StreetAddress}
+	default:
+		t.Fatalf("unexpected event %T", event)
+	}
+	want := human{"gigi", "Gigi", "Giraffe", "Gigi Giraffe", preferredLanguage, domain.GenderFemale, "gigi@zitadel.com", "+41791234567", "CH", "Bundesplatz 3"}
+	if got != want {
+		t.Errorf("upcasted human = %+v, want %+v", got, want)
+	}
+}
+
+func filterEvent(t *testing.T, es *eventstore.Eventstore, repo *filterRepo, event *repository.Event) eventstore.Event {
+	t.Helper()
+	repo.events = []*repository.Event{event}
+	events, err := es.Filter(context.Background(), eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).AddQuery().AggregateTypes("test").Builder())
+	if err != nil {
+		t.Fatalf("unable to map event: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected one event got %d", len(events))
+	}
+	return events[0]
+}
@@ -0,0 +1,137 @@
+package eventstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/eventstore/pii"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+// RegisterPIIFields registers the top level fields of the event type which contain personal data
+// the fields are encrypted with a key per aggregate if the encryption of personal data is enabled
+func (es *Eventstore) RegisterPIIFields(eventType EventType, fields ...string) *Eventstore {
+	if es.pii == nil || eventType == "" {
+		return es
+	}
+	es.pii.RegisterFields(string(eventType), fields...)
+	return es
+}
+
+// RegisterPIIErasure registers an event type which erases the personal data of its aggregate
+// after the event is pushed the personal data of all events of the aggregate can't be decrypted anymore
+func (es *Eventstore) RegisterPIIErasure(eventType EventType) *Eventstore {
+	if es.pii == nil || eventType == "" {
+		return es
+	}
+	es.pii.RegisterErasure(string(eventType))
+	return es
+}
+
+// PII returns the encryption of personal data, so v1 eventstores are able to decrypt the events
+func (es *Eventstore) PII() *pii.Crypto {
+	return es.pii
+}
+
+func (es *Eventstore) encryptPII(events []*repository.Event) (err error) {
+	if es.pii == nil {
+		return nil
+	}
+	for _, event := range events {
+		event.Data, err = es.pii.Encrypt(event.InstanceID, string(event.AggregateType), event.AggregateID, string(event.Type), event.Data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decryptPII returns copies of the events which contained encrypted personal data
+func (es *Eventstore) decryptPII(events []*repository.Event) ([]*repository.Event, error) {
+	if es.pii == nil {
+		return events, nil
+	}
+	decrypter := es.pii.NewDecrypter()
+	decrypted := make([]*repository.Event, len(events))
+	for i, event := range events {
+		if !pii.MightBeEncrypted(event.Data) {
+			decrypted[i] = event
+			continue
+		}
+		data, err := decrypter.Decrypt(event.InstanceID, event.AggregateID, event.Data)
+		if err != nil {
+			return nil, err
+		}
+		copied := *event
+		copied.Data = data
+		decrypted[i] = &copied
+	}
+	return decrypted, nil
+}
+
+// erasePII destroys the keys and removes the snapshots of the aggregates of erasing events
+// the events are already pushed, so a failure is only logged and the key is erased by [Eventstore.StartPIIErasure],
+// snapshots which couldn't be removed are unreadable as soon as the key is erased
+func (es *Eventstore) erasePII(ctx context.Context, events []*repository.Event) {
+	if es.pii == nil {
+		return
+	}
+	for _, event := range events {
+		if !es.pii.IsErasure(string(event.Type)) {
+			continue
+		}
+		logger := logging.WithFields("instance", event.InstanceID, "aggregate", event.AggregateID)
+		err := es.pii.Erase(event.InstanceID, event.AggregateID, string(event.Type))
+		logger.OnError(err).Error("unable to erase personal data")
+		if !es.snapshots.Enabled {
+			continue
+		}
+		// snapshots contain the decrypted state of the aggregate
+		err = es.repo.DeleteSnapshots(ctx, event.InstanceID, event.AggregateID)
+		logger.OnError(err).Error("unable to remove snapshots of erased aggregate")
+	}
+}
+
+// encryptSnapshot encrypts the state of the aggregate with its personal data key,
+// ok is false if the state must not be stored because the aggregate has no key
+func (es *Eventstore) encryptSnapshot(instanceID, aggregateID string, data []byte) ([]byte, bool, error) {
+	if es.pii == nil {
+		return data, true, nil
+	}
+	return es.pii.EncryptSnapshot(instanceID, aggregateID, data)
+}
+
+// decryptSnapshot decrypts the state of the aggregate,
+// ok is false if the state can't be used because the key of the aggregate was erased
+func (es *Eventstore) decryptSnapshot(instanceID, aggregateID string, data []byte) ([]byte, bool, error) {
+	if es.pii == nil {
+		return data, true, nil
+	}
+	return es.pii.DecryptSnapshot(instanceID, aggregateID, data)
+}
+
+// StartPIIErasure periodically destroys the keys of aggregates with erasing events
+// which could not be destroyed on push, until ctx is done
+func (es *Eventstore) StartPIIErasure(ctx context.Context, interval time.Duration) {
+	if es.pii == nil || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				erased, err := es.pii.EraseRemaining()
+				logging.OnError(err).Error("unable to erase personal data")
+				if erased > 0 {
+					logging.WithFields("keys", erased).Info("erased personal data")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,241 @@
+package eventstore_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/pii"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/eventstore/repository/memory"
+	"github.com/zitadel/zitadel/internal/repository/idpintent"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+type keyStorage struct {
+	keys map[string]string
+}
+
+func (s *keyStorage) ReadPIIKey(instanceID, aggregateID string) (string, error) {
+	key, ok := s.keys[instanceID+aggregateID]
+	if !ok {
+		return "", errors.ThrowNotFound(nil, "V2-aeG0a", "key not found")
+	}
+	return key, nil
+}
+
+func (s *keyStorage) EnsurePIIKey(instanceID, _, aggregateID, key string) (string, error) {
+	if existing, ok := s.keys[instanceID+aggregateID]; ok {
+		return existing, nil
+	}
+	s.keys[instanceID+aggregateID] = key
+	return key, nil
+}
+
+func (s *keyStorage) DeletePIIKey(instanceID, aggregateID string) error {
+	delete(s.keys, instanceID+aggregateID)
+	return nil
+}
+
+func (s *keyStorage) DeleteErasedPIIKeys([]string) (int64, error) {
+	return 0, nil
+}
+
+// pushRecorder keeps the events as they were stored
+type pushRecorder struct {
+	*memory.Memory
+	pushed []*repository.Event
+}
+
+func (repo *pushRecorder) Push(ctx context.Context, events []*repository.Event, uniqueConstraints ...*repository.UniqueConstraint) error {
+	repo.pushed = append(repo.pushed, events...)
+	return repo.Memory.Push(ctx, events, uniqueConstraints...)
+}
+
+// rawCommand pushes the payload as is
+type rawCommand struct {
+	eventstore.BaseEvent
+	data []byte
+}
+
+func (c *rawCommand) Data() interface{} {
+	if c.data == nil {
+		return nil
+	}
+	return c.data
+}
+
+func (c *rawCommand) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+// TestPersonalData_erased ensures the personal data of the registered events
+// is stored encrypted and can't be decoded anymore after the owner was removed
+func TestPersonalData_erased(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	userAgg := &user.NewAggregate("user", "org").Aggregate
+	intentAgg := &idpintent.NewAggregate("intent", "instance").Aggregate
+	human := `{"userName":"gigi","firstName":"Gigi","lastName":"Giraffe","nickName":"giri","displayName":"Gigi Giraffe","email":"gigi@zitadel.com","phone":"+41791234567","country":"CH","locality":"Bern","postalCode":"3000","region":"BE","streetAddress":"Bundesplatz 3"}`
+	humanValues := []string{"Gigi", "Giraffe", "giri", "gigi@zitadel.com", "+41791234567", "Bern", "Bundesplatz 3"}
+	tests := []struct {
+		name      string
+		aggregate *eventstore.Aggregate
+		eventType eventstore.EventType
+		data      string
+		values    []string
+	}{
+		{
+			name:      "user added v1",
+			aggregate: userAgg,
+			eventType: user.UserV1AddedType,
+			data:      human,
+			values:    humanValues,
+		},
+		{
+			name:      "user registered v1",
+			aggregate: userAgg,
+			eventType: user.UserV1RegisteredType,
+			data:      human,
+			values:    humanValues,
+		},
+		{
+			name:      "profile changed v1",
+			aggregate: userAgg,
+			eventType: user.UserV1ProfileChangedType,
+			data:      `{"firstName":"Gigi","lastName":"Giraffe","nickName":"giri","displayName":"Gigi Giraffe"}`,
+			values:    []string{"Gigi", "Giraffe", "giri"},
+		},
+		{
+			name:      "email changed v1",
+			aggregate: userAgg,
+			eventType: user.UserV1EmailChangedType,
+			data:      `{"email":"gigi@zitadel.com"}`,
+			values:    []string{"gigi@zitadel.com"},
+		},
+		{
+			name:      "phone changed v1",
+			aggregate: userAgg,
+			eventType: user.UserV1PhoneChangedType,
+			data:      `{"phone":"+41791234567"}`,
+			values:    []string{"+41791234567"},
+		},
+		{
+			name:      "address changed v1",
+			aggregate: userAgg,
+			eventType: user.UserV1AddressChangedType,
+			data:      `{"country":"CH","locality":"Bern","postalCode":"3000","region":"BE","streetAddress":"Bundesplatz 3"}`,
+			values:    []string{"Bern", "Bundesplatz 3"},
+		},
+		{
+			name:      "human added",
+			aggregate: userAgg,
+			eventType: user.HumanAddedType,
+			data:      human,
+			values:    humanValues,
+		},
+		{
+			name:      "human address changed",
+			aggregate: userAgg,
+			eventType: user.HumanAddressChangedType,
+			data:      `{"country":"CH","locality":"Bern","postalCode":"3000","region":"BE","streetAddress":"Bundesplatz 3"}`,
+			values:    []string{"Bern", "Bundesplatz 3"},
+		},
+		{
+			name:      "idp link added",
+			aggregate: userAgg,
+			eventType: user.UserIDPLinkAddedType,
+			data:      `{"idpConfigId":"idp","userId":"external","displayName":"Gigi Giraffe"}`,
+			values:    []string{"Gigi Giraffe"},
+		},
+		{
+			name:      "metadata set",
+			aggregate: userAgg,
+			eventType: user.MetadataSetType,
+			// base64 of "Gigi Giraffe"
+			data:   `{"key":"name","value":"R2lnaSBHaXJhZmZl"}`,
+			values: []string{"R2lnaSBHaXJhZmZl"},
+		},
+		{
+			name:      "idp intent succeeded",
+			aggregate: intentAgg,
+			eventType: idpintent.SucceededEventType,
+			// idpUser and idpMappedUser are base64 of {"name":"Gigi Giraffe"}
+			data:   `{"idpUser":"eyJuYW1lIjoiR2lnaSBHaXJhZmZlIn0=","idpMappedUser":"eyJuYW1lIjoiR2lnaSBHaXJhZmZlIn0=","idpUserId":"external","idpUserName":"gigi@idp.com","userId":"user","idpIdToken":"id-token"}`,
+			values: []string{"eyJuYW1lIjoiR2lnaSBHaXJhZmZlIn0=", "gigi@idp.com", "id-token"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &pushRecorder{Memory: memory.NewMemory(false)}
+			if err := repo.CreateInstance(ctx, "instance"); err != nil {
+				t.Fatal(err)
+			}
+			config := eventstore.TestConfig(repo)
+			config.PII = pii.Config{Enabled: true}
+			config.PIIKeyStorage = &keyStorage{keys: make(map[string]string)}
+			es := eventstore.NewEventstore(config)
+			user.RegisterEventMappers(es)
+			idpintent.RegisterEventMappers(es)
+
+			push := func(eventType eventstore.EventType, data []byte) {
+				t.Helper()
+				_, err := es.Push(ctx, &rawCommand{
+					BaseEvent: *eventstore.NewBaseEventForPush(ctx, tt.aggregate, eventType),
+					data:      data,
+				})
+				if err != nil {
+					t.Fatalf("unable to push: %v", err)
+				}
+			}
+			decoded := func() []byte {
+				t.Helper()
+				events, err := es.Filter(ctx, eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					AddQuery().
+					AggregateTypes(tt.aggregate.Type).
+					AggregateIDs(tt.aggregate.ID).
+					EventTypes(tt.eventType).
+					Builder(),
+				)
+				if err != nil {
+					t.Fatalf("unable to filter: %v", err)
+				}
+				if len(events) != 1 {
+					t.Fatalf("expected one event, got %d", len(events))
+				}
+				data, err := eventstore.EventData(events[0].(eventstore.Command))
+				if err != nil {
+					t.Fatalf("unable to get data: %v", err)
+				}
+				return data
+			}
+
+			push(tt.eventType, []byte(tt.data))
+			stored := repo.pushed[0].Data
+			data := decoded()
+			for _, value := range tt.values {
+				if bytes.Contains(stored, []byte(value)) {
+					t.Errorf("%q stored in plaintext: %s", value, stored)
+				}
+				if !bytes.Contains(data, []byte(value)) {
+					t.Errorf("%q not decrypted: %s", value, data)
+				}
+			}
+
+			switch tt.aggregate.Type {
+			case user.AggregateType:
+				push(user.UserRemovedType, nil)
+			case idpintent.AggregateType:
+				push(idpintent.UserRemovedEventType, nil)
+			}
+			data = decoded()
+			for _, value := range tt.values {
+				if bytes.Contains(data, []byte(value)) {
+					t.Errorf("%q readable after erasure: %s", value, data)
+				}
+			}
+		})
+	}
+}
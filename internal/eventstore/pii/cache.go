@@ -0,0 +1,75 @@
+package pii
+
+import (
+	"sync"
+	"time"
+)
+
+// maxCachedKeys limits the memory of the cache,
+// if it's reached the expired keys are removed or the cache is cleared
+const maxCachedKeys = 10000
+
+type cachedKey struct {
+	value     string
+	expiresAt time.Time
+}
+
+// keyCache caches the existing keys of the aggregates for a ttl
+// missing keys are not cached, so created keys are used immediately
+type keyCache struct {
+	ttl   time.Duration
+	mutex sync.RWMutex
+	keys  map[string]cachedKey
+}
+
+func newKeyCache(ttl time.Duration) *keyCache {
+	return &keyCache{
+		ttl:  ttl,
+		keys: make(map[string]cachedKey),
+	}
+}
+
+func (c *keyCache) get(id string) (string, bool) {
+	if c.ttl <= 0 {
+		return "", false
+	}
+	c.mutex.RLock()
+	key, ok := c.keys[id]
+	c.mutex.RUnlock()
+	if !ok || time.Now().After(key.expiresAt) {
+		return "", false
+	}
+	return key.value, true
+}
+
+func (c *keyCache) set(id, value string) {
+	if c.ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.keys) >= maxCachedKeys {
+		for cachedID, key := range c.keys {
+			if now.After(key.expiresAt) {
+				delete(c.keys, cachedID)
+			}
+		}
+	}
+	if len(c.keys) >= maxCachedKeys {
+		c.keys = make(map[string]cachedKey)
+	}
+	c.keys[id] = cachedKey{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *keyCache) delete(id string) {
+	c.mutex.Lock()
+	delete(c.keys, id)
+	c.mutex.Unlock()
+}
+
+func (c *keyCache) clear() {
+	c.mutex.Lock()
+	c.keys = make(map[string]cachedKey)
+	c.mutex.Unlock()
+}
@@ -0,0 +1,335 @@
+// Package pii encrypts personal data in event payloads with a data key per aggregate.
+// As soon as the key of an aggregate is erased the personal data of its events can't be read anymore
+// (crypto-shredding), the events are still reduced but without the personal data.
+package pii
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+const (
+	envelopeKey = "encryptedPII"
+	// erasureAttempts is the amount of immediate tries to erase a key,
+	// keys which still exist are erased by [Crypto.EraseRemaining]
+	erasureAttempts = 3
+)
+
+type Config struct {
+	// Enabled encrypts the registered fields of pushed events,
+	// encrypted fields are decrypted independent of this flag
+	Enabled bool
+	// KeyCacheTTL is the duration the keys of the aggregates are cached,
+	// erasures of other instances become visible after this duration at the latest
+	KeyCacheTTL time.Duration
+	// ErasureInterval is the time between the erasures of keys which failed to be erased on push
+	ErasureInterval time.Duration
+}
+
+type envelope struct {
+	EncryptedPII []byte `json:"encryptedPII"`
+}
+
+// Crypto encrypts and decrypts the personal data of event payloads
+// without key storage nothing is encrypted and encrypted fields are removed on decryption
+type Crypto struct {
+	keyStorage crypto.PIIKeyStorage
+	enabled    bool
+	keys       *keyCache
+
+	mutex    sync.RWMutex
+	fields   map[string][]string
+	erasures map[string]struct{}
+}
+
+func New(config Config, keyStorage crypto.PIIKeyStorage) *Crypto {
+	return &Crypto{
+		keyStorage: keyStorage,
+		enabled:    config.Enabled && keyStorage != nil,
+		keys:       newKeyCache(config.KeyCacheTTL),
+	}
+}
+
+// RegisterFields registers the top level fields of the event type which contain personal data
+func (c *Crypto) RegisterFields(eventType string, fields ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.fields == nil {
+		c.fields = make(map[string][]string)
+	}
+	c.fields[eventType] = append(c.fields[eventType], fields...)
+}
+
+// RegisterErasure registers an event type which erases the key of its aggregate
+func (c *Crypto) RegisterErasure(eventType string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.erasures == nil {
+		c.erasures = make(map[string]struct{})
+	}
+	c.erasures[eventType] = struct{}{}
+}
+
+// Encrypt encrypts the registered fields of the event type in data
+// with the key of the aggregate, the key is created if it doesn't exist yet
+func (c *Crypto) Encrypt(instanceID, aggregateType, aggregateID, eventType string, data []byte) ([]byte, error) {
+	if !c.enabled || len(data) == 0 {
+		return data, nil
+	}
+	c.mutex.RLock()
+	fields := c.fields[eventType]
+	c.mutex.RUnlock()
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	payload := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &payload); err != nil {
+		// only objects can contain fields
+		return data, nil
+	}
+	var key string
+	for _, field := range fields {
+		value, ok := payload[field]
+		if !ok || bytes.Equal(value, []byte("null")) || isEnvelope(value) {
+			continue
+		}
+		if key == "" {
+			var err error
+			if key, err = c.ensureKey(instanceID, aggregateType, aggregateID); err != nil {
+				return nil, err
+			}
+		}
+		encrypted, err := crypto.EncryptAES(value, key)
+		if err != nil {
+			return nil, errors.ThrowInternal(err, "PII-ieW4u", "unable to encrypt personal data")
+		}
+		if payload[field], err = json.Marshal(&envelope{EncryptedPII: encrypted}); err != nil {
+			return nil, errors.ThrowInternal(err, "PII-Ahk8e", "unable to marshal personal data")
+		}
+	}
+	if key == "" {
+		return data, nil
+	}
+	encrypted, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "PII-oH3ie", "unable to marshal payload")
+	}
+	return encrypted, nil
+}
+
+// ensureKey returns the cached key of the aggregate
+// otherwise the key is created if the aggregate has none yet
+func (c *Crypto) ensureKey(instanceID, aggregateType, aggregateID string) (string, error) {
+	id := keyID(instanceID, aggregateID)
+	if key, ok := c.keys.get(id); ok {
+		return key, nil
+	}
+	key, err := crypto.NewKey(id)
+	if err != nil {
+		return "", errors.ThrowInternal(err, "PII-ohX5a", "unable to create key")
+	}
+	stored, err := c.keyStorage.EnsurePIIKey(instanceID, aggregateType, aggregateID, key.Value)
+	if err != nil {
+		return "", err
+	}
+	c.keys.set(id, stored)
+	return stored, nil
+}
+
+// EncryptSnapshot encrypts the complete state of an aggregate (e.g. a snapshot of a write model)
+// with the existing key of the aggregate, no key is created.
+// ok is false if the aggregate has no key (anymore), the state must not be stored then.
+// If the encryption is disabled the state is returned as is.
+func (c *Crypto) EncryptSnapshot(instanceID, aggregateID string, data []byte) (_ []byte, ok bool, err error) {
+	if !c.enabled {
+		return data, true, nil
+	}
+	key, err := c.NewDecrypter().key(instanceID, aggregateID)
+	if err != nil || key == "" {
+		return nil, false, err
+	}
+	encrypted, err := crypto.EncryptAES(data, key)
+	if err != nil {
+		return nil, false, errors.ThrowInternal(err, "PII-Ro4ei", "unable to encrypt snapshot")
+	}
+	data, err = json.Marshal(&envelope{EncryptedPII: encrypted})
+	if err != nil {
+		return nil, false, errors.ThrowInternal(err, "PII-eiT7a", "unable to marshal snapshot")
+	}
+	return data, true, nil
+}
+
+// DecryptSnapshot decrypts a state encrypted by [Crypto.EncryptSnapshot], unencrypted states are returned as is.
+// ok is false if the key of the aggregate was erased, the state must not be used then.
+func (c *Crypto) DecryptSnapshot(instanceID, aggregateID string, data []byte) (_ []byte, ok bool, err error) {
+	if !isEnvelope(data) {
+		return data, true, nil
+	}
+	key, err := c.NewDecrypter().key(instanceID, aggregateID)
+	if err != nil || key == "" {
+		return nil, false, err
+	}
+	encrypted := new(envelope)
+	if err = json.Unmarshal(data, encrypted); err != nil {
+		return nil, false, errors.ThrowInternal(err, "PII-Xah2u", "unable to unmarshal snapshot")
+	}
+	decrypted, err := crypto.DecryptAES(encrypted.EncryptedPII, key)
+	if err != nil {
+		return nil, false, errors.ThrowInternal(err, "PII-Jae5o", "unable to decrypt snapshot")
+	}
+	return decrypted, true, nil
+}
+
+// IsErasure checks if the event type is registered as erasure
+func (c *Crypto) IsErasure(eventType string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	_, ok := c.erasures[eventType]
+	return ok
+}
+
+// Erase destroys the key of the aggregate if the event type is registered as erasure
+// the erasure is retried a few times, keys which still exist are erased by [Crypto.EraseRemaining]
+func (c *Crypto) Erase(instanceID, aggregateID, eventType string) (err error) {
+	if c.keyStorage == nil {
+		return nil
+	}
+	if !c.IsErasure(eventType) {
+		return nil
+	}
+	id := keyID(instanceID, aggregateID)
+	for i := 0; i < erasureAttempts; i++ {
+		if err = c.keyStorage.DeletePIIKey(instanceID, aggregateID); err == nil {
+			break
+		}
+	}
+	// also evicts keys cached by concurrent reads during the deletion
+	c.keys.delete(id)
+	return err
+}
+
+// EraseRemaining destroys the keys of all aggregates with an erasure event
+// and returns the amount of destroyed keys
+func (c *Crypto) EraseRemaining() (int64, error) {
+	if c.keyStorage == nil {
+		return 0, nil
+	}
+	c.mutex.RLock()
+	erasures := make([]string, 0, len(c.erasures))
+	for eventType := range c.erasures {
+		erasures = append(erasures, eventType)
+	}
+	c.mutex.RUnlock()
+	erased, err := c.keyStorage.DeleteErasedPIIKeys(erasures)
+	if erased > 0 {
+		c.keys.clear()
+	}
+	return erased, err
+}
+
+// NewDecrypter returns a decrypter which remembers the missing keys of the aggregates
+// it must only be used for a single batch of events, so keys are not missing forever
+func (c *Crypto) NewDecrypter() *Decrypter {
+	return &Decrypter{
+		crypto:  c,
+		missing: make(map[string]struct{}),
+	}
+}
+
+type Decrypter struct {
+	crypto  *Crypto
+	missing map[string]struct{}
+}
+
+// Decrypt decrypts all encrypted fields of data
+// the fields are removed if the key of the aggregate was erased
+func (d *Decrypter) Decrypt(instanceID, aggregateID string, data []byte) ([]byte, error) {
+	if !MightBeEncrypted(data) {
+		return data, nil
+	}
+	payload := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return data, nil
+	}
+	var changed bool
+	for field, value := range payload {
+		if !isEnvelope(value) {
+			continue
+		}
+		changed = true
+		key, err := d.key(instanceID, aggregateID)
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			delete(payload, field)
+			continue
+		}
+		encrypted := new(envelope)
+		if err = json.Unmarshal(value, encrypted); err != nil {
+			return nil, errors.ThrowInternal(err, "PII-Kai0u", "unable to unmarshal personal data")
+		}
+		decrypted, err := crypto.DecryptAES(encrypted.EncryptedPII, key)
+		if err != nil {
+			return nil, errors.ThrowInternal(err, "PII-eeC0o", "unable to decrypt personal data")
+		}
+		payload[field] = decrypted
+	}
+	if !changed {
+		return data, nil
+	}
+	decrypted, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "PII-Ua1ie", "unable to marshal payload")
+	}
+	return decrypted, nil
+}
+
+// key returns the key of the aggregate
+// an empty key means the key was erased or is not accessible
+func (d *Decrypter) key(instanceID, aggregateID string) (string, error) {
+	id := keyID(instanceID, aggregateID)
+	if key, ok := d.crypto.keys.get(id); ok {
+		return key, nil
+	}
+	if _, ok := d.missing[id]; ok || d.crypto.keyStorage == nil {
+		return "", nil
+	}
+	key, err := d.crypto.keyStorage.ReadPIIKey(instanceID, aggregateID)
+	if errors.IsNotFound(err) {
+		d.missing[id] = struct{}{}
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	d.crypto.keys.set(id, key)
+	return key, nil
+}
+
+// MightBeEncrypted is a cheap check if data could contain encrypted fields
+func MightBeEncrypted(data []byte) bool {
+	return bytes.Contains(data, []byte(envelopeKey))
+}
+
+func isEnvelope(value json.RawMessage) bool {
+	if len(value) == 0 || value[0] != '{' {
+		return false
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(value, &fields); err != nil {
+		return false
+	}
+	_, ok := fields[envelopeKey]
+	return ok && len(fields) == 1
+}
+
+func keyID(instanceID, aggregateID string) string {
+	return instanceID + ":" + aggregateID
+}
@@ -0,0 +1,288 @@
+package pii
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+type testKeyStorage struct {
+	keys         map[string]string
+	reads        int
+	ensures      int
+	deleteErrors int
+	erased       []string
+}
+
+func newTestKeyStorage() *testKeyStorage {
+	return &testKeyStorage{keys: make(map[string]string)}
+}
+
+func (s *testKeyStorage) ReadPIIKey(instanceID, aggregateID string) (string, error) {
+	s.reads++
+	key, ok := s.keys[keyID(instanceID, aggregateID)]
+	if !ok {
+		return "", errors.ThrowNotFound(nil, "PII-ahB4i", "key not found")
+	}
+	return key, nil
+}
+
+func (s *testKeyStorage) EnsurePIIKey(instanceID, _, aggregateID, key string) (string, error) {
+	s.ensures++
+	if existing, ok := s.keys[keyID(instanceID, aggregateID)]; ok {
+		return existing, nil
+	}
+	s.keys[keyID(instanceID, aggregateID)] = key
+	return key, nil
+}
+
+func (s *testKeyStorage) DeletePIIKey(instanceID, aggregateID string) error {
+	if s.deleteErrors > 0 {
+		s.deleteErrors--
+		return errors.ThrowInternal(nil, "PII-Ooj4e", "unable to delete key")
+	}
+	delete(s.keys, keyID(instanceID, aggregateID))
+	return nil
+}
+
+func (s *testKeyStorage) DeleteErasedPIIKeys(erasureTypes []string) (int64, error) {
+	s.erased = erasureTypes
+	deleted := int64(len(s.keys))
+	s.keys = make(map[string]string)
+	return deleted, nil
+}
+
+func newTestCrypto(enabled bool, keyStorage crypto.PIIKeyStorage) *Crypto {
+	c := New(Config{Enabled: enabled, KeyCacheTTL: time.Minute}, keyStorage)
+	c.RegisterFields("user.added", "firstName", "email")
+	c.RegisterErasure("user.removed")
+	return c
+}
+
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid json %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid json %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestCrypto_Encrypt(t *testing.T) {
+	data := []byte(`{"userName":"gigi","firstName":"Gigi","email":null}`)
+	tests := []struct {
+		name       string
+		enabled    bool
+		keyStorage *testKeyStorage
+		eventType  string
+		encrypted  bool
+	}{
+		{
+			name:       "disabled",
+			enabled:    false,
+			keyStorage: newTestKeyStorage(),
+			eventType:  "user.added",
+		},
+		{
+			name:      "no key storage",
+			enabled:   true,
+			eventType: "user.added",
+		},
+		{
+			name:       "unregistered event type",
+			enabled:    true,
+			keyStorage: newTestKeyStorage(),
+			eventType:  "user.changed",
+		},
+		{
+			name:       "encrypted",
+			enabled:    true,
+			keyStorage: newTestKeyStorage(),
+			eventType:  "user.added",
+			encrypted:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keyStorage crypto.PIIKeyStorage
+			if tt.keyStorage != nil {
+				keyStorage = tt.keyStorage
+			}
+			c := newTestCrypto(tt.enabled, keyStorage)
+			got, err := c.Encrypt("instance", "user", "user", tt.eventType, data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.encrypted {
+				if !bytes.Equal(got, data) {
+					t.Errorf("data must not change, got %s", got)
+				}
+				return
+			}
+			if bytes.Contains(got, []byte("Gigi")) {
+				t.Errorf("personal data not encrypted: %s", got)
+			}
+			if !bytes.Contains(got, []byte(`"userName":"gigi"`)) {
+				t.Errorf("unregistered field must not be encrypted: %s", got)
+			}
+			if _, ok := tt.keyStorage.keys[keyID("instance", "user")]; !ok {
+				t.Error("key of aggregate not created")
+			}
+		})
+	}
+}
+
+func TestCrypto_Decrypt(t *testing.T) {
+	keyStorage := newTestKeyStorage()
+	c := newTestCrypto(true, keyStorage)
+	data := []byte(`{"userName":"gigi","firstName":"Gigi","email":"gigi@zitadel.com"}`)
+	encrypted, err := c.Encrypt("instance", "user", "user", "user.added", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("decrypted", func(t *testing.T) {
+		got, err := c.NewDecrypter().Decrypt("instance", "user", encrypted)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertJSON(t, got, string(data))
+	})
+	t.Run("not encrypted", func(t *testing.T) {
+		got, err := c.NewDecrypter().Decrypt("instance", "user", data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("data must not change, got %s", got)
+		}
+	})
+	t.Run("no key storage", func(t *testing.T) {
+		got, err := New(Config{}, nil).NewDecrypter().Decrypt("instance", "user", encrypted)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertJSON(t, got, `{"userName":"gigi"}`)
+	})
+	t.Run("erased", func(t *testing.T) {
+		if err := c.Erase("instance", "user", "user.changed"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := c.NewDecrypter().Decrypt("instance", "user", encrypted)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertJSON(t, got, string(data))
+
+		if err = c.Erase("instance", "user", "user.removed"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err = c.NewDecrypter().Decrypt("instance", "user", encrypted)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertJSON(t, got, `{"userName":"gigi"}`)
+	})
+}
+
+func TestCrypto_keyCache(t *testing.T) {
+	keyStorage := newTestKeyStorage()
+	c := newTestCrypto(true, keyStorage)
+	data := []byte(`{"firstName":"Gigi"}`)
+	for i := 0; i < 2; i++ {
+		if _, err := c.Encrypt("instance", "user", "user", "user.added", data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if keyStorage.ensures != 1 {
+		t.Errorf("key must be cached, got %d key storage calls", keyStorage.ensures)
+	}
+	encrypted, err := c.Encrypt("instance", "user", "user", "user.added", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = c.NewDecrypter().Decrypt("instance", "user", encrypted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keyStorage.reads != 0 {
+		t.Errorf("key must be read from cache, got %d key storage reads", keyStorage.reads)
+	}
+
+	if err = c.Erase("instance", "user", "user.removed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := c.NewDecrypter().Decrypt("instance", "user", encrypted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertJSON(t, got, `{}`)
+}
+
+func TestCrypto_Erase(t *testing.T) {
+	tests := []struct {
+		name         string
+		deleteErrors int
+		wantErr      bool
+	}{
+		{
+			name:         "retried",
+			deleteErrors: erasureAttempts - 1,
+		},
+		{
+			name:         "failed",
+			deleteErrors: erasureAttempts,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyStorage := newTestKeyStorage()
+			c := newTestCrypto(true, keyStorage)
+			if _, err := c.Encrypt("instance", "user", "user", "user.added", []byte(`{"firstName":"Gigi"}`)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			keyStorage.deleteErrors = tt.deleteErrors
+			err := c.Erase("instance", "user", "user.removed")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := keyStorage.keys[keyID("instance", "user")]; ok != tt.wantErr {
+				t.Errorf("key erased: %t, want %t", !ok, !tt.wantErr)
+			}
+			if _, ok := c.keys.get(keyID("instance", "user")); ok {
+				t.Error("key must not be cached after erasure")
+			}
+		})
+	}
+}
+
+func TestCrypto_EraseRemaining(t *testing.T) {
+	keyStorage := newTestKeyStorage()
+	c := newTestCrypto(true, keyStorage)
+	if _, err := c.Encrypt("instance", "user", "user", "user.added", []byte(`{"firstName":"Gigi"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	erased, err := c.EraseRemaining()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if erased != 1 {
+		t.Errorf("expected 1 erased key, got %d", erased)
+	}
+	if !reflect.DeepEqual(keyStorage.erased, []string{"user.removed"}) {
+		t.Errorf("unexpected erasure types %v", keyStorage.erased)
+	}
+	if _, ok := c.keys.get(keyID("instance", "user")); ok {
+		t.Error("cache must be cleared after erasure")
+	}
+}
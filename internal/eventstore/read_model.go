@@ -1,6 +1,10 @@
 package eventstore
 
-import "time"
+import (
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
 
 // ReadModel is the minimum representation of a read model.
 // It implements a basic reducer
@@ -48,3 +52,15 @@ func (rm *ReadModel) Reduce() error {
 	rm.Events = []Event{}
 	return nil
 }
+
+func (rm *ReadModel) snapshotAggregateID() string {
+	return rm.AggregateID
+}
+
+func (rm *ReadModel) restoreSnapshot(snapshot *repository.Snapshot) {
+	rm.ResourceOwner = snapshot.ResourceOwner
+	rm.InstanceID = snapshot.InstanceID
+	rm.ProcessedSequence = snapshot.Sequence
+	rm.CreationDate = snapshot.AggregateCreationDate
+	rm.ChangeDate = snapshot.ChangeDate
+}
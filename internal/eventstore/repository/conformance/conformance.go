@@ -0,0 +1,545 @@
+// Package conformance verifies that implementations of repository.Repository behave the same.
+// The suite shares the storage between the tests, every test creates its own instance
+// and only reads events of this instance, so it can be run against a database which isn't empty.
+package conformance
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/id"
+)
+
+var instanceCount uint64
+
+// Run runs the conformance tests against the repository
+func Run(t *testing.T, repo repository.Repository) {
+	t.Run("push and filter", func(t *testing.T) { testPushAndFilter(t, repo) })
+	t.Run("push unknown instance", func(t *testing.T) { testPushUnknownInstance(t, repo) })
+	t.Run("filter operations", func(t *testing.T) { testFilterOperations(t, repo) })
+	t.Run("filter order and limit", func(t *testing.T) { testFilterOrderAndLimit(t, repo) })
+	t.Run("filter without filters", func(t *testing.T) { testFilterWithoutFilters(t, repo) })
+	t.Run("latest sequence", func(t *testing.T) { testLatestSequence(t, repo) })
+	t.Run("instance ids", func(t *testing.T) { testInstanceIDs(t, repo) })
+	t.Run("unique constraints", func(t *testing.T) { testUniqueConstraints(t, repo) })
+	t.Run("create instance", func(t *testing.T) { testCreateInstance(t, repo) })
+	t.Run("create instance with generated ids", func(t *testing.T) { testCreateInstanceGeneratedIDs(t, repo) })
+	t.Run("snapshots", func(t *testing.T) { testSnapshots(t, repo) })
+}
+
+func newInstance(t *testing.T, repo repository.Repository) string {
+	t.Helper()
+	instanceID := "conformance" + strconv.FormatInt(time.Now().UnixNano(), 10) + "x" + strconv.FormatUint(atomic.AddUint64(&instanceCount, 1), 10)
+	if err := repo.CreateInstance(context.Background(), instanceID); err != nil {
+		t.Fatalf("unable to create instance: %v", err)
+	}
+	return instanceID
+}
+
+func newEvent(instanceID, aggregateID string, eventType repository.EventType, data string) *repository.Event {
+	event := &repository.Event{
+		Type:          eventType,
+		EditorService: "conformance",
+		EditorUser:    "editor",
+		Version:       "v1",
+		AggregateID:   aggregateID,
+		AggregateType: "conformance.aggregate",
+		ResourceOwner: sql.NullString{String: "owner", Valid: true},
+		InstanceID:    instanceID,
+	}
+	if data != "" {
+		event.Data = []byte(data)
+	}
+	return event
+}
+
+func push(t *testing.T, repo repository.Repository, events ...*repository.Event) {
+	t.Helper()
+	if err := repo.Push(context.Background(), events); err != nil {
+		t.Fatalf("unable to push events: %v", err)
+	}
+}
+
+func filter(t *testing.T, repo repository.Repository, searchQuery *repository.SearchQuery) []*repository.Event {
+	t.Helper()
+	events, err := repo.Filter(context.Background(), searchQuery)
+	if err != nil {
+		t.Fatalf("unable to filter events: %v", err)
+	}
+	return events
+}
+
+func instanceQuery(instanceID string, filters ...*repository.Filter) *repository.SearchQuery {
+	return &repository.SearchQuery{
+		Columns: repository.ColumnsEvent,
+		Filters: [][]*repository.Filter{
+			append([]*repository.Filter{
+				repository.NewFilter(repository.FieldInstanceID, instanceID, repository.OperationEquals),
+			}, filters...),
+		},
+	}
+}
+
+func sequences(events []*repository.Event) []uint64 {
+	sequences := make([]uint64, len(events))
+	for i, event := range events {
+		sequences[i] = event.Sequence
+	}
+	return sequences
+}
+
+func assertSequences(t *testing.T, events []*repository.Event, want ...*repository.Event) {
+	t.Helper()
+	if got, want := sequences(events), sequences(want); !reflect.DeepEqual(got, want) {
+		t.Errorf("sequences = %v, want %v", got, want)
+	}
+}
+
+func assertJSON(t *testing.T, got, want []byte) {
+	t.Helper()
+	if len(got) == 0 || len(want) == 0 {
+		if len(got) != len(want) {
+			t.Errorf("data = %s, want %s", got, want)
+		}
+		return
+	}
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid json %s: %v", got, err)
+	}
+	if err := json.Unmarshal(want, &wantValue); err != nil {
+		t.Fatalf("invalid json %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("data = %s, want %s", got, want)
+	}
+}
+
+func testPushAndFilter(t *testing.T, repo repository.Repository) {
+	instanceID := newInstance(t, repo)
+	first := newEvent(instanceID, "agg1", "conformance.added", `{"name": "gigi"}`)
+	second := newEvent(instanceID, "agg2", "conformance.added", "")
+	third := newEvent(instanceID, "agg1", "conformance.changed", `{"name": "giraffe"}`)
+	third.ResourceOwner = sql.NullString{String: "other", Valid: true}
+	push(t, repo, first, second)
+	push(t, repo, third)
+
+	if first.Sequence == 0 || second.Sequence <= first.Sequence || third.Sequence <= second.Sequence {
+		t.Errorf("sequences must increase: %d, %d, %d", first.Sequence, second.Sequence, third.Sequence)
+	}
+	if first.PreviousAggregateSequence != 0 || first.PreviousAggregateTypeSequence != 0 {
+		t.Errorf("first event must not have previous sequences: %d, %d", first.PreviousAggregateSequence, first.PreviousAggregateTypeSequence)
+	}
+	if second.PreviousAggregateSequence != 0 || second.PreviousAggregateTypeSequence != first.Sequence {
+		t.Errorf("previous sequences of second event = %d, %d", second.PreviousAggregateSequence, second.PreviousAggregateTypeSequence)
+	}
+	if third.PreviousAggregateSequence != first.Sequence || third.PreviousAggregateTypeSequence != second.Sequence {
+		t.Errorf("previous sequences of third event = %d, %d", third.PreviousAggregateSequence, third.PreviousAggregateTypeSequence)
+	}
+	if third.ResourceOwner.String != "owner" {
+		t.Errorf("resource owner of the aggregate must not change, got %s", third.ResourceOwner.String)
+	}
+	if first.CreationDate.IsZero() {
+		t.Error("creation date must be set")
+	}
+
+	events := filter(t, repo, instanceQuery(instanceID,
+		repository.NewFilter(repository.FieldAggregateID, "agg1", repository.OperationEquals),
+	))
+	assertSequences(t, events, first, third)
+	if len(events) != 2 {
+		return
+	}
+	got := events[1]
+	if got.Type != third.Type ||
+		got.AggregateType != third.AggregateType ||
+		got.AggregateID != third.AggregateID ||
+		got.Version != third.Version ||
+		got.EditorService != third.EditorService ||
+		got.EditorUser != third.EditorUser ||
+		got.ResourceOwner.String != "owner" ||
+		got.InstanceID != instanceID ||
+		got.PreviousAggregateSequence != third.PreviousAggregateSequence ||
+		got.PreviousAggregateTypeSequence != third.PreviousAggregateTypeSequence {
+		t.Errorf("filtered event = %+v, want %+v", got, third)
+	}
+	if got.CreationDate.IsZero() {
+		t.Error("creation date of filtered event must be set")
+	}
+	assertJSON(t, got.Data, third.Data)
+
+	events = filter(t, repo, instanceQuery(instanceID,
+		repository.NewFilter(repository.FieldAggregateID, "agg2", repository.OperationEquals),
+	))
+	assertSequences(t, events, second)
+	if len(events) == 1 && len(events[0].Data) != 0 {
+		t.Errorf("data of event without data = %s", events[0].Data)
+	}
+}
+
+func testPushUnknownInstance(t *testing.T, repo repository.Repository) {
+	instanceID := "conformance" + strconv.FormatInt(time.Now().UnixNano(), 10) + "unknown"
+	err := repo.Push(context.Background(), []*repository.Event{newEvent(instanceID, "agg1", "conformance.added", "")})
+	if err == nil {
+		t.Fatal("push to unknown instance must fail")
+	}
+	if events := filter(t, repo, instanceQuery(instanceID)); len(events) != 0 {
+		t.Errorf("no events must be stored, got %d", len(events))
+	}
+}
+
+func testFilterOperations(t *testing.T, repo repository.Repository) {
+	instanceID := newInstance(t, repo)
+	first := newEvent(instanceID, "agg1", "conformance.added", `{"name": "gigi", "roles": ["admin", "user"], "profile": {"age": 5}}`)
+	second := newEvent(instanceID, "agg2", "conformance.added", `{"name": "giraffe"}`)
+	third := newEvent(instanceID, "agg1", "conformance.changed", `{"name": "zebra"}`)
+	third.EditorUser = "other"
+	push(t, repo, first, second)
+	time.Sleep(10 * time.Millisecond)
+	push(t, repo, third)
+	stored := filter(t, repo, instanceQuery(instanceID))
+	if len(stored) != 3 {
+		t.Fatalf("expected 3 events got %d", len(stored))
+	}
+
+	tests := []struct {
+		name   string
+		filter *repository.Filter
+		want   []*repository.Event
+	}{
+		{
+			name:   "equals",
+			filter: repository.NewFilter(repository.FieldEventType, repository.EventType("conformance.added"), repository.OperationEquals),
+			want:   []*repository.Event{first, second},
+		},
+		{
+			name:   "equals aggregate type",
+			filter: repository.NewFilter(repository.FieldAggregateType, repository.AggregateType("conformance.aggregate"), repository.OperationEquals),
+			want:   []*repository.Event{first, second, third},
+		},
+		{
+			name:   "equals resource owner",
+			filter: repository.NewFilter(repository.FieldResourceOwner, "owner", repository.OperationEquals),
+			want:   []*repository.Event{first, second, third},
+		},
+		{
+			name:   "equals editor user",
+			filter: repository.NewFilter(repository.FieldEditorUser, "other", repository.OperationEquals),
+			want:   []*repository.Event{third},
+		},
+		{
+			name:   "in",
+			filter: repository.NewFilter(repository.FieldAggregateID, database.StringArray{"agg2", "agg3"}, repository.OperationIn),
+			want:   []*repository.Event{second},
+		},
+		{
+			name:   "not in",
+			filter: repository.NewFilter(repository.FieldAggregateID, database.StringArray{"agg2", "agg3"}, repository.OperationNotIn),
+			want:   []*repository.Event{first, third},
+		},
+		{
+			name:   "sequence greater",
+			filter: repository.NewFilter(repository.FieldSequence, first.Sequence, repository.OperationGreater),
+			want:   []*repository.Event{second, third},
+		},
+		{
+			name:   "sequence less",
+			filter: repository.NewFilter(repository.FieldSequence, third.Sequence, repository.OperationLess),
+			want:   []*repository.Event{first, second},
+		},
+		{
+			name:   "creation date greater",
+			filter: repository.NewFilter(repository.FieldCreationDate, stored[1].CreationDate, repository.OperationGreater),
+			want:   []*repository.Event{third},
+		},
+		{
+			name:   "creation date less",
+			filter: repository.NewFilter(repository.FieldCreationDate, stored[2].CreationDate, repository.OperationLess),
+			want:   []*repository.Event{first, second},
+		},
+		{
+			name:   "json contains",
+			filter: repository.NewFilter(repository.FieldEventData, map[string]interface{}{"name": "gigi"}, repository.OperationJSONContains),
+			want:   []*repository.Event{first},
+		},
+		{
+			name:   "json contains nested",
+			filter: repository.NewFilter(repository.FieldEventData, map[string]interface{}{"roles": []string{"user"}, "profile": map[string]interface{}{"age": 5}}, repository.OperationJSONContains),
+			want:   []*repository.Event{first},
+		},
+		{
+			name:   "json not contained",
+			filter: repository.NewFilter(repository.FieldEventData, map[string]interface{}{"name": "lion"}, repository.OperationJSONContains),
+			want:   []*repository.Event{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSequences(t, filter(t, repo, instanceQuery(instanceID, tt.filter)), tt.want...)
+		})
+	}
+
+	t.Run("or", func(t *testing.T) {
+		query := instanceQuery(instanceID, repository.NewFilter(repository.FieldAggregateID, "agg2", repository.OperationEquals))
+		query.Filters = append(query.Filters, []*repository.Filter{
+			repository.NewFilter(repository.FieldInstanceID, instanceID, repository.OperationEquals),
+			repository.NewFilter(repository.FieldEventType, repository.EventType("conformance.changed"), repository.OperationEquals),
+		})
+		assertSequences(t, filter(t, repo, query), second, third)
+	})
+}
+
+func testFilterOrderAndLimit(t *testing.T, repo repository.Repository) {
+	instanceID := newInstance(t, repo)
+	first := newEvent(instanceID, "agg1", "conformance.added", "")
+	second := newEvent(instanceID, "agg1", "conformance.changed", "")
+	third := newEvent(instanceID, "agg1", "conformance.changed", "")
+	push(t, repo, first, second, third)
+
+	query := instanceQuery(instanceID)
+	query.Desc = true
+	assertSequences(t, filter(t, repo, query), third, second, first)
+
+	query.Limit = 2
+	assertSequences(t, filter(t, repo, query), third, second)
+
+	query.Desc = false
+	assertSequences(t, filter(t, repo, query), first, second)
+}
+
+func testFilterWithoutFilters(t *testing.T, repo repository.Repository) {
+	_, err := repo.Filter(context.Background(), &repository.SearchQuery{Columns: repository.ColumnsEvent})
+	if !errors.IsErrorInvalidArgument(err) {
+		t.Errorf("filter without filters must fail with invalid argument, got %v", err)
+	}
+}
+
+func testLatestSequence(t *testing.T, repo repository.Repository) {
+	instanceID := newInstance(t, repo)
+	first := newEvent(instanceID, "agg1", "conformance.added", "")
+	second := newEvent(instanceID, "agg2", "conformance.added", "")
+	push(t, repo, first, second)
+
+	query := instanceQuery(instanceID, repository.NewFilter(repository.FieldAggregateID, "agg1", repository.OperationEquals))
+	query.Columns = repository.ColumnsMaxSequence
+	sequence, err := repo.LatestSequence(context.Background(), query)
+	if err != nil {
+		t.Fatalf("unable to get latest sequence: %v", err)
+	}
+	if sequence != first.Sequence {
+		t.Errorf("latest sequence = %d, want %d", sequence, first.Sequence)
+	}
+
+	query = instanceQuery(instanceID, repository.NewFilter(repository.FieldAggregateID, "unknown", repository.OperationEquals))
+	query.Columns = repository.ColumnsMaxSequence
+	sequence, err = repo.LatestSequence(context.Background(), query)
+	if err != nil {
+		t.Fatalf("unable to get latest sequence: %v", err)
+	}
+	if sequence != 0 {
+		t.Errorf("latest sequence without events = %d, want 0", sequence)
+	}
+}
+
+func testInstanceIDs(t *testing.T, repo repository.Repository) {
+	instanceIDs := []string{newInstance(t, repo), newInstance(t, repo)}
+	push(t, repo,
+		newEvent(instanceIDs[0], "agg1", "conformance.added", ""),
+		newEvent(instanceIDs[0], "agg2", "conformance.added", ""),
+		newEvent(instanceIDs[1], "agg1", "conformance.added", ""),
+	)
+
+	ids, err := repo.InstanceIDs(context.Background(), &repository.SearchQuery{
+		Columns: repository.ColumnsInstanceIDs,
+		Filters: [][]*repository.Filter{{
+			repository.NewFilter(repository.FieldInstanceID, database.StringArray(instanceIDs), repository.OperationIn),
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unable to get instance ids: %v", err)
+	}
+	sort.Strings(ids)
+	sort.Strings(instanceIDs)
+	if !reflect.DeepEqual(ids, instanceIDs) {
+		t.Errorf("instance ids = %v, want %v", ids, instanceIDs)
+	}
+}
+
+func testUniqueConstraints(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	instanceID := newInstance(t, repo)
+	constraint := func(field string, action repository.UniqueConstraintAction) *repository.UniqueConstraint {
+		return &repository.UniqueConstraint{
+			UniqueType:   "conformance.name",
+			UniqueField:  field,
+			InstanceID:   instanceID,
+			Action:       action,
+			ErrorMessage: "Errors.Conformance.AlreadyExists",
+		}
+	}
+
+	if err := repo.Push(ctx, []*repository.Event{newEvent(instanceID, "agg1", "conformance.added", "")}, constraint("Gigi", repository.UniqueConstraintAdd)); err != nil {
+		t.Fatalf("unable to add unique constraint: %v", err)
+	}
+	err := repo.Push(ctx, []*repository.Event{newEvent(instanceID, "agg2", "conformance.added", "")}, constraint("gigi", repository.UniqueConstraintAdd))
+	if !errors.IsErrorAlreadyExists(err) {
+		t.Fatalf("unique constraints must be case insensitive, got %v", err)
+	}
+	if events := filter(t, repo, instanceQuery(instanceID, repository.NewFilter(repository.FieldAggregateID, "agg2", repository.OperationEquals))); len(events) != 0 {
+		t.Errorf("events must not be stored if a unique constraint fails, got %d", len(events))
+	}
+
+	if err = repo.Push(ctx, []*repository.Event{newEvent(instanceID, "agg1", "conformance.removed", "")}, constraint("gigi", repository.UniqueConstraintRemoved)); err != nil {
+		t.Fatalf("unable to remove unique constraint: %v", err)
+	}
+	if err = repo.Push(ctx, []*repository.Event{newEvent(instanceID, "agg2", "conformance.added", "")}, constraint("gigi", repository.UniqueConstraintAdd)); err != nil {
+		t.Fatalf("unable to add removed unique constraint: %v", err)
+	}
+
+	if err = repo.Push(ctx, []*repository.Event{newEvent(instanceID, "agg2", "conformance.removed", "")}, constraint("", repository.UniqueConstraintInstanceRemoved)); err != nil {
+		t.Fatalf("unable to remove unique constraints of instance: %v", err)
+	}
+	if err = repo.Push(ctx, []*repository.Event{newEvent(instanceID, "agg3", "conformance.added", "")}, constraint("gigi", repository.UniqueConstraintAdd)); err != nil {
+		t.Fatalf("unable to add unique constraint of removed instance: %v", err)
+	}
+}
+
+func testCreateInstance(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	if err := repo.CreateInstance(ctx, ""); err == nil {
+		t.Error("instance without id must not be created")
+	}
+	if err := repo.CreateInstance(ctx, "asdf;DROP DATABASE zitadel;--"); err == nil {
+		t.Error("instance with invalid id must not be created")
+	}
+	instanceID := newInstance(t, repo)
+	if err := repo.CreateInstance(ctx, instanceID); err == nil {
+		t.Error("instance must not be created twice")
+	}
+}
+
+// testCreateInstanceGeneratedIDs creates instances with the ids of every generator type
+// and pushes an event to each of them, the sequences of the instances must be independent
+func testCreateInstanceGeneratedIDs(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	tests := []struct {
+		generatorType id.GeneratorType
+		next          func() (string, error)
+	}{
+		{
+			// the sonyflake generator requires the identification of the machine, its ids are numeric
+			generatorType: id.GeneratorTypeSonyflake,
+			next: func() (string, error) {
+				return strconv.FormatInt(time.Now().UnixNano(), 10), nil
+			},
+		},
+		{
+			generatorType: id.GeneratorTypeULID,
+			next:          id.NewGenerator(id.GeneratorTypeULID).Next,
+		},
+		{
+			generatorType: id.GeneratorTypeUUIDv7,
+			next:          id.NewGenerator(id.GeneratorTypeUUIDv7).Next,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.generatorType), func(t *testing.T) {
+			instanceID, err := tt.next()
+			if err != nil {
+				t.Fatalf("unable to generate id: %v", err)
+			}
+			if err = repo.CreateInstance(ctx, instanceID); err != nil {
+				t.Fatalf("unable to create instance %s: %v", instanceID, err)
+			}
+			event := newEvent(instanceID, "agg1", "conformance.added", "")
+			push(t, repo, event)
+			if event.Sequence != 1 {
+				t.Errorf("first event of the instance must have sequence 1, got %d", event.Sequence)
+			}
+			if events := filter(t, repo, instanceQuery(instanceID)); len(events) != 1 {
+				t.Errorf("expected 1 event got %d", len(events))
+			}
+		})
+	}
+}
+
+func testSnapshots(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	instanceID := newInstance(t, repo)
+
+	snapshot, err := repo.Snapshot(ctx, instanceID, "conformance.model", "agg1", "query")
+	if err != nil || snapshot != nil {
+		t.Fatalf("snapshot must not exist, got %v, %v", snapshot, err)
+	}
+
+	pushSnapshot := func(version string, sequence uint64, data string) {
+		t.Helper()
+		err := repo.PushSnapshot(ctx, &repository.Snapshot{
+			InstanceID:            instanceID,
+			Type:                  "conformance.model",
+			AggregateID:           "agg1",
+			Query:                 "query",
+			Version:               version,
+			Sequence:              sequence,
+			ResourceOwner:         "owner",
+			AggregateCreationDate: time.Now().Add(-time.Hour),
+			ChangeDate:            time.Now(),
+			Data:                  []byte(data),
+		})
+		if err != nil {
+			t.Fatalf("unable to push snapshot: %v", err)
+		}
+	}
+	assertSnapshot := func(version string, sequence uint64, data string) {
+		t.Helper()
+		snapshot, err := repo.Snapshot(ctx, instanceID, "conformance.model", "agg1", "query")
+		if err != nil {
+			t.Fatalf("unable to read snapshot: %v", err)
+		}
+		if snapshot == nil {
+			t.Fatal("snapshot not found")
+		}
+		if snapshot.Version != version || snapshot.Sequence != sequence || snapshot.ResourceOwner != "owner" {
+			t.Errorf("snapshot = %+v, want version %s and sequence %d", snapshot, version, sequence)
+		}
+		if snapshot.CreationDate.IsZero() || snapshot.AggregateCreationDate.IsZero() || snapshot.ChangeDate.IsZero() {
+			t.Errorf("dates of snapshot must be set: %+v", snapshot)
+		}
+		assertJSON(t, snapshot.Data, []byte(data))
+	}
+
+	pushSnapshot("v1", 5, `{"name": "gigi"}`)
+	assertSnapshot("v1", 5, `{"name": "gigi"}`)
+
+	pushSnapshot("v1", 3, `{"name": "outdated"}`)
+	assertSnapshot("v1", 5, `{"name": "gigi"}`)
+
+	pushSnapshot("v1", 7, `{"name": "giraffe"}`)
+	assertSnapshot("v1", 7, `{"name": "giraffe"}`)
+
+	pushSnapshot("v2", 2, `{"firstName": "gigi"}`)
+	assertSnapshot("v2", 2, `{"firstName": "gigi"}`)
+
+	snapshot, err = repo.Snapshot(ctx, instanceID, "conformance.model", "agg1", "other query")
+	if err != nil || snapshot != nil {
+		t.Errorf("snapshot of other query must not exist, got %v, %v", snapshot, err)
+	}
+
+	if err = repo.DeleteSnapshots(ctx, instanceID, "agg1"); err != nil {
+		t.Fatalf("unable to delete snapshots: %v", err)
+	}
+	snapshot, err = repo.Snapshot(ctx, instanceID, "conformance.model", "agg1", "query")
+	if err != nil || snapshot != nil {
+		t.Errorf("deleted snapshot must not exist, got %v, %v", snapshot, err)
+	}
+}
@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+// matchesAny checks if the event matches all filters of at least one of the sub queries
+func matchesAny(event *repository.Event, subQueries [][]*repository.Filter) (bool, error) {
+	for _, filters := range subQueries {
+		matches, err := matchesAll(event, filters)
+		if err != nil || matches {
+			return matches, err
+		}
+	}
+	return false, nil
+}
+
+func matchesAll(event *repository.Event, filters []*repository.Filter) (bool, error) {
+	for _, filter := range filters {
+		matches, err := matches(event, filter)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matches(event *repository.Event, filter *repository.Filter) (bool, error) {
+	field := fieldValue(event, filter.Field)
+	switch filter.Operation {
+	case repository.OperationEquals:
+		return compare(field, filter.Value) == 0, nil
+	case repository.OperationGreater:
+		return compare(field, filter.Value) > 0, nil
+	case repository.OperationLess:
+		return compare(field, filter.Value) < 0, nil
+	case repository.OperationIn:
+		values, err := list(filter.Value)
+		if err != nil {
+			return false, err
+		}
+		for _, value := range values {
+			if compare(field, value) == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	case repository.OperationNotIn:
+		values, err := list(filter.Value)
+		if err != nil {
+			return false, err
+		}
+		for _, value := range values {
+			if compare(field, value) == 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	case repository.OperationJSONContains:
+		return jsonContains(event.Data, filter.Value)
+	}
+	return false, errors.ThrowInvalidArgument(nil, "MEM-Ree6x", "invalid query factory")
+}
+
+func fieldValue(event *repository.Event, field repository.Field) interface{} {
+	switch field {
+	case repository.FieldAggregateType:
+		return string(event.AggregateType)
+	case repository.FieldAggregateID:
+		return event.AggregateID
+	case repository.FieldSequence:
+		return event.Sequence
+	case repository.FieldResourceOwner:
+		return event.ResourceOwner.String
+	case repository.FieldInstanceID:
+		return event.InstanceID
+	case repository.FieldEditorService:
+		return event.EditorService
+	case repository.FieldEditorUser:
+		return event.EditorUser
+	case repository.FieldEventType:
+		return string(event.Type)
+	case repository.FieldEventData:
+		return string(event.Data)
+	case repository.FieldCreationDate:
+		return event.CreationDate
+	}
+	return nil
+}
+
+// compare returns -1, 0 or 1 if the field is less, equal or greater than the value
+// values of different kinds are never equal
+func compare(field, value interface{}) int {
+	switch f := field.(type) {
+	case time.Time:
+		v, ok := value.(time.Time)
+		if !ok {
+			return -1
+		}
+		switch {
+		case f.Before(v):
+			return -1
+		case f.After(v):
+			return 1
+		}
+		return 0
+	case uint64:
+		rv := reflect.ValueOf(value)
+		var v uint64
+		switch rv.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v = rv.Uint()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if rv.Int() < 0 {
+				return 1
+			}
+			v = uint64(rv.Int())
+		default:
+			return -1
+		}
+		switch {
+		case f < v:
+			return -1
+		case f > v:
+			return 1
+		}
+		return 0
+	case string:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.String {
+			return -1
+		}
+		v := rv.String()
+		switch {
+		case f < v:
+			return -1
+		case f > v:
+			return 1
+		}
+		return 0
+	}
+	return -1
+}
+
+// list returns the entries of the slice value of an in or not in filter
+func list(value interface{}) ([]interface{}, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, errors.ThrowInvalidArgument(nil, "MEM-eiT5o", "invalid query factory")
+	}
+	values := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, nil
+}
+
+// jsonContains behaves like the jsonb containment operator (@>) of the database
+func jsonContains(data []byte, value interface{}) (bool, error) {
+	if len(data) == 0 {
+		return false, nil
+	}
+	var stored interface{}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return false, nil
+	}
+	var contained interface{}
+	switch v := value.(type) {
+	case []byte:
+		if err := json.Unmarshal(v, &contained); err != nil {
+			return false, errors.ThrowInvalidArgument(err, "MEM-Ahc8u", "invalid query factory")
+		}
+	default:
+		marshalled, err := json.Marshal(v)
+		if err != nil {
+			return false, errors.ThrowInvalidArgument(err, "MEM-Oe2ai", "invalid query factory")
+		}
+		if err = json.Unmarshal(marshalled, &contained); err != nil {
+			return false, errors.ThrowInvalidArgument(err, "MEM-Voh1a", "invalid query factory")
+		}
+	}
+	return contains(stored, contained), nil
+}
+
+func contains(stored, contained interface{}) bool {
+	switch c := contained.(type) {
+	case map[string]interface{}:
+		s, ok := stored.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range c {
+			storedValue, ok := s[key]
+			if !ok || !contains(storedValue, value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		s, ok := stored.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, value := range c {
+			found := false
+			for _, storedValue := range s {
+				if contains(storedValue, value) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+	// primitives of arrays are contained if they are part of the array
+	if s, ok := stored.([]interface{}); ok {
+		for _, storedValue := range s {
+			if reflect.DeepEqual(storedValue, contained) {
+				return true
+			}
+		}
+		return false
+	}
+	return reflect.DeepEqual(stored, contained)
+}
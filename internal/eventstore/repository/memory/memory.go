@@ -0,0 +1,312 @@
+// Package memory implements an eventstore repository which keeps all events in memory.
+// It behaves like the sql repository and is meant for tests and embedded setups,
+// the events are lost as soon as the process stops.
+package memory
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+var _ repository.Repository = (*Memory)(nil)
+
+// instanceIDRegexp matches the instance ids without hyphens, as they aren't part of the sequence name in the sql repository
+var instanceIDRegexp = regexp.MustCompile(`^[0-9a-zA-Z]+$`)
+
+type Memory struct {
+	AllowOrderByCreationDate bool
+
+	mutex sync.RWMutex
+	// events are ordered by insertion
+	events []*repository.Event
+	// sequences contains the latest sequence per instance,
+	// the empty instance id represents the system sequence
+	sequences         map[string]uint64
+	uniqueConstraints map[uniqueConstraint]struct{}
+	snapshots         map[snapshotKey]*repository.Snapshot
+	lastID            uint64
+}
+
+type uniqueConstraint struct {
+	instanceID, uniqueType, uniqueField string
+}
+
+type snapshotKey struct {
+	instanceID, snapshotType, aggregateID, query string
+}
+
+func NewMemory(allowOrderByCreationDate bool) *Memory {
+	return &Memory{
+		AllowOrderByCreationDate: allowOrderByCreationDate,
+		sequences:                map[string]uint64{"": 0},
+		uniqueConstraints:        make(map[uniqueConstraint]struct{}),
+		snapshots:                make(map[snapshotKey]*repository.Snapshot),
+	}
+}
+
+func (m *Memory) Health(context.Context) error { return nil }
+
+// Push adds all events to the eventstreams of the aggregates.
+// Nothing is stored if one of the events or unique constraints fails
+func (m *Memory) Push(_ context.Context, events []*repository.Event, uniqueConstraints ...*repository.UniqueConstraint) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	sequences := make(map[string]uint64, len(m.sequences))
+	for instanceID, sequence := range m.sequences {
+		sequences[instanceID] = sequence
+	}
+	pushed := make([]*repository.Event, 0, len(events))
+	for _, event := range events {
+		sequence, ok := sequences[event.InstanceID]
+		if !ok {
+			return errors.ThrowInternal(nil, "MEM-Oox4e", "unable to create event")
+		}
+		stored := *event
+		stored.Sequence = sequence + 1
+		sequences[event.InstanceID] = stored.Sequence
+		stored.CreationDate = time.Now()
+		stored.PreviousAggregateSequence = 0
+		stored.PreviousAggregateTypeSequence = 0
+		stored.Data = copyData(event.Data)
+
+		for _, previous := range [][]*repository.Event{m.events, pushed} {
+			for _, existing := range previous {
+				if existing.InstanceID != stored.InstanceID || existing.AggregateType != stored.AggregateType {
+					continue
+				}
+				stored.PreviousAggregateTypeSequence = existing.Sequence
+				if existing.AggregateID == stored.AggregateID {
+					stored.PreviousAggregateSequence = existing.Sequence
+					stored.ResourceOwner = existing.ResourceOwner
+				}
+			}
+		}
+		pushed = append(pushed, &stored)
+	}
+
+	constraints, err := m.handleUniqueConstraints(uniqueConstraints...)
+	if err != nil {
+		return err
+	}
+
+	for i, event := range events {
+		m.lastID++
+		pushed[i].ID = strconv.FormatUint(m.lastID, 10)
+		event.ID = pushed[i].ID
+		event.Sequence = pushed[i].Sequence
+		event.PreviousAggregateSequence = pushed[i].PreviousAggregateSequence
+		event.PreviousAggregateTypeSequence = pushed[i].PreviousAggregateTypeSequence
+		event.CreationDate = pushed[i].CreationDate
+		event.ResourceOwner = pushed[i].ResourceOwner
+	}
+	m.events = append(m.events, pushed...)
+	m.sequences = sequences
+	m.uniqueConstraints = constraints
+	return nil
+}
+
+// handleUniqueConstraints returns the unique constraints after the constraints are added or removed
+func (m *Memory) handleUniqueConstraints(uniqueConstraints ...*repository.UniqueConstraint) (map[uniqueConstraint]struct{}, error) {
+	constraints := make(map[uniqueConstraint]struct{}, len(m.uniqueConstraints))
+	for constraint := range m.uniqueConstraints {
+		constraints[constraint] = struct{}{}
+	}
+	for _, constraint := range uniqueConstraints {
+		if constraint == nil {
+			continue
+		}
+		constraint.UniqueField = strings.ToLower(constraint.UniqueField)
+		key := uniqueConstraint{
+			instanceID:  constraint.InstanceID,
+			uniqueType:  constraint.UniqueType,
+			uniqueField: constraint.UniqueField,
+		}
+		switch constraint.Action {
+		case repository.UniqueConstraintAdd:
+			if _, ok := constraints[key]; ok {
+				return nil, errors.ThrowAlreadyExists(nil, "MEM-Pai3u", constraint.ErrorMessage)
+			}
+			constraints[key] = struct{}{}
+		case repository.UniqueConstraintRemoved:
+			delete(constraints, key)
+		case repository.UniqueConstraintInstanceRemoved:
+			for existing := range constraints {
+				if existing.instanceID == constraint.InstanceID {
+					delete(constraints, existing)
+				}
+			}
+		}
+	}
+	return constraints, nil
+}
+
+// CreateInstance creates a new sequence for the given instance
+func (m *Memory) CreateInstance(_ context.Context, instanceID string) error {
+	if !instanceIDRegexp.MatchString(strings.ReplaceAll(instanceID, "-", "")) {
+		return errors.ThrowInvalidArgument(nil, "MEM-ahC3o", "Errors.InvalidArgument")
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.sequences[instanceID]; ok {
+		return errors.ThrowInternal(nil, "MEM-Zoo7a", "Errors.Internal")
+	}
+	m.sequences[instanceID] = 0
+	return nil
+}
+
+// Filter returns all events matching the given search query
+func (m *Memory) Filter(_ context.Context, searchQuery *repository.SearchQuery) ([]*repository.Event, error) {
+	events, err := m.filter(searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	m.sort(events, searchQuery.Desc)
+	if searchQuery.Limit > 0 && uint64(len(events)) > searchQuery.Limit {
+		events = events[:searchQuery.Limit]
+	}
+	filtered := make([]*repository.Event, len(events))
+	for i, event := range events {
+		copied := *event
+		// the sql repository doesn't return the id of filtered events
+		copied.ID = ""
+		copied.Data = copyData(event.Data)
+		filtered[i] = &copied
+	}
+	return filtered, nil
+}
+
+// LatestSequence returns the latest sequence found by the search query
+func (m *Memory) LatestSequence(_ context.Context, searchQuery *repository.SearchQuery) (uint64, error) {
+	events, err := m.filter(searchQuery)
+	if err != nil {
+		return 0, err
+	}
+	var sequence uint64
+	for _, event := range events {
+		if event.Sequence > sequence {
+			sequence = event.Sequence
+		}
+	}
+	return sequence, nil
+}
+
+// InstanceIDs returns the instance ids found by the search query
+func (m *Memory) InstanceIDs(_ context.Context, searchQuery *repository.SearchQuery) ([]string, error) {
+	events, err := m.filter(searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]struct{})
+	ids := make([]string, 0)
+	for _, event := range events {
+		if _, ok := found[event.InstanceID]; ok {
+			continue
+		}
+		found[event.InstanceID] = struct{}{}
+		ids = append(ids, event.InstanceID)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Snapshot returns the stored snapshot of the model type, aggregate and query or nil if none exists
+func (m *Memory) Snapshot(_ context.Context, instanceID, snapshotType, aggregateID, query string) (*repository.Snapshot, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	snapshot, ok := m.snapshots[snapshotKey{instanceID: instanceID, snapshotType: snapshotType, aggregateID: aggregateID, query: query}]
+	if !ok {
+		return nil, nil
+	}
+	copied := *snapshot
+	copied.Data = copyData(snapshot.Data)
+	return &copied, nil
+}
+
+// PushSnapshot stores the snapshot if no more recent snapshot of the same version exists
+func (m *Memory) PushSnapshot(_ context.Context, snapshot *repository.Snapshot) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := snapshotKey{instanceID: snapshot.InstanceID, snapshotType: snapshot.Type, aggregateID: snapshot.AggregateID, query: snapshot.Query}
+	if existing, ok := m.snapshots[key]; ok && existing.Version == snapshot.Version && existing.Sequence >= snapshot.Sequence {
+		return nil
+	}
+	stored := *snapshot
+	stored.Data = copyData(snapshot.Data)
+	stored.CreationDate = time.Now()
+	m.snapshots[key] = &stored
+	return nil
+}
+
+// DeleteSnapshots removes all snapshots of the aggregate
+func (m *Memory) DeleteSnapshots(_ context.Context, instanceID, aggregateID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for key := range m.snapshots {
+		if key.instanceID == instanceID && key.aggregateID == aggregateID {
+			delete(m.snapshots, key)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) filter(searchQuery *repository.SearchQuery) ([]*repository.Event, error) {
+	if len(searchQuery.Filters) == 0 {
+		return nil, errors.ThrowInvalidArgument(nil, "MEM-Ohj5a", "invalid query factory")
+	}
+	for _, filters := range searchQuery.Filters {
+		for _, filter := range filters {
+			if err := filter.Validate(); err != nil {
+				return nil, errors.ThrowInvalidArgument(err, "MEM-ieH2c", "invalid query factory")
+			}
+		}
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	events := make([]*repository.Event, 0)
+	for _, event := range m.events {
+		matches, err := matchesAny(event, searchQuery.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *Memory) sort(events []*repository.Event, desc bool) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if desc {
+			i, j = j, i
+		}
+		if m.AllowOrderByCreationDate && !events[i].CreationDate.Equal(events[j].CreationDate) {
+			return events[i].CreationDate.Before(events[j].CreationDate)
+		}
+		return events[i].Sequence < events[j].Sequence
+	})
+}
+
+func copyData(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	copied := make([]byte, len(data))
+	copy(copied, data)
+	return copied
+}
@@ -0,0 +1,15 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/eventstore/repository/conformance"
+)
+
+func TestMemory_conformance(t *testing.T) {
+	conformance.Run(t, NewMemory(false))
+}
+
+func TestMemory_conformanceOrderByCreationDate(t *testing.T) {
+	conformance.Run(t, NewMemory(true))
+}
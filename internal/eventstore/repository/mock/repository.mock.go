@@ -49,6 +49,20 @@ func (mr *MockRepositoryMockRecorder) CreateInstance(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstance", reflect.TypeOf((*MockRepository)(nil).CreateInstance), arg0, arg1)
 }
 
+// DeleteSnapshots mocks base method.
+func (m *MockRepository) DeleteSnapshots(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSnapshots", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSnapshots indicates an expected call of DeleteSnapshots.
+func (mr *MockRepositoryMockRecorder) DeleteSnapshots(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSnapshots", reflect.TypeOf((*MockRepository)(nil).DeleteSnapshots), arg0, arg1, arg2)
+}
+
 // Filter mocks base method.
 func (m *MockRepository) Filter(arg0 context.Context, arg1 *repository.SearchQuery) ([]*repository.Event, error) {
 	m.ctrl.T.Helper()
@@ -126,3 +140,32 @@ func (mr *MockRepositoryMockRecorder) Push(arg0, arg1 interface{}, arg2 ...inter
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*MockRepository)(nil).Push), varargs...)
 }
+
+// PushSnapshot mocks base method.
+func (m *MockRepository) PushSnapshot(arg0 context.Context, arg1 *repository.Snapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushSnapshot indicates an expected call of PushSnapshot.
+func (mr *MockRepositoryMockRecorder) PushSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushSnapshot", reflect.TypeOf((*MockRepository)(nil).PushSnapshot), arg0, arg1)
+}
+
+// Snapshot mocks base method.
+func (m *MockRepository) Snapshot(arg0 context.Context, arg1, arg2, arg3, arg4 string) (*repository.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*repository.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockRepositoryMockRecorder) Snapshot(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockRepository)(nil).Snapshot), arg0, arg1, arg2, arg3, arg4)
+}
@@ -20,4 +20,11 @@ type Repository interface {
 	InstanceIDs(ctx context.Context, queryFactory *SearchQuery) ([]string, error)
 	//CreateInstance creates a new sequence for the given instance
 	CreateInstance(ctx context.Context, instanceID string) error
+	//Snapshot returns the stored snapshot of the model type, aggregate and query
+	// if no snapshot exists nil is returned
+	Snapshot(ctx context.Context, instanceID, snapshotType, aggregateID, query string) (*Snapshot, error)
+	//PushSnapshot stores the snapshot and replaces the previous snapshot of the model type, aggregate and query
+	PushSnapshot(ctx context.Context, snapshot *Snapshot) error
+	//DeleteSnapshots removes all snapshots of the aggregate
+	DeleteSnapshots(ctx context.Context, instanceID, aggregateID string) error
 }
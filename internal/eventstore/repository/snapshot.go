@@ -0,0 +1,31 @@
+package repository
+
+import "time"
+
+// Snapshot is the reduced state of a model up to a sequence
+type Snapshot struct {
+	//InstanceID is the instance the snapshot belongs to
+	InstanceID string
+	//Type identifies the model which was reduced
+	Type string
+	//AggregateID is the id of the reduced aggregate
+	AggregateID string
+	//Query is the fingerprint of the search query which filtered the reduced events
+	// snapshots of other queries must not be used
+	Query string
+	//Version of the reducer which created the snapshot
+	// snapshots of other versions must not be used
+	Version string
+	//Sequence is the sequence of the last event reduced into the snapshot
+	Sequence uint64
+	//ResourceOwner of the reduced aggregate
+	ResourceOwner string
+	//AggregateCreationDate is the creation date of the first reduced event
+	AggregateCreationDate time.Time
+	//ChangeDate is the creation date of the last reduced event
+	ChangeDate time.Time
+	//Data is the json encoded state of the model
+	Data []byte
+	//CreationDate is the time the snapshot was stored
+	CreationDate time.Time
+}
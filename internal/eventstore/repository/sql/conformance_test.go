@@ -0,0 +1,12 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore/repository/conformance"
+)
+
+func TestCRDB_conformance(t *testing.T) {
+	conformance.Run(t, NewCRDB(&database.DB{DB: testCRDBClient, Database: new(testDB)}, false))
+}
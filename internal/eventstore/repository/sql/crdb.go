@@ -73,7 +73,8 @@ const (
 		" $7::VARCHAR AS editor_service," +
 		" COALESCE((resource_owner), $8::VARCHAR) AS resource_owner," +
 		" $9::VARCHAR AS instance_id," +
-		" NEXTVAL(CONCAT('eventstore.', (CASE WHEN $9 <> '' THEN CONCAT('i_', $9) ELSE 'system' END), '_seq'))," +
+		// the hyphens of the instance id (e.g. of uuids) aren't part of the name of the sequence (see CreateInstance)
+		" NEXTVAL(CONCAT('eventstore.', (CASE WHEN $9 <> '' THEN CONCAT('i_', REPLACE($9, '-', '')) ELSE 'system' END), '_seq'))," +
 		" aggregate_sequence AS previous_aggregate_sequence," +
 		" aggregate_type_sequence AS previous_aggregate_type_sequence " +
 		"FROM previous_data " +
@@ -158,10 +159,12 @@ func (db *CRDB) Push(ctx context.Context, events []*repository.Event, uniqueCons
 	return err
 }
 
-var instanceRegexp = regexp.MustCompile(`eventstore\.i_[0-9a-zA-Z]{1,}_seq`)
+var instanceRegexp = regexp.MustCompile(`^eventstore\.i_[0-9a-zA-Z]{1,}_seq$`)
 
+// CreateInstance creates the sequence of the instance,
+// the hyphens of the instance id are removed from the name of the sequence
 func (db *CRDB) CreateInstance(ctx context.Context, instanceID string) error {
-	row := db.QueryRowContext(ctx, "SELECT CONCAT('eventstore.i_', $1::TEXT, '_seq')", instanceID)
+	row := db.QueryRowContext(ctx, "SELECT CONCAT('eventstore.i_', REPLACE($1::TEXT, '-', ''), '_seq')", instanceID)
 	if row.Err() != nil {
 		return caos_errs.ThrowInvalidArgument(row.Err(), "SQL-7gtFA", "Errors.InvalidArgument")
 	}
@@ -3,6 +3,7 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"sync"
 	"testing"
 
@@ -640,6 +641,26 @@ func TestCRDB_CreateInstance(t *testing.T) {
 				exists:  true,
 			},
 		},
+		{
+			name: "correct uuid",
+			args: args{
+				instanceID: "0188b5a2-7c3e-7a1b-9f2d-3c4b5a6d7e8f",
+			},
+			res: res{
+				wantErr: false,
+				exists:  true,
+			},
+		},
+		{
+			name: "injection after hyphen",
+			args: args{
+				instanceID: "x_seq;DROP-DATABASE-zitadel;--",
+			},
+			res: res{
+				wantErr: true,
+				exists:  false,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -651,7 +672,7 @@ func TestCRDB_CreateInstance(t *testing.T) {
 				t.Errorf("CRDB.CreateInstance() error = %v, wantErr %v", err, tt.res.wantErr)
 			}
 
-			sequenceRow := testCRDBClient.QueryRow("SELECT EXISTS(SELECT 1 FROM [SHOW SEQUENCES FROM eventstore] WHERE sequence_name like $1)", "i_"+tt.args.instanceID+"%")
+			sequenceRow := testCRDBClient.QueryRow("SELECT EXISTS(SELECT 1 FROM [SHOW SEQUENCES FROM eventstore] WHERE sequence_name like $1)", "i_"+strings.ReplaceAll(tt.args.instanceID, "-", "")+"%")
 			var exists bool
 			err := sequenceRow.Scan(&exists)
 			if err != nil {
@@ -0,0 +1,106 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	caos_errs "github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+const (
+	snapshotSelect = "SELECT" +
+		" version, event_sequence, resource_owner, aggregate_creation_date, change_date, snapshot_data, creation_date" +
+		" FROM eventstore.snapshots" +
+		" WHERE instance_id = $1 AND snapshot_type = $2 AND aggregate_id = $3 AND query_hash = $4"
+
+	// snapshotUpsert only replaces a snapshot of the same version if the new snapshot is more recent
+	snapshotUpsert = "INSERT INTO eventstore.snapshots (" +
+		" instance_id," +
+		" snapshot_type," +
+		" aggregate_id," +
+		" query_hash," +
+		" version," +
+		" event_sequence," +
+		" resource_owner," +
+		" aggregate_creation_date," +
+		" change_date," +
+		" snapshot_data," +
+		" creation_date" +
+		") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, statement_timestamp())" +
+		" ON CONFLICT (instance_id, snapshot_type, aggregate_id, query_hash) DO UPDATE SET" +
+		" version = EXCLUDED.version," +
+		" event_sequence = EXCLUDED.event_sequence," +
+		" resource_owner = EXCLUDED.resource_owner," +
+		" aggregate_creation_date = EXCLUDED.aggregate_creation_date," +
+		" change_date = EXCLUDED.change_date," +
+		" snapshot_data = EXCLUDED.snapshot_data," +
+		" creation_date = EXCLUDED.creation_date" +
+		" WHERE eventstore.snapshots.version <> EXCLUDED.version" +
+		" OR eventstore.snapshots.event_sequence < EXCLUDED.event_sequence"
+
+	snapshotsDelete = "DELETE FROM eventstore.snapshots WHERE instance_id = $1 AND aggregate_id = $2"
+)
+
+// Snapshot returns the stored snapshot of the model type, aggregate and query or nil if none exists
+func (db *CRDB) Snapshot(ctx context.Context, instanceID, snapshotType, aggregateID, query string) (*repository.Snapshot, error) {
+	snapshot := &repository.Snapshot{
+		InstanceID:  instanceID,
+		Type:        snapshotType,
+		AggregateID: aggregateID,
+		Query:       query,
+	}
+	var (
+		sequence              Sequence
+		data                  Data
+		aggregateCreationDate sql.NullTime
+		changeDate            sql.NullTime
+	)
+	err := db.QueryRowContext(ctx, snapshotSelect, instanceID, snapshotType, aggregateID, query).
+		Scan(&snapshot.Version, &sequence, &snapshot.ResourceOwner, &aggregateCreationDate, &changeDate, &data, &snapshot.CreationDate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, caos_errs.ThrowInternal(err, "SQL-Aez5i", "unable to read snapshot")
+	}
+	snapshot.Sequence = uint64(sequence)
+	snapshot.Data = data
+	snapshot.AggregateCreationDate = aggregateCreationDate.Time
+	snapshot.ChangeDate = changeDate.Time
+	return snapshot, nil
+}
+
+// PushSnapshot stores the snapshot if no more recent snapshot of the same version exists
+func (db *CRDB) PushSnapshot(ctx context.Context, snapshot *repository.Snapshot) error {
+	_, err := db.ExecContext(ctx, snapshotUpsert,
+		snapshot.InstanceID,
+		snapshot.Type,
+		snapshot.AggregateID,
+		snapshot.Query,
+		snapshot.Version,
+		snapshot.Sequence,
+		snapshot.ResourceOwner,
+		nullTime(snapshot.AggregateCreationDate),
+		nullTime(snapshot.ChangeDate),
+		Data(snapshot.Data),
+	)
+	if err != nil {
+		return caos_errs.ThrowInternal(err, "SQL-ohG2e", "unable to store snapshot")
+	}
+	return nil
+}
+
+// DeleteSnapshots removes all snapshots of the aggregate
+func (db *CRDB) DeleteSnapshots(ctx context.Context, instanceID, aggregateID string) error {
+	if _, err := db.ExecContext(ctx, snapshotsDelete, instanceID, aggregateID); err != nil {
+		return caos_errs.ThrowInternal(err, "SQL-Ub4ie", "unable to delete snapshots")
+	}
+	return nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -34,6 +34,7 @@ type SearchQuery struct {
 	eventTypes           []EventType
 	eventData            map[string]interface{}
 	creationDateAfter    time.Time
+	creationDateBefore   time.Time
 }
 
 // Columns defines which fields of the event are needed for the query
@@ -197,6 +198,12 @@ func (query *SearchQuery) CreationDateAfter(time time.Time) *SearchQuery {
 	return query
 }
 
+// CreationDateBefore filters for events which happened before the specified time
+func (query *SearchQuery) CreationDateBefore(time time.Time) *SearchQuery {
+	query.creationDateBefore = time
+	return query
+}
+
 // EventTypes filters for events with the given event types
 func (query *SearchQuery) EventTypes(types ...EventType) *SearchQuery {
 	query.eventTypes = types
@@ -257,6 +264,7 @@ func (builder *SearchQueryBuilder) build(instanceID string) (*repository.SearchQ
 			query.instanceIDFilter,
 			query.excludedInstanceIDFilter,
 			query.creationDateAfterFilter,
+			query.creationDateBeforeFilter,
 			query.builder.resourceOwnerFilter,
 			query.builder.instanceIDFilter,
 			query.builder.editorUserFilter,
@@ -383,6 +391,13 @@ func (query *SearchQuery) creationDateAfterFilter() *repository.Filter {
 	return repository.NewFilter(repository.FieldCreationDate, query.creationDateAfter, repository.OperationGreater)
 }
 
+func (query *SearchQuery) creationDateBeforeFilter() *repository.Filter {
+	if query.creationDateBefore.IsZero() {
+		return nil
+	}
+	return repository.NewFilter(repository.FieldCreationDate, query.creationDateBefore, repository.OperationLess)
+}
+
 func (query *SearchQuery) eventDataFilter() *repository.Filter {
 	if len(query.eventData) == 0 {
 		return nil
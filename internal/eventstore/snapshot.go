@@ -0,0 +1,194 @@
+package eventstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+// SnapshotConfig defines if and when snapshots of reducers implementing Snapshotter are used
+type SnapshotConfig struct {
+	Enabled bool
+	// MinEvents is the amount of events which must be reduced after the latest snapshot
+	// before a new snapshot is stored
+	MinEvents uint64
+}
+
+// Snapshotter is implemented by reducers which can be restored from a snapshot
+// instead of reducing all events of the aggregate on every filter.
+//
+// The reducer must embed WriteModel or ReadModel with the aggregate id set before filtering.
+// The state of the reducer is stored as json, only exported fields are part of the snapshot.
+// As the state contains the decrypted personal data of the aggregate, it's encrypted with the personal data key of the aggregate
+// and removed as soon as the aggregate is erased.
+// The search query of the reducer must only filter events of the aggregate of the snapshot.
+// Snapshots are stored per search query, reducers filtering differently don't share snapshots.
+type Snapshotter interface {
+	reducer
+	// SnapshotType identifies the model, it must be unique over all snapshotters
+	SnapshotType() string
+	// SnapshotVersion must be changed as soon as the state or the reduce logic of the model changes
+	// snapshots of other versions are ignored and replaced
+	SnapshotVersion() string
+
+	snapshotAggregateID() string
+	restoreSnapshot(*repository.Snapshot)
+}
+
+// filterToSnapshotter reduces the events after the latest valid snapshot of the reducer
+// and stores a new snapshot if enough events were reduced
+func (es *Eventstore) filterToSnapshotter(ctx context.Context, searchQuery *SearchQueryBuilder, r Snapshotter) error {
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	aggregateID := r.snapshotAggregateID()
+	if !es.snapshots.Enabled || aggregateID == "" || !searchQuery.isSnapshotable() {
+		return es.filterToReducer(ctx, searchQuery, r)
+	}
+
+	query, err := searchQuery.snapshotQuery()
+	if err != nil {
+		return es.filterToReducer(ctx, searchQuery, r)
+	}
+	snapshot, err := es.repo.Snapshot(ctx, instanceID, r.SnapshotType(), aggregateID, query)
+	logging.WithFields("type", r.SnapshotType(), "aggregate", aggregateID).OnError(err).Warn("unable to read snapshot, all events are reduced")
+	if err != nil || (snapshot != nil && snapshot.Version != r.SnapshotVersion()) {
+		snapshot = nil
+	}
+	if snapshot != nil {
+		data, ok, err := es.decryptSnapshot(instanceID, aggregateID, snapshot.Data)
+		if err != nil {
+			return err
+		}
+		// the key of the aggregate was erased, so the state is reduced from the events without the personal data
+		if !ok {
+			return es.filterToReducer(ctx, searchQuery, r)
+		}
+		if err = json.Unmarshal(data, r); err != nil {
+			return errors.ThrowInternal(err, "V2-Ohf3i", "unable to restore snapshot")
+		}
+		r.restoreSnapshot(snapshot)
+		searchQuery.startAfter(snapshot.Sequence)
+	}
+
+	events, err := es.Filter(ctx, searchQuery)
+	if err != nil {
+		return err
+	}
+	r.AppendEvents(events...)
+	if err = r.Reduce(); err != nil {
+		return err
+	}
+
+	if len(events) == 0 || uint64(len(events)) < es.snapshots.MinEvents {
+		return nil
+	}
+	es.pushSnapshot(ctx, instanceID, aggregateID, query, r, snapshot, events)
+	return nil
+}
+
+// pushSnapshot stores the state of the reducer
+// failures are only logged because the snapshot will be created on the next filter
+func (es *Eventstore) pushSnapshot(ctx context.Context, instanceID, aggregateID, query string, r Snapshotter, previous *repository.Snapshot, events []Event) {
+	logger := logging.WithFields("type", r.SnapshotType(), "aggregate", aggregateID)
+	data, err := json.Marshal(r)
+	if err != nil {
+		logger.WithError(err).Warn("unable to marshal snapshot")
+		return
+	}
+	data, ok, err := es.encryptSnapshot(instanceID, aggregateID, data)
+	if err != nil || !ok {
+		logger.OnError(err).Warn("unable to encrypt snapshot")
+		return
+	}
+	snapshot := &repository.Snapshot{
+		InstanceID:            instanceID,
+		Type:                  r.SnapshotType(),
+		AggregateID:           aggregateID,
+		Query:                 query,
+		Version:               r.SnapshotVersion(),
+		Sequence:              events[len(events)-1].Sequence(),
+		ResourceOwner:         events[0].Aggregate().ResourceOwner,
+		AggregateCreationDate: events[0].CreationDate(),
+		ChangeDate:            events[len(events)-1].CreationDate(),
+		Data:                  data,
+	}
+	if previous != nil {
+		snapshot.AggregateCreationDate = previous.AggregateCreationDate
+	}
+	err = es.repo.PushSnapshot(ctx, snapshot)
+	logger.OnError(err).Warn("unable to push snapshot")
+}
+
+// isSnapshotable checks if the search query filters all events of the aggregates
+// so the reduced state is complete
+func (builder *SearchQueryBuilder) isSnapshotable() bool {
+	if builder.columns != repository.ColumnsEvent ||
+		builder.limit > 0 ||
+		builder.desc ||
+		builder.tx != nil ||
+		builder.editorUser != "" {
+		return false
+	}
+	for _, query := range builder.queries {
+		if query.eventSequenceGreater > 0 ||
+			query.eventSequenceLess > 0 ||
+			!query.creationDateAfter.IsZero() ||
+			!query.creationDateBefore.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// snapshotQuery returns the fingerprint of the search query
+// it must be called before the query is restricted by startAfter
+func (builder *SearchQueryBuilder) snapshotQuery() (string, error) {
+	type query struct {
+		AggregateTypes      []AggregateType        `json:"aggregateTypes,omitempty"`
+		AggregateIDs        []string               `json:"aggregateIDs,omitempty"`
+		InstanceID          string                 `json:"instanceID,omitempty"`
+		ExcludedInstanceIDs []string               `json:"excludedInstanceIDs,omitempty"`
+		EventTypes          []EventType            `json:"eventTypes,omitempty"`
+		EventData           map[string]interface{} `json:"eventData,omitempty"`
+	}
+	fingerprint := struct {
+		ResourceOwner string  `json:"resourceOwner,omitempty"`
+		InstanceID    string  `json:"instanceID,omitempty"`
+		Queries       []query `json:"queries,omitempty"`
+	}{
+		ResourceOwner: builder.resourceOwner,
+		InstanceID:    builder.instanceID,
+		Queries:       make([]query, len(builder.queries)),
+	}
+	for i, q := range builder.queries {
+		fingerprint.Queries[i] = query{
+			AggregateTypes:      q.aggregateTypes,
+			AggregateIDs:        q.aggregateIDs,
+			InstanceID:          q.instanceID,
+			ExcludedInstanceIDs: q.excludedInstanceIDs,
+			EventTypes:          q.eventTypes,
+			EventData:           q.eventData,
+		}
+	}
+	data, err := json.Marshal(fingerprint)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// startAfter restricts all queries to events with a sequence greater than the given sequence
+func (builder *SearchQueryBuilder) startAfter(sequence uint64) {
+	for _, query := range builder.queries {
+		if query.eventSequenceGreater < sequence {
+			query.eventSequenceGreater = sequence
+		}
+	}
+}
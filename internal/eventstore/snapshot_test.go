@@ -0,0 +1,370 @@
+package eventstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/pii"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/eventstore/repository/memory"
+)
+
+type testSnapshotter struct {
+	WriteModel
+
+	Sequences []uint64
+	version   string
+	query     *SearchQueryBuilder
+}
+
+func (s *testSnapshotter) Reduce() error {
+	for _, event := range s.Events {
+		s.Sequences = append(s.Sequences, event.Sequence())
+	}
+	return s.WriteModel.Reduce()
+}
+
+func (s *testSnapshotter) Query() *SearchQueryBuilder {
+	return s.query
+}
+
+func (s *testSnapshotter) SnapshotType() string {
+	return "test.snapshotter"
+}
+
+func (s *testSnapshotter) SnapshotVersion() string {
+	return s.version
+}
+
+func TestEventstore_FilterToQueryReducer_snapshot(t *testing.T) {
+	type fields struct {
+		snapshots SnapshotConfig
+		snapshot  *repository.Snapshot
+	}
+	type args struct {
+		query   *SearchQueryBuilder
+		version string
+	}
+	type res struct {
+		sequences         []uint64
+		processedSequence uint64
+		pushedSnapshot    *repository.Snapshot
+	}
+	events := []*repository.Event{
+		{AggregateType: "test.aggregate", AggregateID: "id", Type: "test.event", Sequence: 1},
+		{AggregateType: "test.aggregate", AggregateID: "id", Type: "test.event", Sequence: 2},
+		{AggregateType: "test.aggregate", AggregateID: "id", Type: "test.event", Sequence: 3},
+	}
+	query := func() *SearchQueryBuilder {
+		return NewSearchQueryBuilder(ColumnsEvent).AddQuery().AggregateTypes("test.aggregate").AggregateIDs("id").Builder()
+	}
+	fingerprint := func(query *SearchQueryBuilder) string {
+		fingerprint, err := query.snapshotQuery()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fingerprint
+	}
+	snapshot := func(version string, sequence uint64, sequences ...uint64) *repository.Snapshot {
+		data, err := json.Marshal(&testSnapshotter{Sequences: sequences})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &repository.Snapshot{
+			Type:        "test.snapshotter",
+			AggregateID: "id",
+			Query:       fingerprint(query()),
+			Version:     version,
+			Sequence:    sequence,
+			Data:        data,
+		}
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "disabled",
+			fields: fields{
+				snapshot: snapshot("v1", 2, 1, 2),
+			},
+			args: args{
+				query:   query(),
+				version: "v1",
+			},
+			res: res{
+				sequences:         []uint64{1, 2, 3},
+				processedSequence: 3,
+			},
+		},
+		{
+			name: "no snapshot, push new",
+			fields: fields{
+				snapshots: SnapshotConfig{Enabled: true, MinEvents: 2},
+			},
+			args: args{
+				query:   query(),
+				version: "v1",
+			},
+			res: res{
+				sequences:         []uint64{1, 2, 3},
+				processedSequence: 3,
+				pushedSnapshot:    snapshot("v1", 3, 1, 2, 3),
+			},
+		},
+		{
+			name: "restore snapshot",
+			fields: fields{
+				snapshots: SnapshotConfig{Enabled: true, MinEvents: 2},
+				snapshot:  snapshot("v1", 2, 1, 2),
+			},
+			args: args{
+				query:   query(),
+				version: "v1",
+			},
+			res: res{
+				sequences:         []uint64{1, 2, 3},
+				processedSequence: 3,
+			},
+		},
+		{
+			name: "outdated version",
+			fields: fields{
+				snapshots: SnapshotConfig{Enabled: true, MinEvents: 2},
+				snapshot:  snapshot("v1", 2, 1, 2),
+			},
+			args: args{
+				query:   query(),
+				version: "v2",
+			},
+			res: res{
+				sequences:         []uint64{1, 2, 3},
+				processedSequence: 3,
+				pushedSnapshot:    snapshot("v2", 3, 1, 2, 3),
+			},
+		},
+		{
+			name: "snapshot of other query",
+			fields: fields{
+				snapshots: SnapshotConfig{Enabled: true, MinEvents: 2},
+				snapshot:  snapshot("v1", 2, 1, 2),
+			},
+			args: args{
+				query:   query().ResourceOwner("ro"),
+				version: "v1",
+			},
+			res: res{
+				sequences:         []uint64{1, 2, 3},
+				processedSequence: 3,
+				pushedSnapshot: func() *repository.Snapshot {
+					pushed := snapshot("v1", 3, 1, 2, 3)
+					pushed.Query = fingerprint(query().ResourceOwner("ro"))
+					return pushed
+				}(),
+			},
+		},
+		{
+			name: "query not snapshotable",
+			fields: fields{
+				snapshots: SnapshotConfig{Enabled: true, MinEvents: 1},
+				snapshot:  snapshot("v1", 2, 1, 2),
+			},
+			args: args{
+				query:   query().Limit(2),
+				version: "v1",
+			},
+			res: res{
+				sequences:         []uint64{1, 2},
+				processedSequence: 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &streamRepo{testRepo: testRepo{events: events, snapshot: tt.fields.snapshot, t: t}}
+			es := &Eventstore{
+				repo:              repo,
+				interceptorMutex:  sync.Mutex{},
+				eventInterceptors: map[EventType]eventTypeInterceptors{},
+				snapshots:         tt.fields.snapshots,
+			}
+			model := &testSnapshotter{
+				WriteModel: WriteModel{AggregateID: "id"},
+				version:    tt.args.version,
+				query:      tt.args.query,
+			}
+			if err := es.FilterToQueryReducer(context.Background(), model); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(model.Sequences, tt.res.sequences) {
+				t.Errorf("expected reduced sequences %v, got %v", tt.res.sequences, model.Sequences)
+			}
+			if model.ProcessedSequence != tt.res.processedSequence {
+				t.Errorf("expected processed sequence %d, got %d", tt.res.processedSequence, model.ProcessedSequence)
+			}
+			if !reflect.DeepEqual(repo.pushedSnapshot, tt.res.pushedSnapshot) {
+				t.Errorf("expected pushed snapshot %+v, got %+v", tt.res.pushedSnapshot, repo.pushedSnapshot)
+			}
+		})
+	}
+}
+
+type piiKeyStorage struct {
+	keys map[string]string
+}
+
+func (s *piiKeyStorage) ReadPIIKey(instanceID, aggregateID string) (string, error) {
+	key, ok := s.keys[instanceID+aggregateID]
+	if !ok {
+		return "", errors.ThrowNotFound(nil, "V2-ceeL4", "key not found")
+	}
+	return key, nil
+}
+
+func (s *piiKeyStorage) EnsurePIIKey(instanceID, _, aggregateID, key string) (string, error) {
+	if existing, ok := s.keys[instanceID+aggregateID]; ok {
+		return existing, nil
+	}
+	s.keys[instanceID+aggregateID] = key
+	return key, nil
+}
+
+func (s *piiKeyStorage) DeletePIIKey(instanceID, aggregateID string) error {
+	delete(s.keys, instanceID+aggregateID)
+	return nil
+}
+
+func (s *piiKeyStorage) DeleteErasedPIIKeys([]string) (int64, error) {
+	return 0, nil
+}
+
+type piiEvent struct {
+	BaseEvent
+	data interface{}
+}
+
+func (e *piiEvent) Data() interface{} {
+	return e.data
+}
+
+func (e *piiEvent) UniqueConstraints() []*EventUniqueConstraint {
+	return nil
+}
+
+type piiSnapshotter struct {
+	WriteModel
+
+	Names []string
+}
+
+func (s *piiSnapshotter) Reduce() error {
+	for _, event := range s.Events {
+		if len(event.DataAsBytes()) == 0 {
+			continue
+		}
+		data := struct {
+			Name string `json:"name"`
+		}{}
+		if err := json.Unmarshal(event.DataAsBytes(), &data); err != nil {
+			return err
+		}
+		if data.Name != "" {
+			s.Names = append(s.Names, data.Name)
+		}
+	}
+	return s.WriteModel.Reduce()
+}
+
+func (s *piiSnapshotter) Query() *SearchQueryBuilder {
+	return NewSearchQueryBuilder(ColumnsEvent).AddQuery().AggregateTypes("test.aggregate").AggregateIDs(s.AggregateID).Builder()
+}
+
+func (s *piiSnapshotter) SnapshotType() string {
+	return "test.pii"
+}
+
+func (s *piiSnapshotter) SnapshotVersion() string {
+	return "v1"
+}
+
+func TestEventstore_snapshot_personalDataErased(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	repo := memory.NewMemory(false)
+	if err := repo.CreateInstance(ctx, "instance"); err != nil {
+		t.Fatal(err)
+	}
+	es := NewEventstore(&Config{
+		repo:          repo,
+		Snapshots:     SnapshotConfig{Enabled: true, MinEvents: 1},
+		PII:           pii.Config{Enabled: true},
+		PIIKeyStorage: &piiKeyStorage{keys: make(map[string]string)},
+	})
+	es.RegisterPIIFields("test.added", "name").RegisterPIIErasure("test.removed")
+	for _, eventType := range []EventType{"test.added", "test.removed"} {
+		es.RegisterFilterEventMapper("test.aggregate", eventType, func(event *repository.Event) (Event, error) {
+			return BaseEventFromRepo(event), nil
+		})
+	}
+	push := func(eventType EventType, data interface{}) {
+		t.Helper()
+		_, err := es.Push(ctx, &piiEvent{
+			BaseEvent: *NewBaseEventForPush(ctx, NewAggregate(ctx, "id", "test.aggregate", "v1"), eventType),
+			data:      data,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	storedSnapshot := func() *repository.Snapshot {
+		t.Helper()
+		model := &piiSnapshotter{WriteModel: WriteModel{AggregateID: "id"}}
+		query, err := model.Query().snapshotQuery()
+		if err != nil {
+			t.Fatal(err)
+		}
+		snapshot, err := repo.Snapshot(ctx, "instance", model.SnapshotType(), "id", query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return snapshot
+	}
+
+	push("test.added", []byte(`{"name":"Gigi"}`))
+	model := &piiSnapshotter{WriteModel: WriteModel{AggregateID: "id"}}
+	if err := es.FilterToQueryReducer(ctx, model); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(model.Names, []string{"Gigi"}) {
+		t.Fatalf("expected decrypted names, got %v", model.Names)
+	}
+	snapshot := storedSnapshot()
+	if snapshot == nil {
+		t.Fatal("snapshot not stored")
+	}
+	if bytes.Contains(snapshot.Data, []byte("Gigi")) {
+		t.Errorf("snapshot contains plaintext personal data: %s", snapshot.Data)
+	}
+
+	push("test.removed", nil)
+	if snapshot = storedSnapshot(); snapshot != nil {
+		t.Errorf("snapshot of erased aggregate not removed: %s", snapshot.Data)
+	}
+	model = &piiSnapshotter{WriteModel: WriteModel{AggregateID: "id"}}
+	if err := es.FilterToQueryReducer(ctx, model); err != nil {
+		t.Fatal(err)
+	}
+	if len(model.Names) > 0 {
+		t.Errorf("personal data readable after erasure: %v", model.Names)
+	}
+	if snapshot = storedSnapshot(); snapshot != nil && bytes.Contains(snapshot.Data, []byte("Gigi")) {
+		t.Errorf("snapshot contains plaintext personal data after erasure: %s", snapshot.Data)
+	}
+}
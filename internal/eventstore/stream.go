@@ -0,0 +1,104 @@
+package eventstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+const (
+	defaultStreamBulkLimit = 200
+	streamQueueSize        = 100
+)
+
+// StreamReducer is called for every event of a stream
+// if an error is returned the stream stops
+type StreamReducer func(Event) error
+
+// Stream calls reduce for every event matching the search query with a sequence greater than `from`, ordered by sequence.
+// Missed events are caught up by filtering the eventstore, afterwards the stream waits for events
+// pushed through this instance (notify) or polls every `pollInterval` to receive events pushed by other instances.
+// The stream ends if the context is done or reduce returns an error.
+//
+// Every sub query of the search query must at least define an aggregate type.
+// To resume a stream, pass the sequence of the last reduced event as `from`.
+func (es *Eventstore) Stream(ctx context.Context, queryFactory *SearchQueryBuilder, from uint64, pollInterval time.Duration, reduce StreamReducer) error {
+	types, err := queryFactory.subscriptionTypes()
+	if err != nil {
+		return err
+	}
+	queryFactory.OrderAsc()
+	if queryFactory.limit == 0 {
+		queryFactory.Limit(defaultStreamBulkLimit)
+	}
+
+	queue := make(chan Event, streamQueueSize)
+	sub := SubscribeEventTypes(queue, types)
+	defer sub.Unsubscribe()
+	wakeUp := make(chan struct{}, 1)
+	go drainToWakeUp(queue, wakeUp)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, query := range queryFactory.queries {
+			query.SequenceGreater(from)
+		}
+		events, err := es.Filter(ctx, queryFactory)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err = reduce(event); err != nil {
+				return err
+			}
+			from = event.Sequence()
+		}
+		if uint64(len(events)) == queryFactory.limit {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wakeUp:
+		case <-ticker.C:
+		}
+	}
+}
+
+// drainToWakeUp reads the subscribed events as fast as possible so notify never blocks on a slow stream
+// the events themselves are not used, the stream filters the eventstore to guarantee the order
+func drainToWakeUp(queue <-chan Event, wakeUp chan<- struct{}) {
+	for range queue {
+		select {
+		case wakeUp <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (builder *SearchQueryBuilder) subscriptionTypes() (map[AggregateType][]EventType, error) {
+	if builder == nil || len(builder.queries) == 0 {
+		return nil, errors.ThrowPreconditionFailed(nil, "V2-Oowu8", "builder invalid")
+	}
+	types := make(map[AggregateType][]EventType)
+	// aggregates which are subscribed for all event types
+	allEvents := make(map[AggregateType]bool)
+	for _, query := range builder.queries {
+		if len(query.aggregateTypes) == 0 {
+			return nil, errors.ThrowPreconditionFailed(nil, "V2-ieD3a", "aggregate types must be defined for a stream")
+		}
+		for _, aggregateType := range query.aggregateTypes {
+			if len(query.eventTypes) == 0 {
+				allEvents[aggregateType] = true
+			}
+			types[aggregateType] = append(types[aggregateType], query.eventTypes...)
+		}
+	}
+	for aggregateType := range allEvents {
+		types[aggregateType] = nil
+	}
+	return types, nil
+}
@@ -0,0 +1,198 @@
+package eventstore
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+// streamRepo returns the events with a sequence greater than the sequence filter
+type streamRepo struct {
+	testRepo
+}
+
+func (repo *streamRepo) Filter(ctx context.Context, searchQuery *repository.SearchQuery) ([]*repository.Event, error) {
+	var greater uint64
+	for _, filter := range searchQuery.Filters[0] {
+		if filter.Field == repository.FieldSequence && filter.Operation == repository.OperationGreater {
+			greater = filter.Value.(uint64)
+		}
+	}
+	events := make([]*repository.Event, 0, len(repo.events))
+	for _, event := range repo.events {
+		if event.Sequence > greater {
+			events = append(events, event)
+		}
+		if searchQuery.Limit > 0 && uint64(len(events)) == searchQuery.Limit {
+			break
+		}
+	}
+	return events, nil
+}
+
+func TestEventstore_Stream(t *testing.T) {
+	type args struct {
+		query *SearchQueryBuilder
+		from  uint64
+	}
+	type res struct {
+		sequences []uint64
+		wantErr   func(error) bool
+	}
+	events := []*repository.Event{
+		{AggregateType: "test.aggregate", Type: "test.event", Sequence: 1},
+		{AggregateType: "test.aggregate", Type: "test.event", Sequence: 2},
+		{AggregateType: "test.aggregate", Type: "test.event", Sequence: 3},
+	}
+	tests := []struct {
+		name string
+		args args
+		res  res
+	}{
+		{
+			name: "no aggregate types",
+			args: args{
+				query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().EventTypes("test.event").Builder(),
+			},
+			res: res{
+				wantErr: errors.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "catch up all",
+			args: args{
+				query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().AggregateTypes("test.aggregate").Builder(),
+			},
+			res: res{
+				sequences: []uint64{1, 2, 3},
+			},
+		},
+		{
+			name: "catch up in bulks",
+			args: args{
+				query: NewSearchQueryBuilder(ColumnsEvent).Limit(2).AddQuery().AggregateTypes("test.aggregate").Builder(),
+			},
+			res: res{
+				sequences: []uint64{1, 2, 3},
+			},
+		},
+		{
+			name: "resume",
+			args: args{
+				query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().AggregateTypes("test.aggregate").Builder(),
+				from:  2,
+			},
+			res: res{
+				sequences: []uint64{3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &Eventstore{
+				repo:              &streamRepo{testRepo: testRepo{events: events, t: t}},
+				interceptorMutex:  sync.Mutex{},
+				eventInterceptors: map[EventType]eventTypeInterceptors{},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			var sequences []uint64
+			err := es.Stream(ctx, tt.args.query, tt.args.from, time.Millisecond, func(event Event) error {
+				sequences = append(sequences, event.Sequence())
+				return nil
+			})
+			if tt.res.wantErr != nil {
+				if !tt.res.wantErr(err) {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != context.DeadlineExceeded {
+				t.Errorf("expected stream to end with deadline exceeded, got: %v", err)
+			}
+			if !reflect.DeepEqual(sequences, tt.res.sequences) {
+				t.Errorf("expected sequences %v, got %v", tt.res.sequences, sequences)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_subscriptionTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SearchQueryBuilder
+		want    map[AggregateType][]EventType
+		wantErr bool
+	}{
+		{
+			name:    "no queries",
+			builder: NewSearchQueryBuilder(ColumnsEvent),
+			wantErr: true,
+		},
+		{
+			name: "event types",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().AggregateTypes("user").EventTypes("user.added").
+				Or().AggregateTypes("user").EventTypes("user.removed").
+				Builder(),
+			want: map[AggregateType][]EventType{
+				"user": {"user.added", "user.removed"},
+			},
+		},
+		{
+			name: "all events of aggregate",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().AggregateTypes("user", "org").EventTypes("user.added").
+				Or().AggregateTypes("org").
+				Builder(),
+			want: map[AggregateType][]EventType{
+				"user": {"user.added"},
+				"org":  nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.subscriptionTypes()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("subscriptionTypes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subscriptionTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscription_Unsubscribe(t *testing.T) {
+	tests := []struct {
+		name  string
+		types map[AggregateType][]EventType
+	}{
+		{
+			name:  "subscribed",
+			types: map[AggregateType][]EventType{"stream.unsubscribe": nil},
+		},
+		{
+			name:  "not subscribed to any aggregate",
+			types: map[AggregateType][]EventType{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := SubscribeEventTypes(make(chan Event), tt.types)
+			sub.Unsubscribe()
+			// unsubscribing twice must not panic
+			sub.Unsubscribe()
+			if _, ok := <-sub.Events; ok {
+				t.Error("expected queue to be closed")
+			}
+		})
+	}
+}
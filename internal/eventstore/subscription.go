@@ -13,8 +13,9 @@ var (
 )
 
 type Subscription struct {
-	Events chan Event
-	types  map[AggregateType][]EventType
+	Events    chan Event
+	types     map[AggregateType][]EventType
+	closeOnce sync.Once
 }
 
 //SubscribeAggregates subscribes for all events on the given aggregates
@@ -41,7 +42,10 @@ func SubscribeAggregates(eventQueue chan Event, aggregates ...AggregateType) *Su
 //SubscribeEventTypes subscribes for the given event types
 // if no event types are provided the subscription is for all events of the aggregate
 func SubscribeEventTypes(eventQueue chan Event, types map[AggregateType][]EventType) *Subscription {
-	aggregates := make([]AggregateType, len(types))
+	aggregates := make([]AggregateType, 0, len(types))
+	for aggregate := range types {
+		aggregates = append(aggregates, aggregate)
+	}
 	sub := &Subscription{
 		Events: eventQueue,
 		types:  types,
@@ -99,11 +103,14 @@ func (s *Subscription) Unsubscribe() {
 				subs = subs[:len(subs)-1]
 			}
 		}
+		subscriptions[aggregate] = subs
 	}
-	_, ok := <-s.Events
-	if ok {
+	// notify sends while holding the mutex,
+	// so no sender is left as soon as the subscription is removed
+	// the queue is closed in any case, so consumers ranging over it return
+	s.closeOnce.Do(func() {
 		close(s.Events)
-	}
+	})
 }
 
 func MapEventsToV1Events(events []Event) []*models.Event {
@@ -0,0 +1,78 @@
+package eventstore
+
+import (
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+// UpcastFunc migrates the payload of an event to the definition of the next schema version
+type UpcastFunc func(data []byte) ([]byte, error)
+
+// Upcaster migrates the payload of the event type from the schema version From to the schema version To.
+// The schema version of a stored event is the aggregate version the event was pushed with.
+type Upcaster struct {
+	EventType EventType
+	From      Version
+	To        Version
+	Upcast    UpcastFunc
+}
+
+// upcasterKey identifies the schema of a payload
+type upcasterKey struct {
+	eventType EventType
+	version   Version
+}
+
+// RegisterUpcaster registers the upcaster for its event type and schema version.
+// Before an event is mapped all upcasters starting at its schema version are applied in a chain,
+// so the event mappers only have to handle the payload of the current schema.
+// Only one upcaster per event type and schema version can be registered and the chain must not contain cycles,
+// registering the same upcaster again is ignored because the event mappers of a package are registered by multiple modules.
+func (es *Eventstore) RegisterUpcaster(upcaster *Upcaster) error {
+	if upcaster == nil || upcaster.Upcast == nil || upcaster.EventType == "" || upcaster.From == "" || upcaster.To == "" {
+		return errors.ThrowInvalidArgument(nil, "V2-Ohz1a", "upcaster invalid")
+	}
+	if upcaster.From == upcaster.To {
+		return errors.ThrowInvalidArgument(nil, "V2-aeR8i", "upcaster must change the schema version")
+	}
+	es.interceptorMutex.Lock()
+	defer es.interceptorMutex.Unlock()
+
+	if es.upcasters == nil {
+		es.upcasters = make(map[upcasterKey]*Upcaster)
+	}
+	key := upcasterKey{eventType: upcaster.EventType, version: upcaster.From}
+	if registered, ok := es.upcasters[key]; ok {
+		if registered == upcaster {
+			return nil
+		}
+		return errors.ThrowAlreadyExistsf(nil, "V2-Iequ5", "upcaster of %s %s already registered", upcaster.EventType, upcaster.From)
+	}
+	// the registered chain has no cycles, so the walk from the target version ends
+	for next := es.upcasters[upcasterKey{eventType: upcaster.EventType, version: upcaster.To}]; next != nil; next = es.upcasters[upcasterKey{eventType: upcaster.EventType, version: next.To}] {
+		if next.To == upcaster.From {
+			return errors.ThrowInvalidArgumentf(nil, "V2-ooD5e", "upcaster of %s %s results in a cycle", upcaster.EventType, upcaster.From)
+		}
+	}
+	es.upcasters[key] = upcaster
+	return nil
+}
+
+// upcast applies the upcasters starting at the schema version of the event
+// the returned event is a copy if any upcaster was applied,
+// the aggregate version of the event stays unchanged
+func (es *Eventstore) upcast(event *repository.Event) (*repository.Event, error) {
+	upcaster := es.upcasters[upcasterKey{eventType: EventType(event.Type), version: Version(event.Version)}]
+	if upcaster == nil {
+		return event, nil
+	}
+	upcasted := *event
+	for ; upcaster != nil; upcaster = es.upcasters[upcasterKey{eventType: upcaster.EventType, version: upcaster.To}] {
+		data, err := upcaster.Upcast(upcasted.Data)
+		if err != nil {
+			return nil, errors.ThrowInternal(err, "V2-Iep3u", "unable to upcast event")
+		}
+		upcasted.Data = data
+	}
+	return &upcasted, nil
+}
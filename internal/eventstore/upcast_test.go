@@ -0,0 +1,214 @@
+package eventstore
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+func appendUpcast(suffix string) UpcastFunc {
+	return func(data []byte) ([]byte, error) {
+		return append(data, suffix...), nil
+	}
+}
+
+func TestEventstore_mapEvents_upcast(t *testing.T) {
+	type upcast struct {
+		from, to Version
+		upcast   UpcastFunc
+	}
+	type res struct {
+		data    string
+		version repository.Version
+		wantErr bool
+	}
+	tests := []struct {
+		name      string
+		upcasters []upcast
+		event     *repository.Event
+		res       res
+	}{
+		{
+			name: "no upcaster",
+			event: &repository.Event{
+				Type:    "test.event",
+				Version: "v1",
+				Data:    []byte("data"),
+			},
+			res: res{
+				data:    "data",
+				version: "v1",
+			},
+		},
+		{
+			name: "current version",
+			upcasters: []upcast{
+				{from: "v1", to: "v2", upcast: appendUpcast("-v2")},
+			},
+			event: &repository.Event{
+				Type:    "test.event",
+				Version: "v2",
+				Data:    []byte("data"),
+			},
+			res: res{
+				data:    "data",
+				version: "v2",
+			},
+		},
+		{
+			name: "chain",
+			upcasters: []upcast{
+				{from: "v2", to: "v3", upcast: appendUpcast("-v3")},
+				{from: "v1", to: "v2", upcast: appendUpcast("-v2")},
+			},
+			event: &repository.Event{
+				Type:    "test.event",
+				Version: "v1",
+				Data:    []byte("data"),
+			},
+			res: res{
+				data:    "data-v2-v3",
+				version: "v1",
+			},
+		},
+		{
+			name: "start in chain",
+			upcasters: []upcast{
+				{from: "v1", to: "v2", upcast: appendUpcast("-v2")},
+				{from: "v2", to: "v3", upcast: appendUpcast("-v3")},
+			},
+			event: &repository.Event{
+				Type:    "test.event",
+				Version: "v2",
+				Data:    []byte("data"),
+			},
+			res: res{
+				data:    "data-v3",
+				version: "v2",
+			},
+		},
+		{
+			name: "upcast failed",
+			upcasters: []upcast{
+				{from: "v1", to: "v2", upcast: func([]byte) ([]byte, error) {
+					return nil, errors.ThrowInternal(nil, "V2-Ahs4o", "test err")
+				}},
+			},
+			event: &repository.Event{
+				Type:    "test.event",
+				Version: "v1",
+				Data:    []byte("data"),
+			},
+			res: res{
+				wantErr: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &Eventstore{
+				interceptorMutex:  sync.Mutex{},
+				eventInterceptors: map[EventType]eventTypeInterceptors{},
+			}
+			es.RegisterFilterEventMapper("test.aggregate", "test.event", testFilterMapper)
+			for _, upcaster := range tt.upcasters {
+				if err := es.RegisterUpcaster(&Upcaster{EventType: "test.event", From: upcaster.from, To: upcaster.to, Upcast: upcaster.upcast}); err != nil {
+					t.Fatalf("unable to register upcaster: %v", err)
+				}
+			}
+			original := *tt.event
+
+			events, err := es.mapEvents([]*repository.Event{tt.event})
+			if (err != nil) != tt.res.wantErr {
+				t.Fatalf("Eventstore.mapEvents() error = %v, wantErr %v", err, tt.res.wantErr)
+			}
+			if !reflect.DeepEqual(*tt.event, original) {
+				t.Errorf("Eventstore.mapEvents() must not change the filtered event")
+			}
+			if tt.res.wantErr {
+				return
+			}
+			event := events[0].(*testEvent)
+			if string(event.DataAsBytes()) != tt.res.data {
+				t.Errorf("data = %s, want %s", event.DataAsBytes(), tt.res.data)
+			}
+			if repository.Version(event.Aggregate().Version) != tt.res.version {
+				t.Errorf("aggregate version = %s, want %s", event.Aggregate().Version, tt.res.version)
+			}
+		})
+	}
+}
+
+func TestEventstore_RegisterUpcaster(t *testing.T) {
+	v2 := &Upcaster{EventType: "test.event", From: "v1", To: "v2", Upcast: appendUpcast("-v2")}
+	tests := []struct {
+		name       string
+		registered []*Upcaster
+		upcaster   *Upcaster
+		isErr      func(error) bool
+	}{
+		{
+			name:     "invalid",
+			upcaster: &Upcaster{EventType: "test.event", From: "v1", To: "v2"},
+			isErr:    errors.IsErrorInvalidArgument,
+		},
+		{
+			name:     "same version",
+			upcaster: &Upcaster{EventType: "test.event", From: "v1", To: "v1", Upcast: appendUpcast("-v1")},
+			isErr:    errors.IsErrorInvalidArgument,
+		},
+		{
+			name:       "duplicate",
+			registered: []*Upcaster{v2},
+			upcaster:   &Upcaster{EventType: "test.event", From: "v1", To: "v2", Upcast: appendUpcast("-other")},
+			isErr:      errors.IsErrorAlreadyExists,
+		},
+		{
+			name:       "same upcaster registered again",
+			registered: []*Upcaster{v2},
+			upcaster:   v2,
+		},
+		{
+			name:       "same version of other event type",
+			registered: []*Upcaster{v2},
+			upcaster:   &Upcaster{EventType: "test.other", From: "v1", To: "v2", Upcast: appendUpcast("-other")},
+		},
+		{
+			name: "cycle",
+			registered: []*Upcaster{
+				v2,
+				{EventType: "test.event", From: "v2", To: "v3", Upcast: appendUpcast("-v3")},
+			},
+			upcaster: &Upcaster{EventType: "test.event", From: "v3", To: "v1", Upcast: appendUpcast("-v1")},
+			isErr:    errors.IsErrorInvalidArgument,
+		},
+		{
+			name:       "cycle of other event type",
+			registered: []*Upcaster{{EventType: "test.other", From: "v1", To: "v2", Upcast: appendUpcast("-v2")}},
+			upcaster:   &Upcaster{EventType: "test.event", From: "v2", To: "v1", Upcast: appendUpcast("-v1")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &Eventstore{
+				interceptorMutex:  sync.Mutex{},
+				eventInterceptors: map[EventType]eventTypeInterceptors{},
+			}
+			for _, registered := range tt.registered {
+				if err := es.RegisterUpcaster(registered); err != nil {
+					t.Fatalf("unable to register upcaster: %v", err)
+				}
+			}
+			err := es.RegisterUpcaster(tt.upcaster)
+			if tt.isErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.isErr != nil && !tt.isErr(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore/pii"
 	"github.com/zitadel/zitadel/internal/eventstore/v1/internal/repository"
 	z_sql "github.com/zitadel/zitadel/internal/eventstore/v1/internal/repository/sql"
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
@@ -20,11 +21,15 @@ var _ Eventstore = (*eventstore)(nil)
 
 type eventstore struct {
 	repo repository.Repository
+	pii  *pii.Crypto
 }
 
-func Start(db *database.DB, allowOrderByCreationDate bool) (Eventstore, error) {
+// Start starts the eventstore,
+// the personal data of the events is decrypted with piiCrypto of the v2 eventstore
+func Start(db *database.DB, allowOrderByCreationDate bool, piiCrypto *pii.Crypto) (Eventstore, error) {
 	return &eventstore{
 		repo: z_sql.Start(db, allowOrderByCreationDate),
+		pii:  piiCrypto,
 	}, nil
 }
 
@@ -32,7 +37,28 @@ func (es *eventstore) FilterEvents(ctx context.Context, searchQuery *models.Sear
 	if err := searchQuery.Validate(); err != nil {
 		return nil, err
 	}
-	return es.repo.Filter(ctx, models.FactoryFromSearchQuery(searchQuery))
+	events, err := es.repo.Filter(ctx, models.FactoryFromSearchQuery(searchQuery))
+	if err != nil {
+		return nil, err
+	}
+	return events, es.decryptPII(events)
+}
+
+func (es *eventstore) decryptPII(events []*models.Event) (err error) {
+	if es.pii == nil {
+		return nil
+	}
+	decrypter := es.pii.NewDecrypter()
+	for _, event := range events {
+		if !pii.MightBeEncrypted(event.Data) {
+			continue
+		}
+		event.Data, err = decrypter.Decrypt(event.InstanceID, event.AggregateID, event.Data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (es *eventstore) Health(ctx context.Context) error {
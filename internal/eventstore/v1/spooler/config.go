@@ -21,7 +21,7 @@ type Config struct {
 }
 
 func (c *Config) New() *Spooler {
-	lockID, err := id.SonyFlakeGenerator().Next()
+	lockID, err := id.DefaultGenerator().Next()
 	logging.OnError(err).Panic("unable to generate lockID")
 
 	//shuffle the handlers for better balance when running multiple pods
@@ -1,6 +1,10 @@
 package eventstore
 
-import "time"
+import (
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
 
 //WriteModel is the minimum representation of a command side write model.
 // It implements a basic reducer
@@ -45,3 +49,14 @@ func (wm *WriteModel) Reduce() error {
 	wm.Events = []Event{}
 	return nil
 }
+
+func (wm *WriteModel) snapshotAggregateID() string {
+	return wm.AggregateID
+}
+
+func (wm *WriteModel) restoreSnapshot(snapshot *repository.Snapshot) {
+	wm.ResourceOwner = snapshot.ResourceOwner
+	wm.InstanceID = snapshot.InstanceID
+	wm.ProcessedSequence = snapshot.Sequence
+	wm.ChangeDate = snapshot.ChangeDate
+}
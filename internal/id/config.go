@@ -1,11 +1,31 @@
 package id
 
+import (
+	"time"
+)
+
 const (
 	DefaultWebhookPath = "http://metadata.google.internal/computeMetadata/v1/instance/id"
+	// DefaultLeaseDuration is the duration of a machine id leased from the database, if none is configured
+	DefaultLeaseDuration = time.Minute
+)
+
+type GeneratorType string
+
+const (
+	// GeneratorTypeSonyflake generates numeric ids, unique per identified machine
+	GeneratorTypeSonyflake GeneratorType = "sonyflake"
+	// GeneratorTypeULID generates lexicographically sortable ids with 80 bits of randomness
+	GeneratorTypeULID GeneratorType = "ulid"
+	// GeneratorTypeUUIDv7 generates time ordered UUIDs (RFC 9562)
+	GeneratorTypeUUIDv7 GeneratorType = "uuidv7"
 )
 
 type Config struct {
+	// Type of the generated ids: sonyflake (default), ulid or uuidv7
+	Generator GeneratorType
 	// Configuration for the identification of machines.
+	// Only required by the sonyflake generator.
 	Identification Identification
 }
 
@@ -16,6 +36,8 @@ type Identification struct {
 	Hostname Hostname
 	// Configuration for using a webhook to identify a machine.
 	Webhook Webhook
+	// Configuration for leasing a machine id from the database.
+	Database Database
 }
 
 type PrivateIp struct {
@@ -39,6 +61,15 @@ type Webhook struct {
 	Headers *map[string]string
 }
 
+type Database struct {
+	// Lease a machine id from the database, which is not used by any other running machine.
+	// If enabled, it's preferred over the other methods.
+	Enabled bool
+	// The duration of the lease, it's renewed after a third of the duration.
+	// If the lease can't be renewed in time, no ids are generated until it's leased again.
+	LeaseDuration time.Duration
+}
+
 func Configure(config *Config) {
 	if config != nil {
 		GeneratorConfig = config
@@ -1,5 +1,49 @@
 package id
 
+import (
+	"sync"
+
+	"github.com/zitadel/logging"
+)
+
 type Generator interface {
 	Next() (string, error)
 }
+
+var (
+	defaultGenerator      Generator
+	defaultGeneratorMutex sync.Mutex
+)
+
+// DefaultGenerator returns the generator of the configured type
+// the function panics if the generator cannot be created
+func DefaultGenerator() Generator {
+	defaultGeneratorMutex.Lock()
+	defer defaultGeneratorMutex.Unlock()
+
+	if defaultGenerator != nil {
+		return defaultGenerator
+	}
+	var generatorType GeneratorType
+	if GeneratorConfig != nil {
+		generatorType = GeneratorConfig.Generator
+	}
+	defaultGenerator = NewGenerator(generatorType)
+	return defaultGenerator
+}
+
+// NewGenerator returns a generator of the type,
+// the function panics if the type is unknown or the sonyflake generator cannot identify the machine
+func NewGenerator(generatorType GeneratorType) Generator {
+	switch generatorType {
+	case GeneratorTypeSonyflake, "":
+		return SonyFlakeGenerator()
+	case GeneratorTypeULID:
+		return newULIDGenerator()
+	case GeneratorTypeUUIDv7:
+		return newUUIDv7Generator()
+	default:
+		logging.WithFields("generator", generatorType).Panic("unknown id generator")
+		return nil
+	}
+}
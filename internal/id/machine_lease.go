@@ -0,0 +1,185 @@
+package id
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/zitadel/logging"
+)
+
+const (
+	leaseMachineIDStmt = `INSERT INTO system.id_machine_leases AS leases (machine_id, owner, expires_at)` +
+		` SELECT s.id, $1, now() + $2 * INTERVAL '1 millisecond'` +
+		` FROM generate_series(0, 65535) AS s(id)` +
+		` WHERE NOT EXISTS (SELECT 1 FROM system.id_machine_leases l WHERE l.machine_id = s.id AND l.expires_at > now())` +
+		` ORDER BY s.id LIMIT 1` +
+		` ON CONFLICT (machine_id) DO UPDATE SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at` +
+		` WHERE leases.expires_at <= now()` +
+		` RETURNING machine_id`
+	// renewMachineIDStmt extends the lease of the machine id,
+	// it's leased again if it expired in the meantime and no other machine leased it
+	renewMachineIDStmt = `INSERT INTO system.id_machine_leases AS leases (machine_id, owner, expires_at)` +
+		` VALUES ($2, $3, now() + $1 * INTERVAL '1 millisecond')` +
+		` ON CONFLICT (machine_id) DO UPDATE SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at` +
+		` WHERE leases.owner = EXCLUDED.owner OR leases.expires_at <= now()`
+
+	// leaseAttempts is the number of tries to lease a machine id,
+	// another machine might lease the same id concurrently
+	leaseAttempts = 5
+)
+
+var (
+	databaseClient *sql.DB
+	machineLease   *machineIDLease
+
+	errLeaseExpired = errors.New("the lease of the machine id expired")
+)
+
+// ConfigureDatabase sets the client used to lease the machine id,
+// if the identification by the database is enabled
+func ConfigureDatabase(client *sql.DB) {
+	databaseClient = client
+}
+
+// machineIDLease is a machine id leased from the database for the duration.
+// The lease is renewed in the background, ids must not be generated after it's expired,
+// because the machine id might be leased by another machine.
+type machineIDLease struct {
+	client   *sql.DB
+	owner    string
+	duration time.Duration
+	now      func() time.Time
+
+	machineID  uint16
+	mutex      sync.RWMutex
+	validUntil time.Time
+}
+
+func leaseMachineID(ctx context.Context, client *sql.DB, duration time.Duration) (*machineIDLease, error) {
+	if client == nil {
+		return nil, errors.New("no database configured")
+	}
+	if duration <= 0 {
+		duration = DefaultLeaseDuration
+	}
+	owner, err := leaseOwner()
+	if err != nil {
+		return nil, err
+	}
+	lease := &machineIDLease{
+		client:   client,
+		owner:    owner,
+		duration: duration,
+		now:      time.Now,
+	}
+	for i := 0; i < leaseAttempts; i++ {
+		start := lease.now()
+		err = client.QueryRowContext(ctx, leaseMachineIDStmt, owner, duration.Milliseconds()).Scan(&lease.machineID)
+		if errors.Is(err, sql.ErrNoRows) {
+			// the id was leased by another machine in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		lease.extend(start)
+		return lease, nil
+	}
+	return nil, errors.New("no machine id available")
+}
+
+// renew extends the lease, if it's still owned by this machine or not leased by any other machine.
+// The machine id of the sonyflake generator can't be changed, therefore the same id is leased again.
+func (l *machineIDLease) renew(ctx context.Context) error {
+	start := l.now()
+	result, err := l.client.ExecContext(ctx, renewMachineIDStmt, l.duration.Milliseconds(), l.machineID, l.owner)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		l.expire()
+		return errLeaseExpired
+	}
+	l.extend(start)
+	return nil
+}
+
+// keepAlive renews the lease after a third of the duration.
+// If the lease is lost, it's tried to lease the machine id again,
+// until then no ids are generated and the health check fails.
+func (l *machineIDLease) keepAlive() {
+	ticker := time.NewTicker(l.duration / 3)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), l.duration/3)
+		err := l.renew(ctx)
+		cancel()
+		if errors.Is(err, errLeaseExpired) {
+			logging.WithFields("machineID", l.machineID).Error("lease of the machine id lost, no ids are generated until it's leased again")
+			continue
+		}
+		logging.WithFields("machineID", l.machineID).OnError(err).Warn("unable to renew the lease of the machine id")
+	}
+}
+
+// extend sets the end of the lease, the duration starts before the statement was executed.
+// A tenth of the duration is kept as safety margin for the clock skew to the database.
+func (l *machineIDLease) extend(start time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.validUntil = start.Add(l.duration - l.duration/10)
+}
+
+func (l *machineIDLease) expire() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.validUntil = time.Time{}
+}
+
+func (l *machineIDLease) valid() bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.now().Before(l.validUntil)
+}
+
+// Health returns an error if the machine id is leased from the database
+// and the lease expired, so no ids can be generated
+func Health(context.Context) error {
+	if machineLease != nil && !machineLease.valid() {
+		return errLeaseExpired
+	}
+	return nil
+}
+
+// leaseOwner identifies the process holding the lease
+func leaseOwner() (string, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	random := make([]byte, 8)
+	if _, err = rand.Read(random); err != nil {
+		return "", err
+	}
+	return host + "-" + hex.EncodeToString(random), nil
+}
+
+func databaseMachineID() (uint16, error) {
+	lease, err := leaseMachineID(context.Background(), databaseClient, GeneratorConfig.Identification.Database.LeaseDuration)
+	if err != nil {
+		return 0, err
+	}
+	machineLease = lease
+	go lease.keepAlive()
+	return lease.machineID, nil
+}
@@ -0,0 +1,88 @@
+package id
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_leaseMachineID(t *testing.T) {
+	client, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer client.Close()
+
+	// the first candidate was leased by another machine concurrently
+	mock.ExpectQuery(regexp.QuoteMeta(leaseMachineIDStmt)).
+		WithArgs(sqlmock.AnyArg(), int64(30000)).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(regexp.QuoteMeta(leaseMachineIDStmt)).
+		WithArgs(sqlmock.AnyArg(), int64(30000)).
+		WillReturnRows(sqlmock.NewRows([]string{"machine_id"}).AddRow(int64(2)))
+
+	lease, err := leaseMachineID(context.Background(), client, 30*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(2), lease.machineID)
+	assert.True(t, lease.valid())
+
+	mock.ExpectExec(regexp.QuoteMeta(renewMachineIDStmt)).
+		WithArgs(int64(30000), int64(2), lease.owner).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, lease.renew(context.Background()))
+	assert.True(t, lease.valid())
+
+	// the lease expired and was taken by another machine
+	mock.ExpectExec(regexp.QuoteMeta(renewMachineIDStmt)).
+		WithArgs(int64(30000), int64(2), lease.owner).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, lease.renew(context.Background()), errLeaseExpired)
+	assert.False(t, lease.valid())
+
+	// the lease of the other machine expired, the machine id is leased again
+	mock.ExpectExec(regexp.QuoteMeta(renewMachineIDStmt)).
+		WithArgs(int64(30000), int64(2), lease.owner).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, lease.renew(context.Background()))
+	assert.True(t, lease.valid())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_machineIDLease_valid(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	lease := &machineIDLease{
+		duration: time.Minute,
+		now:      func() time.Time { return now },
+	}
+	lease.extend(now)
+	assert.True(t, lease.valid())
+
+	// the safety margin has started
+	now = now.Add(55 * time.Second)
+	assert.False(t, lease.valid())
+}
+
+func Test_leaseMachineID_noDatabase(t *testing.T) {
+	_, err := leaseMachineID(context.Background(), nil, time.Minute)
+	assert.Error(t, err)
+}
+
+func TestHealth(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	lease := &machineIDLease{
+		duration: time.Minute,
+		now:      func() time.Time { return now },
+	}
+	lease.extend(now)
+	machineLease = lease
+	defer func() { machineLease = nil }()
+	assert.NoError(t, Health(context.Background()))
+
+	lease.expire()
+	assert.ErrorIs(t, Health(context.Background()), errLeaseExpired)
+}
@@ -24,6 +24,9 @@ type sonyflakeGenerator struct {
 }
 
 func (s *sonyflakeGenerator) Next() (string, error) {
+	if machineLease != nil && !machineLease.valid() {
+		return "", errLeaseExpired
+	}
 	id, err := s.NextID()
 	if err != nil {
 		return "", err
@@ -95,6 +98,14 @@ func machineID() (uint16, error) {
 	}
 
 	errors := []string{}
+	if GeneratorConfig.Identification.Database.Enabled {
+		cid, err := databaseMachineID()
+		if err == nil {
+			return cid, nil
+		}
+		errors = append(errors, fmt.Sprintf("failed to lease machine id from database %s", err))
+	}
+
 	if GeneratorConfig.Identification.PrivateIp.Enabled {
 		ip, err := lower16BitPrivateIP()
 		if err == nil {
@@ -0,0 +1,79 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// crockfordAlphabet is the base32 alphabet of ULIDs.
+// The lower case variant is used, as ids might be part of case insensitive values (e.g. domains)
+const crockfordAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// ulidGenerator generates monotonic ULIDs (https://github.com/ulid/spec):
+// ids of the same millisecond increment the random part of the previous id
+type ulidGenerator struct {
+	mutex       sync.Mutex
+	now         func() time.Time
+	random      io.Reader
+	lastMS      uint64
+	lastEntropy [10]byte
+}
+
+func newULIDGenerator() *ulidGenerator {
+	return &ulidGenerator{
+		now:    time.Now,
+		random: rand.Reader,
+	}
+}
+
+func (g *ulidGenerator) Next() (string, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	ms := uint64(g.now().UnixMilli())
+	// the clock might go backwards, the ids stay monotonic anyway
+	if ms <= g.lastMS {
+		if !increment(g.lastEntropy[:]) {
+			return "", errors.New("ulid entropy overflow in the same millisecond")
+		}
+	} else {
+		if _, err := io.ReadFull(g.random, g.lastEntropy[:]); err != nil {
+			return "", err
+		}
+		g.lastMS = ms
+	}
+
+	var id [16]byte
+	binary.BigEndian.PutUint16(id[0:2], uint16(g.lastMS>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(g.lastMS))
+	copy(id[6:], g.lastEntropy[:])
+	return encodeULID(id), nil
+}
+
+// encodeULID encodes the 128 bits of the id in 26 characters of 5 bits
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	encoded := make([]byte, 26)
+	for i := len(encoded) - 1; i >= 0; i-- {
+		encoded[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(encoded)
+}
+
+// increment adds one to the big endian number and returns false on an overflow
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
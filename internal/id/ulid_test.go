@@ -0,0 +1,63 @@
+package id
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ulidGenerator_Next(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	generator := &ulidGenerator{
+		now:    func() time.Time { return now },
+		random: bytes.NewReader(bytes.Repeat([]byte{0x01}, 20)),
+	}
+
+	first, err := generator.Next()
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-hjkmnp-tv-z]{26}$`), first)
+	assert.Equal(t, "01h1vd8eg0", first[:10], "timestamp")
+
+	// same millisecond, the random part is incremented
+	second, err := generator.Next()
+	require.NoError(t, err)
+	assert.Less(t, first, second)
+	assert.Equal(t, first[:10], second[:10])
+
+	// the clock went backwards, the ids stay monotonic
+	now = now.Add(-time.Second)
+	third, err := generator.Next()
+	require.NoError(t, err)
+	assert.Less(t, second, third)
+
+	now = now.Add(time.Hour)
+	generator.random = bytes.NewReader(make([]byte, 10))
+	fourth, err := generator.Next()
+	require.NoError(t, err)
+	assert.Less(t, third, fourth)
+}
+
+func Test_ulidGenerator_overflow(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	generator := &ulidGenerator{
+		now:    func() time.Time { return now },
+		random: bytes.NewReader(bytes.Repeat([]byte{0xff}, 10)),
+	}
+	_, err := generator.Next()
+	require.NoError(t, err)
+	_, err = generator.Next()
+	assert.Error(t, err)
+}
+
+func Test_encodeULID(t *testing.T) {
+	var max [16]byte
+	for i := range max {
+		max[i] = 0xff
+	}
+	assert.Equal(t, "00000000000000000000000000", encodeULID([16]byte{}))
+	assert.Equal(t, "7zzzzzzzzzzzzzzzzzzzzzzzzz", encodeULID(max))
+}
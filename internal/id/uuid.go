@@ -0,0 +1,80 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"sync"
+	"time"
+)
+
+// uuidV7Generator generates version 7 UUIDs (https://www.rfc-editor.org/rfc/rfc9562#name-uuid-version-7).
+// The 12 bits after the timestamp are a counter, which keeps the ids of the same millisecond ordered.
+// The counter starts at a random value below 2048 for each millisecond,
+// if it overflows the timestamp is incremented.
+type uuidV7Generator struct {
+	mutex   sync.Mutex
+	now     func() time.Time
+	random  io.Reader
+	lastMS  uint64
+	counter uint16
+}
+
+const uuidV7MaxCounter = 0xfff
+
+func newUUIDv7Generator() *uuidV7Generator {
+	return &uuidV7Generator{
+		now:    time.Now,
+		random: rand.Reader,
+	}
+}
+
+func (g *uuidV7Generator) Next() (string, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	var id [16]byte
+	if _, err := io.ReadFull(g.random, id[6:]); err != nil {
+		return "", err
+	}
+
+	ms := uint64(g.now().UnixMilli())
+	if ms <= g.lastMS {
+		g.counter++
+		if g.counter > uuidV7MaxCounter {
+			g.lastMS++
+			g.counter = randomCounter(id[6:8])
+		}
+	} else {
+		g.lastMS = ms
+		g.counter = randomCounter(id[6:8])
+	}
+
+	binary.BigEndian.PutUint16(id[0:2], uint16(g.lastMS>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(g.lastMS))
+	// version 7 and counter
+	binary.BigEndian.PutUint16(id[6:8], 0x7000|g.counter)
+	// variant 10
+	id[8] = 0x80 | id[8]&0x3f
+	return formatUUID(id), nil
+}
+
+// randomCounter returns the start value of the counter (below 2048) from the random bytes
+func randomCounter(random []byte) uint16 {
+	return binary.BigEndian.Uint16(random) & 0x7ff
+}
+
+func formatUUID(id [16]byte) string {
+	encoded := make([]byte, 36)
+	hex.Encode(encoded[0:8], id[0:4])
+	encoded[8] = '-'
+	hex.Encode(encoded[9:13], id[4:6])
+	encoded[13] = '-'
+	hex.Encode(encoded[14:18], id[6:8])
+	encoded[18] = '-'
+	hex.Encode(encoded[19:23], id[8:10])
+	encoded[23] = '-'
+	hex.Encode(encoded[24:], id[10:])
+	return string(encoded)
+}
@@ -0,0 +1,38 @@
+package id
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_uuidV7Generator_Next(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	generator := &uuidV7Generator{
+		now:    func() time.Time { return now },
+		random: bytes.NewReader(bytes.Repeat([]byte{0xff}, 1000)),
+	}
+
+	ids := make([]string, 4)
+	for i := range ids {
+		id, err := generator.Next()
+		require.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+		ids[i] = id
+	}
+	assert.Equal(t, "018876d4-3a00-77ff", ids[0][:18], "timestamp, version and counter")
+	for i := 1; i < len(ids); i++ {
+		assert.Less(t, ids[i-1], ids[i])
+	}
+
+	// the counter overflows, the timestamp is incremented
+	generator.counter = uuidV7MaxCounter
+	overflow, err := generator.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "018876d4-3a01", overflow[:13])
+	assert.Less(t, ids[len(ids)-1], overflow)
+}
@@ -0,0 +1,233 @@
+package idp
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+// ClaimsUser is implemented by a [User], which provides the raw claims (respectively attributes)
+// it was created from. Users not implementing it are marshalled to JSON to get their claims.
+type ClaimsUser interface {
+	User
+	GetClaims() map[string]interface{}
+}
+
+// claims returns the raw claims of the user merged over the standard (OIDC) claims of the [User] interface
+func claims(user User) (map[string]interface{}, error) {
+	claims := map[string]interface{}{
+		"sub":                   user.GetID(),
+		"given_name":            user.GetFirstName(),
+		"family_name":           user.GetLastName(),
+		"name":                  user.GetDisplayName(),
+		"nickname":              user.GetNickname(),
+		"preferred_username":    user.GetPreferredUsername(),
+		"email":                 string(user.GetEmail()),
+		"email_verified":        user.IsEmailVerified(),
+		"phone_number":          string(user.GetPhone()),
+		"phone_number_verified": user.IsPhoneVerified(),
+		"locale":                user.GetPreferredLanguage().String(),
+		"picture":               user.GetAvatarURL(),
+		"profile":               user.GetProfile(),
+	}
+	var source interface{} = user
+	if claimsUser, ok := user.(ClaimsUser); ok {
+		source = claimsUser.GetClaims()
+	}
+	// the claims are marshalled in any case, so the values are of the same (JSON) types for all providers
+	data, err := json.Marshal(source)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "IDP-Cm8ja", "Errors.Internal")
+	}
+	var raw map[string]interface{}
+	// users without (exported) claims or marshalled as other types than objects are ignored
+	_ = json.Unmarshal(data, &raw)
+	for name, value := range raw {
+		claims[name] = value
+	}
+	return claims, nil
+}
+
+// claimPathSegment is a single step of a [claimPath]
+type claimPathSegment struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// claimPath is the parsed claim of a mapping
+type claimPath []claimPathSegment
+
+// ValidClaimPath checks if the claim of a mapping is either a name, a dot path or a supported JSONPath
+func ValidClaimPath(claim string) bool {
+	_, err := parseClaimPath(claim)
+	return err == nil
+}
+
+// parseClaimPath parses a dot path (`address.country`) or a JSONPath starting with `$`.
+// Supported JSONPath expressions are the child operators `.name` and `['name']`,
+// array indexes `[0]` and the wildcard `*` respectively `[*]`.
+func parseClaimPath(claim string) (claimPath, error) {
+	claim = strings.TrimSpace(claim)
+	if claim == "" {
+		return nil, errors.ThrowInvalidArgument(nil, "IDP-Cm9jb", "Errors.IDPConfig.ClaimMappingInvalid")
+	}
+	if !strings.HasPrefix(claim, "$") {
+		path := make(claimPath, 0, strings.Count(claim, ".")+1)
+		for _, name := range strings.Split(claim, ".") {
+			if name == "" {
+				return nil, errors.ThrowInvalidArgument(nil, "IDP-Cm9jc", "Errors.IDPConfig.ClaimMappingInvalid")
+			}
+			path = append(path, claimPathSegment{name: name})
+		}
+		return path, nil
+	}
+	path := make(claimPath, 0)
+	rest := claim[1:]
+	for rest != "" {
+		var segment claimPathSegment
+		var err error
+		switch rest[0] {
+		case '.':
+			segment, rest, err = parseClaimPathChild(rest[1:])
+		case '[':
+			segment, rest, err = parseClaimPathBracket(rest[1:])
+		default:
+			err = errors.ThrowInvalidArgument(nil, "IDP-Cm9jd", "Errors.IDPConfig.ClaimMappingInvalid")
+		}
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, segment)
+	}
+	return path, nil
+}
+
+// parseClaimPathChild parses the name (or wildcard) after a dot
+func parseClaimPathChild(rest string) (claimPathSegment, string, error) {
+	end := strings.IndexAny(rest, ".[")
+	if end < 0 {
+		end = len(rest)
+	}
+	name := rest[:end]
+	if name == "" {
+		return claimPathSegment{}, "", errors.ThrowInvalidArgument(nil, "IDP-Cm9je", "Errors.IDPConfig.ClaimMappingInvalid")
+	}
+	if name == "*" {
+		return claimPathSegment{wildcard: true}, rest[end:], nil
+	}
+	return claimPathSegment{name: name}, rest[end:], nil
+}
+
+// parseClaimPathBracket parses the quoted name, index or wildcard inside brackets
+func parseClaimPathBracket(rest string) (claimPathSegment, string, error) {
+	if rest != "" && (rest[0] == '\'' || rest[0] == '"') {
+		quote := rest[0]
+		end := strings.IndexByte(rest[1:], quote) + 1
+		if end < 1 || len(rest) <= end+1 || rest[end+1] != ']' {
+			return claimPathSegment{}, "", errors.ThrowInvalidArgument(nil, "IDP-Cm9jf", "Errors.IDPConfig.ClaimMappingInvalid")
+		}
+		return claimPathSegment{name: rest[1:end]}, rest[end+2:], nil
+	}
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return claimPathSegment{}, "", errors.ThrowInvalidArgument(nil, "IDP-Cm9jg", "Errors.IDPConfig.ClaimMappingInvalid")
+	}
+	value := strings.TrimSpace(rest[:end])
+	if value == "*" {
+		return claimPathSegment{wildcard: true}, rest[end+1:], nil
+	}
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 {
+		return claimPathSegment{}, "", errors.ThrowInvalidArgument(err, "IDP-Cm9jh", "Errors.IDPConfig.ClaimMappingInvalid")
+	}
+	return claimPathSegment{index: index, isIndex: true}, rest[end+1:], nil
+}
+
+// resolve returns the values of the path in the claims
+func (p claimPath) resolve(claims map[string]interface{}) []interface{} {
+	nodes := []interface{}{claims}
+	for _, segment := range p {
+		next := make([]interface{}, 0, len(nodes))
+		for _, node := range nodes {
+			next = append(next, segment.resolve(node)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (s claimPathSegment) resolve(node interface{}) []interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			values := make([]interface{}, 0, len(n))
+			for _, value := range n {
+				values = append(values, value)
+			}
+			return values
+		}
+		if s.isIndex {
+			return nil
+		}
+		if value, ok := n[s.name]; ok {
+			return []interface{}{value}
+		}
+	case []interface{}:
+		if s.wildcard {
+			return n
+		}
+		if s.isIndex && s.index < len(n) {
+			return []interface{}{n[s.index]}
+		}
+	}
+	return nil
+}
+
+// claimValues resolves the claim of a mapping and converts the values to strings.
+// A claim name matching a claim literally (e.g. `https://example.com/roles`) has precedence over the path.
+// Arrays are flattened, objects are represented as JSON.
+func claimValues(claims map[string]interface{}, claim string) ([]string, error) {
+	var values []interface{}
+	if value, ok := claims[claim]; ok {
+		values = []interface{}{value}
+	} else {
+		path, err := parseClaimPath(claim)
+		if err != nil {
+			return nil, err
+		}
+		values = path.resolve(claims)
+	}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = appendClaimValue(result, value)
+	}
+	return result, nil
+}
+
+func appendClaimValue(values []string, value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return values
+	case string:
+		return append(values, v)
+	case []interface{}:
+		for _, item := range v {
+			values = appendClaimValue(values, item)
+		}
+		return values
+	case bool:
+		return append(values, strconv.FormatBool(v))
+	case float64:
+		return append(values, strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return values
+		}
+		return append(values, string(data))
+	}
+}
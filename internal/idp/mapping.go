@@ -0,0 +1,266 @@
+package idp
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+const defaultClaimSeparator = ","
+
+var _ User = (*MappedUser)(nil)
+
+// MappedUser is a [User] with the claim mappings of the identity provider applied.
+// Profile information without a mapped value is taken from the underlying user.
+type MappedUser struct {
+	User
+	fields map[domain.IDPClaimMappingTarget]string
+	// Metadata contains the mapped metadata by its key.
+	// Claims with multiple values are stored as JSON array.
+	Metadata map[string][]byte
+	// Roles contains the mapped role keys by the id of their project,
+	// only the roles managed by the mappings are contained (see [ManagedRoles]).
+	Roles map[string][]string
+}
+
+// MapUser applies the mappings to the claims of the user
+func MapUser(user User, mappings []*domain.IDPClaimMapping) (*MappedUser, error) {
+	mapped := &MappedUser{
+		User:     user,
+		fields:   make(map[domain.IDPClaimMappingTarget]string),
+		Metadata: make(map[string][]byte),
+		Roles:    make(map[string][]string),
+	}
+	if len(mappings) == 0 {
+		return mapped, nil
+	}
+	userClaims, err := claims(user)
+	if err != nil {
+		return nil, err
+	}
+	for _, mapping := range mappings {
+		values, err := claimValues(userClaims, mapping.Claim)
+		if err != nil {
+			return nil, err
+		}
+		values = transformClaimValues(values, mapping)
+		if len(values) == 0 && mapping.Default != "" {
+			values = []string{mapping.Default}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		mapped.apply(mapping, values)
+	}
+	return mapped, nil
+}
+
+func (u *MappedUser) apply(mapping *domain.IDPClaimMapping, values []string) {
+	switch mapping.Target {
+	case domain.IDPClaimMappingTargetMetadata:
+		u.Metadata[mapping.Key] = metadataValue(values)
+	case domain.IDPClaimMappingTargetRole:
+		for _, value := range values {
+			if containsString(mapping.Roles, value) && !containsString(u.Roles[mapping.Key], value) {
+				u.Roles[mapping.Key] = append(u.Roles[mapping.Key], value)
+			}
+		}
+	default:
+		u.fields[mapping.Target] = values[0]
+	}
+}
+
+// ManagedRoles returns the role keys managed by the mappings by the id of their project.
+// Only these roles are set according to the mapped roles of a user, all other roles are kept.
+func ManagedRoles(mappings []*domain.IDPClaimMapping) map[string][]string {
+	managed := make(map[string][]string)
+	for _, mapping := range mappings {
+		if mapping.Target != domain.IDPClaimMappingTargetRole {
+			continue
+		}
+		for _, role := range mapping.Roles {
+			if !containsString(managed[mapping.Key], role) {
+				managed[mapping.Key] = append(managed[mapping.Key], role)
+			}
+		}
+	}
+	return managed
+}
+
+func transformClaimValues(values []string, mapping *domain.IDPClaimMapping) []string {
+	for _, transform := range mapping.Transforms {
+		switch transform {
+		case domain.IDPClaimTransformLowercase:
+			values = mapStrings(values, strings.ToLower)
+		case domain.IDPClaimTransformUppercase:
+			values = mapStrings(values, strings.ToUpper)
+		case domain.IDPClaimTransformTrim:
+			values = mapStrings(values, strings.TrimSpace)
+		case domain.IDPClaimTransformSplit:
+			separator := mapping.Separator
+			if separator == "" {
+				separator = defaultClaimSeparator
+			}
+			split := make([]string, 0, len(values))
+			for _, value := range values {
+				split = append(split, strings.Split(value, separator)...)
+			}
+			values = split
+		}
+	}
+	nonEmpty := values[:0]
+	for _, value := range values {
+		if value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+	return nonEmpty
+}
+
+func mapStrings(values []string, f func(string) string) []string {
+	for i, value := range values {
+		values[i] = f(value)
+	}
+	return values
+}
+
+func metadataValue(values []string) []byte {
+	if len(values) == 1 {
+		return []byte(values[0])
+	}
+	data, _ := json.Marshal(values)
+	return data
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the user returned by the provider
+func (u *MappedUser) Unwrap() User {
+	return u.User
+}
+
+// MarshalJSON marshals the user returned by the provider,
+// so the stored information of the user doesn't depend on the mappings
+func (u *MappedUser) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.User)
+}
+
+// MappedInformation is the information of a [MappedUser],
+// which is stored in addition to the user returned by the provider (e.g. on a succeeded intent)
+type MappedInformation struct {
+	FirstName         string              `json:"firstName,omitempty"`
+	LastName          string              `json:"lastName,omitempty"`
+	DisplayName       string              `json:"displayName,omitempty"`
+	NickName          string              `json:"nickName,omitempty"`
+	PreferredUsername string              `json:"preferredUsername,omitempty"`
+	Email             domain.EmailAddress `json:"email,omitempty"`
+	IsEmailVerified   bool                `json:"isEmailVerified,omitempty"`
+	Phone             domain.PhoneNumber  `json:"phone,omitempty"`
+	IsPhoneVerified   bool                `json:"isPhoneVerified,omitempty"`
+	PreferredLanguage language.Tag        `json:"preferredLanguage,omitempty"`
+	Metadata          map[string][]byte   `json:"metadata,omitempty"`
+	Roles             map[string][]string `json:"roles,omitempty"`
+}
+
+// Information returns the profile information of the user with the mappings applied,
+// as well as the mapped metadata and roles
+func (u *MappedUser) Information() *MappedInformation {
+	return &MappedInformation{
+		FirstName:         u.GetFirstName(),
+		LastName:          u.GetLastName(),
+		DisplayName:       u.GetDisplayName(),
+		NickName:          u.GetNickname(),
+		PreferredUsername: u.GetPreferredUsername(),
+		Email:             u.GetEmail(),
+		IsEmailVerified:   u.IsEmailVerified(),
+		Phone:             u.GetPhone(),
+		IsPhoneVerified:   u.IsPhoneVerified(),
+		PreferredLanguage: u.GetPreferredLanguage(),
+		Metadata:          u.Metadata,
+		Roles:             u.Roles,
+	}
+}
+
+func (u *MappedUser) field(target domain.IDPClaimMappingTarget, fallback func() string) string {
+	if value, ok := u.fields[target]; ok {
+		return value
+	}
+	return fallback()
+}
+
+func (u *MappedUser) boolField(target domain.IDPClaimMappingTarget, fallback func() bool) bool {
+	if value, ok := u.fields[target]; ok {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return fallback()
+}
+
+func (u *MappedUser) GetFirstName() string {
+	return u.field(domain.IDPClaimMappingTargetFirstName, u.User.GetFirstName)
+}
+
+func (u *MappedUser) GetLastName() string {
+	return u.field(domain.IDPClaimMappingTargetLastName, u.User.GetLastName)
+}
+
+func (u *MappedUser) GetDisplayName() string {
+	return u.field(domain.IDPClaimMappingTargetDisplayName, u.User.GetDisplayName)
+}
+
+func (u *MappedUser) GetNickname() string {
+	return u.field(domain.IDPClaimMappingTargetNickName, u.User.GetNickname)
+}
+
+func (u *MappedUser) GetPreferredUsername() string {
+	return u.field(domain.IDPClaimMappingTargetPreferredUsername, u.User.GetPreferredUsername)
+}
+
+func (u *MappedUser) GetEmail() domain.EmailAddress {
+	return domain.EmailAddress(u.field(domain.IDPClaimMappingTargetEmail, func() string {
+		return string(u.User.GetEmail())
+	}))
+}
+
+func (u *MappedUser) IsEmailVerified() bool {
+	return u.boolField(domain.IDPClaimMappingTargetEmailVerified, u.User.IsEmailVerified)
+}
+
+func (u *MappedUser) GetPhone() domain.PhoneNumber {
+	return domain.PhoneNumber(u.field(domain.IDPClaimMappingTargetPhone, func() string {
+		return string(u.User.GetPhone())
+	}))
+}
+
+func (u *MappedUser) IsPhoneVerified() bool {
+	return u.boolField(domain.IDPClaimMappingTargetPhoneVerified, u.User.IsPhoneVerified)
+}
+
+func (u *MappedUser) GetPreferredLanguage() language.Tag {
+	if value, ok := u.fields[domain.IDPClaimMappingTargetPreferredLanguage]; ok {
+		if tag, err := language.Parse(value); err == nil {
+			return tag
+		}
+	}
+	return u.User.GetPreferredLanguage()
+}
+
+func (u *MappedUser) GetAvatarURL() string {
+	return u.field(domain.IDPClaimMappingTargetAvatarURL, u.User.GetAvatarURL)
+}
+
+func (u *MappedUser) GetProfile() string {
+	return u.field(domain.IDPClaimMappingTargetProfile, u.User.GetProfile)
+}
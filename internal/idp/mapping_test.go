@@ -0,0 +1,243 @@
+package idp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+type testUser struct {
+	ID         string                 `json:"sub"`
+	FirstName  string                 `json:"given_name"`
+	Email      string                 `json:"email"`
+	Groups     []string               `json:"groups,omitempty"`
+	Department string                 `json:"department,omitempty"`
+	Address    map[string]interface{} `json:"address,omitempty"`
+	Roles      []string               `json:"https://example.com/roles,omitempty"`
+}
+
+func (u *testUser) GetID() string                      { return u.ID }
+func (u *testUser) GetFirstName() string               { return u.FirstName }
+func (u *testUser) GetLastName() string                { return "" }
+func (u *testUser) GetDisplayName() string             { return "" }
+func (u *testUser) GetNickname() string                { return "" }
+func (u *testUser) GetPreferredUsername() string       { return u.Email }
+func (u *testUser) GetEmail() domain.EmailAddress      { return domain.EmailAddress(u.Email) }
+func (u *testUser) IsEmailVerified() bool              { return false }
+func (u *testUser) GetPhone() domain.PhoneNumber       { return "" }
+func (u *testUser) IsPhoneVerified() bool              { return false }
+func (u *testUser) GetPreferredLanguage() language.Tag { return language.Und }
+func (u *testUser) GetAvatarURL() string               { return "" }
+func (u *testUser) GetProfile() string                 { return "" }
+
+type testClaimsUser struct {
+	testUser
+	claims map[string]interface{}
+}
+
+func (u *testClaimsUser) GetClaims() map[string]interface{} {
+	return u.claims
+}
+
+func TestMapUser(t *testing.T) {
+	type want struct {
+		firstName         string
+		lastName          string
+		preferredUsername string
+		email             domain.EmailAddress
+		emailVerified     bool
+		preferredLanguage language.Tag
+		metadata          map[string][]byte
+		roles             map[string][]string
+		err               bool
+	}
+	tests := []struct {
+		name     string
+		user     User
+		mappings []*domain.IDPClaimMapping
+		want     want
+	}{
+		{
+			name: "no mappings",
+			user: &testUser{ID: "id", FirstName: "first", Email: "user@example.com"},
+			want: want{
+				firstName:         "first",
+				preferredUsername: "user@example.com",
+				email:             "user@example.com",
+				preferredLanguage: language.Und,
+				metadata:          map[string][]byte{},
+				roles:             map[string][]string{},
+			},
+		},
+		{
+			name: "profile fields with transforms and defaults",
+			user: &testUser{ID: "id", FirstName: "first", Email: "User@Example.com", Department: " Sales "},
+			mappings: []*domain.IDPClaimMapping{
+				{Claim: "email", Target: domain.IDPClaimMappingTargetEmail, Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformLowercase}},
+				{Claim: "email", Target: domain.IDPClaimMappingTargetPreferredUsername, Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformLowercase}},
+				{Claim: "family_name", Target: domain.IDPClaimMappingTargetLastName, Default: "unknown"},
+				{Claim: "missing", Target: domain.IDPClaimMappingTargetFirstName},
+				{Claim: "verified", Target: domain.IDPClaimMappingTargetEmailVerified, Default: "true"},
+				{Claim: "lang", Target: domain.IDPClaimMappingTargetPreferredLanguage, Default: "de"},
+				{Claim: "department", Target: domain.IDPClaimMappingTargetMetadata, Key: "department", Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformTrim, domain.IDPClaimTransformUppercase}},
+			},
+			want: want{
+				firstName:         "first",
+				lastName:          "unknown",
+				preferredUsername: "user@example.com",
+				email:             "user@example.com",
+				emailVerified:     true,
+				preferredLanguage: language.German,
+				metadata:          map[string][]byte{"department": []byte("SALES")},
+				roles:             map[string][]string{},
+			},
+		},
+		{
+			name: "json path and nested claims",
+			user: &testUser{
+				ID:      "id",
+				Email:   "user@example.com",
+				Groups:  []string{"Admin", "Editor", "admin"},
+				Address: map[string]interface{}{"country": "CH", "locality": "Zurich"},
+				Roles:   []string{"viewer"},
+			},
+			mappings: []*domain.IDPClaimMapping{
+				{Claim: "$.groups[*]", Target: domain.IDPClaimMappingTargetRole, Key: "project1", Roles: []string{"admin", "editor"}, Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformLowercase}},
+				{Claim: "$['https://example.com/roles'][0]", Target: domain.IDPClaimMappingTargetRole, Key: "project2", Roles: []string{"viewer"}},
+				{Claim: "https://example.com/roles", Target: domain.IDPClaimMappingTargetRole, Key: "project1", Roles: []string{"viewer"}},
+				{Claim: "$.missing[*]", Target: domain.IDPClaimMappingTargetRole, Key: "project3", Roles: []string{"viewer"}},
+				{Claim: "address.country", Target: domain.IDPClaimMappingTargetMetadata, Key: "country"},
+				{Claim: "$.groups", Target: domain.IDPClaimMappingTargetMetadata, Key: "groups"},
+			},
+			want: want{
+				preferredUsername: "user@example.com",
+				email:             "user@example.com",
+				preferredLanguage: language.Und,
+				metadata: map[string][]byte{
+					"country": []byte("CH"),
+					"groups":  []byte(`["Admin","Editor","admin"]`),
+				},
+				roles: map[string][]string{
+					"project1": {"admin", "editor", "viewer"},
+					"project2": {"viewer"},
+				},
+			},
+		},
+		{
+			name: "raw claims and split",
+			user: &testClaimsUser{
+				testUser: testUser{ID: "id"},
+				claims: map[string]interface{}{
+					"memberOf": []string{"a; b", "c"},
+					"level":    5,
+				},
+			},
+			mappings: []*domain.IDPClaimMapping{
+				{Claim: "memberOf", Target: domain.IDPClaimMappingTargetRole, Key: "project1", Roles: []string{"a", "c", "d"}, Separator: ";", Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformSplit, domain.IDPClaimTransformTrim}},
+				{Claim: "level", Target: domain.IDPClaimMappingTargetMetadata, Key: "level"},
+			},
+			want: want{
+				preferredLanguage: language.Und,
+				metadata:          map[string][]byte{"level": []byte("5")},
+				roles:             map[string][]string{"project1": {"a", "c"}},
+			},
+		},
+		{
+			name: "invalid path",
+			user: &testUser{ID: "id"},
+			mappings: []*domain.IDPClaimMapping{
+				{Claim: "$.groups[", Target: domain.IDPClaimMappingTargetNickName},
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MapUser(tt.user, tt.mappings)
+			if tt.want.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.user, got.Unwrap())
+			assert.Equal(t, tt.want.firstName, got.GetFirstName())
+			assert.Equal(t, tt.want.lastName, got.GetLastName())
+			assert.Equal(t, tt.want.preferredUsername, got.GetPreferredUsername())
+			assert.Equal(t, tt.want.email, got.GetEmail())
+			assert.Equal(t, tt.want.emailVerified, got.IsEmailVerified())
+			assert.Equal(t, tt.want.preferredLanguage, got.GetPreferredLanguage())
+			assert.Equal(t, tt.want.metadata, got.Metadata)
+			assert.Equal(t, tt.want.roles, got.Roles)
+		})
+	}
+}
+
+func TestManagedRoles(t *testing.T) {
+	managed := ManagedRoles([]*domain.IDPClaimMapping{
+		{Claim: "groups", Target: domain.IDPClaimMappingTargetRole, Key: "project1", Roles: []string{"admin", "editor"}},
+		{Claim: "roles", Target: domain.IDPClaimMappingTargetRole, Key: "project1", Roles: []string{"editor", "viewer"}},
+		{Claim: "roles", Target: domain.IDPClaimMappingTargetRole, Key: "project2", Roles: []string{"viewer"}},
+		{Claim: "department", Target: domain.IDPClaimMappingTargetMetadata, Key: "department"},
+	})
+	assert.Equal(t, map[string][]string{
+		"project1": {"admin", "editor", "viewer"},
+		"project2": {"viewer"},
+	}, managed)
+}
+
+func TestMappedUser_Information(t *testing.T) {
+	user := &testUser{ID: "id", FirstName: "first", Email: "User@Example.com", Department: "sales"}
+	mapped, err := MapUser(user, []*domain.IDPClaimMapping{
+		{Claim: "email", Target: domain.IDPClaimMappingTargetEmail, Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformLowercase}},
+		{Claim: "department", Target: domain.IDPClaimMappingTargetMetadata, Key: "department"},
+		{Claim: "department", Target: domain.IDPClaimMappingTargetRole, Key: "project1", Roles: []string{"sales"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &MappedInformation{
+		FirstName:         "first",
+		PreferredUsername: "User@Example.com",
+		Email:             "user@example.com",
+		PreferredLanguage: language.Und,
+		Metadata:          map[string][]byte{"department": []byte("sales")},
+		Roles:             map[string][]string{"project1": {"sales"}},
+	}, mapped.Information())
+
+	// the stored user doesn't depend on the mappings
+	data, err := json.Marshal(mapped)
+	require.NoError(t, err)
+	want, err := json.Marshal(user)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(data))
+}
+
+func TestValidClaimPath(t *testing.T) {
+	tests := []struct {
+		claim string
+		want  bool
+	}{
+		{"email", true},
+		{"address.country", true},
+		{"$.groups[*]", true},
+		{"$['https://example.com/roles'][1]", true},
+		{`$["roles"].*`, true},
+		{"", false},
+		{"address..country", false},
+		{"$groups", false},
+		{"$.groups[", false},
+		{"$.groups[-1]", false},
+		{"$['roles'", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.claim, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidClaimPath(tt.claim))
+		})
+	}
+}
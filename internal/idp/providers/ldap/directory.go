@@ -0,0 +1,93 @@
+package ldap
+
+import (
+	"context"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// pagedSearcher is the part of the [ldap.Client] needed to list the users of the directory
+type pagedSearcher interface {
+	SearchWithPaging(searchRequest *ldap.SearchRequest, pagingSize uint32) (*ldap.SearchResult, error)
+}
+
+// Users lists all users of the directory matching the configured object classes.
+// The directory is searched with the bind user and paged through with the pageSize,
+// so the size limit of the LDAP server isn't exceeded.
+// Entries without an id can't be linked and are therefore ignored.
+func (p *Provider) Users(_ context.Context, pageSize uint32) (users []*User, err error) {
+	for _, server := range p.servers {
+		users, err = p.tryListUsers(server, pageSize)
+		if err == nil {
+			return users, nil
+		}
+	}
+	return nil, err
+}
+
+func (p *Provider) tryListUsers(server string, pageSize uint32) ([]*User, error) {
+	conn, err := getConnection(server, p.startTLS, p.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(p.bindDN, p.bindPassword); err != nil {
+		return nil, err
+	}
+	return p.searchUsers(conn, pageSize)
+}
+
+func (p *Provider) searchUsers(conn pagedSearcher, pageSize uint32) ([]*User, error) {
+	searchRequest := ldap.NewSearchRequest(
+		p.baseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, int(p.timeout.Seconds()), false,
+		objectClassesListSearchQuery(p.userObjectClasses),
+		p.getNecessaryAttributes(),
+		nil,
+	)
+	sr, err := conn.SearchWithPaging(searchRequest, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*User, 0, len(sr.Entries))
+	for _, entry := range sr.Entries {
+		user, err := mapLDAPEntryToUser(
+			entry,
+			p.idAttribute,
+			p.firstNameAttribute,
+			p.lastNameAttribute,
+			p.displayNameAttribute,
+			p.nickNameAttribute,
+			p.preferredUsernameAttribute,
+			p.emailAttribute,
+			p.emailVerifiedAttribute,
+			p.phoneAttribute,
+			p.phoneVerifiedAttribute,
+			p.preferredLanguageAttribute,
+			p.avatarURLAttribute,
+			p.profileAttribute,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if user.GetID() == "" {
+			continue
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// objectClassesListSearchQuery returns a filter matching all entries of the object classes,
+// or any entry if no object classes are configured
+func objectClassesListSearchQuery(classes []string) string {
+	switch len(classes) {
+	case 0:
+		return "(objectClass=*)"
+	case 1:
+		return objectClassesToSearchQuery(classes)
+	default:
+		return "(&" + objectClassesToSearchQuery(classes) + ")"
+	}
+}
@@ -0,0 +1,95 @@
+package ldap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+// pagedDirectory is a fake [pagedSearcher] returning its entries and recording the request
+type pagedDirectory struct {
+	entries  []*ldap.Entry
+	err      error
+	request  *ldap.SearchRequest
+	pageSize uint32
+}
+
+func (d *pagedDirectory) SearchWithPaging(request *ldap.SearchRequest, pageSize uint32) (*ldap.SearchResult, error) {
+	d.request = request
+	d.pageSize = pageSize
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &ldap.SearchResult{Entries: d.entries}, nil
+}
+
+func TestProvider_searchUsers(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   *Provider
+		directory  *pagedDirectory
+		wantFilter string
+		want       []*User
+		wantErr    bool
+	}{
+		{
+			name:      "search error",
+			provider:  New("ldap", nil, "dc=example,dc=com", "", "", "", []string{"inetOrgPerson"}, nil, 0, ""),
+			directory: &pagedDirectory{err: errors.New("size limit exceeded")},
+			wantErr:   true,
+		},
+		{
+			name: "users without id are ignored",
+			provider: New("ldap", nil, "dc=example,dc=com", "", "", "", []string{"inetOrgPerson", "person"}, nil, 0, "",
+				WithCustomIDAttribute("uid"),
+				WithFirstNameAttribute("givenName"),
+				WithLastNameAttribute("sn"),
+				WithEmailAttribute("mail"),
+			),
+			directory: &pagedDirectory{
+				entries: []*ldap.Entry{
+					ldap.NewEntry("cn=alice,dc=example,dc=com", map[string][]string{
+						"uid":       {"alice"},
+						"givenName": {"Alice"},
+						"sn":        {"Smith"},
+						"mail":      {"alice@example.com"},
+					}),
+					ldap.NewEntry("cn=service,dc=example,dc=com", map[string][]string{
+						"givenName": {"Service"},
+					}),
+				},
+			},
+			wantFilter: "(&(objectClass=inetOrgPerson)(objectClass=person))",
+			want: []*User{
+				NewUser("alice", "Alice", "Smith", "", "", "", domain.EmailAddress("alice@example.com"), false, "", false, language.Und, "", ""),
+			},
+		},
+		{
+			name:       "no object classes",
+			provider:   New("ldap", nil, "dc=example,dc=com", "", "", "", nil, nil, 0, "", WithCustomIDAttribute("uid")),
+			directory:  &pagedDirectory{},
+			wantFilter: "(objectClass=*)",
+			want:       []*User{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.provider.searchUsers(tt.directory, 100)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantFilter, tt.directory.request.Filter)
+			assert.Equal(t, "dc=example,dc=com", tt.directory.request.BaseDN)
+			assert.Equal(t, uint32(100), tt.directory.pageSize)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package ldap
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// searcher is the part of the [ldap.Client] needed to resolve the groups of a user
+type searcher interface {
+	Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error)
+}
+
+// groupResolver resolves the groups of a user either by an attribute of the user (e.g. memberOf),
+// a search for groups containing the user as member or both
+type groupResolver struct {
+	attribute       string
+	base            string
+	memberAttribute string
+	nested          bool
+	timeout         time.Duration
+}
+
+func (p *Provider) groupResolver() *groupResolver {
+	if !p.syncsGroups() {
+		return nil
+	}
+	return &groupResolver{
+		attribute:       p.groupsAttribute,
+		base:            p.groupBase,
+		memberAttribute: p.groupMemberAttribute,
+		nested:          p.nestedGroups,
+		timeout:         p.timeout,
+	}
+}
+
+// groups returns the DNs of all groups the user is member of,
+// including the parent groups if nested groups are resolved
+func (g *groupResolver) groups(conn searcher, user *ldap.Entry) ([]string, error) {
+	groups, err := g.parents(conn, user.DN, user.GetAttributeValues(g.attribute))
+	if err != nil || !g.nested {
+		return groups, err
+	}
+	visited := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		visited[strings.ToLower(group)] = true
+	}
+	for i := 0; i < len(groups); i++ {
+		var memberOf []string
+		if g.attribute != "" {
+			memberOf, err = g.attributeValues(conn, groups[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+		parents, err := g.parents(conn, groups[i], memberOf)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			if visited[strings.ToLower(parent)] {
+				continue
+			}
+			visited[strings.ToLower(parent)] = true
+			groups = append(groups, parent)
+		}
+	}
+	return groups, nil
+}
+
+// parents returns the groups the entry (dn) is a direct member of,
+// consisting of the provided memberOf values and the result of the group search
+func (g *groupResolver) parents(conn searcher, dn string, memberOf []string) ([]string, error) {
+	groups := make([]string, 0, len(memberOf))
+	groups = appendUnique(groups, memberOf...)
+	if g.base == "" {
+		return groups, nil
+	}
+	memberAttribute := g.memberAttribute
+	if memberAttribute == "" {
+		memberAttribute = "member"
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		g.base,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, int(g.timeout.Seconds()), false,
+		"("+memberAttribute+"="+ldap.EscapeFilter(dn)+")",
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range result.Entries {
+		groups = appendUnique(groups, entry.DN)
+	}
+	return groups, nil
+}
+
+// attributeValues returns the values of the groups attribute of the entry (dn)
+func (g *groupResolver) attributeValues(conn searcher, dn string) ([]string, error) {
+	result, err := conn.Search(ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, int(g.timeout.Seconds()), false,
+		"(objectClass=*)",
+		[]string{g.attribute},
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Entries) == 0 {
+		return nil, nil
+	}
+	return result.Entries[0].GetAttributeValues(g.attribute), nil
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		if !containsFold(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+func containsFold(list []string, value string) bool {
+	for _, entry := range list {
+		if strings.EqualFold(entry, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package ldap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// directory is a fake [searcher], which maps the DNs of the entries to their memberOf values
+// and answers member searches by them
+type directory map[string][]string
+
+func (d directory) Search(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	if request.Scope == ldap.ScopeBaseObject {
+		memberOf, ok := d[request.BaseDN]
+		if !ok {
+			return &ldap.SearchResult{}, nil
+		}
+		return &ldap.SearchResult{Entries: []*ldap.Entry{ldap.NewEntry(request.BaseDN, map[string][]string{"memberOf": memberOf})}}, nil
+	}
+	result := new(ldap.SearchResult)
+	for dn, memberOf := range d {
+		for _, group := range memberOf {
+			if request.Filter == "(member="+ldap.EscapeFilter(dn)+")" {
+				result.Entries = append(result.Entries, ldap.NewEntry(group, nil))
+			}
+		}
+	}
+	return result, nil
+}
+
+type failingSearcher struct{}
+
+func (failingSearcher) Search(*ldap.SearchRequest) (*ldap.SearchResult, error) {
+	return nil, errors.New("search failed")
+}
+
+func TestProvider_groupResolver_groups(t *testing.T) {
+	dir := directory{
+		"cn=user,dc=example,dc=com":            {"cn=dev,ou=groups,dc=example,dc=com"},
+		"cn=dev,ou=groups,dc=example,dc=com":   {"cn=staff,ou=groups,dc=example,dc=com"},
+		"cn=staff,ou=groups,dc=example,dc=com": {"cn=all,ou=groups,dc=example,dc=com"},
+		// cycle back to the staff group
+		"cn=all,ou=groups,dc=example,dc=com": {"cn=STAFF,ou=groups,dc=example,dc=com"},
+	}
+	type args struct {
+		conn searcher
+		user *ldap.Entry
+	}
+	tests := []struct {
+		name     string
+		resolver *groupResolver
+		args     args
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "memberOf attribute",
+			resolver: &groupResolver{attribute: "memberOf"},
+			args: args{
+				conn: dir,
+				user: ldap.NewEntry("cn=user,dc=example,dc=com", map[string][]string{"memberOf": {"cn=dev,ou=groups,dc=example,dc=com"}}),
+			},
+			want: []string{"cn=dev,ou=groups,dc=example,dc=com"},
+		},
+		{
+			name:     "group search",
+			resolver: &groupResolver{base: "ou=groups,dc=example,dc=com"},
+			args: args{
+				conn: dir,
+				user: ldap.NewEntry("cn=user,dc=example,dc=com", nil),
+			},
+			want: []string{"cn=dev,ou=groups,dc=example,dc=com"},
+		},
+		{
+			name:     "nested groups by attribute",
+			resolver: &groupResolver{attribute: "memberOf", nested: true},
+			args: args{
+				conn: dir,
+				user: ldap.NewEntry("cn=user,dc=example,dc=com", map[string][]string{"memberOf": {"cn=dev,ou=groups,dc=example,dc=com"}}),
+			},
+			want: []string{
+				"cn=dev,ou=groups,dc=example,dc=com",
+				"cn=staff,ou=groups,dc=example,dc=com",
+				"cn=all,ou=groups,dc=example,dc=com",
+			},
+		},
+		{
+			name:     "nested groups by search",
+			resolver: &groupResolver{base: "ou=groups,dc=example,dc=com", memberAttribute: "member", nested: true},
+			args: args{
+				conn: dir,
+				user: ldap.NewEntry("cn=user,dc=example,dc=com", nil),
+			},
+			want: []string{
+				"cn=dev,ou=groups,dc=example,dc=com",
+				"cn=staff,ou=groups,dc=example,dc=com",
+				"cn=all,ou=groups,dc=example,dc=com",
+			},
+		},
+		{
+			name:     "search error",
+			resolver: &groupResolver{base: "ou=groups,dc=example,dc=com"},
+			args: args{
+				conn: failingSearcher{},
+				user: ldap.NewEntry("cn=user,dc=example,dc=com", nil),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resolver.groups(tt.args.conn, tt.args.user)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProvider_groupResolver(t *testing.T) {
+	assert.Nil(t, New("ldap", nil, "", "", "", "", nil, nil, 0, "").groupResolver())
+	assert.Equal(t,
+		&groupResolver{attribute: "memberOf", base: "ou=groups", memberAttribute: "uniqueMember", nested: true},
+		New("ldap", nil, "", "", "", "", nil, nil, 0, "",
+			WithGroupsAttribute("memberOf"),
+			WithGroupSearch("ou=groups", "uniqueMember"),
+			WithNestedGroups(),
+		).groupResolver(),
+	)
+}
@@ -44,6 +44,11 @@ type Provider struct {
 	preferredLanguageAttribute string
 	avatarURLAttribute         string
 	profileAttribute           string
+
+	groupsAttribute      string
+	groupBase            string
+	groupMemberAttribute string
+	nestedGroups         bool
 }
 
 type ProviderOpts func(provider *Provider)
@@ -175,6 +180,30 @@ func WithProfileAttribute(name string) ProviderOpts {
 	}
 }
 
+// WithGroupsAttribute configures to read the groups of the user from the LDAP attribute (e.g. memberOf),
+// which has to contain the DNs of the groups
+func WithGroupsAttribute(name string) ProviderOpts {
+	return func(p *Provider) {
+		p.groupsAttribute = name
+	}
+}
+
+// WithGroupSearch configures to search the groups of the user below the groupBase,
+// where the memberAttribute (e.g. member or uniqueMember) contains the DN of the user
+func WithGroupSearch(groupBase, memberAttribute string) ProviderOpts {
+	return func(p *Provider) {
+		p.groupBase = groupBase
+		p.groupMemberAttribute = memberAttribute
+	}
+}
+
+// WithNestedGroups configures to also resolve the groups, the groups of the user are member of (recursively)
+func WithNestedGroups() ProviderOpts {
+	return func(p *Provider) {
+		p.nestedGroups = true
+	}
+}
+
 func New(
 	name string,
 	servers []string,
@@ -275,5 +304,13 @@ func (p *Provider) getNecessaryAttributes() []string {
 	if p.profileAttribute != "" {
 		attributes = append(attributes, p.profileAttribute)
 	}
+	if p.groupsAttribute != "" {
+		attributes = append(attributes, p.groupsAttribute)
+	}
 	return attributes
 }
+
+// syncsGroups returns if the groups of the user have to be resolved
+func (p *Provider) syncsGroups() bool {
+	return p.groupsAttribute != "" || p.groupBase != ""
+}
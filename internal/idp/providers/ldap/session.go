@@ -34,8 +34,9 @@ func (s *Session) GetAuthURL() string {
 
 func (s *Session) FetchUser(_ context.Context) (_ idp.User, err error) {
 	var user *ldap.Entry
+	var groups []string
 	for _, server := range s.Provider.servers {
-		user, err = tryBind(server,
+		user, groups, err = tryBind(server,
 			s.Provider.startTLS,
 			s.Provider.bindDN,
 			s.Provider.bindPassword,
@@ -44,7 +45,8 @@ func (s *Session) FetchUser(_ context.Context) (_ idp.User, err error) {
 			s.Provider.userObjectClasses,
 			s.Provider.userFilters,
 			s.User,
-			s.Password, s.Provider.timeout)
+			s.Password, s.Provider.timeout,
+			s.Provider.groupResolver())
 		// If there were invalid credentials or multiple users with the credentials cancel process
 		if err != nil && (errors.Is(err, ErrFailedLogin) || errors.Is(err, ErrNoSingleUser)) {
 			return nil, err
@@ -58,7 +60,7 @@ func (s *Session) FetchUser(_ context.Context) (_ idp.User, err error) {
 		return nil, err
 	}
 
-	return mapLDAPEntryToUser(
+	mappedUser, err := mapLDAPEntryToUser(
 		user,
 		s.Provider.idAttribute,
 		s.Provider.firstNameAttribute,
@@ -74,6 +76,11 @@ func (s *Session) FetchUser(_ context.Context) (_ idp.User, err error) {
 		s.Provider.avatarURLAttribute,
 		s.Provider.profileAttribute,
 	)
+	if err != nil {
+		return nil, err
+	}
+	mappedUser.groups = groups
+	return mappedUser, nil
 }
 
 func tryBind(
@@ -88,18 +95,19 @@ func tryBind(
 	username string,
 	password string,
 	timeout time.Duration,
-) (*ldap.Entry, error) {
+	groups *groupResolver,
+) (*ldap.Entry, []string, error) {
 	conn, err := getConnection(server, startTLS, timeout)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer conn.Close()
 
 	if err := conn.Bind(bindDN, bindPassword); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return trySearchAndUserBind(
+	user, err := trySearchAndUserBind(
 		conn,
 		baseDN,
 		attributes,
@@ -109,6 +117,18 @@ func tryBind(
 		password,
 		timeout,
 	)
+	if err != nil || groups == nil {
+		return user, nil, err
+	}
+	// the groups are searched with the bind user again, as the user itself might not be allowed to
+	if err := conn.Bind(bindDN, bindPassword); err != nil {
+		return nil, nil, err
+	}
+	userGroups, err := groups.groups(conn, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, userGroups, nil
 }
 
 func getConnection(
@@ -20,6 +20,7 @@ type User struct {
 	preferredLanguage language.Tag
 	avatarURL         string
 	profile           string
+	groups            []string
 }
 
 func NewUser(
@@ -51,6 +52,7 @@ func NewUser(
 		preferredLanguage,
 		avatarURL,
 		profile,
+		nil,
 	}
 }
 
@@ -93,3 +95,17 @@ func (u *User) GetAvatarURL() string {
 func (u *User) GetProfile() string {
 	return u.profile
 }
+
+// GetGroups returns the DNs of the groups the user is member of,
+// if the provider is configured to resolve them
+func (u *User) GetGroups() []string {
+	return u.groups
+}
+
+// GetClaims returns the groups of the user,
+// the other information is mapped by the attribute names of the provider
+func (u *User) GetClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"groups": u.groups,
+	}
+}
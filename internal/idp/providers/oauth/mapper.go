@@ -108,3 +108,8 @@ func (u *UserMapper) GetAvatarURL() string {
 func (u *UserMapper) GetProfile() string {
 	return ""
 }
+
+// GetClaims is an implementation of the [idp.ClaimsUser] interface.
+func (u *UserMapper) GetClaims() map[string]interface{} {
+	return u.RawInfo
+}
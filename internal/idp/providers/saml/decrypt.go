@@ -0,0 +1,158 @@
+package saml
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+const (
+	algorithmAES128CBC = "http://www.w3.org/2001/04/xmlenc#aes128-cbc"
+	algorithmAES192CBC = "http://www.w3.org/2001/04/xmlenc#aes192-cbc"
+	algorithmAES256CBC = "http://www.w3.org/2001/04/xmlenc#aes256-cbc"
+	algorithmAES128GCM = "http://www.w3.org/2009/xmlenc11#aes128-gcm"
+	algorithmAES192GCM = "http://www.w3.org/2009/xmlenc11#aes192-gcm"
+	algorithmAES256GCM = "http://www.w3.org/2009/xmlenc11#aes256-gcm"
+	algorithmRSAOAEP   = "http://www.w3.org/2001/04/xmlenc#rsa-oaep-mgf1p"
+	algorithmRSAOAEP11 = "http://www.w3.org/2009/xmlenc11#rsa-oaep"
+	algorithmRSA15     = "http://www.w3.org/2001/04/xmlenc#rsa-1_5"
+	algorithmSHA1      = "http://www.w3.org/2000/09/xmldsig#sha1"
+	algorithmSHA256    = "http://www.w3.org/2001/04/xmlenc#sha256"
+	algorithmSHA512    = "http://www.w3.org/2001/04/xmlenc#sha512"
+)
+
+var errUnsupportedAlgorithm = errors.New("unsupported encryption algorithm")
+
+// decryptElement decrypts an encrypted element (e.g. EncryptedAssertion) as defined by XML Encryption.
+// The symmetric key has to be encrypted with the public key of the service provider
+// and can either be part of the KeyInfo of the EncryptedData or a sibling of it.
+func decryptElement(key *rsa.PrivateKey, encrypted *etree.Element) (*etree.Element, error) {
+	encryptedData := encrypted.FindElement("./EncryptedData")
+	if encryptedData == nil {
+		return nil, errors.New("missing EncryptedData")
+	}
+	encryptedKey := encryptedData.FindElement("./KeyInfo/EncryptedKey")
+	if encryptedKey == nil {
+		encryptedKey = encrypted.FindElement("./EncryptedKey")
+	}
+	if encryptedKey == nil {
+		return nil, errors.New("missing EncryptedKey")
+	}
+	symmetricKey, err := decryptKey(key, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := cipherValue(encryptedData)
+	if err != nil {
+		return nil, err
+	}
+	plainText, err := decryptData(symmetricKey, encryptionMethod(encryptedData), cipherText)
+	if err != nil {
+		return nil, err
+	}
+	doc := etree.NewDocument()
+	if err = doc.ReadFromBytes(plainText); err != nil {
+		return nil, err
+	}
+	if doc.Root() == nil {
+		return nil, errors.New("empty EncryptedData")
+	}
+	return doc.Root(), nil
+}
+
+func decryptKey(key *rsa.PrivateKey, encryptedKey *etree.Element) ([]byte, error) {
+	cipherText, err := cipherValue(encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	switch algorithm := encryptionMethod(encryptedKey); algorithm {
+	case algorithmRSAOAEP, algorithmRSAOAEP11:
+		digest, err := digestMethod(encryptedKey)
+		if err != nil {
+			return nil, err
+		}
+		return rsa.DecryptOAEP(digest, rand.Reader, key, cipherText, nil)
+	case algorithmRSA15:
+		return rsa.DecryptPKCS1v15(rand.Reader, key, cipherText)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedAlgorithm, algorithm)
+	}
+}
+
+func decryptData(key []byte, algorithm string, cipherText []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	switch algorithm {
+	case algorithmAES128CBC, algorithmAES192CBC, algorithmAES256CBC:
+		if len(cipherText) < 2*aes.BlockSize || len(cipherText)%aes.BlockSize != 0 {
+			return nil, errors.New("invalid cipher text length")
+		}
+		iv, data := cipherText[:aes.BlockSize], cipherText[aes.BlockSize:]
+		plainText := make([]byte, len(data))
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(plainText, data)
+		// the padding of XML Encryption only defines the last byte (padding length), the others are arbitrary
+		padding := int(plainText[len(plainText)-1])
+		if padding == 0 || padding > aes.BlockSize {
+			return nil, errors.New("invalid padding")
+		}
+		return plainText[:len(plainText)-padding], nil
+	case algorithmAES128GCM, algorithmAES192GCM, algorithmAES256GCM:
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		if len(cipherText) < gcm.NonceSize() {
+			return nil, errors.New("invalid cipher text length")
+		}
+		return gcm.Open(nil, cipherText[:gcm.NonceSize()], cipherText[gcm.NonceSize():], nil)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedAlgorithm, algorithm)
+	}
+}
+
+func encryptionMethod(el *etree.Element) string {
+	method := el.FindElement("./EncryptionMethod")
+	if method == nil {
+		return ""
+	}
+	return method.SelectAttrValue("Algorithm", "")
+}
+
+// digestMethod returns the hash of the RSA-OAEP key transport, which defaults to SHA1
+func digestMethod(encryptedKey *etree.Element) (hash.Hash, error) {
+	method := encryptedKey.FindElement("./EncryptionMethod/DigestMethod")
+	if method == nil {
+		return sha1.New(), nil //nolint:gosec
+	}
+	switch algorithm := method.SelectAttrValue("Algorithm", ""); algorithm {
+	case algorithmSHA1:
+		return sha1.New(), nil //nolint:gosec
+	case algorithmSHA256:
+		return sha256.New(), nil
+	case algorithmSHA512:
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedAlgorithm, algorithm)
+	}
+}
+
+func cipherValue(el *etree.Element) ([]byte, error) {
+	value := el.FindElement("./CipherData/CipherValue")
+	if value == nil {
+		return nil, errors.New("missing CipherValue")
+	}
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value.Text()), ""))
+}
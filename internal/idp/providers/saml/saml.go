@@ -0,0 +1,413 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/zitadel/saml/pkg/provider/signature"
+	saml_xml "github.com/zitadel/saml/pkg/provider/xml"
+	"github.com/zitadel/saml/pkg/provider/xml/md"
+	"github.com/zitadel/saml/pkg/provider/xml/saml"
+	"github.com/zitadel/saml/pkg/provider/xml/samlp"
+	"github.com/zitadel/saml/pkg/provider/xml/xml_dsig"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/idp"
+)
+
+const (
+	queryRequest    = "SAMLRequest"
+	queryRelayState = "RelayState"
+	querySigAlg     = "SigAlg"
+	querySignature  = "Signature"
+
+	// requestIDPrefix makes sure the ID of the AuthnRequest is a valid xs:ID, which must not start with a digit
+	requestIDPrefix = "id-"
+
+	nameIDFormatUnspecified = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	protocolSAML2           = "urn:oasis:names:tc:SAML:2.0:protocol"
+	signatureAlgorithm      = dsig.RSASHA256SignatureMethod
+
+	// MetadataPath and ACSPath are the paths of the SAML endpoints of the service provider relative to its root URL
+	MetadataPath = "/metadata"
+	ACSPath      = "/acs"
+)
+
+var _ idp.Provider = (*Provider)(nil)
+
+var (
+	ErrMissingSSOService = errors.New("no single sign-on service with a supported binding found in the metadata")
+	ErrInvalidKey        = errors.New("invalid key or certificate")
+)
+
+// Provider is the [idp.Provider] implementation for a SAML 2.0 identity provider.
+// ZITADEL acts as service provider (SP) and initiates the authentication.
+type Provider struct {
+	name              string
+	entityID          string
+	acsURL            string
+	metadata          *md.EntityDescriptorType
+	certificate       []byte
+	key               *rsa.PrivateKey
+	binding           string
+	withSignedRequest bool
+
+	isLinkingAllowed  bool
+	isCreationAllowed bool
+	isAutoCreation    bool
+	isAutoUpdate      bool
+}
+
+type ProviderOpts func(provider *Provider)
+
+// WithLinkingAllowed allows end users to link the federated user to an existing one.
+func WithLinkingAllowed() ProviderOpts {
+	return func(p *Provider) {
+		p.isLinkingAllowed = true
+	}
+}
+
+// WithCreationAllowed allows end users to create a new user using the federated information.
+func WithCreationAllowed() ProviderOpts {
+	return func(p *Provider) {
+		p.isCreationAllowed = true
+	}
+}
+
+// WithAutoCreation enables that federated users are automatically created if not already existing.
+func WithAutoCreation() ProviderOpts {
+	return func(p *Provider) {
+		p.isAutoCreation = true
+	}
+}
+
+// WithAutoUpdate enables that information retrieved from the provider is automatically used to update
+// the existing user on each authentication.
+func WithAutoUpdate() ProviderOpts {
+	return func(p *Provider) {
+		p.isAutoUpdate = true
+	}
+}
+
+// WithBinding sets the binding (URN) used to send the AuthnRequest to the identity provider.
+// If not set or not supported by the identity provider, the first supported binding of the metadata is used.
+func WithBinding(binding string) ProviderOpts {
+	return func(p *Provider) {
+		p.binding = binding
+	}
+}
+
+// WithSignedRequest enables that the AuthnRequest is signed with the key of the service provider.
+func WithSignedRequest() ProviderOpts {
+	return func(p *Provider) {
+		p.withSignedRequest = true
+	}
+}
+
+// New creates a SAML provider using the metadata (XML) of the identity provider
+// and the PEM encoded certificate and private key of the service provider.
+// The entityID and acsURL identify the service provider and the endpoint the identity provider sends its response to.
+func New(name, entityID, acsURL string, metadata, certificate, key []byte, options ...ProviderOpts) (*Provider, error) {
+	entityDescriptor, err := saml_xml.ParseMetadataXmlIntoStruct(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if entityDescriptor.IDPSSODescriptor == nil {
+		return nil, ErrMissingSSOService
+	}
+	if block, _ := pem.Decode(key); block == nil {
+		return nil, ErrInvalidKey
+	}
+	privateKey, err := crypto.BytesToPrivateKey(key)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	provider := &Provider{
+		name:        name,
+		entityID:    entityID,
+		acsURL:      acsURL,
+		metadata:    entityDescriptor,
+		certificate: certificate,
+		key:         privateKey,
+	}
+	for _, option := range options {
+		option(provider)
+	}
+	if _, err = provider.ssoService(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// MetadataURL returns the URL of the metadata of the service provider, which is also used as its entity ID,
+// for the root URL of its SAML endpoints.
+func MetadataURL(rootURL string) string {
+	return rootURL + MetadataPath
+}
+
+// ACSURL returns the URL of the assertion consumer service for the root URL of the SAML endpoints.
+func ACSURL(rootURL string) string {
+	return rootURL + ACSPath
+}
+
+// Name implements the [idp.Provider] interface.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// BeginAuth implements the [idp.Provider] interface.
+// It will create a [Session] with an AuthnRequest for the single sign-on service of the identity provider.
+// The ID of the AuthnRequest is derived from the state,
+// so the response of the identity provider can be correlated without storing the request.
+func (p *Provider) BeginAuth(ctx context.Context, state string, _ ...any) (idp.Session, error) {
+	service, err := p.ssoService()
+	if err != nil {
+		return nil, err
+	}
+	request := p.authnRequest(RequestID(state), service)
+	if service.Binding == domain.SAMLBindingPostURN {
+		form, err := p.postForm(request, state)
+		if err != nil {
+			return nil, err
+		}
+		return &Session{Provider: p, RequestID: request.Id, AuthURL: service.Location, PostForm: form}, nil
+	}
+	authURL, err := p.redirectURL(request, state, service.Location)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{Provider: p, RequestID: request.Id, AuthURL: authURL}, nil
+}
+
+// IsLinkingAllowed implements the [idp.Provider] interface.
+func (p *Provider) IsLinkingAllowed() bool {
+	return p.isLinkingAllowed
+}
+
+// IsCreationAllowed implements the [idp.Provider] interface.
+func (p *Provider) IsCreationAllowed() bool {
+	return p.isCreationAllowed
+}
+
+// IsAutoCreation implements the [idp.Provider] interface.
+func (p *Provider) IsAutoCreation() bool {
+	return p.isAutoCreation
+}
+
+// IsAutoUpdate implements the [idp.Provider] interface.
+func (p *Provider) IsAutoUpdate() bool {
+	return p.isAutoUpdate
+}
+
+// EntityID returns the entity ID of the service provider.
+func (p *Provider) EntityID() string {
+	return p.entityID
+}
+
+// RequestID returns the ID of the AuthnRequest for the state (auth request ID).
+func RequestID(state string) string {
+	return requestIDPrefix + state
+}
+
+// Metadata returns the metadata (XML) of the service provider, which has to be registered on the identity provider.
+func (p *Provider) Metadata() ([]byte, error) {
+	certificate, err := p.certificateDER()
+	if err != nil {
+		return nil, err
+	}
+	keyInfo := func(use md.KeyTypes) md.KeyDescriptorType {
+		return md.KeyDescriptorType{
+			Use: use,
+			KeyInfo: xml_dsig.KeyInfoType{
+				X509Data: []xml_dsig.X509DataType{{X509Certificate: base64.StdEncoding.EncodeToString(certificate)}},
+			},
+		}
+	}
+	metadata := &md.EntityDescriptorType{
+		EntityID: md.EntityIDType(p.entityID),
+		SPSSODescriptor: &md.SPSSODescriptorType{
+			AuthnRequestsSigned:        boolString(p.withSignedRequest),
+			WantAssertionsSigned:       "true",
+			ProtocolSupportEnumeration: protocolSAML2,
+			KeyDescriptor: []md.KeyDescriptorType{
+				keyInfo(md.KeyTypesSigning),
+				keyInfo(md.KeyTypesEncryption),
+			},
+			NameIDFormat: []string{nameIDFormatUnspecified},
+			AssertionConsumerService: []md.IndexedEndpointType{
+				{
+					Index:     "0",
+					IsDefault: "true",
+					Binding:   domain.SAMLBindingPostURN,
+					Location:  p.acsURL,
+				},
+			},
+		},
+	}
+	data, err := saml_xml.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+// ssoService returns the single sign-on service of the identity provider for the configured binding
+// or the first supported one, if the configured binding is not set or not offered by the identity provider.
+func (p *Provider) ssoService() (md.EndpointType, error) {
+	var fallback *md.EndpointType
+	for i, service := range p.metadata.IDPSSODescriptor.SingleSignOnService {
+		if service.Binding != domain.SAMLBindingPostURN && service.Binding != domain.SAMLBindingRedirectURN {
+			continue
+		}
+		if service.Binding == p.binding {
+			return service, nil
+		}
+		if fallback == nil {
+			fallback = &p.metadata.IDPSSODescriptor.SingleSignOnService[i]
+		}
+	}
+	if fallback == nil {
+		return md.EndpointType{}, ErrMissingSSOService
+	}
+	return *fallback, nil
+}
+
+func (p *Provider) authnRequest(id string, service md.EndpointType) *samlp.AuthnRequestType {
+	return &samlp.AuthnRequestType{
+		Id:                          id,
+		Version:                     "2.0",
+		IssueInstant:                time.Now().UTC().Format(time.RFC3339),
+		Destination:                 service.Location,
+		ProtocolBinding:             domain.SAMLBindingPostURN,
+		AssertionConsumerServiceURL: p.acsURL,
+		Issuer: &saml.NameIDType{
+			Format: "urn:oasis:names:tc:SAML:2.0:nameid-format:entity",
+			Text:   p.entityID,
+		},
+		NameIDPolicy: &samlp.NameIDPolicyType{
+			AllowCreate: true,
+			Format:      nameIDFormatUnspecified,
+		},
+	}
+}
+
+// redirectURL returns the URL for the HTTP-Redirect binding,
+// where the (deflated) request and the signature are passed as query parameters
+func (p *Provider) redirectURL(request *samlp.AuthnRequestType, relayState, location string) (string, error) {
+	data, err := saml_xml.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	encoded, err := deflateAndBase64([]byte(data))
+	if err != nil {
+		return "", err
+	}
+	query := queryRequest + "=" + url.QueryEscape(encoded)
+	if relayState != "" {
+		query += "&" + queryRelayState + "=" + url.QueryEscape(relayState)
+	}
+	if p.withSignedRequest {
+		signingContext, err := p.signingContext()
+		if err != nil {
+			return "", err
+		}
+		query += "&" + querySigAlg + "=" + url.QueryEscape(signatureAlgorithm)
+		sig, err := signature.CreateRedirect(signingContext, query)
+		if err != nil {
+			return "", err
+		}
+		query += "&" + querySignature + "=" + url.QueryEscape(base64.StdEncoding.EncodeToString(sig))
+	}
+	separator := "?"
+	if strings.Contains(location, "?") {
+		separator = "&"
+	}
+	return location + separator + query, nil
+}
+
+// postForm returns the form values for the HTTP-POST binding,
+// where the signature is embedded into the request
+func (p *Provider) postForm(request *samlp.AuthnRequestType, relayState string) (url.Values, error) {
+	data, err := saml_xml.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	if p.withSignedRequest {
+		doc := etree.NewDocument()
+		if err = doc.ReadFromString(data); err != nil {
+			return nil, err
+		}
+		signingContext, err := p.signingContext()
+		if err != nil {
+			return nil, err
+		}
+		signed, err := signingContext.SignEnveloped(doc.Root())
+		if err != nil {
+			return nil, err
+		}
+		doc.SetRoot(signed)
+		if data, err = doc.WriteToString(); err != nil {
+			return nil, err
+		}
+	}
+	form := url.Values{
+		queryRequest: {base64.StdEncoding.EncodeToString([]byte(data))},
+	}
+	if relayState != "" {
+		form.Set(queryRelayState, relayState)
+	}
+	return form, nil
+}
+
+func (p *Provider) signingContext() (*dsig.SigningContext, error) {
+	certificate, err := p.certificateDER()
+	if err != nil {
+		return nil, err
+	}
+	tlsCert, err := signature.ParseTlsKeyPair(certificate, p.key)
+	if err != nil {
+		return nil, err
+	}
+	return signature.GetSigningContext(tlsCert, signatureAlgorithm)
+}
+
+func (p *Provider) certificateDER() ([]byte, error) {
+	block, _ := pem.Decode(p.certificate)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+	return block.Bytes, nil
+}
+
+func deflateAndBase64(data []byte) (string, error) {
+	var buf bytes.Buffer
+	writer, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err = writer.Write(data); err != nil {
+		return "", err
+	}
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
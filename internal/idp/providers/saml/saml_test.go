@@ -0,0 +1,205 @@
+package saml
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zitadel/saml/pkg/provider/signature"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+const (
+	testEntityID = "https://zitadel.cloud/idps/idp1/saml/metadata"
+	testACSURL   = "https://zitadel.cloud/idps/saml/acs"
+	testIDPID    = "https://idp.com/metadata"
+)
+
+type testKeyPair struct {
+	key         *rsa.PrivateKey
+	certificate []byte
+}
+
+func newTestKeyPair(t *testing.T) *testKeyPair {
+	key, _, certificate, err := crypto.GenerateCACertificate(2048, &crypto.CertificateInformations{
+		SerialNumber: big.NewInt(1),
+		CommonName:   "test",
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	})
+	require.NoError(t, err)
+	return &testKeyPair{key: key, certificate: certificate}
+}
+
+func (k *testKeyPair) certificateBase64(t *testing.T) string {
+	block, _ := pem.Decode(k.certificate)
+	require.NotNil(t, block)
+	return base64.StdEncoding.EncodeToString(block.Bytes)
+}
+
+func testMetadata(t *testing.T, idpKeys *testKeyPair, bindings ...string) []byte {
+	services := ""
+	for _, binding := range bindings {
+		services += fmt.Sprintf(`<md:SingleSignOnService Binding="%s" Location="https://idp.com/sso"/>`, binding)
+	}
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="%s">
+	<md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+		<md:KeyDescriptor use="signing">
+			<ds:KeyInfo><ds:X509Data><ds:X509Certificate>%s</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+		</md:KeyDescriptor>
+		%s
+	</md:IDPSSODescriptor>
+</md:EntityDescriptor>`, testIDPID, idpKeys.certificateBase64(t), services))
+}
+
+func newTestProvider(t *testing.T, idpKeys, spKeys *testKeyPair, bindings []string, options ...ProviderOpts) *Provider {
+	provider, err := New("saml", testEntityID, testACSURL,
+		testMetadata(t, idpKeys, bindings...),
+		spKeys.certificate,
+		crypto.PrivateKeyToBytes(spKeys.key),
+		options...,
+	)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestNew(t *testing.T) {
+	idpKeys := newTestKeyPair(t)
+	spKeys := newTestKeyPair(t)
+
+	_, err := New("saml", testEntityID, testACSURL, []byte("<invalid"), spKeys.certificate, crypto.PrivateKeyToBytes(spKeys.key))
+	assert.Error(t, err)
+
+	_, err = New("saml", testEntityID, testACSURL, testMetadata(t, idpKeys, "urn:oasis:names:tc:SAML:2.0:bindings:SOAP"), spKeys.certificate, crypto.PrivateKeyToBytes(spKeys.key))
+	assert.ErrorIs(t, err, ErrMissingSSOService)
+
+	_, err = New("saml", testEntityID, testACSURL, testMetadata(t, idpKeys, domain.SAMLBindingPostURN), spKeys.certificate, []byte("key"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	provider, err := New("saml", testEntityID, testACSURL, testMetadata(t, idpKeys, domain.SAMLBindingPostURN), spKeys.certificate, crypto.PrivateKeyToBytes(spKeys.key),
+		WithLinkingAllowed(),
+		WithCreationAllowed(),
+		WithAutoCreation(),
+		WithAutoUpdate(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "saml", provider.Name())
+	assert.Equal(t, testEntityID, provider.EntityID())
+	assert.True(t, provider.IsLinkingAllowed())
+	assert.True(t, provider.IsCreationAllowed())
+	assert.True(t, provider.IsAutoCreation())
+	assert.True(t, provider.IsAutoUpdate())
+}
+
+func TestProvider_BeginAuth(t *testing.T) {
+	idpKeys := newTestKeyPair(t)
+	spKeys := newTestKeyPair(t)
+
+	t.Run("redirect binding, signed", func(t *testing.T) {
+		provider := newTestProvider(t, idpKeys, spKeys,
+			[]string{domain.SAMLBindingPostURN, domain.SAMLBindingRedirectURN},
+			WithBinding(domain.SAMLBindingRedirectURN),
+			WithSignedRequest(),
+		)
+		session, err := provider.BeginAuth(context.Background(), "state")
+		require.NoError(t, err)
+		samlSession := session.(*Session)
+		assert.Equal(t, "id-state", samlSession.RequestID)
+		assert.Nil(t, samlSession.PostForm)
+
+		authURL, err := url.Parse(session.GetAuthURL())
+		require.NoError(t, err)
+		assert.Equal(t, "idp.com", authURL.Host)
+		query := authURL.Query()
+		assert.Equal(t, "state", query.Get(queryRelayState))
+		assert.Equal(t, signatureAlgorithm, query.Get(querySigAlg))
+
+		sig, err := base64.StdEncoding.DecodeString(query.Get(querySignature))
+		require.NoError(t, err)
+		signed := queryRequest + "=" + url.QueryEscape(query.Get(queryRequest)) +
+			"&" + queryRelayState + "=" + url.QueryEscape(query.Get(queryRelayState)) +
+			"&" + querySigAlg + "=" + url.QueryEscape(query.Get(querySigAlg))
+		assert.NoError(t, signature.ValidateRedirect(signatureAlgorithm, []byte(signed), sig, &spKeys.key.PublicKey))
+	})
+	t.Run("post binding, signed", func(t *testing.T) {
+		provider := newTestProvider(t, idpKeys, spKeys,
+			[]string{domain.SAMLBindingPostURN, domain.SAMLBindingRedirectURN},
+			WithBinding(domain.SAMLBindingPostURN),
+			WithSignedRequest(),
+		)
+		session, err := provider.BeginAuth(context.Background(), "state")
+		require.NoError(t, err)
+		samlSession := session.(*Session)
+		assert.Equal(t, "https://idp.com/sso", session.GetAuthURL())
+		assert.Equal(t, "state", samlSession.PostForm.Get(queryRelayState))
+
+		request, err := base64.StdEncoding.DecodeString(samlSession.PostForm.Get(queryRequest))
+		require.NoError(t, err)
+		doc := etree.NewDocument()
+		require.NoError(t, doc.ReadFromBytes(request))
+		assert.Equal(t, "id-state", doc.Root().SelectAttrValue("ID", ""))
+		assert.Equal(t, testACSURL, doc.Root().SelectAttrValue("AssertionConsumerServiceURL", ""))
+
+		spCertificate, err := x509.ParseCertificate(mustDecodePEM(t, spKeys.certificate))
+		require.NoError(t, err)
+		assert.NoError(t, signature.ValidatePost([]*x509.Certificate{spCertificate}, doc.Root()))
+	})
+	t.Run("binding not supported by idp, fallback", func(t *testing.T) {
+		provider := newTestProvider(t, idpKeys, spKeys,
+			[]string{domain.SAMLBindingRedirectURN},
+			WithBinding(domain.SAMLBindingPostURN),
+		)
+		session, err := provider.BeginAuth(context.Background(), "state")
+		require.NoError(t, err)
+		samlSession := session.(*Session)
+		assert.Nil(t, samlSession.PostForm)
+		authURL, err := url.Parse(session.GetAuthURL())
+		require.NoError(t, err)
+		assert.NotEmpty(t, authURL.Query().Get(queryRequest))
+		assert.Empty(t, authURL.Query().Get(querySignature))
+	})
+}
+
+func TestProvider_Metadata(t *testing.T) {
+	provider := newTestProvider(t, newTestKeyPair(t), newTestKeyPair(t), []string{domain.SAMLBindingPostURN}, WithSignedRequest())
+
+	metadata, err := provider.Metadata()
+	require.NoError(t, err)
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromBytes(metadata))
+	assert.Equal(t, testEntityID, doc.Root().SelectAttrValue("entityID", ""))
+	descriptor := doc.Root().FindElement("./SPSSODescriptor")
+	require.NotNil(t, descriptor)
+	assert.Equal(t, "true", descriptor.SelectAttrValue("AuthnRequestsSigned", ""))
+	assert.Len(t, descriptor.SelectElements("KeyDescriptor"), 2)
+	acs := descriptor.FindElement("./AssertionConsumerService")
+	require.NotNil(t, acs)
+	assert.Equal(t, testACSURL, acs.SelectAttrValue("Location", ""))
+	assert.Equal(t, domain.SAMLBindingPostURN, acs.SelectAttrValue("Binding", ""))
+}
+
+func mustDecodePEM(t *testing.T, data []byte) []byte {
+	block, _ := pem.Decode(data)
+	require.NotNil(t, block)
+	return block.Bytes
+}
+
+func mustTLSCertificate(t *testing.T, keys *testKeyPair) tls.Certificate {
+	certificate, err := signature.ParseTlsKeyPair(mustDecodePEM(t, keys.certificate), keys.key)
+	require.NoError(t, err)
+	return certificate
+}
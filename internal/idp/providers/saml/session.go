@@ -0,0 +1,251 @@
+package saml
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+	"github.com/zitadel/saml/pkg/provider/signature"
+	saml_xml "github.com/zitadel/saml/pkg/provider/xml"
+	"github.com/zitadel/saml/pkg/provider/xml/saml"
+	"github.com/zitadel/saml/pkg/provider/xml/samlp"
+
+	"github.com/zitadel/zitadel/internal/idp"
+)
+
+const (
+	statusSuccess = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	// maxClockSkew is the tolerated difference between the clocks of the identity provider and ZITADEL
+	maxClockSkew = 3 * time.Minute
+)
+
+var _ idp.Session = (*Session)(nil)
+
+var (
+	ErrNoResponse       = errors.New("no SAML response provided")
+	ErrInvalidResponse  = errors.New("invalid SAML response")
+	ErrResponseStatus   = errors.New("authentication failed on the identity provider")
+	ErrMissingSignature = errors.New("neither the response nor the assertion is signed")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrInvalidAssertion = errors.New("invalid assertion")
+)
+
+// Session is the [idp.Session] implementation for the SAML provider.
+type Session struct {
+	*Provider
+	// AuthURL is the URL of the single sign-on service,
+	// including the request for the HTTP-Redirect binding
+	AuthURL string
+	// PostForm is set for the HTTP-POST binding and has to be posted to the AuthURL
+	PostForm url.Values
+	// RequestID is the ID of the AuthnRequest the response has to be issued for
+	RequestID string
+	// Response is the (base64 encoded) SAMLResponse sent to the assertion consumer service
+	Response  string
+	Assertion *saml.AssertionType
+}
+
+// GetAuthURL implements the [idp.Session] interface.
+func (s *Session) GetAuthURL() string {
+	return s.AuthURL
+}
+
+// FetchUser implements the [idp.Session] interface.
+// It will validate the received SAMLResponse, decrypt the assertion if needed
+// and map the subject and attributes of the assertion into an [idp.User].
+func (s *Session) FetchUser(_ context.Context) (_ idp.User, err error) {
+	if s.Response == "" {
+		return nil, ErrNoResponse
+	}
+	s.Assertion, err = s.Provider.parseResponse(s.Response, s.RequestID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return NewUser(s.Assertion), nil
+}
+
+// parseResponse validates the SAMLResponse and returns its (decrypted) assertion.
+// Either the response or the assertion must be signed by the identity provider.
+func (p *Provider) parseResponse(encoded, requestID string, now time.Time) (*saml.AssertionType, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	doc := etree.NewDocument()
+	if err = doc.ReadFromBytes(data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	root := doc.Root()
+	if root == nil || root.Tag != "Response" || root.NamespaceURI() != protocolSAML2 {
+		return nil, ErrInvalidResponse
+	}
+	response := new(samlp.ResponseType)
+	if err = xml.Unmarshal(data, response); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if response.Status.StatusCode.Value != statusSuccess {
+		return nil, fmt.Errorf("%w: %s %s", ErrResponseStatus, response.Status.StatusCode.Value, response.Status.StatusMessage)
+	}
+	if response.InResponseTo != requestID {
+		return nil, fmt.Errorf("%w: unexpected InResponseTo", ErrInvalidResponse)
+	}
+	if response.Destination != "" && response.Destination != p.acsURL {
+		return nil, fmt.Errorf("%w: unexpected Destination", ErrInvalidResponse)
+	}
+	if response.Issuer != nil && response.Issuer.Text != "" && response.Issuer.Text != string(p.metadata.EntityID) {
+		return nil, fmt.Errorf("%w: unexpected Issuer", ErrInvalidResponse)
+	}
+
+	certificates, err := signature.ParseCertificates(saml_xml.GetCertsFromKeyDescriptors(p.metadata.IDPSSODescriptor.KeyDescriptor))
+	if err != nil {
+		return nil, err
+	}
+	responseSigned := root.FindElement("./Signature") != nil
+	if responseSigned {
+		if err = signature.ValidatePost(certificates, root); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+	}
+
+	assertionElement, err := p.assertionElement(root)
+	if err != nil {
+		return nil, err
+	}
+	if assertionElement.FindElement("./Signature") != nil {
+		if err = signature.ValidatePost(certificates, assertionElement); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+	} else if !responseSigned {
+		return nil, ErrMissingSignature
+	}
+
+	assertion, err := unmarshalAssertion(assertionElement)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.validateAssertion(assertion, requestID, now); err != nil {
+		return nil, err
+	}
+	return assertion, nil
+}
+
+// assertionElement returns the single (plain or encrypted) assertion of the response
+func (p *Provider) assertionElement(response *etree.Element) (*etree.Element, error) {
+	assertions := response.SelectElements("Assertion")
+	encryptedAssertions := response.SelectElements("EncryptedAssertion")
+	if len(assertions)+len(encryptedAssertions) != 1 {
+		return nil, fmt.Errorf("%w: response must contain exactly one assertion", ErrInvalidResponse)
+	}
+	if len(assertions) == 1 {
+		return assertions[0], nil
+	}
+	assertion, err := decryptElement(p.key, encryptedAssertions[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAssertion, err)
+	}
+	if assertion.Tag != "Assertion" {
+		return nil, ErrInvalidAssertion
+	}
+	return assertion, nil
+}
+
+// unmarshalAssertion detaches the element from the response (including the namespace declarations of its parents)
+// and unmarshals it into the [saml.AssertionType]
+func unmarshalAssertion(el *etree.Element) (*saml.AssertionType, error) {
+	ctx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = ctx.SubContext(el); err != nil {
+		return nil, err
+	}
+	detached, err := etreeutils.NSDetatch(ctx, el)
+	if err != nil {
+		return nil, err
+	}
+	doc := etree.NewDocument()
+	doc.SetRoot(detached)
+	data, err := doc.WriteToBytes()
+	if err != nil {
+		return nil, err
+	}
+	assertion := new(saml.AssertionType)
+	if err = xml.Unmarshal(data, assertion); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAssertion, err)
+	}
+	return assertion, nil
+}
+
+// validateAssertion checks the issuer, the conditions and the subject confirmation of the assertion
+func (p *Provider) validateAssertion(assertion *saml.AssertionType, requestID string, now time.Time) error {
+	if assertion.Issuer.Text != string(p.metadata.EntityID) {
+		return fmt.Errorf("%w: unexpected Issuer", ErrInvalidAssertion)
+	}
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || assertion.Subject.NameID.Text == "" {
+		return fmt.Errorf("%w: missing NameID", ErrInvalidAssertion)
+	}
+	if conditions := assertion.Conditions; conditions != nil {
+		if err := checkTimeRange(conditions.NotBefore, conditions.NotOnOrAfter, now); err != nil {
+			return err
+		}
+		for _, restriction := range conditions.AudienceRestriction {
+			if !containsAudience(restriction.Audience, p.entityID) {
+				return fmt.Errorf("%w: unexpected Audience", ErrInvalidAssertion)
+			}
+		}
+	}
+	for _, confirmation := range assertion.Subject.SubjectConfirmation {
+		data := confirmation.SubjectConfirmationData
+		if data == nil {
+			continue
+		}
+		if data.InResponseTo != "" && data.InResponseTo != requestID {
+			return fmt.Errorf("%w: unexpected InResponseTo", ErrInvalidAssertion)
+		}
+		if data.Recipient != "" && data.Recipient != p.acsURL {
+			return fmt.Errorf("%w: unexpected Recipient", ErrInvalidAssertion)
+		}
+		if err := checkTimeRange(data.NotBefore, data.NotOnOrAfter, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkTimeRange(notBefore, notOnOrAfter string, now time.Time) error {
+	if notBefore != "" {
+		start, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAssertion, err)
+		}
+		if now.Add(maxClockSkew).Before(start) {
+			return fmt.Errorf("%w: not yet valid", ErrInvalidAssertion)
+		}
+	}
+	if notOnOrAfter != "" {
+		end, err := time.Parse(time.RFC3339, notOnOrAfter)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAssertion, err)
+		}
+		if !now.Add(-maxClockSkew).Before(end) {
+			return fmt.Errorf("%w: expired", ErrInvalidAssertion)
+		}
+	}
+	return nil
+}
+
+func containsAudience(audiences []string, entityID string) bool {
+	for _, audience := range audiences {
+		if strings.TrimSpace(audience) == entityID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+package saml
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zitadel/saml/pkg/provider/signature"
+
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+type testResponse struct {
+	inResponseTo string
+	status       string
+	audience     string
+	notOnOrAfter time.Time
+	signResponse bool
+	signAssert   bool
+	encrypt      bool
+	tamper       bool
+}
+
+func (r testResponse) assertion() string {
+	return fmt.Sprintf(`<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="assertion1" Version="2.0" IssueInstant="%[1]s">
+	<saml:Issuer>%[2]s</saml:Issuer>
+	<saml:Subject>
+		<saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified">user1</saml:NameID>
+		<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">
+			<saml:SubjectConfirmationData InResponseTo="%[3]s" Recipient="%[4]s" NotOnOrAfter="%[5]s"/>
+		</saml:SubjectConfirmation>
+	</saml:Subject>
+	<saml:Conditions NotBefore="%[1]s" NotOnOrAfter="%[5]s">
+		<saml:AudienceRestriction><saml:Audience>%[6]s</saml:Audience></saml:AudienceRestriction>
+	</saml:Conditions>
+	<saml:AttributeStatement>
+		<saml:Attribute Name="urn:oid:0.9.2342.19200300.100.1.3" FriendlyName="mail"><saml:AttributeValue>user@idp.com</saml:AttributeValue></saml:Attribute>
+		<saml:Attribute Name="givenName"><saml:AttributeValue>first</saml:AttributeValue></saml:Attribute>
+		<saml:Attribute Name="sn"><saml:AttributeValue>last</saml:AttributeValue></saml:Attribute>
+		<saml:Attribute Name="groups"><saml:AttributeValue>admins</saml:AttributeValue><saml:AttributeValue>users</saml:AttributeValue></saml:Attribute>
+	</saml:AttributeStatement>
+</saml:Assertion>`,
+		time.Now().UTC().Add(-time.Minute).Format(time.RFC3339),
+		testIDPID,
+		r.inResponseTo,
+		testACSURL,
+		r.notOnOrAfter.UTC().Format(time.RFC3339),
+		r.audience,
+	)
+}
+
+func (r testResponse) encode(t *testing.T, idpKeys, spKeys *testKeyPair) string {
+	signingContext, err := signature.GetSigningContext(mustTLSCertificate(t, idpKeys), signatureAlgorithm)
+	require.NoError(t, err)
+
+	assertionDoc := etree.NewDocument()
+	require.NoError(t, assertionDoc.ReadFromString(r.assertion()))
+	assertion := assertionDoc.Root()
+	if r.signAssert {
+		assertion, err = signingContext.SignEnveloped(assertion)
+		require.NoError(t, err)
+	}
+	if r.encrypt {
+		assertionDoc.SetRoot(assertion)
+		plain, err := assertionDoc.WriteToBytes()
+		require.NoError(t, err)
+		assertion = encryptAssertion(t, &spKeys.key.PublicKey, plain)
+	}
+
+	responseDoc := etree.NewDocument()
+	require.NoError(t, responseDoc.ReadFromString(fmt.Sprintf(`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="response1" Version="2.0" IssueInstant="%s" Destination="%s" InResponseTo="%s">
+	<saml:Issuer>%s</saml:Issuer>
+	<samlp:Status><samlp:StatusCode Value="%s"/></samlp:Status>
+</samlp:Response>`,
+		time.Now().UTC().Format(time.RFC3339),
+		testACSURL,
+		r.inResponseTo,
+		testIDPID,
+		r.status,
+	)))
+	response := responseDoc.Root()
+	response.AddChild(assertion)
+	if r.signResponse {
+		response, err = signingContext.SignEnveloped(response)
+		require.NoError(t, err)
+		responseDoc.SetRoot(response)
+	}
+	data, err := responseDoc.WriteToString()
+	require.NoError(t, err)
+	if r.tamper {
+		data = strings.Replace(data, "user@idp.com", "admin@idp.com", 1)
+	}
+	return base64.StdEncoding.EncodeToString([]byte(data))
+}
+
+func encryptAssertion(t *testing.T, publicKey *rsa.PublicKey, plain []byte) *etree.Element {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+	cipherText := gcm.Seal(nonce, nonce, plain, nil)
+	encryptedKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, publicKey, key, nil) //nolint:gosec
+	require.NoError(t, err)
+
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromString(fmt.Sprintf(`<saml:EncryptedAssertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">
+	<xenc:EncryptedData xmlns:xenc="http://www.w3.org/2001/04/xmlenc#" Type="http://www.w3.org/2001/04/xmlenc#Element">
+		<xenc:EncryptionMethod Algorithm="%s"/>
+		<ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+			<xenc:EncryptedKey>
+				<xenc:EncryptionMethod Algorithm="%s"/>
+				<xenc:CipherData><xenc:CipherValue>%s</xenc:CipherValue></xenc:CipherData>
+			</xenc:EncryptedKey>
+		</ds:KeyInfo>
+		<xenc:CipherData><xenc:CipherValue>%s</xenc:CipherValue></xenc:CipherData>
+	</xenc:EncryptedData>
+</saml:EncryptedAssertion>`,
+		algorithmAES256GCM,
+		algorithmRSAOAEP,
+		base64.StdEncoding.EncodeToString(encryptedKey),
+		base64.StdEncoding.EncodeToString(cipherText),
+	)))
+	return doc.Root()
+}
+
+func TestSession_FetchUser(t *testing.T) {
+	idpKeys := newTestKeyPair(t)
+	spKeys := newTestKeyPair(t)
+	valid := testResponse{
+		inResponseTo: RequestID("state"),
+		status:       statusSuccess,
+		audience:     testEntityID,
+		notOnOrAfter: time.Now().Add(5 * time.Minute),
+		signAssert:   true,
+	}
+	tests := []struct {
+		name     string
+		response func() string
+		wantErr  error
+	}{
+		{
+			name:     "no response, error",
+			response: func() string { return "" },
+			wantErr:  ErrNoResponse,
+		},
+		{
+			name:     "invalid response, error",
+			response: func() string { return base64.StdEncoding.EncodeToString([]byte("<invalid/>")) },
+			wantErr:  ErrInvalidResponse,
+		},
+		{
+			name: "status not success, error",
+			response: func() string {
+				r := valid
+				r.status = "urn:oasis:names:tc:SAML:2.0:status:Requester"
+				return r.encode(t, idpKeys, spKeys)
+			},
+			wantErr: ErrResponseStatus,
+		},
+		{
+			name: "other request, error",
+			response: func() string {
+				r := valid
+				r.inResponseTo = RequestID("other")
+				return r.encode(t, idpKeys, spKeys)
+			},
+			wantErr: ErrInvalidResponse,
+		},
+		{
+			name: "not signed, error",
+			response: func() string {
+				r := valid
+				r.signAssert = false
+				return r.encode(t, idpKeys, spKeys)
+			},
+			wantErr: ErrMissingSignature,
+		},
+		{
+			name: "tampered assertion, error",
+			response: func() string {
+				r := valid
+				r.tamper = true
+				return r.encode(t, idpKeys, spKeys)
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "signed by other key, error",
+			response: func() string {
+				return valid.encode(t, newTestKeyPair(t), spKeys)
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "other audience, error",
+			response: func() string {
+				r := valid
+				r.audience = "https://other.com"
+				return r.encode(t, idpKeys, spKeys)
+			},
+			wantErr: ErrInvalidAssertion,
+		},
+		{
+			name: "expired, error",
+			response: func() string {
+				r := valid
+				r.notOnOrAfter = time.Now().Add(-time.Hour)
+				return r.encode(t, idpKeys, spKeys)
+			},
+			wantErr: ErrInvalidAssertion,
+		},
+		{
+			name: "signed assertion, ok",
+			response: func() string {
+				return valid.encode(t, idpKeys, spKeys)
+			},
+		},
+		{
+			name: "signed response, ok",
+			response: func() string {
+				r := valid
+				r.signAssert = false
+				r.signResponse = true
+				return r.encode(t, idpKeys, spKeys)
+			},
+		},
+		{
+			name: "encrypted signed assertion, ok",
+			response: func() string {
+				r := valid
+				r.encrypt = true
+				return r.encode(t, idpKeys, spKeys)
+			},
+		},
+		{
+			name: "encrypted for other key, error",
+			response: func() string {
+				r := valid
+				r.encrypt = true
+				return r.encode(t, idpKeys, newTestKeyPair(t))
+			},
+			wantErr: ErrInvalidAssertion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestProvider(t, idpKeys, spKeys, []string{domain.SAMLBindingPostURN})
+			session := &Session{
+				Provider:  provider,
+				RequestID: RequestID("state"),
+				Response:  tt.response(),
+			}
+			user, err := session.FetchUser(context.Background())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user1", user.GetID())
+			assert.Equal(t, "first", user.GetFirstName())
+			assert.Equal(t, "last", user.GetLastName())
+			assert.Equal(t, "user1", user.GetPreferredUsername())
+			assert.Equal(t, domain.EmailAddress("user@idp.com"), user.GetEmail())
+			assert.Equal(t, []string{"admins", "users"}, user.(*User).Attributes["groups"])
+			assert.NotNil(t, session.Assertion)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package saml
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/zitadel/saml/pkg/provider/xml/saml"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+// well-known attribute names (friendly names, LDAP OIDs and WS-Federation claims) of the user information
+var (
+	firstNameAttributes = []string{
+		"givenName", "firstName", "first_name",
+		"urn:oid:2.5.4.42",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/givenname",
+	}
+	lastNameAttributes = []string{
+		"sn", "surname", "lastName", "last_name",
+		"urn:oid:2.5.4.4",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/surname",
+	}
+	displayNameAttributes = []string{
+		"displayName", "name", "cn",
+		"urn:oid:2.16.840.1.113730.3.1.241",
+		"urn:oid:2.5.4.3",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name",
+	}
+	nickNameAttributes = []string{
+		"nickname", "nickName",
+	}
+	preferredUsernameAttributes = []string{
+		"uid", "username", "preferred_username",
+		"urn:oid:0.9.2342.19200300.100.1.1",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn",
+	}
+	emailAttributes = []string{
+		"email", "mail", "emailAddress",
+		"urn:oid:0.9.2342.19200300.100.1.3",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress",
+	}
+	emailVerifiedAttributes = []string{
+		"email_verified", "emailVerified",
+	}
+	phoneAttributes = []string{
+		"telephoneNumber", "phone", "mobile",
+		"urn:oid:2.5.4.20",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/mobilephone",
+	}
+	phoneVerifiedAttributes = []string{
+		"phone_verified", "phoneVerified",
+	}
+	preferredLanguageAttributes = []string{
+		"preferredLanguage", "locale", "language",
+		"urn:oid:2.16.840.1.113730.3.1.39",
+	}
+	avatarURLAttributes = []string{
+		"picture", "avatar",
+	}
+	profileAttributes = []string{
+		"profile", "website",
+	}
+)
+
+// User is the [idp.User] of a SAML assertion.
+// The ID is the NameID of the subject, the other information is taken from the attributes using well-known names.
+type User struct {
+	ID         string
+	Attributes map[string][]string
+}
+
+func NewUser(assertion *saml.AssertionType) *User {
+	user := &User{
+		Attributes: make(map[string][]string),
+	}
+	if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		user.ID = strings.TrimSpace(assertion.Subject.NameID.Text)
+	}
+	for _, statement := range assertion.AttributeStatement {
+		for _, attribute := range statement.Attribute {
+			if attribute == nil {
+				continue
+			}
+			values := make([]string, 0, len(attribute.AttributeValue))
+			for _, value := range attribute.AttributeValue {
+				values = append(values, strings.TrimSpace(value))
+			}
+			user.Attributes[attribute.Name] = append(user.Attributes[attribute.Name], values...)
+			if attribute.FriendlyName != "" && attribute.FriendlyName != attribute.Name {
+				user.Attributes[attribute.FriendlyName] = append(user.Attributes[attribute.FriendlyName], values...)
+			}
+		}
+	}
+	return user
+}
+
+// attribute returns the first value of the first attribute present
+func (u *User) attribute(names []string) string {
+	for _, name := range names {
+		for _, value := range u.Attributes[name] {
+			if value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+func (u *User) GetID() string {
+	return u.ID
+}
+
+func (u *User) GetFirstName() string {
+	return u.attribute(firstNameAttributes)
+}
+
+func (u *User) GetLastName() string {
+	return u.attribute(lastNameAttributes)
+}
+
+func (u *User) GetDisplayName() string {
+	return u.attribute(displayNameAttributes)
+}
+
+func (u *User) GetNickname() string {
+	return u.attribute(nickNameAttributes)
+}
+
+func (u *User) GetPreferredUsername() string {
+	if username := u.attribute(preferredUsernameAttributes); username != "" {
+		return username
+	}
+	return u.ID
+}
+
+func (u *User) GetEmail() domain.EmailAddress {
+	return domain.EmailAddress(u.attribute(emailAttributes))
+}
+
+func (u *User) IsEmailVerified() bool {
+	verified, _ := strconv.ParseBool(u.attribute(emailVerifiedAttributes))
+	return verified
+}
+
+func (u *User) GetPhone() domain.PhoneNumber {
+	return domain.PhoneNumber(u.attribute(phoneAttributes))
+}
+
+func (u *User) IsPhoneVerified() bool {
+	verified, _ := strconv.ParseBool(u.attribute(phoneVerifiedAttributes))
+	return verified
+}
+
+func (u *User) GetPreferredLanguage() language.Tag {
+	return language.Make(u.attribute(preferredLanguageAttributes))
+}
+
+func (u *User) GetAvatarURL() string {
+	return u.attribute(avatarURLAttributes)
+}
+
+func (u *User) GetProfile() string {
+	return u.attribute(profileAttributes)
+}
+
+// GetClaims returns all attributes (by name and friendly name) with their values
+func (u *User) GetClaims() map[string]interface{} {
+	claims := make(map[string]interface{}, len(u.Attributes))
+	for name, values := range u.Attributes {
+		claims[name] = values
+	}
+	return claims
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+const (
+	// DeliveryIDHeader identifies a delivery, it stays the same for retries
+	// so receivers are able to deduplicate at-least-once deliveries
+	DeliveryIDHeader = "x-zitadel-delivery-id"
+)
+
+type RetryConfig struct {
+	// MaxAttempts defines how often a delivery is tried before it fails, values below 1 are handled as 1
+	MaxAttempts uint
+	// InitialBackoff is the delay before the first retry, it doubles for each following retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts
+	MaxBackoff time.Duration
+}
+
+// Backoff returns the delay before the given attempt (starting at 1)
+func (c *RetryConfig) Backoff(attempt uint) time.Duration {
+	if attempt < 2 || c.InitialBackoff <= 0 {
+		return 0
+	}
+	backoff := c.InitialBackoff
+	for i := uint(2); i < attempt && (c.MaxBackoff <= 0 || backoff < c.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if c.MaxBackoff > 0 && backoff > c.MaxBackoff {
+		return c.MaxBackoff
+	}
+	return backoff
+}
+
+// Delivery sends signed payloads to a single endpoint
+type Delivery struct {
+	Config
+	Retry      RetryConfig
+	SigningKey []byte
+	Client     *http.Client
+	// now is used to sign the payloads and makes time.Now mockable
+	now func() time.Time
+}
+
+func NewDelivery(cfg Config, retry RetryConfig, signingKey []byte, timeout time.Duration) *Delivery {
+	return &Delivery{
+		Config:     cfg,
+		Retry:      retry,
+		SigningKey: signingKey,
+		Client:     &http.Client{Timeout: timeout},
+		now:        time.Now,
+	}
+}
+
+// Deliver sends the payload until the endpoint returns a success status or the attempts are exhausted
+// the delays between the attempts grow exponentially
+func (d *Delivery) Deliver(ctx context.Context, deliveryID string, payload []byte) (err error) {
+	attempts := d.Retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	for attempt := uint(1); attempt <= attempts; attempt++ {
+		if backoff := d.Retry.Backoff(attempt); backoff > 0 {
+			select {
+			case <-ctx.Done():
+				return errors.ThrowDeadlineExceeded(ctx.Err(), "WEBH-ohW2u", "delivery canceled")
+			case <-time.After(backoff):
+			}
+		}
+		if err = d.send(ctx, deliveryID, payload); err == nil {
+			return nil
+		}
+		logging.WithFields("calling_url", d.CallURL, "delivery", deliveryID, "attempt", attempt).WithError(err).Debug("delivery attempt failed")
+	}
+	return err
+}
+
+func (d *Delivery) send(ctx context.Context, deliveryID string, payload []byte) error {
+	sender := &Sender{Client: d.Client, now: d.now}
+	return sender.Send(ctx, d.Config, d.SigningKey, deliveryID, payload)
+}
+
+// Sender sends signed payloads to varying endpoints,
+// all sends share the client, so the connections to the endpoints are reused
+type Sender struct {
+	Client *http.Client
+	// now is used to sign the payloads and makes time.Now mockable
+	now func() time.Time
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		Client: &http.Client{Timeout: timeout},
+		now:    time.Now,
+	}
+}
+
+// Send calls the endpoint of the config once with the payload signed by the signing key
+func (s *Sender) Send(ctx context.Context, cfg Config, signingKey []byte, deliveryID string, payload []byte) error {
+	method := cfg.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, cfg.CallURL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.ThrowInternal(err, "WEBH-Ui3ah", "unable to create request")
+	}
+	for key, values := range cfg.Headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryIDHeader, deliveryID)
+	if len(signingKey) > 0 {
+		req.Header.Set(SignatureHeader, Sign(signingKey, s.now(), payload))
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return errors.ThrowUnavailable(err, "WEBH-Ooqu7", "unable to call webhook")
+	}
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if err = resp.Body.Close(); err != nil {
+		return errors.ThrowInternal(err, "WEBH-Ahth2", "unable to close response body")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.ThrowUnknown(fmt.Errorf("calling url %s returned %s", cfg.CallURL, resp.Status), "WEBH-ieL4o", "webhook didn't return a success status")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryConfig_Backoff(t *testing.T) {
+	cfg := &RetryConfig{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+	}
+	for attempt, want := range map[uint]time.Duration{
+		1: 0,
+		2: time.Second,
+		3: 2 * time.Second,
+		4: 4 * time.Second,
+		5: 5 * time.Second,
+		9: 5 * time.Second,
+	} {
+		if got := cfg.Backoff(attempt); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestDelivery_Deliver(t *testing.T) {
+	key := []byte("key")
+	payload := []byte(`{"sequence":1}`)
+	tests := []struct {
+		name         string
+		failAttempts int
+		maxAttempts  uint
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "first attempt succeeds",
+			maxAttempts:  3,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retry succeeds",
+			failAttempts: 2,
+			maxAttempts:  3,
+			wantAttempts: 3,
+		},
+		{
+			name:         "attempts exhausted",
+			failAttempts: 5,
+			maxAttempts:  3,
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if r.Header.Get(DeliveryIDHeader) != "instance:1" {
+					t.Errorf("unexpected delivery id %q", r.Header.Get(DeliveryIDHeader))
+				}
+				body := make([]byte, len(payload))
+				_, _ = r.Body.Read(body)
+				if err := VerifySignature(key, r.Header.Get(SignatureHeader), body, time.Minute); err != nil {
+					t.Errorf("invalid signature: %v", err)
+				}
+				if attempts <= tt.failAttempts {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			d := NewDelivery(
+				Config{CallURL: server.URL},
+				RetryConfig{MaxAttempts: tt.maxAttempts, InitialBackoff: time.Millisecond},
+				key,
+				time.Second,
+			)
+			err := d.Deliver(context.Background(), "instance:1", payload)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Deliver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, attempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/errors"
+)
+
+const (
+	// SignatureHeader contains the timestamp and the HMAC-SHA256 signature of a delivered payload
+	// formatted as `t=<unix seconds>,v1=<hex encoded signature>`
+	SignatureHeader = "x-zitadel-signature"
+
+	signatureTimestampKey = "t"
+	signatureV1Key        = "v1"
+)
+
+// Sign creates the value of the SignatureHeader
+// the signature is created over the timestamp and the payload separated by a dot
+// so receivers are able to reject replayed deliveries
+func Sign(key []byte, timestamp time.Time, payload []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return signatureTimestampKey + "=" + unix + "," + signatureV1Key + "=" + computeSignature(key, unix, payload)
+}
+
+// VerifySignature checks the value of the SignatureHeader against the payload
+// and rejects signatures older than the tolerance
+func VerifySignature(key []byte, header string, payload []byte, tolerance time.Duration) error {
+	var unix, signature string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case signatureTimestampKey:
+			unix = v
+		case signatureV1Key:
+			signature = v
+		}
+	}
+	timestamp, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || signature == "" {
+		return errors.ThrowInvalidArgument(err, "WEBH-Eeph4", "invalid signature header")
+	}
+	if tolerance > 0 && time.Since(time.Unix(timestamp, 0)) > tolerance {
+		return errors.ThrowInvalidArgument(nil, "WEBH-ahG3u", "signature expired")
+	}
+	if !hmac.Equal([]byte(signature), []byte(computeSignature(key, unix, payload))) {
+		return errors.ThrowInvalidArgument(nil, "WEBH-Xee5k", "invalid signature")
+	}
+	return nil
+}
+
+func computeSignature(key []byte, unix string, payload []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
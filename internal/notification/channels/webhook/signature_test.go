@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	key := []byte("key")
+	payload := []byte(`{"sequence":1}`)
+	type args struct {
+		key       []byte
+		header    string
+		payload   []byte
+		tolerance time.Duration
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			args: args{
+				key:     key,
+				header:  Sign(key, time.Now(), payload),
+				payload: payload,
+			},
+		},
+		{
+			name: "within tolerance",
+			args: args{
+				key:       key,
+				header:    Sign(key, time.Now().Add(-time.Minute), payload),
+				payload:   payload,
+				tolerance: 5 * time.Minute,
+			},
+		},
+		{
+			name: "expired",
+			args: args{
+				key:       key,
+				header:    Sign(key, time.Now().Add(-time.Hour), payload),
+				payload:   payload,
+				tolerance: 5 * time.Minute,
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong key",
+			args: args{
+				key:     []byte("other"),
+				header:  Sign(key, time.Now(), payload),
+				payload: payload,
+			},
+			wantErr: true,
+		},
+		{
+			name: "modified payload",
+			args: args{
+				key:     key,
+				header:  Sign(key, time.Now(), payload),
+				payload: []byte(`{"sequence":2}`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid header",
+			args: args{
+				key:     key,
+				header:  "v1=abc",
+				payload: payload,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifySignature(tt.args.key, tt.args.header, tt.args.payload, tt.args.tolerance); (err != nil) != tt.wantErr {
+				t.Errorf("VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zitadel/logging"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/notification/channels/webhook"
+	"github.com/zitadel/zitadel/internal/query/projection"
+	"github.com/zitadel/zitadel/internal/telemetry/metrics"
+)
+
+const (
+	eventWebhookMetricLabelWebhook  = "webhook"
+	eventWebhookMetricLabelInstance = "instance"
+
+	eventWebhookMaxErrorLength = 1000
+)
+
+type EventWebhooksConfig struct {
+	Enabled bool
+	// Timeout of a single call to a webhook
+	Timeout time.Duration
+	// Retry defines the attempts and the delays between them,
+	// deliveries failing MaxAttempts times are kept as failed deliveries (dead letters)
+	Retry webhook.RetryConfig
+	// WorkerInterval is the delay between two polls of due deliveries
+	WorkerInterval time.Duration
+	// BulkLimit is the maximum amount of deliveries sent concurrently by a poll
+	BulkLimit uint64
+	// Events defines the event types per aggregate type which can be subscribed by the event webhooks of the instances
+	Events map[string][]string
+}
+
+func (c *EventWebhooksConfig) Validate() error {
+	if c.Timeout <= 0 {
+		return errors.ThrowInvalidArgument(nil, "HANDL-Ooj4u", "timeout of event webhooks must be positive")
+	}
+	if c.WorkerInterval <= 0 {
+		return errors.ThrowInvalidArgument(nil, "HANDL-eiS5u", "worker interval of event webhooks must be positive")
+	}
+	if c.BulkLimit == 0 {
+		return errors.ThrowInvalidArgument(nil, "HANDL-Vah5i", "bulk limit of event webhooks must be positive")
+	}
+	for aggregateType, eventTypes := range c.Events {
+		if len(eventTypes) == 0 {
+			return errors.ThrowInvalidArgumentf(nil, "HANDL-Iek1o", "no event types defined for aggregate %s of event webhooks", aggregateType)
+		}
+	}
+	return nil
+}
+
+// eventFilter loads the delivered events, decrypting their personal data
+type eventFilter interface {
+	Filter(ctx context.Context, queryFactory *eventstore.SearchQueryBuilder) ([]eventstore.Event, error)
+}
+
+// EventWebhookWorker sends the deliveries added by the event webhook projection.
+// The deliveries are claimed for the duration of the calls, so multiple instances of the worker don't send the same delivery concurrently.
+// The payloads are built from the referenced events at the time of the call, so erased personal data is never sent.
+// Successful deliveries are removed, failed deliveries are retried with an increasing delay
+// until they failed MaxAttempts times and remain as failed deliveries (dead letters).
+type EventWebhookWorker struct {
+	ctx           context.Context
+	client        *database.DB
+	events        eventFilter
+	sender        *webhook.Sender
+	cfg           *EventWebhooksConfig
+	keyEncryption crypto.EncryptionAlgorithm
+	metricSuccessfulDelivery,
+	metricFailedDelivery string
+	now func() time.Time
+}
+
+func NewEventWebhookWorker(
+	ctx context.Context,
+	cfg *EventWebhooksConfig,
+	client *database.DB,
+	es *eventstore.Eventstore,
+	keyEncryption crypto.EncryptionAlgorithm,
+	metricSuccessfulDelivery,
+	metricFailedDelivery string,
+) (*EventWebhookWorker, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &EventWebhookWorker{
+		ctx:                      ctx,
+		client:                   client,
+		events:                   es,
+		sender:                   webhook.NewSender(cfg.Timeout),
+		cfg:                      cfg,
+		keyEncryption:            keyEncryption,
+		metricSuccessfulDelivery: metricSuccessfulDelivery,
+		metricFailedDelivery:     metricFailedDelivery,
+		now:                      time.Now,
+	}, nil
+}
+
+func (w *EventWebhookWorker) Start() {
+	go w.run()
+}
+
+func (w *EventWebhookWorker) run() {
+	ticker := time.NewTicker(w.cfg.WorkerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			err := w.deliverDue(w.ctx)
+			logging.OnError(err).Warn("unable to deliver event webhooks")
+		}
+	}
+}
+
+type eventWebhookDelivery struct {
+	instanceID    string
+	webhookID     string
+	eventSequence uint64
+	eventType     string
+	aggregateType string
+	aggregateID   string
+	attempts      uint64
+	callURL       string
+	signingKey    *crypto.CryptoValue
+}
+
+func (d *eventWebhookDelivery) id() string {
+	return d.instanceID + ":" + d.webhookID + ":" + strconv.FormatUint(d.eventSequence, 10)
+}
+
+// deliverDue claims the due deliveries and sends them concurrently
+func (w *EventWebhookWorker) deliverDue(ctx context.Context) error {
+	deliveries, err := w.claim(ctx)
+	if err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *eventWebhookDelivery) {
+			defer wg.Done()
+			err := w.deliver(ctx, delivery)
+			logging.WithFields("delivery", delivery.id()).OnError(err).Warn("unable to store result of event webhook delivery")
+		}(delivery)
+	}
+	wg.Wait()
+	return nil
+}
+
+const eventWebhookClaimStmt = "WITH claimed AS (" +
+	"UPDATE " + projection.EventWebhookDeliveryTable +
+	" SET " + projection.EventWebhookDeliveryNextAttemptCol + " = $1" +
+	" WHERE (" + projection.EventWebhookDeliveryInstanceIDCol + ", " + projection.EventWebhookDeliveryWebhookIDCol + ", " + projection.EventWebhookDeliveryEventSequenceCol + ") IN (" +
+	"SELECT " + projection.EventWebhookDeliveryInstanceIDCol + ", " + projection.EventWebhookDeliveryWebhookIDCol + ", " + projection.EventWebhookDeliveryEventSequenceCol +
+	" FROM " + projection.EventWebhookDeliveryTable +
+	" WHERE " + projection.EventWebhookDeliveryStateCol + " = $2 AND " + projection.EventWebhookDeliveryNextAttemptCol + " <= $3" +
+	" ORDER BY " + projection.EventWebhookDeliveryNextAttemptCol + " LIMIT $4)" +
+	" AND " + projection.EventWebhookDeliveryStateCol + " = $2 AND " + projection.EventWebhookDeliveryNextAttemptCol + " <= $3" +
+	" RETURNING " + projection.EventWebhookDeliveryInstanceIDCol + ", " + projection.EventWebhookDeliveryWebhookIDCol + ", " + projection.EventWebhookDeliveryEventSequenceCol + ", " +
+	projection.EventWebhookDeliveryEventTypeCol + ", " + projection.EventWebhookDeliveryAggregateTypeCol + ", " + projection.EventWebhookDeliveryAggregateIDCol + ", " +
+	projection.EventWebhookDeliveryAttemptsCol +
+	") SELECT c." + projection.EventWebhookDeliveryInstanceIDCol + ", c." + projection.EventWebhookDeliveryWebhookIDCol + ", c." + projection.EventWebhookDeliveryEventSequenceCol +
+	", c." + projection.EventWebhookDeliveryEventTypeCol + ", c." + projection.EventWebhookDeliveryAggregateTypeCol + ", c." + projection.EventWebhookDeliveryAggregateIDCol +
+	", c." + projection.EventWebhookDeliveryAttemptsCol +
+	", w." + projection.EventWebhookCallURLCol + ", w." + projection.EventWebhookSigningKeyCol +
+	" FROM claimed c JOIN " + projection.EventWebhookProjectionTable + " w" +
+	" ON w." + projection.EventWebhookInstanceIDCol + " = c." + projection.EventWebhookDeliveryInstanceIDCol +
+	" AND w." + projection.EventWebhookIDCol + " = c." + projection.EventWebhookDeliveryWebhookIDCol
+
+// claim moves the next attempt of the due deliveries behind the timeout of the calls,
+// if the worker stops during the calls the deliveries are due again after the lease
+func (w *EventWebhookWorker) claim(ctx context.Context) (_ []*eventWebhookDelivery, err error) {
+	now := w.now()
+	rows, err := w.client.QueryContext(ctx, eventWebhookClaimStmt,
+		now.Add(2*w.cfg.Timeout),
+		domain.EventWebhookDeliveryStatePending,
+		now,
+		w.cfg.BulkLimit,
+	)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "HANDL-ahj1E", "unable to claim event webhook deliveries")
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = errors.ThrowInternal(closeErr, "HANDL-Ohp8e", "unable to close rows")
+		}
+	}()
+	deliveries := make([]*eventWebhookDelivery, 0, w.cfg.BulkLimit)
+	for rows.Next() {
+		delivery := new(eventWebhookDelivery)
+		if err := rows.Scan(
+			&delivery.instanceID,
+			&delivery.webhookID,
+			&delivery.eventSequence,
+			&delivery.eventType,
+			&delivery.aggregateType,
+			&delivery.aggregateID,
+			&delivery.attempts,
+			&delivery.callURL,
+			&delivery.signingKey,
+		); err != nil {
+			return nil, errors.ThrowInternal(err, "HANDL-Ek9ai", "unable to scan event webhook delivery")
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ThrowInternal(err, "HANDL-ua5Ie", "unable to read event webhook deliveries")
+	}
+	return deliveries, nil
+}
+
+// deliver sends the delivery once with a context bounded by the timeout and stores the result
+func (w *EventWebhookWorker) deliver(ctx context.Context, delivery *eventWebhookDelivery) error {
+	labels := map[string]attribute.Value{
+		eventWebhookMetricLabelWebhook:  attribute.StringValue(delivery.webhookID),
+		eventWebhookMetricLabelInstance: attribute.StringValue(delivery.instanceID),
+	}
+	err := w.send(ctx, delivery)
+	if err == nil {
+		w.count(ctx, w.metricSuccessfulDelivery, labels)
+		return w.succeeded(ctx, delivery)
+	}
+	logging.WithFields("delivery", delivery.id(), "attempt", delivery.attempts+1).WithError(err).Info("event webhook delivery failed")
+	w.count(ctx, w.metricFailedDelivery, labels)
+	return w.failed(ctx, delivery, err)
+}
+
+func (w *EventWebhookWorker) send(ctx context.Context, delivery *eventWebhookDelivery) error {
+	var signingKey []byte
+	if delivery.signingKey != nil {
+		key, err := crypto.DecryptString(delivery.signingKey, w.keyEncryption)
+		if err != nil {
+			return err
+		}
+		signingKey = []byte(key)
+	}
+	payload, err := w.payload(ctx, delivery)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.Timeout)
+	defer cancel()
+	return w.sender.Send(ctx, webhook.Config{CallURL: delivery.callURL}, signingKey, delivery.id(), payload)
+}
+
+// payload loads the referenced event and builds the body of the delivery
+func (w *EventWebhookWorker) payload(ctx context.Context, delivery *eventWebhookDelivery) ([]byte, error) {
+	events, err := w.events.Filter(ctx, eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID(delivery.instanceID).
+		Limit(1).
+		AddQuery().
+		AggregateTypes(eventstore.AggregateType(delivery.aggregateType)).
+		AggregateIDs(delivery.aggregateID).
+		EventTypes(eventstore.EventType(delivery.eventType)).
+		SequenceGreater(delivery.eventSequence-1).
+		SequenceLess(delivery.eventSequence+1).
+		Builder(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, errors.ThrowNotFound(nil, "HANDL-Ieh4o", "delivered event not found")
+	}
+	payload, err := json.Marshal(projection.EventWebhookPayloadFromEvent(events[0]))
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "HANDL-Wh6dl", "unable to marshal event")
+	}
+	return payload, nil
+}
+
+func (w *EventWebhookWorker) count(ctx context.Context, metric string, labels map[string]attribute.Value) {
+	err := metrics.AddCount(ctx, metric, 1, labels)
+	logging.WithFields("metric", metric).OnError(err).Warn("unable to count event webhook delivery")
+}
+
+const eventWebhookDeliveredStmt = "DELETE FROM " + projection.EventWebhookDeliveryTable +
+	" WHERE " + projection.EventWebhookDeliveryInstanceIDCol + " = $1 AND " + projection.EventWebhookDeliveryWebhookIDCol + " = $2 AND " + projection.EventWebhookDeliveryEventSequenceCol + " = $3"
+
+func (w *EventWebhookWorker) succeeded(ctx context.Context, delivery *eventWebhookDelivery) error {
+	_, err := w.client.ExecContext(ctx, eventWebhookDeliveredStmt, delivery.instanceID, delivery.webhookID, delivery.eventSequence)
+	if err != nil {
+		return errors.ThrowInternal(err, "HANDL-Oov4a", "unable to remove event webhook delivery")
+	}
+	return nil
+}
+
+const eventWebhookFailedStmt = "UPDATE " + projection.EventWebhookDeliveryTable +
+	" SET (" + projection.EventWebhookDeliveryAttemptsCol + ", " + projection.EventWebhookDeliveryNextAttemptCol + ", " + projection.EventWebhookDeliveryLastErrorCol + ", " + projection.EventWebhookDeliveryStateCol + ") = ($1, $2, $3, $4)" +
+	" WHERE " + projection.EventWebhookDeliveryInstanceIDCol + " = $5 AND " + projection.EventWebhookDeliveryWebhookIDCol + " = $6 AND " + projection.EventWebhookDeliveryEventSequenceCol + " = $7"
+
+func (w *EventWebhookWorker) failed(ctx context.Context, delivery *eventWebhookDelivery, deliveryErr error) error {
+	attempts := delivery.attempts + 1
+	state := domain.EventWebhookDeliveryStatePending
+	if attempts >= uint64(w.cfg.Retry.MaxAttempts) {
+		state = domain.EventWebhookDeliveryStateFailed
+	}
+	lastError := deliveryErr.Error()
+	if len(lastError) > eventWebhookMaxErrorLength {
+		lastError = lastError[:eventWebhookMaxErrorLength]
+	}
+	_, err := w.client.ExecContext(ctx, eventWebhookFailedStmt,
+		attempts,
+		w.now().Add(w.cfg.Retry.Backoff(uint(attempts)+1)),
+		lastError,
+		state,
+		delivery.instanceID,
+		delivery.webhookID,
+		delivery.eventSequence,
+	)
+	if err != nil {
+		return errors.ThrowInternal(err, "HANDL-ieL4o", "unable to update event webhook delivery")
+	}
+	return nil
+}
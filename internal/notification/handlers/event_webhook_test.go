@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/notification/channels/webhook"
+	"github.com/zitadel/zitadel/internal/query/projection"
+)
+
+type mockEventFilter []eventstore.Event
+
+func (m mockEventFilter) Filter(context.Context, *eventstore.SearchQueryBuilder) ([]eventstore.Event, error) {
+	return m, nil
+}
+
+func TestEventWebhookWorker_deliverDue(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	type fields struct {
+		status   int
+		attempts uint64
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		expect func(m sqlmock.Sqlmock)
+	}{
+		{
+			name: "delivered, removed",
+			fields: fields{
+				status:   http.StatusOK,
+				attempts: 0,
+			},
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(regexp.QuoteMeta(eventWebhookDeliveredStmt)).
+					WithArgs("instance-id", "webhook-id", uint64(15)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed, retried with backoff",
+			fields: fields{
+				status:   http.StatusInternalServerError,
+				attempts: 1,
+			},
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(regexp.QuoteMeta(eventWebhookFailedStmt)).
+					WithArgs(uint64(2), now.Add(2*time.Second), sqlmock.AnyArg(), domain.EventWebhookDeliveryStatePending, "instance-id", "webhook-id", uint64(15)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "failed max attempts, dead letter",
+			fields: fields{
+				status:   http.StatusInternalServerError,
+				attempts: 2,
+			},
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(regexp.QuoteMeta(eventWebhookFailedStmt)).
+					WithArgs(uint64(3), sqlmock.AnyArg(), sqlmock.AnyArg(), domain.EventWebhookDeliveryStateFailed, "instance-id", "webhook-id", uint64(15)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signature, deliveryID string
+			payload := new(projection.EventWebhookPayload)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				signature = r.Header.Get(webhook.SignatureHeader)
+				deliveryID = r.Header.Get(webhook.DeliveryIDHeader)
+				if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+					t.Errorf("unable to decode payload: %v", err)
+				}
+				w.WriteHeader(tt.fields.status)
+			}))
+			defer server.Close()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("unable to create mock: %v", err)
+			}
+			defer db.Close()
+			mock.ExpectQuery(regexp.QuoteMeta(eventWebhookClaimStmt)).
+				WithArgs(now.Add(2*time.Second), domain.EventWebhookDeliveryStatePending, now, uint64(10)).
+				WillReturnRows(
+					sqlmock.NewRows([]string{"instance_id", "webhook_id", "event_sequence", "event_type", "aggregate_type", "aggregate_id", "attempts", "call_url", "signing_key"}).
+						AddRow("instance-id", "webhook-id", uint64(15), "user.human.added", "user", "user-id", tt.fields.attempts, server.URL, driver.Value([]byte(`{"cryptoType":0,"algorithm":"enc","keyID":"id","crypted":"a2V5"}`))),
+				)
+			tt.expect(mock)
+
+			w := &EventWebhookWorker{
+				client: &database.DB{DB: db},
+				events: mockEventFilter{
+					eventstore.BaseEventFromRepo(&repository.Event{
+						Sequence:      15,
+						Type:          "user.human.added",
+						AggregateType: "user",
+						AggregateID:   "user-id",
+						InstanceID:    "instance-id",
+						Data:          []byte(`{"userName":"username"}`),
+					}),
+				},
+				sender: webhook.NewSender(time.Second),
+				cfg: &EventWebhooksConfig{
+					Timeout:   time.Second,
+					BulkLimit: 10,
+					Retry: webhook.RetryConfig{
+						MaxAttempts:    3,
+						InitialBackoff: time.Second,
+						MaxBackoff:     time.Minute,
+					},
+				},
+				keyEncryption: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				now:           func() time.Time { return now },
+			}
+			if err := w.deliverDue(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+			if deliveryID != "instance-id:webhook-id:15" {
+				t.Errorf("unexpected delivery id %q", deliveryID)
+			}
+			if signature == "" {
+				t.Error("delivery not signed")
+			}
+			if payload.AggregateID != "user-id" || string(payload.Payload) != `{"userName":"username"}` {
+				t.Errorf("unexpected payload %+v", payload)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/zitadel/logging"
+
 	"github.com/zitadel/zitadel/internal/command"
 	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
@@ -126,9 +128,9 @@ func (u *userNotifier) reduceInitCodeAdded(event eventstore.Event) (*handler.Sta
 	if alreadyHandled {
 		return crdb.NewNoOpStatement(e), nil
 	}
-	code, err := crypto.DecryptString(e.Code, u.queries.UserDataCrypto)
-	if err != nil {
-		return nil, err
+	code, sendable, err := u.decryptCode(e.Aggregate(), e.Code)
+	if err != nil || !sendable {
+		return crdb.NewNoOpStatement(e), err
 	}
 	colors, err := u.queries.ActiveLabelPolicyByOrg(ctx, e.Aggregate().ResourceOwner, false)
 	if err != nil {
@@ -174,6 +176,7 @@ func (u *userNotifier) reduceInitCodeAdded(event eventstore.Event) (*handler.Sta
 	if err != nil {
 		return nil, err
 	}
+	u.deleteCodeCopy(e.Aggregate(), e.Code)
 	return crdb.NewNoOpStatement(e), nil
 }
 
@@ -183,6 +186,7 @@ func (u *userNotifier) reduceEmailCodeAdded(event eventstore.Event) (*handler.St
 		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-SWf3g", "reduce.wrong.event.type %s", user.HumanEmailCodeAddedType)
 	}
 	if e.CodeReturned {
+		u.deleteCodeCopy(e.Aggregate(), e.Code)
 		return crdb.NewNoOpStatement(e), nil
 	}
 	ctx := HandlerContext(event.Aggregate())
@@ -195,9 +199,9 @@ func (u *userNotifier) reduceEmailCodeAdded(event eventstore.Event) (*handler.St
 	if alreadyHandled {
 		return crdb.NewNoOpStatement(e), nil
 	}
-	code, err := crypto.DecryptString(e.Code, u.queries.UserDataCrypto)
-	if err != nil {
-		return nil, err
+	code, sendable, err := u.decryptCode(e.Aggregate(), e.Code)
+	if err != nil || !sendable {
+		return crdb.NewNoOpStatement(e), err
 	}
 	colors, err := u.queries.ActiveLabelPolicyByOrg(ctx, e.Aggregate().ResourceOwner, false)
 	if err != nil {
@@ -243,6 +247,7 @@ func (u *userNotifier) reduceEmailCodeAdded(event eventstore.Event) (*handler.St
 	if err != nil {
 		return nil, err
 	}
+	u.deleteCodeCopy(e.Aggregate(), e.Code)
 	return crdb.NewNoOpStatement(e), nil
 }
 
@@ -252,6 +257,7 @@ func (u *userNotifier) reducePasswordCodeAdded(event eventstore.Event) (*handler
 		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-Eeg3s", "reduce.wrong.event.type %s", user.HumanPasswordCodeAddedType)
 	}
 	if e.CodeReturned {
+		u.deleteCodeCopy(e.Aggregate(), e.Code)
 		return crdb.NewNoOpStatement(e), nil
 	}
 	ctx := HandlerContext(event.Aggregate())
@@ -264,9 +270,9 @@ func (u *userNotifier) reducePasswordCodeAdded(event eventstore.Event) (*handler
 	if alreadyHandled {
 		return crdb.NewNoOpStatement(e), nil
 	}
-	code, err := crypto.DecryptString(e.Code, u.queries.UserDataCrypto)
-	if err != nil {
-		return nil, err
+	code, sendable, err := u.decryptCode(e.Aggregate(), e.Code)
+	if err != nil || !sendable {
+		return crdb.NewNoOpStatement(e), err
 	}
 	colors, err := u.queries.ActiveLabelPolicyByOrg(ctx, e.Aggregate().ResourceOwner, false)
 	if err != nil {
@@ -328,6 +334,7 @@ func (u *userNotifier) reducePasswordCodeAdded(event eventstore.Event) (*handler
 	if err != nil {
 		return nil, err
 	}
+	u.deleteCodeCopy(e.Aggregate(), e.Code)
 	return crdb.NewNoOpStatement(e), nil
 }
 
@@ -398,6 +405,7 @@ func (u *userNotifier) reducePasswordlessCodeRequested(event eventstore.Event) (
 		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-EDtjd", "reduce.wrong.event.type %s", user.HumanPasswordlessInitCodeAddedType)
 	}
 	if e.CodeReturned {
+		u.deleteCodeCopy(e.Aggregate(), e.Code)
 		return crdb.NewNoOpStatement(e), nil
 	}
 	ctx := HandlerContext(event.Aggregate())
@@ -408,9 +416,9 @@ func (u *userNotifier) reducePasswordlessCodeRequested(event eventstore.Event) (
 	if alreadyHandled {
 		return crdb.NewNoOpStatement(e), nil
 	}
-	code, err := crypto.DecryptString(e.Code, u.queries.UserDataCrypto)
-	if err != nil {
-		return nil, err
+	code, sendable, err := u.decryptCode(e.Aggregate(), e.Code)
+	if err != nil || !sendable {
+		return crdb.NewNoOpStatement(e), err
 	}
 	colors, err := u.queries.ActiveLabelPolicyByOrg(ctx, e.Aggregate().ResourceOwner, false)
 	if err != nil {
@@ -456,6 +464,7 @@ func (u *userNotifier) reducePasswordlessCodeRequested(event eventstore.Event) (
 	if err != nil {
 		return nil, err
 	}
+	u.deleteCodeCopy(e.Aggregate(), e.Code)
 	return crdb.NewNoOpStatement(e), nil
 }
 
@@ -545,9 +554,9 @@ func (u *userNotifier) reducePhoneCodeAdded(event eventstore.Event) (*handler.St
 	if alreadyHandled {
 		return crdb.NewNoOpStatement(e), nil
 	}
-	code, err := crypto.DecryptString(e.Code, u.queries.UserDataCrypto)
-	if err != nil {
-		return nil, err
+	code, sendable, err := u.decryptCode(e.Aggregate(), e.Code)
+	if err != nil || !sendable {
+		return crdb.NewNoOpStatement(e), err
 	}
 	colors, err := u.queries.ActiveLabelPolicyByOrg(ctx, e.Aggregate().ResourceOwner, false)
 	if err != nil {
@@ -587,6 +596,7 @@ func (u *userNotifier) reducePhoneCodeAdded(event eventstore.Event) (*handler.St
 	if err != nil {
 		return nil, err
 	}
+	u.deleteCodeCopy(e.Aggregate(), e.Code)
 	return crdb.NewNoOpStatement(e), nil
 }
 
@@ -596,3 +606,23 @@ func (u *userNotifier) checkIfCodeAlreadyHandledOrExpired(ctx context.Context, e
 	}
 	return u.queries.IsAlreadyHandled(ctx, event, data, user.AggregateType, eventTypes...)
 }
+
+// decryptCode returns the plain code to send.
+// The encrypted copy of a hashed code is deleted after the code was sent or is expired,
+// so such a code isn't sendable anymore.
+func (u *userNotifier) decryptCode(aggregate eventstore.Aggregate, code *crypto.CryptoValue) (_ string, sendable bool, err error) {
+	plain, err := crypto.DecryptCode(aggregate.InstanceID, code, u.queries.UserDataCrypto)
+	if errors.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return plain, true, nil
+}
+
+// deleteCodeCopy deletes the encrypted copy of a hashed code which was sent or returned to the caller
+func (u *userNotifier) deleteCodeCopy(aggregate eventstore.Aggregate, code *crypto.CryptoValue) {
+	err := crypto.DeleteCodeCopy(aggregate.InstanceID, code, u.queries.UserDataCrypto)
+	logging.WithFields("instance", aggregate.InstanceID, "aggregate", aggregate.ID).OnError(err).Warn("unable to delete the copy of the code")
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/zitadel/zitadel/internal/command"
 	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/notification/handlers"
 	_ "github.com/zitadel/zitadel/internal/notification/statik"
@@ -23,6 +24,8 @@ const (
 	metricFailedDeliveriesSMS       = "failed_deliveries_sms"
 	metricSuccessfulDeliveriesJSON  = "successful_deliveries_json"
 	metricFailedDeliveriesJSON      = "failed_deliveries_json"
+	metricSuccessfulDeliveriesEvent = "successful_deliveries_event_webhook"
+	metricFailedDeliveriesEvent     = "failed_deliveries_event_webhook"
 )
 
 func Start(
@@ -31,18 +34,21 @@ func Start(
 	quotaHandlerCustomConfig projection.CustomConfig,
 	telemetryHandlerCustomConfig projection.CustomConfig,
 	telemetryCfg handlers.TelemetryPusherConfig,
+	eventWebhooksCfg handlers.EventWebhooksConfig,
 	externalDomain string,
 	externalPort uint16,
 	externalSecure bool,
 	commands *command.Commands,
 	queries *query.Queries,
 	es *eventstore.Eventstore,
+	client *database.DB,
 	assetsPrefix func(context.Context) string,
 	fileSystemPath string,
 	userEncryption,
 	smtpEncryption,
-	smsEncryption crypto.EncryptionAlgorithm,
-) {
+	smsEncryption,
+	eventWebhookEncryption crypto.EncryptionAlgorithm,
+) error {
 	statikFS, err := statik_fs.NewWithNamespace("notification")
 	logging.OnError(err).Panic("unable to start listener")
 	err = metrics.RegisterCounter(metricSuccessfulDeliveriesEmail, "Successfully delivered emails")
@@ -57,6 +63,10 @@ func Start(
 	logging.WithFields("metric", metricSuccessfulDeliveriesJSON).OnError(err).Panic("unable to register counter")
 	err = metrics.RegisterCounter(metricFailedDeliveriesJSON, "Failed JSON message deliveries")
 	logging.WithFields("metric", metricFailedDeliveriesJSON).OnError(err).Panic("unable to register counter")
+	err = metrics.RegisterCounter(metricSuccessfulDeliveriesEvent, "Successfully delivered events to event webhooks")
+	logging.WithFields("metric", metricSuccessfulDeliveriesEvent).OnError(err).Panic("unable to register counter")
+	err = metrics.RegisterCounter(metricFailedDeliveriesEvent, "Failed event deliveries to event webhooks")
+	logging.WithFields("metric", metricFailedDeliveriesEvent).OnError(err).Panic("unable to register counter")
 	q := handlers.NewNotificationQueries(queries, es, externalDomain, externalPort, externalSecure, fileSystemPath, userEncryption, smtpEncryption, smsEncryption, statikFS)
 	handlers.NewUserNotifier(
 		ctx,
@@ -88,4 +98,20 @@ func Start(
 			metricFailedDeliveriesJSON,
 		).Start()
 	}
+	if eventWebhooksCfg.Enabled {
+		worker, err := handlers.NewEventWebhookWorker(
+			ctx,
+			&eventWebhooksCfg,
+			client,
+			es,
+			eventWebhookEncryption,
+			metricSuccessfulDeliveriesEvent,
+			metricFailedDeliveriesEvent,
+		)
+		if err != nil {
+			return err
+		}
+		worker.Start()
+	}
+	return nil
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/api/call"
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/errors"
 	"github.com/zitadel/zitadel/internal/query/projection"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
@@ -116,6 +117,43 @@ func (q *Queries) ClearCurrentSequence(ctx context.Context, projectionName strin
 	return tx.Commit()
 }
 
+// RebuildProjection starts the rebuild of the projection into shadow tables,
+// the projection stays available until the shadow tables replace it
+func (q *Queries) RebuildProjection(ctx context.Context, projectionName string) error {
+	return projection.StartRebuild(projectionName)
+}
+
+type ProjectionRebuild struct {
+	ProjectionName    string
+	State             domain.ProjectionRebuildState
+	Round             uint64
+	ReplayedInstances uint64
+	Instances         uint64
+	Swapped           bool
+	StartedAt         time.Time
+	ChangedAt         time.Time
+	Error             string
+}
+
+// ProjectionRebuild returns the progress of the latest rebuild of the projection started in this process
+func (q *Queries) ProjectionRebuild(ctx context.Context, projectionName string) (*ProjectionRebuild, error) {
+	status, err := projection.RebuildStatusByName(projectionName)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectionRebuild{
+		ProjectionName:    status.ProjectionName,
+		State:             status.State,
+		Round:             uint64(status.Progress.Round),
+		ReplayedInstances: uint64(status.Progress.ReplayedInstances),
+		Instances:         uint64(status.Progress.Instances),
+		Swapped:           status.Progress.Swapped,
+		StartedAt:         status.StartedAt,
+		ChangedAt:         status.ChangedAt,
+		Error:             status.Error,
+	}, nil
+}
+
 func (q *Queries) checkAndLock(ctx context.Context, projectionName string) error {
 	projectionQuery, args, err := sq.Select("count(*)").
 		From("[show tables from projections]").
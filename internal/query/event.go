@@ -41,6 +41,31 @@ func (q *Queries) SearchEvents(ctx context.Context, query *eventstore.SearchQuer
 	return q.convertEvents(ctx, events), nil
 }
 
+const (
+	// eventStreamPollInterval defines how often a stream checks for events pushed by other ZITADEL instances
+	eventStreamPollInterval = 5 * time.Second
+	// eventStreamMaxEditors limits the editors cached by a stream, the cache is reset as soon as it's exceeded
+	eventStreamMaxEditors = 1000
+)
+
+// StreamEvents calls reduce for every event matching the query with a sequence greater than `from` in order of their sequence.
+// It returns as soon as the context is done or reduce returns an error.
+func (q *Queries) StreamEvents(ctx context.Context, query *eventstore.SearchQueryBuilder, from uint64, auditLogRetention time.Duration, reduce func(*Event) error) (err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	users := make(map[string]*EventEditor)
+	return q.eventstore.Stream(ctx, query, from, eventStreamPollInterval, func(event eventstore.Event) error {
+		if auditLogRetention != 0 && len(filterAuditLogRetention(ctx, []eventstore.Event{event}, auditLogRetention)) == 0 {
+			return nil
+		}
+		if len(users) >= eventStreamMaxEditors {
+			users = make(map[string]*EventEditor)
+		}
+		return reduce(q.convertEvent(ctx, event, users))
+	})
+}
+
 func filterAuditLogRetention(ctx context.Context, events []eventstore.Event, auditLogRetention time.Duration) []eventstore.Event {
 	callTime := call.FromContext(ctx)
 	if callTime.IsZero() {
@@ -0,0 +1,363 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	errs "errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/api/call"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/query/projection"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+)
+
+type EventWebhooks struct {
+	SearchResponse
+	EventWebhooks []*EventWebhook
+}
+
+type EventWebhook struct {
+	ID            string
+	CreationDate  time.Time
+	ChangeDate    time.Time
+	ResourceOwner string
+	Sequence      uint64
+
+	Name       string
+	CallURL    string
+	EventTypes database.StringArray
+}
+
+type EventWebhookSearchQueries struct {
+	SearchRequest
+	Queries []SearchQuery
+}
+
+func (q *EventWebhookSearchQueries) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
+	query = q.SearchRequest.toQuery(query)
+	for _, q := range q.Queries {
+		query = q.toQuery(query)
+	}
+	return query
+}
+
+type EventWebhookDeliveries struct {
+	SearchResponse
+	Deliveries []*EventWebhookDelivery
+}
+
+type EventWebhookDelivery struct {
+	WebhookID     string
+	EventSequence uint64
+	EventType     string
+	CreationDate  time.Time
+	Attempts      uint64
+	NextAttempt   time.Time
+	LastError     string
+	State         domain.EventWebhookDeliveryState
+}
+
+type EventWebhookDeliverySearchQueries struct {
+	SearchRequest
+	Queries []SearchQuery
+}
+
+func (q *EventWebhookDeliverySearchQueries) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
+	query = q.SearchRequest.toQuery(query)
+	for _, q := range q.Queries {
+		query = q.toQuery(query)
+	}
+	return query
+}
+
+var (
+	eventWebhooksTable = table{
+		name:          projection.EventWebhookProjectionTable,
+		instanceIDCol: projection.EventWebhookInstanceIDCol,
+	}
+	EventWebhookColumnID = Column{
+		name:  projection.EventWebhookIDCol,
+		table: eventWebhooksTable,
+	}
+	EventWebhookColumnCreationDate = Column{
+		name:  projection.EventWebhookCreationDateCol,
+		table: eventWebhooksTable,
+	}
+	EventWebhookColumnChangeDate = Column{
+		name:  projection.EventWebhookChangeDateCol,
+		table: eventWebhooksTable,
+	}
+	EventWebhookColumnResourceOwner = Column{
+		name:  projection.EventWebhookResourceOwnerCol,
+		table: eventWebhooksTable,
+	}
+	EventWebhookColumnInstanceID = Column{
+		name:  projection.EventWebhookInstanceIDCol,
+		table: eventWebhooksTable,
+	}
+	EventWebhookColumnSequence = Column{
+		name:  projection.EventWebhookSequenceCol,
+		table: eventWebhooksTable,
+	}
+	EventWebhookColumnName = Column{
+		name:  projection.EventWebhookNameCol,
+		table: eventWebhooksTable,
+	}
+	EventWebhookColumnCallURL = Column{
+		name:  projection.EventWebhookCallURLCol,
+		table: eventWebhooksTable,
+	}
+	EventWebhookColumnEventTypes = Column{
+		name:  projection.EventWebhookEventTypesCol,
+		table: eventWebhooksTable,
+	}
+)
+
+var (
+	eventWebhookDeliveriesTable = table{
+		name:          projection.EventWebhookDeliveryTable,
+		instanceIDCol: projection.EventWebhookDeliveryInstanceIDCol,
+	}
+	EventWebhookDeliveryColumnInstanceID = Column{
+		name:  projection.EventWebhookDeliveryInstanceIDCol,
+		table: eventWebhookDeliveriesTable,
+	}
+	EventWebhookDeliveryColumnWebhookID = Column{
+		name:  projection.EventWebhookDeliveryWebhookIDCol,
+		table: eventWebhookDeliveriesTable,
+	}
+	EventWebhookDeliveryColumnEventSequence = Column{
+		name:  projection.EventWebhookDeliveryEventSequenceCol,
+		table: eventWebhookDeliveriesTable,
+	}
+	EventWebhookDeliveryColumnEventType = Column{
+		name:  projection.EventWebhookDeliveryEventTypeCol,
+		table: eventWebhookDeliveriesTable,
+	}
+	EventWebhookDeliveryColumnCreationDate = Column{
+		name:  projection.EventWebhookDeliveryCreationDateCol,
+		table: eventWebhookDeliveriesTable,
+	}
+	EventWebhookDeliveryColumnAttempts = Column{
+		name:  projection.EventWebhookDeliveryAttemptsCol,
+		table: eventWebhookDeliveriesTable,
+	}
+	EventWebhookDeliveryColumnNextAttempt = Column{
+		name:  projection.EventWebhookDeliveryNextAttemptCol,
+		table: eventWebhookDeliveriesTable,
+	}
+	EventWebhookDeliveryColumnLastError = Column{
+		name:  projection.EventWebhookDeliveryLastErrorCol,
+		table: eventWebhookDeliveriesTable,
+	}
+	EventWebhookDeliveryColumnState = Column{
+		name:  projection.EventWebhookDeliveryStateCol,
+		table: eventWebhookDeliveriesTable,
+	}
+)
+
+func (q *Queries) EventWebhookByID(ctx context.Context, shouldTriggerBulk bool, id string) (_ *EventWebhook, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if shouldTriggerBulk {
+		projection.EventWebhookProjection.Trigger(ctx)
+	}
+
+	query, scan := prepareEventWebhookQuery(ctx, q.client)
+	stmt, args, err := query.Where(
+		sq.Eq{
+			EventWebhookColumnID.identifier():         id,
+			EventWebhookColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+		},
+	).ToSql()
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "QUERY-Wh1qr", "Errors.Query.SQLStatement")
+	}
+
+	row := q.client.QueryRowContext(ctx, stmt, args...)
+	return scan(row)
+}
+
+func (q *Queries) SearchEventWebhooks(ctx context.Context, queries *EventWebhookSearchQueries) (_ *EventWebhooks, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	query, scan := prepareEventWebhooksQuery(ctx, q.client)
+	stmt, args, err := queries.toQuery(query).
+		Where(sq.Eq{
+			EventWebhookColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+		}).ToSql()
+	if err != nil {
+		return nil, errors.ThrowInvalidArgument(err, "QUERY-Wh2qr", "Errors.Query.InvalidRequest")
+	}
+
+	rows, err := q.client.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "QUERY-Wh3qr", "Errors.Internal")
+	}
+	webhooks, err := scan(rows)
+	if err != nil {
+		return nil, err
+	}
+	webhooks.LatestSequence, err = q.latestSequence(ctx, eventWebhooksTable)
+	return webhooks, err
+}
+
+// SearchEventWebhookDeliveries returns the deliveries of the event webhooks of the instance,
+// use NewEventWebhookDeliveryStateQuery with [domain.EventWebhookDeliveryStateFailed] to list the dead letters
+func (q *Queries) SearchEventWebhookDeliveries(ctx context.Context, queries *EventWebhookDeliverySearchQueries) (_ *EventWebhookDeliveries, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	query, scan := prepareEventWebhookDeliveriesQuery(ctx, q.client)
+	stmt, args, err := queries.toQuery(query).
+		Where(sq.Eq{
+			EventWebhookDeliveryColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+		}).ToSql()
+	if err != nil {
+		return nil, errors.ThrowInvalidArgument(err, "QUERY-Wh4qr", "Errors.Query.InvalidRequest")
+	}
+
+	rows, err := q.client.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "QUERY-Wh5qr", "Errors.Internal")
+	}
+	deliveries, err := scan(rows)
+	if err != nil {
+		return nil, err
+	}
+	deliveries.LatestSequence, err = q.latestSequence(ctx, eventWebhooksTable)
+	return deliveries, err
+}
+
+func NewEventWebhookNameSearchQuery(method TextComparison, value string) (SearchQuery, error) {
+	return NewTextQuery(EventWebhookColumnName, value, method)
+}
+
+func NewEventWebhookDeliveryWebhookIDSearchQuery(id string) (SearchQuery, error) {
+	return NewTextQuery(EventWebhookDeliveryColumnWebhookID, id, TextEquals)
+}
+
+func NewEventWebhookDeliveryStateQuery(state domain.EventWebhookDeliveryState) (SearchQuery, error) {
+	return NewNumberQuery(EventWebhookDeliveryColumnState, state, NumberEquals)
+}
+
+func prepareEventWebhookQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Row) (*EventWebhook, error)) {
+	return sq.Select(
+			EventWebhookColumnID.identifier(),
+			EventWebhookColumnCreationDate.identifier(),
+			EventWebhookColumnChangeDate.identifier(),
+			EventWebhookColumnResourceOwner.identifier(),
+			EventWebhookColumnSequence.identifier(),
+			EventWebhookColumnName.identifier(),
+			EventWebhookColumnCallURL.identifier(),
+			EventWebhookColumnEventTypes.identifier(),
+		).From(eventWebhooksTable.identifier() + db.Timetravel(call.Took(ctx))).
+			PlaceholderFormat(sq.Dollar), func(row *sql.Row) (*EventWebhook, error) {
+			webhook := new(EventWebhook)
+			err := row.Scan(
+				&webhook.ID,
+				&webhook.CreationDate,
+				&webhook.ChangeDate,
+				&webhook.ResourceOwner,
+				&webhook.Sequence,
+				&webhook.Name,
+				&webhook.CallURL,
+				&webhook.EventTypes,
+			)
+			if err != nil {
+				if errs.Is(err, sql.ErrNoRows) {
+					return nil, errors.ThrowNotFound(err, "QUERY-Wh6qr", "Errors.EventWebhook.NotFound")
+				}
+				return nil, errors.ThrowInternal(err, "QUERY-Wh7qr", "Errors.Internal")
+			}
+			return webhook, nil
+		}
+}
+
+func prepareEventWebhooksQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*EventWebhooks, error)) {
+	return sq.Select(
+			EventWebhookColumnID.identifier(),
+			EventWebhookColumnCreationDate.identifier(),
+			EventWebhookColumnChangeDate.identifier(),
+			EventWebhookColumnResourceOwner.identifier(),
+			EventWebhookColumnSequence.identifier(),
+			EventWebhookColumnName.identifier(),
+			EventWebhookColumnCallURL.identifier(),
+			EventWebhookColumnEventTypes.identifier(),
+			countColumn.identifier(),
+		).From(eventWebhooksTable.identifier() + db.Timetravel(call.Took(ctx))).
+			PlaceholderFormat(sq.Dollar), func(rows *sql.Rows) (*EventWebhooks, error) {
+			webhooks := &EventWebhooks{EventWebhooks: []*EventWebhook{}}
+			for rows.Next() {
+				webhook := new(EventWebhook)
+				err := rows.Scan(
+					&webhook.ID,
+					&webhook.CreationDate,
+					&webhook.ChangeDate,
+					&webhook.ResourceOwner,
+					&webhook.Sequence,
+					&webhook.Name,
+					&webhook.CallURL,
+					&webhook.EventTypes,
+					&webhooks.Count,
+				)
+				if err != nil {
+					return nil, errors.ThrowInternal(err, "QUERY-Wh8qr", "Errors.Internal")
+				}
+				webhooks.EventWebhooks = append(webhooks.EventWebhooks, webhook)
+			}
+			if err := rows.Close(); err != nil {
+				return nil, errors.ThrowInternal(err, "QUERY-Wh9qr", "Errors.Query.CloseRows")
+			}
+			return webhooks, nil
+		}
+}
+
+func prepareEventWebhookDeliveriesQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*EventWebhookDeliveries, error)) {
+	return sq.Select(
+			EventWebhookDeliveryColumnWebhookID.identifier(),
+			EventWebhookDeliveryColumnEventSequence.identifier(),
+			EventWebhookDeliveryColumnEventType.identifier(),
+			EventWebhookDeliveryColumnCreationDate.identifier(),
+			EventWebhookDeliveryColumnAttempts.identifier(),
+			EventWebhookDeliveryColumnNextAttempt.identifier(),
+			EventWebhookDeliveryColumnLastError.identifier(),
+			EventWebhookDeliveryColumnState.identifier(),
+			countColumn.identifier(),
+		).From(eventWebhookDeliveriesTable.identifier() + db.Timetravel(call.Took(ctx))).
+			PlaceholderFormat(sq.Dollar), func(rows *sql.Rows) (*EventWebhookDeliveries, error) {
+			deliveries := &EventWebhookDeliveries{Deliveries: []*EventWebhookDelivery{}}
+			for rows.Next() {
+				delivery := new(EventWebhookDelivery)
+				var lastError sql.NullString
+				err := rows.Scan(
+					&delivery.WebhookID,
+					&delivery.EventSequence,
+					&delivery.EventType,
+					&delivery.CreationDate,
+					&delivery.Attempts,
+					&delivery.NextAttempt,
+					&lastError,
+					&delivery.State,
+					&deliveries.Count,
+				)
+				if err != nil {
+					return nil, errors.ThrowInternal(err, "QUERY-Wh0qr", "Errors.Internal")
+				}
+				delivery.LastError = lastError.String
+				deliveries.Deliveries = append(deliveries.Deliveries, delivery)
+			}
+			if err := rows.Close(); err != nil {
+				return nil, errors.ThrowInternal(err, "QUERY-Wh1dq", "Errors.Query.CloseRows")
+			}
+			return deliveries, nil
+		}
+}
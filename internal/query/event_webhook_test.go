@@ -0,0 +1,295 @@
+package query
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+var (
+	prepareEventWebhookStmt = `SELECT projections.event_webhooks.id,` +
+		` projections.event_webhooks.creation_date,` +
+		` projections.event_webhooks.change_date,` +
+		` projections.event_webhooks.resource_owner,` +
+		` projections.event_webhooks.sequence,` +
+		` projections.event_webhooks.name,` +
+		` projections.event_webhooks.call_url,` +
+		` projections.event_webhooks.event_types`
+	prepareEventWebhookCols = []string{
+		"id",
+		"creation_date",
+		"change_date",
+		"resource_owner",
+		"sequence",
+		"name",
+		"call_url",
+		"event_types",
+	}
+	expectedEventWebhookQuery = regexp.QuoteMeta(prepareEventWebhookStmt +
+		` FROM projections.event_webhooks` +
+		` AS OF SYSTEM TIME '-1 ms'`)
+	expectedEventWebhooksQuery = regexp.QuoteMeta(prepareEventWebhookStmt +
+		`, COUNT(*) OVER ()` +
+		` FROM projections.event_webhooks` +
+		` AS OF SYSTEM TIME '-1 ms'`)
+	prepareEventWebhooksCols = append(prepareEventWebhookCols, "count")
+
+	expectedEventWebhookDeliveriesQuery = regexp.QuoteMeta(`SELECT projections.event_webhooks_deliveries.webhook_id,` +
+		` projections.event_webhooks_deliveries.event_sequence,` +
+		` projections.event_webhooks_deliveries.event_type,` +
+		` projections.event_webhooks_deliveries.creation_date,` +
+		` projections.event_webhooks_deliveries.attempts,` +
+		` projections.event_webhooks_deliveries.next_attempt,` +
+		` projections.event_webhooks_deliveries.last_error,` +
+		` projections.event_webhooks_deliveries.state,` +
+		` COUNT(*) OVER ()` +
+		` FROM projections.event_webhooks_deliveries` +
+		` AS OF SYSTEM TIME '-1 ms'`)
+	prepareEventWebhookDeliveriesCols = []string{
+		"webhook_id",
+		"event_sequence",
+		"event_type",
+		"creation_date",
+		"attempts",
+		"next_attempt",
+		"last_error",
+		"state",
+		"count",
+	}
+)
+
+func Test_EventWebhookPrepares(t *testing.T) {
+	type want struct {
+		sqlExpectations sqlExpectation
+		err             checkErr
+	}
+	tests := []struct {
+		name    string
+		prepare interface{}
+		want    want
+		object  interface{}
+	}{
+		{
+			name:    "prepareEventWebhookQuery no result",
+			prepare: prepareEventWebhookQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					expectedEventWebhookQuery,
+					nil,
+					nil,
+				),
+				err: func(err error) (error, bool) {
+					if !errs.IsNotFound(err) {
+						return fmt.Errorf("err should be zitadel.NotFoundError got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (*EventWebhook)(nil),
+		},
+		{
+			name:    "prepareEventWebhookQuery found",
+			prepare: prepareEventWebhookQuery,
+			want: want{
+				sqlExpectations: mockQuery(
+					expectedEventWebhookQuery,
+					prepareEventWebhookCols,
+					[]driver.Value{
+						"webhook-id",
+						testNow,
+						testNow,
+						"ro",
+						uint64(20211109),
+						"name",
+						"https://example.com/hook",
+						database.StringArray{"user.human.added"},
+					},
+				),
+			},
+			object: &EventWebhook{
+				ID:            "webhook-id",
+				CreationDate:  testNow,
+				ChangeDate:    testNow,
+				ResourceOwner: "ro",
+				Sequence:      20211109,
+				Name:          "name",
+				CallURL:       "https://example.com/hook",
+				EventTypes:    database.StringArray{"user.human.added"},
+			},
+		},
+		{
+			name:    "prepareEventWebhookQuery sql err",
+			prepare: prepareEventWebhookQuery,
+			want: want{
+				sqlExpectations: mockQueryErr(
+					expectedEventWebhookQuery,
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: nil,
+		},
+		{
+			name:    "prepareEventWebhooksQuery no result",
+			prepare: prepareEventWebhooksQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					expectedEventWebhooksQuery,
+					nil,
+					nil,
+				),
+			},
+			object: &EventWebhooks{EventWebhooks: []*EventWebhook{}},
+		},
+		{
+			name:    "prepareEventWebhooksQuery multiple result",
+			prepare: prepareEventWebhooksQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					expectedEventWebhooksQuery,
+					prepareEventWebhooksCols,
+					[][]driver.Value{
+						{
+							"webhook-id",
+							testNow,
+							testNow,
+							"ro",
+							uint64(20211109),
+							"name",
+							"https://example.com/hook",
+							database.StringArray{"user.human.added"},
+						},
+						{
+							"webhook-id-2",
+							testNow,
+							testNow,
+							"ro",
+							uint64(20211110),
+							"name-2",
+							"https://example.com/other",
+							database.StringArray{"user.removed", "org.added"},
+						},
+					},
+				),
+			},
+			object: &EventWebhooks{
+				SearchResponse: SearchResponse{
+					Count: 2,
+				},
+				EventWebhooks: []*EventWebhook{
+					{
+						ID:            "webhook-id",
+						CreationDate:  testNow,
+						ChangeDate:    testNow,
+						ResourceOwner: "ro",
+						Sequence:      20211109,
+						Name:          "name",
+						CallURL:       "https://example.com/hook",
+						EventTypes:    database.StringArray{"user.human.added"},
+					},
+					{
+						ID:            "webhook-id-2",
+						CreationDate:  testNow,
+						ChangeDate:    testNow,
+						ResourceOwner: "ro",
+						Sequence:      20211110,
+						Name:          "name-2",
+						CallURL:       "https://example.com/other",
+						EventTypes:    database.StringArray{"user.removed", "org.added"},
+					},
+				},
+			},
+		},
+		{
+			name:    "prepareEventWebhookDeliveriesQuery failed deliveries",
+			prepare: prepareEventWebhookDeliveriesQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					expectedEventWebhookDeliveriesQuery,
+					prepareEventWebhookDeliveriesCols,
+					[][]driver.Value{
+						{
+							"webhook-id",
+							uint64(20211109),
+							"user.human.added",
+							testNow,
+							uint64(10),
+							testNow,
+							"status code 500",
+							domain.EventWebhookDeliveryStateFailed,
+						},
+						{
+							"webhook-id",
+							uint64(20211110),
+							"user.removed",
+							testNow,
+							uint64(0),
+							testNow,
+							nil,
+							domain.EventWebhookDeliveryStatePending,
+						},
+					},
+				),
+			},
+			object: &EventWebhookDeliveries{
+				SearchResponse: SearchResponse{
+					Count: 2,
+				},
+				Deliveries: []*EventWebhookDelivery{
+					{
+						WebhookID:     "webhook-id",
+						EventSequence: 20211109,
+						EventType:     "user.human.added",
+						CreationDate:  testNow,
+						Attempts:      10,
+						NextAttempt:   testNow,
+						LastError:     "status code 500",
+						State:         domain.EventWebhookDeliveryStateFailed,
+					},
+					{
+						WebhookID:     "webhook-id",
+						EventSequence: 20211110,
+						EventType:     "user.removed",
+						CreationDate:  testNow,
+						NextAttempt:   testNow,
+						State:         domain.EventWebhookDeliveryStatePending,
+					},
+				},
+			},
+		},
+		{
+			name:    "prepareEventWebhookDeliveriesQuery sql err",
+			prepare: prepareEventWebhookDeliveriesQuery,
+			want: want{
+				sqlExpectations: mockQueryErr(
+					expectedEventWebhookDeliveriesQuery,
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, tt.prepare, tt.object, tt.want.sqlExpectations, tt.want.err, defaultPrepareArgs...)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	errs "errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/api/call"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/query/projection"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+)
+
+// IDPClaimMappings are the claim mappings of an identity provider, applied to the user information after the authentication
+type IDPClaimMappings struct {
+	IDPID         string
+	CreationDate  time.Time
+	ChangeDate    time.Time
+	Sequence      uint64
+	ResourceOwner string
+	Mappings      []*domain.IDPClaimMapping
+}
+
+var (
+	idpClaimMappingTable = table{
+		name:          projection.IDPClaimMappingTable,
+		instanceIDCol: projection.IDPClaimMappingInstanceIDCol,
+	}
+	IDPClaimMappingIDPIDCol = Column{
+		name:  projection.IDPClaimMappingIDPIDCol,
+		table: idpClaimMappingTable,
+	}
+	IDPClaimMappingCreationDateCol = Column{
+		name:  projection.IDPClaimMappingCreationDateCol,
+		table: idpClaimMappingTable,
+	}
+	IDPClaimMappingChangeDateCol = Column{
+		name:  projection.IDPClaimMappingChangeDateCol,
+		table: idpClaimMappingTable,
+	}
+	IDPClaimMappingSequenceCol = Column{
+		name:  projection.IDPClaimMappingSequenceCol,
+		table: idpClaimMappingTable,
+	}
+	IDPClaimMappingResourceOwnerCol = Column{
+		name:  projection.IDPClaimMappingResourceOwnerCol,
+		table: idpClaimMappingTable,
+	}
+	IDPClaimMappingInstanceIDCol = Column{
+		name:  projection.IDPClaimMappingInstanceIDCol,
+		table: idpClaimMappingTable,
+	}
+	IDPClaimMappingMappingsCol = Column{
+		name:  projection.IDPClaimMappingMappingsCol,
+		table: idpClaimMappingTable,
+	}
+	IDPClaimMappingOwnerRemovedCol = Column{
+		name:  projection.IDPClaimMappingOwnerRemovedCol,
+		table: idpClaimMappingTable,
+	}
+)
+
+// IDPClaimMappingsByID returns the claim mappings of the identity provider.
+// If no mappings were set, the result contains no mappings.
+func (q *Queries) IDPClaimMappingsByID(ctx context.Context, shouldTriggerBulk bool, idpID string, withOwnerRemoved bool) (_ *IDPClaimMappings, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if shouldTriggerBulk {
+		err := projection.IDPClaimMappingProjection.Trigger(ctx)
+		logging.OnError(err).WithField("projection", idpClaimMappingTable.identifier()).Warn("could not trigger projection for query")
+	}
+
+	eq := sq.Eq{
+		IDPClaimMappingIDPIDCol.identifier():      idpID,
+		IDPClaimMappingInstanceIDCol.identifier(): authz.GetInstance(ctx).InstanceID(),
+	}
+	if !withOwnerRemoved {
+		eq[IDPClaimMappingOwnerRemovedCol.identifier()] = false
+	}
+	query, scan := prepareIDPClaimMappingsQuery(ctx, q.client)
+	stmt, args, err := query.Where(eq).ToSql()
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "QUERY-Cm2ps", "Errors.Query.SQLStatement")
+	}
+
+	row := q.client.QueryRowContext(ctx, stmt, args...)
+	mappings, err := scan(row)
+	if errors.IsNotFound(err) {
+		return &IDPClaimMappings{IDPID: idpID}, nil
+	}
+	return mappings, err
+}
+
+func prepareIDPClaimMappingsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Row) (*IDPClaimMappings, error)) {
+	return sq.Select(
+			IDPClaimMappingIDPIDCol.identifier(),
+			IDPClaimMappingCreationDateCol.identifier(),
+			IDPClaimMappingChangeDateCol.identifier(),
+			IDPClaimMappingSequenceCol.identifier(),
+			IDPClaimMappingResourceOwnerCol.identifier(),
+			IDPClaimMappingMappingsCol.identifier(),
+		).From(idpClaimMappingTable.identifier() + db.Timetravel(call.Took(ctx))).
+			PlaceholderFormat(sq.Dollar),
+		func(row *sql.Row) (*IDPClaimMappings, error) {
+			idpClaimMappings := new(IDPClaimMappings)
+			mappings := database.JSONArray[*domain.IDPClaimMapping]{}
+			err := row.Scan(
+				&idpClaimMappings.IDPID,
+				&idpClaimMappings.CreationDate,
+				&idpClaimMappings.ChangeDate,
+				&idpClaimMappings.Sequence,
+				&idpClaimMappings.ResourceOwner,
+				&mappings,
+			)
+			if err != nil {
+				if errs.Is(err, sql.ErrNoRows) {
+					return nil, errors.ThrowNotFound(err, "QUERY-Cm3pt", "Errors.IDPConfig.NotExisting")
+				}
+				return nil, errors.ThrowInternal(err, "QUERY-Cm4pu", "Errors.Internal")
+			}
+			idpClaimMappings.Mappings = mappings
+			return idpClaimMappings, nil
+		}
+}
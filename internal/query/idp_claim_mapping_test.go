@@ -0,0 +1,124 @@
+package query
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	errs "github.com/zitadel/zitadel/internal/errors"
+)
+
+var (
+	prepareIDPClaimMappingsStmt = `SELECT projections.idp_claim_mappings.idp_id,` +
+		` projections.idp_claim_mappings.creation_date,` +
+		` projections.idp_claim_mappings.change_date,` +
+		` projections.idp_claim_mappings.sequence,` +
+		` projections.idp_claim_mappings.resource_owner,` +
+		` projections.idp_claim_mappings.mappings` +
+		` FROM projections.idp_claim_mappings` +
+		` AS OF SYSTEM TIME '-1 ms'`
+	prepareIDPClaimMappingsCols = []string{
+		"idp_id",
+		"creation_date",
+		"change_date",
+		"sequence",
+		"resource_owner",
+		"mappings",
+	}
+)
+
+func Test_IDPClaimMappingsPrepares(t *testing.T) {
+	type want struct {
+		sqlExpectations sqlExpectation
+		err             checkErr
+	}
+	tests := []struct {
+		name    string
+		prepare interface{}
+		want    want
+		object  interface{}
+	}{
+		{
+			name:    "prepareIDPClaimMappingsQuery no result",
+			prepare: prepareIDPClaimMappingsQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareIDPClaimMappingsStmt),
+					nil,
+					nil,
+				),
+				err: func(err error) (error, bool) {
+					if !errs.IsNotFound(err) {
+						return fmt.Errorf("err should be zitadel.NotFoundError got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (*IDPClaimMappings)(nil),
+		},
+		{
+			name:    "prepareIDPClaimMappingsQuery found",
+			prepare: prepareIDPClaimMappingsQuery,
+			want: want{
+				sqlExpectations: mockQuery(
+					regexp.QuoteMeta(prepareIDPClaimMappingsStmt),
+					prepareIDPClaimMappingsCols,
+					[]driver.Value{
+						"idp-id",
+						testNow,
+						testNow,
+						uint64(20211108),
+						"ro",
+						[]byte(`[{"claim":"given_name","target":1},{"claim":"$.groups[*]","target":14,"key":"project-id","transforms":[1],"roles":["admin"]}]`),
+					},
+				),
+			},
+			object: &IDPClaimMappings{
+				IDPID:         "idp-id",
+				CreationDate:  testNow,
+				ChangeDate:    testNow,
+				Sequence:      20211108,
+				ResourceOwner: "ro",
+				Mappings: []*domain.IDPClaimMapping{
+					{
+						Claim:  "given_name",
+						Target: domain.IDPClaimMappingTargetFirstName,
+					},
+					{
+						Claim:      "$.groups[*]",
+						Target:     domain.IDPClaimMappingTargetRole,
+						Key:        "project-id",
+						Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformLowercase},
+						Roles:      []string{"admin"},
+					},
+				},
+			},
+		},
+		{
+			name:    "prepareIDPClaimMappingsQuery sql err",
+			prepare: prepareIDPClaimMappingsQuery,
+			want: want{
+				sqlExpectations: mockQueryErr(
+					regexp.QuoteMeta(prepareIDPClaimMappingsStmt),
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, tt.prepare, tt.object, tt.want.sqlExpectations, tt.want.err, defaultPrepareArgs...)
+		})
+	}
+}
@@ -44,6 +44,7 @@ type IDPTemplate struct {
 	*GitLabSelfHostedIDPTemplate
 	*GoogleIDPTemplate
 	*LDAPIDPTemplate
+	*SAMLIDPTemplate
 }
 
 type IDPTemplates struct {
@@ -139,6 +140,16 @@ type LDAPIDPTemplate struct {
 	UserFilters       []string
 	Timeout           time.Duration
 	idp.LDAPAttributes
+	idp.LDAPGroupSync
+}
+
+type SAMLIDPTemplate struct {
+	IDPID             string
+	Metadata          []byte
+	Key               *crypto.CryptoValue
+	Certificate       []byte
+	Binding           string
+	WithSignedRequest bool
 }
 
 var (
@@ -604,6 +615,61 @@ var (
 		name:  projection.LDAPProfileAttributeCol,
 		table: ldapIdpTemplateTable,
 	}
+	LDAPGroupsAttributeCol = Column{
+		name:  projection.LDAPGroupsAttributeCol,
+		table: ldapIdpTemplateTable,
+	}
+	LDAPGroupBaseCol = Column{
+		name:  projection.LDAPGroupBaseCol,
+		table: ldapIdpTemplateTable,
+	}
+	LDAPGroupMemberAttributeCol = Column{
+		name:  projection.LDAPGroupMemberAttributeCol,
+		table: ldapIdpTemplateTable,
+	}
+	LDAPNestedGroupsCol = Column{
+		name:  projection.LDAPNestedGroupsCol,
+		table: ldapIdpTemplateTable,
+	}
+	LDAPGroupMappingsCol = Column{
+		name:  projection.LDAPGroupMappingsCol,
+		table: ldapIdpTemplateTable,
+	}
+)
+
+var (
+	samlIdpTemplateTable = table{
+		name:          projection.IDPTemplateSAMLTable,
+		instanceIDCol: projection.SAMLInstanceIDCol,
+	}
+	SAMLIDCol = Column{
+		name:  projection.SAMLIDCol,
+		table: samlIdpTemplateTable,
+	}
+	SAMLInstanceIDCol = Column{
+		name:  projection.SAMLInstanceIDCol,
+		table: samlIdpTemplateTable,
+	}
+	SAMLMetadataCol = Column{
+		name:  projection.SAMLMetadataCol,
+		table: samlIdpTemplateTable,
+	}
+	SAMLKeyCol = Column{
+		name:  projection.SAMLKeyCol,
+		table: samlIdpTemplateTable,
+	}
+	SAMLCertificateCol = Column{
+		name:  projection.SAMLCertificateCol,
+		table: samlIdpTemplateTable,
+	}
+	SAMLBindingCol = Column{
+		name:  projection.SAMLBindingCol,
+		table: samlIdpTemplateTable,
+	}
+	SAMLWithSignedRequestCol = Column{
+		name:  projection.SAMLWithSignedRequestCol,
+		table: samlIdpTemplateTable,
+	}
 )
 
 // IDPTemplateByID searches for the requested id
@@ -799,6 +865,18 @@ func prepareIDPTemplateByIDQuery(ctx context.Context, db prepareDatabase) (sq.Se
 			LDAPPreferredLanguageAttributeCol.identifier(),
 			LDAPAvatarURLAttributeCol.identifier(),
 			LDAPProfileAttributeCol.identifier(),
+			LDAPGroupsAttributeCol.identifier(),
+			LDAPGroupBaseCol.identifier(),
+			LDAPGroupMemberAttributeCol.identifier(),
+			LDAPNestedGroupsCol.identifier(),
+			LDAPGroupMappingsCol.identifier(),
+			// saml
+			SAMLIDCol.identifier(),
+			SAMLMetadataCol.identifier(),
+			SAMLKeyCol.identifier(),
+			SAMLCertificateCol.identifier(),
+			SAMLBindingCol.identifier(),
+			SAMLWithSignedRequestCol.identifier(),
 		).From(idpTemplateTable.identifier()).
 			LeftJoin(join(OAuthIDCol, IDPTemplateIDCol)).
 			LeftJoin(join(OIDCIDCol, IDPTemplateIDCol)).
@@ -809,7 +887,8 @@ func prepareIDPTemplateByIDQuery(ctx context.Context, db prepareDatabase) (sq.Se
 			LeftJoin(join(GitLabIDCol, IDPTemplateIDCol)).
 			LeftJoin(join(GitLabSelfHostedIDCol, IDPTemplateIDCol)).
 			LeftJoin(join(GoogleIDCol, IDPTemplateIDCol)).
-			LeftJoin(join(LDAPIDCol, IDPTemplateIDCol) + db.Timetravel(call.Took(ctx))).
+			LeftJoin(join(LDAPIDCol, IDPTemplateIDCol)).
+			LeftJoin(join(SAMLIDCol, IDPTemplateIDCol) + db.Timetravel(call.Took(ctx))).
 			PlaceholderFormat(sq.Dollar),
 		func(row *sql.Row) (*IDPTemplate, error) {
 			idpTemplate := new(IDPTemplate)
@@ -897,6 +976,18 @@ func prepareIDPTemplateByIDQuery(ctx context.Context, db prepareDatabase) (sq.Se
 			ldapPreferredLanguageAttribute := sql.NullString{}
 			ldapAvatarURLAttribute := sql.NullString{}
 			ldapProfileAttribute := sql.NullString{}
+			ldapGroupsAttribute := sql.NullString{}
+			ldapGroupBase := sql.NullString{}
+			ldapGroupMemberAttribute := sql.NullString{}
+			ldapNestedGroups := sql.NullBool{}
+			ldapGroupMappings := database.JSONArray[idp.LDAPGroupMapping]{}
+
+			samlID := sql.NullString{}
+			samlMetadata := []byte{}
+			samlKey := new(crypto.CryptoValue)
+			samlCertificate := []byte{}
+			samlBinding := sql.NullString{}
+			samlWithSignedRequest := sql.NullBool{}
 
 			err := row.Scan(
 				&idpTemplate.ID,
@@ -994,6 +1085,18 @@ func prepareIDPTemplateByIDQuery(ctx context.Context, db prepareDatabase) (sq.Se
 				&ldapPreferredLanguageAttribute,
 				&ldapAvatarURLAttribute,
 				&ldapProfileAttribute,
+				&ldapGroupsAttribute,
+				&ldapGroupBase,
+				&ldapGroupMemberAttribute,
+				&ldapNestedGroups,
+				&ldapGroupMappings,
+				// saml
+				&samlID,
+				&samlMetadata,
+				&samlKey,
+				&samlCertificate,
+				&samlBinding,
+				&samlWithSignedRequest,
 			)
 			if err != nil {
 				if errs.Is(err, sql.ErrNoRows) {
@@ -1116,6 +1219,23 @@ func prepareIDPTemplateByIDQuery(ctx context.Context, db prepareDatabase) (sq.Se
 						AvatarURLAttribute:         ldapAvatarURLAttribute.String,
 						ProfileAttribute:           ldapProfileAttribute.String,
 					},
+					LDAPGroupSync: idp.LDAPGroupSync{
+						GroupsAttribute:      ldapGroupsAttribute.String,
+						GroupBase:            ldapGroupBase.String,
+						GroupMemberAttribute: ldapGroupMemberAttribute.String,
+						NestedGroups:         ldapNestedGroups.Bool,
+						GroupMappings:        ldapGroupMappings,
+					},
+				}
+			}
+			if samlID.Valid {
+				idpTemplate.SAMLIDPTemplate = &SAMLIDPTemplate{
+					IDPID:             samlID.String,
+					Metadata:          samlMetadata,
+					Key:               samlKey,
+					Certificate:       samlCertificate,
+					Binding:           samlBinding.String,
+					WithSignedRequest: samlWithSignedRequest.Bool,
 				}
 			}
 
@@ -1220,6 +1340,18 @@ func prepareIDPTemplatesQuery(ctx context.Context, db prepareDatabase) (sq.Selec
 			LDAPPreferredLanguageAttributeCol.identifier(),
 			LDAPAvatarURLAttributeCol.identifier(),
 			LDAPProfileAttributeCol.identifier(),
+			LDAPGroupsAttributeCol.identifier(),
+			LDAPGroupBaseCol.identifier(),
+			LDAPGroupMemberAttributeCol.identifier(),
+			LDAPNestedGroupsCol.identifier(),
+			LDAPGroupMappingsCol.identifier(),
+			// saml
+			SAMLIDCol.identifier(),
+			SAMLMetadataCol.identifier(),
+			SAMLKeyCol.identifier(),
+			SAMLCertificateCol.identifier(),
+			SAMLBindingCol.identifier(),
+			SAMLWithSignedRequestCol.identifier(),
 			countColumn.identifier(),
 		).From(idpTemplateTable.identifier()).
 			LeftJoin(join(OAuthIDCol, IDPTemplateIDCol)).
@@ -1231,7 +1363,8 @@ func prepareIDPTemplatesQuery(ctx context.Context, db prepareDatabase) (sq.Selec
 			LeftJoin(join(GitLabIDCol, IDPTemplateIDCol)).
 			LeftJoin(join(GitLabSelfHostedIDCol, IDPTemplateIDCol)).
 			LeftJoin(join(GoogleIDCol, IDPTemplateIDCol)).
-			LeftJoin(join(LDAPIDCol, IDPTemplateIDCol) + db.Timetravel(call.Took(ctx))).
+			LeftJoin(join(LDAPIDCol, IDPTemplateIDCol)).
+			LeftJoin(join(SAMLIDCol, IDPTemplateIDCol) + db.Timetravel(call.Took(ctx))).
 			PlaceholderFormat(sq.Dollar),
 		func(rows *sql.Rows) (*IDPTemplates, error) {
 			templates := make([]*IDPTemplate, 0)
@@ -1322,6 +1455,18 @@ func prepareIDPTemplatesQuery(ctx context.Context, db prepareDatabase) (sq.Selec
 				ldapPreferredLanguageAttribute := sql.NullString{}
 				ldapAvatarURLAttribute := sql.NullString{}
 				ldapProfileAttribute := sql.NullString{}
+				ldapGroupsAttribute := sql.NullString{}
+				ldapGroupBase := sql.NullString{}
+				ldapGroupMemberAttribute := sql.NullString{}
+				ldapNestedGroups := sql.NullBool{}
+				ldapGroupMappings := database.JSONArray[idp.LDAPGroupMapping]{}
+
+				samlID := sql.NullString{}
+				samlMetadata := []byte{}
+				samlKey := new(crypto.CryptoValue)
+				samlCertificate := []byte{}
+				samlBinding := sql.NullString{}
+				samlWithSignedRequest := sql.NullBool{}
 
 				err := rows.Scan(
 					&idpTemplate.ID,
@@ -1419,6 +1564,18 @@ func prepareIDPTemplatesQuery(ctx context.Context, db prepareDatabase) (sq.Selec
 					&ldapPreferredLanguageAttribute,
 					&ldapAvatarURLAttribute,
 					&ldapProfileAttribute,
+					&ldapGroupsAttribute,
+					&ldapGroupBase,
+					&ldapGroupMemberAttribute,
+					&ldapNestedGroups,
+					&ldapGroupMappings,
+					// saml
+					&samlID,
+					&samlMetadata,
+					&samlKey,
+					&samlCertificate,
+					&samlBinding,
+					&samlWithSignedRequest,
 					&count,
 				)
 
@@ -1540,6 +1697,23 @@ func prepareIDPTemplatesQuery(ctx context.Context, db prepareDatabase) (sq.Selec
 							AvatarURLAttribute:         ldapAvatarURLAttribute.String,
 							ProfileAttribute:           ldapProfileAttribute.String,
 						},
+						LDAPGroupSync: idp.LDAPGroupSync{
+							GroupsAttribute:      ldapGroupsAttribute.String,
+							GroupBase:            ldapGroupBase.String,
+							GroupMemberAttribute: ldapGroupMemberAttribute.String,
+							NestedGroups:         ldapNestedGroups.Bool,
+							GroupMappings:        ldapGroupMappings,
+						},
+					}
+				}
+				if samlID.Valid {
+					idpTemplate.SAMLIDPTemplate = &SAMLIDPTemplate{
+						IDPID:             samlID.String,
+						Metadata:          samlMetadata,
+						Key:               samlKey,
+						Certificate:       samlCertificate,
+						Binding:           samlBinding.String,
+						WithSignedRequest: samlWithSignedRequest.Bool,
 					}
 				}
 				templates = append(templates, idpTemplate)
@@ -16,112 +16,125 @@ import (
 )
 
 var (
-	idpTemplateQuery = `SELECT projections.idp_templates5.id,` +
-		` projections.idp_templates5.resource_owner,` +
-		` projections.idp_templates5.creation_date,` +
-		` projections.idp_templates5.change_date,` +
-		` projections.idp_templates5.sequence,` +
-		` projections.idp_templates5.state,` +
-		` projections.idp_templates5.name,` +
-		` projections.idp_templates5.type,` +
-		` projections.idp_templates5.owner_type,` +
-		` projections.idp_templates5.is_creation_allowed,` +
-		` projections.idp_templates5.is_linking_allowed,` +
-		` projections.idp_templates5.is_auto_creation,` +
-		` projections.idp_templates5.is_auto_update,` +
+	idpTemplateQuery = `SELECT projections.idp_templates6.id,` +
+		` projections.idp_templates6.resource_owner,` +
+		` projections.idp_templates6.creation_date,` +
+		` projections.idp_templates6.change_date,` +
+		` projections.idp_templates6.sequence,` +
+		` projections.idp_templates6.state,` +
+		` projections.idp_templates6.name,` +
+		` projections.idp_templates6.type,` +
+		` projections.idp_templates6.owner_type,` +
+		` projections.idp_templates6.is_creation_allowed,` +
+		` projections.idp_templates6.is_linking_allowed,` +
+		` projections.idp_templates6.is_auto_creation,` +
+		` projections.idp_templates6.is_auto_update,` +
 		// oauth
-		` projections.idp_templates5_oauth2.idp_id,` +
-		` projections.idp_templates5_oauth2.client_id,` +
-		` projections.idp_templates5_oauth2.client_secret,` +
-		` projections.idp_templates5_oauth2.authorization_endpoint,` +
-		` projections.idp_templates5_oauth2.token_endpoint,` +
-		` projections.idp_templates5_oauth2.user_endpoint,` +
-		` projections.idp_templates5_oauth2.scopes,` +
-		` projections.idp_templates5_oauth2.id_attribute,` +
+		` projections.idp_templates6_oauth2.idp_id,` +
+		` projections.idp_templates6_oauth2.client_id,` +
+		` projections.idp_templates6_oauth2.client_secret,` +
+		` projections.idp_templates6_oauth2.authorization_endpoint,` +
+		` projections.idp_templates6_oauth2.token_endpoint,` +
+		` projections.idp_templates6_oauth2.user_endpoint,` +
+		` projections.idp_templates6_oauth2.scopes,` +
+		` projections.idp_templates6_oauth2.id_attribute,` +
 		// oidc
-		` projections.idp_templates5_oidc.idp_id,` +
-		` projections.idp_templates5_oidc.issuer,` +
-		` projections.idp_templates5_oidc.client_id,` +
-		` projections.idp_templates5_oidc.client_secret,` +
-		` projections.idp_templates5_oidc.scopes,` +
-		` projections.idp_templates5_oidc.id_token_mapping,` +
+		` projections.idp_templates6_oidc.idp_id,` +
+		` projections.idp_templates6_oidc.issuer,` +
+		` projections.idp_templates6_oidc.client_id,` +
+		` projections.idp_templates6_oidc.client_secret,` +
+		` projections.idp_templates6_oidc.scopes,` +
+		` projections.idp_templates6_oidc.id_token_mapping,` +
 		// jwt
-		` projections.idp_templates5_jwt.idp_id,` +
-		` projections.idp_templates5_jwt.issuer,` +
-		` projections.idp_templates5_jwt.jwt_endpoint,` +
-		` projections.idp_templates5_jwt.keys_endpoint,` +
-		` projections.idp_templates5_jwt.header_name,` +
+		` projections.idp_templates6_jwt.idp_id,` +
+		` projections.idp_templates6_jwt.issuer,` +
+		` projections.idp_templates6_jwt.jwt_endpoint,` +
+		` projections.idp_templates6_jwt.keys_endpoint,` +
+		` projections.idp_templates6_jwt.header_name,` +
 		// azure
-		` projections.idp_templates5_azure.idp_id,` +
-		` projections.idp_templates5_azure.client_id,` +
-		` projections.idp_templates5_azure.client_secret,` +
-		` projections.idp_templates5_azure.scopes,` +
-		` projections.idp_templates5_azure.tenant,` +
-		` projections.idp_templates5_azure.is_email_verified,` +
+		` projections.idp_templates6_azure.idp_id,` +
+		` projections.idp_templates6_azure.client_id,` +
+		` projections.idp_templates6_azure.client_secret,` +
+		` projections.idp_templates6_azure.scopes,` +
+		` projections.idp_templates6_azure.tenant,` +
+		` projections.idp_templates6_azure.is_email_verified,` +
 		// github
-		` projections.idp_templates5_github.idp_id,` +
-		` projections.idp_templates5_github.client_id,` +
-		` projections.idp_templates5_github.client_secret,` +
-		` projections.idp_templates5_github.scopes,` +
+		` projections.idp_templates6_github.idp_id,` +
+		` projections.idp_templates6_github.client_id,` +
+		` projections.idp_templates6_github.client_secret,` +
+		` projections.idp_templates6_github.scopes,` +
 		// github enterprise
-		` projections.idp_templates5_github_enterprise.idp_id,` +
-		` projections.idp_templates5_github_enterprise.client_id,` +
-		` projections.idp_templates5_github_enterprise.client_secret,` +
-		` projections.idp_templates5_github_enterprise.authorization_endpoint,` +
-		` projections.idp_templates5_github_enterprise.token_endpoint,` +
-		` projections.idp_templates5_github_enterprise.user_endpoint,` +
-		` projections.idp_templates5_github_enterprise.scopes,` +
+		` projections.idp_templates6_github_enterprise.idp_id,` +
+		` projections.idp_templates6_github_enterprise.client_id,` +
+		` projections.idp_templates6_github_enterprise.client_secret,` +
+		` projections.idp_templates6_github_enterprise.authorization_endpoint,` +
+		` projections.idp_templates6_github_enterprise.token_endpoint,` +
+		` projections.idp_templates6_github_enterprise.user_endpoint,` +
+		` projections.idp_templates6_github_enterprise.scopes,` +
 		// gitlab
-		` projections.idp_templates5_gitlab.idp_id,` +
-		` projections.idp_templates5_gitlab.client_id,` +
-		` projections.idp_templates5_gitlab.client_secret,` +
-		` projections.idp_templates5_gitlab.scopes,` +
+		` projections.idp_templates6_gitlab.idp_id,` +
+		` projections.idp_templates6_gitlab.client_id,` +
+		` projections.idp_templates6_gitlab.client_secret,` +
+		` projections.idp_templates6_gitlab.scopes,` +
 		// gitlab self hosted
-		` projections.idp_templates5_gitlab_self_hosted.idp_id,` +
-		` projections.idp_templates5_gitlab_self_hosted.issuer,` +
-		` projections.idp_templates5_gitlab_self_hosted.client_id,` +
-		` projections.idp_templates5_gitlab_self_hosted.client_secret,` +
-		` projections.idp_templates5_gitlab_self_hosted.scopes,` +
+		` projections.idp_templates6_gitlab_self_hosted.idp_id,` +
+		` projections.idp_templates6_gitlab_self_hosted.issuer,` +
+		` projections.idp_templates6_gitlab_self_hosted.client_id,` +
+		` projections.idp_templates6_gitlab_self_hosted.client_secret,` +
+		` projections.idp_templates6_gitlab_self_hosted.scopes,` +
 		// google
-		` projections.idp_templates5_google.idp_id,` +
-		` projections.idp_templates5_google.client_id,` +
-		` projections.idp_templates5_google.client_secret,` +
-		` projections.idp_templates5_google.scopes,` +
+		` projections.idp_templates6_google.idp_id,` +
+		` projections.idp_templates6_google.client_id,` +
+		` projections.idp_templates6_google.client_secret,` +
+		` projections.idp_templates6_google.scopes,` +
 		// ldap
-		` projections.idp_templates5_ldap2.idp_id,` +
-		` projections.idp_templates5_ldap2.servers,` +
-		` projections.idp_templates5_ldap2.start_tls,` +
-		` projections.idp_templates5_ldap2.base_dn,` +
-		` projections.idp_templates5_ldap2.bind_dn,` +
-		` projections.idp_templates5_ldap2.bind_password,` +
-		` projections.idp_templates5_ldap2.user_base,` +
-		` projections.idp_templates5_ldap2.user_object_classes,` +
-		` projections.idp_templates5_ldap2.user_filters,` +
-		` projections.idp_templates5_ldap2.timeout,` +
-		` projections.idp_templates5_ldap2.id_attribute,` +
-		` projections.idp_templates5_ldap2.first_name_attribute,` +
-		` projections.idp_templates5_ldap2.last_name_attribute,` +
-		` projections.idp_templates5_ldap2.display_name_attribute,` +
-		` projections.idp_templates5_ldap2.nick_name_attribute,` +
-		` projections.idp_templates5_ldap2.preferred_username_attribute,` +
-		` projections.idp_templates5_ldap2.email_attribute,` +
-		` projections.idp_templates5_ldap2.email_verified,` +
-		` projections.idp_templates5_ldap2.phone_attribute,` +
-		` projections.idp_templates5_ldap2.phone_verified_attribute,` +
-		` projections.idp_templates5_ldap2.preferred_language_attribute,` +
-		` projections.idp_templates5_ldap2.avatar_url_attribute,` +
-		` projections.idp_templates5_ldap2.profile_attribute` +
-		` FROM projections.idp_templates5` +
-		` LEFT JOIN projections.idp_templates5_oauth2 ON projections.idp_templates5.id = projections.idp_templates5_oauth2.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_oauth2.instance_id` +
-		` LEFT JOIN projections.idp_templates5_oidc ON projections.idp_templates5.id = projections.idp_templates5_oidc.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_oidc.instance_id` +
-		` LEFT JOIN projections.idp_templates5_jwt ON projections.idp_templates5.id = projections.idp_templates5_jwt.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_jwt.instance_id` +
-		` LEFT JOIN projections.idp_templates5_azure ON projections.idp_templates5.id = projections.idp_templates5_azure.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_azure.instance_id` +
-		` LEFT JOIN projections.idp_templates5_github ON projections.idp_templates5.id = projections.idp_templates5_github.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_github.instance_id` +
-		` LEFT JOIN projections.idp_templates5_github_enterprise ON projections.idp_templates5.id = projections.idp_templates5_github_enterprise.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_github_enterprise.instance_id` +
-		` LEFT JOIN projections.idp_templates5_gitlab ON projections.idp_templates5.id = projections.idp_templates5_gitlab.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_gitlab.instance_id` +
-		` LEFT JOIN projections.idp_templates5_gitlab_self_hosted ON projections.idp_templates5.id = projections.idp_templates5_gitlab_self_hosted.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_gitlab_self_hosted.instance_id` +
-		` LEFT JOIN projections.idp_templates5_google ON projections.idp_templates5.id = projections.idp_templates5_google.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_google.instance_id` +
-		` LEFT JOIN projections.idp_templates5_ldap2 ON projections.idp_templates5.id = projections.idp_templates5_ldap2.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_ldap2.instance_id` +
+		` projections.idp_templates6_ldap2.idp_id,` +
+		` projections.idp_templates6_ldap2.servers,` +
+		` projections.idp_templates6_ldap2.start_tls,` +
+		` projections.idp_templates6_ldap2.base_dn,` +
+		` projections.idp_templates6_ldap2.bind_dn,` +
+		` projections.idp_templates6_ldap2.bind_password,` +
+		` projections.idp_templates6_ldap2.user_base,` +
+		` projections.idp_templates6_ldap2.user_object_classes,` +
+		` projections.idp_templates6_ldap2.user_filters,` +
+		` projections.idp_templates6_ldap2.timeout,` +
+		` projections.idp_templates6_ldap2.id_attribute,` +
+		` projections.idp_templates6_ldap2.first_name_attribute,` +
+		` projections.idp_templates6_ldap2.last_name_attribute,` +
+		` projections.idp_templates6_ldap2.display_name_attribute,` +
+		` projections.idp_templates6_ldap2.nick_name_attribute,` +
+		` projections.idp_templates6_ldap2.preferred_username_attribute,` +
+		` projections.idp_templates6_ldap2.email_attribute,` +
+		` projections.idp_templates6_ldap2.email_verified,` +
+		` projections.idp_templates6_ldap2.phone_attribute,` +
+		` projections.idp_templates6_ldap2.phone_verified_attribute,` +
+		` projections.idp_templates6_ldap2.preferred_language_attribute,` +
+		` projections.idp_templates6_ldap2.avatar_url_attribute,` +
+		` projections.idp_templates6_ldap2.profile_attribute,` +
+		` projections.idp_templates6_ldap2.groups_attribute,` +
+		` projections.idp_templates6_ldap2.group_base,` +
+		` projections.idp_templates6_ldap2.group_member_attribute,` +
+		` projections.idp_templates6_ldap2.nested_groups,` +
+		` projections.idp_templates6_ldap2.group_mappings,` +
+		// saml
+		` projections.idp_templates6_saml.idp_id,` +
+		` projections.idp_templates6_saml.metadata,` +
+		` projections.idp_templates6_saml.key,` +
+		` projections.idp_templates6_saml.certificate,` +
+		` projections.idp_templates6_saml.binding,` +
+		` projections.idp_templates6_saml.with_signed_request` +
+		` FROM projections.idp_templates6` +
+		` LEFT JOIN projections.idp_templates6_oauth2 ON projections.idp_templates6.id = projections.idp_templates6_oauth2.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_oauth2.instance_id` +
+		` LEFT JOIN projections.idp_templates6_oidc ON projections.idp_templates6.id = projections.idp_templates6_oidc.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_oidc.instance_id` +
+		` LEFT JOIN projections.idp_templates6_jwt ON projections.idp_templates6.id = projections.idp_templates6_jwt.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_jwt.instance_id` +
+		` LEFT JOIN projections.idp_templates6_azure ON projections.idp_templates6.id = projections.idp_templates6_azure.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_azure.instance_id` +
+		` LEFT JOIN projections.idp_templates6_github ON projections.idp_templates6.id = projections.idp_templates6_github.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_github.instance_id` +
+		` LEFT JOIN projections.idp_templates6_github_enterprise ON projections.idp_templates6.id = projections.idp_templates6_github_enterprise.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_github_enterprise.instance_id` +
+		` LEFT JOIN projections.idp_templates6_gitlab ON projections.idp_templates6.id = projections.idp_templates6_gitlab.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_gitlab.instance_id` +
+		` LEFT JOIN projections.idp_templates6_gitlab_self_hosted ON projections.idp_templates6.id = projections.idp_templates6_gitlab_self_hosted.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_gitlab_self_hosted.instance_id` +
+		` LEFT JOIN projections.idp_templates6_google ON projections.idp_templates6.id = projections.idp_templates6_google.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_google.instance_id` +
+		` LEFT JOIN projections.idp_templates6_ldap2 ON projections.idp_templates6.id = projections.idp_templates6_ldap2.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_ldap2.instance_id` +
+		` LEFT JOIN projections.idp_templates6_saml ON projections.idp_templates6.id = projections.idp_templates6_saml.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_saml.instance_id` +
 		` AS OF SYSTEM TIME '-1 ms'`
 	idpTemplateCols = []string{
 		"id",
@@ -219,114 +232,139 @@ var (
 		"preferred_language_attribute",
 		"avatar_url_attribute",
 		"profile_attribute",
+		"groups_attribute",
+		"group_base",
+		"group_member_attribute",
+		"nested_groups",
+		"group_mappings",
+		// saml config
+		"idp_id",
+		"metadata",
+		"key",
+		"certificate",
+		"binding",
+		"with_signed_request",
 	}
-	idpTemplatesQuery = `SELECT projections.idp_templates5.id,` +
-		` projections.idp_templates5.resource_owner,` +
-		` projections.idp_templates5.creation_date,` +
-		` projections.idp_templates5.change_date,` +
-		` projections.idp_templates5.sequence,` +
-		` projections.idp_templates5.state,` +
-		` projections.idp_templates5.name,` +
-		` projections.idp_templates5.type,` +
-		` projections.idp_templates5.owner_type,` +
-		` projections.idp_templates5.is_creation_allowed,` +
-		` projections.idp_templates5.is_linking_allowed,` +
-		` projections.idp_templates5.is_auto_creation,` +
-		` projections.idp_templates5.is_auto_update,` +
+	idpTemplatesQuery = `SELECT projections.idp_templates6.id,` +
+		` projections.idp_templates6.resource_owner,` +
+		` projections.idp_templates6.creation_date,` +
+		` projections.idp_templates6.change_date,` +
+		` projections.idp_templates6.sequence,` +
+		` projections.idp_templates6.state,` +
+		` projections.idp_templates6.name,` +
+		` projections.idp_templates6.type,` +
+		` projections.idp_templates6.owner_type,` +
+		` projections.idp_templates6.is_creation_allowed,` +
+		` projections.idp_templates6.is_linking_allowed,` +
+		` projections.idp_templates6.is_auto_creation,` +
+		` projections.idp_templates6.is_auto_update,` +
 		// oauth
-		` projections.idp_templates5_oauth2.idp_id,` +
-		` projections.idp_templates5_oauth2.client_id,` +
-		` projections.idp_templates5_oauth2.client_secret,` +
-		` projections.idp_templates5_oauth2.authorization_endpoint,` +
-		` projections.idp_templates5_oauth2.token_endpoint,` +
-		` projections.idp_templates5_oauth2.user_endpoint,` +
-		` projections.idp_templates5_oauth2.scopes,` +
-		` projections.idp_templates5_oauth2.id_attribute,` +
+		` projections.idp_templates6_oauth2.idp_id,` +
+		` projections.idp_templates6_oauth2.client_id,` +
+		` projections.idp_templates6_oauth2.client_secret,` +
+		` projections.idp_templates6_oauth2.authorization_endpoint,` +
+		` projections.idp_templates6_oauth2.token_endpoint,` +
+		` projections.idp_templates6_oauth2.user_endpoint,` +
+		` projections.idp_templates6_oauth2.scopes,` +
+		` projections.idp_templates6_oauth2.id_attribute,` +
 		// oidc
-		` projections.idp_templates5_oidc.idp_id,` +
-		` projections.idp_templates5_oidc.issuer,` +
-		` projections.idp_templates5_oidc.client_id,` +
-		` projections.idp_templates5_oidc.client_secret,` +
-		` projections.idp_templates5_oidc.scopes,` +
-		` projections.idp_templates5_oidc.id_token_mapping,` +
+		` projections.idp_templates6_oidc.idp_id,` +
+		` projections.idp_templates6_oidc.issuer,` +
+		` projections.idp_templates6_oidc.client_id,` +
+		` projections.idp_templates6_oidc.client_secret,` +
+		` projections.idp_templates6_oidc.scopes,` +
+		` projections.idp_templates6_oidc.id_token_mapping,` +
 		// jwt
-		` projections.idp_templates5_jwt.idp_id,` +
-		` projections.idp_templates5_jwt.issuer,` +
-		` projections.idp_templates5_jwt.jwt_endpoint,` +
-		` projections.idp_templates5_jwt.keys_endpoint,` +
-		` projections.idp_templates5_jwt.header_name,` +
+		` projections.idp_templates6_jwt.idp_id,` +
+		` projections.idp_templates6_jwt.issuer,` +
+		` projections.idp_templates6_jwt.jwt_endpoint,` +
+		` projections.idp_templates6_jwt.keys_endpoint,` +
+		` projections.idp_templates6_jwt.header_name,` +
 		// azure
-		` projections.idp_templates5_azure.idp_id,` +
-		` projections.idp_templates5_azure.client_id,` +
-		` projections.idp_templates5_azure.client_secret,` +
-		` projections.idp_templates5_azure.scopes,` +
-		` projections.idp_templates5_azure.tenant,` +
-		` projections.idp_templates5_azure.is_email_verified,` +
+		` projections.idp_templates6_azure.idp_id,` +
+		` projections.idp_templates6_azure.client_id,` +
+		` projections.idp_templates6_azure.client_secret,` +
+		` projections.idp_templates6_azure.scopes,` +
+		` projections.idp_templates6_azure.tenant,` +
+		` projections.idp_templates6_azure.is_email_verified,` +
 		// github
-		` projections.idp_templates5_github.idp_id,` +
-		` projections.idp_templates5_github.client_id,` +
-		` projections.idp_templates5_github.client_secret,` +
-		` projections.idp_templates5_github.scopes,` +
+		` projections.idp_templates6_github.idp_id,` +
+		` projections.idp_templates6_github.client_id,` +
+		` projections.idp_templates6_github.client_secret,` +
+		` projections.idp_templates6_github.scopes,` +
 		// github enterprise
-		` projections.idp_templates5_github_enterprise.idp_id,` +
-		` projections.idp_templates5_github_enterprise.client_id,` +
-		` projections.idp_templates5_github_enterprise.client_secret,` +
-		` projections.idp_templates5_github_enterprise.authorization_endpoint,` +
-		` projections.idp_templates5_github_enterprise.token_endpoint,` +
-		` projections.idp_templates5_github_enterprise.user_endpoint,` +
-		` projections.idp_templates5_github_enterprise.scopes,` +
+		` projections.idp_templates6_github_enterprise.idp_id,` +
+		` projections.idp_templates6_github_enterprise.client_id,` +
+		` projections.idp_templates6_github_enterprise.client_secret,` +
+		` projections.idp_templates6_github_enterprise.authorization_endpoint,` +
+		` projections.idp_templates6_github_enterprise.token_endpoint,` +
+		` projections.idp_templates6_github_enterprise.user_endpoint,` +
+		` projections.idp_templates6_github_enterprise.scopes,` +
 		// gitlab
-		` projections.idp_templates5_gitlab.idp_id,` +
-		` projections.idp_templates5_gitlab.client_id,` +
-		` projections.idp_templates5_gitlab.client_secret,` +
-		` projections.idp_templates5_gitlab.scopes,` +
+		` projections.idp_templates6_gitlab.idp_id,` +
+		` projections.idp_templates6_gitlab.client_id,` +
+		` projections.idp_templates6_gitlab.client_secret,` +
+		` projections.idp_templates6_gitlab.scopes,` +
 		// gitlab self hosted
-		` projections.idp_templates5_gitlab_self_hosted.idp_id,` +
-		` projections.idp_templates5_gitlab_self_hosted.issuer,` +
-		` projections.idp_templates5_gitlab_self_hosted.client_id,` +
-		` projections.idp_templates5_gitlab_self_hosted.client_secret,` +
-		` projections.idp_templates5_gitlab_self_hosted.scopes,` +
+		` projections.idp_templates6_gitlab_self_hosted.idp_id,` +
+		` projections.idp_templates6_gitlab_self_hosted.issuer,` +
+		` projections.idp_templates6_gitlab_self_hosted.client_id,` +
+		` projections.idp_templates6_gitlab_self_hosted.client_secret,` +
+		` projections.idp_templates6_gitlab_self_hosted.scopes,` +
 		// google
-		` projections.idp_templates5_google.idp_id,` +
-		` projections.idp_templates5_google.client_id,` +
-		` projections.idp_templates5_google.client_secret,` +
-		` projections.idp_templates5_google.scopes,` +
+		` projections.idp_templates6_google.idp_id,` +
+		` projections.idp_templates6_google.client_id,` +
+		` projections.idp_templates6_google.client_secret,` +
+		` projections.idp_templates6_google.scopes,` +
 		// ldap
-		` projections.idp_templates5_ldap2.idp_id,` +
-		` projections.idp_templates5_ldap2.servers,` +
-		` projections.idp_templates5_ldap2.start_tls,` +
-		` projections.idp_templates5_ldap2.base_dn,` +
-		` projections.idp_templates5_ldap2.bind_dn,` +
-		` projections.idp_templates5_ldap2.bind_password,` +
-		` projections.idp_templates5_ldap2.user_base,` +
-		` projections.idp_templates5_ldap2.user_object_classes,` +
-		` projections.idp_templates5_ldap2.user_filters,` +
-		` projections.idp_templates5_ldap2.timeout,` +
-		` projections.idp_templates5_ldap2.id_attribute,` +
-		` projections.idp_templates5_ldap2.first_name_attribute,` +
-		` projections.idp_templates5_ldap2.last_name_attribute,` +
-		` projections.idp_templates5_ldap2.display_name_attribute,` +
-		` projections.idp_templates5_ldap2.nick_name_attribute,` +
-		` projections.idp_templates5_ldap2.preferred_username_attribute,` +
-		` projections.idp_templates5_ldap2.email_attribute,` +
-		` projections.idp_templates5_ldap2.email_verified,` +
-		` projections.idp_templates5_ldap2.phone_attribute,` +
-		` projections.idp_templates5_ldap2.phone_verified_attribute,` +
-		` projections.idp_templates5_ldap2.preferred_language_attribute,` +
-		` projections.idp_templates5_ldap2.avatar_url_attribute,` +
-		` projections.idp_templates5_ldap2.profile_attribute,` +
+		` projections.idp_templates6_ldap2.idp_id,` +
+		` projections.idp_templates6_ldap2.servers,` +
+		` projections.idp_templates6_ldap2.start_tls,` +
+		` projections.idp_templates6_ldap2.base_dn,` +
+		` projections.idp_templates6_ldap2.bind_dn,` +
+		` projections.idp_templates6_ldap2.bind_password,` +
+		` projections.idp_templates6_ldap2.user_base,` +
+		` projections.idp_templates6_ldap2.user_object_classes,` +
+		` projections.idp_templates6_ldap2.user_filters,` +
+		` projections.idp_templates6_ldap2.timeout,` +
+		` projections.idp_templates6_ldap2.id_attribute,` +
+		` projections.idp_templates6_ldap2.first_name_attribute,` +
+		` projections.idp_templates6_ldap2.last_name_attribute,` +
+		` projections.idp_templates6_ldap2.display_name_attribute,` +
+		` projections.idp_templates6_ldap2.nick_name_attribute,` +
+		` projections.idp_templates6_ldap2.preferred_username_attribute,` +
+		` projections.idp_templates6_ldap2.email_attribute,` +
+		` projections.idp_templates6_ldap2.email_verified,` +
+		` projections.idp_templates6_ldap2.phone_attribute,` +
+		` projections.idp_templates6_ldap2.phone_verified_attribute,` +
+		` projections.idp_templates6_ldap2.preferred_language_attribute,` +
+		` projections.idp_templates6_ldap2.avatar_url_attribute,` +
+		` projections.idp_templates6_ldap2.profile_attribute,` +
+		` projections.idp_templates6_ldap2.groups_attribute,` +
+		` projections.idp_templates6_ldap2.group_base,` +
+		` projections.idp_templates6_ldap2.group_member_attribute,` +
+		` projections.idp_templates6_ldap2.nested_groups,` +
+		` projections.idp_templates6_ldap2.group_mappings,` +
+		// saml
+		` projections.idp_templates6_saml.idp_id,` +
+		` projections.idp_templates6_saml.metadata,` +
+		` projections.idp_templates6_saml.key,` +
+		` projections.idp_templates6_saml.certificate,` +
+		` projections.idp_templates6_saml.binding,` +
+		` projections.idp_templates6_saml.with_signed_request,` +
 		` COUNT(*) OVER ()` +
-		` FROM projections.idp_templates5` +
-		` LEFT JOIN projections.idp_templates5_oauth2 ON projections.idp_templates5.id = projections.idp_templates5_oauth2.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_oauth2.instance_id` +
-		` LEFT JOIN projections.idp_templates5_oidc ON projections.idp_templates5.id = projections.idp_templates5_oidc.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_oidc.instance_id` +
-		` LEFT JOIN projections.idp_templates5_jwt ON projections.idp_templates5.id = projections.idp_templates5_jwt.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_jwt.instance_id` +
-		` LEFT JOIN projections.idp_templates5_azure ON projections.idp_templates5.id = projections.idp_templates5_azure.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_azure.instance_id` +
-		` LEFT JOIN projections.idp_templates5_github ON projections.idp_templates5.id = projections.idp_templates5_github.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_github.instance_id` +
-		` LEFT JOIN projections.idp_templates5_github_enterprise ON projections.idp_templates5.id = projections.idp_templates5_github_enterprise.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_github_enterprise.instance_id` +
-		` LEFT JOIN projections.idp_templates5_gitlab ON projections.idp_templates5.id = projections.idp_templates5_gitlab.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_gitlab.instance_id` +
-		` LEFT JOIN projections.idp_templates5_gitlab_self_hosted ON projections.idp_templates5.id = projections.idp_templates5_gitlab_self_hosted.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_gitlab_self_hosted.instance_id` +
-		` LEFT JOIN projections.idp_templates5_google ON projections.idp_templates5.id = projections.idp_templates5_google.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_google.instance_id` +
-		` LEFT JOIN projections.idp_templates5_ldap2 ON projections.idp_templates5.id = projections.idp_templates5_ldap2.idp_id AND projections.idp_templates5.instance_id = projections.idp_templates5_ldap2.instance_id` +
+		` FROM projections.idp_templates6` +
+		` LEFT JOIN projections.idp_templates6_oauth2 ON projections.idp_templates6.id = projections.idp_templates6_oauth2.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_oauth2.instance_id` +
+		` LEFT JOIN projections.idp_templates6_oidc ON projections.idp_templates6.id = projections.idp_templates6_oidc.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_oidc.instance_id` +
+		` LEFT JOIN projections.idp_templates6_jwt ON projections.idp_templates6.id = projections.idp_templates6_jwt.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_jwt.instance_id` +
+		` LEFT JOIN projections.idp_templates6_azure ON projections.idp_templates6.id = projections.idp_templates6_azure.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_azure.instance_id` +
+		` LEFT JOIN projections.idp_templates6_github ON projections.idp_templates6.id = projections.idp_templates6_github.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_github.instance_id` +
+		` LEFT JOIN projections.idp_templates6_github_enterprise ON projections.idp_templates6.id = projections.idp_templates6_github_enterprise.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_github_enterprise.instance_id` +
+		` LEFT JOIN projections.idp_templates6_gitlab ON projections.idp_templates6.id = projections.idp_templates6_gitlab.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_gitlab.instance_id` +
+		` LEFT JOIN projections.idp_templates6_gitlab_self_hosted ON projections.idp_templates6.id = projections.idp_templates6_gitlab_self_hosted.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_gitlab_self_hosted.instance_id` +
+		` LEFT JOIN projections.idp_templates6_google ON projections.idp_templates6.id = projections.idp_templates6_google.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_google.instance_id` +
+		` LEFT JOIN projections.idp_templates6_ldap2 ON projections.idp_templates6.id = projections.idp_templates6_ldap2.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_ldap2.instance_id` +
+		` LEFT JOIN projections.idp_templates6_saml ON projections.idp_templates6.id = projections.idp_templates6_saml.idp_id AND projections.idp_templates6.instance_id = projections.idp_templates6_saml.instance_id` +
 		` AS OF SYSTEM TIME '-1 ms'`
 	idpTemplatesCols = []string{
 		"id",
@@ -424,6 +462,18 @@ var (
 		"preferred_language_attribute",
 		"avatar_url_attribute",
 		"profile_attribute",
+		"groups_attribute",
+		"group_base",
+		"group_member_attribute",
+		"nested_groups",
+		"group_mappings",
+		// saml config
+		"idp_id",
+		"metadata",
+		"key",
+		"certificate",
+		"binding",
+		"with_signed_request",
 		"count",
 	}
 )
@@ -560,6 +610,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 						nil,
 						nil,
 						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// saml config
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
 					},
 				),
 			},
@@ -692,6 +754,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 						nil,
 						nil,
 						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// saml config
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
 					},
 				),
 			},
@@ -822,6 +896,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 						nil,
 						nil,
 						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// saml config
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
 					},
 				),
 			},
@@ -951,6 +1037,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 						nil,
 						nil,
 						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// saml config
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
 					},
 				),
 			},
@@ -1079,6 +1177,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 						nil,
 						nil,
 						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// saml config
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
 					},
 				),
 			},
@@ -1207,6 +1317,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 						nil,
 						nil,
 						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// saml config
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
 					},
 				),
 			},
@@ -1336,6 +1458,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 						nil,
 						nil,
 						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// saml config
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
 					},
 				),
 			},
@@ -1464,6 +1598,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 						"lang",
 						"avatar",
 						"profile",
+						"memberOf",
+						"ou=groups",
+						"member",
+						true,
+						[]byte(`[{"group": "cn=admins", "projectId": "project-id", "roles": ["admin"]}]`),
+						// saml config
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
 					},
 				),
 			},
@@ -1506,6 +1652,157 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 						AvatarURLAttribute:         "avatar",
 						ProfileAttribute:           "profile",
 					},
+					LDAPGroupSync: idp.LDAPGroupSync{
+						GroupsAttribute:      "memberOf",
+						GroupBase:            "ou=groups",
+						GroupMemberAttribute: "member",
+						NestedGroups:         true,
+						GroupMappings: []idp.LDAPGroupMapping{
+							{Group: "cn=admins", ProjectID: "project-id", Roles: []string{"admin"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "prepareIDPTemplateByIDQuery saml idp",
+			prepare: prepareIDPTemplateByIDQuery,
+			want: want{
+				sqlExpectations: mockQuery(
+					regexp.QuoteMeta(idpTemplateQuery),
+					idpTemplateCols,
+					[]driver.Value{
+						"idp-id",
+						"ro",
+						testNow,
+						testNow,
+						uint64(20211109),
+						domain.IDPConfigStateActive,
+						"idp-name",
+						domain.IDPTypeSAML,
+						domain.IdentityProviderTypeOrg,
+						true,
+						true,
+						true,
+						true,
+						// oauth
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// oidc
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// jwt
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// azure
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// github
+						nil,
+						nil,
+						nil,
+						nil,
+						// github enterprise
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// gitlab
+						nil,
+						nil,
+						nil,
+						nil,
+						// gitlab self hosted
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// google config
+						nil,
+						nil,
+						nil,
+						nil,
+						// ldap config
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// saml config
+						"idp-id",
+						[]byte("metadata"),
+						nil,
+						[]byte("certificate"),
+						"binding",
+						true,
+					},
+				),
+			},
+			object: &IDPTemplate{
+				CreationDate:      testNow,
+				ChangeDate:        testNow,
+				Sequence:          20211109,
+				ResourceOwner:     "ro",
+				ID:                "idp-id",
+				State:             domain.IDPStateActive,
+				Name:              "idp-name",
+				Type:              domain.IDPTypeSAML,
+				OwnerType:         domain.IdentityProviderTypeOrg,
+				IsCreationAllowed: true,
+				IsLinkingAllowed:  true,
+				IsAutoCreation:    true,
+				IsAutoUpdate:      true,
+				SAMLIDPTemplate: &SAMLIDPTemplate{
+					IDPID:             "idp-id",
+					Metadata:          []byte("metadata"),
+					Key:               nil,
+					Certificate:       []byte("certificate"),
+					Binding:           "binding",
+					WithSignedRequest: true,
 				},
 			},
 		},
@@ -1612,6 +1909,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 						nil,
 						nil,
 						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						// saml config
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
 					},
 				),
 			},
@@ -1770,6 +2079,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 							"lang",
 							"avatar",
 							"profile",
+							"memberOf",
+							"ou=groups",
+							"member",
+							true,
+							[]byte(`[{"group": "cn=admins", "projectId": "project-id", "roles": ["admin"]}]`),
+							// saml config
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
 						},
 					},
 				),
@@ -1818,6 +2139,15 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 								AvatarURLAttribute:         "avatar",
 								ProfileAttribute:           "profile",
 							},
+							LDAPGroupSync: idp.LDAPGroupSync{
+								GroupsAttribute:      "memberOf",
+								GroupBase:            "ou=groups",
+								GroupMemberAttribute: "member",
+								NestedGroups:         true,
+								GroupMappings: []idp.LDAPGroupMapping{
+									{Group: "cn=admins", ProjectID: "project-id", Roles: []string{"admin"}},
+								},
+							},
 						},
 					},
 				},
@@ -1927,6 +2257,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 							nil,
 							nil,
 							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							// saml config
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
 						},
 					},
 				),
@@ -2058,6 +2400,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 							"lang",
 							"avatar",
 							"profile",
+							"memberOf",
+							"ou=groups",
+							"member",
+							true,
+							[]byte(`[{"group": "cn=admins", "projectId": "project-id", "roles": ["admin"]}]`),
+							// saml config
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
 						},
 						{
 							"idp-id-google",
@@ -2155,6 +2509,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 							nil,
 							nil,
 							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							// saml config
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
 						},
 						{
 							"idp-id-oauth",
@@ -2252,6 +2618,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 							nil,
 							nil,
 							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							// saml config
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
 						},
 						{
 							"idp-id-oidc",
@@ -2349,6 +2727,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 							nil,
 							nil,
 							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							// saml config
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
 						},
 						{
 							"idp-id-jwt",
@@ -2446,6 +2836,18 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 							nil,
 							nil,
 							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							// saml config
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
+							nil,
 						},
 					},
 				),
@@ -2494,6 +2896,15 @@ func Test_IDPTemplateTemplatesPrepares(t *testing.T) {
 								AvatarURLAttribute:         "avatar",
 								ProfileAttribute:           "profile",
 							},
+							LDAPGroupSync: idp.LDAPGroupSync{
+								GroupsAttribute:      "memberOf",
+								GroupBase:            "ou=groups",
+								GroupMemberAttribute: "member",
+								NestedGroups:         true,
+								GroupMappings: []idp.LDAPGroupMapping{
+									{Group: "cn=admins", ProjectID: "project-id", Roles: []string{"admin"}},
+								},
+							},
 						},
 					},
 					{
@@ -2,7 +2,6 @@ package query
 
 import (
 	"context"
-	"crypto/rsa"
 	"database/sql"
 	"time"
 
@@ -86,17 +85,17 @@ func (k *privateKey) Key() *crypto.CryptoValue {
 	return k.privateKey
 }
 
-type rsaPublicKey struct {
+type publicKey struct {
 	key
 	expiry    time.Time
-	publicKey *rsa.PublicKey
+	publicKey interface{}
 }
 
-func (r *rsaPublicKey) Expiry() time.Time {
+func (r *publicKey) Expiry() time.Time {
 	return r.expiry
 }
 
-func (r *rsaPublicKey) Key() interface{} {
+func (r *publicKey) Key() interface{} {
 	return r.publicKey
 }
 
@@ -263,7 +262,7 @@ func preparePublicKeysQuery(ctx context.Context, db prepareDatabase) (sq.SelectB
 			keys := make([]PublicKey, 0)
 			var count uint64
 			for rows.Next() {
-				k := new(rsaPublicKey)
+				k := new(publicKey)
 				var keyValue []byte
 				err := rows.Scan(
 					&k.id,
@@ -280,7 +279,7 @@ func preparePublicKeysQuery(ctx context.Context, db prepareDatabase) (sq.SelectB
 				if err != nil {
 					return nil, err
 				}
-				k.publicKey, err = crypto.BytesToPublicKey(keyValue)
+				k.publicKey, err = crypto.BytesToPublicSigningKey(keyValue)
 				if err != nil {
 					return nil, err
 				}
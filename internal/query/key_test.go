@@ -113,7 +113,7 @@ func Test_KeyPrepares(t *testing.T) {
 					Count: 1,
 				},
 				Keys: []PublicKey{
-					&rsaPublicKey{
+					&publicKey{
 						key: key{
 							id:            "key-id",
 							creationDate:  testNow,
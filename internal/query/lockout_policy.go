@@ -26,6 +26,11 @@ type LockoutPolicy struct {
 
 	MaxPasswordAttempts uint64
 	ShowFailures        bool
+	MaxOTPAttempts      uint64
+	MaxPasskeyAttempts  uint64
+	MaxIPAttempts       uint64
+	LockoutDuration     time.Duration
+	ProgressiveDelay    time.Duration
 
 	IsDefault bool
 }
@@ -67,6 +72,26 @@ var (
 		name:  projection.LockoutPolicyMaxPasswordAttemptsCol,
 		table: lockoutTable,
 	}
+	LockoutColMaxOTPAttempts = Column{
+		name:  projection.LockoutPolicyMaxOTPAttemptsCol,
+		table: lockoutTable,
+	}
+	LockoutColMaxPasskeyAttempts = Column{
+		name:  projection.LockoutPolicyMaxPasskeyAttemptsCol,
+		table: lockoutTable,
+	}
+	LockoutColMaxIPAttempts = Column{
+		name:  projection.LockoutPolicyMaxIPAttemptsCol,
+		table: lockoutTable,
+	}
+	LockoutColLockoutDuration = Column{
+		name:  projection.LockoutPolicyLockoutDurationCol,
+		table: lockoutTable,
+	}
+	LockoutColProgressiveDelay = Column{
+		name:  projection.LockoutPolicyProgressiveDelayCol,
+		table: lockoutTable,
+	}
 	LockoutColIsDefault = Column{
 		name:  projection.LockoutPolicyIsDefaultCol,
 		table: lockoutTable,
@@ -142,6 +167,11 @@ func prepareLockoutPolicyQuery(ctx context.Context, db prepareDatabase) (sq.Sele
 			LockoutColResourceOwner.identifier(),
 			LockoutColShowFailures.identifier(),
 			LockoutColMaxPasswordAttempts.identifier(),
+			LockoutColMaxOTPAttempts.identifier(),
+			LockoutColMaxPasskeyAttempts.identifier(),
+			LockoutColMaxIPAttempts.identifier(),
+			LockoutColLockoutDuration.identifier(),
+			LockoutColProgressiveDelay.identifier(),
 			LockoutColIsDefault.identifier(),
 			LockoutColState.identifier(),
 		).
@@ -157,6 +187,11 @@ func prepareLockoutPolicyQuery(ctx context.Context, db prepareDatabase) (sq.Sele
 				&policy.ResourceOwner,
 				&policy.ShowFailures,
 				&policy.MaxPasswordAttempts,
+				&policy.MaxOTPAttempts,
+				&policy.MaxPasskeyAttempts,
+				&policy.MaxIPAttempts,
+				&policy.LockoutDuration,
+				&policy.ProgressiveDelay,
 				&policy.IsDefault,
 				&policy.State,
 			)
@@ -7,22 +7,28 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/zitadel/zitadel/internal/domain"
 	errs "github.com/zitadel/zitadel/internal/errors"
 )
 
 var (
-	prepareLockoutPolicyStmt = `SELECT projections.lockout_policies2.id,` +
-		` projections.lockout_policies2.sequence,` +
-		` projections.lockout_policies2.creation_date,` +
-		` projections.lockout_policies2.change_date,` +
-		` projections.lockout_policies2.resource_owner,` +
-		` projections.lockout_policies2.show_failure,` +
-		` projections.lockout_policies2.max_password_attempts,` +
-		` projections.lockout_policies2.is_default,` +
-		` projections.lockout_policies2.state` +
-		` FROM projections.lockout_policies2` +
+	prepareLockoutPolicyStmt = `SELECT projections.lockout_policies3.id,` +
+		` projections.lockout_policies3.sequence,` +
+		` projections.lockout_policies3.creation_date,` +
+		` projections.lockout_policies3.change_date,` +
+		` projections.lockout_policies3.resource_owner,` +
+		` projections.lockout_policies3.show_failure,` +
+		` projections.lockout_policies3.max_password_attempts,` +
+		` projections.lockout_policies3.max_otp_attempts,` +
+		` projections.lockout_policies3.max_passkey_attempts,` +
+		` projections.lockout_policies3.max_ip_attempts,` +
+		` projections.lockout_policies3.lockout_duration,` +
+		` projections.lockout_policies3.progressive_delay,` +
+		` projections.lockout_policies3.is_default,` +
+		` projections.lockout_policies3.state` +
+		` FROM projections.lockout_policies3` +
 		` AS OF SYSTEM TIME '-1 ms'`
 
 	prepareLockoutPolicyCols = []string{
@@ -33,6 +39,11 @@ var (
 		"resource_owner",
 		"show_failure",
 		"max_password_attempts",
+		"max_otp_attempts",
+		"max_passkey_attempts",
+		"max_ip_attempts",
+		"lockout_duration",
+		"progressive_delay",
 		"is_default",
 		"state",
 	}
@@ -82,6 +93,11 @@ func Test_LockoutPolicyPrepares(t *testing.T) {
 						"ro",
 						true,
 						20,
+						5,
+						10,
+						50,
+						time.Minute * 15,
+						time.Second,
 						true,
 						domain.PolicyStateActive,
 					},
@@ -96,6 +112,11 @@ func Test_LockoutPolicyPrepares(t *testing.T) {
 				State:               domain.PolicyStateActive,
 				ShowFailures:        true,
 				MaxPasswordAttempts: 20,
+				MaxOTPAttempts:      5,
+				MaxPasskeyAttempts:  10,
+				MaxIPAttempts:       50,
+				LockoutDuration:     time.Minute * 15,
+				ProgressiveDelay:    time.Second,
 				IsDefault:           true,
 			},
 		},
@@ -26,6 +26,8 @@ type PasswordAgePolicy struct {
 
 	ExpireWarnDays uint64
 	MaxAgeDays     uint64
+	MinAgeDays     uint64
+	HistoryCount   uint64
 
 	IsDefault bool
 }
@@ -67,6 +69,14 @@ var (
 		name:  projection.AgePolicyMaxAgeDaysCol,
 		table: passwordAgeTable,
 	}
+	PasswordAgeColMinAge = Column{
+		name:  projection.AgePolicyMinAgeDaysCol,
+		table: passwordAgeTable,
+	}
+	PasswordAgeColHistoryCount = Column{
+		name:  projection.AgePolicyHistoryCountCol,
+		table: passwordAgeTable,
+	}
 	PasswordAgeColIsDefault = Column{
 		name:  projection.AgePolicyIsDefaultCol,
 		table: passwordAgeTable,
@@ -142,6 +152,8 @@ func preparePasswordAgePolicyQuery(ctx context.Context, db prepareDatabase) (sq.
 			PasswordAgeColResourceOwner.identifier(),
 			PasswordAgeColWarnDays.identifier(),
 			PasswordAgeColMaxAge.identifier(),
+			PasswordAgeColMinAge.identifier(),
+			PasswordAgeColHistoryCount.identifier(),
 			PasswordAgeColIsDefault.identifier(),
 			PasswordAgeColState.identifier(),
 		).
@@ -157,6 +169,8 @@ func preparePasswordAgePolicyQuery(ctx context.Context, db prepareDatabase) (sq.
 				&policy.ResourceOwner,
 				&policy.ExpireWarnDays,
 				&policy.MaxAgeDays,
+				&policy.MinAgeDays,
+				&policy.HistoryCount,
 				&policy.IsDefault,
 				&policy.State,
 			)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/api/call"
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/errors"
 	"github.com/zitadel/zitadel/internal/query/projection"
@@ -24,11 +25,20 @@ type PasswordComplexityPolicy struct {
 	ResourceOwner string
 	State         domain.PolicyState
 
-	MinLength    uint64
-	HasLowercase bool
-	HasUppercase bool
-	HasNumber    bool
-	HasSymbol    bool
+	MinLength      uint64
+	HasLowercase   bool
+	HasUppercase   bool
+	HasNumber      bool
+	HasSymbol      bool
+	RejectBreached bool
+	MaxLength      uint64
+
+	ForbidUsername       bool
+	ForbidEmailLocalPart bool
+	ForbidOrgName        bool
+
+	Rules       database.JSONArray[domain.PasswordRule]
+	BannedWords database.StringArray
 
 	IsDefault bool
 }
@@ -135,6 +145,34 @@ var (
 		name:  projection.ComplexityPolicyHasSymbolCol,
 		table: passwordComplexityTable,
 	}
+	PasswordComplexityColRejectBreached = Column{
+		name:  projection.ComplexityPolicyRejectBreachedCol,
+		table: passwordComplexityTable,
+	}
+	PasswordComplexityColMaxLength = Column{
+		name:  projection.ComplexityPolicyMaxLengthCol,
+		table: passwordComplexityTable,
+	}
+	PasswordComplexityColForbidUsername = Column{
+		name:  projection.ComplexityPolicyForbidUsernameCol,
+		table: passwordComplexityTable,
+	}
+	PasswordComplexityColForbidEmailLocalPart = Column{
+		name:  projection.ComplexityPolicyForbidEmailLocalPartCol,
+		table: passwordComplexityTable,
+	}
+	PasswordComplexityColForbidOrgName = Column{
+		name:  projection.ComplexityPolicyForbidOrgNameCol,
+		table: passwordComplexityTable,
+	}
+	PasswordComplexityColRules = Column{
+		name:  projection.ComplexityPolicyRulesCol,
+		table: passwordComplexityTable,
+	}
+	PasswordComplexityColBannedWords = Column{
+		name:  projection.ComplexityPolicyBannedWordsCol,
+		table: passwordComplexityTable,
+	}
 	PasswordComplexityColIsDefault = Column{
 		name:  projection.ComplexityPolicyIsDefaultCol,
 		table: passwordComplexityTable,
@@ -161,6 +199,13 @@ func preparePasswordComplexityPolicyQuery(ctx context.Context, db prepareDatabas
 			PasswordComplexityColHasUpperCase.identifier(),
 			PasswordComplexityColHasNumber.identifier(),
 			PasswordComplexityColHasSymbol.identifier(),
+			PasswordComplexityColRejectBreached.identifier(),
+			PasswordComplexityColMaxLength.identifier(),
+			PasswordComplexityColForbidUsername.identifier(),
+			PasswordComplexityColForbidEmailLocalPart.identifier(),
+			PasswordComplexityColForbidOrgName.identifier(),
+			PasswordComplexityColRules.identifier(),
+			PasswordComplexityColBannedWords.identifier(),
 			PasswordComplexityColIsDefault.identifier(),
 			PasswordComplexityColState.identifier(),
 		).
@@ -179,6 +224,13 @@ func preparePasswordComplexityPolicyQuery(ctx context.Context, db prepareDatabas
 				&policy.HasUppercase,
 				&policy.HasNumber,
 				&policy.HasSymbol,
+				&policy.RejectBreached,
+				&policy.MaxLength,
+				&policy.ForbidUsername,
+				&policy.ForbidEmailLocalPart,
+				&policy.ForbidOrgName,
+				&policy.Rules,
+				&policy.BannedWords,
 				&policy.IsDefault,
 				&policy.State,
 			)
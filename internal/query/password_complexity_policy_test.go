@@ -8,24 +8,32 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
 	errs "github.com/zitadel/zitadel/internal/errors"
 )
 
 var (
-	preparePasswordComplexityPolicyStmt = `SELECT projections.password_complexity_policies2.id,` +
-		` projections.password_complexity_policies2.sequence,` +
-		` projections.password_complexity_policies2.creation_date,` +
-		` projections.password_complexity_policies2.change_date,` +
-		` projections.password_complexity_policies2.resource_owner,` +
-		` projections.password_complexity_policies2.min_length,` +
-		` projections.password_complexity_policies2.has_lowercase,` +
-		` projections.password_complexity_policies2.has_uppercase,` +
-		` projections.password_complexity_policies2.has_number,` +
-		` projections.password_complexity_policies2.has_symbol,` +
-		` projections.password_complexity_policies2.is_default,` +
-		` projections.password_complexity_policies2.state` +
-		` FROM projections.password_complexity_policies2` +
+	preparePasswordComplexityPolicyStmt = `SELECT projections.password_complexity_policies4.id,` +
+		` projections.password_complexity_policies4.sequence,` +
+		` projections.password_complexity_policies4.creation_date,` +
+		` projections.password_complexity_policies4.change_date,` +
+		` projections.password_complexity_policies4.resource_owner,` +
+		` projections.password_complexity_policies4.min_length,` +
+		` projections.password_complexity_policies4.has_lowercase,` +
+		` projections.password_complexity_policies4.has_uppercase,` +
+		` projections.password_complexity_policies4.has_number,` +
+		` projections.password_complexity_policies4.has_symbol,` +
+		` projections.password_complexity_policies4.reject_breached,` +
+		` projections.password_complexity_policies4.max_length,` +
+		` projections.password_complexity_policies4.forbid_username,` +
+		` projections.password_complexity_policies4.forbid_email_local_part,` +
+		` projections.password_complexity_policies4.forbid_org_name,` +
+		` projections.password_complexity_policies4.rules,` +
+		` projections.password_complexity_policies4.banned_words,` +
+		` projections.password_complexity_policies4.is_default,` +
+		` projections.password_complexity_policies4.state` +
+		` FROM projections.password_complexity_policies4` +
 		` AS OF SYSTEM TIME '-1 ms'`
 	preparePasswordComplexityPolicyCols = []string{
 		"id",
@@ -38,6 +46,13 @@ var (
 		"has_uppercase",
 		"has_number",
 		"has_symbol",
+		"reject_breached",
+		"max_length",
+		"forbid_username",
+		"forbid_email_local_part",
+		"forbid_org_name",
+		"rules",
+		"banned_words",
 		"is_default",
 		"state",
 	}
@@ -91,23 +106,39 @@ func Test_PasswordComplexityPolicyPrepares(t *testing.T) {
 						true,
 						true,
 						true,
+						64,
+						true,
+						true,
+						true,
+						[]byte(`[{"type":2,"pattern":"(?i)qwerty"}]`),
+						database.StringArray{"zitadel"},
+						true,
 						domain.PolicyStateActive,
 					},
 				),
 			},
 			object: &PasswordComplexityPolicy{
-				ID:            "pol-id",
-				CreationDate:  testNow,
-				ChangeDate:    testNow,
-				Sequence:      20211109,
-				ResourceOwner: "ro",
-				State:         domain.PolicyStateActive,
-				MinLength:     8,
-				HasLowercase:  true,
-				HasUppercase:  true,
-				HasNumber:     true,
-				HasSymbol:     true,
-				IsDefault:     true,
+				ID:                   "pol-id",
+				CreationDate:         testNow,
+				ChangeDate:           testNow,
+				Sequence:             20211109,
+				ResourceOwner:        "ro",
+				State:                domain.PolicyStateActive,
+				MinLength:            8,
+				HasLowercase:         true,
+				HasUppercase:         true,
+				HasNumber:            true,
+				HasSymbol:            true,
+				RejectBreached:       true,
+				MaxLength:            64,
+				ForbidUsername:       true,
+				ForbidEmailLocalPart: true,
+				ForbidOrgName:        true,
+				Rules: database.JSONArray[domain.PasswordRule]{
+					{Type: domain.PasswordRuleTypeDeny, Pattern: `(?i)qwerty`},
+				},
+				BannedWords: database.StringArray{"zitadel"},
+				IsDefault:   true,
 			},
 		},
 		{
@@ -0,0 +1,646 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/policy"
+	"github.com/zitadel/zitadel/internal/repository/project"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+)
+
+// PointInTime defines the state of a resource to read
+// either all events created before the timestamp
+// or all events up to and including the sequence are reduced
+type PointInTime struct {
+	Timestamp time.Time
+	Sequence  uint64
+}
+
+func (p PointInTime) IsZero() bool {
+	return p.Timestamp.IsZero() && p.Sequence == 0
+}
+
+func (p PointInTime) filter(query *eventstore.SearchQuery) *eventstore.SearchQuery {
+	if !p.Timestamp.IsZero() {
+		query = query.CreationDateBefore(p.Timestamp)
+	}
+	if p.Sequence > 0 {
+		query = query.SequenceLess(p.Sequence + 1)
+	}
+	return query
+}
+
+// UserAt reconstructs the user as it was at the point in time by replaying its events
+// login names are not computed because they depend on the domains of the organisation
+func (q *Queries) UserAt(ctx context.Context, userID, resourceOwner string, at PointInTime) (_ *User, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if userID == "" {
+		return nil, errors.ThrowInvalidArgument(nil, "QUERY-Aeth4", "Errors.User.UserIDMissing")
+	}
+	if at.IsZero() {
+		return nil, errors.ThrowInvalidArgument(nil, "QUERY-ohB5e", "Errors.Query.InvalidRequest")
+	}
+	readModel := newUserAtReadModel(userID, resourceOwner, at)
+	if err = q.eventstore.FilterToQueryReducer(ctx, readModel); err != nil {
+		return nil, err
+	}
+	if readModel.user.State == domain.UserStateUnspecified {
+		return nil, errors.ThrowNotFound(nil, "QUERY-Iek7i", "Errors.User.NotFound")
+	}
+	return readModel.User(), nil
+}
+
+// OrgAt reconstructs the organisation as it was at the point in time by replaying its events
+func (q *Queries) OrgAt(ctx context.Context, orgID string, at PointInTime) (_ *Org, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if orgID == "" {
+		return nil, errors.ThrowInvalidArgument(nil, "QUERY-Uo1ve", "Errors.Org.Invalid")
+	}
+	if at.IsZero() {
+		return nil, errors.ThrowInvalidArgument(nil, "QUERY-ceiG0", "Errors.Query.InvalidRequest")
+	}
+	readModel := newOrgAtReadModel(orgID, at)
+	if err = q.eventstore.FilterToQueryReducer(ctx, readModel); err != nil {
+		return nil, err
+	}
+	if readModel.org.State == domain.OrgStateUnspecified {
+		return nil, errors.ThrowNotFound(nil, "QUERY-Ahc5o", "Errors.Org.NotFound")
+	}
+	return readModel.Org(), nil
+}
+
+// ProjectAt reconstructs the project as it was at the point in time by replaying its events
+func (q *Queries) ProjectAt(ctx context.Context, projectID, resourceOwner string, at PointInTime) (_ *Project, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if projectID == "" {
+		return nil, errors.ThrowInvalidArgument(nil, "QUERY-Jo0ai", "Errors.Project.ProjectIDMissing")
+	}
+	if at.IsZero() {
+		return nil, errors.ThrowInvalidArgument(nil, "QUERY-Pah3u", "Errors.Query.InvalidRequest")
+	}
+	readModel := newProjectAtReadModel(projectID, resourceOwner, at)
+	if err = q.eventstore.FilterToQueryReducer(ctx, readModel); err != nil {
+		return nil, err
+	}
+	if readModel.project.State == domain.ProjectStateUnspecified {
+		return nil, errors.ThrowNotFound(nil, "QUERY-eiB8u", "Errors.Project.NotFound")
+	}
+	return readModel.Project(), nil
+}
+
+// PasswordComplexityPolicyAt reconstructs the password complexity policy of the organisation
+// as it was at the point in time, if the organisation had no own policy the default policy is returned.
+// If orgID is empty the default policy of the instance is returned.
+func (q *Queries) PasswordComplexityPolicyAt(ctx context.Context, orgID string, at PointInTime) (_ *PasswordComplexityPolicy, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if at.IsZero() {
+		return nil, errors.ThrowInvalidArgument(nil, "QUERY-Ooph9", "Errors.Query.InvalidRequest")
+	}
+	readModel := newPasswordComplexityPolicyAtReadModel(authz.GetInstance(ctx).InstanceID(), orgID, at)
+	if err = q.eventstore.FilterToQueryReducer(ctx, readModel); err != nil {
+		return nil, err
+	}
+	complexityPolicy := readModel.Policy()
+	if complexityPolicy == nil {
+		return nil, errors.ThrowNotFound(nil, "QUERY-oaJ4a", "Errors.PasswordComplexity.NotFound")
+	}
+	return complexityPolicy, nil
+}
+
+type userAtReadModel struct {
+	*eventstore.ReadModel
+	at PointInTime
+
+	user User
+}
+
+func newUserAtReadModel(userID, resourceOwner string, at PointInTime) *userAtReadModel {
+	return &userAtReadModel{
+		ReadModel: &eventstore.ReadModel{
+			AggregateID:   userID,
+			ResourceOwner: resourceOwner,
+		},
+		at: at,
+	}
+}
+
+// Reduce reduces the events of the user,
+// the events of the first aggregate version (e.g. user.added, user.selfregistered, user.email.changed)
+// are mapped to the events of humans and reduced the same way
+func (rm *userAtReadModel) Reduce() error {
+	for _, event := range rm.Events {
+		switch e := event.(type) {
+		case *user.HumanAddedEvent:
+			rm.user.Username = e.UserName
+			rm.user.Type = domain.UserTypeHuman
+			rm.user.State = domain.UserStateActive
+			rm.user.Human = &Human{
+				FirstName:         e.FirstName,
+				LastName:          e.LastName,
+				NickName:          e.NickName,
+				DisplayName:       e.DisplayName,
+				PreferredLanguage: e.PreferredLanguage,
+				Gender:            e.Gender,
+				Email:             e.EmailAddress,
+				Phone:             e.PhoneNumber,
+			}
+			rm.user.Human.Address = humanAddress(e.Country, e.Locality, e.PostalCode, e.Region, e.StreetAddress)
+		case *user.HumanRegisteredEvent:
+			rm.user.Username = e.UserName
+			rm.user.Type = domain.UserTypeHuman
+			rm.user.State = domain.UserStateActive
+			rm.user.Human = &Human{
+				FirstName:         e.FirstName,
+				LastName:          e.LastName,
+				NickName:          e.NickName,
+				DisplayName:       e.DisplayName,
+				PreferredLanguage: e.PreferredLanguage,
+				Gender:            e.Gender,
+				Email:             e.EmailAddress,
+				Phone:             e.PhoneNumber,
+			}
+			rm.user.Human.Address = humanAddress(e.Country, e.Locality, e.PostalCode, e.Region, e.StreetAddress)
+		case *user.MachineAddedEvent:
+			rm.user.Username = e.UserName
+			rm.user.Type = domain.UserTypeMachine
+			rm.user.State = domain.UserStateActive
+			rm.user.Machine = &Machine{
+				Name:            e.Name,
+				Description:     e.Description,
+				AccessTokenType: e.AccessTokenType,
+			}
+		case *user.HumanInitialCodeAddedEvent:
+			rm.user.State = domain.UserStateInitial
+		case *user.HumanInitializedCheckSucceededEvent:
+			rm.user.State = domain.UserStateActive
+		case *user.UserLockedEvent:
+			rm.user.State = domain.UserStateLocked
+		case *user.UserDeactivatedEvent:
+			rm.user.State = domain.UserStateInactive
+		case *user.UserUnlockedEvent, *user.UserReactivatedEvent:
+			rm.user.State = domain.UserStateActive
+		case *user.UserRemovedEvent:
+			rm.user.State = domain.UserStateDeleted
+		case *user.UsernameChangedEvent:
+			rm.user.Username = e.UserName
+		case *user.DomainClaimedEvent:
+			rm.user.Username = e.UserName
+		case *user.HumanProfileChangedEvent:
+			if rm.user.Human == nil {
+				continue
+			}
+			if e.FirstName != "" {
+				rm.user.Human.FirstName = e.FirstName
+			}
+			if e.LastName != "" {
+				rm.user.Human.LastName = e.LastName
+			}
+			if e.NickName != nil {
+				rm.user.Human.NickName = *e.NickName
+			}
+			if e.DisplayName != nil {
+				rm.user.Human.DisplayName = *e.DisplayName
+			}
+			if e.PreferredLanguage != nil {
+				rm.user.Human.PreferredLanguage = *e.PreferredLanguage
+			}
+			if e.Gender != nil {
+				rm.user.Human.Gender = *e.Gender
+			}
+		case *user.HumanEmailChangedEvent:
+			if rm.user.Human == nil {
+				continue
+			}
+			rm.user.Human.Email = e.EmailAddress
+			rm.user.Human.IsEmailVerified = false
+		case *user.HumanEmailVerifiedEvent:
+			if rm.user.Human == nil {
+				continue
+			}
+			rm.user.Human.IsEmailVerified = true
+			if rm.user.State == domain.UserStateInitial {
+				rm.user.State = domain.UserStateActive
+			}
+		case *user.HumanPhoneChangedEvent:
+			if rm.user.Human == nil {
+				continue
+			}
+			rm.user.Human.Phone = e.PhoneNumber
+			rm.user.Human.IsPhoneVerified = false
+		case *user.HumanPhoneRemovedEvent:
+			if rm.user.Human == nil {
+				continue
+			}
+			rm.user.Human.Phone = ""
+			rm.user.Human.IsPhoneVerified = false
+		case *user.HumanPhoneVerifiedEvent:
+			if rm.user.Human == nil {
+				continue
+			}
+			rm.user.Human.IsPhoneVerified = true
+		case *user.HumanAddressChangedEvent:
+			if rm.user.Human == nil {
+				continue
+			}
+			rm.reduceAddressChanged(e)
+		case *user.HumanAvatarAddedEvent:
+			if rm.user.Human == nil {
+				continue
+			}
+			rm.user.Human.AvatarKey = e.StoreKey
+		case *user.HumanAvatarRemovedEvent:
+			if rm.user.Human == nil {
+				continue
+			}
+			rm.user.Human.AvatarKey = ""
+		case *user.MachineChangedEvent:
+			if rm.user.Machine == nil {
+				continue
+			}
+			if e.Name != nil {
+				rm.user.Machine.Name = *e.Name
+			}
+			if e.Description != nil {
+				rm.user.Machine.Description = *e.Description
+			}
+			if e.AccessTokenType != nil {
+				rm.user.Machine.AccessTokenType = *e.AccessTokenType
+			}
+		case *user.MachineSecretSetEvent:
+			if rm.user.Machine == nil {
+				continue
+			}
+			rm.user.Machine.HasSecret = true
+		case *user.MachineSecretRemovedEvent:
+			if rm.user.Machine == nil {
+				continue
+			}
+			rm.user.Machine.HasSecret = false
+		}
+	}
+	return rm.ReadModel.Reduce()
+}
+
+func (rm *userAtReadModel) reduceAddressChanged(e *user.HumanAddressChangedEvent) {
+	address := rm.user.Human.Address
+	if address == nil {
+		address = new(HumanAddress)
+	}
+	if e.Country != nil {
+		address.Country = *e.Country
+	}
+	if e.Locality != nil {
+		address.Locality = *e.Locality
+	}
+	if e.PostalCode != nil {
+		address.PostalCode = *e.PostalCode
+	}
+	if e.Region != nil {
+		address.Region = *e.Region
+	}
+	if e.StreetAddress != nil {
+		address.StreetAddress = *e.StreetAddress
+	}
+	rm.user.Human.Address = humanAddress(address.Country, address.Locality, address.PostalCode, address.Region, address.StreetAddress)
+}
+
+// humanAddress returns nil if the human has no address
+func humanAddress(country, locality, postalCode, region, streetAddress string) *HumanAddress {
+	if country == "" && locality == "" && postalCode == "" && region == "" && streetAddress == "" {
+		return nil
+	}
+	return &HumanAddress{
+		Country:       country,
+		Locality:      locality,
+		PostalCode:    postalCode,
+		Region:        region,
+		StreetAddress: streetAddress,
+	}
+}
+
+func (rm *userAtReadModel) User() *User {
+	u := rm.user
+	u.ID = rm.AggregateID
+	u.ResourceOwner = rm.ResourceOwner
+	u.CreationDate = rm.CreationDate
+	u.ChangeDate = rm.ChangeDate
+	u.Sequence = rm.ProcessedSequence
+	return &u
+}
+
+func (rm *userAtReadModel) Query() *eventstore.SearchQueryBuilder {
+	query := rm.at.filter(
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes(user.AggregateType).
+			AggregateIDs(rm.AggregateID),
+	).Builder()
+	if rm.ResourceOwner != "" {
+		query.ResourceOwner(rm.ResourceOwner)
+	}
+	return query
+}
+
+type orgAtReadModel struct {
+	*eventstore.ReadModel
+	at PointInTime
+
+	org Org
+}
+
+func newOrgAtReadModel(orgID string, at PointInTime) *orgAtReadModel {
+	return &orgAtReadModel{
+		ReadModel: &eventstore.ReadModel{
+			AggregateID:   orgID,
+			ResourceOwner: orgID,
+		},
+		at: at,
+	}
+}
+
+func (rm *orgAtReadModel) Reduce() error {
+	for _, event := range rm.Events {
+		switch e := event.(type) {
+		case *org.OrgAddedEvent:
+			rm.org.Name = e.Name
+			rm.org.State = domain.OrgStateActive
+		case *org.OrgChangedEvent:
+			rm.org.Name = e.Name
+		case *org.OrgDeactivatedEvent:
+			rm.org.State = domain.OrgStateInactive
+		case *org.OrgReactivatedEvent:
+			rm.org.State = domain.OrgStateActive
+		case *org.OrgRemovedEvent:
+			rm.org.State = domain.OrgStateRemoved
+		case *org.DomainPrimarySetEvent:
+			rm.org.Domain = e.Domain
+		}
+	}
+	return rm.ReadModel.Reduce()
+}
+
+func (rm *orgAtReadModel) Org() *Org {
+	o := rm.org
+	o.ID = rm.AggregateID
+	o.ResourceOwner = rm.ResourceOwner
+	o.CreationDate = rm.CreationDate
+	o.ChangeDate = rm.ChangeDate
+	o.Sequence = rm.ProcessedSequence
+	return &o
+}
+
+func (rm *orgAtReadModel) Query() *eventstore.SearchQueryBuilder {
+	return rm.at.filter(
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes(org.AggregateType).
+			AggregateIDs(rm.AggregateID).
+			EventTypes(
+				org.OrgAddedEventType,
+				org.OrgChangedEventType,
+				org.OrgDeactivatedEventType,
+				org.OrgReactivatedEventType,
+				org.OrgRemovedEventType,
+				org.OrgDomainPrimarySetEventType,
+			),
+	).Builder()
+}
+
+type projectAtReadModel struct {
+	*eventstore.ReadModel
+	at PointInTime
+
+	project Project
+}
+
+func newProjectAtReadModel(projectID, resourceOwner string, at PointInTime) *projectAtReadModel {
+	return &projectAtReadModel{
+		ReadModel: &eventstore.ReadModel{
+			AggregateID:   projectID,
+			ResourceOwner: resourceOwner,
+		},
+		at: at,
+	}
+}
+
+func (rm *projectAtReadModel) Reduce() error {
+	for _, event := range rm.Events {
+		switch e := event.(type) {
+		case *project.ProjectAddedEvent:
+			rm.project.Name = e.Name
+			rm.project.ProjectRoleAssertion = e.ProjectRoleAssertion
+			rm.project.ProjectRoleCheck = e.ProjectRoleCheck
+			rm.project.HasProjectCheck = e.HasProjectCheck
+			rm.project.PrivateLabelingSetting = e.PrivateLabelingSetting
+			rm.project.State = domain.ProjectStateActive
+		case *project.ProjectChangeEvent:
+			if e.Name != nil {
+				rm.project.Name = *e.Name
+			}
+			if e.ProjectRoleAssertion != nil {
+				rm.project.ProjectRoleAssertion = *e.ProjectRoleAssertion
+			}
+			if e.ProjectRoleCheck != nil {
+				rm.project.ProjectRoleCheck = *e.ProjectRoleCheck
+			}
+			if e.HasProjectCheck != nil {
+				rm.project.HasProjectCheck = *e.HasProjectCheck
+			}
+			if e.PrivateLabelingSetting != nil {
+				rm.project.PrivateLabelingSetting = *e.PrivateLabelingSetting
+			}
+		case *project.ProjectDeactivatedEvent:
+			rm.project.State = domain.ProjectStateInactive
+		case *project.ProjectReactivatedEvent:
+			rm.project.State = domain.ProjectStateActive
+		case *project.ProjectRemovedEvent:
+			rm.project.State = domain.ProjectStateRemoved
+		}
+	}
+	return rm.ReadModel.Reduce()
+}
+
+func (rm *projectAtReadModel) Project() *Project {
+	p := rm.project
+	p.ID = rm.AggregateID
+	p.ResourceOwner = rm.ResourceOwner
+	p.CreationDate = rm.CreationDate
+	p.ChangeDate = rm.ChangeDate
+	p.Sequence = rm.ProcessedSequence
+	return &p
+}
+
+func (rm *projectAtReadModel) Query() *eventstore.SearchQueryBuilder {
+	query := rm.at.filter(
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes(project.AggregateType).
+			AggregateIDs(rm.AggregateID).
+			EventTypes(
+				project.ProjectAddedType,
+				project.ProjectChangedType,
+				project.ProjectDeactivatedType,
+				project.ProjectReactivatedType,
+				project.ProjectRemovedType,
+			),
+	).Builder()
+	if rm.ResourceOwner != "" {
+		query.ResourceOwner(rm.ResourceOwner)
+	}
+	return query
+}
+
+type passwordComplexityPolicyAtReadModel struct {
+	*eventstore.ReadModel
+	at         PointInTime
+	instanceID string
+	orgID      string
+
+	defaultPolicy *PasswordComplexityPolicy
+	orgPolicy     *PasswordComplexityPolicy
+}
+
+func newPasswordComplexityPolicyAtReadModel(instanceID, orgID string, at PointInTime) *passwordComplexityPolicyAtReadModel {
+	return &passwordComplexityPolicyAtReadModel{
+		ReadModel:  &eventstore.ReadModel{},
+		at:         at,
+		instanceID: instanceID,
+		orgID:      orgID,
+	}
+}
+
+func (rm *passwordComplexityPolicyAtReadModel) Reduce() error {
+	for _, event := range rm.Events {
+		switch e := event.(type) {
+		case *instance.PasswordComplexityPolicyAddedEvent:
+			rm.defaultPolicy = addedPasswordComplexityPolicy(&e.PasswordComplexityPolicyAddedEvent)
+			rm.defaultPolicy.IsDefault = true
+		case *instance.PasswordComplexityPolicyChangedEvent:
+			changePasswordComplexityPolicy(rm.defaultPolicy, &e.PasswordComplexityPolicyChangedEvent)
+		case *org.PasswordComplexityPolicyAddedEvent:
+			rm.orgPolicy = addedPasswordComplexityPolicy(&e.PasswordComplexityPolicyAddedEvent)
+		case *org.PasswordComplexityPolicyChangedEvent:
+			changePasswordComplexityPolicy(rm.orgPolicy, &e.PasswordComplexityPolicyChangedEvent)
+		case *org.PasswordComplexityPolicyRemovedEvent:
+			rm.orgPolicy = nil
+		}
+	}
+	return rm.ReadModel.Reduce()
+}
+
+func addedPasswordComplexityPolicy(e *policy.PasswordComplexityPolicyAddedEvent) *PasswordComplexityPolicy {
+	return &PasswordComplexityPolicy{
+		ID:                   e.Aggregate().ID,
+		Sequence:             e.Sequence(),
+		CreationDate:         e.CreationDate(),
+		ChangeDate:           e.CreationDate(),
+		ResourceOwner:        e.Aggregate().ResourceOwner,
+		State:                domain.PolicyStateActive,
+		MinLength:            e.MinLength,
+		HasLowercase:         e.HasLowercase,
+		HasUppercase:         e.HasUppercase,
+		HasNumber:            e.HasNumber,
+		HasSymbol:            e.HasSymbol,
+		RejectBreached:       e.RejectBreached,
+		MaxLength:            e.MaxLength,
+		ForbidUsername:       e.ForbidUsername,
+		ForbidEmailLocalPart: e.ForbidEmailLocalPart,
+		ForbidOrgName:        e.ForbidOrgName,
+		Rules:                e.Rules,
+		BannedWords:          e.BannedWords,
+	}
+}
+
+func changePasswordComplexityPolicy(p *PasswordComplexityPolicy, e *policy.PasswordComplexityPolicyChangedEvent) {
+	if p == nil {
+		return
+	}
+	p.Sequence = e.Sequence()
+	p.ChangeDate = e.CreationDate()
+	if e.MinLength != nil {
+		p.MinLength = *e.MinLength
+	}
+	if e.HasLowercase != nil {
+		p.HasLowercase = *e.HasLowercase
+	}
+	if e.HasUppercase != nil {
+		p.HasUppercase = *e.HasUppercase
+	}
+	if e.HasNumber != nil {
+		p.HasNumber = *e.HasNumber
+	}
+	if e.HasSymbol != nil {
+		p.HasSymbol = *e.HasSymbol
+	}
+	if e.RejectBreached != nil {
+		p.RejectBreached = *e.RejectBreached
+	}
+	if e.MaxLength != nil {
+		p.MaxLength = *e.MaxLength
+	}
+	if e.ForbidUsername != nil {
+		p.ForbidUsername = *e.ForbidUsername
+	}
+	if e.ForbidEmailLocalPart != nil {
+		p.ForbidEmailLocalPart = *e.ForbidEmailLocalPart
+	}
+	if e.ForbidOrgName != nil {
+		p.ForbidOrgName = *e.ForbidOrgName
+	}
+	if e.Rules != nil {
+		p.Rules = *e.Rules
+	}
+	if e.BannedWords != nil {
+		p.BannedWords = *e.BannedWords
+	}
+}
+
+// Policy returns the policy of the organisation or the default policy if the organisation had none
+func (rm *passwordComplexityPolicyAtReadModel) Policy() *PasswordComplexityPolicy {
+	if rm.orgPolicy != nil {
+		return rm.orgPolicy
+	}
+	return rm.defaultPolicy
+}
+
+func (rm *passwordComplexityPolicyAtReadModel) Query() *eventstore.SearchQueryBuilder {
+	query := rm.at.filter(
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes(instance.AggregateType).
+			AggregateIDs(rm.instanceID).
+			EventTypes(
+				instance.PasswordComplexityPolicyAddedEventType,
+				instance.PasswordComplexityPolicyChangedEventType,
+			),
+	).Builder()
+	if rm.orgID != "" {
+		query = rm.at.filter(
+			query.AddQuery().
+				AggregateTypes(org.AggregateType).
+				AggregateIDs(rm.orgID).
+				EventTypes(
+					org.PasswordComplexityPolicyAddedEventType,
+					org.PasswordComplexityPolicyChangedEventType,
+					org.PasswordComplexityPolicyRemovedEventType,
+				),
+		).Builder()
+	}
+	return query
+}
@@ -0,0 +1,344 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/policy"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+func newInstancePasswordComplexityPolicyChangedEvent(t *testing.T, aggregate *eventstore.Aggregate, changes ...policy.PasswordComplexityPolicyChanges) eventstore.Event {
+	t.Helper()
+	event, err := instance.NewPasswordComplexityPolicyChangedEvent(context.Background(), aggregate, changes)
+	if err != nil {
+		t.Fatalf("unable to create event: %v", err)
+	}
+	return event
+}
+
+// newUserV1Event maps the payload of the first aggregate version like the eventstore does
+func newUserV1Event(t *testing.T, eventType eventstore.EventType, data string) eventstore.Event {
+	t.Helper()
+	mapper := map[eventstore.EventType]func(*repository.Event) (eventstore.Event, error){
+		user.UserV1AddedType:                     user.HumanAddedEventMapper,
+		user.UserV1RegisteredType:                user.HumanRegisteredEventMapper,
+		user.UserV1ProfileChangedType:            user.HumanProfileChangedEventMapper,
+		user.UserV1EmailChangedType:              user.HumanEmailChangedEventMapper,
+		user.UserV1EmailVerifiedType:             user.HumanEmailVerifiedEventMapper,
+		user.UserV1PhoneChangedType:              user.HumanPhoneChangedEventMapper,
+		user.UserV1PhoneVerifiedType:             user.HumanPhoneVerifiedEventMapper,
+		user.UserV1PhoneRemovedType:              user.HumanPhoneRemovedEventMapper,
+		user.UserV1AddressChangedType:            user.HumanAddressChangedEventMapper,
+		user.UserV1InitialCodeAddedType:          user.HumanInitialCodeAddedEventMapper,
+		user.UserV1InitializedCheckSucceededType: user.HumanInitializedCheckSucceededEventMapper,
+	}[eventType]
+	event, err := mapper(&repository.Event{
+		AggregateID:   "user1",
+		AggregateType: repository.AggregateType(user.AggregateType),
+		ResourceOwner: sql.NullString{String: "org1", Valid: true},
+		Type:          repository.EventType(eventType),
+		Version:       "v1",
+		Data:          []byte(data),
+	})
+	if err != nil {
+		t.Fatalf("unable to map event: %v", err)
+	}
+	return event
+}
+
+func newAddressChangedEvent(t *testing.T, aggregate *eventstore.Aggregate, changes ...user.AddressChanges) eventstore.Event {
+	t.Helper()
+	event, err := user.NewAddressChangedEvent(context.Background(), aggregate, changes)
+	if err != nil {
+		t.Fatalf("unable to create event: %v", err)
+	}
+	return event
+}
+
+func Test_userAtReadModel_Reduce(t *testing.T) {
+	ctx := context.Background()
+	agg := &user.NewAggregate("user1", "org1").Aggregate
+	tests := []struct {
+		name   string
+		events []eventstore.Event
+		want   *User
+	}{
+		{
+			name: "added",
+			events: []eventstore.Event{
+				user.NewHumanAddedEvent(ctx, agg, "gigi", "Gigi", "Giraffe", "", "Gigi Giraffe", language.German, domain.GenderFemale, "gigi@zitadel.com", false),
+			},
+			want: &User{
+				ID:            "user1",
+				ResourceOwner: "org1",
+				State:         domain.UserStateActive,
+				Type:          domain.UserTypeHuman,
+				Username:      "gigi",
+				Human: &Human{
+					FirstName:         "Gigi",
+					LastName:          "Giraffe",
+					DisplayName:       "Gigi Giraffe",
+					PreferredLanguage: language.German,
+					Gender:            domain.GenderFemale,
+					Email:             "gigi@zitadel.com",
+				},
+			},
+		},
+		{
+			name: "initialised, verified and locked",
+			events: []eventstore.Event{
+				user.NewHumanAddedEvent(ctx, agg, "gigi", "Gigi", "Giraffe", "", "Gigi Giraffe", language.German, domain.GenderFemale, "gigi@zitadel.com", false),
+				user.NewHumanInitialCodeAddedEvent(ctx, agg, nil, 0),
+				user.NewHumanEmailVerifiedEvent(ctx, agg),
+				user.NewUserLockedEvent(ctx, agg),
+			},
+			want: &User{
+				ID:            "user1",
+				ResourceOwner: "org1",
+				State:         domain.UserStateLocked,
+				Type:          domain.UserTypeHuman,
+				Username:      "gigi",
+				Human: &Human{
+					FirstName:         "Gigi",
+					LastName:          "Giraffe",
+					DisplayName:       "Gigi Giraffe",
+					PreferredLanguage: language.German,
+					Gender:            domain.GenderFemale,
+					Email:             "gigi@zitadel.com",
+					IsEmailVerified:   true,
+				},
+			},
+		},
+		{
+			name: "added with address, address changed",
+			events: []eventstore.Event{
+				func() eventstore.Event {
+					e := user.NewHumanAddedEvent(ctx, agg, "gigi", "Gigi", "Giraffe", "", "Gigi Giraffe", language.German, domain.GenderFemale, "gigi@zitadel.com", false)
+					e.AddAddressData("CH", "Bern", "3000", "BE", "Bundesplatz 3")
+					return e
+				}(),
+				newAddressChangedEvent(t, agg, user.ChangeLocality("Zürich"), user.ChangePostalCode("8000"), user.ChangeRegion("ZH"), user.ChangeStreetAddress("Bahnhofstrasse 1")),
+			},
+			want: &User{
+				ID:            "user1",
+				ResourceOwner: "org1",
+				State:         domain.UserStateActive,
+				Type:          domain.UserTypeHuman,
+				Username:      "gigi",
+				Human: &Human{
+					FirstName:         "Gigi",
+					LastName:          "Giraffe",
+					DisplayName:       "Gigi Giraffe",
+					PreferredLanguage: language.German,
+					Gender:            domain.GenderFemale,
+					Email:             "gigi@zitadel.com",
+					Address: &HumanAddress{
+						Country:       "CH",
+						Locality:      "Zürich",
+						PostalCode:    "8000",
+						Region:        "ZH",
+						StreetAddress: "Bahnhofstrasse 1",
+					},
+				},
+			},
+		},
+		{
+			name: "address added",
+			events: []eventstore.Event{
+				user.NewHumanAddedEvent(ctx, agg, "gigi", "Gigi", "Giraffe", "", "Gigi Giraffe", language.German, domain.GenderFemale, "gigi@zitadel.com", false),
+				newAddressChangedEvent(t, agg, user.ChangeCountry("CH")),
+			},
+			want: &User{
+				ID:            "user1",
+				ResourceOwner: "org1",
+				State:         domain.UserStateActive,
+				Type:          domain.UserTypeHuman,
+				Username:      "gigi",
+				Human: &Human{
+					FirstName:         "Gigi",
+					LastName:          "Giraffe",
+					DisplayName:       "Gigi Giraffe",
+					PreferredLanguage: language.German,
+					Gender:            domain.GenderFemale,
+					Email:             "gigi@zitadel.com",
+					Address:           &HumanAddress{Country: "CH"},
+				},
+			},
+		},
+		{
+			name: "v1 added and changed",
+			events: []eventstore.Event{
+				newUserV1Event(t, user.UserV1AddedType, `{"userName":"gigi","firstName":"Gigi","lastName":"Giraffe","displayName":"Gigi Giraffe","preferredLanguage":"de","gender":1,"email":"gigi@zitadel.com","phone":"+41791234567","country":"CH"}`),
+				newUserV1Event(t, user.UserV1InitialCodeAddedType, `{}`),
+				newUserV1Event(t, user.UserV1InitializedCheckSucceededType, `{}`),
+				newUserV1Event(t, user.UserV1ProfileChangedType, `{"firstName":"Gigu","nickName":"gigu"}`),
+				newUserV1Event(t, user.UserV1EmailChangedType, `{"email":"gigu@zitadel.com"}`),
+				newUserV1Event(t, user.UserV1EmailVerifiedType, `{}`),
+				newUserV1Event(t, user.UserV1PhoneChangedType, `{"phone":"+41797654321"}`),
+				newUserV1Event(t, user.UserV1PhoneVerifiedType, `{}`),
+				newUserV1Event(t, user.UserV1AddressChangedType, `{"locality":"Bern","streetAddress":"Bundesplatz 3"}`),
+			},
+			want: &User{
+				ID:            "user1",
+				ResourceOwner: "org1",
+				State:         domain.UserStateActive,
+				Type:          domain.UserTypeHuman,
+				Username:      "gigi",
+				Human: &Human{
+					FirstName:         "Gigu",
+					LastName:          "Giraffe",
+					NickName:          "gigu",
+					DisplayName:       "Gigi Giraffe",
+					PreferredLanguage: language.German,
+					Gender:            domain.GenderFemale,
+					Email:             "gigu@zitadel.com",
+					IsEmailVerified:   true,
+					Phone:             "+41797654321",
+					IsPhoneVerified:   true,
+					Address: &HumanAddress{
+						Country:       "CH",
+						Locality:      "Bern",
+						StreetAddress: "Bundesplatz 3",
+					},
+				},
+			},
+		},
+		{
+			name: "v1 registered, phone removed",
+			events: []eventstore.Event{
+				newUserV1Event(t, user.UserV1RegisteredType, `{"userName":"gigi","firstName":"Gigi","lastName":"Giraffe","email":"gigi@zitadel.com","phone":"+41791234567"}`),
+				newUserV1Event(t, user.UserV1PhoneRemovedType, `{}`),
+			},
+			want: &User{
+				ID:            "user1",
+				ResourceOwner: "org1",
+				State:         domain.UserStateActive,
+				Type:          domain.UserTypeHuman,
+				Username:      "gigi",
+				Human: &Human{
+					FirstName: "Gigi",
+					LastName:  "Giraffe",
+					Email:     "gigi@zitadel.com",
+				},
+			},
+		},
+		{
+			name: "removed",
+			events: []eventstore.Event{
+				user.NewMachineAddedEvent(ctx, agg, "machine", "Machine", "", false, domain.OIDCTokenTypeBearer),
+				user.NewUserRemovedEvent(ctx, agg, "machine", nil, false),
+			},
+			want: &User{
+				ID:            "user1",
+				ResourceOwner: "org1",
+				State:         domain.UserStateDeleted,
+				Type:          domain.UserTypeMachine,
+				Username:      "machine",
+				Machine: &Machine{
+					Name:            "Machine",
+					AccessTokenType: domain.OIDCTokenTypeBearer,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := newUserAtReadModel("user1", "org1", PointInTime{Sequence: 1})
+			rm.AppendEvents(tt.events...)
+			if err := rm.Reduce(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := rm.User(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("User() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_passwordComplexityPolicyAtReadModel_Reduce(t *testing.T) {
+	ctx := context.Background()
+	instanceAgg := &instance.NewAggregate("instance1").Aggregate
+	orgAgg := &org.NewAggregate("org1").Aggregate
+	tests := []struct {
+		name   string
+		events []eventstore.Event
+		want   *PasswordComplexityPolicy
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name: "default policy",
+			events: []eventstore.Event{
+				instance.NewPasswordComplexityPolicyAddedEvent(ctx, instanceAgg, 8, true, true, true, true, false, 0, false, false, false, nil, nil),
+				newInstancePasswordComplexityPolicyChangedEvent(t, instanceAgg, policy.ChangeMinLength(10), policy.ChangeRejectBreached(true)),
+			},
+			want: &PasswordComplexityPolicy{
+				ID:             "instance1",
+				ResourceOwner:  "instance1",
+				State:          domain.PolicyStateActive,
+				MinLength:      10,
+				HasLowercase:   true,
+				HasUppercase:   true,
+				HasNumber:      true,
+				HasSymbol:      true,
+				RejectBreached: true,
+				IsDefault:      true,
+			},
+		},
+		{
+			name: "org policy",
+			events: []eventstore.Event{
+				instance.NewPasswordComplexityPolicyAddedEvent(ctx, instanceAgg, 8, true, true, true, true, false, 0, false, false, false, nil, nil),
+				org.NewPasswordComplexityPolicyAddedEvent(ctx, orgAgg, 12, false, false, false, false, false, 0, false, false, false, nil, nil),
+			},
+			want: &PasswordComplexityPolicy{
+				ID:            "org1",
+				ResourceOwner: "org1",
+				State:         domain.PolicyStateActive,
+				MinLength:     12,
+			},
+		},
+		{
+			name: "org policy removed",
+			events: []eventstore.Event{
+				instance.NewPasswordComplexityPolicyAddedEvent(ctx, instanceAgg, 8, true, true, true, true, false, 0, false, false, false, nil, nil),
+				org.NewPasswordComplexityPolicyAddedEvent(ctx, orgAgg, 12, false, false, false, false, false, 0, false, false, false, nil, nil),
+				org.NewPasswordComplexityPolicyRemovedEvent(ctx, orgAgg),
+			},
+			want: &PasswordComplexityPolicy{
+				ID:            "instance1",
+				ResourceOwner: "instance1",
+				State:         domain.PolicyStateActive,
+				MinLength:     8,
+				HasLowercase:  true,
+				HasUppercase:  true,
+				HasNumber:     true,
+				HasSymbol:     true,
+				IsDefault:     true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := newPasswordComplexityPolicyAtReadModel("instance1", "org1", PointInTime{Sequence: 1})
+			rm.AppendEvents(tt.events...)
+			if err := rm.Reduce(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := rm.Policy(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Policy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
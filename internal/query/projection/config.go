@@ -12,6 +12,10 @@ type Config struct {
 	BulkLimit             uint64
 	Customizations        map[string]CustomConfig
 	HandleActiveInstances time.Duration
+	// LagMetricsInterval is the interval in which the lags of the projections are queried for the metrics
+	LagMetricsInterval time.Duration
+	// EventWebhookEvents are the event types per aggregate type which can be delivered to the event webhooks
+	EventWebhookEvents map[string][]string
 }
 
 type CustomConfig struct {
@@ -0,0 +1,347 @@
+package projection
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/handler"
+	"github.com/zitadel/zitadel/internal/eventstore/handler/crdb"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+const (
+	EventWebhookProjectionTable = "projections.event_webhooks"
+	EventWebhookDeliveryTable   = EventWebhookProjectionTable + "_" + eventWebhookDeliveryTableSuffix
+
+	EventWebhookIDCol            = "id"
+	EventWebhookCreationDateCol  = "creation_date"
+	EventWebhookChangeDateCol    = "change_date"
+	EventWebhookSequenceCol      = "sequence"
+	EventWebhookAddedSequenceCol = "added_sequence"
+	EventWebhookResourceOwnerCol = "resource_owner"
+	EventWebhookInstanceIDCol    = "instance_id"
+	EventWebhookNameCol          = "name"
+	EventWebhookCallURLCol       = "call_url"
+	EventWebhookEventTypesCol    = "event_types"
+	EventWebhookSigningKeyCol    = "signing_key"
+
+	eventWebhookDeliveryTableSuffix      = "deliveries"
+	EventWebhookDeliveryInstanceIDCol    = "instance_id"
+	EventWebhookDeliveryWebhookIDCol     = "webhook_id"
+	EventWebhookDeliveryEventSequenceCol = "event_sequence"
+	EventWebhookDeliveryEventTypeCol     = "event_type"
+	EventWebhookDeliveryAggregateTypeCol = "aggregate_type"
+	EventWebhookDeliveryAggregateIDCol   = "aggregate_id"
+	EventWebhookDeliveryCreationDateCol  = "creation_date"
+	EventWebhookDeliveryAttemptsCol      = "attempts"
+	EventWebhookDeliveryNextAttemptCol   = "next_attempt"
+	EventWebhookDeliveryLastErrorCol     = "last_error"
+	EventWebhookDeliveryStateCol         = "state"
+)
+
+// eventWebhookProjection projects the event webhooks of the instances
+// and adds a delivery (outbox entry) for each deliverable event to all webhooks subscribed to the event type.
+// The deliveries only reference the events, so no personal data is copied out of the (encrypted) events.
+// They are sent by the delivery worker of the notifications, outside the transaction of the projection.
+type eventWebhookProjection struct {
+	crdb.StatementHandler
+}
+
+func newEventWebhookProjection(ctx context.Context, config crdb.StatementHandlerConfig, deliverableEvents map[string][]string) *eventWebhookProjection {
+	p := new(eventWebhookProjection)
+	config.ProjectionName = EventWebhookProjectionTable
+	config.Reducers = p.reducers(deliverableEvents)
+	config.InitCheck = crdb.NewMultiTableCheck(
+		crdb.NewTable([]*crdb.Column{
+			crdb.NewColumn(EventWebhookIDCol, crdb.ColumnTypeText),
+			crdb.NewColumn(EventWebhookCreationDateCol, crdb.ColumnTypeTimestamp),
+			crdb.NewColumn(EventWebhookChangeDateCol, crdb.ColumnTypeTimestamp),
+			crdb.NewColumn(EventWebhookSequenceCol, crdb.ColumnTypeInt64),
+			crdb.NewColumn(EventWebhookAddedSequenceCol, crdb.ColumnTypeInt64),
+			crdb.NewColumn(EventWebhookResourceOwnerCol, crdb.ColumnTypeText),
+			crdb.NewColumn(EventWebhookInstanceIDCol, crdb.ColumnTypeText),
+			crdb.NewColumn(EventWebhookNameCol, crdb.ColumnTypeText),
+			crdb.NewColumn(EventWebhookCallURLCol, crdb.ColumnTypeText),
+			crdb.NewColumn(EventWebhookEventTypesCol, crdb.ColumnTypeTextArray),
+			crdb.NewColumn(EventWebhookSigningKeyCol, crdb.ColumnTypeJSONB),
+		},
+			crdb.NewPrimaryKey(EventWebhookInstanceIDCol, EventWebhookIDCol),
+		),
+		crdb.NewSuffixedTable([]*crdb.Column{
+			crdb.NewColumn(EventWebhookDeliveryInstanceIDCol, crdb.ColumnTypeText),
+			crdb.NewColumn(EventWebhookDeliveryWebhookIDCol, crdb.ColumnTypeText),
+			crdb.NewColumn(EventWebhookDeliveryEventSequenceCol, crdb.ColumnTypeInt64),
+			crdb.NewColumn(EventWebhookDeliveryEventTypeCol, crdb.ColumnTypeText),
+			crdb.NewColumn(EventWebhookDeliveryAggregateTypeCol, crdb.ColumnTypeText),
+			crdb.NewColumn(EventWebhookDeliveryAggregateIDCol, crdb.ColumnTypeText),
+			crdb.NewColumn(EventWebhookDeliveryCreationDateCol, crdb.ColumnTypeTimestamp),
+			crdb.NewColumn(EventWebhookDeliveryAttemptsCol, crdb.ColumnTypeInt64, crdb.Default(0)),
+			crdb.NewColumn(EventWebhookDeliveryNextAttemptCol, crdb.ColumnTypeTimestamp),
+			crdb.NewColumn(EventWebhookDeliveryLastErrorCol, crdb.ColumnTypeText, crdb.Nullable()),
+			crdb.NewColumn(EventWebhookDeliveryStateCol, crdb.ColumnTypeEnum),
+		},
+			crdb.NewPrimaryKey(EventWebhookDeliveryInstanceIDCol, EventWebhookDeliveryWebhookIDCol, EventWebhookDeliveryEventSequenceCol),
+			eventWebhookDeliveryTableSuffix,
+			crdb.WithIndex(crdb.NewIndex("due", []string{EventWebhookDeliveryStateCol, EventWebhookDeliveryNextAttemptCol})),
+		),
+	)
+	p.StatementHandler = crdb.NewStatementHandler(ctx, config)
+	return p
+}
+
+func (p *eventWebhookProjection) reducers(deliverableEvents map[string][]string) []handler.AggregateReducer {
+	instanceReducers := []handler.EventReducer{
+		{
+			Event:  instance.EventWebhookAddedEventType,
+			Reduce: p.reduceEventWebhookAdded,
+		},
+		{
+			Event:  instance.EventWebhookChangedEventType,
+			Reduce: p.reduceEventWebhookChanged,
+		},
+		{
+			Event:  instance.EventWebhookSigningKeyChangedEventType,
+			Reduce: p.reduceEventWebhookSigningKeyChanged,
+		},
+		{
+			Event:  instance.EventWebhookRemovedEventType,
+			Reduce: p.reduceEventWebhookRemoved,
+		},
+		{
+			Event:  instance.InstanceRemovedEventType,
+			Reduce: p.reduceInstanceRemoved,
+		},
+	}
+	reduced := make(map[eventstore.EventType]bool, len(instanceReducers))
+	for _, reducer := range instanceReducers {
+		reduced[reducer.Event] = true
+	}
+
+	reducers := []handler.AggregateReducer{
+		{
+			Aggregate:     instance.AggregateType,
+			EventRedusers: instanceReducers,
+		},
+	}
+	for aggregateType, eventTypes := range deliverableEvents {
+		eventReducers := make([]handler.EventReducer, 0, len(eventTypes))
+		for _, eventType := range eventTypes {
+			// the events of the webhooks themselves are reduced above and never delivered
+			if reduced[eventstore.EventType(eventType)] {
+				continue
+			}
+			reduced[eventstore.EventType(eventType)] = true
+			eventReducers = append(eventReducers, handler.EventReducer{
+				Event:  eventstore.EventType(eventType),
+				Reduce: p.reduceDeliverableEvent,
+			})
+		}
+		if eventstore.AggregateType(aggregateType) == instance.AggregateType {
+			reducers[0].EventRedusers = append(reducers[0].EventRedusers, eventReducers...)
+			continue
+		}
+		if len(eventReducers) == 0 {
+			continue
+		}
+		reducers = append(reducers, handler.AggregateReducer{
+			Aggregate:     eventstore.AggregateType(aggregateType),
+			EventRedusers: eventReducers,
+		})
+	}
+	return reducers
+}
+
+func (p *eventWebhookProjection) reduceEventWebhookAdded(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*instance.EventWebhookAddedEvent)
+	if !ok {
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-Wh1ad", "reduce.wrong.event.type %s", instance.EventWebhookAddedEventType)
+	}
+	return crdb.NewCreateStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(EventWebhookIDCol, e.ID),
+			handler.NewCol(EventWebhookCreationDateCol, e.CreationDate()),
+			handler.NewCol(EventWebhookChangeDateCol, e.CreationDate()),
+			handler.NewCol(EventWebhookSequenceCol, e.Sequence()),
+			handler.NewCol(EventWebhookAddedSequenceCol, e.Sequence()),
+			handler.NewCol(EventWebhookResourceOwnerCol, e.Aggregate().ResourceOwner),
+			handler.NewCol(EventWebhookInstanceIDCol, e.Aggregate().InstanceID),
+			handler.NewCol(EventWebhookNameCol, e.Name),
+			handler.NewCol(EventWebhookCallURLCol, e.CallURL),
+			handler.NewCol(EventWebhookEventTypesCol, database.StringArray(e.EventTypes)),
+			handler.NewCol(EventWebhookSigningKeyCol, e.SigningKey),
+		},
+	), nil
+}
+
+func (p *eventWebhookProjection) reduceEventWebhookChanged(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*instance.EventWebhookChangedEvent)
+	if !ok {
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-Wh2ch", "reduce.wrong.event.type %s", instance.EventWebhookChangedEventType)
+	}
+	columns := []handler.Column{
+		handler.NewCol(EventWebhookChangeDateCol, e.CreationDate()),
+		handler.NewCol(EventWebhookSequenceCol, e.Sequence()),
+	}
+	if e.Name != nil {
+		columns = append(columns, handler.NewCol(EventWebhookNameCol, *e.Name))
+	}
+	if e.CallURL != nil {
+		columns = append(columns, handler.NewCol(EventWebhookCallURLCol, *e.CallURL))
+	}
+	if e.EventTypes != nil {
+		columns = append(columns, handler.NewCol(EventWebhookEventTypesCol, database.StringArray(*e.EventTypes)))
+	}
+	return crdb.NewUpdateStatement(
+		e,
+		columns,
+		[]handler.Condition{
+			handler.NewCond(EventWebhookIDCol, e.ID),
+			handler.NewCond(EventWebhookInstanceIDCol, e.Aggregate().InstanceID),
+		},
+	), nil
+}
+
+func (p *eventWebhookProjection) reduceEventWebhookSigningKeyChanged(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*instance.EventWebhookSigningKeyChangedEvent)
+	if !ok {
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-Wh3sk", "reduce.wrong.event.type %s", instance.EventWebhookSigningKeyChangedEventType)
+	}
+	return crdb.NewUpdateStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(EventWebhookChangeDateCol, e.CreationDate()),
+			handler.NewCol(EventWebhookSequenceCol, e.Sequence()),
+			handler.NewCol(EventWebhookSigningKeyCol, e.SigningKey),
+		},
+		[]handler.Condition{
+			handler.NewCond(EventWebhookIDCol, e.ID),
+			handler.NewCond(EventWebhookInstanceIDCol, e.Aggregate().InstanceID),
+		},
+	), nil
+}
+
+func (p *eventWebhookProjection) reduceEventWebhookRemoved(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*instance.EventWebhookRemovedEvent)
+	if !ok {
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-Wh4rm", "reduce.wrong.event.type %s", instance.EventWebhookRemovedEventType)
+	}
+	return crdb.NewMultiStatement(
+		e,
+		crdb.AddDeleteStatement(
+			[]handler.Condition{
+				handler.NewCond(EventWebhookIDCol, e.ID),
+				handler.NewCond(EventWebhookInstanceIDCol, e.Aggregate().InstanceID),
+			},
+		),
+		crdb.AddDeleteStatement(
+			[]handler.Condition{
+				handler.NewCond(EventWebhookDeliveryWebhookIDCol, e.ID),
+				handler.NewCond(EventWebhookDeliveryInstanceIDCol, e.Aggregate().InstanceID),
+			},
+			crdb.WithTableSuffix(eventWebhookDeliveryTableSuffix),
+		),
+	), nil
+}
+
+func (p *eventWebhookProjection) reduceInstanceRemoved(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*instance.InstanceRemovedEvent)
+	if !ok {
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-Wh5rm", "reduce.wrong.event.type %s", instance.InstanceRemovedEventType)
+	}
+	return crdb.NewMultiStatement(
+		e,
+		crdb.AddDeleteStatement(
+			[]handler.Condition{
+				handler.NewCond(EventWebhookInstanceIDCol, e.Aggregate().ID),
+			},
+		),
+		crdb.AddDeleteStatement(
+			[]handler.Condition{
+				handler.NewCond(EventWebhookDeliveryInstanceIDCol, e.Aggregate().ID),
+			},
+			crdb.WithTableSuffix(eventWebhookDeliveryTableSuffix),
+		),
+	), nil
+}
+
+// reduceDeliverableEvent adds a pending delivery of the event for every webhook of the instance subscribed to its type.
+// Webhooks added after the event are ignored, so a replay of the projection doesn't deliver events older than the webhook.
+// Existing deliveries are kept, so a replay doesn't reset the attempts.
+func (p *eventWebhookProjection) reduceDeliverableEvent(event eventstore.Event) (*handler.Statement, error) {
+	args := []interface{}{
+		event.Sequence(),
+		string(event.Type()),
+		string(event.Aggregate().Type),
+		event.Aggregate().ID,
+		event.CreationDate(),
+		domain.EventWebhookDeliveryStatePending,
+		event.Aggregate().InstanceID,
+		database.StringArray{string(event.Type())},
+	}
+	return &handler.Statement{
+		AggregateType:    event.Aggregate().Type,
+		Sequence:         event.Sequence(),
+		PreviousSequence: event.PreviousAggregateTypeSequence(),
+		InstanceID:       event.Aggregate().InstanceID,
+		Execute: func(ex handler.Executer, projectionName string) error {
+			if projectionName == "" {
+				return handler.ErrNoProjection
+			}
+			stmt := "INSERT INTO " + projectionName + "_" + eventWebhookDeliveryTableSuffix + " (" +
+				EventWebhookDeliveryInstanceIDCol + ", " +
+				EventWebhookDeliveryWebhookIDCol + ", " +
+				EventWebhookDeliveryEventSequenceCol + ", " +
+				EventWebhookDeliveryEventTypeCol + ", " +
+				EventWebhookDeliveryAggregateTypeCol + ", " +
+				EventWebhookDeliveryAggregateIDCol + ", " +
+				EventWebhookDeliveryCreationDateCol + ", " +
+				EventWebhookDeliveryAttemptsCol + ", " +
+				EventWebhookDeliveryNextAttemptCol + ", " +
+				EventWebhookDeliveryStateCol +
+				") SELECT " + EventWebhookInstanceIDCol + ", " + EventWebhookIDCol + ", $1, $2, $3, $4, $5, 0, $5, $6" +
+				" FROM " + projectionName +
+				" WHERE " + EventWebhookInstanceIDCol + " = $7 AND " + EventWebhookEventTypesCol + " @> $8 AND " + EventWebhookAddedSequenceCol + " < $1" +
+				" ON CONFLICT DO NOTHING"
+			if _, err := ex.Exec(stmt, args...); err != nil {
+				return errors.ThrowInternal(err, "HANDL-Wh7dl", "exec failed")
+			}
+			return nil
+		},
+	}, nil
+}
+
+// EventWebhookPayload is the body sent to the event webhooks,
+// it's built from the event loaded at the time of the delivery
+type EventWebhookPayload struct {
+	InstanceID    string          `json:"instanceId"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   string          `json:"aggregateId"`
+	ResourceOwner string          `json:"resourceOwner"`
+	Sequence      uint64          `json:"sequence"`
+	CreationDate  time.Time       `json:"creationDate"`
+	EventType     string          `json:"eventType"`
+	EditorUser    string          `json:"editorUser"`
+	EditorService string          `json:"editorService"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+}
+
+func EventWebhookPayloadFromEvent(event eventstore.Event) *EventWebhookPayload {
+	return &EventWebhookPayload{
+		InstanceID:    event.Aggregate().InstanceID,
+		AggregateType: string(event.Aggregate().Type),
+		AggregateID:   event.Aggregate().ID,
+		ResourceOwner: event.Aggregate().ResourceOwner,
+		Sequence:      event.Sequence(),
+		CreationDate:  event.CreationDate(),
+		EventType:     string(event.Type()),
+		EditorUser:    event.EditorUser(),
+		EditorService: event.EditorService(),
+		Payload:       event.DataAsBytes(),
+	}
+}
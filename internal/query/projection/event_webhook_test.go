@@ -0,0 +1,282 @@
+package projection
+
+import (
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/handler"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+func TestEventWebhookProjection_reduces(t *testing.T) {
+	type args struct {
+		event func(t *testing.T) eventstore.Event
+	}
+	tests := []struct {
+		name   string
+		args   args
+		reduce func(event eventstore.Event) (*handler.Statement, error)
+		want   wantReduce
+	}{
+		{
+			name: "reduceEventWebhookAdded",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(instance.EventWebhookAddedEventType),
+					instance.AggregateType,
+					[]byte(`{
+						"id": "webhook-id",
+						"name": "name",
+						"callUrl": "https://example.com/hook",
+						"eventTypes": ["user.human.added"],
+						"signingKey": {
+							"cryptoType": 0,
+							"algorithm": "RSA-265",
+							"keyId": "key-id"
+						}
+					}`),
+				), instance.EventWebhookAddedEventMapper),
+			},
+			reduce: (&eventWebhookProjection{}).reduceEventWebhookAdded,
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("instance"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "INSERT INTO projections.event_webhooks (id, creation_date, change_date, sequence, added_sequence, resource_owner, instance_id, name, call_url, event_types, signing_key) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+							expectedArgs: []interface{}{
+								"webhook-id",
+								anyArg{},
+								anyArg{},
+								uint64(15),
+								uint64(15),
+								"ro-id",
+								"instance-id",
+								"name",
+								"https://example.com/hook",
+								database.StringArray{"user.human.added"},
+								anyArg{},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceEventWebhookChanged",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(instance.EventWebhookChangedEventType),
+					instance.AggregateType,
+					[]byte(`{
+						"id": "webhook-id",
+						"callUrl": "https://example.com/other",
+						"eventTypes": ["user.human.added", "user.removed"]
+					}`),
+				), instance.EventWebhookChangedEventMapper),
+			},
+			reduce: (&eventWebhookProjection{}).reduceEventWebhookChanged,
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("instance"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.event_webhooks SET (change_date, sequence, call_url, event_types) = ($1, $2, $3, $4) WHERE (id = $5) AND (instance_id = $6)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								"https://example.com/other",
+								database.StringArray{"user.human.added", "user.removed"},
+								"webhook-id",
+								"instance-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceEventWebhookSigningKeyChanged",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(instance.EventWebhookSigningKeyChangedEventType),
+					instance.AggregateType,
+					[]byte(`{
+						"id": "webhook-id",
+						"signingKey": {
+							"cryptoType": 0,
+							"algorithm": "RSA-265",
+							"keyId": "key-id"
+						}
+					}`),
+				), instance.EventWebhookSigningKeyChangedEventMapper),
+			},
+			reduce: (&eventWebhookProjection{}).reduceEventWebhookSigningKeyChanged,
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("instance"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.event_webhooks SET (change_date, sequence, signing_key) = ($1, $2, $3) WHERE (id = $4) AND (instance_id = $5)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								anyArg{},
+								"webhook-id",
+								"instance-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceEventWebhookRemoved",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(instance.EventWebhookRemovedEventType),
+					instance.AggregateType,
+					[]byte(`{"id": "webhook-id"}`),
+				), instance.EventWebhookRemovedEventMapper),
+			},
+			reduce: (&eventWebhookProjection{}).reduceEventWebhookRemoved,
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("instance"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "DELETE FROM projections.event_webhooks WHERE (id = $1) AND (instance_id = $2)",
+							expectedArgs: []interface{}{
+								"webhook-id",
+								"instance-id",
+							},
+						},
+						{
+							expectedStmt: "DELETE FROM projections.event_webhooks_deliveries WHERE (webhook_id = $1) AND (instance_id = $2)",
+							expectedArgs: []interface{}{
+								"webhook-id",
+								"instance-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "instance reduceInstanceRemoved",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(instance.InstanceRemovedEventType),
+					instance.AggregateType,
+					nil,
+				), instance.InstanceRemovedEventMapper),
+			},
+			reduce: (&eventWebhookProjection{}).reduceInstanceRemoved,
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("instance"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "DELETE FROM projections.event_webhooks WHERE (instance_id = $1)",
+							expectedArgs: []interface{}{
+								"agg-id",
+							},
+						},
+						{
+							expectedStmt: "DELETE FROM projections.event_webhooks_deliveries WHERE (instance_id = $1)",
+							expectedArgs: []interface{}{
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := baseEvent(t)
+			got, err := tt.reduce(event)
+			if _, ok := err.(errors.InvalidArgument); !ok {
+				t.Errorf("no wrong event mapping: %v, got: %v", err, got)
+			}
+
+			event = tt.args.event(t)
+			got, err = tt.reduce(event)
+			assertReduce(t, got, err, EventWebhookProjectionTable, tt.want)
+		})
+	}
+}
+
+func TestEventWebhookProjection_reduceDeliverableEvent(t *testing.T) {
+	event := getEvent(testEvent(
+		repository.EventType(user.HumanAddedType),
+		user.AggregateType,
+		[]byte(`{"userName": "username"}`),
+	), user.HumanAddedEventMapper)(t)
+
+	got, err := (&eventWebhookProjection{}).reduceDeliverableEvent(event)
+	assertReduce(t, got, err, EventWebhookProjectionTable, wantReduce{
+		aggregateType:    eventstore.AggregateType("user"),
+		sequence:         15,
+		previousSequence: 10,
+		executer: &testExecuter{
+			executions: []execution{
+				{
+					expectedStmt: "INSERT INTO projections.event_webhooks_deliveries (instance_id, webhook_id, event_sequence, event_type, aggregate_type, aggregate_id, creation_date, attempts, next_attempt, state) SELECT instance_id, id, $1, $2, $3, $4, $5, 0, $5, $6 FROM projections.event_webhooks WHERE instance_id = $7 AND event_types @> $8 AND added_sequence < $1 ON CONFLICT DO NOTHING",
+					expectedArgs: []interface{}{
+						uint64(15),
+						"user.human.added",
+						"user",
+						"agg-id",
+						anyArg{},
+						domain.EventWebhookDeliveryStatePending,
+						"instance-id",
+						database.StringArray{"user.human.added"},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestEventWebhookProjection_reducers(t *testing.T) {
+	reducers := (&eventWebhookProjection{}).reducers(map[string][]string{
+		"instance": {string(instance.EventWebhookAddedEventType), string(instance.InstanceDomainAddedEventType)},
+		"user":     {string(user.HumanAddedType), string(user.HumanAddedType)},
+		"org":      {string(instance.EventWebhookRemovedEventType)},
+	})
+	got := make(map[eventstore.AggregateType][]eventstore.EventType, len(reducers))
+	for _, reducer := range reducers {
+		if _, ok := got[reducer.Aggregate]; ok {
+			t.Errorf("aggregate %s subscribed twice", reducer.Aggregate)
+		}
+		for _, eventReducer := range reducer.EventRedusers {
+			got[reducer.Aggregate] = append(got[reducer.Aggregate], eventReducer.Event)
+		}
+	}
+	if len(got) != 2 {
+		t.Errorf("expected instance and user aggregate, got %v", got)
+	}
+	if len(got[instance.AggregateType]) != 6 || got[instance.AggregateType][5] != instance.InstanceDomainAddedEventType {
+		t.Errorf("unexpected instance reducers %v", got[instance.AggregateType])
+	}
+	if len(got[user.AggregateType]) != 1 || got[user.AggregateType][0] != user.HumanAddedType {
+		t.Errorf("unexpected user reducers %v", got[user.AggregateType])
+	}
+}
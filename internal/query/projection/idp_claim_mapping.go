@@ -0,0 +1,191 @@
+package projection
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/handler"
+	"github.com/zitadel/zitadel/internal/eventstore/handler/crdb"
+	"github.com/zitadel/zitadel/internal/repository/idp"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
+)
+
+const (
+	IDPClaimMappingTable = "projections.idp_claim_mappings"
+
+	IDPClaimMappingIDPIDCol         = "idp_id"
+	IDPClaimMappingCreationDateCol  = "creation_date"
+	IDPClaimMappingChangeDateCol    = "change_date"
+	IDPClaimMappingSequenceCol      = "sequence"
+	IDPClaimMappingResourceOwnerCol = "resource_owner"
+	IDPClaimMappingInstanceIDCol    = "instance_id"
+	IDPClaimMappingMappingsCol      = "mappings"
+	IDPClaimMappingOwnerRemovedCol  = "owner_removed"
+)
+
+type idpClaimMappingProjection struct {
+	crdb.StatementHandler
+}
+
+func newIDPClaimMappingProjection(ctx context.Context, config crdb.StatementHandlerConfig) *idpClaimMappingProjection {
+	p := new(idpClaimMappingProjection)
+	config.ProjectionName = IDPClaimMappingTable
+	config.Reducers = p.reducers()
+	config.InitCheck = crdb.NewTableCheck(
+		crdb.NewTable([]*crdb.Column{
+			crdb.NewColumn(IDPClaimMappingIDPIDCol, crdb.ColumnTypeText),
+			crdb.NewColumn(IDPClaimMappingCreationDateCol, crdb.ColumnTypeTimestamp),
+			crdb.NewColumn(IDPClaimMappingChangeDateCol, crdb.ColumnTypeTimestamp),
+			crdb.NewColumn(IDPClaimMappingSequenceCol, crdb.ColumnTypeInt64),
+			crdb.NewColumn(IDPClaimMappingResourceOwnerCol, crdb.ColumnTypeText),
+			crdb.NewColumn(IDPClaimMappingInstanceIDCol, crdb.ColumnTypeText),
+			crdb.NewColumn(IDPClaimMappingMappingsCol, crdb.ColumnTypeJSONB, crdb.Nullable()),
+			crdb.NewColumn(IDPClaimMappingOwnerRemovedCol, crdb.ColumnTypeBool, crdb.Default(false)),
+		},
+			crdb.NewPrimaryKey(IDPClaimMappingInstanceIDCol, IDPClaimMappingIDPIDCol),
+			crdb.WithIndex(crdb.NewIndex("owner_removed", []string{IDPClaimMappingOwnerRemovedCol})),
+		),
+	)
+	p.StatementHandler = crdb.NewStatementHandler(ctx, config)
+	return p
+}
+
+func (p *idpClaimMappingProjection) reducers() []handler.AggregateReducer {
+	return []handler.AggregateReducer{
+		{
+			Aggregate: instance.AggregateType,
+			EventRedusers: []handler.EventReducer{
+				{
+					Event:  instance.IDPClaimMappingsSetEventType,
+					Reduce: p.reduceSet,
+				},
+				{
+					Event:  instance.IDPRemovedEventType,
+					Reduce: p.reduceIDPRemoved,
+				},
+				{
+					Event:  instance.IDPConfigRemovedEventType,
+					Reduce: p.reduceIDPConfigRemoved,
+				},
+				{
+					Event:  instance.InstanceRemovedEventType,
+					Reduce: reduceInstanceRemovedHelper(IDPClaimMappingInstanceIDCol),
+				},
+			},
+		},
+		{
+			Aggregate: org.AggregateType,
+			EventRedusers: []handler.EventReducer{
+				{
+					Event:  org.IDPClaimMappingsSetEventType,
+					Reduce: p.reduceSet,
+				},
+				{
+					Event:  org.IDPRemovedEventType,
+					Reduce: p.reduceIDPRemoved,
+				},
+				{
+					Event:  org.IDPConfigRemovedEventType,
+					Reduce: p.reduceIDPConfigRemoved,
+				},
+				{
+					Event:  org.OrgRemovedEventType,
+					Reduce: p.reduceOwnerRemoved,
+				},
+			},
+		},
+	}
+}
+
+func (p *idpClaimMappingProjection) reduceSet(event eventstore.Event) (*handler.Statement, error) {
+	var e idp.ClaimMappingsSetEvent
+	switch event := event.(type) {
+	case *instance.IDPClaimMappingsSetEvent:
+		e = event.ClaimMappingsSetEvent
+	case *org.IDPClaimMappingsSetEvent:
+		e = event.ClaimMappingsSetEvent
+	default:
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-Cm4lw", "reduce.wrong.event.type %v", []eventstore.EventType{instance.IDPClaimMappingsSetEventType, org.IDPClaimMappingsSetEventType})
+	}
+
+	return crdb.NewUpsertStatement(
+		&e,
+		[]handler.Column{
+			handler.NewCol(IDPClaimMappingInstanceIDCol, nil),
+			handler.NewCol(IDPClaimMappingIDPIDCol, nil),
+		},
+		[]handler.Column{
+			handler.NewCol(IDPClaimMappingIDPIDCol, e.ID),
+			handler.NewCol(IDPClaimMappingCreationDateCol, e.CreationDate()),
+			handler.NewCol(IDPClaimMappingChangeDateCol, e.CreationDate()),
+			handler.NewCol(IDPClaimMappingSequenceCol, e.Sequence()),
+			handler.NewCol(IDPClaimMappingResourceOwnerCol, e.Aggregate().ResourceOwner),
+			handler.NewCol(IDPClaimMappingInstanceIDCol, e.Aggregate().InstanceID),
+			handler.NewCol(IDPClaimMappingMappingsCol, database.JSONArray[*domain.IDPClaimMapping](e.Mappings)),
+		},
+	), nil
+}
+
+func (p *idpClaimMappingProjection) reduceIDPRemoved(event eventstore.Event) (*handler.Statement, error) {
+	var e idp.RemovedEvent
+	switch event := event.(type) {
+	case *instance.IDPRemovedEvent:
+		e = event.RemovedEvent
+	case *org.IDPRemovedEvent:
+		e = event.RemovedEvent
+	default:
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-Cm5lx", "reduce.wrong.event.type %v", []eventstore.EventType{instance.IDPRemovedEventType, org.IDPRemovedEventType})
+	}
+
+	return crdb.NewDeleteStatement(
+		&e,
+		[]handler.Condition{
+			handler.NewCond(IDPClaimMappingIDPIDCol, e.ID),
+			handler.NewCond(IDPClaimMappingInstanceIDCol, e.Aggregate().InstanceID),
+		},
+	), nil
+}
+
+func (p *idpClaimMappingProjection) reduceIDPConfigRemoved(event eventstore.Event) (*handler.Statement, error) {
+	var idpID string
+	switch e := event.(type) {
+	case *instance.IDPConfigRemovedEvent:
+		idpID = e.ConfigID
+	case *org.IDPConfigRemovedEvent:
+		idpID = e.ConfigID
+	default:
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-Cm6ly", "reduce.wrong.event.type %v", []eventstore.EventType{instance.IDPConfigRemovedEventType, org.IDPConfigRemovedEventType})
+	}
+
+	return crdb.NewDeleteStatement(
+		event,
+		[]handler.Condition{
+			handler.NewCond(IDPClaimMappingIDPIDCol, idpID),
+			handler.NewCond(IDPClaimMappingInstanceIDCol, event.Aggregate().InstanceID),
+		},
+	), nil
+}
+
+func (p *idpClaimMappingProjection) reduceOwnerRemoved(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*org.OrgRemovedEvent)
+	if !ok {
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-Cm7lz", "reduce.wrong.event.type %s", org.OrgRemovedEventType)
+	}
+
+	return crdb.NewUpdateStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(IDPClaimMappingChangeDateCol, e.CreationDate()),
+			handler.NewCol(IDPClaimMappingSequenceCol, e.Sequence()),
+			handler.NewCol(IDPClaimMappingOwnerRemovedCol, true),
+		},
+		[]handler.Condition{
+			handler.NewCond(IDPClaimMappingInstanceIDCol, e.Aggregate().InstanceID),
+			handler.NewCond(IDPClaimMappingResourceOwnerCol, e.Aggregate().ID),
+		},
+	), nil
+}
@@ -0,0 +1,226 @@
+package projection
+
+import (
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/handler"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
+)
+
+func TestIDPClaimMappingProjection_reduces(t *testing.T) {
+	type args struct {
+		event func(t *testing.T) eventstore.Event
+	}
+	tests := []struct {
+		name   string
+		args   args
+		reduce func(event eventstore.Event) (*handler.Statement, error)
+		want   wantReduce
+	}{
+		{
+			name: "instance reduceSet",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(instance.IDPClaimMappingsSetEventType),
+					instance.AggregateType,
+					[]byte(`{
+	"id": "idp-id",
+	"mappings": [
+		{"claim": "given_name", "target": 1},
+		{"claim": "$.groups[*]", "target": 14, "key": "project-id", "transforms": [1], "roles": ["admin"]}
+	]
+}`),
+				), instance.IDPClaimMappingsSetEventMapper),
+			},
+			reduce: (&idpClaimMappingProjection{}).reduceSet,
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("instance"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "INSERT INTO projections.idp_claim_mappings (idp_id, creation_date, change_date, sequence, resource_owner, instance_id, mappings) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (instance_id, idp_id) DO UPDATE SET (creation_date, change_date, sequence, resource_owner, mappings) = (EXCLUDED.creation_date, EXCLUDED.change_date, EXCLUDED.sequence, EXCLUDED.resource_owner, EXCLUDED.mappings)",
+							expectedArgs: []interface{}{
+								"idp-id",
+								anyArg{},
+								anyArg{},
+								uint64(15),
+								"ro-id",
+								"instance-id",
+								database.JSONArray[*domain.IDPClaimMapping]{
+									{Claim: "given_name", Target: domain.IDPClaimMappingTargetFirstName},
+									{Claim: "$.groups[*]", Target: domain.IDPClaimMappingTargetRole, Key: "project-id", Transforms: []domain.IDPClaimTransform{domain.IDPClaimTransformLowercase}, Roles: []string{"admin"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "org reduceSet",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(org.IDPClaimMappingsSetEventType),
+					org.AggregateType,
+					[]byte(`{
+	"id": "idp-id",
+	"mappings": []
+}`),
+				), org.IDPClaimMappingsSetEventMapper),
+			},
+			reduce: (&idpClaimMappingProjection{}).reduceSet,
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("org"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "INSERT INTO projections.idp_claim_mappings (idp_id, creation_date, change_date, sequence, resource_owner, instance_id, mappings) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (instance_id, idp_id) DO UPDATE SET (creation_date, change_date, sequence, resource_owner, mappings) = (EXCLUDED.creation_date, EXCLUDED.change_date, EXCLUDED.sequence, EXCLUDED.resource_owner, EXCLUDED.mappings)",
+							expectedArgs: []interface{}{
+								"idp-id",
+								anyArg{},
+								anyArg{},
+								uint64(15),
+								"ro-id",
+								"instance-id",
+								database.JSONArray[*domain.IDPClaimMapping]{},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "instance reduceIDPRemoved",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(instance.IDPRemovedEventType),
+					instance.AggregateType,
+					[]byte(`{"id": "idp-id"}`),
+				), instance.IDPRemovedEventMapper),
+			},
+			reduce: (&idpClaimMappingProjection{}).reduceIDPRemoved,
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("instance"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "DELETE FROM projections.idp_claim_mappings WHERE (idp_id = $1) AND (instance_id = $2)",
+							expectedArgs: []interface{}{
+								"idp-id",
+								"instance-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "org reduceIDPConfigRemoved",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(org.IDPConfigRemovedEventType),
+					org.AggregateType,
+					[]byte(`{"idpConfigId": "idp-id"}`),
+				), org.IDPConfigRemovedEventMapper),
+			},
+			reduce: (&idpClaimMappingProjection{}).reduceIDPConfigRemoved,
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("org"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "DELETE FROM projections.idp_claim_mappings WHERE (idp_id = $1) AND (instance_id = $2)",
+							expectedArgs: []interface{}{
+								"idp-id",
+								"instance-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceOwnerRemoved",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(org.OrgRemovedEventType),
+					org.AggregateType,
+					nil,
+				), org.OrgRemovedEventMapper),
+			},
+			reduce: (&idpClaimMappingProjection{}).reduceOwnerRemoved,
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("org"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.idp_claim_mappings SET (change_date, sequence, owner_removed) = ($1, $2, $3) WHERE (instance_id = $4) AND (resource_owner = $5)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								true,
+								"instance-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceInstanceRemoved",
+			args: args{
+				event: getEvent(testEvent(
+					repository.EventType(instance.InstanceRemovedEventType),
+					instance.AggregateType,
+					nil,
+				), instance.InstanceRemovedEventMapper),
+			},
+			reduce: reduceInstanceRemovedHelper(IDPClaimMappingInstanceIDCol),
+			want: wantReduce{
+				aggregateType:    eventstore.AggregateType("instance"),
+				sequence:         15,
+				previousSequence: 10,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "DELETE FROM projections.idp_claim_mappings WHERE (instance_id = $1)",
+							expectedArgs: []interface{}{
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := baseEvent(t)
+			got, err := tt.reduce(event)
+			if _, ok := err.(errors.InvalidArgument); !ok {
+				t.Errorf("no wrong event mapping: %v, got: %v", err, got)
+			}
+
+			event = tt.args.event(t)
+			got, err = tt.reduce(event)
+			assertReduce(t, got, err, IDPClaimMappingTable, tt.want)
+		})
+	}
+}
@@ -17,7 +17,7 @@ import (
 )
 
 const (
-	IDPTemplateTable                 = "projections.idp_templates5"
+	IDPTemplateTable                 = "projections.idp_templates6"
 	IDPTemplateOAuthTable            = IDPTemplateTable + "_" + IDPTemplateOAuthSuffix
 	IDPTemplateOIDCTable             = IDPTemplateTable + "_" + IDPTemplateOIDCSuffix
 	IDPTemplateJWTTable              = IDPTemplateTable + "_" + IDPTemplateJWTSuffix
@@ -28,6 +28,7 @@ const (
 	IDPTemplateGitLabSelfHostedTable = IDPTemplateTable + "_" + IDPTemplateGitLabSelfHostedSuffix
 	IDPTemplateGoogleTable           = IDPTemplateTable + "_" + IDPTemplateGoogleSuffix
 	IDPTemplateLDAPTable             = IDPTemplateTable + "_" + IDPTemplateLDAPSuffix
+	IDPTemplateSAMLTable             = IDPTemplateTable + "_" + IDPTemplateSAMLSuffix
 
 	IDPTemplateOAuthSuffix            = "oauth2"
 	IDPTemplateOIDCSuffix             = "oidc"
@@ -39,6 +40,7 @@ const (
 	IDPTemplateGitLabSelfHostedSuffix = "gitlab_self_hosted"
 	IDPTemplateGoogleSuffix           = "google"
 	IDPTemplateLDAPSuffix             = "ldap2"
+	IDPTemplateSAMLSuffix             = "saml"
 
 	IDPTemplateIDCol                = "id"
 	IDPTemplateCreationDateCol      = "creation_date"
@@ -147,6 +149,19 @@ const (
 	LDAPPreferredLanguageAttributeCol = "preferred_language_attribute"
 	LDAPAvatarURLAttributeCol         = "avatar_url_attribute"
 	LDAPProfileAttributeCol           = "profile_attribute"
+	LDAPGroupsAttributeCol            = "groups_attribute"
+	LDAPGroupBaseCol                  = "group_base"
+	LDAPGroupMemberAttributeCol       = "group_member_attribute"
+	LDAPNestedGroupsCol               = "nested_groups"
+	LDAPGroupMappingsCol              = "group_mappings"
+
+	SAMLIDCol                = "idp_id"
+	SAMLInstanceIDCol        = "instance_id"
+	SAMLMetadataCol          = "metadata"
+	SAMLKeyCol               = "key"
+	SAMLCertificateCol       = "certificate"
+	SAMLBindingCol           = "binding"
+	SAMLWithSignedRequestCol = "with_signed_request"
 )
 
 type idpTemplateProjection struct {
@@ -316,11 +331,29 @@ func newIDPTemplateProjection(ctx context.Context, config crdb.StatementHandlerC
 			crdb.NewColumn(LDAPPreferredLanguageAttributeCol, crdb.ColumnTypeText, crdb.Nullable()),
 			crdb.NewColumn(LDAPAvatarURLAttributeCol, crdb.ColumnTypeText, crdb.Nullable()),
 			crdb.NewColumn(LDAPProfileAttributeCol, crdb.ColumnTypeText, crdb.Nullable()),
+			crdb.NewColumn(LDAPGroupsAttributeCol, crdb.ColumnTypeText, crdb.Nullable()),
+			crdb.NewColumn(LDAPGroupBaseCol, crdb.ColumnTypeText, crdb.Nullable()),
+			crdb.NewColumn(LDAPGroupMemberAttributeCol, crdb.ColumnTypeText, crdb.Nullable()),
+			crdb.NewColumn(LDAPNestedGroupsCol, crdb.ColumnTypeBool, crdb.Default(false)),
+			crdb.NewColumn(LDAPGroupMappingsCol, crdb.ColumnTypeJSONB, crdb.Nullable()),
 		},
 			crdb.NewPrimaryKey(LDAPInstanceIDCol, LDAPIDCol),
 			IDPTemplateLDAPSuffix,
 			crdb.WithForeignKey(crdb.NewForeignKeyOfPublicKeys()),
 		),
+		crdb.NewSuffixedTable([]*crdb.Column{
+			crdb.NewColumn(SAMLIDCol, crdb.ColumnTypeText),
+			crdb.NewColumn(SAMLInstanceIDCol, crdb.ColumnTypeText),
+			crdb.NewColumn(SAMLMetadataCol, crdb.ColumnTypeBytes),
+			crdb.NewColumn(SAMLKeyCol, crdb.ColumnTypeJSONB),
+			crdb.NewColumn(SAMLCertificateCol, crdb.ColumnTypeBytes),
+			crdb.NewColumn(SAMLBindingCol, crdb.ColumnTypeText, crdb.Nullable()),
+			crdb.NewColumn(SAMLWithSignedRequestCol, crdb.ColumnTypeBool, crdb.Nullable()),
+		},
+			crdb.NewPrimaryKey(SAMLInstanceIDCol, SAMLIDCol),
+			IDPTemplateSAMLSuffix,
+			crdb.WithForeignKey(crdb.NewForeignKeyOfPublicKeys()),
+		),
 	)
 	p.StatementHandler = crdb.NewStatementHandler(ctx, config)
 	return p
@@ -443,6 +476,14 @@ func (p *idpTemplateProjection) reducers() []handler.AggregateReducer {
 					Event:  instance.LDAPIDPChangedEventType,
 					Reduce: p.reduceLDAPIDPChanged,
 				},
+				{
+					Event:  instance.SAMLIDPAddedEventType,
+					Reduce: p.reduceSAMLIDPAdded,
+				},
+				{
+					Event:  instance.SAMLIDPChangedEventType,
+					Reduce: p.reduceSAMLIDPChanged,
+				},
 				{
 					Event:  instance.IDPConfigRemovedEventType,
 					Reduce: p.reduceIDPConfigRemoved,
@@ -572,6 +613,14 @@ func (p *idpTemplateProjection) reducers() []handler.AggregateReducer {
 					Event:  org.LDAPIDPChangedEventType,
 					Reduce: p.reduceLDAPIDPChanged,
 				},
+				{
+					Event:  org.SAMLIDPAddedEventType,
+					Reduce: p.reduceSAMLIDPAdded,
+				},
+				{
+					Event:  org.SAMLIDPChangedEventType,
+					Reduce: p.reduceSAMLIDPChanged,
+				},
 				{
 					Event:  org.IDPConfigRemovedEventType,
 					Reduce: p.reduceIDPConfigRemoved,
@@ -1811,6 +1860,11 @@ func (p *idpTemplateProjection) reduceLDAPIDPAdded(event eventstore.Event) (*han
 				handler.NewCol(LDAPPreferredLanguageAttributeCol, idpEvent.PreferredLanguageAttribute),
 				handler.NewCol(LDAPAvatarURLAttributeCol, idpEvent.AvatarURLAttribute),
 				handler.NewCol(LDAPProfileAttributeCol, idpEvent.ProfileAttribute),
+				handler.NewCol(LDAPGroupsAttributeCol, idpEvent.GroupsAttribute),
+				handler.NewCol(LDAPGroupBaseCol, idpEvent.GroupBase),
+				handler.NewCol(LDAPGroupMemberAttributeCol, idpEvent.GroupMemberAttribute),
+				handler.NewCol(LDAPNestedGroupsCol, idpEvent.NestedGroups),
+				handler.NewCol(LDAPGroupMappingsCol, database.JSONArray[idp.LDAPGroupMapping](idpEvent.GroupMappings)),
 			},
 			crdb.WithTableSuffix(IDPTemplateLDAPSuffix),
 		),
@@ -1858,6 +1912,98 @@ func (p *idpTemplateProjection) reduceLDAPIDPChanged(event eventstore.Event) (*h
 		ops...,
 	), nil
 }
+
+func (p *idpTemplateProjection) reduceSAMLIDPAdded(event eventstore.Event) (*handler.Statement, error) {
+	var idpEvent idp.SAMLIDPAddedEvent
+	var idpOwnerType domain.IdentityProviderType
+	switch e := event.(type) {
+	case *org.SAMLIDPAddedEvent:
+		idpEvent = e.SAMLIDPAddedEvent
+		idpOwnerType = domain.IdentityProviderTypeOrg
+	case *instance.SAMLIDPAddedEvent:
+		idpEvent = e.SAMLIDPAddedEvent
+		idpOwnerType = domain.IdentityProviderTypeSystem
+	default:
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-ys02m1", "reduce.wrong.event.type %v", []eventstore.EventType{org.SAMLIDPAddedEventType, instance.SAMLIDPAddedEventType})
+	}
+
+	return crdb.NewMultiStatement(
+		&idpEvent,
+		crdb.AddCreateStatement(
+			[]handler.Column{
+				handler.NewCol(IDPTemplateIDCol, idpEvent.ID),
+				handler.NewCol(IDPTemplateCreationDateCol, idpEvent.CreationDate()),
+				handler.NewCol(IDPTemplateChangeDateCol, idpEvent.CreationDate()),
+				handler.NewCol(IDPTemplateSequenceCol, idpEvent.Sequence()),
+				handler.NewCol(IDPTemplateResourceOwnerCol, idpEvent.Aggregate().ResourceOwner),
+				handler.NewCol(IDPTemplateInstanceIDCol, idpEvent.Aggregate().InstanceID),
+				handler.NewCol(IDPTemplateStateCol, domain.IDPStateActive),
+				handler.NewCol(IDPTemplateNameCol, idpEvent.Name),
+				handler.NewCol(IDPTemplateOwnerTypeCol, idpOwnerType),
+				handler.NewCol(IDPTemplateTypeCol, domain.IDPTypeSAML),
+				handler.NewCol(IDPTemplateIsCreationAllowedCol, idpEvent.IsCreationAllowed),
+				handler.NewCol(IDPTemplateIsLinkingAllowedCol, idpEvent.IsLinkingAllowed),
+				handler.NewCol(IDPTemplateIsAutoCreationCol, idpEvent.IsAutoCreation),
+				handler.NewCol(IDPTemplateIsAutoUpdateCol, idpEvent.IsAutoUpdate),
+			},
+		),
+		crdb.AddCreateStatement(
+			[]handler.Column{
+				handler.NewCol(SAMLIDCol, idpEvent.ID),
+				handler.NewCol(SAMLInstanceIDCol, idpEvent.Aggregate().InstanceID),
+				handler.NewCol(SAMLMetadataCol, idpEvent.Metadata),
+				handler.NewCol(SAMLKeyCol, idpEvent.Key),
+				handler.NewCol(SAMLCertificateCol, idpEvent.Certificate),
+				handler.NewCol(SAMLBindingCol, idpEvent.Binding),
+				handler.NewCol(SAMLWithSignedRequestCol, idpEvent.WithSignedRequest),
+			},
+			crdb.WithTableSuffix(IDPTemplateSAMLSuffix),
+		),
+	), nil
+}
+
+func (p *idpTemplateProjection) reduceSAMLIDPChanged(event eventstore.Event) (*handler.Statement, error) {
+	var idpEvent idp.SAMLIDPChangedEvent
+	switch e := event.(type) {
+	case *org.SAMLIDPChangedEvent:
+		idpEvent = e.SAMLIDPChangedEvent
+	case *instance.SAMLIDPChangedEvent:
+		idpEvent = e.SAMLIDPChangedEvent
+	default:
+		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-7b21ks", "reduce.wrong.event.type %v", []eventstore.EventType{org.SAMLIDPChangedEventType, instance.SAMLIDPChangedEventType})
+	}
+
+	ops := make([]func(eventstore.Event) crdb.Exec, 0, 2)
+	ops = append(ops,
+		crdb.AddUpdateStatement(
+			reduceIDPChangedTemplateColumns(idpEvent.Name, idpEvent.CreationDate(), idpEvent.Sequence(), idpEvent.OptionChanges),
+			[]handler.Condition{
+				handler.NewCond(IDPTemplateIDCol, idpEvent.ID),
+				handler.NewCond(IDPTemplateInstanceIDCol, idpEvent.Aggregate().InstanceID),
+			},
+		),
+	)
+
+	samlCols := reduceSAMLIDPChangedColumns(idpEvent)
+	if len(samlCols) > 0 {
+		ops = append(ops,
+			crdb.AddUpdateStatement(
+				samlCols,
+				[]handler.Condition{
+					handler.NewCond(SAMLIDCol, idpEvent.ID),
+					handler.NewCond(SAMLInstanceIDCol, idpEvent.Aggregate().InstanceID),
+				},
+				crdb.WithTableSuffix(IDPTemplateSAMLSuffix),
+			),
+		)
+	}
+
+	return crdb.NewMultiStatement(
+		&idpEvent,
+		ops...,
+	), nil
+}
+
 func (p *idpTemplateProjection) reduceIDPConfigRemoved(event eventstore.Event) (*handler.Statement, error) {
 	var idpEvent idpconfig.IDPConfigRemovedEvent
 	switch e := event.(type) {
@@ -2174,5 +2320,40 @@ func reduceLDAPIDPChangedColumns(idpEvent idp.LDAPIDPChangedEvent) []handler.Col
 	if idpEvent.ProfileAttribute != nil {
 		ldapCols = append(ldapCols, handler.NewCol(LDAPProfileAttributeCol, *idpEvent.ProfileAttribute))
 	}
+	if idpEvent.GroupsAttribute != nil {
+		ldapCols = append(ldapCols, handler.NewCol(LDAPGroupsAttributeCol, *idpEvent.GroupsAttribute))
+	}
+	if idpEvent.GroupBase != nil {
+		ldapCols = append(ldapCols, handler.NewCol(LDAPGroupBaseCol, *idpEvent.GroupBase))
+	}
+	if idpEvent.GroupMemberAttribute != nil {
+		ldapCols = append(ldapCols, handler.NewCol(LDAPGroupMemberAttributeCol, *idpEvent.GroupMemberAttribute))
+	}
+	if idpEvent.NestedGroups != nil {
+		ldapCols = append(ldapCols, handler.NewCol(LDAPNestedGroupsCol, *idpEvent.NestedGroups))
+	}
+	if idpEvent.GroupMappings != nil {
+		ldapCols = append(ldapCols, handler.NewCol(LDAPGroupMappingsCol, database.JSONArray[idp.LDAPGroupMapping](*idpEvent.GroupMappings)))
+	}
 	return ldapCols
 }
+
+func reduceSAMLIDPChangedColumns(idpEvent idp.SAMLIDPChangedEvent) []handler.Column {
+	samlCols := make([]handler.Column, 0, 5)
+	if idpEvent.Metadata != nil {
+		samlCols = append(samlCols, handler.NewCol(SAMLMetadataCol, idpEvent.Metadata))
+	}
+	if idpEvent.Key != nil {
+		samlCols = append(samlCols, handler.NewCol(SAMLKeyCol, idpEvent.Key))
+	}
+	if idpEvent.Certificate != nil {
+		samlCols = append(samlCols, handler.NewCol(SAMLCertificateCol, idpEvent.Certificate))
+	}
+	if idpEvent.Binding != nil {
+		samlCols = append(samlCols, handler.NewCol(SAMLBindingCol, *idpEvent.Binding))
+	}
+	if idpEvent.WithSignedRequest != nil {
+		samlCols = append(samlCols, handler.NewCol(SAMLWithSignedRequestCol, *idpEvent.WithSignedRequest))
+	}
+	return samlCols
+}
@@ -14,7 +14,7 @@ import (
 )
 
 const (
-	LockoutPolicyTable = "projections.lockout_policies2"
+	LockoutPolicyTable = "projections.lockout_policies3"
 
 	LockoutPolicyIDCol                  = "id"
 	LockoutPolicyCreationDateCol        = "creation_date"
@@ -26,6 +26,11 @@ const (
 	LockoutPolicyInstanceIDCol          = "instance_id"
 	LockoutPolicyMaxPasswordAttemptsCol = "max_password_attempts"
 	LockoutPolicyShowLockOutFailuresCol = "show_failure"
+	LockoutPolicyMaxOTPAttemptsCol      = "max_otp_attempts"
+	LockoutPolicyMaxPasskeyAttemptsCol  = "max_passkey_attempts"
+	LockoutPolicyMaxIPAttemptsCol       = "max_ip_attempts"
+	LockoutPolicyLockoutDurationCol     = "lockout_duration"
+	LockoutPolicyProgressiveDelayCol    = "progressive_delay"
 	LockoutPolicyOwnerRemovedCol        = "owner_removed"
 )
 
@@ -49,6 +54,11 @@ func newLockoutPolicyProjection(ctx context.Context, config crdb.StatementHandle
 			crdb.NewColumn(LockoutPolicyInstanceIDCol, crdb.ColumnTypeText),
 			crdb.NewColumn(LockoutPolicyMaxPasswordAttemptsCol, crdb.ColumnTypeInt64),
 			crdb.NewColumn(LockoutPolicyShowLockOutFailuresCol, crdb.ColumnTypeBool),
+			crdb.NewColumn(LockoutPolicyMaxOTPAttemptsCol, crdb.ColumnTypeInt64, crdb.Default(0)),
+			crdb.NewColumn(LockoutPolicyMaxPasskeyAttemptsCol, crdb.ColumnTypeInt64, crdb.Default(0)),
+			crdb.NewColumn(LockoutPolicyMaxIPAttemptsCol, crdb.ColumnTypeInt64, crdb.Default(0)),
+			crdb.NewColumn(LockoutPolicyLockoutDurationCol, crdb.ColumnTypeInt64, crdb.Default(0)),
+			crdb.NewColumn(LockoutPolicyProgressiveDelayCol, crdb.ColumnTypeInt64, crdb.Default(0)),
 			crdb.NewColumn(LockoutPolicyOwnerRemovedCol, crdb.ColumnTypeBool, crdb.Default(false)),
 		},
 			crdb.NewPrimaryKey(LockoutPolicyInstanceIDCol, LockoutPolicyIDCol),
@@ -125,6 +135,11 @@ func (p *lockoutPolicyProjection) reduceAdded(event eventstore.Event) (*handler.
 			handler.NewCol(LockoutPolicyStateCol, domain.PolicyStateActive),
 			handler.NewCol(LockoutPolicyMaxPasswordAttemptsCol, policyEvent.MaxPasswordAttempts),
 			handler.NewCol(LockoutPolicyShowLockOutFailuresCol, policyEvent.ShowLockOutFailures),
+			handler.NewCol(LockoutPolicyMaxOTPAttemptsCol, policyEvent.MaxOTPAttempts),
+			handler.NewCol(LockoutPolicyMaxPasskeyAttemptsCol, policyEvent.MaxPasskeyAttempts),
+			handler.NewCol(LockoutPolicyMaxIPAttemptsCol, policyEvent.MaxIPAttempts),
+			handler.NewCol(LockoutPolicyLockoutDurationCol, policyEvent.LockoutDuration),
+			handler.NewCol(LockoutPolicyProgressiveDelayCol, policyEvent.ProgressiveDelay),
 			handler.NewCol(LockoutPolicyIsDefaultCol, isDefault),
 			handler.NewCol(LockoutPolicyResourceOwnerCol, policyEvent.Aggregate().ResourceOwner),
 			handler.NewCol(LockoutPolicyInstanceIDCol, policyEvent.Aggregate().InstanceID),
@@ -151,6 +166,21 @@ func (p *lockoutPolicyProjection) reduceChanged(event eventstore.Event) (*handle
 	if policyEvent.ShowLockOutFailures != nil {
 		cols = append(cols, handler.NewCol(LockoutPolicyShowLockOutFailuresCol, *policyEvent.ShowLockOutFailures))
 	}
+	if policyEvent.MaxOTPAttempts != nil {
+		cols = append(cols, handler.NewCol(LockoutPolicyMaxOTPAttemptsCol, *policyEvent.MaxOTPAttempts))
+	}
+	if policyEvent.MaxPasskeyAttempts != nil {
+		cols = append(cols, handler.NewCol(LockoutPolicyMaxPasskeyAttemptsCol, *policyEvent.MaxPasskeyAttempts))
+	}
+	if policyEvent.MaxIPAttempts != nil {
+		cols = append(cols, handler.NewCol(LockoutPolicyMaxIPAttemptsCol, *policyEvent.MaxIPAttempts))
+	}
+	if policyEvent.LockoutDuration != nil {
+		cols = append(cols, handler.NewCol(LockoutPolicyLockoutDurationCol, *policyEvent.LockoutDuration))
+	}
+	if policyEvent.ProgressiveDelay != nil {
+		cols = append(cols, handler.NewCol(LockoutPolicyProgressiveDelayCol, *policyEvent.ProgressiveDelay))
+	}
 	return crdb.NewUpdateStatement(
 		&policyEvent,
 		cols,
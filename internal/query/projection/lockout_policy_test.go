@@ -2,6 +2,7 @@ package projection
 
 import (
 	"testing"
+	"time"
 
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/errors"
@@ -30,7 +31,12 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 					org.AggregateType,
 					[]byte(`{
 						"maxPasswordAttempts": 10,
-						"showLockOutFailures": true
+						"showLockOutFailures": true,
+						"maxOTPAttempts": 5,
+						"maxPasskeyAttempts": 3,
+						"maxIPAttempts": 20,
+						"lockoutDuration": 900000000000,
+						"progressiveDelay": 1000000000
 }`),
 				), org.LockoutPolicyAddedEventMapper),
 			},
@@ -42,7 +48,7 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.lockout_policies2 (creation_date, change_date, sequence, id, state, max_password_attempts, show_failure, is_default, resource_owner, instance_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+							expectedStmt: "INSERT INTO projections.lockout_policies3 (creation_date, change_date, sequence, id, state, max_password_attempts, show_failure, max_otp_attempts, max_passkey_attempts, max_ip_attempts, lockout_duration, progressive_delay, is_default, resource_owner, instance_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -51,6 +57,11 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 								domain.PolicyStateActive,
 								uint64(10),
 								true,
+								uint64(5),
+								uint64(3),
+								uint64(20),
+								15 * time.Minute,
+								time.Second,
 								false,
 								"ro-id",
 								"instance-id",
@@ -69,7 +80,9 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 					org.AggregateType,
 					[]byte(`{
 						"maxPasswordAttempts": 10,
-						"showLockOutFailures": true
+						"showLockOutFailures": true,
+						"maxIPAttempts": 20,
+						"lockoutDuration": 900000000000
 		}`),
 				), org.LockoutPolicyChangedEventMapper),
 			},
@@ -80,12 +93,14 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.lockout_policies2 SET (change_date, sequence, max_password_attempts, show_failure) = ($1, $2, $3, $4) WHERE (id = $5) AND (instance_id = $6)",
+							expectedStmt: "UPDATE projections.lockout_policies3 SET (change_date, sequence, max_password_attempts, show_failure, max_ip_attempts, lockout_duration) = ($1, $2, $3, $4, $5, $6) WHERE (id = $7) AND (instance_id = $8)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								uint64(15),
 								uint64(10),
 								true,
+								uint64(20),
+								15 * time.Minute,
 								"agg-id",
 								"instance-id",
 							},
@@ -111,7 +126,7 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.lockout_policies2 WHERE (id = $1) AND (instance_id = $2)",
+							expectedStmt: "DELETE FROM projections.lockout_policies3 WHERE (id = $1) AND (instance_id = $2)",
 							expectedArgs: []interface{}{
 								"agg-id",
 								"instance-id",
@@ -138,7 +153,7 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.lockout_policies2 WHERE (instance_id = $1)",
+							expectedStmt: "DELETE FROM projections.lockout_policies3 WHERE (instance_id = $1)",
 							expectedArgs: []interface{}{
 								"agg-id",
 							},
@@ -167,7 +182,7 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.lockout_policies2 (creation_date, change_date, sequence, id, state, max_password_attempts, show_failure, is_default, resource_owner, instance_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+							expectedStmt: "INSERT INTO projections.lockout_policies3 (creation_date, change_date, sequence, id, state, max_password_attempts, show_failure, max_otp_attempts, max_passkey_attempts, max_ip_attempts, lockout_duration, progressive_delay, is_default, resource_owner, instance_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -176,6 +191,11 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 								domain.PolicyStateActive,
 								uint64(10),
 								true,
+								uint64(0),
+								uint64(0),
+								uint64(0),
+								time.Duration(0),
+								time.Duration(0),
 								true,
 								"ro-id",
 								"instance-id",
@@ -205,7 +225,7 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.lockout_policies2 SET (change_date, sequence, max_password_attempts, show_failure) = ($1, $2, $3, $4) WHERE (id = $5) AND (instance_id = $6)",
+							expectedStmt: "UPDATE projections.lockout_policies3 SET (change_date, sequence, max_password_attempts, show_failure) = ($1, $2, $3, $4) WHERE (id = $5) AND (instance_id = $6)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								uint64(15),
@@ -236,7 +256,7 @@ func TestLockoutPolicyProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.lockout_policies2 SET (change_date, sequence, owner_removed) = ($1, $2, $3) WHERE (instance_id = $4) AND (resource_owner = $5)",
+							expectedStmt: "UPDATE projections.lockout_policies3 SET (change_date, sequence, owner_removed) = ($1, $2, $3) WHERE (instance_id = $4) AND (resource_owner = $5)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								uint64(15),
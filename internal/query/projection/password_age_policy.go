@@ -14,7 +14,7 @@ import (
 )
 
 const (
-	PasswordAgeTable = "projections.password_age_policies2"
+	PasswordAgeTable = "projections.password_age_policies3"
 
 	AgePolicyIDCol             = "id"
 	AgePolicyCreationDateCol   = "creation_date"
@@ -26,6 +26,8 @@ const (
 	AgePolicyInstanceIDCol     = "instance_id"
 	AgePolicyExpireWarnDaysCol = "expire_warn_days"
 	AgePolicyMaxAgeDaysCol     = "max_age_days"
+	AgePolicyMinAgeDaysCol     = "min_age_days"
+	AgePolicyHistoryCountCol   = "history_count"
 	AgePolicyOwnerRemovedCol   = "owner_removed"
 )
 
@@ -49,6 +51,8 @@ func newPasswordAgeProjection(ctx context.Context, config crdb.StatementHandlerC
 			crdb.NewColumn(AgePolicyInstanceIDCol, crdb.ColumnTypeText),
 			crdb.NewColumn(AgePolicyExpireWarnDaysCol, crdb.ColumnTypeInt64),
 			crdb.NewColumn(AgePolicyMaxAgeDaysCol, crdb.ColumnTypeInt64),
+			crdb.NewColumn(AgePolicyMinAgeDaysCol, crdb.ColumnTypeInt64, crdb.Default(0)),
+			crdb.NewColumn(AgePolicyHistoryCountCol, crdb.ColumnTypeInt64, crdb.Default(0)),
 			crdb.NewColumn(AgePolicyOwnerRemovedCol, crdb.ColumnTypeBool, crdb.Default(false)),
 		},
 			crdb.NewPrimaryKey(AgePolicyInstanceIDCol, AgePolicyIDCol),
@@ -125,6 +129,8 @@ func (p *passwordAgeProjection) reduceAdded(event eventstore.Event) (*handler.St
 			handler.NewCol(AgePolicyStateCol, domain.PolicyStateActive),
 			handler.NewCol(AgePolicyExpireWarnDaysCol, policyEvent.ExpireWarnDays),
 			handler.NewCol(AgePolicyMaxAgeDaysCol, policyEvent.MaxAgeDays),
+			handler.NewCol(AgePolicyMinAgeDaysCol, policyEvent.MinAgeDays),
+			handler.NewCol(AgePolicyHistoryCountCol, policyEvent.HistoryCount),
 			handler.NewCol(AgePolicyIsDefaultCol, isDefault),
 			handler.NewCol(AgePolicyResourceOwnerCol, policyEvent.Aggregate().ResourceOwner),
 			handler.NewCol(AgePolicyInstanceIDCol, policyEvent.Aggregate().InstanceID),
@@ -151,6 +157,12 @@ func (p *passwordAgeProjection) reduceChanged(event eventstore.Event) (*handler.
 	if policyEvent.MaxAgeDays != nil {
 		cols = append(cols, handler.NewCol(AgePolicyMaxAgeDaysCol, *policyEvent.MaxAgeDays))
 	}
+	if policyEvent.MinAgeDays != nil {
+		cols = append(cols, handler.NewCol(AgePolicyMinAgeDaysCol, *policyEvent.MinAgeDays))
+	}
+	if policyEvent.HistoryCount != nil {
+		cols = append(cols, handler.NewCol(AgePolicyHistoryCountCol, *policyEvent.HistoryCount))
+	}
 	return crdb.NewUpdateStatement(
 		&policyEvent,
 		cols,
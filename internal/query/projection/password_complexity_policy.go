@@ -3,6 +3,7 @@ package projection
 import (
 	"context"
 
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/errors"
 	"github.com/zitadel/zitadel/internal/eventstore"
@@ -14,22 +15,29 @@ import (
 )
 
 const (
-	PasswordComplexityTable = "projections.password_complexity_policies2"
+	PasswordComplexityTable = "projections.password_complexity_policies4"
 
-	ComplexityPolicyIDCol            = "id"
-	ComplexityPolicyCreationDateCol  = "creation_date"
-	ComplexityPolicyChangeDateCol    = "change_date"
-	ComplexityPolicySequenceCol      = "sequence"
-	ComplexityPolicyStateCol         = "state"
-	ComplexityPolicyIsDefaultCol     = "is_default"
-	ComplexityPolicyResourceOwnerCol = "resource_owner"
-	ComplexityPolicyInstanceIDCol    = "instance_id"
-	ComplexityPolicyMinLengthCol     = "min_length"
-	ComplexityPolicyHasLowercaseCol  = "has_lowercase"
-	ComplexityPolicyHasUppercaseCol  = "has_uppercase"
-	ComplexityPolicyHasSymbolCol     = "has_symbol"
-	ComplexityPolicyHasNumberCol     = "has_number"
-	ComplexityPolicyOwnerRemovedCol  = "owner_removed"
+	ComplexityPolicyIDCol                   = "id"
+	ComplexityPolicyCreationDateCol         = "creation_date"
+	ComplexityPolicyChangeDateCol           = "change_date"
+	ComplexityPolicySequenceCol             = "sequence"
+	ComplexityPolicyStateCol                = "state"
+	ComplexityPolicyIsDefaultCol            = "is_default"
+	ComplexityPolicyResourceOwnerCol        = "resource_owner"
+	ComplexityPolicyInstanceIDCol           = "instance_id"
+	ComplexityPolicyMinLengthCol            = "min_length"
+	ComplexityPolicyHasLowercaseCol         = "has_lowercase"
+	ComplexityPolicyHasUppercaseCol         = "has_uppercase"
+	ComplexityPolicyHasSymbolCol            = "has_symbol"
+	ComplexityPolicyHasNumberCol            = "has_number"
+	ComplexityPolicyRejectBreachedCol       = "reject_breached"
+	ComplexityPolicyMaxLengthCol            = "max_length"
+	ComplexityPolicyForbidUsernameCol       = "forbid_username"
+	ComplexityPolicyForbidEmailLocalPartCol = "forbid_email_local_part"
+	ComplexityPolicyForbidOrgNameCol        = "forbid_org_name"
+	ComplexityPolicyRulesCol                = "rules"
+	ComplexityPolicyBannedWordsCol          = "banned_words"
+	ComplexityPolicyOwnerRemovedCol         = "owner_removed"
 )
 
 type passwordComplexityProjection struct {
@@ -55,6 +63,13 @@ func newPasswordComplexityProjection(ctx context.Context, config crdb.StatementH
 			crdb.NewColumn(ComplexityPolicyHasUppercaseCol, crdb.ColumnTypeBool),
 			crdb.NewColumn(ComplexityPolicyHasSymbolCol, crdb.ColumnTypeBool),
 			crdb.NewColumn(ComplexityPolicyHasNumberCol, crdb.ColumnTypeBool),
+			crdb.NewColumn(ComplexityPolicyRejectBreachedCol, crdb.ColumnTypeBool, crdb.Default(false)),
+			crdb.NewColumn(ComplexityPolicyMaxLengthCol, crdb.ColumnTypeInt64, crdb.Default(0)),
+			crdb.NewColumn(ComplexityPolicyForbidUsernameCol, crdb.ColumnTypeBool, crdb.Default(false)),
+			crdb.NewColumn(ComplexityPolicyForbidEmailLocalPartCol, crdb.ColumnTypeBool, crdb.Default(false)),
+			crdb.NewColumn(ComplexityPolicyForbidOrgNameCol, crdb.ColumnTypeBool, crdb.Default(false)),
+			crdb.NewColumn(ComplexityPolicyRulesCol, crdb.ColumnTypeJSONB, crdb.Nullable()),
+			crdb.NewColumn(ComplexityPolicyBannedWordsCol, crdb.ColumnTypeTextArray, crdb.Nullable()),
 			crdb.NewColumn(ComplexityPolicyOwnerRemovedCol, crdb.ColumnTypeBool, crdb.Default(false)),
 		},
 			crdb.NewPrimaryKey(ComplexityPolicyInstanceIDCol, ComplexityPolicyIDCol),
@@ -134,6 +149,13 @@ func (p *passwordComplexityProjection) reduceAdded(event eventstore.Event) (*han
 			handler.NewCol(ComplexityPolicyHasUppercaseCol, policyEvent.HasUppercase),
 			handler.NewCol(ComplexityPolicyHasSymbolCol, policyEvent.HasSymbol),
 			handler.NewCol(ComplexityPolicyHasNumberCol, policyEvent.HasNumber),
+			handler.NewCol(ComplexityPolicyRejectBreachedCol, policyEvent.RejectBreached),
+			handler.NewCol(ComplexityPolicyMaxLengthCol, policyEvent.MaxLength),
+			handler.NewCol(ComplexityPolicyForbidUsernameCol, policyEvent.ForbidUsername),
+			handler.NewCol(ComplexityPolicyForbidEmailLocalPartCol, policyEvent.ForbidEmailLocalPart),
+			handler.NewCol(ComplexityPolicyForbidOrgNameCol, policyEvent.ForbidOrgName),
+			handler.NewCol(ComplexityPolicyRulesCol, database.JSONArray[domain.PasswordRule](policyEvent.Rules)),
+			handler.NewCol(ComplexityPolicyBannedWordsCol, database.StringArray(policyEvent.BannedWords)),
 			handler.NewCol(ComplexityPolicyResourceOwnerCol, policyEvent.Aggregate().ResourceOwner),
 			handler.NewCol(ComplexityPolicyInstanceIDCol, policyEvent.Aggregate().InstanceID),
 			handler.NewCol(ComplexityPolicyIsDefaultCol, isDefault),
@@ -169,6 +191,27 @@ func (p *passwordComplexityProjection) reduceChanged(event eventstore.Event) (*h
 	if policyEvent.HasNumber != nil {
 		cols = append(cols, handler.NewCol(ComplexityPolicyHasNumberCol, *policyEvent.HasNumber))
 	}
+	if policyEvent.RejectBreached != nil {
+		cols = append(cols, handler.NewCol(ComplexityPolicyRejectBreachedCol, *policyEvent.RejectBreached))
+	}
+	if policyEvent.MaxLength != nil {
+		cols = append(cols, handler.NewCol(ComplexityPolicyMaxLengthCol, *policyEvent.MaxLength))
+	}
+	if policyEvent.ForbidUsername != nil {
+		cols = append(cols, handler.NewCol(ComplexityPolicyForbidUsernameCol, *policyEvent.ForbidUsername))
+	}
+	if policyEvent.ForbidEmailLocalPart != nil {
+		cols = append(cols, handler.NewCol(ComplexityPolicyForbidEmailLocalPartCol, *policyEvent.ForbidEmailLocalPart))
+	}
+	if policyEvent.ForbidOrgName != nil {
+		cols = append(cols, handler.NewCol(ComplexityPolicyForbidOrgNameCol, *policyEvent.ForbidOrgName))
+	}
+	if policyEvent.Rules != nil {
+		cols = append(cols, handler.NewCol(ComplexityPolicyRulesCol, database.JSONArray[domain.PasswordRule](*policyEvent.Rules)))
+	}
+	if policyEvent.BannedWords != nil {
+		cols = append(cols, handler.NewCol(ComplexityPolicyBannedWordsCol, database.StringArray(*policyEvent.BannedWords)))
+	}
 	return crdb.NewUpdateStatement(
 		&policyEvent,
 		cols,
@@ -3,6 +3,7 @@ package projection
 import (
 	"testing"
 
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/errors"
 	"github.com/zitadel/zitadel/internal/eventstore"
@@ -33,7 +34,14 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 	"hasLowercase": true,
 	"hasUppercase": true,
 	"HasNumber": true,
-	"HasSymbol": true
+	"HasSymbol": true,
+	"rejectBreached": true,
+	"maxLength": 64,
+	"forbidUsername": true,
+	"forbidEmailLocalPart": true,
+	"forbidOrgName": true,
+	"rules": [{"type": 2, "pattern": "(?i)qwerty", "description": "no keyboard sequences"}],
+	"bannedWords": ["zitadel"]
 }`),
 				), org.PasswordComplexityPolicyAddedEventMapper),
 			},
@@ -45,7 +53,7 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.password_complexity_policies2 (creation_date, change_date, sequence, id, state, min_length, has_lowercase, has_uppercase, has_symbol, has_number, resource_owner, instance_id, is_default) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+							expectedStmt: "INSERT INTO projections.password_complexity_policies4 (creation_date, change_date, sequence, id, state, min_length, has_lowercase, has_uppercase, has_symbol, has_number, reject_breached, max_length, forbid_username, forbid_email_local_part, forbid_org_name, rules, banned_words, resource_owner, instance_id, is_default) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -57,6 +65,13 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 								true,
 								true,
 								true,
+								true,
+								uint64(64),
+								true,
+								true,
+								true,
+								database.JSONArray[domain.PasswordRule]{{Type: domain.PasswordRuleTypeDeny, Pattern: `(?i)qwerty`, Description: "no keyboard sequences"}},
+								database.StringArray{"zitadel"},
 								"ro-id",
 								"instance-id",
 								false,
@@ -78,7 +93,10 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 			"hasLowercase": true,
 			"hasUppercase": true,
 			"HasNumber": true,
-			"HasSymbol": true
+			"HasSymbol": true,
+			"rejectBreached": true,
+			"maxLength": 64,
+			"bannedWords": []
 		}`),
 				), org.PasswordComplexityPolicyChangedEventMapper),
 			},
@@ -89,7 +107,7 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.password_complexity_policies2 SET (change_date, sequence, min_length, has_lowercase, has_uppercase, has_symbol, has_number) = ($1, $2, $3, $4, $5, $6, $7) WHERE (id = $8) AND (instance_id = $9)",
+							expectedStmt: "UPDATE projections.password_complexity_policies4 SET (change_date, sequence, min_length, has_lowercase, has_uppercase, has_symbol, has_number, reject_breached, max_length, banned_words) = ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) WHERE (id = $11) AND (instance_id = $12)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								uint64(15),
@@ -98,6 +116,9 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 								true,
 								true,
 								true,
+								true,
+								uint64(64),
+								database.StringArray{},
 								"agg-id",
 								"instance-id",
 							},
@@ -123,7 +144,7 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.password_complexity_policies2 WHERE (id = $1) AND (instance_id = $2)",
+							expectedStmt: "DELETE FROM projections.password_complexity_policies4 WHERE (id = $1) AND (instance_id = $2)",
 							expectedArgs: []interface{}{
 								"agg-id",
 								"instance-id",
@@ -150,7 +171,7 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.password_complexity_policies2 WHERE (instance_id = $1)",
+							expectedStmt: "DELETE FROM projections.password_complexity_policies4 WHERE (instance_id = $1)",
 							expectedArgs: []interface{}{
 								"agg-id",
 							},
@@ -182,7 +203,7 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.password_complexity_policies2 (creation_date, change_date, sequence, id, state, min_length, has_lowercase, has_uppercase, has_symbol, has_number, resource_owner, instance_id, is_default) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+							expectedStmt: "INSERT INTO projections.password_complexity_policies4 (creation_date, change_date, sequence, id, state, min_length, has_lowercase, has_uppercase, has_symbol, has_number, reject_breached, max_length, forbid_username, forbid_email_local_part, forbid_org_name, rules, banned_words, resource_owner, instance_id, is_default) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								anyArg{},
@@ -194,6 +215,13 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
+								uint64(0),
+								false,
+								false,
+								false,
+								database.JSONArray[domain.PasswordRule](nil),
+								database.StringArray(nil),
 								"ro-id",
 								"instance-id",
 								true,
@@ -226,7 +254,7 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.password_complexity_policies2 SET (change_date, sequence, min_length, has_lowercase, has_uppercase, has_symbol, has_number) = ($1, $2, $3, $4, $5, $6, $7) WHERE (id = $8) AND (instance_id = $9)",
+							expectedStmt: "UPDATE projections.password_complexity_policies4 SET (change_date, sequence, min_length, has_lowercase, has_uppercase, has_symbol, has_number) = ($1, $2, $3, $4, $5, $6, $7) WHERE (id = $8) AND (instance_id = $9)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								uint64(15),
@@ -260,7 +288,7 @@ func TestPasswordComplexityProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.password_complexity_policies2 SET (change_date, sequence, owner_removed) = ($1, $2, $3) WHERE (instance_id = $4) AND (resource_owner = $5)",
+							expectedStmt: "UPDATE projections.password_complexity_policies4 SET (change_date, sequence, owner_removed) = ($1, $2, $3) WHERE (instance_id = $4) AND (resource_owner = $5)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								uint64(15),
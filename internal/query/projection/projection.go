@@ -38,6 +38,7 @@ var (
 	IDPUserLinkProjection               *idpUserLinkProjection
 	IDPLoginPolicyLinkProjection        *idpLoginPolicyLinkProjection
 	IDPTemplateProjection               *idpTemplateProjection
+	IDPClaimMappingProjection           *idpClaimMappingProjection
 	MailTemplateProjection              *mailTemplateProjection
 	MessageTextProjection               *messageTextProjection
 	CustomTextProjection                *customTextProjection
@@ -68,11 +69,14 @@ var (
 	DeviceAuthProjection                *deviceAuthProjection
 	SessionProjection                   *sessionProjection
 	MilestoneProjection                 *milestoneProjection
+	EventWebhookProjection              *eventWebhookProjection
 )
 
 type projection interface {
 	Start()
 	Init(ctx context.Context) error
+	Rebuild(ctx context.Context, progress func(crdb.RebuildProgress)) error
+	Name() string
 }
 
 var (
@@ -119,6 +123,7 @@ func Create(ctx context.Context, sqlClient *database.DB, es *eventstore.Eventsto
 	IDPUserLinkProjection = newIDPUserLinkProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["idp_user_links"]))
 	IDPLoginPolicyLinkProjection = newIDPLoginPolicyLinkProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["idp_login_policy_links"]))
 	IDPTemplateProjection = newIDPTemplateProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["idp_templates"]))
+	IDPClaimMappingProjection = newIDPClaimMappingProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["idp_claim_mappings"]))
 	MailTemplateProjection = newMailTemplateProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["mail_templates"]))
 	MessageTextProjection = newMessageTextProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["message_texts"]))
 	CustomTextProjection = newCustomTextProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["custom_texts"]))
@@ -146,7 +151,9 @@ func Create(ctx context.Context, sqlClient *database.DB, es *eventstore.Eventsto
 	DeviceAuthProjection = newDeviceAuthProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["device_auth"]))
 	SessionProjection = newSessionProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["sessions"]))
 	MilestoneProjection = newMilestoneProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["milestones"]))
+	EventWebhookProjection = newEventWebhookProjection(ctx, applyCustomConfig(projectionConfig, config.Customizations["event_webhooks"]), config.EventWebhookEvents)
 	newProjectionsList()
+	rebuildCtx = ctx
 	return nil
 }
 
@@ -214,6 +221,7 @@ func newProjectionsList() {
 		LoginPolicyProjection,
 		IDPProjection,
 		IDPTemplateProjection,
+		IDPClaimMappingProjection,
 		AppProjection,
 		IDPUserLinkProjection,
 		IDPLoginPolicyLinkProjection,
@@ -244,5 +252,6 @@ func newProjectionsList() {
 		DeviceAuthProjection,
 		SessionProjection,
 		MilestoneProjection,
+		EventWebhookProjection,
 	}
 }
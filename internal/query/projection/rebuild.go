@@ -0,0 +1,119 @@
+package projection
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore/handler/crdb"
+)
+
+// RebuildStatus is the status of a rebuild started in this process
+type RebuildStatus struct {
+	ProjectionName string
+	State          domain.ProjectionRebuildState
+	Progress       crdb.RebuildProgress
+	StartedAt      time.Time
+	ChangedAt      time.Time
+	Error          string
+}
+
+var (
+	// rebuildCtx is the context of the projections, rebuilds in the background stop as soon as it's done
+	rebuildCtx = context.Background()
+
+	rebuildsMu sync.RWMutex
+	rebuilds   = make(map[string]*RebuildStatus)
+)
+
+// Rebuild replays all events of the projection into shadow tables
+// and replaces the tables of the projection as soon as the shadow tables caught up.
+// The name of the projection can be passed with or without the projections schema.
+func Rebuild(ctx context.Context, name string, progress func(crdb.RebuildProgress)) error {
+	p, err := projectionByName(name)
+	if err != nil {
+		return err
+	}
+	return p.Rebuild(ctx, progress)
+}
+
+// StartRebuild validates the name of the projection and rebuilds it in the background
+// the rebuild stops if the projections are stopped
+func StartRebuild(name string) error {
+	p, err := projectionByName(name)
+	if err != nil {
+		return err
+	}
+	rebuildsMu.Lock()
+	if status := rebuilds[p.Name()]; status != nil && status.State == domain.ProjectionRebuildStateRunning {
+		rebuildsMu.Unlock()
+		return errors.ThrowPreconditionFailed(nil, "PROJE-ieF3u", "Errors.ProjectionName.RebuildRunning")
+	}
+	now := time.Now()
+	rebuilds[p.Name()] = &RebuildStatus{
+		ProjectionName: p.Name(),
+		State:          domain.ProjectionRebuildStateRunning,
+		StartedAt:      now,
+		ChangedAt:      now,
+	}
+	rebuildsMu.Unlock()
+
+	go func() {
+		logging.WithFields("projection", p.Name()).Info("rebuild started")
+		err := p.Rebuild(rebuildCtx, func(progress crdb.RebuildProgress) {
+			updateRebuild(p.Name(), func(status *RebuildStatus) {
+				status.Progress = progress
+			})
+		})
+		logging.WithFields("projection", p.Name()).OnError(err).Error("rebuild failed")
+		updateRebuild(p.Name(), func(status *RebuildStatus) {
+			status.State = domain.ProjectionRebuildStateDone
+			if err != nil {
+				status.State = domain.ProjectionRebuildStateFailed
+				status.Error = err.Error()
+			}
+		})
+	}()
+	return nil
+}
+
+// RebuildStatusByName returns the status of the latest rebuild of the projection started in this process
+func RebuildStatusByName(name string) (*RebuildStatus, error) {
+	p, err := projectionByName(name)
+	if err != nil {
+		return nil, err
+	}
+	rebuildsMu.RLock()
+	defer rebuildsMu.RUnlock()
+	status, ok := rebuilds[p.Name()]
+	if !ok {
+		return nil, errors.ThrowNotFound(nil, "PROJE-Eim4o", "Errors.ProjectionName.RebuildNotFound")
+	}
+	copied := *status
+	return &copied, nil
+}
+
+func updateRebuild(name string, update func(*RebuildStatus)) {
+	rebuildsMu.Lock()
+	defer rebuildsMu.Unlock()
+	status := rebuilds[name]
+	update(status)
+	status.ChangedAt = time.Now()
+}
+
+func projectionByName(name string) (projection, error) {
+	if !strings.Contains(name, ".") {
+		name = "projections." + name
+	}
+	for _, p := range projections {
+		if p.Name() == name {
+			return p, nil
+		}
+	}
+	return nil, errors.ThrowNotFound(nil, "PROJE-Ohm2e", "Errors.ProjectionName.Invalid")
+}
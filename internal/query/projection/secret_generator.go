@@ -11,7 +11,7 @@ import (
 )
 
 const (
-	SecretGeneratorProjectionTable = "projections.secret_generators2"
+	SecretGeneratorProjectionTable = "projections.secret_generators3"
 
 	SecretGeneratorColumnGeneratorType       = "generator_type"
 	SecretGeneratorColumnAggregateID         = "aggregate_id"
@@ -26,6 +26,7 @@ const (
 	SecretGeneratorColumnIncludeUpperLetters = "include_upper_letters"
 	SecretGeneratorColumnIncludeDigits       = "include_digits"
 	SecretGeneratorColumnIncludeSymbols      = "include_symbols"
+	SecretGeneratorColumnHashed              = "hashed"
 )
 
 type secretGeneratorProjection struct {
@@ -51,6 +52,7 @@ func newSecretGeneratorProjection(ctx context.Context, config crdb.StatementHand
 			crdb.NewColumn(SecretGeneratorColumnIncludeUpperLetters, crdb.ColumnTypeBool),
 			crdb.NewColumn(SecretGeneratorColumnIncludeDigits, crdb.ColumnTypeBool),
 			crdb.NewColumn(SecretGeneratorColumnIncludeSymbols, crdb.ColumnTypeBool),
+			crdb.NewColumn(SecretGeneratorColumnHashed, crdb.ColumnTypeBool, crdb.Default(false)),
 		},
 			crdb.NewPrimaryKey(SecretGeneratorColumnInstanceID, SecretGeneratorColumnGeneratorType, SecretGeneratorColumnAggregateID),
 		),
@@ -106,6 +108,7 @@ func (p *secretGeneratorProjection) reduceSecretGeneratorAdded(event eventstore.
 			handler.NewCol(SecretGeneratorColumnIncludeUpperLetters, e.IncludeUpperLetters),
 			handler.NewCol(SecretGeneratorColumnIncludeDigits, e.IncludeDigits),
 			handler.NewCol(SecretGeneratorColumnIncludeSymbols, e.IncludeSymbols),
+			handler.NewCol(SecretGeneratorColumnHashed, e.Hashed),
 		},
 	), nil
 }
@@ -116,7 +119,7 @@ func (p *secretGeneratorProjection) reduceSecretGeneratorChanged(event eventstor
 		return nil, errors.ThrowInvalidArgumentf(nil, "HANDL-s00Fs", "reduce.wrong.event.type %s", instance.SecretGeneratorChangedEventType)
 	}
 
-	columns := make([]handler.Column, 0, 7)
+	columns := make([]handler.Column, 0, 9)
 	columns = append(columns, handler.NewCol(SecretGeneratorColumnChangeDate, e.CreationDate()),
 		handler.NewCol(SecretGeneratorColumnSequence, e.Sequence()))
 	if e.Length != nil {
@@ -137,6 +140,9 @@ func (p *secretGeneratorProjection) reduceSecretGeneratorChanged(event eventstor
 	if e.IncludeSymbols != nil {
 		columns = append(columns, handler.NewCol(SecretGeneratorColumnIncludeSymbols, *e.IncludeSymbols))
 	}
+	if e.Hashed != nil {
+		columns = append(columns, handler.NewCol(SecretGeneratorColumnHashed, *e.Hashed))
+	}
 	return crdb.NewUpdateStatement(
 		e,
 		columns,
@@ -39,7 +39,7 @@ func TestSecretGeneratorProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.secret_generators2 WHERE (aggregate_id = $1) AND (generator_type = $2) AND (instance_id = $3)",
+							expectedStmt: "DELETE FROM projections.secret_generators3 WHERE (aggregate_id = $1) AND (generator_type = $2) AND (instance_id = $3)",
 							expectedArgs: []interface{}{
 								"agg-id",
 								domain.SecretGeneratorTypeInitCode,
@@ -56,7 +56,7 @@ func TestSecretGeneratorProjection_reduces(t *testing.T) {
 				event: getEvent(testEvent(
 					repository.EventType(instance.SecretGeneratorChangedEventType),
 					instance.AggregateType,
-					[]byte(`{"generatorType": 1, "length": 4, "expiry": 10000000, "includeLowerLetters": true, "includeUpperLetters": true, "includeDigits": true, "includeSymbols": true}`),
+					[]byte(`{"generatorType": 1, "length": 4, "expiry": 10000000, "includeLowerLetters": true, "includeUpperLetters": true, "includeDigits": true, "includeSymbols": true, "hashed": true}`),
 				), instance.SecretGeneratorChangedEventMapper),
 			},
 			reduce: (&secretGeneratorProjection{}).reduceSecretGeneratorChanged,
@@ -67,7 +67,7 @@ func TestSecretGeneratorProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.secret_generators2 SET (change_date, sequence, length, expiry, include_lower_letters, include_upper_letters, include_digits, include_symbols) = ($1, $2, $3, $4, $5, $6, $7, $8) WHERE (aggregate_id = $9) AND (generator_type = $10) AND (instance_id = $11)",
+							expectedStmt: "UPDATE projections.secret_generators3 SET (change_date, sequence, length, expiry, include_lower_letters, include_upper_letters, include_digits, include_symbols, hashed) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE (aggregate_id = $10) AND (generator_type = $11) AND (instance_id = $12)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								uint64(15),
@@ -77,6 +77,7 @@ func TestSecretGeneratorProjection_reduces(t *testing.T) {
 								true,
 								true,
 								true,
+								true,
 								"agg-id",
 								domain.SecretGeneratorTypeInitCode,
 								"instance-id",
@@ -103,7 +104,7 @@ func TestSecretGeneratorProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.secret_generators2 (aggregate_id, generator_type, creation_date, change_date, resource_owner, instance_id, sequence, length, expiry, include_lower_letters, include_upper_letters, include_digits, include_symbols) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+							expectedStmt: "INSERT INTO projections.secret_generators3 (aggregate_id, generator_type, creation_date, change_date, resource_owner, instance_id, sequence, length, expiry, include_lower_letters, include_upper_letters, include_digits, include_symbols, hashed) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)",
 							expectedArgs: []interface{}{
 								"agg-id",
 								domain.SecretGeneratorTypeInitCode,
@@ -118,6 +119,7 @@ func TestSecretGeneratorProjection_reduces(t *testing.T) {
 								true,
 								true,
 								true,
+								false,
 							},
 						},
 					},
@@ -141,7 +143,7 @@ func TestSecretGeneratorProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.secret_generators2 WHERE (instance_id = $1)",
+							expectedStmt: "DELETE FROM projections.secret_generators3 WHERE (instance_id = $1)",
 							expectedArgs: []interface{}{
 								"agg-id",
 							},
@@ -0,0 +1,225 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/zitadel/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/telemetry/metrics"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+)
+
+const (
+	// latestSequenceSelect selects the sequence of the latest event of the aggregate type of the current sequence
+	latestSequenceSelect = "(SELECT max(e.event_sequence) FROM eventstore.events e" +
+		" WHERE e.aggregate_type = projections.current_sequences.aggregate_type" +
+		" AND e.instance_id = projections.current_sequences.instance_id) AS latest_sequence"
+	// pendingSinceSelect selects the creation date of the oldest event which is not processed yet
+	pendingSinceSelect = "(SELECT min(e.creation_date) FROM eventstore.events e" +
+		" WHERE e.aggregate_type = projections.current_sequences.aggregate_type" +
+		" AND e.instance_id = projections.current_sequences.instance_id" +
+		" AND e.event_sequence > projections.current_sequences.current_sequence) AS pending_since"
+	failedEventsCountSelect = "(SELECT count(*) FROM projections.failed_events f" +
+		" WHERE f.projection_name = projections.current_sequences.projection_name" +
+		" AND f.instance_id = projections.current_sequences.instance_id) AS failed_events"
+
+	// defaultProjectionLagsInterval is used if no interval is configured
+	defaultProjectionLagsInterval = time.Minute
+)
+
+type ProjectionLags struct {
+	ProjectionLags []*ProjectionLag
+}
+
+type ProjectionLag struct {
+	ProjectionName  string
+	InstanceID      string
+	CurrentSequence uint64
+	LatestSequence  uint64
+	// LastRun is the last time the projection updated its current sequence
+	LastRun time.Time
+	// PendingSince is the creation date of the oldest event the projection did not process yet,
+	// it's zero if the projection is up to date
+	PendingSince time.Time
+	FailedEvents uint64
+}
+
+// SequenceLag is the difference between the sequence of the latest event and the processed sequence
+func (l *ProjectionLag) SequenceLag() uint64 {
+	if l.LatestSequence <= l.CurrentSequence {
+		return 0
+	}
+	return l.LatestSequence - l.CurrentSequence
+}
+
+// TimeLag is the age of the oldest event the projection did not process yet
+func (l *ProjectionLag) TimeLag() time.Duration {
+	if l.PendingSince.IsZero() {
+		return 0
+	}
+	return time.Since(l.PendingSince)
+}
+
+// SearchProjectionLags returns the lag of all projections of all instances
+func (q *Queries) SearchProjectionLags(ctx context.Context) (lags *ProjectionLags, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	query, scan := prepareProjectionLagsQuery(ctx, q.client)
+	stmt, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.ThrowInvalidArgument(err, "QUERY-Eib3o", "Errors.Query.InvalidRequest")
+	}
+
+	rows, err := q.client.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "QUERY-aeN5u", "Errors.Internal")
+	}
+	return scan(rows)
+}
+
+func prepareProjectionLagsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*ProjectionLags, error)) {
+	return sq.Select(
+			CurrentSequenceColProjectionName.identifier(),
+			CurrentSequenceColInstanceID.identifier(),
+			CurrentSequenceColCurrentSequence.identifier(),
+			CurrentSequenceColTimestamp.identifier(),
+			latestSequenceSelect,
+			pendingSinceSelect,
+			failedEventsCountSelect).
+			From(currentSequencesTable.identifier()).
+			OrderBy(CurrentSequenceColProjectionName.identifier(), CurrentSequenceColInstanceID.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) (*ProjectionLags, error) {
+			lags := make([]*ProjectionLag, 0)
+			// the current sequences are stored per aggregate type,
+			// the rows of the same projection and instance are merged
+			var lag *ProjectionLag
+			for rows.Next() {
+				row := new(ProjectionLag)
+				var (
+					latestSequence sql.NullInt64
+					pendingSince   sql.NullTime
+				)
+				err := rows.Scan(
+					&row.ProjectionName,
+					&row.InstanceID,
+					&row.CurrentSequence,
+					&row.LastRun,
+					&latestSequence,
+					&pendingSince,
+					&row.FailedEvents,
+				)
+				if err != nil {
+					return nil, err
+				}
+				row.LatestSequence = uint64(latestSequence.Int64)
+				row.PendingSince = pendingSince.Time
+
+				if lag == nil || lag.ProjectionName != row.ProjectionName || lag.InstanceID != row.InstanceID {
+					lag = row
+					lags = append(lags, lag)
+					continue
+				}
+				lag.merge(row)
+			}
+
+			if err := rows.Close(); err != nil {
+				return nil, errors.ThrowInternal(err, "QUERY-ahW5o", "Errors.Query.CloseRows")
+			}
+
+			return &ProjectionLags{
+				ProjectionLags: lags,
+			}, nil
+		}
+}
+
+// merge combines the lag of another aggregate type of the same projection and instance
+func (l *ProjectionLag) merge(other *ProjectionLag) {
+	if other.SequenceLag() > l.SequenceLag() {
+		l.CurrentSequence = other.CurrentSequence
+		l.LatestSequence = other.LatestSequence
+	}
+	if other.LastRun.After(l.LastRun) {
+		l.LastRun = other.LastRun
+	}
+	if !other.PendingSince.IsZero() && (l.PendingSince.IsZero() || other.PendingSince.Before(l.PendingSince)) {
+		l.PendingSince = other.PendingSince
+	}
+}
+
+// projectionLagObserver reports the lags of the projections as gauges
+// the lags are queried in the background once per interval,
+// so collecting the metrics doesn't query the database
+type projectionLagObserver struct {
+	queries *Queries
+
+	mu   sync.RWMutex
+	lags []*ProjectionLag
+}
+
+func registerProjectionLagMetrics(ctx context.Context, queries *Queries, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultProjectionLagsInterval
+	}
+	observer := &projectionLagObserver{queries: queries}
+	err := metrics.RegisterValueObserver(metrics.ProjectionSequenceLag, metrics.ProjectionSequenceLagDescription, observer.observe(func(lag *ProjectionLag) int64 {
+		return int64(lag.SequenceLag())
+	}))
+	logging.OnError(err).Panic("unable to register projection sequence lag metric")
+	err = metrics.RegisterValueObserver(metrics.ProjectionTimeLag, metrics.ProjectionTimeLagDescription, observer.observe(func(lag *ProjectionLag) int64 {
+		return lag.TimeLag().Milliseconds()
+	}))
+	logging.OnError(err).Panic("unable to register projection time lag metric")
+	err = metrics.RegisterValueObserver(metrics.ProjectionFailedEvents, metrics.ProjectionFailedEventsDescription, observer.observe(func(lag *ProjectionLag) int64 {
+		return int64(lag.FailedEvents)
+	}))
+	logging.OnError(err).Panic("unable to register projection failed events metric")
+	go observer.refresh(ctx, interval)
+}
+
+func (o *projectionLagObserver) observe(value func(*ProjectionLag) int64) instrument.Int64Callback {
+	return func(ctx context.Context, observer instrument.Int64Observer) error {
+		o.mu.RLock()
+		defer o.mu.RUnlock()
+		for _, lag := range o.lags {
+			observer.Observe(value(lag),
+				attribute.String(metrics.ProjectionName, lag.ProjectionName),
+				attribute.String(metrics.InstanceID, lag.InstanceID),
+			)
+		}
+		return nil
+	}
+}
+
+// refresh queries the lags once per interval until the context is done
+func (o *projectionLagObserver) refresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		o.update(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (o *projectionLagObserver) update(ctx context.Context) {
+	lags, err := o.queries.SearchProjectionLags(ctx)
+	if err != nil {
+		logging.WithError(err).Warn("unable to query projection lags")
+		return
+	}
+	o.mu.Lock()
+	o.lags = lags.ProjectionLags
+	o.mu.Unlock()
+}
@@ -0,0 +1,263 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/zitadel/zitadel/internal/database"
+)
+
+var (
+	prepareProjectionLagsStmt = `SELECT projections.current_sequences.projection_name,` +
+		` projections.current_sequences.instance_id,` +
+		` projections.current_sequences.current_sequence,` +
+		` projections.current_sequences.timestamp,` +
+		` (SELECT max(e.event_sequence) FROM eventstore.events e` +
+		` WHERE e.aggregate_type = projections.current_sequences.aggregate_type` +
+		` AND e.instance_id = projections.current_sequences.instance_id) AS latest_sequence,` +
+		` (SELECT min(e.creation_date) FROM eventstore.events e` +
+		` WHERE e.aggregate_type = projections.current_sequences.aggregate_type` +
+		` AND e.instance_id = projections.current_sequences.instance_id` +
+		` AND e.event_sequence > projections.current_sequences.current_sequence) AS pending_since,` +
+		` (SELECT count(*) FROM projections.failed_events f` +
+		` WHERE f.projection_name = projections.current_sequences.projection_name` +
+		` AND f.instance_id = projections.current_sequences.instance_id) AS failed_events` +
+		` FROM projections.current_sequences` +
+		` ORDER BY projections.current_sequences.projection_name, projections.current_sequences.instance_id`
+
+	prepareProjectionLagsCols = []string{
+		"projection_name",
+		"instance_id",
+		"current_sequence",
+		"timestamp",
+		"latest_sequence",
+		"pending_since",
+		"failed_events",
+	}
+)
+
+func Test_ProjectionLagsPrepares(t *testing.T) {
+	type want struct {
+		sqlExpectations sqlExpectation
+		err             checkErr
+	}
+	tests := []struct {
+		name    string
+		prepare interface{}
+		want    want
+		object  interface{}
+	}{
+		{
+			name:    "prepareProjectionLagsQuery no result",
+			prepare: prepareProjectionLagsQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareProjectionLagsStmt),
+					nil,
+					nil,
+				),
+			},
+			object: &ProjectionLags{ProjectionLags: []*ProjectionLag{}},
+		},
+		{
+			name:    "prepareProjectionLagsQuery up to date",
+			prepare: prepareProjectionLagsQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareProjectionLagsStmt),
+					prepareProjectionLagsCols,
+					[][]driver.Value{
+						{
+							"projections.users",
+							"instance-id",
+							uint64(20211108),
+							testNow,
+							int64(20211108),
+							nil,
+							uint64(0),
+						},
+					},
+				),
+			},
+			object: &ProjectionLags{
+				ProjectionLags: []*ProjectionLag{
+					{
+						ProjectionName:  "projections.users",
+						InstanceID:      "instance-id",
+						CurrentSequence: 20211108,
+						LatestSequence:  20211108,
+						LastRun:         testNow,
+					},
+				},
+			},
+		},
+		{
+			name:    "prepareProjectionLagsQuery merges aggregate types",
+			prepare: prepareProjectionLagsQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareProjectionLagsStmt),
+					prepareProjectionLagsCols,
+					[][]driver.Value{
+						{
+							"projections.users",
+							"instance-id",
+							uint64(10),
+							testNow,
+							int64(12),
+							testNow.Add(-time.Minute),
+							uint64(1),
+						},
+						{
+							"projections.users",
+							"instance-id",
+							uint64(8),
+							testNow.Add(-time.Hour),
+							int64(20),
+							testNow.Add(-2 * time.Minute),
+							uint64(1),
+						},
+						{
+							"projections.users",
+							"instance-id2",
+							uint64(20),
+							testNow,
+							nil,
+							nil,
+							uint64(0),
+						},
+					},
+				),
+			},
+			object: &ProjectionLags{
+				ProjectionLags: []*ProjectionLag{
+					{
+						ProjectionName:  "projections.users",
+						InstanceID:      "instance-id",
+						CurrentSequence: 8,
+						LatestSequence:  20,
+						LastRun:         testNow,
+						PendingSince:    testNow.Add(-2 * time.Minute),
+						FailedEvents:    1,
+					},
+					{
+						ProjectionName:  "projections.users",
+						InstanceID:      "instance-id2",
+						CurrentSequence: 20,
+						LastRun:         testNow,
+					},
+				},
+			},
+		},
+		{
+			name:    "prepareProjectionLagsQuery sql err",
+			prepare: prepareProjectionLagsQuery,
+			want: want{
+				sqlExpectations: mockQueryErr(
+					regexp.QuoteMeta(prepareProjectionLagsStmt),
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, tt.prepare, tt.object, tt.want.sqlExpectations, tt.want.err, defaultPrepareArgs...)
+		})
+	}
+}
+
+func TestProjectionLag_SequenceLag(t *testing.T) {
+	tests := []struct {
+		name string
+		lag  *ProjectionLag
+		want uint64
+	}{
+		{
+			name: "up to date",
+			lag:  &ProjectionLag{CurrentSequence: 10, LatestSequence: 10},
+			want: 0,
+		},
+		{
+			name: "no events",
+			lag:  &ProjectionLag{CurrentSequence: 10},
+			want: 0,
+		},
+		{
+			name: "behind",
+			lag:  &ProjectionLag{CurrentSequence: 10, LatestSequence: 15},
+			want: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.lag.SequenceLag(); got != tt.want {
+				t.Errorf("SequenceLag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type testInt64Observer struct {
+	values []int64
+}
+
+func (o *testInt64Observer) Observe(value int64, _ ...attribute.KeyValue) {
+	o.values = append(o.values, value)
+}
+
+func TestProjectionLagObserver(t *testing.T) {
+	client, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("unable to create mock: %v", err)
+	}
+	defer client.Close()
+	// the lags are queried once, collecting the metrics uses the queried lags
+	mock.ExpectQuery(regexp.QuoteMeta(prepareProjectionLagsStmt)).
+		WillReturnRows(
+			sqlmock.NewRows(prepareProjectionLagsCols).
+				AddRow("projections.users8", "instance-id", uint64(10), testNow, int64(15), nil, uint64(2)),
+		)
+
+	o := &projectionLagObserver{queries: &Queries{client: &database.DB{DB: client}}}
+	observe := o.observe(func(lag *ProjectionLag) int64 {
+		return int64(lag.SequenceLag())
+	})
+	observer := new(testInt64Observer)
+	if err = observe(context.Background(), observer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(observer.values) != 0 {
+		t.Errorf("no lags expected before the first query, got %v", observer.values)
+	}
+
+	o.update(context.Background())
+	for i := 0; i < 2; i++ {
+		observer = new(testInt64Observer)
+		if err = observe(context.Background(), observer); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(observer.values) != 1 || observer.values[0] != 5 {
+			t.Errorf("expected sequence lag 5, got %v", observer.values)
+		}
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
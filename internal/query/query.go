@@ -79,15 +79,7 @@ func StartQueries(
 		zitadelRoles:                        zitadelRoles,
 		sessionTokenVerifier:                sessionTokenVerifier,
 	}
-	iam_repo.RegisterEventMappers(repo.eventstore)
-	usr_repo.RegisterEventMappers(repo.eventstore)
-	org.RegisterEventMappers(repo.eventstore)
-	project.RegisterEventMappers(repo.eventstore)
-	action.RegisterEventMappers(repo.eventstore)
-	keypair.RegisterEventMappers(repo.eventstore)
-	usergrant.RegisterEventMappers(repo.eventstore)
-	session.RegisterEventMappers(repo.eventstore)
-	idpintent.RegisterEventMappers(repo.eventstore)
+	RegisterEventMappers(repo.eventstore)
 
 	repo.idpConfigEncryption = idpConfigEncryption
 	repo.multifactors = domain.MultifactorConfigs{
@@ -98,6 +90,7 @@ func StartQueries(
 	}
 
 	repo.checkPermission = permissionCheck(repo)
+	registerProjectionLagMetrics(ctx, repo, projections.LagMetricsInterval)
 
 	err = projection.Create(ctx, sqlClient, es, projections, keyEncryptionAlgorithm, certEncryptionAlgorithm)
 	if err != nil {
@@ -108,6 +101,19 @@ func StartQueries(
 	return repo, nil
 }
 
+// RegisterEventMappers registers the mappers of the events reduced by the queries and projections
+func RegisterEventMappers(es *eventstore.Eventstore) {
+	iam_repo.RegisterEventMappers(es)
+	usr_repo.RegisterEventMappers(es)
+	org.RegisterEventMappers(es)
+	project.RegisterEventMappers(es)
+	action.RegisterEventMappers(es)
+	keypair.RegisterEventMappers(es)
+	usergrant.RegisterEventMappers(es)
+	session.RegisterEventMappers(es)
+	idpintent.RegisterEventMappers(es)
+}
+
 func (q *Queries) Health(ctx context.Context) error {
 	return q.client.Ping()
 }
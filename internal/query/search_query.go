@@ -110,6 +110,61 @@ func (q *orQuery) comp() sq.Sqlizer {
 	return or
 }
 
+type andQuery struct {
+	queries []SearchQuery
+}
+
+func newAndQuery(queries ...SearchQuery) (*andQuery, error) {
+	if len(queries) == 0 {
+		return nil, ErrMissingColumn
+	}
+	return &andQuery{queries: queries}, nil
+}
+
+func (q *andQuery) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(q.comp())
+}
+
+func (q *andQuery) comp() sq.Sqlizer {
+	and := make(sq.And, len(q.queries))
+	for i, query := range q.queries {
+		and[i] = query.comp()
+	}
+	return and
+}
+
+type notQuery struct {
+	query SearchQuery
+}
+
+func newNotQuery(query SearchQuery) (*notQuery, error) {
+	if query == nil {
+		return nil, ErrMissingColumn
+	}
+	return &notQuery{query: query}, nil
+}
+
+func (q *notQuery) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(q.comp())
+}
+
+func (q *notQuery) comp() sq.Sqlizer {
+	return &not{q.query.comp()}
+}
+
+// not negates the condition, squirrel doesn't provide a NOT operator
+type not struct {
+	sq.Sqlizer
+}
+
+func (n *not) ToSql() (string, []interface{}, error) {
+	sql, args, err := n.Sqlizer.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return "NOT (" + sql + ")", args, nil
+}
+
 type ColumnComparisonQuery struct {
 	Column1 Column
 	Compare ColumnComparison
@@ -1018,6 +1018,70 @@ func TestTextQuery_comp(t *testing.T) {
 	}
 }
 
+func TestNotQuery_comp(t *testing.T) {
+	type want struct {
+		sql  string
+		args []interface{}
+	}
+	tests := []struct {
+		name  string
+		query SearchQuery
+		want  want
+	}{
+		{
+			name: "text query",
+			query: &TextQuery{
+				Column:  testCol,
+				Text:    "Hurst",
+				Compare: TextEquals,
+			},
+			want: want{
+				sql:  "NOT (test_table.test_col = ?)",
+				args: []interface{}{"Hurst"},
+			},
+		},
+		{
+			name: "and query",
+			query: &andQuery{
+				queries: []SearchQuery{
+					&TextQuery{
+						Column:  testCol,
+						Text:    "Hurst",
+						Compare: TextEquals,
+					},
+					&NumberQuery{
+						Column:  testCol2,
+						Number:  1,
+						Compare: NumberEquals,
+					},
+				},
+			},
+			want: want{
+				sql:  "NOT ((test_table.test_col = ? AND test_table2.test_col2 = ?))",
+				args: []interface{}{"Hurst", 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := newNotQuery(tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sql, args, err := q.comp().ToSql()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.want.sql {
+				t.Errorf("wrong sql: want: %q, got: %q", tt.want.sql, sql)
+			}
+			if !reflect.DeepEqual(args, tt.want.args) {
+				t.Errorf("wrong args: want: %v, got: %v", tt.want.args, args)
+			}
+		})
+	}
+}
+
 func TestTextComparisonFromMethod(t *testing.T) {
 	type args struct {
 		m domain.SearchMethod
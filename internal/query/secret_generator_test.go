@@ -14,19 +14,20 @@ import (
 )
 
 var (
-	prepareSecretGeneratorStmt = `SELECT projections.secret_generators2.aggregate_id,` +
-		` projections.secret_generators2.generator_type,` +
-		` projections.secret_generators2.creation_date,` +
-		` projections.secret_generators2.change_date,` +
-		` projections.secret_generators2.resource_owner,` +
-		` projections.secret_generators2.sequence,` +
-		` projections.secret_generators2.length,` +
-		` projections.secret_generators2.expiry,` +
-		` projections.secret_generators2.include_lower_letters,` +
-		` projections.secret_generators2.include_upper_letters,` +
-		` projections.secret_generators2.include_digits,` +
-		` projections.secret_generators2.include_symbols` +
-		` FROM projections.secret_generators2` +
+	prepareSecretGeneratorStmt = `SELECT projections.secret_generators3.aggregate_id,` +
+		` projections.secret_generators3.generator_type,` +
+		` projections.secret_generators3.creation_date,` +
+		` projections.secret_generators3.change_date,` +
+		` projections.secret_generators3.resource_owner,` +
+		` projections.secret_generators3.sequence,` +
+		` projections.secret_generators3.length,` +
+		` projections.secret_generators3.expiry,` +
+		` projections.secret_generators3.include_lower_letters,` +
+		` projections.secret_generators3.include_upper_letters,` +
+		` projections.secret_generators3.include_digits,` +
+		` projections.secret_generators3.include_symbols,` +
+		` projections.secret_generators3.hashed` +
+		` FROM projections.secret_generators3` +
 		` AS OF SYSTEM TIME '-1 ms'`
 	prepareSecretGeneratorCols = []string{
 		"aggregate_id",
@@ -41,21 +42,23 @@ var (
 		"include_upper_letters",
 		"include_digits",
 		"include_symbols",
+		"hashed",
 	}
-	prepareSecretGeneratorsStmt = `SELECT projections.secret_generators2.aggregate_id,` +
-		` projections.secret_generators2.generator_type,` +
-		` projections.secret_generators2.creation_date,` +
-		` projections.secret_generators2.change_date,` +
-		` projections.secret_generators2.resource_owner,` +
-		` projections.secret_generators2.sequence,` +
-		` projections.secret_generators2.length,` +
-		` projections.secret_generators2.expiry,` +
-		` projections.secret_generators2.include_lower_letters,` +
-		` projections.secret_generators2.include_upper_letters,` +
-		` projections.secret_generators2.include_digits,` +
-		` projections.secret_generators2.include_symbols,` +
+	prepareSecretGeneratorsStmt = `SELECT projections.secret_generators3.aggregate_id,` +
+		` projections.secret_generators3.generator_type,` +
+		` projections.secret_generators3.creation_date,` +
+		` projections.secret_generators3.change_date,` +
+		` projections.secret_generators3.resource_owner,` +
+		` projections.secret_generators3.sequence,` +
+		` projections.secret_generators3.length,` +
+		` projections.secret_generators3.expiry,` +
+		` projections.secret_generators3.include_lower_letters,` +
+		` projections.secret_generators3.include_upper_letters,` +
+		` projections.secret_generators3.include_digits,` +
+		` projections.secret_generators3.include_symbols,` +
+		` projections.secret_generators3.hashed,` +
 		` COUNT(*) OVER ()` +
-		` FROM projections.secret_generators2` +
+		` FROM projections.secret_generators3` +
 		` AS OF SYSTEM TIME '-1 ms'`
 	prepareSecretGeneratorsCols = []string{
 		"aggregate_id",
@@ -70,6 +73,7 @@ var (
 		"include_upper_letters",
 		"include_digits",
 		"include_symbols",
+		"hashed",
 		"count",
 	}
 )
@@ -118,6 +122,7 @@ func Test_SecretGeneratorsPrepares(t *testing.T) {
 							true,
 							true,
 							true,
+							false,
 						},
 					},
 				),
@@ -165,6 +170,7 @@ func Test_SecretGeneratorsPrepares(t *testing.T) {
 							true,
 							true,
 							true,
+							false,
 						},
 						{
 							"agg-id",
@@ -179,6 +185,7 @@ func Test_SecretGeneratorsPrepares(t *testing.T) {
 							true,
 							true,
 							true,
+							false,
 						},
 					},
 				),
@@ -274,6 +281,7 @@ func Test_SecretGeneratorsPrepares(t *testing.T) {
 						true,
 						true,
 						true,
+						true,
 					},
 				),
 			},
@@ -290,6 +298,7 @@ func Test_SecretGeneratorsPrepares(t *testing.T) {
 				IncludeUpperLetters: true,
 				IncludeDigits:       true,
 				IncludeSymbols:      true,
+				Hashed:              true,
 			},
 		},
 		{
@@ -74,6 +74,10 @@ var (
 		name:  projection.SecretGeneratorColumnIncludeSymbols,
 		table: secretGeneratorsTable,
 	}
+	SecretGeneratorColumnHashed = Column{
+		name:  projection.SecretGeneratorColumnHashed,
+		table: secretGeneratorsTable,
+	}
 )
 
 type SecretGenerators struct {
@@ -95,6 +99,7 @@ type SecretGenerator struct {
 	IncludeUpperLetters bool
 	IncludeDigits       bool
 	IncludeSymbols      bool
+	Hashed              bool
 }
 
 type SecretGeneratorSearchQueries struct {
@@ -114,8 +119,9 @@ func (q *Queries) InitEncryptionGenerator(ctx context.Context, generatorType dom
 		IncludeUpperLetters: generatorConfig.IncludeUpperLetters,
 		IncludeDigits:       generatorConfig.IncludeDigits,
 		IncludeSymbols:      generatorConfig.IncludeSymbols,
+		Hashed:              generatorConfig.Hashed,
 	}
-	return crypto.NewEncryptionGenerator(cryptoConfig, algorithm), nil
+	return crypto.NewCodeGenerator(cryptoConfig, algorithm, authz.GetInstance(ctx).InstanceID())
 }
 
 func (q *Queries) InitHashGenerator(ctx context.Context, generatorType domain.SecretGeneratorType, algorithm crypto.HashAlgorithm) (crypto.Generator, error) {
@@ -201,7 +207,8 @@ func prepareSecretGeneratorQuery(ctx context.Context, db prepareDatabase) (sq.Se
 			SecretGeneratorColumnIncludeLowerLetters.identifier(),
 			SecretGeneratorColumnIncludeUpperLetters.identifier(),
 			SecretGeneratorColumnIncludeDigits.identifier(),
-			SecretGeneratorColumnIncludeSymbols.identifier()).
+			SecretGeneratorColumnIncludeSymbols.identifier(),
+			SecretGeneratorColumnHashed.identifier()).
 			From(secretGeneratorsTable.identifier() + db.Timetravel(call.Took(ctx))).
 			PlaceholderFormat(sq.Dollar),
 		func(row *sql.Row) (*SecretGenerator, error) {
@@ -219,6 +226,7 @@ func prepareSecretGeneratorQuery(ctx context.Context, db prepareDatabase) (sq.Se
 				&secretGenerator.IncludeUpperLetters,
 				&secretGenerator.IncludeDigits,
 				&secretGenerator.IncludeSymbols,
+				&secretGenerator.Hashed,
 			)
 			if err != nil {
 				if errs.Is(err, sql.ErrNoRows) {
@@ -244,6 +252,7 @@ func prepareSecretGeneratorsQuery(ctx context.Context, db prepareDatabase) (sq.S
 			SecretGeneratorColumnIncludeUpperLetters.identifier(),
 			SecretGeneratorColumnIncludeDigits.identifier(),
 			SecretGeneratorColumnIncludeSymbols.identifier(),
+			SecretGeneratorColumnHashed.identifier(),
 			countColumn.identifier()).
 			From(secretGeneratorsTable.identifier() + db.Timetravel(call.Took(ctx))).
 			PlaceholderFormat(sq.Dollar),
@@ -265,6 +274,7 @@ func prepareSecretGeneratorsQuery(ctx context.Context, db prepareDatabase) (sq.S
 					&secretGenerator.IncludeUpperLetters,
 					&secretGenerator.IncludeDigits,
 					&secretGenerator.IncludeSymbols,
+					&secretGenerator.Hashed,
 					&count,
 				)
 				if err != nil {
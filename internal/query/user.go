@@ -51,6 +51,16 @@ type Human struct {
 	IsEmailVerified   bool
 	Phone             domain.PhoneNumber
 	IsPhoneVerified   bool
+	// Address is only reconstructed by [Queries.UserAt], the user projection doesn't contain it
+	Address *HumanAddress
+}
+
+type HumanAddress struct {
+	Country       string
+	Locality      string
+	PostalCode    string
+	Region        string
+	StreetAddress string
 }
 
 type Profile struct {
@@ -657,6 +667,21 @@ func NewUserPreferredLoginNameSearchQuery(value string, comparison TextCompariso
 	return NewTextQuery(userPreferredLoginNameCol, value, comparison)
 }
 
+// NewUserOrSearchQuery matches users matching any of the queries
+func NewUserOrSearchQuery(queries ...SearchQuery) (SearchQuery, error) {
+	return newOrQuery(queries...)
+}
+
+// NewUserAndSearchQuery matches users matching all of the queries
+func NewUserAndSearchQuery(queries ...SearchQuery) (SearchQuery, error) {
+	return newAndQuery(queries...)
+}
+
+// NewUserNotSearchQuery matches users not matching the query
+func NewUserNotSearchQuery(query SearchQuery) (SearchQuery, error) {
+	return newNotQuery(query)
+}
+
 func NewUserLoginNamesSearchQuery(value string) (SearchQuery, error) {
 	return NewTextQuery(userLoginNamesLowerListCol, strings.ToLower(value), TextListContains)
 }
@@ -67,6 +67,8 @@ func (wm *HumanOTPReadModel) Reduce() error {
 		case *user.HumanOTPAddedEvent:
 			wm.Secret = e.Secret
 			wm.State = domain.MFAStateNotReady
+		case *user.HumanOTPSecretReencryptedEvent:
+			wm.Secret = e.Secret
 		case *user.HumanOTPVerifiedEvent:
 			wm.State = domain.MFAStateReady
 		case *user.HumanOTPRemovedEvent:
@@ -86,6 +88,7 @@ func (wm *HumanOTPReadModel) Query() *eventstore.SearchQueryBuilder {
 		EventTypes(user.HumanMFAOTPAddedType,
 			user.HumanMFAOTPVerifiedType,
 			user.HumanMFAOTPRemovedType,
+			user.HumanMFAOTPSecretReencryptedType,
 			user.UserRemovedType,
 			user.UserV1MFAOTPAddedType,
 			user.UserV1MFAOTPVerifiedType,
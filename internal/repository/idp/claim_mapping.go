@@ -0,0 +1,51 @@
+package idp
+
+import (
+	"encoding/json"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+// ClaimMappingsSetEvent replaces all claim mappings of the identity provider
+type ClaimMappingsSetEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	ID       string                    `json:"id"`
+	Mappings []*domain.IDPClaimMapping `json:"mappings"`
+}
+
+func NewClaimMappingsSetEvent(
+	base *eventstore.BaseEvent,
+	id string,
+	mappings []*domain.IDPClaimMapping,
+) *ClaimMappingsSetEvent {
+	return &ClaimMappingsSetEvent{
+		BaseEvent: *base,
+		ID:        id,
+		Mappings:  mappings,
+	}
+}
+
+func (e *ClaimMappingsSetEvent) Data() interface{} {
+	return e
+}
+
+func (e *ClaimMappingsSetEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+func ClaimMappingsSetEventMapper(event *repository.Event) (eventstore.Event, error) {
+	e := &ClaimMappingsSetEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+
+	err := json.Unmarshal(event.Data, e)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "IDP-Cm3sl", "unable to unmarshal event")
+	}
+
+	return e, nil
+}
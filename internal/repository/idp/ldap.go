@@ -2,6 +2,7 @@ package idp
 
 import (
 	"encoding/json"
+	"reflect"
 	"time"
 
 	"github.com/zitadel/zitadel/internal/crypto"
@@ -26,6 +27,7 @@ type LDAPIDPAddedEvent struct {
 	Timeout           time.Duration       `json:"timeout"`
 
 	LDAPAttributes
+	LDAPGroupSync
 	Options
 }
 
@@ -131,6 +133,65 @@ func (o *LDAPAttributes) ReduceChanges(changes LDAPAttributeChanges) {
 	}
 }
 
+// LDAPGroupSync defines how the groups of a user are resolved on login
+// and which user grants they are mapped to
+type LDAPGroupSync struct {
+	GroupsAttribute      string             `json:"groupsAttribute,omitempty"`
+	GroupBase            string             `json:"groupBase,omitempty"`
+	GroupMemberAttribute string             `json:"groupMemberAttribute,omitempty"`
+	NestedGroups         bool               `json:"nestedGroups,omitempty"`
+	GroupMappings        []LDAPGroupMapping `json:"groupMappings,omitempty"`
+}
+
+// LDAPGroupMapping grants the roles of the project to all members of the group (DN)
+type LDAPGroupMapping struct {
+	Group     string   `json:"group"`
+	ProjectID string   `json:"projectId"`
+	Roles     []string `json:"roles"`
+}
+
+func (o *LDAPGroupSync) Changes(groupSync LDAPGroupSync) LDAPGroupSyncChanges {
+	changes := LDAPGroupSyncChanges{}
+	if o.GroupsAttribute != groupSync.GroupsAttribute {
+		changes.GroupsAttribute = &groupSync.GroupsAttribute
+	}
+	if o.GroupBase != groupSync.GroupBase {
+		changes.GroupBase = &groupSync.GroupBase
+	}
+	if o.GroupMemberAttribute != groupSync.GroupMemberAttribute {
+		changes.GroupMemberAttribute = &groupSync.GroupMemberAttribute
+	}
+	if o.NestedGroups != groupSync.NestedGroups {
+		changes.NestedGroups = &groupSync.NestedGroups
+	}
+	if !reflect.DeepEqual(o.GroupMappings, groupSync.GroupMappings) {
+		mappings := groupSync.GroupMappings
+		if mappings == nil {
+			mappings = []LDAPGroupMapping{}
+		}
+		changes.GroupMappings = &mappings
+	}
+	return changes
+}
+
+func (o *LDAPGroupSync) ReduceChanges(changes LDAPGroupSyncChanges) {
+	if changes.GroupsAttribute != nil {
+		o.GroupsAttribute = *changes.GroupsAttribute
+	}
+	if changes.GroupBase != nil {
+		o.GroupBase = *changes.GroupBase
+	}
+	if changes.GroupMemberAttribute != nil {
+		o.GroupMemberAttribute = *changes.GroupMemberAttribute
+	}
+	if changes.NestedGroups != nil {
+		o.NestedGroups = *changes.NestedGroups
+	}
+	if changes.GroupMappings != nil {
+		o.GroupMappings = *changes.GroupMappings
+	}
+}
+
 func NewLDAPIDPAddedEvent(
 	base *eventstore.BaseEvent,
 	id string,
@@ -145,6 +206,7 @@ func NewLDAPIDPAddedEvent(
 	userFilters []string,
 	timeout time.Duration,
 	attributes LDAPAttributes,
+	groupSync LDAPGroupSync,
 	options Options,
 ) *LDAPIDPAddedEvent {
 	return &LDAPIDPAddedEvent{
@@ -161,6 +223,7 @@ func NewLDAPIDPAddedEvent(
 		UserFilters:       userFilters,
 		Timeout:           timeout,
 		LDAPAttributes:    attributes,
+		LDAPGroupSync:     groupSync,
 		Options:           options,
 	}
 }
@@ -202,6 +265,7 @@ type LDAPIDPChangedEvent struct {
 	Timeout           *time.Duration      `json:"timeout,omitempty"`
 
 	LDAPAttributeChanges
+	LDAPGroupSyncChanges
 	OptionChanges
 }
 
@@ -237,6 +301,22 @@ func (o LDAPAttributeChanges) IsZero() bool {
 		o.ProfileAttribute == nil
 }
 
+type LDAPGroupSyncChanges struct {
+	GroupsAttribute      *string             `json:"groupsAttribute,omitempty"`
+	GroupBase            *string             `json:"groupBase,omitempty"`
+	GroupMemberAttribute *string             `json:"groupMemberAttribute,omitempty"`
+	NestedGroups         *bool               `json:"nestedGroups,omitempty"`
+	GroupMappings        *[]LDAPGroupMapping `json:"groupMappings,omitempty"`
+}
+
+func (o LDAPGroupSyncChanges) IsZero() bool {
+	return o.GroupsAttribute == nil &&
+		o.GroupBase == nil &&
+		o.GroupMemberAttribute == nil &&
+		o.NestedGroups == nil &&
+		o.GroupMappings == nil
+}
+
 func NewLDAPIDPChangedEvent(
 	base *eventstore.BaseEvent,
 	id string,
@@ -323,6 +403,12 @@ func ChangeLDAPAttributes(attributes LDAPAttributeChanges) func(*LDAPIDPChangedE
 	}
 }
 
+func ChangeLDAPGroupSync(groupSync LDAPGroupSyncChanges) func(*LDAPIDPChangedEvent) {
+	return func(e *LDAPIDPChangedEvent) {
+		e.LDAPGroupSyncChanges = groupSync
+	}
+}
+
 func ChangeLDAPOptions(options OptionChanges) func(*LDAPIDPChangedEvent) {
 	return func(e *LDAPIDPChangedEvent) {
 		e.OptionChanges = options
@@ -7,5 +7,14 @@ import (
 func RegisterEventMappers(es *eventstore.Eventstore) {
 	es.RegisterFilterEventMapper(AggregateType, StartedEventType, StartedEventMapper).
 		RegisterFilterEventMapper(AggregateType, SucceededEventType, SucceededEventMapper).
-		RegisterFilterEventMapper(AggregateType, FailedEventType, FailedEventMapper)
+		RegisterFilterEventMapper(AggregateType, FailedEventType, FailedEventMapper).
+		RegisterFilterEventMapper(AggregateType, UserRemovedEventType, UserRemovedEventMapper)
+	registerPersonalData(es)
+}
+
+// registerPersonalData registers the fields containing the personal data of the user at the identity provider,
+// they are erased as soon as the user of the intent is removed
+func registerPersonalData(es *eventstore.Eventstore) {
+	es.RegisterPIIFields(SucceededEventType, "idpUser", "idpMappedUser", "idpUserName", "idpIdToken").
+		RegisterPIIErasure(UserRemovedEventType)
 }
@@ -15,6 +15,8 @@ const (
 	StartedEventType   = instanceEventTypePrefix + "started"
 	SucceededEventType = instanceEventTypePrefix + "succeeded"
 	FailedEventType    = instanceEventTypePrefix + "failed"
+	// UserRemovedEventType erases the personal data of the intent after its user was removed
+	UserRemovedEventType = instanceEventTypePrefix + "user.removed"
 )
 
 type StartedEvent struct {
@@ -69,6 +71,7 @@ type SucceededEvent struct {
 	eventstore.BaseEvent `json:"-"`
 
 	IDPUser        []byte              `json:"idpUser"`
+	IDPMappedUser  []byte              `json:"idpMappedUser,omitempty"`
 	IDPUserID      string              `json:"idpUserId,omitempty"`
 	IDPUserName    string              `json:"idpUserName,omitempty"`
 	UserID         string              `json:"userId,omitempty"`
@@ -79,7 +82,8 @@ type SucceededEvent struct {
 func NewSucceededEvent(
 	ctx context.Context,
 	aggregate *eventstore.Aggregate,
-	idpUser []byte,
+	idpUser,
+	idpMappedUser []byte,
 	idpUserID,
 	idpUserName,
 	userID string,
@@ -93,6 +97,7 @@ func NewSucceededEvent(
 			SucceededEventType,
 		),
 		IDPUser:        idpUser,
+		IDPMappedUser:  idpMappedUser,
 		IDPUserID:      idpUserID,
 		IDPUserName:    idpUserName,
 		UserID:         userID,
@@ -163,3 +168,34 @@ func FailedEventMapper(event *repository.Event) (eventstore.Event, error) {
 
 	return e, nil
 }
+
+type UserRemovedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func NewUserRemovedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+) *UserRemovedEvent {
+	return &UserRemovedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			UserRemovedEventType,
+		),
+	}
+}
+
+func (e *UserRemovedEvent) Data() interface{} {
+	return nil
+}
+
+func (e *UserRemovedEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+func UserRemovedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	return &UserRemovedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}, nil
+}
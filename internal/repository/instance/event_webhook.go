@@ -0,0 +1,229 @@
+package instance
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+const (
+	eventWebhookPrefix                     = "event.webhook."
+	EventWebhookAddedEventType             = instanceEventTypePrefix + eventWebhookPrefix + "added"
+	EventWebhookChangedEventType           = instanceEventTypePrefix + eventWebhookPrefix + "changed"
+	EventWebhookSigningKeyChangedEventType = instanceEventTypePrefix + eventWebhookPrefix + "signing.key.changed"
+	EventWebhookRemovedEventType           = instanceEventTypePrefix + eventWebhookPrefix + "removed"
+)
+
+type EventWebhookAddedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	ID         string              `json:"id,omitempty"`
+	Name       string              `json:"name,omitempty"`
+	CallURL    string              `json:"callUrl,omitempty"`
+	EventTypes []string            `json:"eventTypes,omitempty"`
+	SigningKey *crypto.CryptoValue `json:"signingKey,omitempty"`
+}
+
+func NewEventWebhookAddedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	id,
+	name,
+	callURL string,
+	eventTypes []string,
+	signingKey *crypto.CryptoValue,
+) *EventWebhookAddedEvent {
+	return &EventWebhookAddedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			EventWebhookAddedEventType,
+		),
+		ID:         id,
+		Name:       name,
+		CallURL:    callURL,
+		EventTypes: eventTypes,
+		SigningKey: signingKey,
+	}
+}
+
+func (e *EventWebhookAddedEvent) Data() interface{} {
+	return e
+}
+
+func (e *EventWebhookAddedEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+func EventWebhookAddedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	eventWebhookAdded := &EventWebhookAddedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	err := json.Unmarshal(event.Data, eventWebhookAdded)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "IAM-Wh3ad", "unable to unmarshal event webhook added")
+	}
+
+	return eventWebhookAdded, nil
+}
+
+type EventWebhookChangedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	ID         string    `json:"id,omitempty"`
+	Name       *string   `json:"name,omitempty"`
+	CallURL    *string   `json:"callUrl,omitempty"`
+	EventTypes *[]string `json:"eventTypes,omitempty"`
+}
+
+func NewEventWebhookChangedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	id string,
+	changes []EventWebhookChanges,
+) (*EventWebhookChangedEvent, error) {
+	if len(changes) == 0 {
+		return nil, errors.ThrowPreconditionFailed(nil, "IAM-Wh4ch", "Errors.NoChangesFound")
+	}
+	changeEvent := &EventWebhookChangedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			EventWebhookChangedEventType,
+		),
+		ID: id,
+	}
+	for _, change := range changes {
+		change(changeEvent)
+	}
+	return changeEvent, nil
+}
+
+type EventWebhookChanges func(event *EventWebhookChangedEvent)
+
+func ChangeEventWebhookName(name string) func(event *EventWebhookChangedEvent) {
+	return func(e *EventWebhookChangedEvent) {
+		e.Name = &name
+	}
+}
+
+func ChangeEventWebhookCallURL(callURL string) func(event *EventWebhookChangedEvent) {
+	return func(e *EventWebhookChangedEvent) {
+		e.CallURL = &callURL
+	}
+}
+
+func ChangeEventWebhookEventTypes(eventTypes []string) func(event *EventWebhookChangedEvent) {
+	return func(e *EventWebhookChangedEvent) {
+		e.EventTypes = &eventTypes
+	}
+}
+
+func (e *EventWebhookChangedEvent) Data() interface{} {
+	return e
+}
+
+func (e *EventWebhookChangedEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+func EventWebhookChangedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	eventWebhookChanged := &EventWebhookChangedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	err := json.Unmarshal(event.Data, eventWebhookChanged)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "IAM-Wh5ch", "unable to unmarshal event webhook changed")
+	}
+
+	return eventWebhookChanged, nil
+}
+
+type EventWebhookSigningKeyChangedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	ID         string              `json:"id,omitempty"`
+	SigningKey *crypto.CryptoValue `json:"signingKey,omitempty"`
+}
+
+func NewEventWebhookSigningKeyChangedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	id string,
+	signingKey *crypto.CryptoValue,
+) *EventWebhookSigningKeyChangedEvent {
+	return &EventWebhookSigningKeyChangedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			EventWebhookSigningKeyChangedEventType,
+		),
+		ID:         id,
+		SigningKey: signingKey,
+	}
+}
+
+func (e *EventWebhookSigningKeyChangedEvent) Data() interface{} {
+	return e
+}
+
+func (e *EventWebhookSigningKeyChangedEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+func EventWebhookSigningKeyChangedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	signingKeyChanged := &EventWebhookSigningKeyChangedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	err := json.Unmarshal(event.Data, signingKeyChanged)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "IAM-Wh6sk", "unable to unmarshal event webhook signing key changed")
+	}
+
+	return signingKeyChanged, nil
+}
+
+type EventWebhookRemovedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	ID string `json:"id,omitempty"`
+}
+
+func NewEventWebhookRemovedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	id string,
+) *EventWebhookRemovedEvent {
+	return &EventWebhookRemovedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			EventWebhookRemovedEventType,
+		),
+		ID: id,
+	}
+}
+
+func (e *EventWebhookRemovedEvent) Data() interface{} {
+	return e
+}
+
+func (e *EventWebhookRemovedEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+func EventWebhookRemovedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	eventWebhookRemoved := &EventWebhookRemovedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	err := json.Unmarshal(event.Data, eventWebhookRemoved)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "IAM-Wh7rm", "unable to unmarshal event webhook removed")
+	}
+
+	return eventWebhookRemoved, nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/repository"
 	"github.com/zitadel/zitadel/internal/repository/idp"
@@ -33,7 +34,10 @@ const (
 	GoogleIDPChangedEventType           eventstore.EventType = "instance.idp.google.changed"
 	LDAPIDPAddedEventType               eventstore.EventType = "instance.idp.ldap.v2.added"
 	LDAPIDPChangedEventType             eventstore.EventType = "instance.idp.ldap.v2.changed"
+	SAMLIDPAddedEventType               eventstore.EventType = "instance.idp.saml.added"
+	SAMLIDPChangedEventType             eventstore.EventType = "instance.idp.saml.changed"
 	IDPRemovedEventType                 eventstore.EventType = "instance.idp.removed"
+	IDPClaimMappingsSetEventType        eventstore.EventType = "instance.idp.claim.mappings.set"
 )
 
 type OAuthIDPAddedEvent struct {
@@ -849,6 +853,7 @@ func NewLDAPIDPAddedEvent(
 	userFilters []string,
 	timeout time.Duration,
 	attributes idp.LDAPAttributes,
+	groupSync idp.LDAPGroupSync,
 	options idp.Options,
 ) *LDAPIDPAddedEvent {
 
@@ -871,6 +876,7 @@ func NewLDAPIDPAddedEvent(
 			userFilters,
 			timeout,
 			attributes,
+			groupSync,
 			options,
 		),
 	}
@@ -920,6 +926,86 @@ func LDAPIDPChangedEventMapper(event *repository.Event) (eventstore.Event, error
 	return &LDAPIDPChangedEvent{LDAPIDPChangedEvent: *e.(*idp.LDAPIDPChangedEvent)}, nil
 }
 
+type SAMLIDPAddedEvent struct {
+	idp.SAMLIDPAddedEvent
+}
+
+func NewSAMLIDPAddedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	id,
+	name string,
+	metadata []byte,
+	key *crypto.CryptoValue,
+	certificate []byte,
+	binding string,
+	withSignedRequest bool,
+	options idp.Options,
+) *SAMLIDPAddedEvent {
+
+	return &SAMLIDPAddedEvent{
+		SAMLIDPAddedEvent: *idp.NewSAMLIDPAddedEvent(
+			eventstore.NewBaseEventForPush(
+				ctx,
+				aggregate,
+				SAMLIDPAddedEventType,
+			),
+			id,
+			name,
+			metadata,
+			key,
+			certificate,
+			binding,
+			withSignedRequest,
+			options,
+		),
+	}
+}
+
+func SAMLIDPAddedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	e, err := idp.SAMLIDPAddedEventMapper(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SAMLIDPAddedEvent{SAMLIDPAddedEvent: *e.(*idp.SAMLIDPAddedEvent)}, nil
+}
+
+type SAMLIDPChangedEvent struct {
+	idp.SAMLIDPChangedEvent
+}
+
+func NewSAMLIDPChangedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	id string,
+	changes []idp.SAMLIDPChanges,
+) (*SAMLIDPChangedEvent, error) {
+
+	changedEvent, err := idp.NewSAMLIDPChangedEvent(
+		eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			SAMLIDPChangedEventType,
+		),
+		id,
+		changes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &SAMLIDPChangedEvent{SAMLIDPChangedEvent: *changedEvent}, nil
+}
+
+func SAMLIDPChangedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	e, err := idp.SAMLIDPChangedEventMapper(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SAMLIDPChangedEvent{SAMLIDPChangedEvent: *e.(*idp.SAMLIDPChangedEvent)}, nil
+}
+
 type IDPRemovedEvent struct {
 	idp.RemovedEvent
 }
@@ -953,3 +1039,35 @@ func IDPRemovedEventMapper(event *repository.Event) (eventstore.Event, error) {
 
 	return &IDPRemovedEvent{RemovedEvent: *e.(*idp.RemovedEvent)}, nil
 }
+
+type IDPClaimMappingsSetEvent struct {
+	idp.ClaimMappingsSetEvent
+}
+
+func NewIDPClaimMappingsSetEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	id string,
+	mappings []*domain.IDPClaimMapping,
+) *IDPClaimMappingsSetEvent {
+	return &IDPClaimMappingsSetEvent{
+		ClaimMappingsSetEvent: *idp.NewClaimMappingsSetEvent(
+			eventstore.NewBaseEventForPush(
+				ctx,
+				aggregate,
+				IDPClaimMappingsSetEventType,
+			),
+			id,
+			mappings,
+		),
+	}
+}
+
+func IDPClaimMappingsSetEventMapper(event *repository.Event) (eventstore.Event, error) {
+	e, err := idp.ClaimMappingsSetEventMapper(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IDPClaimMappingsSetEvent{ClaimMappingsSetEvent: *e.(*idp.ClaimMappingsSetEvent)}, nil
+}
@@ -23,6 +23,7 @@ type OIDCSettingsAddedEvent struct {
 	IdTokenLifetime            time.Duration `json:"idTokenLifetime,omitempty"`
 	RefreshTokenIdleExpiration time.Duration `json:"refreshTokenIdleExpiration,omitempty"`
 	RefreshTokenExpiration     time.Duration `json:"refreshTokenExpiration,omitempty"`
+	SigningKeyAlgorithm        string        `json:"signingKeyAlgorithm,omitempty"`
 }
 
 func NewOIDCSettingsAddedEvent(
@@ -32,6 +33,7 @@ func NewOIDCSettingsAddedEvent(
 	idTokenLifetime,
 	refreshTokenIdleExpiration,
 	refreshTokenExpiration time.Duration,
+	signingKeyAlgorithm string,
 ) *OIDCSettingsAddedEvent {
 	return &OIDCSettingsAddedEvent{
 		BaseEvent: *eventstore.NewBaseEventForPush(
@@ -43,6 +45,7 @@ func NewOIDCSettingsAddedEvent(
 		IdTokenLifetime:            idTokenLifetime,
 		RefreshTokenIdleExpiration: refreshTokenIdleExpiration,
 		RefreshTokenExpiration:     refreshTokenExpiration,
+		SigningKeyAlgorithm:        signingKeyAlgorithm,
 	}
 }
 
@@ -73,6 +76,7 @@ type OIDCSettingsChangedEvent struct {
 	IdTokenLifetime            *time.Duration `json:"idTokenLifetime,omitempty"`
 	RefreshTokenIdleExpiration *time.Duration `json:"refreshTokenIdleExpiration,omitempty"`
 	RefreshTokenExpiration     *time.Duration `json:"refreshTokenExpiration,omitempty"`
+	SigningKeyAlgorithm        *string        `json:"signingKeyAlgorithm,omitempty"`
 }
 
 func (e *OIDCSettingsChangedEvent) Data() interface{} {
@@ -130,6 +134,12 @@ func ChangeOIDCSettingsRefreshTokenExpiration(refreshTokenExpiration time.Durati
 	}
 }
 
+func ChangeOIDCSettingsSigningKeyAlgorithm(signingKeyAlgorithm string) func(event *OIDCSettingsChangedEvent) {
+	return func(e *OIDCSettingsChangedEvent) {
+		e.SigningKeyAlgorithm = &signingKeyAlgorithm
+	}
+}
+
 func OIDCSettingsChangedEventMapper(event *repository.Event) (eventstore.Event, error) {
 	e := &OIDCSettingsChangedEvent{
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
@@ -3,6 +3,7 @@ package instance
 import (
 	"context"
 
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 
 	"github.com/zitadel/zitadel/internal/eventstore/repository"
@@ -25,7 +26,14 @@ func NewPasswordComplexityPolicyAddedEvent(
 	hasLowercase,
 	hasUppercase,
 	hasNumber,
-	hasSymbol bool,
+	hasSymbol,
+	rejectBreached bool,
+	maxLength uint64,
+	forbidUsername,
+	forbidEmailLocalPart,
+	forbidOrgName bool,
+	rules []domain.PasswordRule,
+	bannedWords []string,
 ) *PasswordComplexityPolicyAddedEvent {
 	return &PasswordComplexityPolicyAddedEvent{
 		PasswordComplexityPolicyAddedEvent: *policy.NewPasswordComplexityPolicyAddedEvent(
@@ -37,7 +45,14 @@ func NewPasswordComplexityPolicyAddedEvent(
 			hasLowercase,
 			hasUppercase,
 			hasNumber,
-			hasSymbol),
+			hasSymbol,
+			rejectBreached,
+			maxLength,
+			forbidUsername,
+			forbidEmailLocalPart,
+			forbidOrgName,
+			rules,
+			bannedWords),
 	}
 }
 
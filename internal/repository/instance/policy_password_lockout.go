@@ -2,6 +2,7 @@ package instance
 
 import (
 	"context"
+	"time"
 
 	"github.com/zitadel/zitadel/internal/eventstore"
 
@@ -23,6 +24,11 @@ func NewLockoutPolicyAddedEvent(
 	aggregate *eventstore.Aggregate,
 	maxAttempts uint64,
 	showLockoutFailure bool,
+	maxOTPAttempts,
+	maxPasskeyAttempts,
+	maxIPAttempts uint64,
+	lockoutDuration,
+	progressiveDelay time.Duration,
 ) *LockoutPolicyAddedEvent {
 	return &LockoutPolicyAddedEvent{
 		LockoutPolicyAddedEvent: *policy.NewLockoutPolicyAddedEvent(
@@ -31,7 +37,12 @@ func NewLockoutPolicyAddedEvent(
 				aggregate,
 				LockoutPolicyAddedEventType),
 			maxAttempts,
-			showLockoutFailure),
+			showLockoutFailure,
+			maxOTPAttempts,
+			maxPasskeyAttempts,
+			maxIPAttempts,
+			lockoutDuration,
+			progressiveDelay),
 	}
 }
 
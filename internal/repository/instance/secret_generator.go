@@ -42,6 +42,7 @@ type SecretGeneratorAddedEvent struct {
 	IncludeUpperLetters bool                       `json:"includeUpperLetters,omitempty"`
 	IncludeDigits       bool                       `json:"includeDigits,omitempty"`
 	IncludeSymbols      bool                       `json:"includeSymbols,omitempty"`
+	Hashed              bool                       `json:"hashed,omitempty"`
 }
 
 func NewSecretGeneratorAddedEvent(
@@ -53,7 +54,8 @@ func NewSecretGeneratorAddedEvent(
 	includeLowerLetters,
 	includeUpperLetters,
 	includeDigits,
-	includeSymbols bool,
+	includeSymbols,
+	hashed bool,
 ) *SecretGeneratorAddedEvent {
 	return &SecretGeneratorAddedEvent{
 		BaseEvent: *eventstore.NewBaseEventForPush(
@@ -68,6 +70,7 @@ func NewSecretGeneratorAddedEvent(
 		IncludeUpperLetters: includeUpperLetters,
 		IncludeDigits:       includeDigits,
 		IncludeSymbols:      includeSymbols,
+		Hashed:              hashed,
 	}
 }
 
@@ -101,6 +104,7 @@ type SecretGeneratorChangedEvent struct {
 	IncludeUpperLetters *bool                      `json:"includeUpperLetters,omitempty"`
 	IncludeDigits       *bool                      `json:"includeDigits,omitempty"`
 	IncludeSymbols      *bool                      `json:"includeSymbols,omitempty"`
+	Hashed              *bool                      `json:"hashed,omitempty"`
 }
 
 func (e *SecretGeneratorChangedEvent) Data() interface{} {
@@ -172,6 +176,12 @@ func ChangeSecretGeneratorIncludeSymbols(includeSymbols bool) func(event *Secret
 	}
 }
 
+func ChangeSecretGeneratorHashed(hashed bool) func(event *SecretGeneratorChangedEvent) {
+	return func(e *SecretGeneratorChangedEvent) {
+		e.Hashed = &hashed
+	}
+}
+
 func SecretGeneratorChangedEventMapper(event *repository.Event) (eventstore.Event, error) {
 	e := &SecretGeneratorChangedEvent{
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
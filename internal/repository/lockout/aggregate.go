@@ -0,0 +1,31 @@
+package lockout
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+const (
+	AggregateType    = "ip_lockout"
+	AggregateVersion = "v1"
+)
+
+type Aggregate struct {
+	eventstore.Aggregate
+}
+
+// NewIPAggregate returns the aggregate of the failed checks of a source IP in the instance of the context
+func NewIPAggregate(ctx context.Context, ip string) *Aggregate {
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	return &Aggregate{
+		Aggregate: eventstore.Aggregate{
+			Type:          AggregateType,
+			Version:       AggregateVersion,
+			ID:            ip,
+			ResourceOwner: instanceID,
+			InstanceID:    instanceID,
+		},
+	}
+}
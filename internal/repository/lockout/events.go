@@ -0,0 +1,42 @@
+package lockout
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+const (
+	eventTypePrefix        = eventstore.EventType("ip_lockout.")
+	IPCheckFailedEventType = eventTypePrefix + "check.failed"
+)
+
+// IPCheckFailedEvent is a failed check of any user from the source IP of the aggregate
+type IPCheckFailedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func (e *IPCheckFailedEvent) Data() interface{} {
+	return nil
+}
+
+func (e *IPCheckFailedEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+func NewIPCheckFailedEvent(ctx context.Context, aggregate *eventstore.Aggregate) *IPCheckFailedEvent {
+	return &IPCheckFailedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			IPCheckFailedEventType,
+		),
+	}
+}
+
+func IPCheckFailedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	return &IPCheckFailedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}, nil
+}
@@ -0,0 +1,9 @@
+package lockout
+
+import (
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+func RegisterEventMappers(es *eventstore.Eventstore) {
+	es.RegisterFilterEventMapper(AggregateType, IPCheckFailedEventType, IPCheckFailedEventMapper)
+}
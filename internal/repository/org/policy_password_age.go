@@ -23,7 +23,9 @@ func NewPasswordAgePolicyAddedEvent(
 	ctx context.Context,
 	aggregate *eventstore.Aggregate,
 	expireWarnDays,
-	maxAgeDays uint64,
+	maxAgeDays,
+	minAgeDays,
+	historyCount uint64,
 ) *PasswordAgePolicyAddedEvent {
 	return &PasswordAgePolicyAddedEvent{
 		PasswordAgePolicyAddedEvent: *policy.NewPasswordAgePolicyAddedEvent(
@@ -32,7 +34,9 @@ func NewPasswordAgePolicyAddedEvent(
 				aggregate,
 				PasswordAgePolicyAddedEventType),
 			expireWarnDays,
-			maxAgeDays),
+			maxAgeDays,
+			minAgeDays,
+			historyCount),
 	}
 }
 
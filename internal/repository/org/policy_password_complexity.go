@@ -3,6 +3,7 @@ package org
 import (
 	"context"
 
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 
 	"github.com/zitadel/zitadel/internal/eventstore/repository"
@@ -26,7 +27,14 @@ func NewPasswordComplexityPolicyAddedEvent(
 	hasLowercase,
 	hasUppercase,
 	hasNumber,
-	hasSymbol bool,
+	hasSymbol,
+	rejectBreached bool,
+	maxLength uint64,
+	forbidUsername,
+	forbidEmailLocalPart,
+	forbidOrgName bool,
+	rules []domain.PasswordRule,
+	bannedWords []string,
 ) *PasswordComplexityPolicyAddedEvent {
 	return &PasswordComplexityPolicyAddedEvent{
 		PasswordComplexityPolicyAddedEvent: *policy.NewPasswordComplexityPolicyAddedEvent(
@@ -38,7 +46,14 @@ func NewPasswordComplexityPolicyAddedEvent(
 			hasLowercase,
 			hasUppercase,
 			hasNumber,
-			hasSymbol),
+			hasSymbol,
+			rejectBreached,
+			maxLength,
+			forbidUsername,
+			forbidEmailLocalPart,
+			forbidOrgName,
+			rules,
+			bannedWords),
 	}
 }
 
@@ -2,6 +2,7 @@ package org
 
 import (
 	"context"
+	"time"
 
 	"github.com/zitadel/zitadel/internal/eventstore"
 
@@ -24,6 +25,11 @@ func NewLockoutPolicyAddedEvent(
 	aggregate *eventstore.Aggregate,
 	maxAttempts uint64,
 	showLockoutFailure bool,
+	maxOTPAttempts,
+	maxPasskeyAttempts,
+	maxIPAttempts uint64,
+	lockoutDuration,
+	progressiveDelay time.Duration,
 ) *LockoutPolicyAddedEvent {
 	return &LockoutPolicyAddedEvent{
 		LockoutPolicyAddedEvent: *policy.NewLockoutPolicyAddedEvent(
@@ -32,7 +38,12 @@ func NewLockoutPolicyAddedEvent(
 				aggregate,
 				LockoutPolicyAddedEventType),
 			maxAttempts,
-			showLockoutFailure),
+			showLockoutFailure,
+			maxOTPAttempts,
+			maxPasskeyAttempts,
+			maxIPAttempts,
+			lockoutDuration,
+			progressiveDelay),
 	}
 }
 
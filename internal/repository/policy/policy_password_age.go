@@ -19,6 +19,8 @@ type PasswordAgePolicyAddedEvent struct {
 
 	ExpireWarnDays uint64 `json:"expireWarnDays,omitempty"`
 	MaxAgeDays     uint64 `json:"maxAgeDays,omitempty"`
+	MinAgeDays     uint64 `json:"minAgeDays,omitempty"`
+	HistoryCount   uint64 `json:"historyCount,omitempty"`
 }
 
 func (e *PasswordAgePolicyAddedEvent) Data() interface{} {
@@ -32,13 +34,17 @@ func (e *PasswordAgePolicyAddedEvent) UniqueConstraints() []*eventstore.EventUni
 func NewPasswordAgePolicyAddedEvent(
 	base *eventstore.BaseEvent,
 	expireWarnDays,
-	maxAgeDays uint64,
+	maxAgeDays,
+	minAgeDays,
+	historyCount uint64,
 ) *PasswordAgePolicyAddedEvent {
 
 	return &PasswordAgePolicyAddedEvent{
 		BaseEvent:      *base,
 		ExpireWarnDays: expireWarnDays,
 		MaxAgeDays:     maxAgeDays,
+		MinAgeDays:     minAgeDays,
+		HistoryCount:   historyCount,
 	}
 }
 
@@ -60,6 +66,8 @@ type PasswordAgePolicyChangedEvent struct {
 
 	ExpireWarnDays *uint64 `json:"expireWarnDays,omitempty"`
 	MaxAgeDays     *uint64 `json:"maxAgeDays,omitempty"`
+	MinAgeDays     *uint64 `json:"minAgeDays,omitempty"`
+	HistoryCount   *uint64 `json:"historyCount,omitempty"`
 }
 
 func (e *PasswordAgePolicyChangedEvent) Data() interface{} {
@@ -100,6 +108,18 @@ func ChangeMaxAgeDays(maxAgeDays uint64) func(*PasswordAgePolicyChangedEvent) {
 	}
 }
 
+func ChangeMinAgeDays(minAgeDays uint64) func(*PasswordAgePolicyChangedEvent) {
+	return func(e *PasswordAgePolicyChangedEvent) {
+		e.MinAgeDays = &minAgeDays
+	}
+}
+
+func ChangeHistoryCount(historyCount uint64) func(*PasswordAgePolicyChangedEvent) {
+	return func(e *PasswordAgePolicyChangedEvent) {
+		e.HistoryCount = &historyCount
+	}
+}
+
 func PasswordAgePolicyChangedEventMapper(event *repository.Event) (eventstore.Event, error) {
 	e := &PasswordAgePolicyChangedEvent{
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
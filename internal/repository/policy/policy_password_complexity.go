@@ -3,6 +3,7 @@ package policy
 import (
 	"encoding/json"
 
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 
 	"github.com/zitadel/zitadel/internal/errors"
@@ -18,11 +19,19 @@ const (
 type PasswordComplexityPolicyAddedEvent struct {
 	eventstore.BaseEvent `json:"-"`
 
-	MinLength    uint64 `json:"minLength,omitempty"`
-	HasLowercase bool   `json:"hasLowercase,omitempty"`
-	HasUppercase bool   `json:"hasUppercase,omitempty"`
-	HasNumber    bool   `json:"hasNumber,omitempty"`
-	HasSymbol    bool   `json:"hasSymbol,omitempty"`
+	MinLength      uint64 `json:"minLength,omitempty"`
+	HasLowercase   bool   `json:"hasLowercase,omitempty"`
+	HasUppercase   bool   `json:"hasUppercase,omitempty"`
+	HasNumber      bool   `json:"hasNumber,omitempty"`
+	HasSymbol      bool   `json:"hasSymbol,omitempty"`
+	RejectBreached bool   `json:"rejectBreached,omitempty"`
+
+	MaxLength            uint64                `json:"maxLength,omitempty"`
+	ForbidUsername       bool                  `json:"forbidUsername,omitempty"`
+	ForbidEmailLocalPart bool                  `json:"forbidEmailLocalPart,omitempty"`
+	ForbidOrgName        bool                  `json:"forbidOrgName,omitempty"`
+	Rules                []domain.PasswordRule `json:"rules,omitempty"`
+	BannedWords          []string              `json:"bannedWords,omitempty"`
 }
 
 func (e *PasswordComplexityPolicyAddedEvent) Data() interface{} {
@@ -39,15 +48,29 @@ func NewPasswordComplexityPolicyAddedEvent(
 	hasLowerCase,
 	hasUpperCase,
 	hasNumber,
-	hasSymbol bool,
+	hasSymbol,
+	rejectBreached bool,
+	maxLength uint64,
+	forbidUsername,
+	forbidEmailLocalPart,
+	forbidOrgName bool,
+	rules []domain.PasswordRule,
+	bannedWords []string,
 ) *PasswordComplexityPolicyAddedEvent {
 	return &PasswordComplexityPolicyAddedEvent{
-		BaseEvent:    *base,
-		MinLength:    minLength,
-		HasLowercase: hasLowerCase,
-		HasUppercase: hasUpperCase,
-		HasNumber:    hasNumber,
-		HasSymbol:    hasSymbol,
+		BaseEvent:            *base,
+		MinLength:            minLength,
+		HasLowercase:         hasLowerCase,
+		HasUppercase:         hasUpperCase,
+		HasNumber:            hasNumber,
+		HasSymbol:            hasSymbol,
+		RejectBreached:       rejectBreached,
+		MaxLength:            maxLength,
+		ForbidUsername:       forbidUsername,
+		ForbidEmailLocalPart: forbidEmailLocalPart,
+		ForbidOrgName:        forbidOrgName,
+		Rules:                rules,
+		BannedWords:          bannedWords,
 	}
 }
 
@@ -67,11 +90,19 @@ func PasswordComplexityPolicyAddedEventMapper(event *repository.Event) (eventsto
 type PasswordComplexityPolicyChangedEvent struct {
 	eventstore.BaseEvent `json:"-"`
 
-	MinLength    *uint64 `json:"minLength,omitempty"`
-	HasLowercase *bool   `json:"hasLowercase,omitempty"`
-	HasUppercase *bool   `json:"hasUppercase,omitempty"`
-	HasNumber    *bool   `json:"hasNumber,omitempty"`
-	HasSymbol    *bool   `json:"hasSymbol,omitempty"`
+	MinLength      *uint64 `json:"minLength,omitempty"`
+	HasLowercase   *bool   `json:"hasLowercase,omitempty"`
+	HasUppercase   *bool   `json:"hasUppercase,omitempty"`
+	HasNumber      *bool   `json:"hasNumber,omitempty"`
+	HasSymbol      *bool   `json:"hasSymbol,omitempty"`
+	RejectBreached *bool   `json:"rejectBreached,omitempty"`
+
+	MaxLength            *uint64                `json:"maxLength,omitempty"`
+	ForbidUsername       *bool                  `json:"forbidUsername,omitempty"`
+	ForbidEmailLocalPart *bool                  `json:"forbidEmailLocalPart,omitempty"`
+	ForbidOrgName        *bool                  `json:"forbidOrgName,omitempty"`
+	Rules                *[]domain.PasswordRule `json:"rules,omitempty"`
+	BannedWords          *[]string              `json:"bannedWords,omitempty"`
 }
 
 func (e *PasswordComplexityPolicyChangedEvent) Data() interface{} {
@@ -130,6 +161,50 @@ func ChangeHasSymbol(hasSymbol bool) func(*PasswordComplexityPolicyChangedEvent)
 	}
 }
 
+func ChangeRejectBreached(rejectBreached bool) func(*PasswordComplexityPolicyChangedEvent) {
+	return func(e *PasswordComplexityPolicyChangedEvent) {
+		e.RejectBreached = &rejectBreached
+	}
+}
+
+func ChangeMaxLength(maxLength uint64) func(*PasswordComplexityPolicyChangedEvent) {
+	return func(e *PasswordComplexityPolicyChangedEvent) {
+		e.MaxLength = &maxLength
+	}
+}
+
+func ChangeForbidUsername(forbidUsername bool) func(*PasswordComplexityPolicyChangedEvent) {
+	return func(e *PasswordComplexityPolicyChangedEvent) {
+		e.ForbidUsername = &forbidUsername
+	}
+}
+
+func ChangeForbidEmailLocalPart(forbidEmailLocalPart bool) func(*PasswordComplexityPolicyChangedEvent) {
+	return func(e *PasswordComplexityPolicyChangedEvent) {
+		e.ForbidEmailLocalPart = &forbidEmailLocalPart
+	}
+}
+
+func ChangeForbidOrgName(forbidOrgName bool) func(*PasswordComplexityPolicyChangedEvent) {
+	return func(e *PasswordComplexityPolicyChangedEvent) {
+		e.ForbidOrgName = &forbidOrgName
+	}
+}
+
+// ChangeRules replaces all custom rules of the policy
+func ChangeRules(rules []domain.PasswordRule) func(*PasswordComplexityPolicyChangedEvent) {
+	return func(e *PasswordComplexityPolicyChangedEvent) {
+		e.Rules = &rules
+	}
+}
+
+// ChangeBannedWords replaces the whole dictionary of the policy
+func ChangeBannedWords(bannedWords []string) func(*PasswordComplexityPolicyChangedEvent) {
+	return func(e *PasswordComplexityPolicyChangedEvent) {
+		e.BannedWords = &bannedWords
+	}
+}
+
 func PasswordComplexityPolicyChangedEventMapper(event *repository.Event) (eventstore.Event, error) {
 	e := &PasswordComplexityPolicyChangedEvent{
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
@@ -2,6 +2,7 @@ package policy
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/zitadel/zitadel/internal/eventstore"
 
@@ -18,8 +19,13 @@ const (
 type LockoutPolicyAddedEvent struct {
 	eventstore.BaseEvent `json:"-"`
 
-	MaxPasswordAttempts uint64 `json:"maxPasswordAttempts,omitempty"`
-	ShowLockOutFailures bool   `json:"showLockOutFailures,omitempty"`
+	MaxPasswordAttempts uint64        `json:"maxPasswordAttempts,omitempty"`
+	ShowLockOutFailures bool          `json:"showLockOutFailures,omitempty"`
+	MaxOTPAttempts      uint64        `json:"maxOTPAttempts,omitempty"`
+	MaxPasskeyAttempts  uint64        `json:"maxPasskeyAttempts,omitempty"`
+	MaxIPAttempts       uint64        `json:"maxIPAttempts,omitempty"`
+	LockoutDuration     time.Duration `json:"lockoutDuration,omitempty"`
+	ProgressiveDelay    time.Duration `json:"progressiveDelay,omitempty"`
 }
 
 func (e *LockoutPolicyAddedEvent) Data() interface{} {
@@ -34,12 +40,22 @@ func NewLockoutPolicyAddedEvent(
 	base *eventstore.BaseEvent,
 	maxAttempts uint64,
 	showLockOutFailures bool,
+	maxOTPAttempts,
+	maxPasskeyAttempts,
+	maxIPAttempts uint64,
+	lockoutDuration,
+	progressiveDelay time.Duration,
 ) *LockoutPolicyAddedEvent {
 
 	return &LockoutPolicyAddedEvent{
 		BaseEvent:           *base,
 		MaxPasswordAttempts: maxAttempts,
 		ShowLockOutFailures: showLockOutFailures,
+		MaxOTPAttempts:      maxOTPAttempts,
+		MaxPasskeyAttempts:  maxPasskeyAttempts,
+		MaxIPAttempts:       maxIPAttempts,
+		LockoutDuration:     lockoutDuration,
+		ProgressiveDelay:    progressiveDelay,
 	}
 }
 
@@ -59,8 +75,13 @@ func LockoutPolicyAddedEventMapper(event *repository.Event) (eventstore.Event, e
 type LockoutPolicyChangedEvent struct {
 	eventstore.BaseEvent `json:"-"`
 
-	MaxPasswordAttempts *uint64 `json:"maxPasswordAttempts,omitempty"`
-	ShowLockOutFailures *bool   `json:"showLockOutFailures,omitempty"`
+	MaxPasswordAttempts *uint64        `json:"maxPasswordAttempts,omitempty"`
+	ShowLockOutFailures *bool          `json:"showLockOutFailures,omitempty"`
+	MaxOTPAttempts      *uint64        `json:"maxOTPAttempts,omitempty"`
+	MaxPasskeyAttempts  *uint64        `json:"maxPasskeyAttempts,omitempty"`
+	MaxIPAttempts       *uint64        `json:"maxIPAttempts,omitempty"`
+	LockoutDuration     *time.Duration `json:"lockoutDuration,omitempty"`
+	ProgressiveDelay    *time.Duration `json:"progressiveDelay,omitempty"`
 }
 
 func (e *LockoutPolicyChangedEvent) Data() interface{} {
@@ -101,6 +122,36 @@ func ChangeShowLockOutFailures(showLockOutFailures bool) func(*LockoutPolicyChan
 	}
 }
 
+func ChangeMaxOTPAttempts(maxAttempts uint64) func(*LockoutPolicyChangedEvent) {
+	return func(e *LockoutPolicyChangedEvent) {
+		e.MaxOTPAttempts = &maxAttempts
+	}
+}
+
+func ChangeMaxPasskeyAttempts(maxAttempts uint64) func(*LockoutPolicyChangedEvent) {
+	return func(e *LockoutPolicyChangedEvent) {
+		e.MaxPasskeyAttempts = &maxAttempts
+	}
+}
+
+func ChangeMaxIPAttempts(maxAttempts uint64) func(*LockoutPolicyChangedEvent) {
+	return func(e *LockoutPolicyChangedEvent) {
+		e.MaxIPAttempts = &maxAttempts
+	}
+}
+
+func ChangeLockoutDuration(lockoutDuration time.Duration) func(*LockoutPolicyChangedEvent) {
+	return func(e *LockoutPolicyChangedEvent) {
+		e.LockoutDuration = &lockoutDuration
+	}
+}
+
+func ChangeProgressiveDelay(progressiveDelay time.Duration) func(*LockoutPolicyChangedEvent) {
+	return func(e *LockoutPolicyChangedEvent) {
+		e.ProgressiveDelay = &progressiveDelay
+	}
+}
+
 func LockoutPolicyChangedEventMapper(event *repository.Event) (eventstore.Event, error) {
 	e := &LockoutPolicyChangedEvent{
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
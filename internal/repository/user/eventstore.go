@@ -1,6 +1,8 @@
 package user
 
 import (
+	"github.com/zitadel/logging"
+
 	"github.com/zitadel/zitadel/internal/eventstore"
 )
 
@@ -62,6 +64,7 @@ func RegisterEventMappers(es *eventstore.Eventstore) {
 		RegisterFilterEventMapper(AggregateType, HumanPasswordChangeSentType, HumanPasswordChangeSentEventMapper).
 		RegisterFilterEventMapper(AggregateType, HumanPasswordCheckSucceededType, HumanPasswordCheckSucceededEventMapper).
 		RegisterFilterEventMapper(AggregateType, HumanPasswordCheckFailedType, HumanPasswordCheckFailedEventMapper).
+		RegisterFilterEventMapper(AggregateType, HumanPasswordHashUpdatedType, HumanPasswordHashUpdatedEventMapper).
 		RegisterFilterEventMapper(AggregateType, UserIDPLinkAddedType, UserIDPLinkAddedEventMapper).
 		RegisterFilterEventMapper(AggregateType, UserIDPLinkRemovedType, UserIDPLinkRemovedEventMapper).
 		RegisterFilterEventMapper(AggregateType, UserIDPLinkCascadeRemovedType, UserIDPLinkCascadeRemovedEventMapper).
@@ -85,6 +88,7 @@ func RegisterEventMappers(es *eventstore.Eventstore) {
 		RegisterFilterEventMapper(AggregateType, HumanMFAOTPAddedType, HumanOTPAddedEventMapper).
 		RegisterFilterEventMapper(AggregateType, HumanMFAOTPVerifiedType, HumanOTPVerifiedEventMapper).
 		RegisterFilterEventMapper(AggregateType, HumanMFAOTPRemovedType, HumanOTPRemovedEventMapper).
+		RegisterFilterEventMapper(AggregateType, HumanMFAOTPSecretReencryptedType, HumanOTPSecretReencryptedEventMapper).
 		RegisterFilterEventMapper(AggregateType, HumanMFAOTPCheckSucceededType, HumanOTPCheckSucceededEventMapper).
 		RegisterFilterEventMapper(AggregateType, HumanMFAOTPCheckFailedType, HumanOTPCheckFailedEventMapper).
 		RegisterFilterEventMapper(AggregateType, HumanU2FTokenAddedType, HumanU2FAddedEventMapper).
@@ -119,4 +123,47 @@ func RegisterEventMappers(es *eventstore.Eventstore) {
 		RegisterFilterEventMapper(AggregateType, MachineSecretRemovedType, MachineSecretRemovedEventMapper).
 		RegisterFilterEventMapper(AggregateType, MachineSecretCheckSucceededType, MachineSecretCheckSucceededEventMapper).
 		RegisterFilterEventMapper(AggregateType, MachineSecretCheckFailedType, MachineSecretCheckFailedEventMapper)
+	registerPersonalData(es)
+	registerUpcasters(es)
+}
+
+// upcasters migrate the payloads of previous aggregate versions
+var upcasters = []*eventstore.Upcaster{
+	{EventType: UserV1AddedType, From: "v1", To: AggregateVersion, Upcast: upcastPreferredLanguageV1},
+	{EventType: UserV1RegisteredType, From: "v1", To: AggregateVersion, Upcast: upcastPreferredLanguageV1},
+	{EventType: HumanAddedType, From: "v1", To: AggregateVersion, Upcast: upcastPreferredLanguageV1},
+	{EventType: HumanRegisteredType, From: "v1", To: AggregateVersion, Upcast: upcastPreferredLanguageV1},
+}
+
+func registerUpcasters(es *eventstore.Eventstore) {
+	for _, upcaster := range upcasters {
+		err := es.RegisterUpcaster(upcaster)
+		logging.WithFields("event_type", upcaster.EventType).OnError(err).Panic("unable to register upcaster")
+	}
+}
+
+// registerPersonalData registers the fields containing personal data of humans,
+// they are erased as soon as the user is removed
+func registerPersonalData(es *eventstore.Eventstore) {
+	profile := []string{"firstName", "lastName", "nickName", "displayName"}
+	address := []string{"country", "locality", "postalCode", "region", "streetAddress"}
+	human := append(append(profile, "userName", "email", "phone"), address...)
+
+	es.RegisterPIIFields(UserV1AddedType, human...).
+		RegisterPIIFields(UserV1RegisteredType, human...).
+		RegisterPIIFields(UserV1ProfileChangedType, profile...).
+		RegisterPIIFields(UserV1EmailChangedType, "email").
+		RegisterPIIFields(UserV1PhoneChangedType, "phone").
+		RegisterPIIFields(UserV1AddressChangedType, address...).
+		RegisterPIIFields(HumanAddedType, human...).
+		RegisterPIIFields(HumanRegisteredType, human...).
+		RegisterPIIFields(UserUserNameChangedType, "userName").
+		RegisterPIIFields(UserDomainClaimedType, "userName").
+		RegisterPIIFields(HumanProfileChangedType, profile...).
+		RegisterPIIFields(HumanEmailChangedType, "email").
+		RegisterPIIFields(HumanPhoneChangedType, "phone").
+		RegisterPIIFields(HumanAddressChangedType, address...).
+		RegisterPIIFields(UserIDPLinkAddedType, "displayName").
+		RegisterPIIFields(MetadataSetType, "value").
+		RegisterPIIErasure(UserRemovedType)
 }
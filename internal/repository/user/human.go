@@ -132,6 +132,28 @@ func HumanAddedEventMapper(event *repository.Event) (eventstore.Event, error) {
 	return humanAdded, nil
 }
 
+// upcastPreferredLanguageV1 removes the preferred language of payloads of the first aggregate version
+// if it's not a well-formed language tag, because the language was stored as free text
+// and unmarshalling it into a language.Tag fails
+func upcastPreferredLanguageV1(data []byte) ([]byte, error) {
+	payload := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	raw, ok := payload["preferredLanguage"]
+	if !ok {
+		return data, nil
+	}
+	var preferredLanguage string
+	if err := json.Unmarshal(raw, &preferredLanguage); err == nil {
+		if _, err = language.Parse(preferredLanguage); err == nil {
+			return data, nil
+		}
+	}
+	delete(payload, "preferredLanguage")
+	return json.Marshal(payload)
+}
+
 type HumanRegisteredEvent struct {
 	eventstore.BaseEvent  `json:"-"`
 	UserName              string `json:"userName"`
@@ -12,12 +12,13 @@ import (
 )
 
 const (
-	otpEventPrefix                = mfaEventPrefix + "otp."
-	HumanMFAOTPAddedType          = otpEventPrefix + "added"
-	HumanMFAOTPVerifiedType       = otpEventPrefix + "verified"
-	HumanMFAOTPRemovedType        = otpEventPrefix + "removed"
-	HumanMFAOTPCheckSucceededType = otpEventPrefix + "check.succeeded"
-	HumanMFAOTPCheckFailedType    = otpEventPrefix + "check.failed"
+	otpEventPrefix                   = mfaEventPrefix + "otp."
+	HumanMFAOTPAddedType             = otpEventPrefix + "added"
+	HumanMFAOTPVerifiedType          = otpEventPrefix + "verified"
+	HumanMFAOTPRemovedType           = otpEventPrefix + "removed"
+	HumanMFAOTPSecretReencryptedType = otpEventPrefix + "secret.reencrypted"
+	HumanMFAOTPCheckSucceededType    = otpEventPrefix + "check.succeeded"
+	HumanMFAOTPCheckFailedType       = otpEventPrefix + "check.failed"
 )
 
 type HumanOTPAddedEvent struct {
@@ -60,6 +61,48 @@ func HumanOTPAddedEventMapper(event *repository.Event) (eventstore.Event, error)
 	return otpAdded, nil
 }
 
+// HumanOTPSecretReencryptedEvent replaces the secret with the same secret
+// encrypted by the current key after a key rotation
+type HumanOTPSecretReencryptedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	Secret *crypto.CryptoValue `json:"otpSecret,omitempty"`
+}
+
+func (e *HumanOTPSecretReencryptedEvent) Data() interface{} {
+	return e
+}
+
+func (e *HumanOTPSecretReencryptedEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+func NewHumanOTPSecretReencryptedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	secret *crypto.CryptoValue,
+) *HumanOTPSecretReencryptedEvent {
+	return &HumanOTPSecretReencryptedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			HumanMFAOTPSecretReencryptedType,
+		),
+		Secret: secret,
+	}
+}
+
+func HumanOTPSecretReencryptedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	reencrypted := &HumanOTPSecretReencryptedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	err := json.Unmarshal(event.Data, reencrypted)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "USER-Ooch4", "unable to unmarshal human otp secret reencrypted")
+	}
+	return reencrypted, nil
+}
+
 type HumanOTPVerifiedEvent struct {
 	eventstore.BaseEvent `json:"-"`
 	UserAgentID          string `json:"userAgentID,omitempty"`
@@ -21,6 +21,7 @@ const (
 	HumanPasswordCodeSentType       = passwordEventPrefix + "code.sent"
 	HumanPasswordCheckSucceededType = passwordEventPrefix + "check.succeeded"
 	HumanPasswordCheckFailedType    = passwordEventPrefix + "check.failed"
+	HumanPasswordHashUpdatedType    = passwordEventPrefix + "hash.updated"
 )
 
 type HumanPasswordChangedEvent struct {
@@ -268,3 +269,46 @@ func HumanPasswordCheckFailedEventMapper(event *repository.Event) (eventstore.Ev
 
 	return humanAdded, nil
 }
+
+// HumanPasswordHashUpdatedEvent is pushed if the password was hashed again with the preferred algorithm
+// after a successful password check
+type HumanPasswordHashUpdatedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	Secret *crypto.CryptoValue `json:"secret,omitempty"`
+}
+
+func (e *HumanPasswordHashUpdatedEvent) Data() interface{} {
+	return e
+}
+
+func (e *HumanPasswordHashUpdatedEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+func NewHumanPasswordHashUpdatedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	secret *crypto.CryptoValue,
+) *HumanPasswordHashUpdatedEvent {
+	return &HumanPasswordHashUpdatedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			HumanPasswordHashUpdatedType,
+		),
+		Secret: secret,
+	}
+}
+
+func HumanPasswordHashUpdatedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	hashUpdated := &HumanPasswordHashUpdatedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	err := json.Unmarshal(event.Data, hashUpdated)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "USER-ohG3e", "unable to unmarshal human password hash updated")
+	}
+
+	return hashUpdated, nil
+}
@@ -50,10 +50,16 @@ func NewRemoveUsernameUniqueConstraint(userName, resourceOwner string, userLogin
 
 type UserLockedEvent struct {
 	eventstore.BaseEvent `json:"-"`
+
+	// LockedUntil is set if the user is unlocked automatically (lockout duration of the lockout policy)
+	LockedUntil *time.Time `json:"lockedUntil,omitempty"`
 }
 
 func (e *UserLockedEvent) Data() interface{} {
-	return nil
+	if e.LockedUntil == nil {
+		return nil
+	}
+	return e
 }
 
 func (e *UserLockedEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
@@ -70,10 +76,29 @@ func NewUserLockedEvent(ctx context.Context, aggregate *eventstore.Aggregate) *U
 	}
 }
 
-func UserLockedEventMapper(event *repository.Event) (eventstore.Event, error) {
+func NewUserLockedUntilEvent(ctx context.Context, aggregate *eventstore.Aggregate, lockedUntil time.Time) *UserLockedEvent {
 	return &UserLockedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			UserLockedType,
+		),
+		LockedUntil: &lockedUntil,
+	}
+}
+
+func UserLockedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	userLocked := &UserLockedEvent{
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
-	}, nil
+	}
+	if len(event.Data) == 0 {
+		return userLocked, nil
+	}
+	err := json.Unmarshal(event.Data, userLocked)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "USER-Ohx3i", "unable to unmarshal user locked")
+	}
+	return userLocked, nil
 }
 
 type UserUnlockedEvent struct {
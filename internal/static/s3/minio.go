@@ -16,7 +16,10 @@ import (
 	"github.com/zitadel/zitadel/internal/static"
 )
 
-var _ static.Storage = (*Minio)(nil)
+var (
+	_ static.Storage      = (*Minio)(nil)
+	_ static.ObjectReader = (*Minio)(nil)
+)
 
 type Minio struct {
 	Client       *minio.Client
@@ -69,6 +72,24 @@ func (m *Minio) GetObject(ctx context.Context, instanceID, resourceOwner, name s
 	return asset, info, nil
 }
 
+func (m *Minio) ReadObject(ctx context.Context, instanceID, resourceOwner, name string) (io.ReadCloser, error) {
+	bucketName := m.prefixBucketName(instanceID)
+	objectName := fmt.Sprintf("%s/%s", resourceOwner, name)
+	object, err := m.Client.GetObject(ctx, bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, caos_errs.ThrowInternal(err, "MINIO-Oov3e", "Errors.Assets.Object.GetFailed")
+	}
+	// the object is requested lazily, stat returns the error if it doesn't exist
+	if _, err = object.Stat(); err != nil {
+		object.Close()
+		if errResp := minio.ToErrorResponse(err); errResp.StatusCode == http.StatusNotFound {
+			return nil, caos_errs.ThrowNotFound(err, "MINIO-ahV3u", "Errors.Assets.Object.GetFailed")
+		}
+		return nil, caos_errs.ThrowInternal(err, "MINIO-Ieth8", "Errors.Assets.Object.GetFailed")
+	}
+	return object, nil
+}
+
 func (m *Minio) GetObjectInfo(ctx context.Context, instanceID, resourceOwner, name string) (*static.Asset, error) {
 	bucketName := m.prefixBucketName(instanceID)
 	objectName := fmt.Sprintf("%s/%s", resourceOwner, name)
@@ -19,11 +19,18 @@ type Storage interface {
 	//TODO: add functionality to move asset location
 }
 
+// ObjectReader is implemented by storages which are able to stream large objects
+// instead of reading them into memory
+type ObjectReader interface {
+	ReadObject(ctx context.Context, instanceID, resourceOwner, name string) (io.ReadCloser, error)
+}
+
 type ObjectType int32
 
 const (
 	ObjectTypeUserAvatar ObjectType = iota
 	ObjectTypeStyling
+	ObjectTypeEventArchive
 )
 
 func (o ObjectType) String() string {
@@ -32,6 +39,8 @@ func (o ObjectType) String() string {
 		return "0"
 	case ObjectTypeStyling:
 		return "1"
+	case ObjectTypeEventArchive:
+		return "2"
 	default:
 		return ""
 	}
@@ -16,6 +16,15 @@ const (
 	SpoolerDivCounterDescription    = "Spooler div from last successful run to now in milliseconds"
 	Database                        = "database"
 	ViewName                        = "view_name"
+
+	ProjectionSequenceLag             = "zitadel.projection_sequence_lag"
+	ProjectionSequenceLagDescription  = "Difference between the latest event sequence and the sequence processed by the projection"
+	ProjectionTimeLag                 = "zitadel.projection_lag_milliseconds"
+	ProjectionTimeLagDescription      = "Age of the oldest event not processed by the projection in milliseconds"
+	ProjectionFailedEvents            = "zitadel.projection_failed_events"
+	ProjectionFailedEventsDescription = "Events which failed to be processed by the projection"
+	ProjectionName                    = "projection_name"
+	InstanceID                        = "instance_id"
 )
 
 type Metrics interface {
@@ -29,6 +29,13 @@ func (u *Human) appendOTPAddedEvent(event *es_models.Event) error {
 	return u.OTP.setData(event)
 }
 
+func (u *Human) appendOTPSecretReencryptedEvent(event *es_models.Event) error {
+	if u.OTP == nil {
+		return nil
+	}
+	return u.OTP.setData(event)
+}
+
 func (u *Human) appendOTPVerifiedEvent() {
 	u.OTP.State = int32(model.MFAStateReady)
 }
@@ -97,6 +97,8 @@ func (h *Human) AppendEvent(event *es_models.Event) (err error) {
 	case user.UserV1MFAOTPVerifiedType,
 		user.HumanMFAOTPVerifiedType:
 		h.appendOTPVerifiedEvent()
+	case user.HumanMFAOTPSecretReencryptedType:
+		err = h.appendOTPSecretReencryptedEvent(event)
 	case user.UserV1MFAOTPRemovedType,
 		user.HumanMFAOTPRemovedType:
 		h.appendOTPRemovedEvent()